@@ -13,6 +13,7 @@ import (
 	"cscan/api/internal/handler"
 	"cscan/api/internal/svc"
 	"cscan/model"
+	"cscan/pkg/notify"
 	"cscan/scheduler"
 
 	"github.com/google/uuid"
@@ -70,6 +71,10 @@ func main() {
 	// 启动孤儿任务恢复后台任务（每 5 分钟检查一次）
 	go startOrphanedTaskRecovery(svcCtx)
 
+	// 启动通知摘要发送和Worker离线检测（每分钟一次）
+	go startNotifyDigestFlush(svcCtx)
+	go startWorkerOfflineMonitor(svcCtx)
+
 	// logx.Infof("Starting API server at %s:%d...", c.Host, c.Port)
 	fmt.Println("---------------------------------------------------------")
 	logx.Infof("✅ CScan API is running at: %s:%d", c.Host, c.Port)
@@ -185,6 +190,37 @@ func createAndPushCronTask(ctx context.Context, svcCtx *svc.ServiceContext, sche
 			enabledModules++
 		}
 	}
+	if ext, ok := taskConfig["external"].(map[string]interface{}); ok {
+		if enable, _ := ext["enable"].(bool); enable {
+			enabledModules++
+		}
+	}
+	if cr, ok := taskConfig["crawler"].(map[string]interface{}); ok {
+		if enable, _ := cr["enable"].(bool); enable {
+			enabledModules++
+		}
+	}
+	if bf, ok := taskConfig["bruteforce"].(map[string]interface{}); ok {
+		if enable, _ := bf["enable"].(bool); enable {
+			enabledModules++
+		}
+	}
+	if tk, ok := taskConfig["takeover"].(map[string]interface{}); ok {
+		if enable, _ := tk["enable"].(bool); enable {
+			enabledModules++
+		}
+	}
+	if dr, ok := taskConfig["dnsrecord"].(map[string]interface{}); ok {
+		if enable, _ := dr["enable"].(bool); enable {
+			enabledModules++
+		}
+	}
+	// 声明式工作流按阶段数计数
+	if wf, ok := taskConfig["workflow"].(map[string]interface{}); ok {
+		if stages, ok := wf["stages"].([]interface{}); ok && len(stages) > 0 {
+			enabledModules = len(stages)
+		}
+	}
 	if enabledModules == 0 {
 		enabledModules = 1
 	}
@@ -410,3 +446,73 @@ func cleanupStaleProcessingTasks(svcCtx *svc.ServiceContext) {
 		logx.Infof("[OrphanedTaskRecovery] Cleaned up %d stale processing records", cleaned)
 	}
 }
+
+// startNotifyDigestFlush 定期发送到期的通知摘要（静默时段推迟和小时/每日摘要）
+func startNotifyDigestFlush(svcCtx *svc.ServiceContext) {
+	logx.Info("Notify digest flush background job started")
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Second)
+		if err := svcCtx.NotifyRoute.Flush(ctx); err != nil {
+			logx.Errorf("[NotifyDigest] flush failed: %v", err)
+		}
+		cancel()
+	}
+}
+
+// startWorkerOfflineMonitor 检测心跳过期的Worker并发送离线通知。
+// 主动下线的Worker由离线接口通知，这里只处理仍在Worker集合中但心跳已过期的情况
+func startWorkerOfflineMonitor(svcCtx *svc.ServiceContext) {
+	logx.Info("Worker offline monitor started")
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	// offline 记录已通知过的离线Worker，首次检查只记录不通知，避免重启API时重复通知
+	var offline map[string]bool
+	for range ticker.C {
+		current := offlineWorkers(svcCtx)
+		if current == nil {
+			continue
+		}
+		if offline != nil {
+			for name := range current {
+				if !offline[name] {
+					logx.Infof("[WorkerOfflineMonitor] Worker %s heartbeat expired", name)
+					svcCtx.NotifyRoute.DispatchAsync(&notify.Event{
+						Type:   notify.EventWorkerOffline,
+						Title:  name,
+						Target: name,
+						Detail: "心跳超时",
+					})
+				}
+			}
+		}
+		offline = current
+	}
+}
+
+// offlineWorkers 返回Worker集合中心跳已过期的Worker，查询失败时返回 nil
+func offlineWorkers(svcCtx *svc.ServiceContext) map[string]bool {
+	ctx := context.Background()
+	workers, err := svcCtx.RedisClient.SMembers(ctx, "cscan:workers").Result()
+	if err != nil {
+		logx.Errorf("[WorkerOfflineMonitor] Failed to get workers: %v", err)
+		return nil
+	}
+
+	offline := make(map[string]bool)
+	for _, name := range workers {
+		exists, err := svcCtx.RedisClient.Exists(ctx, "cscan:worker:"+name).Result()
+		if err != nil {
+			return nil
+		}
+		if exists == 0 {
+			offline[name] = true
+		}
+	}
+	return offline
+}
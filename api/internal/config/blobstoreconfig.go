@@ -0,0 +1,16 @@
+package config
+
+// BlobStoreConfig 截图等二进制文件存储配置，文件不写入 MongoDB，资产中只保存存储键
+type BlobStoreConfig struct {
+	// 存储类型: local 本地目录, s3 兼容 S3 协议的对象存储（MinIO/OSS/COS 等）
+	Type string `json:",default=local,options=local|s3"`
+	// 本地存储目录
+	Dir string `json:",default=./data/blobs"`
+
+	Endpoint     string `json:",optional"`
+	Region       string `json:",optional"`
+	Bucket       string `json:",optional"`
+	AccessKey    string `json:",optional"`
+	SecretKey    string `json:",optional"`
+	UsePathStyle bool   `json:",optional"`
+}
@@ -16,7 +16,9 @@ type Config struct {
 		Uri    string
 		DbName string
 	}
-	Redis   redis.RedisConf
-	TaskRpc zrpc.RpcClientConf
-	Console ConsoleConfig `json:",optional"`
+	Redis     redis.RedisConf
+	TaskRpc   zrpc.RpcClientConf
+	Console   ConsoleConfig   `json:",optional"`
+	GeoIP     GeoIPConfig     `json:",optional"`
+	BlobStore BlobStoreConfig `json:",optional"`
 }
@@ -0,0 +1,7 @@
+package config
+
+// GeoIPConfig IP归属地/ASN数据库配置
+type GeoIPConfig struct {
+	// API 主机本地数据库目录（*.mmdb/*.xdb），与上传的数据集一起用于补全 Worker 未识别的IP归属地
+	Dir string `json:",optional"`
+}
@@ -0,0 +1,52 @@
+package asset
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cscan/api/internal/svc"
+	"cscan/pkg/blobstore"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/pathvar"
+)
+
+// ScreenshotFileHandler 读取截图文件
+// 图片标签无法携带认证头，只接受资产接口签出的地址（签名绑定存储键和过期时间）
+func ScreenshotFileHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := pathvar.Vars(r)["key"]
+		if !svc.IsScreenshotKey(key) {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query()
+		if !svcCtx.Screenshots.Verify(key, query.Get("exp"), query.Get("sig")) {
+			http.Error(w, "截图地址无效或已过期", http.StatusForbidden)
+			return
+		}
+		data, contentType, err := svcCtx.Screenshots.Open(r.Context(), key)
+		if err != nil {
+			if !errors.Is(err, blobstore.ErrNotFound) {
+				logx.Errorf("[Screenshot] open %s error: %v", key, err)
+			}
+			http.NotFound(w, r)
+			return
+		}
+		// 内容寻址，同一键的内容不会变化，缓存到签名过期
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "private, max-age="+strconv.FormatInt(screenshotMaxAge(query.Get("exp")), 10))
+		w.Write(data)
+	}
+}
+
+// screenshotMaxAge 签名剩余有效秒数
+func screenshotMaxAge(exp string) int64 {
+	expAt, _ := strconv.ParseInt(exp, 10, 64)
+	if left := expAt - time.Now().Unix(); left > 0 {
+		return left
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+package bruteforce
+
+import (
+	"net/http"
+
+	"cscan/api/internal/logic"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/pkg/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// BruteforceDictListHandler 弱口令字典列表
+func BruteforceDictListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.BruteforceDictListReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewBruteforceDictListLogic(r.Context(), svcCtx)
+		resp, err := l.BruteforceDictList(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// BruteforceDictSaveHandler 保存弱口令字典
+func BruteforceDictSaveHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.BruteforceDictSaveReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewBruteforceDictSaveLogic(r.Context(), svcCtx)
+		resp, err := l.BruteforceDictSave(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// BruteforceDictDeleteHandler 删除弱口令字典
+func BruteforceDictDeleteHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.BruteforceDictDeleteReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewBruteforceDictDeleteLogic(r.Context(), svcCtx)
+		resp, err := l.BruteforceDictDelete(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// BruteforceDictClearHandler 清空弱口令字典
+func BruteforceDictClearHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewBruteforceDictClearLogic(r.Context(), svcCtx)
+		resp, err := l.BruteforceDictClear()
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// BruteforceDictEnabledListHandler 获取启用的弱口令字典列表（用于任务创建时选择）
+func BruteforceDictEnabledListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewBruteforceDictEnabledListLogic(r.Context(), svcCtx)
+		resp, err := l.BruteforceDictEnabledList()
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
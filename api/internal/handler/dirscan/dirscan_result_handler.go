@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
 	"cscan/model"
@@ -59,13 +60,18 @@ func DirScanResultListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		workspaceId := middleware.GetWorkspaceId(ctx)
 		resultModel := model.NewDirScanResultModel(svcCtx.MongoDB)
 
-		// 如果有通用 Query 且未指定更细的 URL/Path/Authority 条件，则将 Query 作为 URL 关键字使用
-		if req.Query != "" && req.Url == "" && req.Path == "" && req.Authority == "" {
-			req.Url = req.Query
-		}
-
 		// 构建查询条件 - 当 workspaceId 为空或 "all" 时查询所有
 		filter := bson.M{}
+
+		// 查询语法（关键词模糊匹配 URL）
+		queryFilter, err := common.CompileQuery(common.DirScanQuerySchema, req.Query)
+		if err != nil {
+			httpx.OkJson(w, &DirScanResultListResp{Code: 400, Msg: err.Error()})
+			return
+		}
+		if queryFilter != nil {
+			filter["$and"] = []bson.M{queryFilter}
+		}
 		if workspaceId != "" && workspaceId != "all" {
 			filter["workspace_id"] = workspaceId
 		}
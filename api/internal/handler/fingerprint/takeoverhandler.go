@@ -0,0 +1,88 @@
+package fingerprint
+
+import (
+	"net/http"
+
+	"cscan/api/internal/logic"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/pkg/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// TakeoverSignatureListHandler 子域接管签名列表
+func TakeoverSignatureListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.TakeoverSignatureListReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewTakeoverSignatureLogic(r.Context(), svcCtx)
+		resp, err := l.TakeoverSignatureList(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// TakeoverSignatureSaveHandler 保存子域接管签名
+func TakeoverSignatureSaveHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.TakeoverSignatureSaveReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewTakeoverSignatureLogic(r.Context(), svcCtx)
+		resp, err := l.TakeoverSignatureSave(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// TakeoverSignatureDeleteHandler 删除子域接管签名
+func TakeoverSignatureDeleteHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.TakeoverSignatureDeleteReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewTakeoverSignatureLogic(r.Context(), svcCtx)
+		resp, err := l.TakeoverSignatureDelete(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// TakeoverSignatureUpdateEnabledHandler 启用或禁用子域接管签名
+func TakeoverSignatureUpdateEnabledHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.TakeoverSignatureUpdateEnabledReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewTakeoverSignatureLogic(r.Context(), svcCtx)
+		resp, err := l.TakeoverSignatureUpdateEnabled(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
@@ -0,0 +1,95 @@
+package geoip
+
+import (
+	"io"
+	"net/http"
+
+	"cscan/api/internal/logic"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/pkg/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// GeoIPDatasetListHandler IP归属地数据集列表
+func GeoIPDatasetListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewGeoIPDatasetLogic(r.Context(), svcCtx)
+		resp, err := l.GeoIPDatasetList()
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// GeoIPDatasetUploadHandler 上传IP归属地数据集（multipart 表单）
+func GeoIPDatasetUploadHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GeoIPDatasetUploadReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			response.ParamError(w, "请选择数据集文件")
+			return
+		}
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		if err != nil {
+			response.ParamError(w, "读取数据集文件失败")
+			return
+		}
+
+		l := logic.NewGeoIPDatasetLogic(r.Context(), svcCtx)
+		resp, err := l.GeoIPDatasetUpload(&req, header.Filename, content)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// GeoIPDatasetDeleteHandler 删除IP归属地数据集
+func GeoIPDatasetDeleteHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GeoIPDatasetDeleteReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewGeoIPDatasetLogic(r.Context(), svcCtx)
+		resp, err := l.GeoIPDatasetDelete(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// GeoIPDatasetUpdateEnabledHandler 启用或禁用IP归属地数据集
+func GeoIPDatasetUpdateEnabledHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GeoIPDatasetUpdateEnabledReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewGeoIPDatasetLogic(r.Context(), svcCtx)
+		resp, err := l.GeoIPDatasetUpdateEnabled(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
@@ -125,3 +125,54 @@ func HighRiskFilterConfigSaveHandler(svcCtx *svc.ServiceContext) http.HandlerFun
 		}
 	}
 }
+
+// NotifyRouteListHandler 通知路由规则列表
+func NotifyRouteListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewNotifyRouteLogic(r.Context(), svcCtx)
+		resp, err := l.List()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
+
+// NotifyRouteSaveHandler 保存通知路由规则
+func NotifyRouteSaveHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.NotifyRouteSaveReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewNotifyRouteLogic(r.Context(), svcCtx)
+		resp, err := l.Save(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
+
+// NotifyRouteDeleteHandler 删除通知路由规则
+func NotifyRouteDeleteHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.NotifyRouteDeleteReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewNotifyRouteLogic(r.Context(), svcCtx)
+		resp, err := l.Delete(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"cscan/api/internal/logic"
+	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 
@@ -69,3 +70,80 @@ func OrganizationUpdateStatusHandler(svcCtx *svc.ServiceContext) http.HandlerFun
 		httpx.OkJson(w, resp)
 	}
 }
+
+// OrganizationDashboardHandler 组织看板
+func OrganizationDashboardHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.OrgDashboardReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.OkJson(w, &types.BaseResp{Code: 400, Msg: err.Error()})
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewOrganizationDashboardLogic(r.Context(), svcCtx)
+		resp, _ := l.OrganizationDashboard(&req, workspaceId)
+		httpx.OkJson(w, resp)
+	}
+}
+
+// OrgAttributionRuleListHandler 资产归属规则列表
+func OrgAttributionRuleListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.OrgAttributionRuleListReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.OkJson(w, &types.BaseResp{Code: 400, Msg: err.Error()})
+			return
+		}
+
+		l := logic.NewOrgAttributionLogic(r.Context(), svcCtx)
+		resp, _ := l.RuleList(&req)
+		httpx.OkJson(w, resp)
+	}
+}
+
+// OrgAttributionRuleSaveHandler 保存资产归属规则
+func OrgAttributionRuleSaveHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.OrgAttributionRuleSaveReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.OkJson(w, &types.BaseResp{Code: 400, Msg: err.Error()})
+			return
+		}
+
+		l := logic.NewOrgAttributionLogic(r.Context(), svcCtx)
+		resp, _ := l.RuleSave(&req)
+		httpx.OkJson(w, resp)
+	}
+}
+
+// OrgAttributionRuleDeleteHandler 删除资产归属规则
+func OrgAttributionRuleDeleteHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.OrgAttributionRuleDeleteReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.OkJson(w, &types.BaseResp{Code: 400, Msg: err.Error()})
+			return
+		}
+
+		l := logic.NewOrgAttributionLogic(r.Context(), svcCtx)
+		resp, _ := l.RuleDelete(&req)
+		httpx.OkJson(w, resp)
+	}
+}
+
+// OrgAttributionApplyHandler 对已有资产重新执行归属规则
+func OrgAttributionApplyHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.OrgAttributionApplyReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.OkJson(w, &types.BaseResp{Code: 400, Msg: err.Error()})
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewOrgAttributionLogic(r.Context(), svcCtx)
+		resp, _ := l.Apply(&req, workspaceId)
+		httpx.OkJson(w, resp)
+	}
+}
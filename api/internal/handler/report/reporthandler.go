@@ -36,16 +36,16 @@ func ReportExportHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 
 		workspaceId := middleware.GetWorkspaceId(r.Context())
 		l := logic.NewReportExportLogic(r.Context(), svcCtx)
-		data, filename, err := l.ReportExport(&req, workspaceId)
+		file, err := l.ReportExport(&req, workspaceId)
 		if err != nil {
 			httpResult(w, &types.BaseResp{Code: 500, Msg: err.Error()})
 			return
 		}
 
 		// 设置响应头
-		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-		w.Header().Set("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(filename))
-		w.Write(data)
+		w.Header().Set("Content-Type", file.ContentType)
+		w.Header().Set("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(file.Filename))
+		w.Write(file.Data)
 	}
 }
 
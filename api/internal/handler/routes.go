@@ -6,8 +6,10 @@ import (
 	"cscan/api/internal/handler/ai"
 	"cscan/api/internal/handler/asset"
 	"cscan/api/internal/handler/blacklist"
+	"cscan/api/internal/handler/bruteforce"
 	"cscan/api/internal/handler/dirscan"
 	"cscan/api/internal/handler/fingerprint"
+	"cscan/api/internal/handler/geoip"
 	"cscan/api/internal/handler/notify"
 	"cscan/api/internal/handler/onlineapi"
 	"cscan/api/internal/handler/organization"
@@ -22,6 +24,7 @@ import (
 	"cscan/api/internal/handler/workspace"
 	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
+	"cscan/model"
 
 	"github.com/zeromicro/go-zero/rest"
 )
@@ -65,25 +68,34 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 			// Worker安装相关（无需认证，Worker需要调用）
 			{Method: http.MethodGet, Path: "/api/v1/worker/download", Handler: worker.WorkerDownloadHandler(svcCtx)},
 			{Method: http.MethodPost, Path: "/api/v1/worker/validate", Handler: worker.WorkerValidateKeyHandler(svcCtx)},
+			{Method: http.MethodPost, Path: "/api/v1/worker/enroll", Handler: worker.WorkerEnrollHandler(svcCtx)},
 			// Worker WebSocket端点（认证在WebSocket握手后进行）
 			{Method: http.MethodGet, Path: "/api/v1/worker/ws", Handler: worker.WorkerWSEndpointHandler(svcCtx, WorkerWSHandlerInstance)},
 			// 静态文件 - docker-compose-worker.yaml
 			{Method: http.MethodGet, Path: "/static/docker-compose-worker.yaml", Handler: worker.DockerComposeWorkerHandler(svcCtx)},
+			// 截图文件（图片标签无法携带认证头，只接受资产接口签出的带过期时间的地址）
+			{Method: http.MethodGet, Path: "/api/screenshot/:key", Handler: asset.ScreenshotFileHandler(svcCtx)},
 		},
 	)
 
-	// Worker专用路由（需要Install Key认证）
-	workerAuthMiddleware := middleware.NewWorkerAuthMiddleware(svcCtx.RedisClient)
+	// Worker专用路由（需要Worker凭证认证，凭证通过安装密钥注册获得）
+	workerAuthMiddleware := middleware.NewWorkerAuthMiddleware(svcCtx.WorkerCredentialModel)
+	workerAuthMiddleware.TaskWorker = worker.TaskWorkerLookup(svcCtx)
 	workerRoutes := []rest.Route{
 		// 任务相关
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/check", Handler: worker.WorkerTaskCheckHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/update", Handler: worker.WorkerTaskUpdateHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/result", Handler: worker.WorkerTaskResultHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/task/result/stream", Handler: worker.WorkerTaskResultStreamHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/vul", Handler: worker.WorkerVulResultHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/dirscan", Handler: worker.WorkerDirScanResultHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/task/dnsrecord", Handler: worker.WorkerDNSRecordResultHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/task/urlasset", Handler: worker.WorkerURLAssetResultHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/subtask/done", Handler: worker.WorkerSubTaskDoneHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/control", Handler: worker.WorkerTaskControlHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/recovery", Handler: worker.WorkerTaskRecoveryHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/task/newassets", Handler: worker.WorkerTaskNewAssetsHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/task/outofscope", Handler: worker.WorkerTaskOutOfScopeHandler(svcCtx)},
 		// 心跳
 		{Method: http.MethodPost, Path: "/api/v1/worker/heartbeat", Handler: worker.WorkerHeartbeatHandler(svcCtx)},
 		// Worker离线通知
@@ -98,8 +110,16 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		{Method: http.MethodPost, Path: "/api/v1/worker/config/poc", Handler: worker.WorkerConfigPocHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/config/dirscandict", Handler: worker.WorkerConfigDirScanDictHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/config/subdomaindict", Handler: worker.WorkerConfigSubdomainDictHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/bruteforcedict", Handler: worker.WorkerConfigBruteforceDictHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/externalscanners", Handler: worker.WorkerConfigExternalScannersHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/takeover", Handler: worker.WorkerConfigTakeoverHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/cdn", Handler: worker.WorkerConfigCDNHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/secretrules", Handler: worker.WorkerConfigSecretRulesHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/geoip", Handler: worker.WorkerConfigGeoIPHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/geoip/download", Handler: worker.WorkerConfigGeoIPDownloadHandler(svcCtx)},
 		// 黑名单规则（供Worker使用）
 		{Method: http.MethodPost, Path: "/api/v1/worker/config/blacklist", Handler: blacklist.BlacklistRulesHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/scope", Handler: worker.WorkerConfigScopeHandler(svcCtx)},
 	}
 
 	// 为Worker路由包装认证中间件
@@ -112,265 +132,330 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 
 	server.AddRoutes(workerRoutes)
 
-	// 需要认证的路由
-	authMiddleware := middleware.NewAuthMiddleware(svcCtx.Config.Auth.AccessSecret)
+	// 需要认证的路由，每个路由声明所需权限，按用户在当前工作空间的角色校验
+	authMiddleware := middleware.NewAuthMiddleware(svcCtx.Config.Auth.AccessSecret, svcCtx.ApiTokenModel)
+	rbac := middleware.NewRBACMiddleware(svcCtx.UserModel)
 	authRoutes := []rest.Route{
 		// 用户管理
-		{Method: http.MethodPost, Path: "/api/v1/user/list", Handler: user.UserListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/user/create", Handler: user.UserCreateHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/user/update", Handler: user.UserUpdateHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/user/delete", Handler: user.UserDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/user/resetPassword", Handler: user.UserResetPasswordHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/user/scanConfig/save", Handler: user.SaveScanConfigHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/user/scanConfig/get", Handler: user.GetScanConfigHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/user/list", Handler: rbac.Require(model.PermUserManage, user.UserListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/user/create", Handler: rbac.Require(model.PermUserManage, user.UserCreateHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/user/update", Handler: rbac.Require(model.PermUserManage, user.UserUpdateHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/user/delete", Handler: rbac.Require(model.PermUserManage, user.UserDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/user/resetPassword", Handler: rbac.Require(model.PermUserManage, user.UserResetPasswordHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/user/scanConfig/save", Handler: rbac.Require(model.PermView, user.SaveScanConfigHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/user/scanConfig/get", Handler: rbac.Require(model.PermView, user.GetScanConfigHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/user/token/list", Handler: rbac.Require(model.PermView, user.ApiTokenListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/user/token/create", Handler: rbac.Require(model.PermView, user.ApiTokenCreateHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/user/token/revoke", Handler: rbac.Require(model.PermView, user.ApiTokenRevokeHandler(svcCtx))},
 
 		// Worker日志（需要认证）
-		{Method: http.MethodGet, Path: "/api/v1/worker/logs/stream", Handler: worker.WorkerLogsHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/worker/logs/history", Handler: worker.WorkerLogsHistoryHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/worker/logs/export", Handler: worker.WorkerLogsExportHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/worker/logs/clear", Handler: worker.WorkerLogsClearHandler(svcCtx)},
+		{Method: http.MethodGet, Path: "/api/v1/worker/logs/stream", Handler: rbac.Require(model.PermView, worker.WorkerLogsHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/logs/history", Handler: rbac.Require(model.PermView, worker.WorkerLogsHistoryHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/logs/export", Handler: rbac.Require(model.PermView, worker.WorkerLogsExportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/logs/clear", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerLogsClearHandler(svcCtx))},
 
 		// 工作空间
-		{Method: http.MethodPost, Path: "/api/v1/workspace/list", Handler: workspace.WorkspaceListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/workspace/save", Handler: workspace.WorkspaceSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/workspace/delete", Handler: workspace.WorkspaceDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/workspace/list", Handler: rbac.Require(model.PermView, workspace.WorkspaceListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/workspace/save", Handler: rbac.Require(model.PermUserManage, workspace.WorkspaceSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/workspace/delete", Handler: rbac.Require(model.PermUserManage, workspace.WorkspaceDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/workspace/scope/get", Handler: rbac.Require(model.PermView, workspace.ScanScopeGetHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/workspace/scope/save", Handler: rbac.Require(model.PermSettings, workspace.ScanScopeSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/workspace/scope/outofscope", Handler: rbac.Require(model.PermView, workspace.OutOfScopeListHandler(svcCtx))},
 
 		// 组织管理
-		{Method: http.MethodPost, Path: "/api/v1/organization/list", Handler: organization.OrganizationListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/organization/save", Handler: organization.OrganizationSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/organization/delete", Handler: organization.OrganizationDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/organization/updateStatus", Handler: organization.OrganizationUpdateStatusHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/organization/list", Handler: rbac.Require(model.PermView, organization.OrganizationListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/organization/save", Handler: rbac.Require(model.PermAssetManage, organization.OrganizationSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/organization/delete", Handler: rbac.Require(model.PermAssetManage, organization.OrganizationDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/organization/updateStatus", Handler: rbac.Require(model.PermAssetManage, organization.OrganizationUpdateStatusHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/organization/dashboard", Handler: rbac.Require(model.PermView, organization.OrganizationDashboardHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/organization/rule/list", Handler: rbac.Require(model.PermView, organization.OrgAttributionRuleListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/organization/rule/save", Handler: rbac.Require(model.PermAssetManage, organization.OrgAttributionRuleSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/organization/rule/delete", Handler: rbac.Require(model.PermAssetManage, organization.OrgAttributionRuleDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/organization/rule/apply", Handler: rbac.Require(model.PermAssetManage, organization.OrgAttributionApplyHandler(svcCtx))},
 
 		// 资产管理
-		{Method: http.MethodPost, Path: "/api/v1/asset/list", Handler: asset.AssetListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/stat", Handler: asset.AssetStatHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/groups", Handler: asset.AssetGroupsHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/groups/delete", Handler: asset.DeleteAssetGroupHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/inventory", Handler: asset.AssetInventoryHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/screenshots", Handler: asset.ScreenshotsHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/filterOptions", Handler: asset.AssetFilterOptionsHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/exposures", Handler: asset.AssetExposuresHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/updateLabels", Handler: asset.AssetUpdateLabelsHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/addLabel", Handler: asset.AssetAddLabelHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/removeLabel", Handler: asset.AssetRemoveLabelHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/delete", Handler: asset.AssetDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/batchDelete", Handler: asset.AssetBatchDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/clear", Handler: asset.AssetClearHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/history", Handler: asset.AssetHistoryHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/import", Handler: asset.AssetImportHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/asset/list", Handler: rbac.Require(model.PermView, asset.AssetListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/stat", Handler: rbac.Require(model.PermView, asset.AssetStatHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/groups", Handler: rbac.Require(model.PermView, asset.AssetGroupsHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/groups/delete", Handler: rbac.Require(model.PermAssetDelete, asset.DeleteAssetGroupHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/inventory", Handler: rbac.Require(model.PermView, asset.AssetInventoryHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/screenshots", Handler: rbac.Require(model.PermView, asset.ScreenshotsHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/filterOptions", Handler: rbac.Require(model.PermView, asset.AssetFilterOptionsHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/exposures", Handler: rbac.Require(model.PermView, asset.AssetExposuresHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/updateLabels", Handler: rbac.Require(model.PermAssetManage, asset.AssetUpdateLabelsHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/addLabel", Handler: rbac.Require(model.PermAssetManage, asset.AssetAddLabelHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/removeLabel", Handler: rbac.Require(model.PermAssetManage, asset.AssetRemoveLabelHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/delete", Handler: rbac.Require(model.PermAssetDelete, asset.AssetDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/batchDelete", Handler: rbac.Require(model.PermAssetDelete, asset.AssetBatchDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/clear", Handler: rbac.Require(model.PermAssetDelete, asset.AssetClearHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/history", Handler: rbac.Require(model.PermView, asset.AssetHistoryHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/import", Handler: rbac.Require(model.PermAssetManage, asset.AssetImportHandler(svcCtx))},
 
 		// 扫描结果集成 API
-		{Method: http.MethodPost, Path: "/api/v1/assets/withScans", Handler: asset.AssetsWithScansHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/assets/dirscans", Handler: asset.AssetDirScansHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/assets/vulnscans", Handler: asset.AssetVulnScansHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/assets/history", Handler: asset.AssetHistoryV2Handler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/assets/compareVersions", Handler: asset.CompareVersionsHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/assets/withScans", Handler: rbac.Require(model.PermView, asset.AssetsWithScansHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/assets/dirscans", Handler: rbac.Require(model.PermView, asset.AssetDirScansHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/assets/vulnscans", Handler: rbac.Require(model.PermView, asset.AssetVulnScansHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/assets/history", Handler: rbac.Require(model.PermView, asset.AssetHistoryV2Handler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/assets/compareVersions", Handler: rbac.Require(model.PermView, asset.CompareVersionsHandler(svcCtx))},
 
 		// 站点管理
-		{Method: http.MethodPost, Path: "/api/v1/asset/site/list", Handler: asset.SiteListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/site/stat", Handler: asset.SiteStatHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/site/delete", Handler: asset.SiteDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/site/batchDelete", Handler: asset.SiteBatchDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/asset/site/list", Handler: rbac.Require(model.PermView, asset.SiteListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/site/stat", Handler: rbac.Require(model.PermView, asset.SiteStatHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/site/delete", Handler: rbac.Require(model.PermAssetDelete, asset.SiteDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/site/batchDelete", Handler: rbac.Require(model.PermAssetDelete, asset.SiteBatchDeleteHandler(svcCtx))},
 
 		// 域名管理
-		{Method: http.MethodPost, Path: "/api/v1/asset/domain/list", Handler: asset.DomainListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/domain/stat", Handler: asset.DomainStatHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/domain/delete", Handler: asset.DomainDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/domain/batchDelete", Handler: asset.DomainBatchDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/asset/domain/list", Handler: rbac.Require(model.PermView, asset.DomainListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/domain/stat", Handler: rbac.Require(model.PermView, asset.DomainStatHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/domain/delete", Handler: rbac.Require(model.PermAssetDelete, asset.DomainDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/domain/batchDelete", Handler: rbac.Require(model.PermAssetDelete, asset.DomainBatchDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/domain/dns", Handler: rbac.Require(model.PermView, asset.DomainDNSRecordsHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/url/list", Handler: rbac.Require(model.PermView, asset.URLAssetListHandler(svcCtx))},
 
 		// IP管理
-		{Method: http.MethodPost, Path: "/api/v1/asset/ip/list", Handler: asset.IPListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/ip/stat", Handler: asset.IPStatHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/ip/delete", Handler: asset.IPDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/ip/batchDelete", Handler: asset.IPBatchDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/asset/ip/list", Handler: rbac.Require(model.PermView, asset.IPListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/ip/stat", Handler: rbac.Require(model.PermView, asset.IPStatHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/ip/delete", Handler: rbac.Require(model.PermAssetDelete, asset.IPDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/ip/batchDelete", Handler: rbac.Require(model.PermAssetDelete, asset.IPBatchDeleteHandler(svcCtx))},
 
 		// 任务管理
-		{Method: http.MethodPost, Path: "/api/v1/task/list", Handler: task.MainTaskListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/create", Handler: task.MainTaskCreateHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/update", Handler: task.MainTaskUpdateHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/delete", Handler: task.MainTaskDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/batchDelete", Handler: task.MainTaskBatchDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/retry", Handler: task.MainTaskRetryHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/start", Handler: task.MainTaskStartHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/pause", Handler: task.MainTaskPauseHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/resume", Handler: task.MainTaskResumeHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/stop", Handler: task.MainTaskStopHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/stat", Handler: task.TaskStatHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/profile/list", Handler: task.TaskProfileListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/profile/save", Handler: task.TaskProfileSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/profile/delete", Handler: task.TaskProfileDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/logs", Handler: task.GetTaskLogsHandler(svcCtx)},
-		{Method: http.MethodGet, Path: "/api/v1/task/logs/stream", Handler: task.TaskLogsStreamHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/task/list", Handler: rbac.Require(model.PermView, task.MainTaskListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/create", Handler: rbac.Require(model.PermTaskManage, task.MainTaskCreateHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/update", Handler: rbac.Require(model.PermTaskManage, task.MainTaskUpdateHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/delete", Handler: rbac.Require(model.PermTaskDelete, task.MainTaskDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/batchDelete", Handler: rbac.Require(model.PermTaskDelete, task.MainTaskBatchDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/retry", Handler: rbac.Require(model.PermTaskManage, task.MainTaskRetryHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/start", Handler: rbac.Require(model.PermTaskManage, task.MainTaskStartHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/pause", Handler: rbac.Require(model.PermTaskManage, task.MainTaskPauseHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/resume", Handler: rbac.Require(model.PermTaskManage, task.MainTaskResumeHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/stop", Handler: rbac.Require(model.PermTaskManage, task.MainTaskStopHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/stat", Handler: rbac.Require(model.PermView, task.TaskStatHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/diff", Handler: rbac.Require(model.PermView, task.TaskDiffHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/profile/list", Handler: rbac.Require(model.PermView, task.TaskProfileListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/profile/save", Handler: rbac.Require(model.PermTaskManage, task.TaskProfileSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/profile/delete", Handler: rbac.Require(model.PermTaskDelete, task.TaskProfileDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/logs", Handler: rbac.Require(model.PermView, task.GetTaskLogsHandler(svcCtx))},
+		{Method: http.MethodGet, Path: "/api/v1/task/logs/stream", Handler: rbac.Require(model.PermView, task.TaskLogsStreamHandler(svcCtx))},
+		{Method: http.MethodGet, Path: "/api/v1/task/results/stream", Handler: rbac.Require(model.PermView, task.TaskResultsStreamHandler(svcCtx))},
 		// 任务分片管理
-		{Method: http.MethodPost, Path: "/api/v1/task/chunk/progress", Handler: task.ChunkProgressHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/chunk/preview", Handler: task.ChunkPreviewHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/task/chunk/progress", Handler: rbac.Require(model.PermView, task.ChunkProgressHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/chunk/preview", Handler: rbac.Require(model.PermView, task.ChunkPreviewHandler(svcCtx))},
 
 		// 扫描配置模板管理
-		{Method: http.MethodPost, Path: "/api/v1/task/template/list", Handler: task.ScanTemplateListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/template/save", Handler: task.ScanTemplateSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/template/delete", Handler: task.ScanTemplateDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/template/detail", Handler: task.ScanTemplateDetailHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/template/fromTask", Handler: task.ScanTemplateFromTaskHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/template/categories", Handler: task.ScanTemplateCategoriesHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/template/export", Handler: task.ScanTemplateExportHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/template/import", Handler: task.ScanTemplateImportHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/template/use", Handler: task.ScanTemplateUseHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/task/template/list", Handler: rbac.Require(model.PermView, task.ScanTemplateListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/template/save", Handler: rbac.Require(model.PermTaskManage, task.ScanTemplateSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/template/delete", Handler: rbac.Require(model.PermTaskDelete, task.ScanTemplateDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/template/detail", Handler: rbac.Require(model.PermView, task.ScanTemplateDetailHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/template/fromTask", Handler: rbac.Require(model.PermTaskManage, task.ScanTemplateFromTaskHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/template/categories", Handler: rbac.Require(model.PermView, task.ScanTemplateCategoriesHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/template/export", Handler: rbac.Require(model.PermView, task.ScanTemplateExportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/template/import", Handler: rbac.Require(model.PermTaskManage, task.ScanTemplateImportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/template/use", Handler: rbac.Require(model.PermTaskManage, task.ScanTemplateUseHandler(svcCtx))},
 
 		// 定时任务管理
-		{Method: http.MethodPost, Path: "/api/v1/task/cron/list", Handler: task.CronTaskListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/cron/save", Handler: task.CronTaskSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/cron/toggle", Handler: task.CronTaskToggleHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/cron/delete", Handler: task.CronTaskDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/cron/batchDelete", Handler: task.CronTaskBatchDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/cron/runNow", Handler: task.CronTaskRunNowHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/task/cron/validate", Handler: task.ValidateCronSpecHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/task/cron/list", Handler: rbac.Require(model.PermView, task.CronTaskListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/cron/save", Handler: rbac.Require(model.PermTaskManage, task.CronTaskSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/cron/toggle", Handler: rbac.Require(model.PermTaskManage, task.CronTaskToggleHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/cron/delete", Handler: rbac.Require(model.PermTaskDelete, task.CronTaskDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/cron/batchDelete", Handler: rbac.Require(model.PermTaskDelete, task.CronTaskBatchDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/cron/runNow", Handler: rbac.Require(model.PermTaskManage, task.CronTaskRunNowHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/task/cron/validate", Handler: rbac.Require(model.PermView, task.ValidateCronSpecHandler(svcCtx))},
 
 		// 漏洞管理
-		{Method: http.MethodPost, Path: "/api/v1/vul/list", Handler: vul.VulListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/vul/detail", Handler: vul.VulDetailHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/vul/stat", Handler: vul.VulStatHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/vul/delete", Handler: vul.VulDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/vul/batchDelete", Handler: vul.VulBatchDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/vul/clear", Handler: vul.VulClearHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/vul/list", Handler: rbac.Require(model.PermView, vul.VulListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/detail", Handler: rbac.Require(model.PermView, vul.VulDetailHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/stat", Handler: rbac.Require(model.PermView, vul.VulStatHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/delete", Handler: rbac.Require(model.PermAssetDelete, vul.VulDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/batchDelete", Handler: rbac.Require(model.PermAssetDelete, vul.VulBatchDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/clear", Handler: rbac.Require(model.PermAssetDelete, vul.VulClearHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/triage", Handler: rbac.Require(model.PermVulTriage, vul.VulTriageHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/assign", Handler: rbac.Require(model.PermVulTriage, vul.VulAssignHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/comment", Handler: rbac.Require(model.PermVulTriage, vul.VulCommentHandler(svcCtx))},
+
+		// 离线漏洞情报库
+		{Method: http.MethodPost, Path: "/api/v1/vul/feed/import", Handler: rbac.Require(model.PermPocManage, vul.VulnFeedImportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/feed/stats", Handler: rbac.Require(model.PermView, vul.VulnFeedStatsHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/vul/feed/clear", Handler: rbac.Require(model.PermPocManage, vul.VulnFeedClearHandler(svcCtx))},
 
 		// Worker管理
-		{Method: http.MethodPost, Path: "/api/v1/worker/list", Handler: worker.WorkerListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/worker/delete", Handler: worker.WorkerDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/worker/rename", Handler: worker.WorkerRenameHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/worker/restart", Handler: worker.WorkerRestartHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/worker/concurrency", Handler: worker.WorkerSetConcurrencyHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/list", Handler: rbac.Require(model.PermView, worker.WorkerListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/delete", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/rename", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerRenameHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/restart", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerRestartHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/concurrency", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerSetConcurrencyHandler(svcCtx))},
+		// 外部扫描器会在 Worker 上执行任意程序，修改需要控制台权限
+		{Method: http.MethodPost, Path: "/api/v1/worker/scanner/list", Handler: rbac.Require(model.PermView, worker.ExternalScannerListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/scanner/save", Handler: rbac.Require(model.PermWorkerConsole, worker.ExternalScannerSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/scanner/delete", Handler: rbac.Require(model.PermWorkerConsole, worker.ExternalScannerDeleteHandler(svcCtx))},
 		// Worker安装管理（需要认证）
-		{Method: http.MethodPost, Path: "/api/v1/worker/install/command", Handler: worker.WorkerInstallCommandHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/worker/install/refresh", Handler: worker.WorkerRefreshKeyHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/install/command", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerInstallCommandHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/install/refresh", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerRefreshKeyHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/credential/list", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerCredentialListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/credential/revoke", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerCredentialRevokeHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/worker/credential/approveReenroll", Handler: rbac.Require(model.PermWorkerManage, worker.WorkerCredentialApproveReenrollHandler(svcCtx))},
 
 		// 在线API搜索
-		{Method: http.MethodPost, Path: "/api/v1/onlineapi/search", Handler: onlineapi.OnlineSearchHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/onlineapi/import", Handler: onlineapi.OnlineImportHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/onlineapi/importAll", Handler: onlineapi.OnlineImportAllHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/onlineapi/config/list", Handler: onlineapi.APIConfigListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/onlineapi/config/save", Handler: onlineapi.APIConfigSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/onlineapi/search", Handler: rbac.Require(model.PermView, onlineapi.OnlineSearchHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/onlineapi/import", Handler: rbac.Require(model.PermAssetManage, onlineapi.OnlineImportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/onlineapi/importAll", Handler: rbac.Require(model.PermAssetManage, onlineapi.OnlineImportAllHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/onlineapi/config/list", Handler: rbac.Require(model.PermSettings, onlineapi.APIConfigListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/onlineapi/config/save", Handler: rbac.Require(model.PermSettings, onlineapi.APIConfigSaveHandler(svcCtx))},
 
 		// POC标签映射
-		{Method: http.MethodPost, Path: "/api/v1/poc/tagmapping/list", Handler: poc.TagMappingListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/tagmapping/save", Handler: poc.TagMappingSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/tagmapping/delete", Handler: poc.TagMappingDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/poc/tagmapping/list", Handler: rbac.Require(model.PermView, poc.TagMappingListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/tagmapping/save", Handler: rbac.Require(model.PermPocManage, poc.TagMappingSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/tagmapping/delete", Handler: rbac.Require(model.PermPocManage, poc.TagMappingDeleteHandler(svcCtx))},
 
 		// 自定义POC
-		{Method: http.MethodPost, Path: "/api/v1/poc/custom/list", Handler: poc.CustomPocListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/custom/save", Handler: poc.CustomPocSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/custom/delete", Handler: poc.CustomPocDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/custom/batchImport", Handler: poc.CustomPocBatchImportHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/custom/clearAll", Handler: poc.CustomPocClearAllHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/custom/scanAssets", Handler: poc.CustomPocScanAssetsHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/poc/custom/list", Handler: rbac.Require(model.PermView, poc.CustomPocListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/custom/save", Handler: rbac.Require(model.PermPocManage, poc.CustomPocSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/custom/delete", Handler: rbac.Require(model.PermPocManage, poc.CustomPocDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/custom/batchImport", Handler: rbac.Require(model.PermPocManage, poc.CustomPocBatchImportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/custom/clearAll", Handler: rbac.Require(model.PermPocManage, poc.CustomPocClearAllHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/custom/scanAssets", Handler: rbac.Require(model.PermTaskManage, poc.CustomPocScanAssetsHandler(svcCtx))},
 
 		// Nuclei默认模板
-		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/templates", Handler: poc.NucleiTemplateListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/categories", Handler: poc.NucleiTemplateCategoriesHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/sync", Handler: poc.NucleiTemplateSyncHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/download", Handler: poc.NucleiTemplateDownloadHandler(svcCtx)},
-		{Method: http.MethodGet, Path: "/api/v1/poc/nuclei/download/status", Handler: poc.NucleiTemplateDownloadStatusHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/clear", Handler: poc.NucleiTemplateClearHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/updateEnabled", Handler: poc.NucleiTemplateUpdateEnabledHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/detail", Handler: poc.NucleiTemplateDetailHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/templates", Handler: rbac.Require(model.PermView, poc.NucleiTemplateListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/categories", Handler: rbac.Require(model.PermView, poc.NucleiTemplateCategoriesHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/sync", Handler: rbac.Require(model.PermPocManage, poc.NucleiTemplateSyncHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/download", Handler: rbac.Require(model.PermPocManage, poc.NucleiTemplateDownloadHandler(svcCtx))},
+		{Method: http.MethodGet, Path: "/api/v1/poc/nuclei/download/status", Handler: rbac.Require(model.PermView, poc.NucleiTemplateDownloadStatusHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/clear", Handler: rbac.Require(model.PermPocManage, poc.NucleiTemplateClearHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/updateEnabled", Handler: rbac.Require(model.PermPocManage, poc.NucleiTemplateUpdateEnabledHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/nuclei/detail", Handler: rbac.Require(model.PermView, poc.NucleiTemplateDetailHandler(svcCtx))},
 
 		// 指纹管理
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/list", Handler: fingerprint.FingerprintListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/save", Handler: fingerprint.FingerprintSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/delete", Handler: fingerprint.FingerprintDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/categories", Handler: fingerprint.FingerprintCategoriesHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/sync", Handler: fingerprint.FingerprintSyncHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/updateEnabled", Handler: fingerprint.FingerprintUpdateEnabledHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/batchUpdateEnabled", Handler: fingerprint.FingerprintBatchUpdateEnabledHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/import", Handler: fingerprint.FingerprintImportHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/importFromFile", Handler: fingerprint.FingerprintImportFromFileHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/clearCustom", Handler: fingerprint.FingerprintClearCustomHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/validate", Handler: fingerprint.FingerprintValidateHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/batchValidate", Handler: fingerprint.FingerprintBatchValidateHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/matchAssets", Handler: fingerprint.FingerprintMatchAssetsHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/list", Handler: rbac.Require(model.PermView, fingerprint.FingerprintListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/save", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/delete", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/categories", Handler: rbac.Require(model.PermView, fingerprint.FingerprintCategoriesHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/sync", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintSyncHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/updateEnabled", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintUpdateEnabledHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/batchUpdateEnabled", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintBatchUpdateEnabledHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/import", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintImportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/importFromFile", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintImportFromFileHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/clearCustom", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintClearCustomHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/validate", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintValidateHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/batchValidate", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintBatchValidateHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/matchAssets", Handler: rbac.Require(model.PermPocManage, fingerprint.FingerprintMatchAssetsHandler(svcCtx))},
+
+		// 子域接管签名
+		{Method: http.MethodPost, Path: "/api/v1/takeover/signature/list", Handler: rbac.Require(model.PermView, fingerprint.TakeoverSignatureListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/takeover/signature/save", Handler: rbac.Require(model.PermPocManage, fingerprint.TakeoverSignatureSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/takeover/signature/delete", Handler: rbac.Require(model.PermPocManage, fingerprint.TakeoverSignatureDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/takeover/signature/updateEnabled", Handler: rbac.Require(model.PermPocManage, fingerprint.TakeoverSignatureUpdateEnabledHandler(svcCtx))},
+
+		// 敏感信息规则
+		{Method: http.MethodPost, Path: "/api/v1/secret/rule/list", Handler: rbac.Require(model.PermView, fingerprint.SecretRuleListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/secret/rule/save", Handler: rbac.Require(model.PermPocManage, fingerprint.SecretRuleSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/secret/rule/delete", Handler: rbac.Require(model.PermPocManage, fingerprint.SecretRuleDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/secret/rule/updateEnabled", Handler: rbac.Require(model.PermPocManage, fingerprint.SecretRuleUpdateEnabledHandler(svcCtx))},
+
+		// CDN识别规则
+		{Method: http.MethodPost, Path: "/api/v1/cdn/provider/list", Handler: rbac.Require(model.PermView, fingerprint.CDNProviderListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/cdn/provider/save", Handler: rbac.Require(model.PermPocManage, fingerprint.CDNProviderSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/cdn/provider/delete", Handler: rbac.Require(model.PermPocManage, fingerprint.CDNProviderDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/cdn/provider/updateEnabled", Handler: rbac.Require(model.PermPocManage, fingerprint.CDNProviderUpdateEnabledHandler(svcCtx))},
+
+		// IP归属地数据集
+		{Method: http.MethodPost, Path: "/api/v1/geoip/dataset/list", Handler: rbac.Require(model.PermView, geoip.GeoIPDatasetListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/geoip/dataset/upload", Handler: rbac.Require(model.PermPocManage, geoip.GeoIPDatasetUploadHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/geoip/dataset/delete", Handler: rbac.Require(model.PermPocManage, geoip.GeoIPDatasetDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/geoip/dataset/updateEnabled", Handler: rbac.Require(model.PermPocManage, geoip.GeoIPDatasetUpdateEnabledHandler(svcCtx))},
 
 		// POC验证
-		{Method: http.MethodPost, Path: "/api/v1/poc/custom/validate", Handler: poc.PocValidateHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/custom/validateSyntax", Handler: poc.ValidatePocSyntaxHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/batchValidate", Handler: poc.PocBatchValidateHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/poc/queryResult", Handler: poc.PocValidationResultQueryHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/poc/custom/validate", Handler: rbac.Require(model.PermTaskManage, poc.PocValidateHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/custom/validateSyntax", Handler: rbac.Require(model.PermView, poc.ValidatePocSyntaxHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/batchValidate", Handler: rbac.Require(model.PermTaskManage, poc.PocBatchValidateHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/poc/queryResult", Handler: rbac.Require(model.PermView, poc.PocValidationResultQueryHandler(svcCtx))},
 
 		// HTTP服务映射（旧接口，保持兼容）
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/httpservice/list", Handler: fingerprint.HttpServiceMappingListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/httpservice/save", Handler: fingerprint.HttpServiceMappingSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/httpservice/delete", Handler: fingerprint.HttpServiceMappingDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/httpservice/list", Handler: rbac.Require(model.PermView, fingerprint.HttpServiceMappingListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/httpservice/save", Handler: rbac.Require(model.PermPocManage, fingerprint.HttpServiceMappingSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/httpservice/delete", Handler: rbac.Require(model.PermPocManage, fingerprint.HttpServiceMappingDeleteHandler(svcCtx))},
 
 		// HTTP服务设置（新接口）
-		{Method: http.MethodGet, Path: "/api/v1/httpservice/config", Handler: fingerprint.HttpServiceConfigGetHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/httpservice/config", Handler: fingerprint.HttpServiceConfigSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/httpservice/mapping/list", Handler: fingerprint.HttpServiceMappingListV2Handler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/httpservice/mapping/save", Handler: fingerprint.HttpServiceMappingSaveV2Handler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/httpservice/mapping/delete", Handler: fingerprint.HttpServiceMappingDeleteV2Handler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/httpservice/export", Handler: fingerprint.HttpServiceExportHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/httpservice/import", Handler: fingerprint.HttpServiceImportHandler(svcCtx)},
+		{Method: http.MethodGet, Path: "/api/v1/httpservice/config", Handler: rbac.Require(model.PermView, fingerprint.HttpServiceConfigGetHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/httpservice/config", Handler: rbac.Require(model.PermSettings, fingerprint.HttpServiceConfigSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/httpservice/mapping/list", Handler: rbac.Require(model.PermView, fingerprint.HttpServiceMappingListV2Handler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/httpservice/mapping/save", Handler: rbac.Require(model.PermPocManage, fingerprint.HttpServiceMappingSaveV2Handler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/httpservice/mapping/delete", Handler: rbac.Require(model.PermPocManage, fingerprint.HttpServiceMappingDeleteV2Handler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/httpservice/export", Handler: rbac.Require(model.PermView, fingerprint.HttpServiceExportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/httpservice/import", Handler: rbac.Require(model.PermPocManage, fingerprint.HttpServiceImportHandler(svcCtx))},
 
 		// 主动扫描指纹
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/list", Handler: fingerprint.ActiveFingerprintListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/save", Handler: fingerprint.ActiveFingerprintSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/delete", Handler: fingerprint.ActiveFingerprintDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/import", Handler: fingerprint.ActiveFingerprintImportHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/export", Handler: fingerprint.ActiveFingerprintExportHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/clear", Handler: fingerprint.ActiveFingerprintClearHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/validate", Handler: fingerprint.ActiveFingerprintValidateHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/list", Handler: rbac.Require(model.PermView, fingerprint.ActiveFingerprintListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/save", Handler: rbac.Require(model.PermPocManage, fingerprint.ActiveFingerprintSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/delete", Handler: rbac.Require(model.PermPocManage, fingerprint.ActiveFingerprintDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/import", Handler: rbac.Require(model.PermPocManage, fingerprint.ActiveFingerprintImportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/export", Handler: rbac.Require(model.PermView, fingerprint.ActiveFingerprintExportHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/clear", Handler: rbac.Require(model.PermPocManage, fingerprint.ActiveFingerprintClearHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/fingerprint/active/validate", Handler: rbac.Require(model.PermPocManage, fingerprint.ActiveFingerprintValidateHandler(svcCtx))},
 
 		// 报告管理
-		{Method: http.MethodPost, Path: "/api/v1/report/detail", Handler: report.ReportDetailHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/report/export", Handler: report.ReportExportHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/report/detail", Handler: rbac.Require(model.PermView, report.ReportDetailHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/report/export", Handler: rbac.Require(model.PermView, report.ReportExportHandler(svcCtx))},
 
 		// Subfinder数据源配置
-		{Method: http.MethodPost, Path: "/api/v1/subfinder/provider/list", Handler: subfinder.SubfinderProviderListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/subfinder/provider/save", Handler: subfinder.SubfinderProviderSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/subfinder/provider/info", Handler: subfinder.SubfinderProviderInfoHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/subfinder/provider/list", Handler: rbac.Require(model.PermSettings, subfinder.SubfinderProviderListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/subfinder/provider/save", Handler: rbac.Require(model.PermSettings, subfinder.SubfinderProviderSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/subfinder/provider/info", Handler: rbac.Require(model.PermSettings, subfinder.SubfinderProviderInfoHandler(svcCtx))},
 
 		// AI辅助
-		{Method: http.MethodPost, Path: "/api/v1/ai/generatePoc", Handler: ai.GeneratePocHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/ai/config/get", Handler: ai.AIConfigGetHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/ai/config/save", Handler: ai.AIConfigSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/ai/generatePoc", Handler: rbac.Require(model.PermPocManage, ai.GeneratePocHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/ai/config/get", Handler: rbac.Require(model.PermSettings, ai.AIConfigGetHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/ai/config/save", Handler: rbac.Require(model.PermSettings, ai.AIConfigSaveHandler(svcCtx))},
 
 		// 目录扫描字典
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/list", Handler: dirscan.DirScanDictListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/save", Handler: dirscan.DirScanDictSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/delete", Handler: dirscan.DirScanDictDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/clear", Handler: dirscan.DirScanDictClearHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/enabled", Handler: dirscan.DirScanDictEnabledListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/list", Handler: rbac.Require(model.PermView, dirscan.DirScanDictListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/save", Handler: rbac.Require(model.PermPocManage, dirscan.DirScanDictSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/delete", Handler: rbac.Require(model.PermPocManage, dirscan.DirScanDictDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/clear", Handler: rbac.Require(model.PermPocManage, dirscan.DirScanDictClearHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/enabled", Handler: rbac.Require(model.PermPocManage, dirscan.DirScanDictEnabledListHandler(svcCtx))},
 
 		// 子域名字典
-		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/list", Handler: subdomain.SubdomainDictListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/save", Handler: subdomain.SubdomainDictSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/delete", Handler: subdomain.SubdomainDictDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/clear", Handler: subdomain.SubdomainDictClearHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/enabled", Handler: subdomain.SubdomainDictEnabledListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/list", Handler: rbac.Require(model.PermView, subdomain.SubdomainDictListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/save", Handler: rbac.Require(model.PermPocManage, subdomain.SubdomainDictSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/delete", Handler: rbac.Require(model.PermPocManage, subdomain.SubdomainDictDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/clear", Handler: rbac.Require(model.PermPocManage, subdomain.SubdomainDictClearHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/subdomain/dict/enabled", Handler: rbac.Require(model.PermPocManage, subdomain.SubdomainDictEnabledListHandler(svcCtx))},
+
+		// 弱口令字典
+		{Method: http.MethodPost, Path: "/api/v1/bruteforce/dict/list", Handler: rbac.Require(model.PermView, bruteforce.BruteforceDictListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/bruteforce/dict/save", Handler: rbac.Require(model.PermPocManage, bruteforce.BruteforceDictSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/bruteforce/dict/delete", Handler: rbac.Require(model.PermPocManage, bruteforce.BruteforceDictDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/bruteforce/dict/clear", Handler: rbac.Require(model.PermPocManage, bruteforce.BruteforceDictClearHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/bruteforce/dict/enabled", Handler: rbac.Require(model.PermPocManage, bruteforce.BruteforceDictEnabledListHandler(svcCtx))},
 
 		// 目录扫描结果
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/list", Handler: dirscan.DirScanResultListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/stat", Handler: dirscan.DirScanResultStatHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/delete", Handler: dirscan.DirScanResultDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/batchDelete", Handler: dirscan.DirScanResultBatchDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/clear", Handler: dirscan.DirScanResultClearHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/list", Handler: rbac.Require(model.PermView, dirscan.DirScanResultListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/stat", Handler: rbac.Require(model.PermView, dirscan.DirScanResultStatHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/delete", Handler: rbac.Require(model.PermAssetDelete, dirscan.DirScanResultDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/batchDelete", Handler: rbac.Require(model.PermAssetDelete, dirscan.DirScanResultBatchDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/clear", Handler: rbac.Require(model.PermAssetDelete, dirscan.DirScanResultClearHandler(svcCtx))},
 
 		// 通知配置
-		{Method: http.MethodPost, Path: "/api/v1/notify/config/list", Handler: notify.NotifyConfigListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/notify/config/save", Handler: notify.NotifyConfigSaveHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/notify/config/delete", Handler: notify.NotifyConfigDeleteHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/notify/config/test", Handler: notify.NotifyConfigTestHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/notify/providers", Handler: notify.NotifyProviderListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/notify/highrisk/config/get", Handler: notify.HighRiskFilterConfigGetHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/notify/highrisk/config/save", Handler: notify.HighRiskFilterConfigSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/notify/config/list", Handler: rbac.Require(model.PermNotifyManage, notify.NotifyConfigListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/notify/config/save", Handler: rbac.Require(model.PermNotifyManage, notify.NotifyConfigSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/notify/config/delete", Handler: rbac.Require(model.PermNotifyManage, notify.NotifyConfigDeleteHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/notify/config/test", Handler: rbac.Require(model.PermNotifyManage, notify.NotifyConfigTestHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/notify/providers", Handler: rbac.Require(model.PermView, notify.NotifyProviderListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/notify/highrisk/config/get", Handler: rbac.Require(model.PermNotifyManage, notify.HighRiskFilterConfigGetHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/notify/highrisk/config/save", Handler: rbac.Require(model.PermNotifyManage, notify.HighRiskFilterConfigSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/notify/route/list", Handler: rbac.Require(model.PermNotifyManage, notify.NotifyRouteListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/notify/route/save", Handler: rbac.Require(model.PermNotifyManage, notify.NotifyRouteSaveHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/notify/route/delete", Handler: rbac.Require(model.PermNotifyManage, notify.NotifyRouteDeleteHandler(svcCtx))},
 
 		// 全局主题配置（需要认证才能保存）
-		{Method: http.MethodPost, Path: "/api/v1/theme/config/save", Handler: notify.ThemeConfigSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/theme/config/save", Handler: rbac.Require(model.PermSettings, notify.ThemeConfigSaveHandler(svcCtx))},
 
 		// 资产指纹和端口统计
-		{Method: http.MethodPost, Path: "/api/v1/asset/fingerprints/list", Handler: asset.AssetFingerprintsListHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/asset/ports/stats", Handler: asset.AssetPortsStatsHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/asset/fingerprints/list", Handler: rbac.Require(model.PermView, asset.AssetFingerprintsListHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/asset/ports/stats", Handler: rbac.Require(model.PermView, asset.AssetPortsStatsHandler(svcCtx))},
 
 		// 全局黑名单
-		{Method: http.MethodPost, Path: "/api/v1/blacklist/config/get", Handler: blacklist.BlacklistConfigGetHandler(svcCtx)},
-		{Method: http.MethodPost, Path: "/api/v1/blacklist/config/save", Handler: blacklist.BlacklistConfigSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/blacklist/config/get", Handler: rbac.Require(model.PermView, blacklist.BlacklistConfigGetHandler(svcCtx))},
+		{Method: http.MethodPost, Path: "/api/v1/blacklist/config/save", Handler: rbac.Require(model.PermSettings, blacklist.BlacklistConfigSaveHandler(svcCtx))},
 	}
 
 	// 为每个路由包装认证中间件
@@ -402,7 +487,7 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 
 	server.AddRoutes(adminRoutes)
 
-	// Worker控制台路由（需要认证 + 管理员权限）
+	// Worker控制台路由（需要认证 + 控制台权限）
 	consoleAuthMiddleware := middleware.NewConsoleAuthMiddleware(svcCtx.RedisClient)
 	consoleRoutes := []rest.Route{
 		// Worker控制台信息
@@ -418,19 +503,20 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		{Method: http.MethodPost, Path: "/api/v1/worker/console/terminal/close", Handler: worker.WorkerTerminalCloseHandler(svcCtx, WorkerWSHandlerInstance)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/console/terminal/exec", Handler: worker.WorkerTerminalExecHandler(svcCtx, WorkerWSHandlerInstance)},
 		{Method: http.MethodGet, Path: "/api/v1/worker/console/terminal/history", Handler: worker.WorkerTerminalHistoryHandler(svcCtx)},
+		{Method: http.MethodGet, Path: "/api/v1/worker/console/terminal/recording", Handler: worker.WorkerTerminalRecordingHandler(svcCtx)},
 		// 审计日志
 		{Method: http.MethodGet, Path: "/api/v1/worker/console/audit", Handler: worker.WorkerAuditLogHandler(svcCtx)},
 		{Method: http.MethodDelete, Path: "/api/v1/worker/console/audit", Handler: worker.WorkerAuditLogClearHandler(svcCtx)},
 	}
 
-	// 为控制台路由包装认证中间件和管理员权限中间件
+	// 为控制台路由包装认证中间件和控制台权限中间件
 	for i := range consoleRoutes {
 		originalHandler := consoleRoutes[i].Handler
 		consoleRoutes[i].Handler = func(w http.ResponseWriter, r *http.Request) {
 			// 先进行JWT认证
 			authMiddleware.Handle(func(w http.ResponseWriter, r *http.Request) {
-				// 再进行管理员权限检查
-				consoleAuthMiddleware.Handle(http.HandlerFunc(originalHandler)).ServeHTTP(w, r)
+				// 再进行控制台权限检查
+				rbac.Require(model.PermWorkerConsole, consoleAuthMiddleware.Handle(http.HandlerFunc(originalHandler))).ServeHTTP(w, r)
 			}).ServeHTTP(w, r)
 		}
 	}
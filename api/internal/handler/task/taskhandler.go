@@ -1,14 +1,17 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 
 	"cscan/api/internal/logic"
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
+	"cscan/model"
 	"cscan/pkg/response"
 
 	"github.com/zeromicro/go-zero/rest/httpx"
@@ -364,6 +367,86 @@ func TaskLogsStreamHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
+// TaskResultsStreamHandler SSE实时推送任务结果入库事件，id 为主任务ID
+// 只能订阅当前用户有查看权限的工作空间中的任务
+func TaskResultsStreamHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mainTaskId := r.URL.Query().Get("id")
+		if mainTaskId == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+		if status, msg := authorizeMainTask(r.Context(), svcCtx, mainTaskId); status != http.StatusOK {
+			http.Error(w, msg, status)
+			return
+		}
+
+		// 设置SSE响应头
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("X-Accel-Buffering", "no")
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		flusher.Flush()
+
+		// 先发送最近的结果事件
+		events, err := svcCtx.RedisClient.XRevRangeN(r.Context(), svc.TaskResultStreamKey(mainTaskId), "+", "-", 100).Result()
+		if err == nil && len(events) > 0 {
+			for i := len(events) - 1; i >= 0; i-- {
+				if data, ok := events[i].Values["data"].(string); ok {
+					fmt.Fprintf(w, "data: %s\n\n", data)
+				}
+			}
+			flusher.Flush()
+		}
+
+		pubsub := svcCtx.RedisClient.Subscribe(r.Context(), svc.TaskResultStreamChannel(mainTaskId))
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				fmt.Fprintf(w, ": heartbeat\n\n")
+				flusher.Flush()
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				fmt.Fprintf(w, "data: %s\n\n", msg.Payload)
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// authorizeMainTask 在请求的工作空间（全部空间时逐个查找）中查找主任务，并校验任务所在工作空间的查看权限
+func authorizeMainTask(ctx context.Context, svcCtx *svc.ServiceContext, mainTaskId string) (int, string) {
+	for _, wsId := range common.GetWorkspaceIds(ctx, svcCtx, middleware.GetWorkspaceId(ctx)) {
+		task, err := svcCtx.GetMainTaskModel(wsId).FindById(ctx, mainTaskId)
+		if err != nil || task == nil {
+			continue
+		}
+		if !middleware.AuthorizeWorkspace(ctx, wsId, model.PermView) {
+			return http.StatusForbidden, "无权查看该任务"
+		}
+		return http.StatusOK, ""
+	}
+	return http.StatusNotFound, "任务不存在"
+}
+
 // ChunkProgressHandler 获取任务分片进度
 func ChunkProgressHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -401,3 +484,23 @@ func ChunkPreviewHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		httpx.OkJson(w, resp)
 	}
 }
+
+// TaskDiffHandler 对比定时任务两次执行的结果
+func TaskDiffHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.TaskDiffReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewTaskDiffLogic(r.Context(), svcCtx)
+		resp, err := l.TaskDiff(&req, workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
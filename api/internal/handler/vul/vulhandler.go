@@ -132,3 +132,75 @@ func VulStatHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		httpx.OkJson(w, resp)
 	}
 }
+
+// VulTriageHandler 变更漏洞处置状态
+func VulTriageHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulTriageReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+		if len(req.Ids) == 0 {
+			response.Error(w, xerr.NewParamError("请选择漏洞"))
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewVulLogic(r.Context(), svcCtx)
+		resp, err := l.VulTriage(&req, workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulAssignHandler 指派漏洞负责人
+func VulAssignHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulAssignReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+		if len(req.Ids) == 0 {
+			response.Error(w, xerr.NewParamError("请选择漏洞"))
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewVulLogic(r.Context(), svcCtx)
+		resp, err := l.VulAssign(&req, workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulCommentHandler 添加漏洞评论
+func VulCommentHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulCommentReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+		if req.Id == "" {
+			response.Error(w, xerr.NewParamError("漏洞ID不能为空"))
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewVulLogic(r.Context(), svcCtx)
+		resp, err := l.VulComment(&req, workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
@@ -0,0 +1,76 @@
+package vul
+
+import (
+	"io"
+	"net/http"
+
+	"cscan/api/internal/logic"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/pkg/response"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// VulnFeedImportHandler 导入离线漏洞情报（multipart 表单）
+func VulnFeedImportHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulnFeedImportReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			response.ParamError(w, "请选择情报文件")
+			return
+		}
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		if err != nil {
+			response.ParamError(w, "读取情报文件失败")
+			return
+		}
+
+		l := logic.NewVulnFeedLogic(r.Context(), svcCtx)
+		resp, err := l.VulnFeedImport(&req, header.Filename, content)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulnFeedStatsHandler 漏洞情报库统计
+func VulnFeedStatsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewVulnFeedLogic(r.Context(), svcCtx)
+		resp, err := l.VulnFeedStats()
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulnFeedClearHandler 清空漏洞情报
+func VulnFeedClearHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulnFeedClearReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewVulnFeedLogic(r.Context(), svcCtx)
+		resp, err := l.VulnFeedClear(&req)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
@@ -2,13 +2,18 @@ package worker
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
 	"cscan/model"
 	"cscan/pkg/response"
+	"cscan/pkg/utils"
 	"cscan/rpc/task/pb"
+	"cscan/scanner"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/rest/httpx"
@@ -61,6 +66,10 @@ type WorkerFingerprintDocument struct {
 	Url       []string          `json:"url"`
 	IsBuiltin bool              `json:"isBuiltin"`
 	Enabled   bool              `json:"enabled"`
+
+	// 版本识别，RPC 不传递，由此处按指纹ID补充
+	CPE        string                   `json:"cpe,omitempty"`
+	Extractors []model.VersionExtractor `json:"extractors,omitempty"`
 }
 
 // WorkerFingerprintsResp 指纹获取响应
@@ -287,6 +296,22 @@ func WorkerConfigFingerprintsHandler(svcCtx *svc.ServiceContext) http.HandlerFun
 			})
 		}
 
+		// 补充CPE和版本提取规则，用于Worker生成带版本的CPE
+		if rules, err := svcCtx.FingerprintModel.FindVersionRules(r.Context()); err != nil {
+			logx.Errorf("[WorkerConfigFingerprints] FindVersionRules error: %v", err)
+		} else if len(rules) > 0 {
+			byId := make(map[string]*model.Fingerprint, len(rules))
+			for i := range rules {
+				byId[rules[i].Id.Hex()] = &rules[i]
+			}
+			for i := range fingerprints {
+				if rule := byId[fingerprints[i].Id]; rule != nil {
+					fingerprints[i].CPE = rule.CPE
+					fingerprints[i].Extractors = rule.Extractors
+				}
+			}
+		}
+
 		httpx.OkJson(w, &WorkerFingerprintsResp{
 			Code:         0,
 			Msg:          "success",
@@ -744,3 +769,397 @@ func WorkerConfigSubdomainDictHandler(svcCtx *svc.ServiceContext) http.HandlerFu
 		})
 	}
 }
+
+// ==================== External Scanner Config Types ====================
+
+// WorkerExternalScannersReq 外部扫描器声明获取请求
+type WorkerExternalScannersReq struct {
+	Names []string `json:"names"` // 扫描器名称列表
+}
+
+// WorkerExternalScannersResp 外部扫描器声明获取响应
+type WorkerExternalScannersResp struct {
+	Code     int                            `json:"code"`
+	Msg      string                         `json:"msg"`
+	Scanners []*scanner.ExternalScannerSpec `json:"scanners"`
+}
+
+// ==================== External Scanner Handler ====================
+
+// WorkerConfigExternalScannersHandler 外部扫描器声明获取接口，只返回已启用的扫描器
+// POST /api/v1/worker/config/externalscanners
+func WorkerConfigExternalScannersHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerExternalScannersReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerExternalScannersResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if len(req.Names) == 0 {
+			httpx.OkJson(w, &WorkerExternalScannersResp{Code: 400, Msg: "names不能为空"})
+			return
+		}
+
+		docs, err := svcCtx.ExternalScannerModel.FindEnabledByNames(r.Context(), req.Names)
+		if err != nil {
+			logx.Errorf("[WorkerConfigExternalScanners] FindEnabledByNames error: %v", err)
+			httpx.OkJson(w, &WorkerExternalScannersResp{Code: 500, Msg: "获取外部扫描器失败"})
+			return
+		}
+
+		specs := make([]*scanner.ExternalScannerSpec, 0, len(docs))
+		for _, d := range docs {
+			specs = append(specs, &scanner.ExternalScannerSpec{
+				Name:        d.Name,
+				Description: d.Description,
+				Binary:      d.Binary,
+				Args:        d.Args,
+				Env:         d.Env,
+				InputFormat: d.InputFormat,
+				ResultType:  d.ResultType,
+				FieldMap:    d.FieldMap,
+				Timeout:     d.Timeout,
+			})
+		}
+
+		httpx.OkJson(w, &WorkerExternalScannersResp{
+			Code:     0,
+			Msg:      "success",
+			Scanners: specs,
+		})
+	}
+}
+
+// ==================== Scan Scope Handler ====================
+
+// WorkerScanScopeReq 扫描范围获取请求
+type WorkerScanScopeReq struct {
+	WorkspaceId string `json:"workspaceId"`
+}
+
+// WorkerScanScopeResp 扫描范围获取响应，Enabled 为 false 时不限制目标
+type WorkerScanScopeResp struct {
+	Code    int               `json:"code"`
+	Msg     string            `json:"msg"`
+	Enabled bool              `json:"enabled"`
+	Rules   *utils.ScopeRules `json:"rules,omitempty"`
+}
+
+// WorkerConfigScopeHandler 工作空间扫描范围获取接口
+// POST /api/v1/worker/config/scope
+func WorkerConfigScopeHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerScanScopeReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerScanScopeResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if req.WorkspaceId == "" {
+			req.WorkspaceId = "default"
+		}
+
+		doc, err := svcCtx.ScanScopeModel.GetByWorkspace(r.Context(), req.WorkspaceId)
+		if err != nil {
+			logx.Errorf("[WorkerConfigScope] GetByWorkspace error: %v", err)
+			httpx.OkJson(w, &WorkerScanScopeResp{Code: 500, Msg: "获取扫描范围失败"})
+			return
+		}
+		if doc == nil || !doc.Enabled {
+			httpx.OkJson(w, &WorkerScanScopeResp{Code: 0, Msg: "success"})
+			return
+		}
+
+		httpx.OkJson(w, &WorkerScanScopeResp{
+			Code:    0,
+			Msg:     "success",
+			Enabled: true,
+			Rules:   common.ScopeRules(doc),
+		})
+	}
+}
+
+// ==================== Takeover Signature Config Types ====================
+
+// WorkerTakeoverSignaturesResp 子域接管签名获取响应
+type WorkerTakeoverSignaturesResp struct {
+	Code       int                          `json:"code"`
+	Msg        string                       `json:"msg"`
+	Signatures []*scanner.TakeoverSignature `json:"signatures"`
+}
+
+// ==================== Takeover Signature Handler ====================
+
+// WorkerConfigTakeoverHandler 子域接管签名获取接口，只返回已启用的签名
+// POST /api/v1/worker/config/takeover
+func WorkerConfigTakeoverHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docs, err := svcCtx.TakeoverSignatureModel.FindEnabled(r.Context())
+		if err != nil {
+			logx.Errorf("[WorkerConfigTakeover] FindEnabled error: %v", err)
+			httpx.OkJson(w, &WorkerTakeoverSignaturesResp{Code: 500, Msg: "获取子域接管签名失败"})
+			return
+		}
+
+		signatures := make([]*scanner.TakeoverSignature, 0, len(docs))
+		for _, d := range docs {
+			signatures = append(signatures, &scanner.TakeoverSignature{
+				Service:      d.Service,
+				RecordType:   d.RecordType,
+				Patterns:     d.Patterns,
+				Fingerprints: d.Fingerprints,
+				HttpStatus:   d.HttpStatus,
+				NXDomain:     d.NXDomain,
+				Refused:      d.Refused,
+				Severity:     d.Severity,
+				Description:  d.Description,
+			})
+		}
+
+		httpx.OkJson(w, &WorkerTakeoverSignaturesResp{
+			Code:       0,
+			Msg:        "success",
+			Signatures: signatures,
+		})
+	}
+}
+
+// ==================== CDN Provider Config Types ====================
+
+// WorkerCDNProvidersResp CDN识别规则获取响应
+type WorkerCDNProvidersResp struct {
+	Code      int                    `json:"code"`
+	Msg       string                 `json:"msg"`
+	Providers []*scanner.CDNProvider `json:"providers"`
+}
+
+// ==================== CDN Provider Handler ====================
+
+// WorkerConfigCDNHandler CDN识别规则获取接口，只返回已启用的规则
+// POST /api/v1/worker/config/cdn
+func WorkerConfigCDNHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docs, err := svcCtx.CDNProviderModel.FindEnabled(r.Context())
+		if err != nil {
+			logx.Errorf("[WorkerConfigCDN] FindEnabled error: %v", err)
+			httpx.OkJson(w, &WorkerCDNProvidersResp{Code: 500, Msg: "获取CDN识别规则失败"})
+			return
+		}
+
+		providers := make([]*scanner.CDNProvider, 0, len(docs))
+		for _, d := range docs {
+			providers = append(providers, &scanner.CDNProvider{
+				Name:         d.Name,
+				Type:         d.Type,
+				CNAMEs:       d.CNAMEs,
+				CIDRs:        d.CIDRs,
+				Headers:      d.Headers,
+				BodyPatterns: d.BodyPatterns,
+			})
+		}
+
+		httpx.OkJson(w, &WorkerCDNProvidersResp{
+			Code:      0,
+			Msg:       "success",
+			Providers: providers,
+		})
+	}
+}
+
+// ==================== Secret Rule Config Types ====================
+
+// WorkerSecretRulesResp 爬虫敏感信息规则获取响应
+type WorkerSecretRulesResp struct {
+	Code  int                   `json:"code"`
+	Msg   string                `json:"msg"`
+	Rules []*scanner.SecretRule `json:"rules"`
+}
+
+// ==================== Secret Rule Handler ====================
+
+// WorkerConfigSecretRulesHandler 爬虫敏感信息规则获取接口，只返回已启用的规则
+// POST /api/v1/worker/config/secretrules
+func WorkerConfigSecretRulesHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docs, err := svcCtx.SecretRuleModel.FindEnabled(r.Context())
+		if err != nil {
+			logx.Errorf("[WorkerConfigSecretRules] FindEnabled error: %v", err)
+			httpx.OkJson(w, &WorkerSecretRulesResp{Code: 500, Msg: "获取敏感信息规则失败"})
+			return
+		}
+
+		rules := make([]*scanner.SecretRule, 0, len(docs))
+		for _, d := range docs {
+			rules = append(rules, &scanner.SecretRule{
+				Name:        d.Name,
+				Pattern:     d.Pattern,
+				Severity:    d.Severity,
+				Description: d.Description,
+			})
+		}
+
+		httpx.OkJson(w, &WorkerSecretRulesResp{
+			Code:  0,
+			Msg:   "success",
+			Rules: rules,
+		})
+	}
+}
+
+// ==================== GeoIP Dataset Config Types ====================
+
+// WorkerGeoIPDatasetItem IP归属地数据集描述，文件内容通过下载接口获取
+type WorkerGeoIPDatasetItem struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	FileName string `json:"fileName"`
+	Size     int64  `json:"size"`
+	Sha256   string `json:"sha256"`
+}
+
+// WorkerGeoIPDatasetsResp IP归属地数据集列表响应
+type WorkerGeoIPDatasetsResp struct {
+	Code     int                      `json:"code"`
+	Msg      string                   `json:"msg"`
+	Datasets []WorkerGeoIPDatasetItem `json:"datasets"`
+}
+
+// WorkerGeoIPDownloadReq IP归属地数据集下载请求
+type WorkerGeoIPDownloadReq struct {
+	Id string `json:"id"`
+}
+
+// ==================== GeoIP Dataset Handler ====================
+
+// WorkerConfigGeoIPHandler IP归属地数据集列表接口，只返回已启用的数据集
+// POST /api/v1/worker/config/geoip
+func WorkerConfigGeoIPHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docs, err := svcCtx.GeoIPDatasetModel.FindEnabled(r.Context())
+		if err != nil {
+			logx.Errorf("[WorkerConfigGeoIP] FindEnabled error: %v", err)
+			httpx.OkJson(w, &WorkerGeoIPDatasetsResp{Code: 500, Msg: "获取IP归属地数据集失败"})
+			return
+		}
+
+		datasets := make([]WorkerGeoIPDatasetItem, 0, len(docs))
+		for _, d := range docs {
+			datasets = append(datasets, WorkerGeoIPDatasetItem{
+				Id:       d.Id.Hex(),
+				Name:     d.Name,
+				Type:     d.Type,
+				FileName: d.FileName,
+				Size:     d.Size,
+				Sha256:   d.Sha256,
+			})
+		}
+
+		httpx.OkJson(w, &WorkerGeoIPDatasetsResp{
+			Code:     0,
+			Msg:      "success",
+			Datasets: datasets,
+		})
+	}
+}
+
+// WorkerConfigGeoIPDownloadHandler IP归属地数据集文件下载接口，直接返回文件内容
+// POST /api/v1/worker/config/geoip/download
+func WorkerConfigGeoIPDownloadHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerGeoIPDownloadReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Id == "" {
+			http.Error(w, "invalid dataset id", http.StatusBadRequest)
+			return
+		}
+
+		doc, err := svcCtx.GeoIPDatasetModel.FindById(r.Context(), req.Id)
+		if err != nil || !doc.Enabled {
+			http.Error(w, "dataset not found", http.StatusNotFound)
+			return
+		}
+		f, err := svcCtx.GeoIPDatasetModel.OpenFile(doc)
+		if err != nil {
+			logx.Errorf("[WorkerConfigGeoIP] open dataset %s error: %v", doc.Name, err)
+			http.Error(w, "open dataset failed", http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.FormatInt(doc.Size, 10))
+		w.Header().Set("X-Dataset-Sha256", doc.Sha256)
+		if _, err := io.Copy(w, f); err != nil {
+			logx.Errorf("[WorkerConfigGeoIP] send dataset %s error: %v", doc.Name, err)
+		}
+	}
+}
+
+// ==================== Bruteforce Dict Config Types ====================
+
+// WorkerBruteforceDictReq 弱口令字典获取请求
+type WorkerBruteforceDictReq struct {
+	DictIds []string `json:"dictIds"` // 字典ID列表，为空时返回全部已启用的字典
+}
+
+// WorkerBruteforceDictItem 弱口令字典项
+type WorkerBruteforceDictItem struct {
+	Id          string               `json:"id"`
+	Name        string               `json:"name"`
+	Service     string               `json:"service"`     // 适用协议，all 表示全部协议
+	Credentials []scanner.Credential `json:"credentials"` // 解析后的凭据列表
+}
+
+// WorkerBruteforceDictResp 弱口令字典获取响应
+type WorkerBruteforceDictResp struct {
+	Code  int                        `json:"code"`
+	Msg   string                     `json:"msg"`
+	Dicts []WorkerBruteforceDictItem `json:"dicts"`
+	Count int                        `json:"count"`
+}
+
+// ==================== Bruteforce Dict Handler ====================
+
+// WorkerConfigBruteforceDictHandler 弱口令字典配置获取接口
+// POST /api/v1/worker/config/bruteforcedict
+func WorkerConfigBruteforceDictHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerBruteforceDictReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerBruteforceDictResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+
+		ctx := r.Context()
+		dictModel := model.NewBruteforceDictModel(svcCtx.MongoDB)
+
+		var dicts []model.BruteforceDict
+		var err error
+		if len(req.DictIds) > 0 {
+			dicts, err = dictModel.FindByIds(ctx, req.DictIds)
+		} else {
+			dicts, err = dictModel.FindEnabled(ctx)
+		}
+		if err != nil {
+			logx.Errorf("[WorkerConfigBruteforceDict] find dicts error: %v", err)
+			httpx.OkJson(w, &WorkerBruteforceDictResp{Code: 500, Msg: "获取字典失败"})
+			return
+		}
+
+		items := make([]WorkerBruteforceDictItem, 0, len(dicts))
+		for _, d := range dicts {
+			items = append(items, WorkerBruteforceDictItem{
+				Id:          d.Id.Hex(),
+				Name:        d.Name,
+				Service:     d.Service,
+				Credentials: scanner.ParseCredentials(d.Content),
+			})
+		}
+
+		httpx.OkJson(w, &WorkerBruteforceDictResp{
+			Code:  0,
+			Msg:   "success",
+			Dicts: items,
+			Count: len(items),
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"cscan/api/internal/svc"
+	"cscan/pkg/notify"
 	"cscan/pkg/response"
 	"cscan/rpc/task/pb"
 
@@ -148,6 +149,13 @@ func WorkerOfflineHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 
 		logx.Infof("[WorkerOffline] Worker %s offline, deleted from Redis", req.WorkerName)
 
+		svcCtx.NotifyRoute.DispatchAsync(&notify.Event{
+			Type:   notify.EventWorkerOffline,
+			Title:  req.WorkerName,
+			Target: req.WorkerName,
+			Detail: "Worker主动下线",
+		})
+
 		httpx.OkJson(w, &WorkerOfflineResp{
 			Code:    0,
 			Msg:     "success",
@@ -66,6 +66,76 @@ func WorkerValidateKeyHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
+// WorkerEnrollHandler Worker注册，使用安装密钥换取独立凭证（Worker调用）
+func WorkerEnrollHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.WorkerEnrollReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewWorkerInstallLogic(r.Context(), svcCtx)
+		resp, err := l.EnrollWorker(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
+
+// WorkerCredentialListHandler Worker凭证列表
+func WorkerCredentialListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewWorkerCredentialLogic(r.Context(), svcCtx)
+		resp, err := l.WorkerCredentialList()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
+
+// WorkerCredentialRevokeHandler 吊销Worker凭证
+func WorkerCredentialRevokeHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.WorkerCredentialRevokeReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewWorkerCredentialLogic(r.Context(), svcCtx)
+		resp, err := l.WorkerCredentialRevoke(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
+
+// WorkerCredentialApproveReenrollHandler 批准Worker同名重新注册
+func WorkerCredentialApproveReenrollHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.WorkerCredentialApproveReq
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := logic.NewWorkerCredentialLogic(r.Context(), svcCtx)
+		resp, err := l.WorkerCredentialApproveReenroll(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
+
 // WorkerDownloadHandler Worker二进制下载
 func WorkerDownloadHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -1,17 +1,24 @@
 package worker
 
 import (
+	"context"
 	"encoding/json"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"cscan/api/internal/svc"
 	"cscan/model"
+	"cscan/pkg/geoip"
 	"cscan/pkg/response"
 	"cscan/rpc/task/pb"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/rest/httpx"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // ==================== Result Types ====================
@@ -21,39 +28,58 @@ type WorkerIPV4 struct {
 	IP       string `json:"ip"`
 	IPInt    uint32 `json:"ipInt"`
 	Location string `json:"location"`
+	Country  string `json:"country,omitempty"` // 归属地/ASN，RPC 只传递 Location，结构化字段由此处直接写入
+	Region   string `json:"region,omitempty"`
+	City     string `json:"city,omitempty"`
+	ASN      int    `json:"asn,omitempty"`
+	Org      string `json:"org,omitempty"`
 }
 
 // WorkerIPV6 IPv6信息
 type WorkerIPV6 struct {
 	IP       string `json:"ip"`
 	Location string `json:"location"`
+	Country  string `json:"country,omitempty"`
+	Region   string `json:"region,omitempty"`
+	City     string `json:"city,omitempty"`
+	ASN      int    `json:"asn,omitempty"`
+	Org      string `json:"org,omitempty"`
 }
 
 // WorkerAssetDocument 资产文档
 type WorkerAssetDocument struct {
-	Authority  string       `json:"authority"`
-	Host       string       `json:"host"`
-	Port       int32        `json:"port"`
-	Category   string       `json:"category"`
-	Service    string       `json:"service"`
-	Server     string       `json:"server"`
-	Banner     string       `json:"banner"`
-	Title      string       `json:"title"`
-	App        []string     `json:"app"`
-	HttpStatus string       `json:"httpStatus"`
-	HttpHeader string       `json:"httpHeader"`
-	HttpBody   string       `json:"httpBody"`
-	Cert       string       `json:"cert"`
-	IconHash   string       `json:"iconHash"`
-	IsCdn      bool         `json:"isCdn"`
-	Cname      string       `json:"cname"`
-	IsCloud    bool         `json:"isCloud"`
-	Ipv4       []WorkerIPV4 `json:"ipv4"`
-	Ipv6       []WorkerIPV6 `json:"ipv6"`
-	Screenshot string       `json:"screenshot"`
-	IsHttp     bool         `json:"isHttp"`
-	Source     string       `json:"source"`
-	IconData   []byte       `json:"iconData"`
+	Authority     string          `json:"authority"`
+	Host          string          `json:"host"`
+	Port          int32           `json:"port"`
+	Transport     string          `json:"transport,omitempty"` // 传输层协议: tcp/udp，为空表示tcp
+	Category      string          `json:"category"`
+	Service       string          `json:"service"`
+	Server        string          `json:"server"`
+	Banner        string          `json:"banner"`
+	Title         string          `json:"title"`
+	App           []string        `json:"app"`
+	HttpStatus    string          `json:"httpStatus"`
+	HttpHeader    string          `json:"httpHeader"`
+	HttpBody      string          `json:"httpBody"`
+	Cert          string          `json:"cert"`
+	CertInfo      *model.CertInfo `json:"certInfo,omitempty"` // 结构化证书信息，RPC 不传递，由此处直接写入
+	IconHash      string          `json:"iconHash"`
+	IsCdn         bool            `json:"isCdn"`
+	Cname         string          `json:"cname"`
+	IsCloud       bool            `json:"isCloud"`
+	CDNProvider   string          `json:"cdnProvider,omitempty"` // CDN/WAF/云厂商名称，RPC 不传递，由此处直接写入
+	CloudProvider string          `json:"cloudProvider,omitempty"`
+	WAF           string          `json:"waf,omitempty"`
+	CPE           []string        `json:"cpe,omitempty"` // 带版本应用生成的CPE，RPC 不传递，由此处匹配漏洞情报后直接写入
+	Ipv4          []WorkerIPV4    `json:"ipv4"`
+	Ipv6          []WorkerIPV6    `json:"ipv6"`
+	Screenshot    string          `json:"screenshot"`
+	IsHttp        bool            `json:"isHttp"`
+	Source        string          `json:"source"`
+	IconData      []byte          `json:"iconData"`
+
+	// 截图感知哈希，保存截图时计算，RPC 不传递，由此处直接写入
+	ScreenshotHash string `json:"-"`
 }
 
 // WorkerTaskResultReq 资产结果上报请求
@@ -134,63 +160,7 @@ func WorkerTaskResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			return
 		}
 
-		// 转换资产数据为RPC格式
-		pbAssets := make([]*pb.AssetDocument, 0, len(req.Assets))
-		for _, asset := range req.Assets {
-			pbAsset := &pb.AssetDocument{
-				Authority:  asset.Authority,
-				Host:       asset.Host,
-				Port:       asset.Port,
-				Category:   asset.Category,
-				Service:    asset.Service,
-				Server:     asset.Server,
-				Banner:     asset.Banner,
-				Title:      asset.Title,
-				App:        asset.App,
-				HttpStatus: asset.HttpStatus,
-				HttpHeader: asset.HttpHeader,
-				HttpBody:   asset.HttpBody,
-				Cert:       asset.Cert,
-				IconHash:   asset.IconHash,
-				IsCdn:      asset.IsCdn,
-				Cname:      asset.Cname,
-				IsCloud:    asset.IsCloud,
-				Screenshot: asset.Screenshot,
-				IsHttp:     asset.IsHttp,
-				Source:     asset.Source,
-				IconData:   asset.IconData,
-			}
-
-			// 转换IPv4
-			for _, ipv4 := range asset.Ipv4 {
-				pbAsset.Ipv4 = append(pbAsset.Ipv4, &pb.IPV4{
-					Ip:       ipv4.IP,
-					IpInt:    ipv4.IPInt,
-					Location: ipv4.Location,
-				})
-			}
-
-			// 转换IPv6
-			for _, ipv6 := range asset.Ipv6 {
-				pbAsset.Ipv6 = append(pbAsset.Ipv6, &pb.IPV6{
-					Ip:       ipv6.IP,
-					Location: ipv6.Location,
-				})
-			}
-
-			pbAssets = append(pbAssets, pbAsset)
-		}
-
-		// 调用RPC SaveTaskResult
-		rpcReq := &pb.SaveTaskResultReq{
-			WorkspaceId: req.WorkspaceId,
-			MainTaskId:  req.MainTaskId,
-			OrgId:       req.OrgId,
-			Assets:      pbAssets,
-			IsFinalSave: req.IsFinalSave, // 传递最终保存标志
-		}
-
-		rpcResp, err := svcCtx.TaskRpcClient.SaveTaskResult(r.Context(), rpcReq)
+		rpcResp, err := saveWorkerAssets(r.Context(), svcCtx, &req)
 		if err != nil {
 			logx.Errorf("[WorkerTaskResult] RPC SaveTaskResult error: %v", err)
 			response.Error(w, err)
@@ -208,87 +178,279 @@ func WorkerTaskResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
-// ==================== Vul Result Handler ====================
+// saveWorkerAssets 补全归属地、转存截图后通过RPC保存资产，并写入RPC消息中不包含的扩展字段
+func saveWorkerAssets(ctx context.Context, svcCtx *svc.ServiceContext, req *WorkerTaskResultReq) (*pb.SaveTaskResultResp, error) {
+	// Worker 未能识别的IP使用 API 主机的数据集补全归属地
+	if resolver := svcCtx.GeoIP.Resolver(ctx); resolver != nil {
+		for i := range req.Assets {
+			enrichAssetGeo(resolver, &req.Assets[i])
+		}
+	}
 
-// WorkerVulResultHandler 漏洞结果上报接口
-// POST /api/v1/worker/task/vul
-func WorkerVulResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var req WorkerVulResultReq
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			httpx.OkJson(w, &WorkerVulResultResp{Code: 400, Msg: "参数解析失败"})
-			return
+	// 截图写入文件存储，资产文档中只保存存储键
+	for i := range req.Assets {
+		asset := &req.Assets[i]
+		if asset.Screenshot == "" {
+			continue
 		}
+		ref, phash, err := svcCtx.Screenshots.Save(ctx, asset.Screenshot)
+		if err != nil {
+			logx.Errorf("[WorkerTaskResult] save screenshot %s error: %v", asset.Authority, err)
+		}
+		if ref != "" {
+			asset.Screenshot = ref
+		}
+		asset.ScreenshotHash = phash
+	}
 
-		if req.WorkspaceId == "" || req.MainTaskId == "" {
-			httpx.OkJson(w, &WorkerVulResultResp{Code: 400, Msg: "workspaceId和mainTaskId不能为空"})
-			return
+	// 转换资产数据为RPC格式
+	pbAssets := make([]*pb.AssetDocument, 0, len(req.Assets))
+	for _, asset := range req.Assets {
+		pbAsset := &pb.AssetDocument{
+			Authority:  asset.Authority,
+			Host:       asset.Host,
+			Port:       asset.Port,
+			Transport:  asset.Transport,
+			Category:   asset.Category,
+			Service:    asset.Service,
+			Server:     asset.Server,
+			Banner:     asset.Banner,
+			Title:      asset.Title,
+			App:        asset.App,
+			HttpStatus: asset.HttpStatus,
+			HttpHeader: asset.HttpHeader,
+			HttpBody:   asset.HttpBody,
+			Cert:       asset.Cert,
+			IconHash:   asset.IconHash,
+			IsCdn:      asset.IsCdn,
+			Cname:      asset.Cname,
+			IsCloud:    asset.IsCloud,
+			Screenshot: asset.Screenshot,
+			IsHttp:     asset.IsHttp,
+			Source:     asset.Source,
+			IconData:   asset.IconData,
 		}
 
-		// 转换漏洞数据为RPC格式
-		pbVuls := make([]*pb.VulDocument, 0, len(req.Vuls))
-		for _, vul := range req.Vuls {
-			pbVul := &pb.VulDocument{
-				Authority:        vul.Authority,
-				Host:             vul.Host,
-				Port:             vul.Port,
-				Url:              vul.Url,
-				PocFile:          vul.PocFile,
-				Source:           vul.Source,
-				Severity:         vul.Severity,
-				Extra:            vul.Extra,
-				Result:           vul.Result,
-				TaskId:           vul.TaskId,
-				References:       vul.References,
-				ExtractedResults: vul.ExtractedResults,
-			}
+		// 转换IPv4
+		for _, ipv4 := range asset.Ipv4 {
+			pbAsset.Ipv4 = append(pbAsset.Ipv4, &pb.IPV4{
+				Ip:       ipv4.IP,
+				IpInt:    ipv4.IPInt,
+				Location: ipv4.Location,
+			})
+		}
 
-			// 处理可选字段
-			if vul.CvssScore != nil {
-				pbVul.CvssScore = vul.CvssScore
-			}
-			if vul.CveId != nil {
-				pbVul.CveId = vul.CveId
-			}
-			if vul.CweId != nil {
-				pbVul.CweId = vul.CweId
-			}
-			if vul.Remediation != nil {
-				pbVul.Remediation = vul.Remediation
-			}
-			if vul.MatcherName != nil {
-				pbVul.MatcherName = vul.MatcherName
+		// 转换IPv6
+		for _, ipv6 := range asset.Ipv6 {
+			pbAsset.Ipv6 = append(pbAsset.Ipv6, &pb.IPV6{
+				Ip:       ipv6.IP,
+				Location: ipv6.Location,
+			})
+		}
+
+		pbAssets = append(pbAssets, pbAsset)
+	}
+
+	// 调用RPC SaveTaskResult
+	rpcReq := &pb.SaveTaskResultReq{
+		WorkspaceId: req.WorkspaceId,
+		MainTaskId:  req.MainTaskId,
+		OrgId:       req.OrgId,
+		Assets:      pbAssets,
+		IsFinalSave: req.IsFinalSave, // 传递最终保存标志
+	}
+
+	rpcResp, err := svcCtx.TaskRpcClient.SaveTaskResult(ctx, rpcReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// 结构化证书信息、CDN服务商、IP归属地和CPE不在RPC消息中，资产保存后单独写入
+	workspaceId := req.WorkspaceId
+	for _, asset := range req.Assets {
+		if asset.CertInfo != nil {
+			if err := svcCtx.GetAssetModel(workspaceId).UpdateCertInfo(ctx, asset.Host, int(asset.Port), asset.Cert, asset.CertInfo); err != nil {
+				logx.Errorf("[WorkerTaskResult] UpdateCertInfo %s error: %v", asset.Authority, err)
 			}
-			if vul.CurlCommand != nil {
-				pbVul.CurlCommand = vul.CurlCommand
+		}
+		if asset.CDNProvider != "" || asset.CloudProvider != "" || asset.WAF != "" {
+			if err := svcCtx.GetAssetModel(workspaceId).UpdateCDNInfo(ctx, asset.Authority, asset.Host, int(asset.Port),
+				asset.IsCdn, asset.IsCloud, asset.CDNProvider, asset.CloudProvider, asset.WAF); err != nil {
+				logx.Errorf("[WorkerTaskResult] UpdateCDNInfo %s error: %v", asset.Authority, err)
 			}
-			if vul.Request != nil {
-				pbVul.Request = vul.Request
+		}
+		if asset.ScreenshotHash != "" {
+			if err := svcCtx.GetAssetModel(workspaceId).UpdateScreenshotHash(ctx, asset.Host, int(asset.Port), asset.ScreenshotHash); err != nil {
+				logx.Errorf("[WorkerTaskResult] UpdateScreenshotHash %s error: %v", asset.Authority, err)
 			}
-			if vul.Response != nil {
-				pbVul.Response = vul.Response
+		}
+		if ipv4, ipv6, ok := assetIPGeo(&asset); ok {
+			if err := svcCtx.GetAssetModel(workspaceId).UpdateIPGeo(ctx, asset.Authority, asset.Host, int(asset.Port), ipv4, ipv6); err != nil {
+				logx.Errorf("[WorkerTaskResult] UpdateIPGeo %s error: %v", asset.Authority, err)
 			}
-			if vul.ResponseTruncated != nil {
-				pbVul.ResponseTruncated = vul.ResponseTruncated
+		}
+		if len(asset.CPE) > 0 {
+			matchPotentialVulns(ctx, svcCtx, workspaceId, &asset)
+		}
+		// 任务未指定组织时按归属规则自动分配
+		if req.OrgId == "" {
+			attributeAssetOrg(ctx, svcCtx, workspaceId, &asset)
+		}
+	}
+
+	return rpcResp, nil
+}
+
+// matchPotentialVulns 使用资产CPE匹配离线漏洞情报，写入CPE和潜在漏洞
+// 只根据版本推断，不发送任何验证请求；本次上报的CPE会覆盖上次的匹配结果
+func matchPotentialVulns(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId string, asset *WorkerAssetDocument) {
+	vulns, err := svcCtx.VulnFeedModel.Match(ctx, asset.CPE)
+	if err != nil {
+		logx.Errorf("[WorkerTaskResult] match vuln feed %s error: %v", asset.Authority, err)
+		return
+	}
+	if err := svcCtx.GetAssetModel(workspaceId).UpdatePotentialVulns(ctx, asset.Host, int(asset.Port), asset.Transport, asset.CPE, vulns); err != nil {
+		logx.Errorf("[WorkerTaskResult] UpdatePotentialVulns %s error: %v", asset.Authority, err)
+		return
+	}
+	if len(vulns) > 0 {
+		logx.Infof("[WorkerTaskResult] %s matched %d potential vulnerabilities by CPE", asset.Authority, len(vulns))
+	}
+}
+
+// attributeAssetOrg 按归属规则设置资产的组织，未命中规则时保持原有组织
+func attributeAssetOrg(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId string, asset *WorkerAssetDocument) {
+	// ICP备案、云账号等来自导入的属性不在扫描结果中，需从已保存的资产读取
+	stored, err := svcCtx.GetAssetModel(workspaceId).FindByHostPortTransport(ctx, asset.Host, int(asset.Port), asset.Transport)
+	if err != nil && err != mongo.ErrNoDocuments {
+		logx.Errorf("[WorkerTaskResult] FindByHostPortTransport %s error: %v", asset.Authority, err)
+	}
+	orgId := svcCtx.OrgAttribution.Attribute(ctx, attributionAsset(stored, asset))
+	if orgId == "" {
+		return
+	}
+	if err := svcCtx.GetAssetModel(workspaceId).UpdateOrgId(ctx, asset.Authority, asset.Host, int(asset.Port), asset.Transport, orgId); err != nil {
+		logx.Errorf("[WorkerTaskResult] UpdateOrgId %s error: %v", asset.Authority, err)
+	}
+}
+
+// attributionAsset 以已保存的资产为基础，用本次上报的证书和IP覆盖，作为归属匹配的输入
+func attributionAsset(stored *model.Asset, asset *WorkerAssetDocument) *model.Asset {
+	doc := &model.Asset{}
+	if stored != nil {
+		doc.Domain = stored.Domain
+		doc.CertInfo = stored.CertInfo
+		doc.Ip = stored.Ip
+		doc.ICP = stored.ICP
+		doc.CloudAccount = stored.CloudAccount
+	}
+	doc.Host = asset.Host
+	if net.ParseIP(asset.Host) == nil {
+		doc.Domain = asset.Host
+	}
+	if asset.CertInfo != nil {
+		doc.CertInfo = asset.CertInfo
+	}
+	if len(asset.Ipv4) > 0 || len(asset.Ipv6) > 0 {
+		doc.Ip = model.IP{}
+		for _, ip := range asset.Ipv4 {
+			doc.Ip.IpV4 = append(doc.Ip.IpV4, model.IPV4{IPName: ip.IP})
+		}
+		for _, ip := range asset.Ipv6 {
+			doc.Ip.IpV6 = append(doc.Ip.IpV6, model.IPV6{IPName: ip.IP})
+		}
+	}
+	return doc
+}
+
+// enrichAssetGeo 补全缺少归属地的IP，host 为IP且未上报IP列表时一并补全
+func enrichAssetGeo(resolver *geoip.Resolver, asset *WorkerAssetDocument) {
+	if len(asset.Ipv4) == 0 && len(asset.Ipv6) == 0 {
+		if ip := net.ParseIP(asset.Host); ip != nil {
+			if ip.To4() != nil {
+				asset.Ipv4 = []WorkerIPV4{{IP: asset.Host}}
+			} else {
+				asset.Ipv6 = []WorkerIPV6{{IP: asset.Host}}
 			}
-			if vul.VulName != nil {
-				pbVul.VulName = vul.VulName
+		}
+	}
+	for i := range asset.Ipv4 {
+		ip := &asset.Ipv4[i]
+		if ip.Country != "" || ip.ASN != 0 {
+			continue
+		}
+		if rec := resolver.Lookup(ip.IP); rec != nil {
+			ip.Country, ip.Region, ip.City, ip.ASN, ip.Org = rec.Country, rec.Region, rec.City, rec.ASN, rec.Org
+			if ip.Location == "" {
+				ip.Location = rec.Location()
 			}
-			if len(vul.Tags) > 0 {
-				pbVul.Tags = vul.Tags
+		}
+	}
+	for i := range asset.Ipv6 {
+		ip := &asset.Ipv6[i]
+		if ip.Country != "" || ip.ASN != 0 {
+			continue
+		}
+		if rec := resolver.Lookup(ip.IP); rec != nil {
+			ip.Country, ip.Region, ip.City, ip.ASN, ip.Org = rec.Country, rec.Region, rec.City, rec.ASN, rec.Org
+			if ip.Location == "" {
+				ip.Location = rec.Location()
 			}
+		}
+	}
+}
 
-			pbVuls = append(pbVuls, pbVul)
+// assetIPGeo 转换带结构化归属地的IP列表，没有任何归属地字段时返回 false
+func assetIPGeo(asset *WorkerAssetDocument) ([]model.IPV4, []model.IPV6, bool) {
+	found := false
+	ipv4 := make([]model.IPV4, 0, len(asset.Ipv4))
+	for _, ip := range asset.Ipv4 {
+		found = found || ip.Country != "" || ip.ASN != 0
+		ipv4 = append(ipv4, model.IPV4{
+			IPName:   ip.IP,
+			IPInt:    ip.IPInt,
+			Location: ip.Location,
+			Country:  ip.Country,
+			Region:   ip.Region,
+			City:     ip.City,
+			ASN:      ip.ASN,
+			Org:      ip.Org,
+		})
+	}
+	ipv6 := make([]model.IPV6, 0, len(asset.Ipv6))
+	for _, ip := range asset.Ipv6 {
+		found = found || ip.Country != "" || ip.ASN != 0
+		ipv6 = append(ipv6, model.IPV6{
+			IPName:   ip.IP,
+			Location: ip.Location,
+			Country:  ip.Country,
+			Region:   ip.Region,
+			City:     ip.City,
+			ASN:      ip.ASN,
+			Org:      ip.Org,
+		})
+	}
+	return ipv4, ipv6, found
+}
+
+// ==================== Vul Result Handler ====================
+
+// WorkerVulResultHandler 漏洞结果上报接口
+// POST /api/v1/worker/task/vul
+func WorkerVulResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerVulResultReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerVulResultResp{Code: 400, Msg: "参数解析失败"})
+			return
 		}
 
-		// 调用RPC SaveVulResult
-		rpcReq := &pb.SaveVulResultReq{
-			WorkspaceId: req.WorkspaceId,
-			MainTaskId:  req.MainTaskId,
-			Vuls:        pbVuls,
+		if req.WorkspaceId == "" || req.MainTaskId == "" {
+			httpx.OkJson(w, &WorkerVulResultResp{Code: 400, Msg: "workspaceId和mainTaskId不能为空"})
+			return
 		}
 
-		rpcResp, err := svcCtx.TaskRpcClient.SaveVulResult(r.Context(), rpcReq)
+		rpcResp, err := saveWorkerVuls(r.Context(), svcCtx, &req)
 		if err != nil {
 			logx.Errorf("[WorkerVulResult] RPC SaveVulResult error: %v", err)
 			response.Error(w, err)
@@ -304,6 +466,74 @@ func WorkerVulResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
+// saveWorkerVuls 转换漏洞文档并通过RPC保存
+func saveWorkerVuls(ctx context.Context, svcCtx *svc.ServiceContext, req *WorkerVulResultReq) (*pb.SaveVulResultResp, error) {
+	// 转换漏洞数据为RPC格式
+	pbVuls := make([]*pb.VulDocument, 0, len(req.Vuls))
+	for _, vul := range req.Vuls {
+		pbVul := &pb.VulDocument{
+			Authority:        vul.Authority,
+			Host:             vul.Host,
+			Port:             vul.Port,
+			Url:              vul.Url,
+			PocFile:          vul.PocFile,
+			Source:           vul.Source,
+			Severity:         vul.Severity,
+			Extra:            vul.Extra,
+			Result:           vul.Result,
+			TaskId:           vul.TaskId,
+			References:       vul.References,
+			ExtractedResults: vul.ExtractedResults,
+		}
+
+		// 处理可选字段
+		if vul.CvssScore != nil {
+			pbVul.CvssScore = vul.CvssScore
+		}
+		if vul.CveId != nil {
+			pbVul.CveId = vul.CveId
+		}
+		if vul.CweId != nil {
+			pbVul.CweId = vul.CweId
+		}
+		if vul.Remediation != nil {
+			pbVul.Remediation = vul.Remediation
+		}
+		if vul.MatcherName != nil {
+			pbVul.MatcherName = vul.MatcherName
+		}
+		if vul.CurlCommand != nil {
+			pbVul.CurlCommand = vul.CurlCommand
+		}
+		if vul.Request != nil {
+			pbVul.Request = vul.Request
+		}
+		if vul.Response != nil {
+			pbVul.Response = vul.Response
+		}
+		if vul.ResponseTruncated != nil {
+			pbVul.ResponseTruncated = vul.ResponseTruncated
+		}
+		if vul.VulName != nil {
+			pbVul.VulName = vul.VulName
+		}
+		if len(vul.Tags) > 0 {
+			pbVul.Tags = vul.Tags
+		}
+
+		pbVuls = append(pbVuls, pbVul)
+	}
+
+	// 调用RPC SaveVulResult
+	rpcReq := &pb.SaveVulResultReq{
+		WorkspaceId: req.WorkspaceId,
+		MainTaskId:  req.MainTaskId,
+		Vuls:        pbVuls,
+	}
+
+	return svcCtx.TaskRpcClient.SaveVulResult(ctx, rpcReq)
+}
+
 // ==================== Dir Scan Result Types ====================
 
 // WorkerDirScanResultDocument 目录扫描结果文档
@@ -445,3 +675,143 @@ func WorkerDirScanResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		})
 	}
 }
+
+// ==================== DNS Record Result Types ====================
+
+// WorkerDNSRecord DNS记录
+type WorkerDNSRecord struct {
+	Domain string `json:"domain"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	TTL    int64  `json:"ttl"`
+}
+
+// WorkerDNSRecordResultReq DNS记录上报请求
+type WorkerDNSRecordResultReq struct {
+	WorkspaceId string            `json:"workspaceId"`
+	MainTaskId  string            `json:"mainTaskId"`
+	Records     []WorkerDNSRecord `json:"records"`
+}
+
+// ==================== DNS Record Result Handler ====================
+
+// WorkerDNSRecordResultHandler DNS记录上报接口，记录写入工作空间的DNS历史
+// POST /api/v1/worker/task/dnsrecord
+func WorkerDNSRecordResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerDNSRecordResultReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if req.MainTaskId == "" {
+			httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 400, Msg: "mainTaskId不能为空"})
+			return
+		}
+
+		docs := make([]*model.DNSRecord, 0, len(req.Records))
+		for _, rec := range req.Records {
+			if rec.Domain == "" || rec.Type == "" || rec.Value == "" {
+				continue
+			}
+			name := rec.Name
+			if name == "" {
+				name = rec.Domain
+			}
+			docs = append(docs, &model.DNSRecord{
+				Domain: strings.ToLower(rec.Domain),
+				Name:   strings.ToLower(name),
+				Type:   strings.ToUpper(rec.Type),
+				Value:  rec.Value,
+				TTL:    rec.TTL,
+			})
+		}
+
+		if err := svcCtx.GetDNSRecordModel(req.WorkspaceId).BatchUpsert(r.Context(), req.MainTaskId, docs); err != nil {
+			logx.Errorf("[WorkerDNSRecordResult] save failed: %v", err)
+			httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 500, Msg: "保存失败"})
+			return
+		}
+		logx.Infof("[WorkerDNSRecordResult] Saved %d dns records for task %s", len(docs), req.MainTaskId)
+		httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 0, Msg: "success", Success: true})
+	}
+}
+
+// ==================== URL Asset Result Types ====================
+
+// WorkerURLAsset 爬虫发现的URL端点
+type WorkerURLAsset struct {
+	URL         string   `json:"url"`
+	Method      string   `json:"method"`
+	Type        string   `json:"type"`
+	Params      []string `json:"params"`
+	StatusCode  int      `json:"statusCode"`
+	ContentType string   `json:"contentType"`
+	Source      string   `json:"source"`
+}
+
+// WorkerURLAssetResultReq URL端点上报请求
+type WorkerURLAssetResultReq struct {
+	WorkspaceId string           `json:"workspaceId"`
+	MainTaskId  string           `json:"mainTaskId"`
+	Endpoints   []WorkerURLAsset `json:"endpoints"`
+}
+
+// ==================== URL Asset Result Handler ====================
+
+// WorkerURLAssetResultHandler URL端点上报接口，端点写入工作空间的URL资产
+// POST /api/v1/worker/task/urlasset
+func WorkerURLAssetResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerURLAssetResultReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if req.MainTaskId == "" {
+			httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 400, Msg: "mainTaskId不能为空"})
+			return
+		}
+
+		docs := make([]*model.URLAsset, 0, len(req.Endpoints))
+		for _, ep := range req.Endpoints {
+			u, err := url.Parse(ep.URL)
+			if err != nil || u.Hostname() == "" {
+				continue
+			}
+			port, _ := strconv.Atoi(u.Port())
+			if port == 0 {
+				port = 80
+				if u.Scheme == "https" {
+					port = 443
+				}
+			}
+			method := strings.ToUpper(ep.Method)
+			if method == "" {
+				method = http.MethodGet
+			}
+			docs = append(docs, &model.URLAsset{
+				Authority:   net.JoinHostPort(u.Hostname(), strconv.Itoa(port)),
+				Host:        u.Hostname(),
+				Port:        port,
+				URL:         ep.URL,
+				Path:        u.Path,
+				Method:      method,
+				Type:        ep.Type,
+				Params:      ep.Params,
+				StatusCode:  ep.StatusCode,
+				ContentType: ep.ContentType,
+				Source:      ep.Source,
+			})
+		}
+
+		if err := svcCtx.GetURLAssetModel(req.WorkspaceId).BatchUpsert(r.Context(), req.MainTaskId, docs); err != nil {
+			logx.Errorf("[WorkerURLAssetResult] save failed: %v", err)
+			httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 500, Msg: "保存失败"})
+			return
+		}
+		logx.Infof("[WorkerURLAssetResult] Saved %d url assets for task %s", len(docs), req.MainTaskId)
+		httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 0, Msg: "success", Success: true})
+	}
+}
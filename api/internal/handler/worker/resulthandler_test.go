@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"testing"
+
+	"cscan/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributionAssetKeepsStoredAttributes(t *testing.T) {
+	stored := &model.Asset{
+		Host:         "www.example.com",
+		ICP:          "京ICP备12345678号-1",
+		CloudAccount: "aliyun:1234",
+		Ip:           model.IP{IpV4: []model.IPV4{{IPName: "192.0.2.1"}}},
+	}
+	reported := &WorkerAssetDocument{Host: "www.example.com", Port: 443}
+
+	doc := attributionAsset(stored, reported)
+	assert.Equal(t, "京ICP备12345678号-1", doc.ICP)
+	assert.Equal(t, "aliyun:1234", doc.CloudAccount)
+	assert.Equal(t, "www.example.com", doc.Domain)
+	assert.Equal(t, []model.IPV4{{IPName: "192.0.2.1"}}, doc.Ip.IpV4, "stored IPs are used when the result carries none")
+
+	reported.Ipv4 = []WorkerIPV4{{IP: "198.51.100.1"}}
+	doc = attributionAsset(stored, reported)
+	assert.Equal(t, []model.IPV4{{IPName: "198.51.100.1"}}, doc.Ip.IpV4, "reported IPs override stored ones")
+	assert.Equal(t, "aliyun:1234", doc.CloudAccount)
+
+	doc = attributionAsset(nil, &WorkerAssetDocument{Host: "192.0.2.1", Port: 80})
+	assert.Equal(t, "192.0.2.1", doc.Host)
+	assert.Empty(t, doc.Domain)
+	assert.Empty(t, doc.ICP)
+}
@@ -3,16 +3,23 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"cscan/api/internal/config"
+	"cscan/api/internal/logic"
 	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // TestConsoleAuthMiddleware_AdminOnly tests that console routes require admin role
@@ -121,12 +128,18 @@ func TestInstallKeyAuthentication(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateInstallKey(ctx, svcCtx, tt.installKey)
-			if tt.shouldError && err == nil {
-				t.Error("expected error but got none")
+			resp, err := logic.NewWorkerInstallLogic(ctx, svcCtx).ValidateInstallKey(&types.WorkerValidateKeyReq{
+				InstallKey: tt.installKey,
+				WorkerName: "test-worker",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.shouldError && resp.Valid {
+				t.Error("expected install key to be rejected")
 			}
-			if !tt.shouldError && err != nil {
-				t.Errorf("expected no error but got: %v", err)
+			if !tt.shouldError && !resp.Valid {
+				t.Errorf("expected install key to be accepted, got: %s", resp.Msg)
 			}
 		})
 	}
@@ -271,52 +284,134 @@ func TestConsoleConfigZeroValues(t *testing.T) {
 	}
 }
 
+// memWorkerCredentialStore 内存中的Worker凭证存储，按 model.WorkerCredentialModel 的规则只返回未吊销的凭证
+type memWorkerCredentialStore map[string]*model.WorkerCredential
+
+func (s memWorkerCredentialStore) FindActiveByToken(ctx context.Context, token string) (*model.WorkerCredential, error) {
+	doc, ok := s[token]
+	if !ok || doc.Revoked {
+		return nil, nil
+	}
+	return doc, nil
+}
+
+func (s memWorkerCredentialStore) Touch(ctx context.Context, id primitive.ObjectID, ip string) error {
+	return nil
+}
+
 // TestWorkerAuthMiddleware tests Worker authentication middleware
+// The shared install key is only accepted for enrollment, never as a request credential
 // **Validates: Requirements 11.2, 11.3**
 func TestWorkerAuthMiddleware(t *testing.T) {
-	mr, redisClient := setupTestRedis(t)
-	defer mr.Close()
-	defer redisClient.Close()
-
-	// Set up install key
-	validKey := "worker-auth-key"
-	mr.Set("cscan:worker:install_key", validKey)
-
-	workerAuth := middleware.NewWorkerAuthMiddleware(redisClient)
+	store := memWorkerCredentialStore{
+		"csw_valid":   {Id: primitive.NewObjectID(), WorkerName: "worker-a"},
+		"csw_revoked": {Id: primitive.NewObjectID(), WorkerName: "worker-a", Revoked: true},
+	}
+	workerAuth := middleware.NewWorkerAuthMiddleware(store)
+	workerAuth.TaskWorker = func(ctx context.Context, taskId string) string {
+		return map[string]string{"task-a": "worker-a", "task-b": "worker-b"}[taskId]
+	}
 
 	tests := []struct {
 		name           string
-		headerKey      string
+		header         string
+		value          string
+		path           string
+		body           string
 		expectedStatus int
+		expectedWorker string
 	}{
 		{
-			name:           "valid key should authenticate",
-			headerKey:      validKey,
+			name:           "valid credential should be accepted",
+			header:         "X-Worker-Token",
+			value:          "csw_valid",
+			body:           `{"workerName":"worker-a"}`,
 			expectedStatus: http.StatusOK,
+			expectedWorker: "worker-a",
+		},
+		{
+			name:           "revoked credential should be rejected",
+			header:         "X-Worker-Token",
+			value:          "csw_revoked",
+			body:           `{"workerName":"worker-a"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unknown credential should be rejected",
+			header:         "X-Worker-Token",
+			value:          "csw_unknown",
+			expectedStatus: http.StatusUnauthorized,
 		},
 		{
-			name:           "invalid key should fail",
-			headerKey:      "wrong-key",
+			name:           "install key should be rejected",
+			header:         "X-Worker-Key",
+			value:          "worker-auth-key",
 			expectedStatus: http.StatusUnauthorized,
 		},
 		{
-			name:           "missing key should fail",
-			headerKey:      "",
+			name:           "malformed credential should fail",
+			header:         "X-Worker-Token",
+			value:          "wrong-key",
 			expectedStatus: http.StatusUnauthorized,
 		},
+		{
+			name:           "missing credential should fail",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "other worker name should be rejected",
+			header:         "X-Worker-Token",
+			value:          "csw_valid",
+			path:           "/api/v1/worker/task/recovery",
+			body:           `{"workerName":"worker-b"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "status update as other worker should be rejected",
+			header:         "X-Worker-Token",
+			value:          "csw_valid",
+			path:           "/api/v1/worker/task/update",
+			body:           `{"taskId":"task-a","state":"SUCCESS","worker":"worker-b"}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "result stream for task of other worker should be rejected",
+			header:         "X-Worker-Token",
+			value:          "csw_valid",
+			path:           "/api/v1/worker/task/result/stream",
+			body:           `{"taskId":"task-b","streamId":"s1","seq":1}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "result stream for own task should be accepted",
+			header:         "X-Worker-Token",
+			value:          "csw_valid",
+			path:           "/api/v1/worker/task/result/stream",
+			body:           `{"taskId":"task-a","streamId":"s1","seq":1}`,
+			expectedStatus: http.StatusOK,
+			expectedWorker: "worker-a",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var gotWorker, gotBody string
 			testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotWorker = middleware.GetWorkerName(r.Context())
+				body, _ := io.ReadAll(r.Body)
+				gotBody = string(body)
 				w.WriteHeader(http.StatusOK)
 			})
 
 			handler := workerAuth.Handle(testHandler)
 
-			req := httptest.NewRequest(http.MethodPost, "/api/v1/worker/task/check", nil)
-			if tt.headerKey != "" {
-				req.Header.Set("X-Worker-Key", tt.headerKey)
+			path := tt.path
+			if path == "" {
+				path = "/api/v1/worker/task/check"
+			}
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
 			}
 
 			rr := httptest.NewRecorder()
@@ -325,6 +420,14 @@ func TestWorkerAuthMiddleware(t *testing.T) {
 			if rr.Code != tt.expectedStatus {
 				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
 			}
+			if tt.expectedStatus == http.StatusOK {
+				if gotWorker != tt.expectedWorker {
+					t.Errorf("expected worker %q in context, got %q", tt.expectedWorker, gotWorker)
+				}
+				if gotBody != tt.body {
+					t.Errorf("handler should see the original body, got %q", gotBody)
+				}
+			}
 		})
 	}
 }
@@ -447,7 +550,19 @@ func TestSessionTrackingConcurrency(t *testing.T) {
 	}
 }
 
-// setupTestServiceContext creates a test ServiceContext with console config
+// setupTestRedis starts an in-memory Redis server
+func setupTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	return mr, redis.NewClient(&redis.Options{Addr: mr.Addr()})
+}
+
+// setupTestServiceContext creates a test ServiceContext with default console config
+func setupTestServiceContext(t *testing.T, redisClient *redis.Client) *svc.ServiceContext {
+	return setupTestServiceContextWithConfig(t, redisClient)
+}
+
+// setupTestServiceContextWithConfig creates a test ServiceContext with console config
 func setupTestServiceContextWithConfig(t *testing.T, redisClient *redis.Client) *svc.ServiceContext {
 	return &svc.ServiceContext{
 		RedisClient: redisClient,
@@ -16,7 +16,7 @@ func DockerComposeWorkerHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 #
 # 环境变量:
 #   CSCAN_SERVER: API服务器地址 (必填)
-#   CSCAN_KEY: 安装密钥 (必填，从管理后台获取)
+#   CSCAN_KEY: 安装密钥 (首次注册必填，从管理后台获取；注册后使用数据卷中保存的Worker凭证)
 #   CSCAN_NAME: Worker名称 (可选，默认自动生成)
 #   CSCAN_CONCURRENCY: 并发数 (可选，默认5)
 
@@ -31,6 +31,13 @@ services:
       - CSCAN_KEY=${CSCAN_KEY}
       - CSCAN_NAME=${CSCAN_NAME:-}
       - CSCAN_CONCURRENCY=${CSCAN_CONCURRENCY:-5}
+      - CSCAN_CREDENTIAL=/app/data/worker_credential.json
+    volumes:
+      - cscan_worker_data:/app/data
+
+volumes:
+  cscan_worker_data:
+    driver: local
 `
 		w.Header().Set("Content-Type", "text/yaml; charset=utf-8")
 		w.Header().Set("Content-Disposition", "attachment; filename=docker-compose-worker.yaml")
@@ -0,0 +1,196 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cscan/api/internal/svc"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+const (
+	// MaxResultStreamBatch 单个流式批次最多包含的资产与漏洞总数
+	MaxResultStreamBatch = 500
+	// resultStreamBatchTTL 已入库批次的幂等标记保留时间，覆盖Worker重试和任务恢复窗口
+	resultStreamBatchTTL = 24 * time.Hour
+	// resultStreamSavingTTL 批次入库中标记的保留时间，API实例在入库过程中退出时标记自动失效
+	resultStreamSavingTTL = 2 * time.Minute
+)
+
+// 批次幂等标记的状态
+const (
+	resultStreamBatchSaving = "saving"
+	resultStreamBatchDone   = "done"
+)
+
+// 批次中资产和漏洞的入库实现，测试时替换
+var (
+	saveStreamAssets = saveWorkerAssets
+	saveStreamVuls   = saveWorkerVuls
+)
+
+// ==================== Result Stream Types ====================
+
+// WorkerResultStreamReq 流式结果上报请求，Worker 在扫描过程中按批次上报已产生的资产和漏洞
+type WorkerResultStreamReq struct {
+	WorkspaceId string                `json:"workspaceId"`
+	MainTaskId  string                `json:"mainTaskId"`
+	TaskId      string                `json:"taskId"`
+	OrgId       string                `json:"orgId"`
+	Phase       string                `json:"phase"`    // 产生结果的扫描阶段
+	StreamId    string                `json:"streamId"` // 单次阶段执行的流标识
+	Seq         int64                 `json:"seq"`      // 批次序号，与 streamId 组成幂等键
+	Assets      []WorkerAssetDocument `json:"assets"`
+	Vuls        []WorkerVulDocument   `json:"vuls"`
+}
+
+// WorkerResultStreamResp 流式结果上报响应
+type WorkerResultStreamResp struct {
+	Code        int    `json:"code"`
+	Msg         string `json:"msg"`
+	Success     bool   `json:"success"`
+	Duplicate   bool   `json:"duplicate"` // 该批次此前已入库，本次未重复写入
+	NewAsset    int32  `json:"newAsset"`
+	UpdateAsset int32  `json:"updateAsset"`
+	TotalVul    int32  `json:"totalVul"`
+}
+
+// ResultStreamEvent 批次入库后推送给前端的实时结果事件
+type ResultStreamEvent struct {
+	MainTaskId  string   `json:"mainTaskId"`
+	TaskId      string   `json:"taskId"`
+	Phase       string   `json:"phase"`
+	Seq         int64    `json:"seq"`
+	Authorities []string `json:"authorities"`
+	VulCount    int      `json:"vulCount"`
+	NewAsset    int32    `json:"newAsset"`
+	UpdateAsset int32    `json:"updateAsset"`
+	Timestamp   int64    `json:"timestamp"`
+}
+
+// resultStreamBatchKey 批次幂等标记键
+func resultStreamBatchKey(req *WorkerResultStreamReq) string {
+	return fmt.Sprintf("cscan:task:stream:%s:%s:%d", req.MainTaskId, req.StreamId, req.Seq)
+}
+
+// ==================== Result Stream Handler ====================
+
+// WorkerTaskResultStreamHandler 流式结果上报接口
+// POST /api/v1/worker/task/result/stream
+// 同一 streamId+seq 的批次只入库一次，Worker 超时重试不会重复累计漏洞扫描次数
+func WorkerTaskResultStreamHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerResultStreamReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerResultStreamResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if req.WorkspaceId == "" || req.MainTaskId == "" || req.StreamId == "" {
+			httpx.OkJson(w, &WorkerResultStreamResp{Code: 400, Msg: "workspaceId、mainTaskId和streamId不能为空"})
+			return
+		}
+		if n := len(req.Assets) + len(req.Vuls); n > MaxResultStreamBatch {
+			httpx.OkJson(w, &WorkerResultStreamResp{Code: 400, Msg: fmt.Sprintf("单批次结果数量不能超过%d，当前%d", MaxResultStreamBatch, n)})
+			return
+		}
+
+		resp, err := saveResultStreamBatch(r.Context(), svcCtx, &req)
+		if err != nil {
+			logx.Errorf("[WorkerResultStream] task=%s stream=%s seq=%d save failed: %v", req.MainTaskId, req.StreamId, req.Seq, err)
+			httpx.OkJson(w, &WorkerResultStreamResp{Code: 500, Msg: "保存失败: " + err.Error()})
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// saveResultStreamBatch 幂等保存一个结果批次，入库成功后推送实时事件
+// 批次先标记为入库中，全部写入成功后才标记为已入库；入库中的批次被重试时返回可重试的错误，
+// 入库失败时清除标记，资产和漏洞均按唯一键 Upsert，重复写入不影响一致性
+func saveResultStreamBatch(ctx context.Context, svcCtx *svc.ServiceContext, req *WorkerResultStreamReq) (*WorkerResultStreamResp, error) {
+	batchKey := resultStreamBatchKey(req)
+	claimed, err := svcCtx.RedisClient.SetNX(ctx, batchKey, resultStreamBatchSaving, resultStreamSavingTTL).Result()
+	if err != nil {
+		// Redis 不可用时仍然入库，仅重试时可能重复累计
+		logx.Errorf("[WorkerResultStream] claim batch %s failed: %v", batchKey, err)
+		claimed = true
+	}
+	if !claimed {
+		state, _ := svcCtx.RedisClient.Get(ctx, batchKey).Result()
+		if state == resultStreamBatchDone {
+			return &WorkerResultStreamResp{Code: 0, Msg: "duplicate batch", Success: true, Duplicate: true}, nil
+		}
+		// 上一次请求仍在入库，Worker 稍后重试时会得到最终结果
+		return &WorkerResultStreamResp{Code: 409, Msg: "批次正在入库，请稍后重试"}, nil
+	}
+
+	resp := &WorkerResultStreamResp{Code: 0, Msg: "success", Success: true}
+	if len(req.Assets) > 0 {
+		assetResp, err := saveStreamAssets(ctx, svcCtx, &WorkerTaskResultReq{
+			WorkspaceId: req.WorkspaceId,
+			MainTaskId:  req.MainTaskId,
+			OrgId:       req.OrgId,
+			Assets:      req.Assets,
+		})
+		if err != nil {
+			svcCtx.RedisClient.Del(ctx, batchKey)
+			return nil, err
+		}
+		resp.NewAsset, resp.UpdateAsset = assetResp.NewAsset, assetResp.UpdateAsset
+	}
+	if len(req.Vuls) > 0 {
+		vulResp, err := saveStreamVuls(ctx, svcCtx, &WorkerVulResultReq{
+			WorkspaceId: req.WorkspaceId,
+			MainTaskId:  req.MainTaskId,
+			Vuls:        req.Vuls,
+		})
+		if err != nil {
+			// 资产已写入，重试时会再次 Upsert，不影响一致性
+			svcCtx.RedisClient.Del(ctx, batchKey)
+			return nil, err
+		}
+		resp.TotalVul = vulResp.Total
+	}
+
+	svcCtx.RedisClient.Set(ctx, batchKey, resultStreamBatchDone, resultStreamBatchTTL)
+	publishResultStreamEvent(ctx, svcCtx, req, resp)
+	return resp, nil
+}
+
+// publishResultStreamEvent 将批次摘要写入任务结果流并发布到实时频道，供前端刷新
+func publishResultStreamEvent(ctx context.Context, svcCtx *svc.ServiceContext, req *WorkerResultStreamReq, resp *WorkerResultStreamResp) {
+	event := ResultStreamEvent{
+		MainTaskId:  req.MainTaskId,
+		TaskId:      req.TaskId,
+		Phase:       req.Phase,
+		Seq:         req.Seq,
+		Authorities: make([]string, 0, len(req.Assets)),
+		VulCount:    len(req.Vuls),
+		NewAsset:    resp.NewAsset,
+		UpdateAsset: resp.UpdateAsset,
+		Timestamp:   time.Now().UnixMilli(),
+	}
+	for _, asset := range req.Assets {
+		event.Authorities = append(event.Authorities, asset.Authority)
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		logx.Errorf("[WorkerResultStream] marshal event failed: %v", err)
+		return
+	}
+
+	svcCtx.RedisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: svc.TaskResultStreamKey(req.MainTaskId),
+		MaxLen: 1000,
+		Approx: true,
+		Values: map[string]interface{}{"data": string(data)},
+	})
+	svcCtx.RedisClient.Expire(ctx, svc.TaskResultStreamKey(req.MainTaskId), resultStreamBatchTTL)
+	svcCtx.RedisClient.Publish(ctx, svc.TaskResultStreamChannel(req.MainTaskId), string(data))
+}
@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"cscan/api/internal/svc"
+	"cscan/rpc/task/pb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubStreamSave 用内存实现替换批次入库，返回资产和漏洞的入库次数
+func stubStreamSave(t *testing.T, assetErr error) (assetCalls, vulCalls *int) {
+	t.Helper()
+	assetCalls, vulCalls = new(int), new(int)
+	origAssets, origVuls := saveStreamAssets, saveStreamVuls
+	t.Cleanup(func() { saveStreamAssets, saveStreamVuls = origAssets, origVuls })
+	saveStreamAssets = func(ctx context.Context, svcCtx *svc.ServiceContext, req *WorkerTaskResultReq) (*pb.SaveTaskResultResp, error) {
+		*assetCalls++
+		if assetErr != nil {
+			return nil, assetErr
+		}
+		return &pb.SaveTaskResultResp{Success: true, NewAsset: int32(len(req.Assets))}, nil
+	}
+	saveStreamVuls = func(ctx context.Context, svcCtx *svc.ServiceContext, req *WorkerVulResultReq) (*pb.SaveVulResultResp, error) {
+		*vulCalls++
+		return &pb.SaveVulResultResp{Success: true, Total: int32(len(req.Vuls))}, nil
+	}
+	return assetCalls, vulCalls
+}
+
+func newStreamReq(seq int64) *WorkerResultStreamReq {
+	return &WorkerResultStreamReq{
+		WorkspaceId: "ws-1",
+		MainTaskId:  "main-1",
+		TaskId:      "task-1",
+		Phase:       "portscan",
+		StreamId:    "stream-1",
+		Seq:         seq,
+		Assets:      []WorkerAssetDocument{{Authority: "10.0.0.1:80", Host: "10.0.0.1", Port: 80}},
+		Vuls:        []WorkerVulDocument{{Authority: "10.0.0.1:80", Host: "10.0.0.1", Port: 80}},
+	}
+}
+
+func TestSaveResultStreamBatch(t *testing.T) {
+	mr, redisClient := setupTestRedis(t)
+	defer redisClient.Close()
+	svcCtx := setupTestServiceContext(t, redisClient)
+	ctx := context.Background()
+	assetCalls, vulCalls := stubStreamSave(t, nil)
+
+	resp, err := saveResultStreamBatch(ctx, svcCtx, newStreamReq(1))
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.False(t, resp.Duplicate)
+	assert.Equal(t, int32(1), resp.NewAsset)
+	assert.Equal(t, int32(1), resp.TotalVul)
+	state, _ := mr.Get(resultStreamBatchKey(newStreamReq(1)))
+	assert.Equal(t, resultStreamBatchDone, state)
+
+	// 实时事件写入任务结果流
+	entries, err := redisClient.XRange(ctx, svc.TaskResultStreamKey("main-1"), "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	var event ResultStreamEvent
+	require.NoError(t, json.Unmarshal([]byte(entries[0].Values["data"].(string)), &event))
+	assert.Equal(t, []string{"10.0.0.1:80"}, event.Authorities)
+	assert.Equal(t, int64(1), event.Seq)
+
+	// 同一 streamId+seq 重试时不重复入库
+	resp, err = saveResultStreamBatch(ctx, svcCtx, newStreamReq(1))
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.True(t, resp.Duplicate)
+	assert.Equal(t, 1, *assetCalls)
+	assert.Equal(t, 1, *vulCalls)
+
+	// 下一个序号正常入库
+	resp, err = saveResultStreamBatch(ctx, svcCtx, newStreamReq(2))
+	require.NoError(t, err)
+	assert.False(t, resp.Duplicate)
+	assert.Equal(t, 2, *assetCalls)
+}
+
+func TestSaveResultStreamBatchWhileSaving(t *testing.T) {
+	mr, redisClient := setupTestRedis(t)
+	defer redisClient.Close()
+	svcCtx := setupTestServiceContext(t, redisClient)
+	assetCalls, _ := stubStreamSave(t, nil)
+
+	// 另一个请求正在入库该批次
+	req := newStreamReq(1)
+	mr.Set(resultStreamBatchKey(req), resultStreamBatchSaving)
+
+	resp, err := saveResultStreamBatch(context.Background(), svcCtx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 409, resp.Code)
+	assert.False(t, resp.Success)
+	assert.Equal(t, 0, *assetCalls)
+}
+
+func TestSaveResultStreamBatchFailureReleasesClaim(t *testing.T) {
+	mr, redisClient := setupTestRedis(t)
+	defer redisClient.Close()
+	svcCtx := setupTestServiceContext(t, redisClient)
+	stubStreamSave(t, errors.New("mongo unavailable"))
+
+	req := newStreamReq(1)
+	_, err := saveResultStreamBatch(context.Background(), svcCtx, req)
+	require.Error(t, err)
+	assert.False(t, mr.Exists(resultStreamBatchKey(req)), "failed batch must be retryable")
+
+	// 入库恢复后以相同序号重试成功
+	assetCalls, _ := stubStreamSave(t, nil)
+	resp, err := saveResultStreamBatch(context.Background(), svcCtx, req)
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.False(t, resp.Duplicate)
+	assert.Equal(t, 1, *assetCalls)
+}
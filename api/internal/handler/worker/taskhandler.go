@@ -1,13 +1,16 @@
 package worker
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"time"
 
 	"cscan/api/internal/svc"
+	"cscan/model"
 	"cscan/pkg/response"
 	"cscan/rpc/task/pb"
+	"cscan/scheduler"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -51,6 +54,21 @@ type WorkerTaskUpdateResp struct {
 	Success bool   `json:"success"`
 }
 
+// TaskWorkerLookup 从任务执行记录查询任务被分配到的Worker，供Worker认证中间件校验凭证绑定
+func TaskWorkerLookup(svcCtx *svc.ServiceContext) func(ctx context.Context, taskId string) string {
+	return func(ctx context.Context, taskId string) string {
+		data, err := svcCtx.RedisClient.Get(ctx, "cscan:task:execution:"+taskId).Result()
+		if err != nil {
+			return ""
+		}
+		var info scheduler.TaskExecutionInfo
+		if json.Unmarshal([]byte(data), &info) != nil {
+			return ""
+		}
+		return info.WorkerName
+	}
+}
+
 // ==================== Task Check Handler ====================
 
 // WorkerTaskCheckHandler 任务拉取接口
@@ -375,3 +393,103 @@ func WorkerTaskRecoveryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		})
 	}
 }
+
+// ==================== Task New Assets Handler ====================
+
+// WorkerTaskNewAssetsReq 本次任务新发现资产查询请求
+type WorkerTaskNewAssetsReq struct {
+	WorkspaceId string `json:"workspaceId"`
+	MainTaskId  string `json:"mainTaskId"`
+}
+
+// WorkerTaskNewAssetsResp 本次任务新发现资产查询响应
+type WorkerTaskNewAssetsResp struct {
+	Code        int      `json:"code"`
+	Msg         string   `json:"msg"`
+	Authorities []string `json:"authorities"`
+}
+
+// WorkerTaskNewAssetsHandler 查询在本次任务中首次发现的资产，用于工作流的 onlyNew 条件
+// POST /api/v1/worker/task/newassets
+func WorkerTaskNewAssetsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerTaskNewAssetsReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerTaskNewAssetsResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if req.WorkspaceId == "" || req.MainTaskId == "" {
+			httpx.OkJson(w, &WorkerTaskNewAssetsResp{Code: 400, Msg: "workspaceId和mainTaskId不能为空"})
+			return
+		}
+
+		authorities, err := svcCtx.GetAssetModel(req.WorkspaceId).FindAuthoritiesFirstSeenIn(r.Context(), req.MainTaskId)
+		if err != nil {
+			logx.Errorf("[WorkerTaskNewAssets] query failed: %v", err)
+			httpx.OkJson(w, &WorkerTaskNewAssetsResp{Code: 500, Msg: "查询失败"})
+			return
+		}
+
+		httpx.OkJson(w, &WorkerTaskNewAssetsResp{
+			Code:        0,
+			Msg:         "success",
+			Authorities: authorities,
+		})
+	}
+}
+
+// ==================== Task Out Of Scope Handler ====================
+
+// WorkerOutOfScopeItem 范围外目标
+type WorkerOutOfScopeItem struct {
+	Target string `json:"target"`
+	Host   string `json:"host"`
+	Phase  string `json:"phase"`
+	Reason string `json:"reason"`
+}
+
+// WorkerTaskOutOfScopeReq 范围外目标上报请求
+type WorkerTaskOutOfScopeReq struct {
+	WorkspaceId string                  `json:"workspaceId"`
+	MainTaskId  string                  `json:"mainTaskId"`
+	TaskId      string                  `json:"taskId"`
+	Items       []*WorkerOutOfScopeItem `json:"items"`
+}
+
+// WorkerTaskOutOfScopeHandler 记录任务中遇到的范围外目标
+// POST /api/v1/worker/task/outofscope
+func WorkerTaskOutOfScopeHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerTaskOutOfScopeReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if req.MainTaskId == "" {
+			httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 400, Msg: "mainTaskId不能为空"})
+			return
+		}
+
+		docs := make([]*model.OutOfScope, 0, len(req.Items))
+		for _, item := range req.Items {
+			if item == nil || item.Target == "" {
+				continue
+			}
+			docs = append(docs, &model.OutOfScope{
+				MainTaskId: req.MainTaskId,
+				TaskId:     req.TaskId,
+				Target:     item.Target,
+				Host:       item.Host,
+				Phase:      item.Phase,
+				Reason:     item.Reason,
+			})
+		}
+
+		if err := svcCtx.GetOutOfScopeModel(req.WorkspaceId).BatchRecord(r.Context(), docs); err != nil {
+			logx.Errorf("[WorkerTaskOutOfScope] record failed: %v", err)
+			httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 500, Msg: "记录失败"})
+			return
+		}
+		httpx.OkJson(w, &WorkerTaskUpdateResp{Code: 0, Msg: "success", Success: true})
+	}
+}
@@ -7,15 +7,19 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
 	"cscan/model"
+	"cscan/pkg/asciicast"
 	"cscan/pkg/response"
 
 	"github.com/gobwas/ws"
 	"github.com/gobwas/ws/wsutil"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/redis/go-redis/v9"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -47,6 +51,12 @@ func WorkerTerminalOpenHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHa
 		if req.SessionId == "" {
 			req.SessionId = fmt.Sprintf("%d", time.Now().UnixNano())
 		}
+		if req.Cols <= 0 {
+			req.Cols = 80
+		}
+		if req.Rows <= 0 {
+			req.Rows = 24
+		}
 
 		// 获取Worker连接
 		conn, ok := wsHandler.GetConnection(req.WorkerName)
@@ -70,9 +80,23 @@ func WorkerTerminalOpenHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHa
 			return
 		}
 
+		// REST 会话没有客户端连接，由服务端订阅输出写入录像，关闭或空闲超时后保存
+		operator := middleware.GetUsername(r.Context())
+		recorder := asciicast.NewRecorder(req.Cols, req.Rows, fmt.Sprintf("%s@%s", operator, req.WorkerName), 0)
+		recordCtx, stopRecording := context.WithCancel(context.Background())
+		if err := subscribeTerminalOutput(recordCtx, svcCtx, req.WorkerName, req.SessionId, recorder, nil); err != nil {
+			stopRecording()
+			if auditSvc := GetAuditService(); auditSvc != nil {
+				auditSvc.RecordTerminalOperation(r.Context(), r, model.AuditLogTypeTerminalOpen, req.WorkerName, req.SessionId, "", false, err.Error(), time.Since(startTime))
+			}
+			response.ErrorWithCode(w, http.StatusInternalServerError, "failed to subscribe terminal output: "+err.Error())
+			return
+		}
+
 		// 请求打开终端
 		resp, err := conn.RequestTerminalOpen(req.SessionId, req.Cols, req.Rows, 30*time.Second)
 		if err != nil {
+			stopRecording()
 			if auditSvc := GetAuditService(); auditSvc != nil {
 				auditSvc.RecordTerminalOperation(r.Context(), r, model.AuditLogTypeTerminalOpen, req.WorkerName, req.SessionId, "", false, err.Error(), time.Since(startTime))
 			}
@@ -81,6 +105,7 @@ func WorkerTerminalOpenHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHa
 		}
 
 		if !resp.Success {
+			stopRecording()
 			if auditSvc := GetAuditService(); auditSvc != nil {
 				auditSvc.RecordTerminalOperation(r.Context(), r, model.AuditLogTypeTerminalOpen, req.WorkerName, req.SessionId, "", false, resp.Error, time.Since(startTime))
 			}
@@ -90,6 +115,12 @@ func WorkerTerminalOpenHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHa
 
 		// 记录会话
 		wsHandler.AddWorkerSession(req.WorkerName, req.SessionId)
+		wsHandler.startRESTRecording(req.WorkerName, req.SessionId, &terminalRecording{
+			recorder: recorder,
+			operator: operator,
+			clientIP: getClientIP(r),
+			stop:     stopRecording,
+		})
 
 		// 记录审计日志
 		if auditSvc := GetAuditService(); auditSvc != nil {
@@ -152,8 +183,10 @@ func WorkerTerminalCloseHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSH
 			return
 		}
 
-		// 移除会话记录
-		wsHandler.RemoveWorkerSession(req.WorkerName, req.SessionId)
+		// 移除会话记录，REST 打开的会话同时保存录像
+		if !wsHandler.finishRESTRecording(req.WorkerName, req.SessionId) {
+			wsHandler.RemoveWorkerSession(req.WorkerName, req.SessionId)
+		}
 
 		// 记录审计日志
 		if auditSvc := GetAuditService(); auditSvc != nil {
@@ -197,6 +230,18 @@ func WorkerTerminalExecHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHa
 			return
 		}
 
+		// 只能在已打开的会话中执行，Worker 收到未知会话的命令会直接新建终端，绕过录像
+		recording, ok := wsHandler.getRecording(req.WorkerName, req.SessionId)
+		if !ok {
+			if auditSvc := GetAuditService(); auditSvc != nil {
+				auditSvc.RecordTerminalOperation(r.Context(), r, model.AuditLogTypeTerminalExec, req.WorkerName, req.SessionId, req.Command, false, "session not found", time.Since(startTime))
+			}
+			response.ErrorWithCode(w, http.StatusNotFound, "session not found, open the terminal first")
+			return
+		}
+		recording.recorder.Input([]byte(req.Command + "\r"))
+		recording.touch(svcCtx.Config.Console.GetWSIdleTimeout())
+
 		// 请求执行命令
 		resp, err := conn.RequestTerminalInput(req.SessionId, "", req.Command, 30*time.Second)
 
@@ -206,6 +251,7 @@ func WorkerTerminalExecHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHa
 			SessionId:  req.SessionId,
 			Command:    req.Command,
 			Duration:   time.Since(startTime).Milliseconds(),
+			Operator:   middleware.GetUsername(r.Context()),
 			ClientIP:   getClientIP(r),
 			CreateTime: startTime,
 		}
@@ -321,6 +367,48 @@ func WorkerTerminalHistoryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
+// WorkerTerminalRecordingHandler 获取终端会话录像（asciicast v2）
+// GET /api/v1/worker/console/terminal/recording?id=xxx
+func WorkerTerminalRecordingHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		workerName := r.URL.Query().Get("workerName")
+		sessionId := r.URL.Query().Get("sessionId")
+
+		var history *model.CommandHistory
+		var err error
+		switch {
+		case id != "":
+			history, err = svcCtx.CommandHistoryModel.GetRecording(r.Context(), id)
+		case workerName != "" && sessionId != "":
+			history, err = svcCtx.CommandHistoryModel.GetSessionRecording(r.Context(), workerName, sessionId)
+		default:
+			response.ParamError(w, "id or workerName/sessionId is required")
+			return
+		}
+		if err != nil {
+			response.ErrorWithCode(w, http.StatusInternalServerError, "failed to get recording: "+err.Error())
+			return
+		}
+		if history == nil {
+			response.ErrorWithCode(w, http.StatusNotFound, "recording not found")
+			return
+		}
+
+		response.Success(w, map[string]interface{}{
+			"id":         history.Id.Hex(),
+			"workerName": history.WorkerName,
+			"sessionId":  history.SessionId,
+			"operator":   history.Operator,
+			"clientIp":   history.ClientIP,
+			"duration":   history.Duration,
+			"truncated":  history.Truncated,
+			"createTime": history.CreateTime,
+			"recording":  history.Recording,
+		})
+	}
+}
+
 // ==================== Terminal WebSocket Handler ====================
 
 // WorkerTerminalWSHandler 终端WebSocket端点
@@ -354,6 +442,7 @@ func WorkerTerminalWSHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHand
 
 		// 获取客户端IP
 		clientIP := getClientIP(r)
+		operator := middleware.GetUsername(r.Context())
 
 		// 检查会话数限制
 		maxSessions := svcCtx.Config.Console.GetMaxSessionsPerWorker()
@@ -386,6 +475,21 @@ func WorkerTerminalWSHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHand
 
 		logx.Infof("[TerminalWS] Client connected for worker %s, session %s, clientIP %s", workerName, sessionId, clientIP)
 
+		// 创建上下文
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		// 录制整个会话，关闭时保存到命令历史供审计回放
+		recorder := asciicast.NewRecorder(cols, rows, fmt.Sprintf("%s@%s", operator, workerName), 0)
+
+		// 打开终端前先订阅输出，shell 启动后会立即输出提示符
+		outputChan := make(chan []byte, 256)
+		if err := subscribeTerminalOutput(ctx, svcCtx, workerName, sessionId, recorder, outputChan); err != nil {
+			logx.Errorf("[TerminalWS] Failed to subscribe terminal output: %v", err)
+			sendTerminalError(conn, "failed to subscribe terminal output: "+err.Error())
+			return
+		}
+
 		// 在Worker上打开终端会话
 		logx.Infof("[TerminalWS] Requesting terminal open on worker %s...", workerName)
 		openResp, err := workerConn.RequestTerminalOpen(sessionId, cols, rows, 30*time.Second)
@@ -408,8 +512,9 @@ func WorkerTerminalWSHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHand
 
 		logx.Infof("[TerminalWS] Terminal opened successfully on worker %s, session %s", workerName, sessionId)
 
-		// 记录会话
+		// 记录会话，REST 接口在该会话中执行的命令写入同一份录像
 		wsHandler.AddWorkerSession(workerName, sessionId)
+		wsHandler.recordings.Store(terminalRecordingKey(workerName, sessionId), &terminalRecording{recorder: recorder})
 
 		// 记录审计日志
 		if auditSvc := GetAuditService(); auditSvc != nil {
@@ -421,6 +526,19 @@ func WorkerTerminalWSHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHand
 			closeStartTime := time.Now()
 			_, closeErr := workerConn.RequestTerminalClose(sessionId, 5*time.Second)
 			wsHandler.RemoveWorkerSession(workerName, sessionId)
+			wsHandler.recordings.Delete(terminalRecordingKey(workerName, sessionId))
+
+			go recordSessionHistory(svcCtx, &model.CommandHistory{
+				WorkerName: workerName,
+				SessionId:  sessionId,
+				Success:    true,
+				Duration:   recorder.Duration().Milliseconds(),
+				Operator:   operator,
+				ClientIP:   clientIP,
+				CreateTime: recorder.StartTime(),
+				Recording:  recorder.String(),
+				Truncated:  recorder.Truncated(),
+			})
 			
 			// 记录关闭终端的审计日志
 			if auditSvc := GetAuditService(); auditSvc != nil {
@@ -436,17 +554,9 @@ func WorkerTerminalWSHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHand
 			logx.Infof("[TerminalWS] Client disconnected for worker %s, session %s", workerName, sessionId)
 		}()
 
-		// 创建上下文
-		ctx, cancel := context.WithCancel(r.Context())
-		defer cancel()
-
 		// 获取空闲超时配置
 		idleTimeout := svcCtx.Config.Console.GetWSIdleTimeout()
 
-		// 订阅终端输出
-		outputChan := make(chan []byte, 256)
-		go subscribeTerminalOutput(ctx, svcCtx, workerName, sessionId, outputChan)
-
 		// 启动输出转发协程
 		go func() {
 			for {
@@ -499,6 +609,13 @@ func WorkerTerminalWSHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHand
 				// 记录命令开始时间
 				cmdStartTime := time.Now()
 
+				// 原始按键和直接命令都记入录像的输入事件
+				if msg.Command != "" {
+					recorder.Input([]byte(msg.Command + "\r"))
+				} else if input, err := base64.StdEncoding.DecodeString(msg.Data); err == nil {
+					recorder.Input(input)
+				}
+
 				// 发送输入到Worker
 				resp, err := workerConn.RequestTerminalInput(sessionId, msg.Data, msg.Command, 30*time.Second)
 
@@ -509,6 +626,7 @@ func WorkerTerminalWSHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHand
 						SessionId:  sessionId,
 						Command:    msg.Command,
 						Duration:   time.Since(cmdStartTime).Milliseconds(),
+						Operator:   operator,
 						ClientIP:   clientIP,
 						CreateTime: cmdStartTime,
 					}
@@ -538,9 +656,11 @@ func WorkerTerminalWSHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHand
 
 			case "resize":
 				// 调整终端大小
-				_, err := workerConn.RequestTerminalResize(sessionId, msg.Cols, msg.Rows, 10*time.Second)
+				resp, err := workerConn.RequestTerminalResize(sessionId, msg.Cols, msg.Rows, 10*time.Second)
 				if err != nil {
 					logx.Errorf("[TerminalWS] Failed to resize terminal: %v", err)
+				} else if resp.Success {
+					recorder.Resize(msg.Cols, msg.Rows)
 				}
 
 			case "ping":
@@ -555,14 +675,26 @@ func WorkerTerminalWSHandler(svcCtx *svc.ServiceContext, wsHandler *WorkerWSHand
 	}
 }
 
-// subscribeTerminalOutput 订阅终端输出
-func subscribeTerminalOutput(ctx context.Context, svcCtx *svc.ServiceContext, workerName, sessionId string, outputChan chan<- []byte) {
+// subscribeTerminalOutput 订阅终端输出，同时写入会话录像
+// outputChan 为 nil 时只录制不转发；订阅确认后才返回，输出在后台协程中转发，ctx 取消时退订
+func subscribeTerminalOutput(ctx context.Context, svcCtx *svc.ServiceContext, workerName, sessionId string, recorder *asciicast.Recorder, outputChan chan<- []byte) error {
 	channel := fmt.Sprintf("cscan:worker:terminal:%s:%s", workerName, sessionId)
 	pubsub := svcCtx.RedisClient.Subscribe(ctx, channel)
-	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return err
+	}
 
-	ch := pubsub.Channel()
+	go forwardTerminalOutput(ctx, pubsub.Channel(), recorder, outputChan)
+	go func() {
+		<-ctx.Done()
+		pubsub.Close()
+	}()
+	return nil
+}
 
+// forwardTerminalOutput 解码 Redis 中的终端输出并转发给客户端
+func forwardTerminalOutput(ctx context.Context, ch <-chan *redis.Message, recorder *asciicast.Recorder, outputChan chan<- []byte) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -589,6 +721,7 @@ func subscribeTerminalOutput(ctx context.Context, svcCtx *svc.ServiceContext, wo
 				logx.Errorf("[TerminalWS] Failed to decode terminal output: %v", err)
 				continue
 			}
+			recorder.Output(decodedData)
 
 			// 构造前端期望的消息格式
 			frontendMsg, _ := json.Marshal(map[string]interface{}{
@@ -606,6 +739,88 @@ func subscribeTerminalOutput(ctx context.Context, svcCtx *svc.ServiceContext, wo
 	}
 }
 
+// terminalRecording 正在录制的终端会话
+// REST 打开的会话没有客户端连接，stop 用于退订输出，空闲超时后自动关闭并保存录像
+type terminalRecording struct {
+	recorder *asciicast.Recorder
+	operator string
+	clientIP string
+	stop     context.CancelFunc
+	mu       sync.Mutex
+	idle     *time.Timer
+}
+
+func terminalRecordingKey(workerName, sessionId string) string {
+	return workerName + "/" + sessionId
+}
+
+// touch 执行命令后重新开始空闲计时
+func (t *terminalRecording) touch(timeout time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.idle != nil {
+		t.idle.Reset(timeout)
+	}
+}
+
+// getRecording 获取已打开会话的录像
+func (h *WorkerWSHandler) getRecording(workerName, sessionId string) (*terminalRecording, bool) {
+	v, ok := h.recordings.Load(terminalRecordingKey(workerName, sessionId))
+	if !ok {
+		return nil, false
+	}
+	return v.(*terminalRecording), true
+}
+
+// startRESTRecording 登记 REST 打开的会话，空闲超时后关闭 Worker 上的终端并保存录像
+func (h *WorkerWSHandler) startRESTRecording(workerName, sessionId string, rec *terminalRecording) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	h.recordings.Store(terminalRecordingKey(workerName, sessionId), rec)
+	rec.idle = time.AfterFunc(h.svcCtx.Config.Console.GetWSIdleTimeout(), func() {
+		if conn, ok := h.GetConnection(workerName); ok {
+			conn.RequestTerminalClose(sessionId, 5*time.Second)
+		}
+		if h.finishRESTRecording(workerName, sessionId) {
+			logx.Infof("[Terminal] Session %s on worker %s closed after idle timeout", sessionId, workerName)
+		}
+	})
+}
+
+// finishRESTRecording 结束 REST 打开的会话：退订输出、移除会话记录并保存录像
+// 不是 REST 打开的会话返回 false，由 WebSocket 连接断开时自行保存
+func (h *WorkerWSHandler) finishRESTRecording(workerName, sessionId string) bool {
+	key := terminalRecordingKey(workerName, sessionId)
+	if v, ok := h.recordings.Load(key); !ok || v.(*terminalRecording).stop == nil {
+		return false
+	}
+	v, ok := h.recordings.LoadAndDelete(key)
+	if !ok {
+		return false
+	}
+	rec := v.(*terminalRecording)
+	rec.mu.Lock()
+	if rec.idle != nil {
+		rec.idle.Stop()
+	}
+	rec.mu.Unlock()
+	rec.stop()
+	h.RemoveWorkerSession(workerName, sessionId)
+
+	go recordSessionHistory(h.svcCtx, &model.CommandHistory{
+		WorkerName: workerName,
+		SessionId:  sessionId,
+		Success:    true,
+		Duration:   rec.recorder.Duration().Milliseconds(),
+		Operator:   rec.operator,
+		ClientIP:   rec.clientIP,
+		CreateTime: rec.recorder.StartTime(),
+		Recording:  rec.recorder.String(),
+		Truncated:  rec.recorder.Truncated(),
+	})
+	return true
+}
+
 // sendTerminalError 发送终端错误消息
 func sendTerminalError(conn interface{ Write([]byte) (int, error) }, errMsg string) {
 	msg, _ := json.Marshal(map[string]interface{}{
@@ -625,6 +840,16 @@ func recordCommandHistory(svcCtx *svc.ServiceContext, history *model.CommandHist
 	}
 }
 
+// recordSessionHistory 异步保存终端会话录像
+func recordSessionHistory(svcCtx *svc.ServiceContext, history *model.CommandHistory) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := svcCtx.CommandHistoryModel.RecordSession(ctx, history); err != nil {
+		logx.Errorf("[TerminalHistory] Failed to record session %s: %v", history.SessionId, err)
+	}
+}
+
 // getClientIP 获取客户端IP
 func getClientIP(r *http.Request) string {
 	// 尝试从X-Forwarded-For获取
@@ -680,16 +905,26 @@ func WorkerTerminalWSHandlerWithAuth(svcCtx *svc.ServiceContext, wsHandler *Work
 			return
 		}
 
-		// 检查是否是管理员
-		role, _ := claims["role"].(string)
-		if role != "admin" {
-			logx.Errorf("[TerminalWS] Access denied for non-admin user, role: %s", role)
-			http.Error(w, "admin access required", http.StatusForbidden)
+		// 检查控制台权限（按用户当前全局角色实时判断，不信任Token中的角色和请求中的工作空间）
+		userId, _ := claims["userId"].(string)
+		user, err := svcCtx.UserModel.FindById(r.Context(), userId)
+		if err != nil || user == nil || user.Status != model.StatusEnable {
+			http.Error(w, "user not found or disabled", http.StatusForbidden)
 			return
 		}
+		role := user.RoleForPermission("", model.PermWorkerConsole)
+		if !model.HasPermission(role, model.PermWorkerConsole) {
+			logx.Errorf("[TerminalWS] Access denied for user %s, role: %s", user.Username, role)
+			http.Error(w, "worker console permission required", http.StatusForbidden)
+			return
+		}
+
+		// 将用户信息写入上下文，用于审计日志和会话录像的操作人
+		ctx := context.WithValue(r.Context(), middleware.UserIdKey, userId)
+		ctx = context.WithValue(ctx, middleware.UsernameKey, user.Username)
 
 		// 调用原始的 WebSocket handler
-		WorkerTerminalWSHandler(svcCtx, wsHandler)(w, r)
+		WorkerTerminalWSHandler(svcCtx, wsHandler)(w, r.WithContext(ctx))
 	}
 }
 
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cscan/pkg/asciicast"
+)
+
+// TestTerminalExecRequiresOpenSession REST 执行命令必须指定已打开的会话，否则 Worker 会新建一个不录像的终端
+func TestTerminalExecRequiresOpenSession(t *testing.T) {
+	mr, redisClient := setupTestRedis(t)
+	defer mr.Close()
+	defer redisClient.Close()
+
+	svcCtx := setupTestServiceContext(t, redisClient)
+	wsHandler := NewWorkerWSHandler(svcCtx)
+	wsHandler.connections.Store("test-worker", &WorkerConnection{workerName: "test-worker"})
+
+	body := `{"workerName":"test-worker","sessionId":"unknown","command":"id"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/worker/console/terminal/exec", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	WorkerTerminalExecHandler(svcCtx, wsHandler)(w, req)
+
+	var resp struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown session, got %d", resp.Code)
+	}
+}
+
+// TestFinishRESTRecordingSkipsWebSocketSessions WebSocket 会话的录像由连接断开时保存，REST 关闭接口不接管
+func TestFinishRESTRecordingSkipsWebSocketSessions(t *testing.T) {
+	mr, redisClient := setupTestRedis(t)
+	defer mr.Close()
+	defer redisClient.Close()
+
+	wsHandler := NewWorkerWSHandler(setupTestServiceContext(t, redisClient))
+	wsHandler.AddWorkerSession("test-worker", "ws-session")
+	wsHandler.recordings.Store(terminalRecordingKey("test-worker", "ws-session"), &terminalRecording{
+		recorder: asciicast.NewRecorder(80, 24, "admin@test-worker", 0),
+	})
+
+	if wsHandler.finishRESTRecording("test-worker", "ws-session") {
+		t.Error("websocket session should not be finished by the REST close path")
+	}
+	if _, ok := wsHandler.getRecording("test-worker", "ws-session"); !ok {
+		t.Error("websocket recording should be kept")
+	}
+	if wsHandler.GetWorkerSessionCount("test-worker") != 1 {
+		t.Error("websocket session should still be tracked")
+	}
+}
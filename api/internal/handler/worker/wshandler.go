@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"cscan/api/internal/svc"
+	"cscan/model"
 
 	"github.com/gobwas/ws"
 	"github.com/gobwas/ws/wsutil"
@@ -51,7 +52,7 @@ type WSMessage struct {
 // AuthPayload 认证消息载荷
 type AuthPayload struct {
 	WorkerName string `json:"workerName"`
-	InstallKey string `json:"installKey"`
+	Token      string `json:"token"` // 注册后获得的Worker凭证
 }
 
 // LogPayload 日志消息载荷
@@ -268,6 +269,7 @@ type TerminalResizeResponse struct {
 type WorkerConnection struct {
 	conn            net.Conn
 	workerName      string
+	credentialId    string // 认证使用的凭证ID，凭证吊销时据此断开连接
 	svcCtx          *svc.ServiceContext
 	sendChan        chan []byte
 	closeChan       chan struct{}
@@ -278,14 +280,15 @@ type WorkerConnection struct {
 }
 
 // NewWorkerConnection 创建新的Worker连接
-func NewWorkerConnection(conn net.Conn, workerName string, svcCtx *svc.ServiceContext) *WorkerConnection {
+func NewWorkerConnection(conn net.Conn, workerName, credentialId string, svcCtx *svc.ServiceContext) *WorkerConnection {
 	return &WorkerConnection{
-		conn:       conn,
-		workerName: workerName,
-		svcCtx:     svcCtx,
-		sendChan:   make(chan []byte, 256),
-		closeChan:  make(chan struct{}),
-		lastPing:   time.Now(),
+		conn:         conn,
+		workerName:   workerName,
+		credentialId: credentialId,
+		svcCtx:       svcCtx,
+		sendChan:     make(chan []byte, 256),
+		closeChan:    make(chan struct{}),
+		lastPing:     time.Now(),
 	}
 }
 
@@ -756,6 +759,7 @@ type WorkerWSHandler struct {
 	connections    sync.Map // workerName -> *WorkerConnection
 	workerSessions sync.Map // workerName -> map[sessionId]bool
 	sessionMu      sync.RWMutex
+	recordings     sync.Map // workerName/sessionId -> *terminalRecording
 }
 
 // 错误定义
@@ -799,10 +803,11 @@ func (h *WorkerWSHandler) subscribeWorkerControl() {
 	for msg := range ch {
 		// 解析控制命令
 		var cmd struct {
-			Action      string `json:"action"`
-			WorkerName  string `json:"workerName"`
-			NewName     string `json:"newName,omitempty"`
-			Concurrency int    `json:"concurrency,omitempty"`
+			Action       string `json:"action"`
+			WorkerName   string `json:"workerName"`
+			NewName      string `json:"newName,omitempty"`
+			Concurrency  int    `json:"concurrency,omitempty"`
+			CredentialId string `json:"credentialId,omitempty"`
 		}
 		if err := json.Unmarshal([]byte(msg.Payload), &cmd); err != nil {
 			logx.Errorf("[WorkerWS] Invalid control command: %v", err)
@@ -818,6 +823,16 @@ func (h *WorkerWSHandler) subscribeWorkerControl() {
 			continue
 		}
 
+		// 凭证吊销：直接断开连接，Worker重连时认证会失败
+		if cmd.Action == "revoke" {
+			if cmd.CredentialId == "" || cmd.CredentialId == conn.credentialId {
+				conn.Close()
+				conn.conn.Close()
+				logx.Infof("[WorkerWS] Disconnected revoked worker %s", cmd.WorkerName)
+			}
+			continue
+		}
+
 		// 构造并发送控制消息
 		var payload []byte
 		switch cmd.Action {
@@ -1020,19 +1035,20 @@ func handleWebSocketConnection(ctx context.Context, conn net.Conn, svcCtx *svc.S
 	authCtx, authCancel := context.WithTimeout(ctx, 30*time.Second)
 	defer authCancel()
 
-	workerName, err := waitForAuth(authCtx, conn, svcCtx)
+	cred, err := waitForAuth(authCtx, conn, svcCtx)
 	if err != nil {
 		logx.Errorf("[WorkerWS] Authentication failed: %v", err)
 		sendAuthFail(conn, err.Error())
 		return
 	}
+	workerName := cred.WorkerName
 
 	// 认证成功，发送AUTH_OK
 	sendAuthOK(conn)
 	logx.Infof("[WorkerWS] Worker authenticated: %s", workerName)
 
 	// 创建Worker连接
-	wc := NewWorkerConnection(conn, workerName, svcCtx)
+	wc := NewWorkerConnection(conn, workerName, cred.Id.Hex(), svcCtx)
 
 	// 检查是否已有同名连接，如果有则关闭旧连接
 	if oldConn, ok := wsHandler.connections.Load(workerName); ok {
@@ -1044,7 +1060,8 @@ func handleWebSocketConnection(ctx context.Context, conn net.Conn, svcCtx *svc.S
 	// 注册连接
 	wsHandler.connections.Store(workerName, wc)
 	defer func() {
-		wsHandler.connections.Delete(workerName)
+		// 同名Worker可能已重新连接，只移除本连接
+		wsHandler.connections.CompareAndDelete(wc.workerName, wc)
 		wc.Close()
 		logx.Infof("[WorkerWS] Worker disconnected: %s", workerName)
 	}()
@@ -1064,63 +1081,52 @@ func handleWebSocketConnection(ctx context.Context, conn net.Conn, svcCtx *svc.S
 
 // ==================== Authentication ====================
 
-// waitForAuth 等待认证消息
-func waitForAuth(ctx context.Context, conn net.Conn, svcCtx *svc.ServiceContext) (string, error) {
+// waitForAuth 等待认证消息，返回认证通过的Worker凭证
+// Worker名称以凭证记录为准，不使用认证消息中自报的名称
+func waitForAuth(ctx context.Context, conn net.Conn, svcCtx *svc.ServiceContext) (*model.WorkerCredential, error) {
 	conn.SetReadDeadline(time.Now().Add(30 * time.Second))
 	defer conn.SetReadDeadline(time.Time{})
 
 	// 读取认证消息
 	data, _, err := wsutil.ReadClientData(conn)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var msg WSMessage
 	if err := json.Unmarshal(data, &msg); err != nil {
-		return "", ErrInvalidMessage
+		return nil, ErrInvalidMessage
 	}
 
 	if msg.Type != WSTypeAuth {
-		return "", ErrAuthFailed
+		return nil, ErrAuthFailed
 	}
 
 	var authPayload AuthPayload
 	if err := json.Unmarshal(msg.Payload, &authPayload); err != nil {
-		return "", ErrInvalidMessage
-	}
-
-	// 验证Install Key
-	if err := validateInstallKey(ctx, svcCtx, authPayload.InstallKey); err != nil {
-		return "", err
+		return nil, ErrInvalidMessage
 	}
 
-	if authPayload.WorkerName == "" {
-		return "", ErrAuthFailed
-	}
-
-	return authPayload.WorkerName, nil
+	// 验证Worker凭证
+	return validateWorkerCredential(ctx, svcCtx, authPayload.Token)
 }
 
-// validateInstallKey 验证Install Key
-func validateInstallKey(ctx context.Context, svcCtx *svc.ServiceContext, installKey string) error {
-	if installKey == "" {
-		return ErrAuthFailed
+// validateWorkerCredential 验证Worker凭证，吊销或不存在的凭证认证失败
+func validateWorkerCredential(ctx context.Context, svcCtx *svc.ServiceContext, token string) (*model.WorkerCredential, error) {
+	if !model.IsWorkerCredential(token) {
+		logx.Error("[WorkerWS] Missing worker credential, install key is only accepted for enrollment")
+		return nil, ErrAuthFailed
 	}
-
-	// 从Redis获取存储的Install Key
-	installKeyKey := "cscan:worker:install_key"
-	storedKey, err := svcCtx.RedisClient.Get(ctx, installKeyKey).Result()
-	if err != nil || storedKey == "" {
-		logx.Error("[WorkerWS] Install key not configured in Redis")
-		return ErrAuthFailed
+	cred, err := svcCtx.WorkerCredentialModel.FindActiveByToken(ctx, token)
+	if err != nil {
+		logx.Errorf("[WorkerWS] Verify credential failed: %v", err)
+		return nil, ErrAuthFailed
 	}
-
-	if installKey != storedKey {
-		logx.Errorf("[WorkerWS] Invalid install key: %s", installKey)
-		return ErrAuthFailed
+	if cred == nil {
+		logx.Error("[WorkerWS] Invalid or revoked worker credential")
+		return nil, ErrAuthFailed
 	}
-
-	return nil
+	return cred, nil
 }
 
 // sendAuthOK 发送认证成功消息
@@ -0,0 +1,163 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"cscan/api/internal/middleware"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ApiTokenLogic 个人 API Token 管理
+type ApiTokenLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewApiTokenLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ApiTokenLogic {
+	return &ApiTokenLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func convertApiToken(t *model.ApiToken) *types.ApiToken {
+	item := &types.ApiToken{
+		Id:          t.Id.Hex(),
+		Name:        t.Name,
+		TokenHint:   t.TokenHint,
+		WorkspaceId: t.WorkspaceId,
+		Scopes:      t.Scopes,
+		Revoked:     t.Revoked,
+		LastUsedIp:  t.LastUsedIP,
+		CreateTime:  t.CreateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+	if t.ExpireTime != nil {
+		item.ExpireTime = t.ExpireTime.Local().Format("2006-01-02 15:04:05")
+	}
+	if t.LastUsedTime != nil {
+		item.LastUsedTime = t.LastUsedTime.Local().Format("2006-01-02 15:04:05")
+	}
+	return item
+}
+
+// ApiTokenList 当前用户的Token列表
+func (l *ApiTokenLogic) ApiTokenList() (*types.ApiTokenListResp, error) {
+	userId := middleware.GetUserId(l.ctx)
+	if userId == "" {
+		return &types.ApiTokenListResp{Code: 401, Msg: "未登录"}, nil
+	}
+
+	tokens, err := l.svcCtx.ApiTokenModel.FindByUser(l.ctx, userId)
+	if err != nil {
+		l.Errorf("查询API Token失败: %v", err)
+		return &types.ApiTokenListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.ApiToken, 0, len(tokens))
+	for i := range tokens {
+		list = append(list, *convertApiToken(&tokens[i]))
+	}
+	return &types.ApiTokenListResp{Code: 0, Msg: "success", List: list}, nil
+}
+
+// ApiTokenCreate 创建Token，明文只在本次响应中返回
+func (l *ApiTokenLogic) ApiTokenCreate(req *types.ApiTokenCreateReq) (*types.ApiTokenCreateResp, error) {
+	userId := middleware.GetUserId(l.ctx)
+	if userId == "" {
+		return &types.ApiTokenCreateResp{Code: 401, Msg: "未登录"}, nil
+	}
+	// 不允许用Token签发新的Token，避免权限范围被放大
+	if _, ok := middleware.GetApiTokenScopes(l.ctx); ok {
+		return &types.ApiTokenCreateResp{Code: 403, Msg: "不能使用API Token创建新的Token"}, nil
+	}
+	if req.Name == "" {
+		return &types.ApiTokenCreateResp{Code: 400, Msg: "名称不能为空"}, nil
+	}
+	if len(req.Scopes) == 0 {
+		return &types.ApiTokenCreateResp{Code: 400, Msg: "请选择权限范围"}, nil
+	}
+	for _, scope := range req.Scopes {
+		if !model.IsValidApiTokenScope(scope) {
+			return &types.ApiTokenCreateResp{Code: 400, Msg: "无效的权限范围: " + scope}, nil
+		}
+	}
+	if req.ExpireDays < 0 {
+		return &types.ApiTokenCreateResp{Code: 400, Msg: "有效期不能为负数"}, nil
+	}
+
+	workspaceId := req.WorkspaceId
+	if workspaceId == "" {
+		workspaceId = middleware.GetWorkspaceId(l.ctx)
+	}
+	if workspaceId == "" || workspaceId == "all" {
+		return &types.ApiTokenCreateResp{Code: 400, Msg: "Token必须绑定到具体的工作空间"}, nil
+	}
+
+	user, err := l.svcCtx.UserModel.FindById(l.ctx, userId)
+	if err != nil || user == nil {
+		return &types.ApiTokenCreateResp{Code: 404, Msg: "用户不存在"}, nil
+	}
+	if !model.HasPermission(user.RoleFor(workspaceId), model.PermView) {
+		return &types.ApiTokenCreateResp{Code: 403, Msg: "无权访问该工作空间"}, nil
+	}
+
+	plain, hash, err := model.GenerateApiToken()
+	if err != nil {
+		l.Errorf("生成API Token失败: %v", err)
+		return &types.ApiTokenCreateResp{Code: 500, Msg: "生成Token失败"}, nil
+	}
+
+	doc := &model.ApiToken{
+		UserId:      userId,
+		Username:    user.Username,
+		Name:        req.Name,
+		TokenHash:   hash,
+		TokenHint:   plain[:len(model.ApiTokenPrefix)+6],
+		WorkspaceId: workspaceId,
+		Scopes:      req.Scopes,
+	}
+	if req.ExpireDays > 0 {
+		expire := time.Now().AddDate(0, 0, req.ExpireDays)
+		doc.ExpireTime = &expire
+	}
+	if err := l.svcCtx.ApiTokenModel.Create(l.ctx, doc); err != nil {
+		l.Errorf("保存API Token失败: %v", err)
+		return &types.ApiTokenCreateResp{Code: 500, Msg: "创建失败"}, nil
+	}
+
+	return &types.ApiTokenCreateResp{
+		Code:  0,
+		Msg:   "创建成功，请妥善保存Token，关闭后将无法再次查看",
+		Token: plain,
+		Data:  convertApiToken(doc),
+	}, nil
+}
+
+// ApiTokenRevoke 吊销当前用户的Token
+func (l *ApiTokenLogic) ApiTokenRevoke(req *types.ApiTokenRevokeReq) (*types.BaseResp, error) {
+	userId := middleware.GetUserId(l.ctx)
+	if userId == "" {
+		return &types.BaseResp{Code: 401, Msg: "未登录"}, nil
+	}
+	// Token只读权限也能访问本接口，不允许用Token吊销Token
+	if _, ok := middleware.GetApiTokenScopes(l.ctx); ok {
+		return &types.BaseResp{Code: 403, Msg: "不能使用API Token吊销Token"}, nil
+	}
+
+	ok, err := l.svcCtx.ApiTokenModel.Revoke(l.ctx, userId, req.Id)
+	if err != nil {
+		l.Errorf("吊销API Token失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "吊销失败"}, nil
+	}
+	if !ok {
+		return &types.BaseResp{Code: 404, Msg: "Token不存在"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "已吊销"}, nil
+}
@@ -0,0 +1,31 @@
+package logic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cscan/api/internal/middleware"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+)
+
+// 个人设置接口只要求查看权限，API Token 调用必须被拒绝
+func TestApiTokenCallerCannotChangeAccountSettings(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middleware.UserIdKey, "u1")
+	ctx = context.WithValue(ctx, middleware.ApiTokenScopesKey, []string{model.ApiTokenScopeRead})
+	svcCtx := &svc.ServiceContext{}
+
+	resp, err := NewApiTokenLogic(ctx, svcCtx).ApiTokenRevoke(&types.ApiTokenRevokeReq{Id: "t1"})
+	if err != nil || resp.Code != 403 {
+		t.Errorf("ApiTokenRevoke with API token = %+v, %v; want code 403", resp, err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/user/scanConfig/save", nil).WithContext(ctx)
+	resp, err = NewScanConfigLogic(ctx, svcCtx).SaveScanConfig(r, &types.SaveScanConfigReq{Config: "{}"})
+	if err != nil || resp.Code != 403 {
+		t.Errorf("SaveScanConfig with API token = %+v, %v; want code 403", resp, err)
+	}
+}
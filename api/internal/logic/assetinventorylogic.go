@@ -120,7 +120,7 @@ func (l *AssetInventoryLogic) buildInventoryFilter(req *types.AssetInventoryReq)
 }
 
 // convertAssetToInventoryItem 将 Asset 模型转换为清单展示项
-func convertAssetToInventoryItem(asset model.Asset, wsId string) types.AssetInventoryItem {
+func (l *AssetInventoryLogic) convertAssetToInventoryItem(asset model.Asset, wsId string) types.AssetInventoryItem {
 	ip := ""
 	var ips []string
 	if len(asset.Ip.IpV4) > 0 {
@@ -161,7 +161,7 @@ func convertAssetToInventoryItem(asset model.Asset, wsId string) types.AssetInve
 		LastUpdated:     formatTimeAgo(asset.UpdateTime),
 		FirstSeen:       asset.CreateTime.Local().Format("2006-01-02 15:04:05"),
 		LastUpdatedFull: asset.UpdateTime.Local().Format("2006-01-02 15:04:05"),
-		Screenshot:      asset.Screenshot,
+		Screenshot:      l.svcCtx.Screenshots.SignRef(asset.Screenshot),
 		IconHash:        asset.IconHash,
 		IconHashBytes:   iconHashBytes,
 		HttpHeader:      asset.HttpHeader,
@@ -211,7 +211,7 @@ func (l *AssetInventoryLogic) AssetInventory(req *types.AssetInventoryReq, works
 			}
 
 			for _, asset := range assets {
-				allItems = append(allItems, convertAssetToInventoryItem(asset, wsId))
+				allItems = append(allItems, l.convertAssetToInventoryItem(asset, wsId))
 			}
 		}
 
@@ -250,7 +250,7 @@ func (l *AssetInventoryLogic) AssetInventory(req *types.AssetInventoryReq, works
 
 		resultItems = make([]types.AssetInventoryItem, 0, len(assets))
 		for _, asset := range assets {
-			resultItems = append(resultItems, convertAssetToInventoryItem(asset, wsId))
+			resultItems = append(resultItems, l.convertAssetToInventoryItem(asset, wsId))
 		}
 	}
 
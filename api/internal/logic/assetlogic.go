@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net"
+	"net/url"
 	"regexp"
 	"sort"
 	"strconv"
@@ -15,6 +16,7 @@ import (
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
+	"cscan/pkg/utils"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson"
@@ -68,6 +70,27 @@ func formatTimeIfNotZero(t time.Time) string {
 	return t.Local().Format("2006-01-02 15:04:05")
 }
 
+// convertPotentialVulns 转换资产上的潜在漏洞
+func convertPotentialVulns(vulns []model.PotentialVuln) []types.PotentialVuln {
+	if len(vulns) == 0 {
+		return nil
+	}
+	result := make([]types.PotentialVuln, 0, len(vulns))
+	for _, v := range vulns {
+		result = append(result, types.PotentialVuln{
+			VulnId:     v.VulnId,
+			CveId:      v.CveId,
+			Source:     v.Source,
+			CPE:        v.CPE,
+			CVSS:       v.CVSS,
+			Severity:   v.Severity,
+			Summary:    v.Summary,
+			References: v.References,
+		})
+	}
+	return result
+}
+
 // cleanAppName 清理指纹名称，去掉类似 [custom(xxx)] 的后缀
 func cleanAppName(app string) string {
 	// 匹配 [xxx] 或 [xxx(yyy)] 格式的后缀并去掉
@@ -153,69 +176,19 @@ func sortIconHashMap(m map[string]*types.IconHashStatItem, limit int) []types.Ic
 	return result
 }
 
-// parseQuerySyntax 解析查询语法
-// 支持格式: port=80 && service=http || title="test"
-// 如果查询不包含 = 语法，则作为模糊搜索匹配 host/title/domain/service/authority
-func parseQuerySyntax(query string, filter bson.M) {
-	query = strings.TrimSpace(query)
-	if query == "" {
-		return
-	}
-
-	// 如果不包含 = 号，则视为普通文本模糊搜索
-	if !strings.Contains(query, "=") {
-		filter["$or"] = []bson.M{
-			{"host": bson.M{"$regex": query, "$options": "i"}},
-			{"authority": bson.M{"$regex": query, "$options": "i"}},
-			{"title": bson.M{"$regex": query, "$options": "i"}},
-			{"domain": bson.M{"$regex": query, "$options": "i"}},
-			{"service": bson.M{"$regex": query, "$options": "i"}},
-		}
-		return
+// parseQuerySyntax 解析查询语法并合并到 filter
+// 支持 FOFA 风格语法: port>8000 && (title="login" || app=nginx) && ip="10.0.0.0/8"
+// 运算符: = 包含, == 精确, != 不包含, =~ 正则, > >= < <= 数值比较, && || ! 及括号分组
+// 如果查询不包含运算符，则作为模糊搜索匹配 host/title/domain/service/authority
+func parseQuerySyntax(query string, filter bson.M) error {
+	compiled, err := common.CompileQuery(common.AssetQuerySchema, query)
+	if err != nil {
+		return err
 	}
-
-	// 简单解析：支持 field=value 格式，多个条件用 && 连接
-	// 例如: port=80 && service=http && title=test
-	conditions := strings.Split(query, "&&")
-	for _, cond := range conditions {
-		cond = strings.TrimSpace(cond)
-		if cond == "" {
-			continue
-		}
-
-		// 解析 field=value 或 field="value"
-		parts := strings.SplitN(cond, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
-
-		field := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		// 去除引号
-		value = strings.Trim(value, "\"'")
-
-		// 映射字段名
-		switch strings.ToLower(field) {
-		case "port":
-			if port, err := strconv.Atoi(value); err == nil {
-				filter["port"] = port
-			}
-		case "host", "ip":
-			filter["host"] = bson.M{"$regex": value, "$options": "i"}
-		case "service", "protocol":
-			filter["service"] = bson.M{"$regex": value, "$options": "i"}
-		case "title":
-			filter["title"] = bson.M{"$regex": value, "$options": "i"}
-		case "app", "finger", "fingerprint":
-			filter["app"] = bson.M{"$regex": cleanAppName(value), "$options": "i"}
-		case "status", "httpstatus":
-			filter["status"] = value
-		case "domain":
-			filter["domain"] = bson.M{"$regex": value, "$options": "i"}
-		case "banner":
-			filter["banner"] = bson.M{"$regex": value, "$options": "i"}
-		}
+	for k, v := range compiled {
+		filter[k] = v
 	}
+	return nil
 }
 
 type AssetListLogic struct {
@@ -232,16 +205,15 @@ func NewAssetListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AssetLi
 	}
 }
 
-func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string) (resp *types.AssetListResp, err error) {
-	// 添加调试日志
-	l.Logger.Infof("AssetList查询: workspaceId=%s, page=%d, pageSize=%d", workspaceId, req.Page, req.PageSize)
-
-	// 构建查询条件
+// assetListFilter 根据查询语法和独立筛选条件构建资产列表查询条件
+func assetListFilter(req *types.AssetListReq) (bson.M, error) {
 	filter := bson.M{}
 
 	// 如果有语法查询，解析语法
 	if req.Query != "" {
-		parseQuerySyntax(req.Query, filter)
+		if err := parseQuerySyntax(req.Query, filter); err != nil {
+			return nil, err
+		}
 	}
 
 	// 独立筛选条件：无论是否有 query 都生效，且不覆盖 parseQuerySyntax 已设置的字段
@@ -282,29 +254,52 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 		}
 	}
 
+	// 以下筛选字段同样可以出现在 query 中，用 $and 与查询条件组合，不覆盖查询条件
+	var conds []bson.M
 	// 只看新资产
 	if req.OnlyNew {
-		filter["new"] = true
+		conds = append(conds, bson.M{"new": true})
 	}
 	// 只看有更新
 	if req.OnlyUpdated {
-		filter["update"] = true
+		conds = append(conds, bson.M{"update": true})
 	}
-	// 时间范围筛选：最近N天内更新的资产
+	// 时间范围筛选：最近N天内更新的资产，同时要求是已更新状态
 	if req.UpdatedWithinDays > 0 {
 		cutoffTime := time.Now().AddDate(0, 0, -req.UpdatedWithinDays)
-		filter["last_status_change_time"] = bson.M{"$gte": cutoffTime}
-		// 同时要求是已更新状态
-		filter["update"] = true
+		conds = append(conds, bson.M{"last_status_change_time": bson.M{"$gte": cutoffTime}, "update": true})
 	}
 	// 排除CDN/Cloud资产
 	if req.ExcludeCdn {
-		filter["cdn"] = bson.M{"$ne": true}
-		filter["cloud"] = bson.M{"$ne": true}
+		conds = append(conds, bson.M{"cdn": bson.M{"$ne": true}, "cloud": bson.M{"$ne": true}})
 	}
 	// 按组织筛选
 	if req.OrgId != "" {
-		filter["org_id"] = req.OrgId
+		conds = append(conds, bson.M{"org_id": req.OrgId})
+	}
+	// 按传输层协议筛选，历史资产未记录transport，视为tcp
+	switch req.Transport {
+	case model.TransportUDP:
+		conds = append(conds, bson.M{"transport": model.TransportUDP})
+	case model.TransportTCP:
+		conds = append(conds, bson.M{"transport": bson.M{"$ne": model.TransportUDP}})
+	}
+	// 按ASN/国家筛选
+	conds = append(conds, ipGeoConditions(req.ASN, req.Country)...)
+	if len(conds) > 0 {
+		filter = bson.M{"$and": append([]bson.M{filter}, conds...)}
+	}
+	return filter, nil
+}
+
+func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string) (resp *types.AssetListResp, err error) {
+	// 添加调试日志
+	l.Logger.Infof("AssetList查询: workspaceId=%s, page=%d, pageSize=%d", workspaceId, req.Page, req.PageSize)
+
+	// 构建查询条件
+	filter, err := assetListFilter(req)
+	if err != nil {
+		return &types.AssetListResp{Code: 400, Msg: err.Error()}, nil
 	}
 
 	var total int64
@@ -423,12 +418,22 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 				ipInfo.IPV4 = append(ipInfo.IPV4, types.IPV4Info{
 					IP:       ipv4.IPName,
 					Location: ipv4.Location,
+					Country:  ipv4.Country,
+					Region:   ipv4.Region,
+					City:     ipv4.City,
+					ASN:      ipv4.ASN,
+					Org:      ipv4.Org,
 				})
 			}
 			for _, ipv6 := range a.Ip.IpV6 {
 				ipInfo.IPV6 = append(ipInfo.IPV6, types.IPV6Info{
 					IP:       ipv6.IPName,
 					Location: ipv6.Location,
+					Country:  ipv6.Country,
+					Region:   ipv6.Region,
+					City:     ipv6.City,
+					ASN:      ipv6.ASN,
+					Org:      ipv6.Org,
 				})
 			}
 		}
@@ -455,6 +460,7 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 			Authority:            a.Authority,
 			Host:                 a.Host,
 			Port:                 a.Port,
+			Transport:            a.Transport,
 			Category:             a.Category,
 			Service:              a.Service,
 			Title:                a.Title,
@@ -465,11 +471,14 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 			Banner:               a.Banner,
 			IconHash:             a.IconHash,
 			IconData:             iconData,
-			Screenshot:           a.Screenshot,
+			Screenshot:           l.svcCtx.Screenshots.SignRef(a.Screenshot),
 			Location:             location,
 			IP:                   ipInfo,
 			IsCDN:                a.IsCDN,
 			IsCloud:              a.IsCloud,
+			CDNProvider:          a.CDNProvider,
+			CloudProvider:        a.CloudProvider,
+			WAF:                  a.WAF,
 			IsNew:                a.IsNewAsset,
 			IsUpdated:            a.IsUpdated,
 			CreateTime:           a.CreateTime.Local().Format("2006-01-02 15:04:05"),
@@ -482,14 +491,24 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 			// 新增字段 - 风险评分
 			RiskScore: a.RiskScore,
 			RiskLevel: a.RiskLevel,
+			// 版本识别与潜在漏洞
+			CPE:            a.CPE,
+			PotentialVulns: convertPotentialVulns(a.PotentialVulns),
 		})
 	}
 
+	// 按ASN/国家分组统计
+	groups, err := groupAssetsByGeo(l.ctx, l.svcCtx, wsIds, filter, req.GroupBy)
+	if err != nil {
+		l.Logger.Errorf("资产分组统计失败: %v", err)
+	}
+
 	return &types.AssetListResp{
-		Code:  0,
-		Msg:   "success",
-		Total: int(total),
-		List:  list,
+		Code:   0,
+		Msg:    "success",
+		Total:  int(total),
+		List:   list,
+		Groups: groups,
 	}, nil
 }
 
@@ -848,7 +867,7 @@ func (l *AssetHistoryLogic) AssetHistory(req *types.AssetHistoryReq, workspaceId
 			HttpBody:   h.HttpBody,
 			Banner:     h.Banner,
 			IconHash:   h.IconHash,
-			Screenshot: h.Screenshot,
+			Screenshot: l.svcCtx.Screenshots.SignRef(h.Screenshot),
 			TaskId:     h.TaskId,
 			CreateTime: h.CreateTime.Local().Format("2006-01-02 15:04:05"),
 			Changes:    changes,
@@ -910,15 +929,24 @@ func (l *AssetImportLogic) AssetImport(req *types.AssetImportReq, workspaceId st
 		}
 
 		// 创建新资产
-		authority := host + ":" + strconv.Itoa(port)
+		authority := utils.BuildTargetWithPort(host, port)
 		asset := &model.Asset{
-			Authority: authority,
-			Host:      host,
-			Port:      port,
-			Service:   scheme,
-			IsHTTP:    scheme == "http" || scheme == "https",
-			Source:    "import",
+			Authority:    authority,
+			Host:         host,
+			Port:         port,
+			Service:      scheme,
+			IsHTTP:       scheme == "http" || scheme == "https",
+			Source:       "import",
+			OrgId:        req.OrgId,
+			CloudAccount: strings.TrimSpace(req.CloudAccount),
 		}
+		if net.ParseIP(host) == nil {
+			asset.Domain = host
+		}
+		if asset.OrgId == "" {
+			asset.OrgId = l.svcCtx.OrgAttribution.Attribute(l.ctx, asset)
+		}
+		asset.NormalizeAuthority()
 
 		if err := assetModel.Insert(l.ctx, asset); err != nil {
 			errorCount++
@@ -974,58 +1002,37 @@ func parseTarget(target string) (host string, port int, scheme string, err error
 
 	// 处理 URL 格式
 	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
-		// 解析 URL
-		if strings.HasPrefix(target, "https://") {
-			scheme = "https"
-			target = strings.TrimPrefix(target, "https://")
-		} else {
-			scheme = "http"
-			target = strings.TrimPrefix(target, "http://")
-		}
-
-		// 去掉路径部分
-		if idx := strings.Index(target, "/"); idx > 0 {
-			target = target[:idx]
+		u, perr := url.Parse(target)
+		if perr != nil {
+			return "", 0, "", fmt.Errorf("URL格式错误：%s", target)
 		}
-
-		// 去掉查询参数
-		if idx := strings.Index(target, "?"); idx > 0 {
-			target = target[:idx]
-		}
-
-		if target == "" {
+		if u.Host == "" {
 			return "", 0, "", fmt.Errorf("URL格式错误：缺少主机名")
 		}
-
-		// 解析 host:port
-		if strings.Contains(target, ":") {
-			parts := strings.SplitN(target, ":", 2)
-			host = parts[0]
-			if host == "" {
-				return "", 0, "", fmt.Errorf("URL格式错误：主机名为空")
-			}
-			port, err = strconv.Atoi(parts[1])
+		scheme = u.Scheme
+		host = u.Hostname()
+		if host == "" {
+			return "", 0, "", fmt.Errorf("URL格式错误：主机名为空")
+		}
+		if p := u.Port(); p != "" {
+			port, err = strconv.Atoi(p)
 			if err != nil {
-				return "", 0, "", fmt.Errorf("端口格式错误：%s", parts[1])
+				return "", 0, "", fmt.Errorf("端口格式错误：%s", p)
 			}
+		} else if scheme == "https" {
+			port = 443
 		} else {
-			host = target
-			if scheme == "https" {
-				port = 443
-			} else {
-				port = 80
-			}
+			port = 80
 		}
-	} else if strings.Contains(target, ":") {
-		// IP:端口 或 域名:端口 格式
-		parts := strings.SplitN(target, ":", 2)
-		host = parts[0]
+	} else if h, p, serr := net.SplitHostPort(target); serr == nil {
+		// IP:端口、[IPv6]:端口 或 域名:端口 格式
+		host = h
 		if host == "" {
 			return "", 0, "", fmt.Errorf("格式错误：主机名为空")
 		}
-		port, err = strconv.Atoi(parts[1])
+		port, err = strconv.Atoi(p)
 		if err != nil {
-			return "", 0, "", fmt.Errorf("端口格式错误：%s", parts[1])
+			return "", 0, "", fmt.Errorf("端口格式错误：%s", p)
 		}
 		// 根据端口推断协议
 		if port == 443 || port == 8443 {
@@ -1034,11 +1041,12 @@ func parseTarget(target string) (host string, port int, scheme string, err error
 			scheme = "http"
 		}
 	} else {
-		// 只有 host（IP或域名），默认 80 端口
+		// 只有 host（IP或域名，IPv6可带方括号），默认 80 端口
 		host = target
 		port = 80
 		scheme = "http"
 	}
+	host = utils.NormalizeHost(host)
 
 	// 校验端口范围
 	if port <= 0 || port > 65535 {
@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"reflect"
+	"testing"
+
+	"cscan/api/internal/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAssetListFilterKeepsQueryFields(t *testing.T) {
+	query, err := assetListFilter(&types.AssetListReq{Query: `org="org-a" && cdn=true && is_new=false`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := assetListFilter(&types.AssetListReq{
+		Query:      `org="org-a" && cdn=true && is_new=false`,
+		OrgId:      "org-b",
+		ExcludeCdn: true,
+		OnlyNew:    true,
+		Transport:  "udp",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	and, ok := filter["$and"].([]bson.M)
+	if !ok || len(and) != 5 {
+		t.Fatalf("filter = %v, want the query combined with 4 conditions in $and", filter)
+	}
+	if !reflect.DeepEqual(and[0], query) {
+		t.Errorf("query conditions changed: got %v, want %v", and[0], query)
+	}
+	want := []bson.M{
+		{"new": true},
+		{"cdn": bson.M{"$ne": true}, "cloud": bson.M{"$ne": true}},
+		{"org_id": "org-b"},
+		{"transport": "udp"},
+	}
+	if !reflect.DeepEqual(and[1:], want) {
+		t.Errorf("filter conditions = %v, want %v", and[1:], want)
+	}
+}
+
+func TestAssetListFilterWithoutConditions(t *testing.T) {
+	filter, err := assetListFilter(&types.AssetListReq{Query: `port=443`, Host: "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := filter["$and"]; ok {
+		t.Errorf("filter = %v, want no $and without extra conditions", filter)
+	}
+	if _, ok := filter["host"]; !ok {
+		t.Errorf("filter = %v, want host condition", filter)
+	}
+}
+
+func TestParseTargetIPv6(t *testing.T) {
+	cases := []struct {
+		target string
+		host   string
+		port   int
+		scheme string
+	}{
+		{"192.0.2.1:8080", "192.0.2.1", 8080, "http"},
+		{"example.com", "example.com", 80, "http"},
+		{"https://example.com/login?x=1", "example.com", 443, "https"},
+		{"[2001:db8::1]:8443", "2001:db8::1", 8443, "https"},
+		{"2001:DB8:0::1", "2001:db8::1", 80, "http"},
+		{"http://[2001:db8::1]:8080/", "2001:db8::1", 8080, "http"},
+		{"https://[2001:db8::1]", "2001:db8::1", 443, "https"},
+	}
+	for _, c := range cases {
+		host, port, scheme, err := parseTarget(c.target)
+		if err != nil || host != c.host || port != c.port || scheme != c.scheme {
+			t.Errorf("parseTarget(%q) = %q, %d, %q, %v; want %q, %d, %q", c.target, host, port, scheme, err, c.host, c.port, c.scheme)
+		}
+	}
+	for _, target := range []string{"example.com:abc", "[2001:db8::1]:70000", "http://:80"} {
+		if _, _, _, err := parseTarget(target); err == nil {
+			t.Errorf("parseTarget(%q) succeeded, want error", target)
+		}
+	}
+}
@@ -68,7 +68,7 @@ func (l *AssetsWithScansLogic) AssetsWithScans(req *types.AssetsWithScansReq, wo
 			Service:      assetWithSummary.Asset.Service,
 			Title:        assetWithSummary.Asset.Title,
 			App:          assetWithSummary.Asset.App,
-			Screenshot:   assetWithSummary.Asset.Screenshot,
+			Screenshot:   l.svcCtx.Screenshots.SignRef(assetWithSummary.Asset.Screenshot),
 			CreateTime:   assetWithSummary.Asset.CreateTime.Format("2006-01-02 15:04:05"),
 			UpdateTime:   assetWithSummary.Asset.UpdateTime.Format("2006-01-02 15:04:05"),
 		}
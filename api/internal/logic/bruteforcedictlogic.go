@@ -0,0 +1,232 @@
+package logic
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// bruteforceDictAllServices 适用于全部协议的字典
+const bruteforceDictAllServices = "all"
+
+// BruteforceDictListLogic 弱口令字典列表逻辑
+type BruteforceDictListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewBruteforceDictListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BruteforceDictListLogic {
+	return &BruteforceDictListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *BruteforceDictListLogic) BruteforceDictList(req *types.BruteforceDictListReq) (*types.BruteforceDictListResp, error) {
+	dictModel := model.NewBruteforceDictModel(l.svcCtx.MongoDB)
+
+	// 获取列表
+	dicts, err := dictModel.FindAll(l.ctx, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取总数
+	total, err := dictModel.Count(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为响应类型
+	list := make([]types.BruteforceDict, 0, len(dicts))
+	for _, d := range dicts {
+		list = append(list, types.BruteforceDict{
+			Id:          d.Id.Hex(),
+			Name:        d.Name,
+			Description: d.Description,
+			Service:     d.Service,
+			Content:     d.Content,
+			CredCount:   d.CredCount,
+			Enabled:     d.Enabled,
+			IsBuiltin:   d.IsBuiltin,
+			CreateTime:  d.CreateTime.Format("2006-01-02 15:04:05"),
+			UpdateTime:  d.UpdateTime.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return &types.BruteforceDictListResp{
+		Code:     0,
+		Msg:      "success",
+		Total:    int(total),
+		List:     list,
+		Services: scanner.BruteforceServices(),
+	}, nil
+}
+
+// BruteforceDictSaveLogic 保存弱口令字典逻辑
+type BruteforceDictSaveLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewBruteforceDictSaveLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BruteforceDictSaveLogic {
+	return &BruteforceDictSaveLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *BruteforceDictSaveLogic) BruteforceDictSave(req *types.BruteforceDictSaveReq) (*types.BaseRespWithId, error) {
+	dictModel := model.NewBruteforceDictModel(l.svcCtx.MongoDB)
+
+	if strings.TrimSpace(req.Name) == "" {
+		return &types.BaseRespWithId{Code: 400, Msg: "字典名称不能为空"}, nil
+	}
+	if req.Service != bruteforceDictAllServices && !slices.Contains(scanner.BruteforceServices(), req.Service) {
+		return &types.BaseRespWithId{Code: 400, Msg: "不支持的协议: " + req.Service}, nil
+	}
+	credCount := len(scanner.ParseCredentials(req.Content))
+
+	if req.Id != "" {
+		// 更新
+		dict := &model.BruteforceDict{
+			Name:        req.Name,
+			Description: req.Description,
+			Service:     req.Service,
+			Content:     req.Content,
+			CredCount:   credCount,
+			Enabled:     req.Enabled,
+		}
+		if err := dictModel.Update(l.ctx, req.Id, dict); err != nil {
+			return nil, err
+		}
+		return &types.BaseRespWithId{Code: 0, Msg: "success", Id: req.Id}, nil
+	}
+
+	// 新增
+	dict := &model.BruteforceDict{
+		Name:        req.Name,
+		Description: req.Description,
+		Service:     req.Service,
+		Content:     req.Content,
+		CredCount:   credCount,
+		Enabled:     req.Enabled,
+		IsBuiltin:   false,
+		CreateTime:  time.Now(),
+		UpdateTime:  time.Now(),
+	}
+	if err := dictModel.Insert(l.ctx, dict); err != nil {
+		return nil, err
+	}
+
+	return &types.BaseRespWithId{Code: 0, Msg: "success", Id: dict.Id.Hex()}, nil
+}
+
+// BruteforceDictDeleteLogic 删除弱口令字典逻辑
+type BruteforceDictDeleteLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewBruteforceDictDeleteLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BruteforceDictDeleteLogic {
+	return &BruteforceDictDeleteLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *BruteforceDictDeleteLogic) BruteforceDictDelete(req *types.BruteforceDictDeleteReq) (*types.BaseResp, error) {
+	dictModel := model.NewBruteforceDictModel(l.svcCtx.MongoDB)
+
+	if err := dictModel.Delete(l.ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &types.BaseResp{Code: 0, Msg: "success"}, nil
+}
+
+// BruteforceDictClearLogic 清空弱口令字典逻辑
+type BruteforceDictClearLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewBruteforceDictClearLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BruteforceDictClearLogic {
+	return &BruteforceDictClearLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *BruteforceDictClearLogic) BruteforceDictClear() (*types.BruteforceDictClearResp, error) {
+	dictModel := model.NewBruteforceDictModel(l.svcCtx.MongoDB)
+
+	// 只删除非内置字典
+	deleted, err := dictModel.DeleteNonBuiltin(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.BruteforceDictClearResp{
+		Code:    0,
+		Msg:     "success",
+		Deleted: int(deleted),
+	}, nil
+}
+
+// BruteforceDictEnabledListLogic 获取启用的弱口令字典列表逻辑
+type BruteforceDictEnabledListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewBruteforceDictEnabledListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BruteforceDictEnabledListLogic {
+	return &BruteforceDictEnabledListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *BruteforceDictEnabledListLogic) BruteforceDictEnabledList() (*types.BruteforceDictEnabledListResp, error) {
+	dictModel := model.NewBruteforceDictModel(l.svcCtx.MongoDB)
+
+	dicts, err := dictModel.FindEnabled(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]types.BruteforceDictSimple, 0, len(dicts))
+	for _, d := range dicts {
+		list = append(list, types.BruteforceDictSimple{
+			Id:        d.Id.Hex(),
+			Name:      d.Name,
+			Service:   d.Service,
+			CredCount: d.CredCount,
+			IsBuiltin: d.IsBuiltin,
+		})
+	}
+
+	return &types.BruteforceDictEnabledListResp{
+		Code: 0,
+		Msg:  "success",
+		List: list,
+	}, nil
+}
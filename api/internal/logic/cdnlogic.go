@@ -0,0 +1,143 @@
+package logic
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CDNProviderLogic CDN识别规则管理
+type CDNProviderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCDNProviderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CDNProviderLogic {
+	return &CDNProviderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func convertCDNProvider(doc *model.CDNProvider) types.CDNProvider {
+	return types.CDNProvider{
+		Id:           doc.Id.Hex(),
+		Name:         doc.Name,
+		Type:         doc.Type,
+		CNAMEs:       doc.CNAMEs,
+		CIDRs:        doc.CIDRs,
+		Headers:      doc.Headers,
+		BodyPatterns: doc.BodyPatterns,
+		Enabled:      doc.Enabled,
+		IsBuiltin:    doc.IsBuiltin,
+		CreateTime:   doc.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		UpdateTime:   doc.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+}
+
+// CDNProviderList CDN识别规则列表
+func (l *CDNProviderLogic) CDNProviderList(req *types.CDNProviderListReq) (*types.CDNProviderListResp, error) {
+	filter := bson.M{}
+	if req.Keyword != "" {
+		q := regexp.QuoteMeta(req.Keyword)
+		filter["$or"] = []bson.M{
+			{"name": bson.M{"$regex": q, "$options": "i"}},
+			{"cnames": bson.M{"$regex": q, "$options": "i"}},
+		}
+	}
+	if req.Type != "" {
+		filter["type"] = strings.ToLower(req.Type)
+	}
+	if req.Enabled != nil {
+		filter["enabled"] = *req.Enabled
+	}
+
+	total, err := l.svcCtx.CDNProviderModel.Count(l.ctx, filter)
+	if err != nil {
+		l.Errorf("查询CDN识别规则失败: %v", err)
+		return &types.CDNProviderListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+	docs, err := l.svcCtx.CDNProviderModel.FindWithSort(l.ctx, filter, req.Page, req.PageSize, "name", 1)
+	if err != nil {
+		l.Errorf("查询CDN识别规则失败: %v", err)
+		return &types.CDNProviderListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.CDNProvider, 0, len(docs))
+	for i := range docs {
+		list = append(list, convertCDNProvider(&docs[i]))
+	}
+	return &types.CDNProviderListResp{Code: 0, Msg: "success", Total: total, List: list}, nil
+}
+
+// CDNProviderSave 新建或更新CDN识别规则
+func (l *CDNProviderLogic) CDNProviderSave(req *types.CDNProviderSaveReq) (*types.BaseResp, error) {
+	provider := &scanner.CDNProvider{
+		Name:         strings.TrimSpace(req.Name),
+		Type:         strings.ToLower(strings.TrimSpace(req.Type)),
+		CNAMEs:       trimList(req.CNAMEs),
+		CIDRs:        trimList(req.CIDRs),
+		Headers:      trimList(req.Headers),
+		BodyPatterns: trimList(req.BodyPatterns),
+	}
+	if err := provider.Validate(); err != nil {
+		return &types.BaseResp{Code: 400, Msg: err.Error()}, nil
+	}
+
+	existing, err := l.svcCtx.CDNProviderModel.FindByName(l.ctx, provider.Name)
+	if err != nil {
+		l.Errorf("查询CDN识别规则失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存失败"}, nil
+	}
+	if existing != nil && existing.Id.Hex() != req.Id {
+		return &types.BaseResp{Code: 400, Msg: "服务商名称已存在"}, nil
+	}
+
+	doc := &model.CDNProvider{
+		Name:         provider.Name,
+		Type:         provider.Type,
+		CNAMEs:       provider.CNAMEs,
+		CIDRs:        provider.CIDRs,
+		Headers:      provider.Headers,
+		BodyPatterns: provider.BodyPatterns,
+		Enabled:      req.Enabled,
+	}
+	if req.Id == "" {
+		err = l.svcCtx.CDNProviderModel.Create(l.ctx, doc)
+	} else {
+		err = l.svcCtx.CDNProviderModel.Update(l.ctx, req.Id, doc)
+	}
+	if err != nil {
+		l.Errorf("保存CDN识别规则失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+}
+
+// CDNProviderDelete 删除CDN识别规则
+func (l *CDNProviderLogic) CDNProviderDelete(req *types.CDNProviderDeleteReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.CDNProviderModel.DeleteById(l.ctx, req.Id); err != nil {
+		l.Errorf("删除CDN识别规则失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
+// CDNProviderUpdateEnabled 启用或禁用CDN识别规则
+func (l *CDNProviderLogic) CDNProviderUpdateEnabled(req *types.CDNProviderUpdateEnabledReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.CDNProviderModel.UpdateById(l.ctx, req.Id, bson.M{"enabled": req.Enabled}); err != nil {
+		l.Errorf("更新CDN识别规则状态失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "更新失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "更新成功"}, nil
+}
@@ -0,0 +1,159 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+
+	"cscan/pkg/query"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// appSuffixRe 匹配指纹名称中 [custom(xxx)] 一类的来源后缀
+var appSuffixRe = regexp.MustCompile(`\s*\[.*\]\s*$`)
+
+func trimAppSuffix(app string) string {
+	return strings.TrimSpace(appSuffixRe.ReplaceAllString(app, ""))
+}
+
+// assetQueryFields 资产类集合（资产/站点/域名/IP）共用的查询字段
+var assetQueryFields = map[string]query.Field{
+	"host":       {Paths: []string{"host"}, Type: query.FieldIP},
+	"ip":         {Paths: []string{"host", "ip.ipv4.ip", "ip.ipv6.ip"}, Type: query.FieldIP},
+	"port":       {Paths: []string{"port"}, Type: query.FieldNumber},
+	"authority":  {Paths: []string{"authority"}, Type: query.FieldString},
+	"domain":     {Paths: []string{"domain"}, Type: query.FieldString},
+	"service":    {Paths: []string{"service"}, Type: query.FieldString},
+	"protocol":   {Paths: []string{"service"}, Type: query.FieldString},
+	"title":      {Paths: []string{"title"}, Type: query.FieldString},
+	"app":        {Paths: []string{"app"}, Type: query.FieldString, Transform: trimAppSuffix},
+	"finger":     {Paths: []string{"app"}, Type: query.FieldString, Transform: trimAppSuffix},
+	"status":     {Paths: []string{"status"}, Type: query.FieldKeyword},
+	"banner":     {Paths: []string{"banner"}, Type: query.FieldString},
+	"server":     {Paths: []string{"server"}, Type: query.FieldString},
+	"header":     {Paths: []string{"header"}, Type: query.FieldString},
+	"body":       {Paths: []string{"body"}, Type: query.FieldString},
+	"cert":       {Paths: []string{"cert"}, Type: query.FieldString},
+	"icon_hash":  {Paths: []string{"icon_hash"}, Type: query.FieldKeyword},
+	"cname":      {Paths: []string{"cname"}, Type: query.FieldString},
+	"cdn":        {Paths: []string{"cdn"}, Type: query.FieldBool},
+	"cloud":      {Paths: []string{"cloud"}, Type: query.FieldBool},
+	"cdn_name":   {Paths: []string{"cdn_provider"}, Type: query.FieldString},
+	"cloud_name": {Paths: []string{"cloud_provider"}, Type: query.FieldString},
+	"waf":        {Paths: []string{"waf"}, Type: query.FieldString},
+	"category":   {Paths: []string{"category"}, Type: query.FieldKeyword},
+	"source":     {Paths: []string{"source"}, Type: query.FieldKeyword},
+	"transport":  {Paths: []string{"transport"}, Type: query.FieldKeyword, Transform: strings.ToLower},
+	"label":      {Paths: []string{"labels"}, Type: query.FieldString},
+	"org":        {Paths: []string{"org_id"}, Type: query.FieldKeyword},
+	"location":   {Paths: []string{"ip.ipv4.location", "ip.ipv6.location"}, Type: query.FieldString},
+	"country":    {Paths: []string{"ip.ipv4.country", "ip.ipv6.country"}, Type: query.FieldString},
+	"asn":        {Paths: []string{"ip.ipv4.asn", "ip.ipv6.asn"}, Type: query.FieldNumber},
+	"isp":        {Paths: []string{"ip.ipv4.org", "ip.ipv6.org"}, Type: query.FieldString},
+	"risk":       {Paths: []string{"risk_level"}, Type: query.FieldKeyword},
+	"risk_score": {Paths: []string{"risk_score"}, Type: query.FieldNumber},
+	"is_new":     {Paths: []string{"new"}, Type: query.FieldBool},
+	"is_update":  {Paths: []string{"update"}, Type: query.FieldBool},
+}
+
+// withAliases 复制字段表并追加别名
+func withAliases(fields map[string]query.Field, aliases map[string]string) map[string]query.Field {
+	out := make(map[string]query.Field, len(fields)+len(aliases))
+	for k, v := range fields {
+		out[k] = v
+	}
+	for alias, name := range aliases {
+		out[alias] = fields[name]
+	}
+	return out
+}
+
+var assetAliases = map[string]string{
+	"fingerprint": "app",
+	"httpstatus":  "status",
+	"iconhash":    "icon_hash",
+	"labels":      "label",
+	"org_id":      "org",
+	"risk_level":  "risk",
+}
+
+// AssetQuerySchema 资产列表查询语法
+var AssetQuerySchema = &query.Schema{
+	Fields:     withAliases(assetQueryFields, assetAliases),
+	TextFields: []string{"host", "authority", "title", "domain", "service"},
+}
+
+// SiteQuerySchema 站点列表查询语法
+var SiteQuerySchema = &query.Schema{
+	Fields:     withAliases(assetQueryFields, assetAliases),
+	TextFields: []string{"authority", "host", "title"},
+}
+
+// DomainQuerySchema 域名列表查询语法
+var DomainQuerySchema = &query.Schema{
+	Fields:     withAliases(assetQueryFields, assetAliases),
+	TextFields: []string{"domain", "host", "ip.ipv4.ip"},
+}
+
+// IPQuerySchema IP列表查询语法
+var IPQuerySchema = &query.Schema{
+	Fields:     withAliases(assetQueryFields, assetAliases),
+	TextFields: []string{"host", "ip.ipv4.ip", "ip.ipv6.ip"},
+}
+
+// VulQuerySchema 漏洞列表查询语法
+var VulQuerySchema = &query.Schema{
+	Fields: map[string]query.Field{
+		"host":       {Paths: []string{"host"}, Type: query.FieldIP},
+		"ip":         {Paths: []string{"host"}, Type: query.FieldIP},
+		"port":       {Paths: []string{"port"}, Type: query.FieldNumber},
+		"authority":  {Paths: []string{"authority"}, Type: query.FieldString},
+		"url":        {Paths: []string{"url"}, Type: query.FieldString},
+		"poc":        {Paths: []string{"pocfile"}, Type: query.FieldString},
+		"pocfile":    {Paths: []string{"pocfile"}, Type: query.FieldString},
+		"name":       {Paths: []string{"vul_name"}, Type: query.FieldString},
+		"vul_name":   {Paths: []string{"vul_name"}, Type: query.FieldString},
+		"severity":   {Paths: []string{"severity"}, Type: query.FieldKeyword},
+		"source":     {Paths: []string{"source"}, Type: query.FieldKeyword},
+		"cve":        {Paths: []string{"cve_id"}, Type: query.FieldString},
+		"cwe":        {Paths: []string{"cwe_id"}, Type: query.FieldString},
+		"cvss":       {Paths: []string{"cvss_score"}, Type: query.FieldNumber},
+		"tag":        {Paths: []string{"tags"}, Type: query.FieldString},
+		"task":       {Paths: []string{"task_id"}, Type: query.FieldKeyword},
+		"result":     {Paths: []string{"result"}, Type: query.FieldString},
+		"scan_count": {Paths: []string{"scan_count"}, Type: query.FieldNumber},
+		"status":     {Paths: []string{"status"}, Type: query.FieldKeyword},
+		"assignee":   {Paths: []string{"assignee"}, Type: query.FieldKeyword},
+	},
+	TextFields: []string{"authority", "host", "url", "pocfile", "vul_name"},
+}
+
+// DirScanQuerySchema 目录扫描结果查询语法
+var DirScanQuerySchema = &query.Schema{
+	Fields: map[string]query.Field{
+		"host":         {Paths: []string{"host"}, Type: query.FieldIP},
+		"ip":           {Paths: []string{"host"}, Type: query.FieldIP},
+		"port":         {Paths: []string{"port"}, Type: query.FieldNumber},
+		"authority":    {Paths: []string{"authority"}, Type: query.FieldString},
+		"url":          {Paths: []string{"url"}, Type: query.FieldString},
+		"path":         {Paths: []string{"path"}, Type: query.FieldString},
+		"title":        {Paths: []string{"title"}, Type: query.FieldString},
+		"status":       {Paths: []string{"status_code"}, Type: query.FieldNumber},
+		"size":         {Paths: []string{"content_length"}, Type: query.FieldNumber},
+		"words":        {Paths: []string{"content_words"}, Type: query.FieldNumber},
+		"lines":        {Paths: []string{"content_lines"}, Type: query.FieldNumber},
+		"content_type": {Paths: []string{"content_type"}, Type: query.FieldString},
+		"redirect":     {Paths: []string{"redirect_url"}, Type: query.FieldString},
+		"task":         {Paths: []string{"main_task_id"}, Type: query.FieldKeyword},
+	},
+	TextFields: []string{"url"},
+}
+
+// CompileQuery 将查询语句编译为过滤条件，空查询返回 nil
+func CompileQuery(schema *query.Schema, q string) (bson.M, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, nil
+	}
+	return schema.Compile(q)
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"context"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/model"
+	"cscan/pkg/utils"
+)
+
+// ScopeRules 将扫描范围配置转换为匹配规则
+func ScopeRules(doc *model.ScanScope) *utils.ScopeRules {
+	rules := &utils.ScopeRules{
+		Cidrs:    doc.Cidrs,
+		Domains:  doc.Domains,
+		Hosts:    doc.Hosts,
+		Timezone: doc.Timezone,
+		StrictIP: doc.StrictIP,
+	}
+	for _, w := range doc.Windows {
+		rules.Windows = append(rules.Windows, utils.ScopeWindow{Days: w.Days, Start: w.Start, End: w.End})
+	}
+	return rules
+}
+
+// LoadScopeMatcher 加载工作空间已启用的扫描范围，未配置或未启用时返回 nil
+func LoadScopeMatcher(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId string) (*utils.ScopeMatcher, error) {
+	doc, err := svcCtx.ScanScopeModel.GetByWorkspace(ctx, workspaceId)
+	if err != nil || doc == nil || !doc.Enabled {
+		return nil, err
+	}
+	return utils.NewScopeMatcher(ScopeRules(doc))
+}
+
+// TargetsOutOfScope 返回不在扫描范围内的目标，matcher 为 nil 时不限制
+func TargetsOutOfScope(matcher *utils.ScopeMatcher, target string) []string {
+	if matcher == nil {
+		return nil
+	}
+	var outOfScope []string
+	for _, line := range strings.Split(target, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !matcher.InScope(line) {
+			outOfScope = append(outOfScope, line)
+		}
+	}
+	return outOfScope
+}
@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"cscan/pkg/utils"
 )
 
 // TargetValidationError 目标校验错误
@@ -228,6 +230,11 @@ func validateIPv6Target(target string) error {
 		}
 	}
 
+	// IPv6 范围格式
+	if strings.Contains(ipv6, "-") {
+		return validateIPRange(ipv6)
+	}
+
 	// 去除 Zone ID（如 %eth0 或 %5）
 	if zoneIndex := strings.Index(ipv6, "%"); zoneIndex != -1 {
 		ipv6 = ipv6[:zoneIndex]
@@ -243,6 +250,9 @@ func validateIPv6Target(target string) error {
 		if err != nil || mask < 0 || mask > 128 {
 			return fmt.Errorf("无效的IPv6子网掩码: %s", parts[1])
 		}
+		if mask < utils.IPv6MinPrefixLen {
+			return fmt.Errorf("IPv6前缀 /%d 过大，最大支持 /%d", mask, utils.IPv6MinPrefixLen)
+		}
 		ipv6 = parts[0]
 	}
 
@@ -321,12 +331,15 @@ func validateIPRange(ipRange string) error {
 		return fmt.Errorf("结束IP '%s' 无效", parts[1])
 	}
 
+	// IPv6 范围限制地址数量
+	if startIP.To4() == nil || endIP.To4() == nil {
+		_, err := utils.ExpandIPRange(startIP, endIP, 0)
+		return err
+	}
+
 	// 检查起始IP是否小于等于结束IP
 	start := startIP.To4()
 	end := endIP.To4()
-	if start == nil || end == nil {
-		return fmt.Errorf("仅支持IPv4范围")
-	}
 
 	for i := 0; i < 4; i++ {
 		if start[i] > end[i] {
@@ -8,6 +8,7 @@ import (
 
 	"cscan/api/internal/svc"
 	"cscan/model"
+	"cscan/pkg/utils"
 	"cscan/scanner"
 	"cscan/scheduler"
 
@@ -156,7 +157,7 @@ func buildPrewriteAssetKey(asset *scanner.Asset) string {
 		return ""
 	}
 	if asset.Port > 0 {
-		return fmt.Sprintf("%s:%d", asset.Host, asset.Port)
+		return utils.BuildTargetWithPort(asset.Host, asset.Port)
 	}
 	return asset.Authority
 }
@@ -267,6 +268,14 @@ func (b *TaskBuilder) countEnabledModules(configMap map[string]interface{}) int
 	// Simplified parsing for counting
 	// Since we are working with map[string]interface{}, we need to check keys safely
 	// Note: JSON keys from task config are lowercase (e.g. "domainscan", "portscan")
+
+	// Declarative workflow: every stage reports completion once
+	if wf, ok := configMap["workflow"].(map[string]interface{}); ok {
+		if stages, ok := wf["stages"].([]interface{}); ok && len(stages) > 0 {
+			return len(stages)
+		}
+	}
+
 	count := 0
 
 	// DomainScan
@@ -284,7 +293,7 @@ func (b *TaskBuilder) countEnabledModules(configMap map[string]interface{}) int
 	}
 
 	// Other modules...
-	modules := []string{"portidentify", "fingerprint", "dirscan", "pocscan"}
+	modules := []string{"takeover", "dnsrecord", "portidentify", "fingerprint", "external", "crawler", "bruteforce", "dirscan", "pocscan"}
 	for _, mod := range modules {
 		if m, ok := configMap[mod].(map[string]interface{}); ok {
 			if enable, ok := m["enable"].(bool); ok && enable {
@@ -3,6 +3,8 @@ package logic
 import (
 	"context"
 	"strconv"
+	"strings"
+	"time"
 
 	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
@@ -33,6 +35,11 @@ func (l *DomainLogic) DomainList(req *types.DomainListReq, workspaceId string) (
 		return resp, nil
 	}
 
+	queryFilter, err := common.CompileQuery(common.DomainQuerySchema, req.Query)
+	if err != nil {
+		return &types.DomainListResp{Code: 400, Msg: err.Error()}, nil
+	}
+
 	orgMap := common.LoadOrgMap(l.ctx, l.svcCtx)
 
 	// 用于去重和聚合域名
@@ -49,39 +56,32 @@ func (l *DomainLogic) DomainList(req *types.DomainListReq, workspaceId string) (
 			{"source": "subfinder"},
 		}
 
-		filter := bson.M{}
+		conditions := []bson.M{{"$or": baseCondition}}
 
-		// 优先使用通用 Query 关键字（当未指定 Domain/RootDomain/IP 时）
-		if req.Query != "" && req.Domain == "" && req.RootDomain == "" && req.IP == "" {
-			filter["$and"] = []bson.M{
-				{"$or": baseCondition},
-				{"$or": []bson.M{
-					{"domain": bson.M{"$regex": req.Query, "$options": "i"}},
-					{"host": bson.M{"$regex": req.Query, "$options": "i"}},
-					{"ip.ipv4.ip": bson.M{"$regex": req.Query, "$options": "i"}},
-				}},
-			}
-		} else if req.Domain != "" {
+		// 查询语法（关键词模糊匹配 domain/host/ip）
+		if queryFilter != nil {
+			conditions = append(conditions, queryFilter)
+		}
+
+		if req.Domain != "" {
 			// 域名搜索
-			filter["$and"] = []bson.M{
-				{"$or": baseCondition},
-				{"domain": bson.M{"$regex": req.Domain, "$options": "i"}},
-			}
+			conditions = append(conditions, bson.M{"domain": bson.M{"$regex": req.Domain, "$options": "i"}})
 		} else if req.RootDomain != "" {
 			// 根域名搜索
-			filter["$and"] = []bson.M{
-				{"$or": baseCondition},
-				{"$or": []bson.M{
+			conditions = append(conditions, bson.M{
+				"$or": []bson.M{
 					{"domain": bson.M{"$regex": "\\." + req.RootDomain + "$", "$options": "i"}},
 					{"host": bson.M{"$regex": "\\." + req.RootDomain + "$", "$options": "i"}},
-				}},
-			}
+				},
+			})
 		} else if req.IP != "" {
 			// IP搜索 - 搜索解析到该IP的域名
-			filter["$and"] = []bson.M{
-				{"$or": baseCondition},
-				{"ip.ipv4.ip": bson.M{"$regex": req.IP, "$options": "i"}},
-			}
+			conditions = append(conditions, bson.M{"ip.ipv4.ip": bson.M{"$regex": req.IP, "$options": "i"}})
+		}
+
+		filter := bson.M{}
+		if len(conditions) > 1 {
+			filter["$and"] = conditions
 		} else {
 			// 无搜索条件，只用基础条件
 			filter["$or"] = baseCondition
@@ -358,3 +358,43 @@ func (l *DomainLogic) DomainBatchDelete(req *types.DomainBatchDeleteReq, workspa
 
 	return &types.BaseResp{Code: 0, Msg: "成功删除 " + strconv.FormatInt(int64(len(domainNames)), 10) + " 个域名"}, nil
 }
+
+// DomainDNSRecords 域名DNS记录历史，Current 标记最近一次查询中仍存在的记录
+func (l *DomainLogic) DomainDNSRecords(req *types.DomainDNSRecordsReq, workspaceId string) (*types.DomainDNSRecordsResp, error) {
+	domain := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(req.Domain), "."))
+	if domain == "" {
+		return &types.DomainDNSRecordsResp{Code: 400, Msg: "域名不能为空"}, nil
+	}
+	if req.WorkspaceId != "" {
+		workspaceId = req.WorkspaceId
+	}
+
+	list := []types.DNSRecord{}
+	for _, wsId := range common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId) {
+		docs, err := l.svcCtx.GetDNSRecordModel(wsId).FindByDomain(l.ctx, domain, strings.ToUpper(req.Type))
+		if err != nil {
+			return &types.DomainDNSRecordsResp{Code: 500, Msg: "查询失败"}, nil
+		}
+		var latest time.Time
+		for _, doc := range docs {
+			if doc.LastSeen.After(latest) {
+				latest = doc.LastSeen
+			}
+		}
+		for _, doc := range docs {
+			list = append(list, types.DNSRecord{
+				Domain:      doc.Domain,
+				Name:        doc.Name,
+				Type:        doc.Type,
+				Value:       doc.Value,
+				TTL:         doc.TTL,
+				Current:     doc.LastSeen.Equal(latest),
+				FirstSeen:   doc.FirstSeen.Local().Format("2006-01-02 15:04:05"),
+				LastSeen:    doc.LastSeen.Local().Format("2006-01-02 15:04:05"),
+				WorkspaceId: wsId,
+			})
+		}
+	}
+
+	return &types.DomainDNSRecordsResp{Code: 0, Msg: "success", Total: len(list), List: list}, nil
+}
@@ -0,0 +1,124 @@
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ExternalScannerLogic 外部扫描器管理
+type ExternalScannerLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewExternalScannerLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ExternalScannerLogic {
+	return &ExternalScannerLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func convertExternalScanner(doc *model.ExternalScanner) types.ExternalScanner {
+	return types.ExternalScanner{
+		Id:          doc.Id.Hex(),
+		Name:        doc.Name,
+		Description: doc.Description,
+		Binary:      doc.Binary,
+		Args:        doc.Args,
+		Env:         doc.Env,
+		InputFormat: doc.InputFormat,
+		ResultType:  doc.ResultType,
+		FieldMap:    doc.FieldMap,
+		Timeout:     doc.Timeout,
+		Enabled:     doc.Enabled,
+		CreateTime:  doc.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		UpdateTime:  doc.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+}
+
+// ExternalScannerList 外部扫描器列表
+func (l *ExternalScannerLogic) ExternalScannerList() (*types.ExternalScannerListResp, error) {
+	docs, err := l.svcCtx.ExternalScannerModel.FindWithSort(l.ctx, bson.M{}, 0, 0, "name", 1)
+	if err != nil {
+		l.Errorf("查询外部扫描器失败: %v", err)
+		return &types.ExternalScannerListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.ExternalScanner, 0, len(docs))
+	for i := range docs {
+		list = append(list, convertExternalScanner(&docs[i]))
+	}
+	return &types.ExternalScannerListResp{Code: 0, Msg: "success", List: list}, nil
+}
+
+// ExternalScannerSave 新建或更新外部扫描器
+func (l *ExternalScannerLogic) ExternalScannerSave(req *types.ExternalScannerSaveReq) (*types.BaseResp, error) {
+	spec := &scanner.ExternalScannerSpec{
+		Name:        strings.TrimSpace(req.Name),
+		Binary:      strings.TrimSpace(req.Binary),
+		Args:        req.Args,
+		Env:         req.Env,
+		InputFormat: req.InputFormat,
+		ResultType:  req.ResultType,
+		FieldMap:    req.FieldMap,
+		Timeout:     req.Timeout,
+	}
+	if err := spec.Validate(); err != nil {
+		return &types.BaseResp{Code: 400, Msg: err.Error()}, nil
+	}
+	// 不允许与内置扫描器重名，避免任务配置产生歧义
+	if scanner.DefaultRegistry().Has(spec.Name) {
+		return &types.BaseResp{Code: 400, Msg: "名称与内置扫描器冲突: " + spec.Name}, nil
+	}
+
+	existing, err := l.svcCtx.ExternalScannerModel.FindByName(l.ctx, spec.Name)
+	if err != nil {
+		l.Errorf("查询外部扫描器失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存失败"}, nil
+	}
+	if existing != nil && existing.Id.Hex() != req.Id {
+		return &types.BaseResp{Code: 400, Msg: "名称已存在"}, nil
+	}
+
+	doc := &model.ExternalScanner{
+		Name:        spec.Name,
+		Description: req.Description,
+		Binary:      spec.Binary,
+		Args:        spec.Args,
+		Env:         spec.Env,
+		InputFormat: spec.InputFormat,
+		ResultType:  spec.ResultType,
+		FieldMap:    spec.FieldMap,
+		Timeout:     spec.Timeout,
+		Enabled:     req.Enabled,
+	}
+	if req.Id == "" {
+		err = l.svcCtx.ExternalScannerModel.Create(l.ctx, doc)
+	} else {
+		err = l.svcCtx.ExternalScannerModel.Update(l.ctx, req.Id, doc)
+	}
+	if err != nil {
+		l.Errorf("保存外部扫描器失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+}
+
+// ExternalScannerDelete 删除外部扫描器
+func (l *ExternalScannerLogic) ExternalScannerDelete(req *types.ExternalScannerDeleteReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.ExternalScannerModel.DeleteById(l.ctx, req.Id); err != nil {
+		l.Errorf("删除外部扫描器失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
@@ -0,0 +1,125 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/geoip"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GeoIPDatasetLogic IP归属地数据集管理
+type GeoIPDatasetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGeoIPDatasetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GeoIPDatasetLogic {
+	return &GeoIPDatasetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func convertGeoIPDataset(doc *model.GeoIPDataset) types.GeoIPDataset {
+	return types.GeoIPDataset{
+		Id:          doc.Id.Hex(),
+		Name:        doc.Name,
+		Type:        doc.Type,
+		FileName:    doc.FileName,
+		Size:        doc.Size,
+		Sha256:      doc.Sha256,
+		Description: doc.Description,
+		Enabled:     doc.Enabled,
+		CreateTime:  doc.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		UpdateTime:  doc.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+}
+
+// GeoIPDatasetList IP归属地数据集列表
+func (l *GeoIPDatasetLogic) GeoIPDatasetList() (*types.GeoIPDatasetListResp, error) {
+	docs, err := l.svcCtx.GeoIPDatasetModel.FindWithSort(l.ctx, bson.M{}, 0, 0, "name", 1)
+	if err != nil {
+		l.Errorf("查询IP归属地数据集失败: %v", err)
+		return &types.GeoIPDatasetListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.GeoIPDataset, 0, len(docs))
+	for i := range docs {
+		list = append(list, convertGeoIPDataset(&docs[i]))
+	}
+	return &types.GeoIPDatasetListResp{Code: 0, Msg: "success", Total: len(list), List: list}, nil
+}
+
+// GeoIPDatasetUpload 上传IP归属地数据集，文件须能被解析
+func (l *GeoIPDatasetLogic) GeoIPDatasetUpload(req *types.GeoIPDatasetUploadReq, fileName string, content []byte) (*types.BaseResp, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return &types.BaseResp{Code: 400, Msg: "数据集名称不能为空"}, nil
+	}
+	typ := strings.ToLower(strings.TrimSpace(req.Type))
+	if typ == "" {
+		typ = geoip.DetectType(fileName)
+	}
+	if typ != geoip.TypeMMDB && typ != geoip.TypeIP2Region {
+		return &types.BaseResp{Code: 400, Msg: "不支持的数据集类型，仅支持 mmdb 和 ip2region(xdb)"}, nil
+	}
+	if _, err := geoip.NewReader(typ, content); err != nil {
+		return &types.BaseResp{Code: 400, Msg: "数据集文件解析失败: " + err.Error()}, nil
+	}
+
+	existing, err := l.svcCtx.GeoIPDatasetModel.FindByName(l.ctx, name)
+	if err != nil {
+		l.Errorf("查询IP归属地数据集失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "上传失败"}, nil
+	}
+	if existing != nil {
+		return &types.BaseResp{Code: 400, Msg: "数据集名称已存在"}, nil
+	}
+
+	doc := &model.GeoIPDataset{
+		Name:        name,
+		Type:        typ,
+		FileName:    fileName,
+		Description: req.Description,
+		Enabled:     req.Enabled,
+	}
+	if err := l.svcCtx.GeoIPDatasetModel.Create(l.ctx, doc, bytes.NewReader(content)); err != nil {
+		l.Errorf("保存IP归属地数据集失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "上传失败"}, nil
+	}
+	l.svcCtx.GeoIP.Invalidate()
+	return &types.BaseResp{Code: 0, Msg: "上传成功"}, nil
+}
+
+// GeoIPDatasetDelete 删除IP归属地数据集
+func (l *GeoIPDatasetLogic) GeoIPDatasetDelete(req *types.GeoIPDatasetDeleteReq) (*types.BaseResp, error) {
+	doc, err := l.svcCtx.GeoIPDatasetModel.FindById(l.ctx, req.Id)
+	if err != nil {
+		return &types.BaseResp{Code: 404, Msg: "数据集不存在"}, nil
+	}
+	if err := l.svcCtx.GeoIPDatasetModel.Delete(l.ctx, doc); err != nil {
+		l.Errorf("删除IP归属地数据集失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	l.svcCtx.GeoIP.Invalidate()
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
+// GeoIPDatasetUpdateEnabled 启用或禁用IP归属地数据集
+func (l *GeoIPDatasetLogic) GeoIPDatasetUpdateEnabled(req *types.GeoIPDatasetUpdateEnabledReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.GeoIPDatasetModel.UpdateById(l.ctx, req.Id, bson.M{"enabled": req.Enabled}); err != nil {
+		l.Errorf("更新IP归属地数据集状态失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "更新失败"}, nil
+	}
+	l.svcCtx.GeoIP.Invalidate()
+	return &types.BaseResp{Code: 0, Msg: "更新成功"}, nil
+}
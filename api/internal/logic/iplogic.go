@@ -2,8 +2,11 @@ package logic
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 
 	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
@@ -35,6 +38,11 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 		return resp, nil
 	}
 
+	queryFilter, err := common.CompileQuery(common.IPQuerySchema, req.Query)
+	if err != nil {
+		return &types.IPListResp{Code: 400, Msg: err.Error()}, nil
+	}
+
 	orgMap := common.LoadOrgMap(l.ctx, l.svcCtx)
 
 	// 用于聚合IP信息
@@ -51,14 +59,9 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 		// 基础条件：有IP的资产
 		// 不加基础条件，查询所有资产然后提取IP
 
-		// 最上方筛选 (Query)
-		if req.Query != "" {
-			conditions = append(conditions, bson.M{
-				"$or": []bson.M{
-					{"host": bson.M{"$regex": req.Query, "$options": "i"}},
-					{"ip.ipv4.ip": bson.M{"$regex": req.Query, "$options": "i"}},
-				},
-			})
+		// 最上方筛选 (Query 查询语法)
+		if queryFilter != nil {
+			conditions = append(conditions, queryFilter)
 		}
 
 		// IP搜索
@@ -88,6 +91,8 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 		if req.OrgId != "" {
 			conditions = append(conditions, bson.M{"org_id": req.OrgId})
 		}
+		// ASN/国家
+		conditions = append(conditions, ipGeoConditions(req.ASN, req.Country)...)
 
 		if len(conditions) > 0 {
 			filter["$and"] = conditions
@@ -104,11 +109,13 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 			// 收集所有IP地址
 			var ips []string
 			var location string
+			geo := make(map[string]model.IPV4)
 
 			// 从ip.ipv4字段获取IP
 			for _, ipv4 := range asset.Ip.IpV4 {
 				if ipv4.IPName != "" {
 					ips = append(ips, ipv4.IPName)
+					geo[ipv4.IPName] = ipv4
 					if location == "" && ipv4.Location != "" {
 						location = ipv4.Location
 					}
@@ -178,6 +185,9 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 					if existing.Location == "" && location != "" {
 						existing.Location = location
 					}
+					if g, ok := geo[ip]; ok && existing.Country == "" && existing.ASN == "" {
+						existing.Country, existing.ASN, existing.ISP = g.Country, formatASN(g.ASN), g.Org
+					}
 				} else {
 					// 创建新的IP记录
 					ports := []types.PortInfo{}
@@ -201,6 +211,9 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 						Id:          asset.Id.Hex(),
 						IP:          ip,
 						Location:    location,
+						Country:     geo[ip].Country,
+						ASN:         formatASN(geo[ip].ASN),
+						ISP:         geo[ip].Org,
 						Ports:       ports,
 						Domains:     domains,
 						DomainCount: len(domains),
@@ -214,12 +227,38 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 		}
 	}
 
-	// 转换为列表并排序
+	// 转换为列表并排序，同一资产的其他IP可能不属于所筛选的ASN/国家
 	allIPs := make([]types.IPAsset, 0, len(ipMap))
 	for _, ip := range ipMap {
+		if req.ASN > 0 && ip.ASN != formatASN(req.ASN) {
+			continue
+		}
+		if req.Country != "" && !strings.EqualFold(ip.Country, req.Country) {
+			continue
+		}
 		allIPs = append(allIPs, *ip)
 	}
 
+	// 按ASN/国家分组统计IP数量
+	switch req.GroupBy {
+	case "asn":
+		counts := make(map[string]int)
+		for _, ip := range allIPs {
+			if ip.ASN != "" {
+				counts[strings.TrimSpace(ip.ASN+" "+ip.ISP)]++
+			}
+		}
+		resp.Groups = sortMapToStatItems(counts, geoGroupLimit)
+	case "country":
+		counts := make(map[string]int)
+		for _, ip := range allIPs {
+			if ip.Country != "" {
+				counts[ip.Country]++
+			}
+		}
+		resp.Groups = sortMapToStatItems(counts, geoGroupLimit)
+	}
+
 	// 按端口数量降序排序
 	sort.Slice(allIPs, func(i, j int) bool {
 		return len(allIPs[i].Ports) > len(allIPs[j].Ports)
@@ -243,6 +282,58 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 	return resp, nil
 }
 
+// geoGroupLimit 分组统计最多返回的分组数
+const geoGroupLimit = 50
+
+// formatASN 格式化ASN编号，0 返回空字符串
+func formatASN(asn int) string {
+	if asn <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("AS%d", asn)
+}
+
+// ipGeoConditions 构建按IP的ASN/国家筛选条件，国家名称不区分大小写精确匹配
+func ipGeoConditions(asn int, country string) []bson.M {
+	var conds []bson.M
+	if asn > 0 {
+		conds = append(conds, bson.M{"$or": []bson.M{
+			{"ip.ipv4.asn": asn},
+			{"ip.ipv6.asn": asn},
+		}})
+	}
+	if country = strings.TrimSpace(country); country != "" {
+		re := bson.M{"$regex": "^" + regexp.QuoteMeta(country) + "$", "$options": "i"}
+		conds = append(conds, bson.M{"$or": []bson.M{
+			{"ip.ipv4.country": re},
+			{"ip.ipv6.country": re},
+		}})
+	}
+	return conds
+}
+
+// groupAssetsByGeo 按IP的ASN或国家分组统计多个工作空间中匹配 filter 的资产数量
+func groupAssetsByGeo(ctx context.Context, svcCtx *svc.ServiceContext, workspaceIds []string, filter bson.M, groupBy string) ([]types.StatItem, error) {
+	if groupBy != "asn" && groupBy != "country" {
+		return nil, nil
+	}
+	counts := make(map[string]int)
+	for _, wsId := range workspaceIds {
+		results, err := svcCtx.GetAssetModel(wsId).AggregateIPGeo(ctx, filter, groupBy, geoGroupLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			name := fmt.Sprint(r.Key)
+			if groupBy == "asn" {
+				name = strings.TrimSpace("AS" + name + " " + r.Org)
+			}
+			counts[name] += r.Count
+		}
+	}
+	return sortMapToStatItems(counts, geoGroupLimit), nil
+}
+
 // IPStat IP统计
 func (l *IPLogic) IPStat(workspaceId string) (*types.IPStatResp, error) {
 	resp := &types.IPStatResp{Code: 0}
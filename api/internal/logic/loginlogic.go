@@ -41,7 +41,8 @@ func (l *LoginLogic) Login(req *types.LoginReq) (resp *types.LoginResp, err erro
 	// 生成JWT Token
 	now := time.Now().Unix()
 	accessExpire := l.svcCtx.Config.Auth.AccessExpire
-	token, err := l.generateToken(user.Id.Hex(), user.Username, now, accessExpire)
+	role := user.RoleFor("")
+	token, err := l.generateToken(user.Id.Hex(), user.Username, role, now, accessExpire)
 	if err != nil {
 		return &types.LoginResp{
 			Code: 500,
@@ -57,21 +58,23 @@ func (l *LoginLogic) Login(req *types.LoginReq) (resp *types.LoginResp, err erro
 	// 注意：workspaceId 为空时，后端会使用 "default" 工作空间
 
 	return &types.LoginResp{
-		Code:        0,
-		Msg:         "登录成功",
-		Token:       token,
-		UserId:      user.Id.Hex(),
-		Username:    user.Username,
-		Role:        "admin", // 所有用户都是管理员
-		WorkspaceId: workspaceId,
+		Code:           0,
+		Msg:            "登录成功",
+		Token:          token,
+		UserId:         user.Id.Hex(),
+		Username:       user.Username,
+		Role:           role,
+		WorkspaceRoles: user.WorkspaceRoles,
+		WorkspaceId:    workspaceId,
 	}, nil
 }
 
-func (l *LoginLogic) generateToken(userId, username string, iat, expire int64) (string, error) {
+// generateToken 生成JWT，role 为全局角色，工作空间角色由权限中间件按请求实时解析
+func (l *LoginLogic) generateToken(userId, username, role string, iat, expire int64) (string, error) {
 	claims := jwt.MapClaims{
 		"userId":   userId,
 		"username": username,
-		"role":     "admin", // 所有用户都是管理员
+		"role":     role,
 		"iat":      iat,
 		"exp":      iat + expire,
 	}
@@ -53,6 +53,7 @@ func (l *NotifyConfigListLogic) NotifyConfigList() (resp *types.NotifyConfigList
 				HighRiskFingerprints: c.HighRiskFilter.HighRiskFingerprints,
 				HighRiskPorts:        c.HighRiskFilter.HighRiskPorts,
 				HighRiskPocSeverities: c.HighRiskFilter.HighRiskPocSeverities,
+				DiffOnly:             c.HighRiskFilter.DiffOnly,
 			}
 		}
 		list = append(list, item)
@@ -93,6 +94,7 @@ func (l *NotifyConfigSaveLogic) NotifyConfigSave(req *types.NotifyConfigSaveReq)
 			HighRiskFingerprints: req.HighRiskFilter.HighRiskFingerprints,
 			HighRiskPorts:        req.HighRiskFilter.HighRiskPorts,
 			HighRiskPocSeverities: req.HighRiskFilter.HighRiskPocSeverities,
+			DiffOnly:             req.HighRiskFilter.DiffOnly,
 		}
 	}
 
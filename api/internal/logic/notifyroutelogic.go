@@ -0,0 +1,142 @@
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NotifyRouteLogic 通知路由规则管理
+type NotifyRouteLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewNotifyRouteLogic(ctx context.Context, svcCtx *svc.ServiceContext) *NotifyRouteLogic {
+	return &NotifyRouteLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// List 通知路由规则列表
+func (l *NotifyRouteLogic) List() (*types.NotifyRouteListResp, error) {
+	routes, err := l.svcCtx.NotifyRouteModel.FindWithSort(l.ctx, bson.M{}, 0, 0, "create_time", 1)
+	if err != nil {
+		l.Errorf("查询通知路由失败: %v", err)
+		return &types.NotifyRouteListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.NotifyRoute, 0, len(routes))
+	for _, r := range routes {
+		list = append(list, types.NotifyRoute{
+			Id:           r.Id.Hex(),
+			Name:         r.Name,
+			Events:       r.Events,
+			WorkspaceIds: r.WorkspaceIds,
+			OrgIds:       r.OrgIds,
+			MinSeverity:  r.MinSeverity,
+			Tags:         r.Tags,
+			Labels:       r.Labels,
+			ConfigIds:    r.ConfigIds,
+			Template:     r.Template,
+			QuietStart:   r.QuietStart,
+			QuietEnd:     r.QuietEnd,
+			Digest:       r.Digest,
+			Enabled:      r.Enabled,
+			Description:  r.Description,
+			CreateTime:   r.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+	return &types.NotifyRouteListResp{Code: 0, Msg: "success", List: list}, nil
+}
+
+// Save 新增或更新通知路由规则
+func (l *NotifyRouteLogic) Save(req *types.NotifyRouteSaveReq) (*types.BaseResp, error) {
+	route := &model.NotifyRoute{
+		Name:         strings.TrimSpace(req.Name),
+		Events:       req.Events,
+		WorkspaceIds: req.WorkspaceIds,
+		OrgIds:       req.OrgIds,
+		MinSeverity:  req.MinSeverity,
+		Tags:         trimStrings(req.Tags),
+		Labels:       trimStrings(req.Labels),
+		ConfigIds:    req.ConfigIds,
+		Template:     req.Template,
+		QuietStart:   strings.TrimSpace(req.QuietStart),
+		QuietEnd:     strings.TrimSpace(req.QuietEnd),
+		Digest:       req.Digest,
+		Enabled:      req.Enabled,
+		Description:  req.Description,
+	}
+	if route.Name == "" {
+		return &types.BaseResp{Code: 400, Msg: "规则名称不能为空"}, nil
+	}
+	rule := route.RouteRule()
+	if err := rule.Validate(); err != nil {
+		return &types.BaseResp{Code: 400, Msg: "规则无效: " + err.Error()}, nil
+	}
+
+	if req.Id != "" {
+		err := l.svcCtx.NotifyRouteModel.UpdateById(l.ctx, req.Id, bson.M{
+			"name":          route.Name,
+			"events":        route.Events,
+			"workspace_ids": route.WorkspaceIds,
+			"org_ids":       route.OrgIds,
+			"min_severity":  route.MinSeverity,
+			"tags":          route.Tags,
+			"labels":        route.Labels,
+			"config_ids":    route.ConfigIds,
+			"template":      route.Template,
+			"quiet_start":   route.QuietStart,
+			"quiet_end":     route.QuietEnd,
+			"digest":        route.Digest,
+			"enabled":       route.Enabled,
+			"description":   route.Description,
+		})
+		if err != nil {
+			l.Errorf("更新通知路由失败: %v", err)
+			return &types.BaseResp{Code: 500, Msg: "更新失败"}, nil
+		}
+		return &types.BaseResp{Code: 0, Msg: "更新成功"}, nil
+	}
+
+	if err := l.svcCtx.NotifyRouteModel.Insert(l.ctx, route); err != nil {
+		l.Errorf("创建通知路由失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "创建失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "创建成功"}, nil
+}
+
+// Delete 删除通知路由规则及其尚未发送的摘要
+func (l *NotifyRouteLogic) Delete(req *types.NotifyRouteDeleteReq) (*types.BaseResp, error) {
+	if req.Id == "" {
+		return &types.BaseResp{Code: 400, Msg: "ID不能为空"}, nil
+	}
+	if err := l.svcCtx.NotifyRouteModel.DeleteById(l.ctx, req.Id); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	if err := l.svcCtx.NotifyDigestModel.DeleteByRuleId(l.ctx, req.Id); err != nil {
+		l.Errorf("删除通知摘要失败: %v", err)
+	}
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
+// trimStrings 去除空白并丢弃空字符串
+func trimStrings(list []string) []string {
+	out := make([]string, 0, len(list))
+	for _, s := range list {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
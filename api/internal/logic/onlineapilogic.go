@@ -11,6 +11,7 @@ import (
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
+	"cscan/pkg/utils"
 	"cscan/onlineapi"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -207,7 +208,7 @@ func (l *OnlineAPILogic) Import(req *types.OnlineImportReq, workspaceId string)
 		}
 
 		// Construct correct Authority format (host:port)
-		authority := fmt.Sprintf("%s:%d", host, a.Port)
+		authority := utils.BuildTargetWithPort(host, a.Port)
 
 		// 自动添加标签
 		// 使用 title case: fofa -> Fofa
@@ -235,6 +236,7 @@ func (l *OnlineAPILogic) Import(req *types.OnlineImportReq, workspaceId string)
 			Domain: domain,
 			Server: a.Server,
 			Banner: a.Banner,
+			ICP:    a.ICP,
 			// Initialize default fields to ensure compatibility
 			IsNewAsset: true,
 			CreateTime: time.Now(),
@@ -260,6 +262,9 @@ func (l *OnlineAPILogic) Import(req *types.OnlineImportReq, workspaceId string)
 		// 但在线导入通常是新资产或更新基础信息。
 		// 让我们依赖 model.Asset 的逻辑。当前 logic 中构造了 asset 对象。
 
+		// 按归属规则分配组织，未命中时不覆盖已有组织
+		asset.OrgId = l.svc.OrgAttribution.Attribute(l.ctx, asset)
+
 		if err := assetModel.Upsert(l.ctx, asset); err == nil {
 			count++
 		}
@@ -418,7 +423,7 @@ PageLoop:
 			}
 
 			// Construct correct Authority format (host:port)
-			authority := fmt.Sprintf("%s:%d", host, a.Port)
+			authority := utils.BuildTargetWithPort(host, a.Port)
 
 			// 自动添加标签
 			platformTag := req.Platform
@@ -441,6 +446,7 @@ PageLoop:
 				Domain: domain,
 				Server: a.Server,
 				Banner: a.Banner,
+				ICP:    a.ICP,
 				// Initialize default fields
 				IsNewAsset: true,
 				CreateTime: time.Now(),
@@ -454,6 +460,8 @@ PageLoop:
 				}
 			}
 
+			asset.OrgId = l.svc.OrgAttribution.Attribute(l.ctx, asset)
+
 			if err := assetModel.Upsert(l.ctx, asset); err == nil {
 				totalImport++
 			}
@@ -2,7 +2,10 @@ package logic
 
 import (
 	"context"
+	"sort"
+	"strings"
 
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
@@ -40,14 +43,8 @@ func (l *OrganizationListLogic) OrganizationList(req *types.PageReq) (resp *type
 	}
 
 	list := make([]types.Organization, 0, len(orgs))
-	for _, o := range orgs {
-		list = append(list, types.Organization{
-			Id:          o.Id.Hex(),
-			Name:        o.Name,
-			Description: o.Description,
-			Status:      o.Status,
-			CreateTime:  o.CreateTime.Local().Format("2006-01-02 15:04:05"),
-		})
+	for i := range orgs {
+		list = append(list, convertOrganization(&orgs[i]))
 	}
 
 	return &types.OrganizationListResp{
@@ -74,11 +71,19 @@ func NewOrganizationSaveLogic(ctx context.Context, svcCtx *svc.ServiceContext) *
 }
 
 func (l *OrganizationSaveLogic) OrganizationSave(req *types.OrganizationSaveReq) (resp *types.BaseResp, err error) {
+	if msg := l.checkParent(req.Id, req.ParentId); msg != "" {
+		return &types.BaseResp{Code: 400, Msg: msg}, nil
+	}
+	contacts := convertOrgContacts(req.Contacts)
+
 	if req.Id != "" {
 		// 更新
 		update := bson.M{
 			"name":        req.Name,
 			"description": req.Description,
+			"parent_id":   req.ParentId,
+			"contacts":    contacts,
+			"tags":        req.Tags,
 		}
 		if req.Status != "" {
 			update["status"] = req.Status
@@ -87,6 +92,7 @@ func (l *OrganizationSaveLogic) OrganizationSave(req *types.OrganizationSaveReq)
 		if err != nil {
 			return &types.BaseResp{Code: 500, Msg: "更新失败"}, nil
 		}
+		l.svcCtx.OrgAttribution.Invalidate()
 		return &types.BaseResp{Code: 0, Msg: "更新成功"}, nil
 	}
 
@@ -94,6 +100,9 @@ func (l *OrganizationSaveLogic) OrganizationSave(req *types.OrganizationSaveReq)
 	org := &model.Organization{
 		Name:        req.Name,
 		Description: req.Description,
+		ParentId:    req.ParentId,
+		Contacts:    contacts,
+		Tags:        req.Tags,
 	}
 	if err = l.svcCtx.OrganizationModel.Insert(l.ctx, org); err != nil {
 		return &types.BaseResp{Code: 500, Msg: "创建失败"}, nil
@@ -102,6 +111,73 @@ func (l *OrganizationSaveLogic) OrganizationSave(req *types.OrganizationSaveReq)
 	return &types.BaseResp{Code: 0, Msg: "创建成功"}, nil
 }
 
+// checkParent 校验上级组织存在，且不是自身或自身的下级组织
+func (l *OrganizationSaveLogic) checkParent(id, parentId string) string {
+	if parentId == "" {
+		return ""
+	}
+	if parentId == id {
+		return "上级组织不能是自身"
+	}
+	if _, err := l.svcCtx.OrganizationModel.FindById(l.ctx, parentId); err != nil {
+		return "上级组织不存在"
+	}
+	if id == "" {
+		return ""
+	}
+	descendants, err := l.svcCtx.OrganizationModel.DescendantIds(l.ctx, id)
+	if err != nil {
+		return "查询下级组织失败"
+	}
+	for _, d := range descendants {
+		if d == parentId {
+			return "上级组织不能是自身的下级组织"
+		}
+	}
+	return ""
+}
+
+func convertOrganization(o *model.Organization) types.Organization {
+	org := types.Organization{
+		Id:          o.Id.Hex(),
+		Name:        o.Name,
+		Description: o.Description,
+		Status:      o.Status,
+		ParentId:    o.ParentId,
+		Tags:        o.Tags,
+		CreateTime:  o.CreateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+	for _, c := range o.Contacts {
+		org.Contacts = append(org.Contacts, types.OrgContact{
+			Name:  c.Name,
+			Role:  c.Role,
+			Email: c.Email,
+			Phone: c.Phone,
+			IM:    c.IM,
+		})
+	}
+	return org
+}
+
+// convertOrgContacts 转换联系人，忽略没有姓名的条目
+func convertOrgContacts(contacts []types.OrgContact) []model.OrgContact {
+	result := make([]model.OrgContact, 0, len(contacts))
+	for _, c := range contacts {
+		name := strings.TrimSpace(c.Name)
+		if name == "" {
+			continue
+		}
+		result = append(result, model.OrgContact{
+			Name:  name,
+			Role:  strings.TrimSpace(c.Role),
+			Email: strings.TrimSpace(c.Email),
+			Phone: strings.TrimSpace(c.Phone),
+			IM:    strings.TrimSpace(c.IM),
+		})
+	}
+	return result
+}
+
 // OrganizationDeleteLogic 删除组织
 type OrganizationDeleteLogic struct {
 	logx.Logger
@@ -122,9 +198,21 @@ func (l *OrganizationDeleteLogic) OrganizationDelete(req *types.OrganizationDele
 		return &types.BaseResp{Code: 400, Msg: "ID不能为空"}, nil
 	}
 
+	children, err := l.svcCtx.OrganizationModel.CountChildren(l.ctx, req.Id)
+	if err != nil {
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	if children > 0 {
+		return &types.BaseResp{Code: 400, Msg: "请先删除或移动下级组织"}, nil
+	}
+
 	if err = l.svcCtx.OrganizationModel.Delete(l.ctx, req.Id); err != nil {
 		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
 	}
+	if _, err := l.svcCtx.OrgAttributionRuleModel.DeleteByOrgIds(l.ctx, []string{req.Id}); err != nil {
+		l.Errorf("删除组织归属规则失败: %v", err)
+	}
+	l.svcCtx.OrgAttribution.Invalidate()
 
 	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
 }
@@ -156,6 +244,114 @@ func (l *OrganizationUpdateStatusLogic) OrganizationUpdateStatus(req *types.Orga
 	if err != nil {
 		return &types.BaseResp{Code: 500, Msg: "更新状态失败"}, nil
 	}
+	l.svcCtx.OrgAttribution.Invalidate()
 
 	return &types.BaseResp{Code: 0, Msg: "状态更新成功"}, nil
 }
+
+// OrganizationDashboardLogic 组织看板
+type OrganizationDashboardLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewOrganizationDashboardLogic(ctx context.Context, svcCtx *svc.ServiceContext) *OrganizationDashboardLogic {
+	return &OrganizationDashboardLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// OrganizationDashboard 统计组织（默认含下级组织）在工作空间内的资产、潜在漏洞和已验证漏洞
+func (l *OrganizationDashboardLogic) OrganizationDashboard(req *types.OrgDashboardReq, workspaceId string) (*types.OrgDashboardResp, error) {
+	if req.Id == "" {
+		return &types.OrgDashboardResp{Code: 400, Msg: "ID不能为空"}, nil
+	}
+	org, err := l.svcCtx.OrganizationModel.FindById(l.ctx, req.Id)
+	if err != nil {
+		return &types.OrgDashboardResp{Code: 404, Msg: "组织不存在"}, nil
+	}
+
+	orgIds, err := l.svcCtx.AssetAggregation.OrgScope(l.ctx, req.Id, req.IncludeChildren)
+	if err != nil {
+		l.Errorf("查询组织范围失败: %v", err)
+		return &types.OrgDashboardResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	resp := &types.OrgDashboardResp{
+		Code:           0,
+		Msg:            "success",
+		Children:       []types.Organization{},
+		ByRiskLevel:    map[string]int64{},
+		ByService:      []types.StatItem{},
+		ByOrg:          []types.StatItem{},
+		PotentialVulns: map[string]int64{},
+		VulBySeverity:  map[string]int64{},
+	}
+	o := convertOrganization(org)
+	resp.Organization = &o
+
+	children, err := l.svcCtx.OrganizationModel.Find(l.ctx, bson.M{"parent_id": req.Id}, 0, 0)
+	if err == nil {
+		for i := range children {
+			resp.Children = append(resp.Children, convertOrganization(&children[i]))
+		}
+	}
+
+	services := make(map[string]int64)
+	byOrg := make(map[string]int64)
+	for _, wsId := range common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId) {
+		stats, err := l.svcCtx.AssetAggregation.GetOrgAssetStats(l.ctx, wsId, orgIds)
+		if err != nil {
+			l.Errorf("组织资产统计失败: workspace=%s, %v", wsId, err)
+			continue
+		}
+		resp.Total += stats.Total
+		resp.NewAssets += stats.NewAssets
+		resp.VulCount += stats.VulCount
+		resp.HighRiskVulCount += stats.HighRiskVulCount
+		mergeCounts(resp.ByRiskLevel, stats.ByRiskLevel)
+		mergeCounts(resp.PotentialVulns, stats.PotentialVulns)
+		mergeCounts(resp.VulBySeverity, stats.VulBySeverity)
+		mergeCounts(byOrg, stats.ByOrg)
+		for _, s := range stats.ByService {
+			services[s.Name] += s.Count
+		}
+	}
+
+	resp.ByService = sortedStatItems(services, 10)
+	orgMap := common.LoadOrgMap(l.ctx, l.svcCtx)
+	for _, item := range sortedStatItems(byOrg, 0) {
+		if name, ok := orgMap[item.Name]; ok {
+			item.Name = name
+		}
+		resp.ByOrg = append(resp.ByOrg, item)
+	}
+	return resp, nil
+}
+
+func mergeCounts(dst, src map[string]int64) {
+	for k, v := range src {
+		dst[k] += v
+	}
+}
+
+// sortedStatItems 按数量降序排列，limit 为 0 时不限制
+func sortedStatItems(counts map[string]int64, limit int) []types.StatItem {
+	items := make([]types.StatItem, 0, len(counts))
+	for name, count := range counts {
+		items = append(items, types.StatItem{Name: name, Count: int(count)})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Name < items[j].Name
+	})
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
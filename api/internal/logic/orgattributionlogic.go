@@ -0,0 +1,173 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cscan/api/internal/logic/common"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/orgattr"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// orgAttributionApplyPageSize 重新执行归属规则时每页处理的资产数量
+const orgAttributionApplyPageSize = 500
+
+// OrgAttributionLogic 资产归属规则管理
+type OrgAttributionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewOrgAttributionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *OrgAttributionLogic {
+	return &OrgAttributionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RuleList 归属规则列表，按优先级排序
+func (l *OrgAttributionLogic) RuleList(req *types.OrgAttributionRuleListReq) (*types.OrgAttributionRuleListResp, error) {
+	filter := bson.M{}
+	if req.OrgId != "" {
+		filter["org_id"] = req.OrgId
+	}
+	if req.Type != "" {
+		filter["type"] = req.Type
+	}
+
+	rules, err := l.svcCtx.OrgAttributionRuleModel.FindWithSort(l.ctx, filter, 0, 0, "priority", -1)
+	if err != nil {
+		l.Errorf("查询归属规则失败: %v", err)
+		return &types.OrgAttributionRuleListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	orgMap := common.LoadOrgMap(l.ctx, l.svcCtx)
+	list := make([]types.OrgAttributionRule, 0, len(rules))
+	for _, r := range rules {
+		list = append(list, types.OrgAttributionRule{
+			Id:          r.Id.Hex(),
+			OrgId:       r.OrgId,
+			OrgName:     orgMap[r.OrgId],
+			Type:        r.Type,
+			Pattern:     r.Pattern,
+			Priority:    r.Priority,
+			Enabled:     r.Enabled,
+			Description: r.Description,
+			CreateTime:  r.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+	return &types.OrgAttributionRuleListResp{Code: 0, Msg: "success", Total: len(list), List: list}, nil
+}
+
+// RuleSave 新增或更新归属规则
+func (l *OrgAttributionLogic) RuleSave(req *types.OrgAttributionRuleSaveReq) (*types.BaseResp, error) {
+	pattern := strings.TrimSpace(req.Pattern)
+	if err := orgattr.Validate(req.Type, pattern); err != nil {
+		return &types.BaseResp{Code: 400, Msg: "规则无效: " + err.Error()}, nil
+	}
+	if _, err := l.svcCtx.OrganizationModel.FindById(l.ctx, req.OrgId); err != nil {
+		return &types.BaseResp{Code: 400, Msg: "组织不存在"}, nil
+	}
+
+	if req.Id != "" {
+		err := l.svcCtx.OrgAttributionRuleModel.UpdateById(l.ctx, req.Id, bson.M{
+			"org_id":      req.OrgId,
+			"type":        req.Type,
+			"pattern":     pattern,
+			"priority":    req.Priority,
+			"enabled":     req.Enabled,
+			"description": req.Description,
+		})
+		if err != nil {
+			l.Errorf("更新归属规则失败: %v", err)
+			return &types.BaseResp{Code: 500, Msg: "更新失败"}, nil
+		}
+		l.svcCtx.OrgAttribution.Invalidate()
+		return &types.BaseResp{Code: 0, Msg: "更新成功"}, nil
+	}
+
+	rule := &model.OrgAttributionRule{
+		OrgId:       req.OrgId,
+		Type:        req.Type,
+		Pattern:     pattern,
+		Priority:    req.Priority,
+		Enabled:     req.Enabled,
+		Description: req.Description,
+	}
+	if err := l.svcCtx.OrgAttributionRuleModel.Insert(l.ctx, rule); err != nil {
+		l.Errorf("创建归属规则失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "创建失败"}, nil
+	}
+	l.svcCtx.OrgAttribution.Invalidate()
+	return &types.BaseResp{Code: 0, Msg: "创建成功"}, nil
+}
+
+// RuleDelete 删除归属规则，已归属的资产保持不变
+func (l *OrgAttributionLogic) RuleDelete(req *types.OrgAttributionRuleDeleteReq) (*types.BaseResp, error) {
+	if req.Id == "" {
+		return &types.BaseResp{Code: 400, Msg: "ID不能为空"}, nil
+	}
+	if err := l.svcCtx.OrgAttributionRuleModel.DeleteById(l.ctx, req.Id); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	l.svcCtx.OrgAttribution.Invalidate()
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
+// Apply 对工作空间内已有资产重新执行归属规则，未命中规则的资产保持不变
+func (l *OrgAttributionLogic) Apply(req *types.OrgAttributionApplyReq, workspaceId string) (*types.OrgAttributionApplyResp, error) {
+	if l.svcCtx.OrgAttribution.Matcher(l.ctx) == nil {
+		return &types.OrgAttributionApplyResp{Code: 400, Msg: "没有已启用的归属规则"}, nil
+	}
+
+	var scanned, updated int
+	for _, wsId := range common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId) {
+		assetModel := l.svcCtx.GetAssetModel(wsId)
+		// 按 _id 降序游标分页，更新 org_id 不影响后续页
+		pageFilter := bson.M{}
+		if !req.Overwrite {
+			pageFilter["org_id"] = bson.M{"$in": bson.A{nil, ""}}
+		}
+		for {
+			assets, err := assetModel.FindWithSort(l.ctx, pageFilter, 1, orgAttributionApplyPageSize, "_id")
+			if err != nil {
+				l.Errorf("查询资产失败: workspace=%s, %v", wsId, err)
+				break
+			}
+
+			for i := range assets {
+				a := &assets[i]
+				scanned++
+				orgId := l.svcCtx.OrgAttribution.Attribute(l.ctx, a)
+				if orgId == "" || orgId == a.OrgId {
+					continue
+				}
+				if err := assetModel.UpdateOrgId(l.ctx, a.Authority, a.Host, a.Port, a.Transport, orgId); err != nil {
+					l.Errorf("更新资产组织失败: %s, %v", a.Authority, err)
+					continue
+				}
+				updated++
+			}
+
+			if len(assets) < orgAttributionApplyPageSize {
+				break
+			}
+			pageFilter["_id"] = bson.M{"$lt": assets[len(assets)-1].Id}
+		}
+	}
+
+	return &types.OrgAttributionApplyResp{
+		Code:    0,
+		Msg:     fmt.Sprintf("已处理 %d 条资产，更新 %d 条", scanned, updated),
+		Scanned: scanned,
+		Updated: updated,
+	}, nil
+}
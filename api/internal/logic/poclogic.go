@@ -14,6 +14,7 @@ import (
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
+	"cscan/pkg/utils"
 	"cscan/rpc/task/pb"
 	"cscan/scanner"
 
@@ -1042,15 +1043,15 @@ func buildAssetUrl(asset *model.Asset, httpsPorts []int) string {
 		// 使用 host:port 构建
 		if useHttps {
 			if asset.Port == 443 {
-				url = fmt.Sprintf("https://%s", asset.Host)
+				url = fmt.Sprintf("https://%s", utils.BracketHost(asset.Host))
 			} else {
-				url = fmt.Sprintf("https://%s:%d", asset.Host, asset.Port)
+				url = fmt.Sprintf("https://%s", utils.BuildTargetWithPort(asset.Host, asset.Port))
 			}
 		} else {
 			if asset.Port == 80 {
-				url = fmt.Sprintf("http://%s", asset.Host)
+				url = fmt.Sprintf("http://%s", utils.BracketHost(asset.Host))
 			} else {
-				url = fmt.Sprintf("http://%s:%d", asset.Host, asset.Port)
+				url = fmt.Sprintf("http://%s", utils.BuildTargetWithPort(asset.Host, asset.Port))
 			}
 		}
 	}
@@ -1450,7 +1451,7 @@ func (l *NucleiTemplateDownloadLogic) downloadTemplatesWithProgress(taskId strin
 	
 	// 如果 SDK 失败，尝试使用 git clone
 	if downloadErr != nil {
-		logx.Error("[Nuclei Templates] SDK failed: %v, trying git clone...", downloadErr)
+		logx.Errorf("[Nuclei Templates] SDK failed: %v, trying git clone...", downloadErr)
 		updateDownloadStatus(taskId, "downloading", 25, 0, "")
 		
 		// 清理可能的部分下载
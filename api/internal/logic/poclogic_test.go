@@ -0,0 +1,26 @@
+package logic
+
+import (
+	"testing"
+
+	"cscan/model"
+)
+
+func TestBuildAssetUrlIPv6(t *testing.T) {
+	httpsPorts := []int{443, 8443}
+	cases := []struct {
+		asset *model.Asset
+		want  string
+	}{
+		{&model.Asset{Host: "2001:db8::1", Port: 443}, "https://[2001:db8::1]"},
+		{&model.Asset{Host: "2001:db8::1", Port: 80}, "http://[2001:db8::1]"},
+		{&model.Asset{Host: "2001:db8::1", Port: 8080}, "http://[2001:db8::1]:8080"},
+		{&model.Asset{Authority: "[2001:db8::1]:8443", Host: "2001:db8::1", Port: 8443}, "https://[2001:db8::1]:8443"},
+		{&model.Asset{Host: "example.com", Port: 443}, "https://example.com"},
+	}
+	for _, c := range cases {
+		if got := buildAssetUrl(c.asset, httpsPorts); got != c.want {
+			t.Errorf("buildAssetUrl(%s:%d) = %q, want %q", c.asset.Host, c.asset.Port, got, c.want)
+		}
+	}
+}
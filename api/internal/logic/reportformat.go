@@ -0,0 +1,643 @@
+package logic
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+	"time"
+
+	"cscan/model"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 报告导出格式
+const (
+	ReportFormatExcel = "xlsx"
+	ReportFormatSARIF = "sarif"
+	ReportFormatJSON  = "json"
+	ReportFormatJSONL = "jsonl"
+	ReportFormatCSV   = "csv"
+	ReportFormatHTML  = "html"
+)
+
+const reportTimeLayout = "2006-01-02 15:04:05"
+
+// normalizeReportFormat 规范化导出格式，不支持的格式返回空
+func normalizeReportFormat(format string) string {
+	switch f := strings.ToLower(strings.TrimSpace(format)); f {
+	case "", "excel", ReportFormatExcel:
+		return ReportFormatExcel
+	case "ndjson":
+		return ReportFormatJSONL
+	case ReportFormatSARIF, ReportFormatJSON, ReportFormatJSONL, ReportFormatCSV, ReportFormatHTML:
+		return f
+	}
+	return ""
+}
+
+// reportDataset 报告数据，各导出格式共用
+type reportDataset struct {
+	TaskId       string
+	Name         string
+	Target       string // 任务目标，范围导出时为范围描述
+	Status       string
+	CreateTime   time.Time // 任务创建时间，范围导出时为空
+	GenerateTime time.Time
+	Assets       []model.Asset
+	Vuls         []model.Vul
+	DirScans     []model.DirScanResult
+}
+
+func (ds *reportDataset) vulStats() map[string]int {
+	stats := map[string]int{"critical": 0, "high": 0, "medium": 0, "low": 0, "info": 0, "unknown": 0}
+	for _, v := range ds.Vuls {
+		if _, ok := stats[strings.ToLower(v.Severity)]; ok {
+			stats[strings.ToLower(v.Severity)]++
+		}
+	}
+	return stats
+}
+
+// reportTable 表格形式的数据，Excel 的工作表与 CSV 文件一一对应
+type reportTable struct {
+	Key     string // csv 文件名及 sheet 参数
+	Sheet   string // Excel 工作表名
+	Headers []string
+	Widths  map[string]float64
+	Rows    [][]interface{}
+}
+
+func (ds *reportDataset) tables() []reportTable {
+	assets := reportTable{
+		Key:     "asset",
+		Sheet:   "资产列表",
+		Headers: []string{"地址", "主机", "端口", "服务", "标题", "应用", "状态码", "Server", "IconHash", "发现时间"},
+		Widths:  map[string]float64{"A": 30, "B": 15, "E": 40, "F": 30},
+		Rows:    make([][]interface{}, 0, len(ds.Assets)),
+	}
+	for _, a := range ds.Assets {
+		assets.Rows = append(assets.Rows, []interface{}{
+			a.Authority, a.Host, a.Port, a.Service, a.Title, strings.Join(a.App, ", "),
+			a.HttpStatus, a.Server, a.IconHash, a.CreateTime.Local().Format(reportTimeLayout),
+		})
+	}
+
+	vuls := reportTable{
+		Key:     "vul",
+		Sheet:   "漏洞列表",
+		Headers: []string{"地址", "URL", "POC", "漏洞名称", "严重级别", "处置状态", "结果", "发现时间"},
+		Widths:  map[string]float64{"A": 30, "B": 50, "C": 40, "D": 30, "G": 50},
+		Rows:    make([][]interface{}, 0, len(ds.Vuls)),
+	}
+	for _, v := range ds.Vuls {
+		status := v.Status
+		if status == "" {
+			status = model.VulStatusNew
+		}
+		vuls.Rows = append(vuls.Rows, []interface{}{
+			v.Authority, v.Url, v.PocFile, v.VulName, v.Severity, status, v.Result,
+			v.CreateTime.Local().Format(reportTimeLayout),
+		})
+	}
+
+	dirScans := reportTable{
+		Key:     "dirscan",
+		Sheet:   "目录扫描",
+		Headers: []string{"目标", "URL", "路径", "状态码", "大小", "类型", "标题", "发现时间"},
+		Widths:  map[string]float64{"A": 25, "B": 50, "C": 30, "G": 30},
+		Rows:    make([][]interface{}, 0, len(ds.DirScans)),
+	}
+	for _, d := range ds.DirScans {
+		dirScans.Rows = append(dirScans.Rows, []interface{}{
+			d.Authority, d.URL, d.Path, d.StatusCode, d.ContentLength, d.ContentType, d.Title,
+			d.CreateTime.Local().Format(reportTimeLayout),
+		})
+	}
+
+	return []reportTable{assets, vuls, dirScans}
+}
+
+// renderReport 按格式生成报告文件
+func renderReport(ds *reportDataset, format, sheet string) (*ReportFile, error) {
+	base := fmt.Sprintf("report_%s_%s", ds.Name, ds.GenerateTime.Format("20060102150405"))
+
+	var (
+		data []byte
+		err  error
+	)
+	switch format {
+	case ReportFormatExcel:
+		data, err = renderReportExcel(ds)
+		return &ReportFile{Data: data, Filename: base + ".xlsx", ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}, err
+	case ReportFormatSARIF:
+		data, err = renderReportSARIF(ds)
+		return &ReportFile{Data: data, Filename: base + ".sarif", ContentType: "application/sarif+json"}, err
+	case ReportFormatJSON:
+		data, err = renderReportJSON(ds)
+		return &ReportFile{Data: data, Filename: base + ".json", ContentType: "application/json"}, err
+	case ReportFormatJSONL:
+		data, err = renderReportJSONL(ds)
+		return &ReportFile{Data: data, Filename: base + ".jsonl", ContentType: "application/x-ndjson"}, err
+	case ReportFormatCSV:
+		return renderReportCSV(ds, base, sheet)
+	case ReportFormatHTML:
+		data, err = renderReportHTML(ds)
+		return &ReportFile{Data: data, Filename: base + ".html", ContentType: "text/html; charset=utf-8"}, err
+	}
+	return nil, fmt.Errorf("不支持的导出格式: %s", format)
+}
+
+// ==================== Excel ====================
+
+func renderReportExcel(ds *reportDataset) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// 概览Sheet
+	f.SetSheetName("Sheet1", "概览")
+	f.SetCellValue("概览", "A1", "扫描报告")
+	overview := [][2]interface{}{
+		{"任务名称", ds.Name},
+		{"扫描目标", ds.Target},
+		{"任务状态", ds.Status},
+		{"创建时间", formatReportTime(ds.CreateTime)},
+		{"资产数量", len(ds.Assets)},
+		{"漏洞数量", len(ds.Vuls)},
+		{"目录扫描数量", len(ds.DirScans)},
+	}
+	if ds.TaskId == "" {
+		overview[0][0], overview[1][0] = "报告名称", "导出范围"
+		overview = append(overview[:2], overview[4:]...)
+	}
+	for i, item := range overview {
+		f.SetCellValue("概览", fmt.Sprintf("A%d", i+3), item[0])
+		f.SetCellValue("概览", fmt.Sprintf("B%d", i+3), item[1])
+	}
+
+	// 设置概览样式
+	titleStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Size: 16},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	})
+	f.SetCellStyle("概览", "A1", "A1", titleStyle)
+	f.MergeCell("概览", "A1", "B1")
+
+	for _, t := range ds.tables() {
+		f.NewSheet(t.Sheet)
+		header := make([]interface{}, len(t.Headers))
+		for i, h := range t.Headers {
+			header[i] = h
+		}
+		f.SetSheetRow(t.Sheet, "A1", &header)
+		for i, row := range t.Rows {
+			row := row
+			f.SetSheetRow(t.Sheet, fmt.Sprintf("A%d", i+2), &row)
+		}
+		for col, width := range t.Widths {
+			f.SetColWidth(t.Sheet, col, col, width)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ==================== CSV ====================
+
+// renderReportCSV 指定 sheet 时导出单个CSV，否则将每个表打包为 zip
+func renderReportCSV(ds *reportDataset, base, sheet string) (*ReportFile, error) {
+	tables := ds.tables()
+	if sheet != "" {
+		for _, t := range tables {
+			if t.Key == sheet {
+				data, err := writeReportCSV(t)
+				return &ReportFile{Data: data, Filename: base + "_" + t.Key + ".csv", ContentType: "text/csv; charset=utf-8"}, err
+			}
+		}
+		return nil, fmt.Errorf("不支持的sheet: %s", sheet)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, t := range tables {
+		data, err := writeReportCSV(t)
+		if err != nil {
+			return nil, err
+		}
+		w, err := zw.Create(t.Key + ".csv")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &ReportFile{Data: buf.Bytes(), Filename: base + "_csv.zip", ContentType: "application/zip"}, nil
+}
+
+func writeReportCSV(t reportTable) ([]byte, error) {
+	var buf bytes.Buffer
+	// UTF-8 BOM，避免 Excel 打开中文乱码
+	buf.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.Headers); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(t.Headers))
+	for _, row := range t.Rows {
+		for i, v := range row {
+			record[i] = fmt.Sprint(v)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// ==================== JSON / JSONL ====================
+
+type reportSummary struct {
+	TaskId       string         `json:"taskId,omitempty"`
+	Name         string         `json:"name"`
+	Target       string         `json:"target"`
+	Status       string         `json:"status,omitempty"`
+	CreateTime   string         `json:"createTime,omitempty"`
+	GenerateTime string         `json:"generateTime"`
+	AssetCount   int            `json:"assetCount"`
+	VulCount     int            `json:"vulCount"`
+	DirScanCount int            `json:"dirScanCount"`
+	VulStats     map[string]int `json:"vulStats"`
+}
+
+func (ds *reportDataset) summary() reportSummary {
+	return reportSummary{
+		TaskId:       ds.TaskId,
+		Name:         ds.Name,
+		Target:       ds.Target,
+		Status:       ds.Status,
+		CreateTime:   formatReportTime(ds.CreateTime),
+		GenerateTime: ds.GenerateTime.Format(time.RFC3339),
+		AssetCount:   len(ds.Assets),
+		VulCount:     len(ds.Vuls),
+		DirScanCount: len(ds.DirScans),
+		VulStats:     ds.vulStats(),
+	}
+}
+
+// exportAssets 去掉截图，机器可读格式不携带图片
+func (ds *reportDataset) exportAssets() []model.Asset {
+	assets := make([]model.Asset, len(ds.Assets))
+	copy(assets, ds.Assets)
+	for i := range assets {
+		assets[i].Screenshot = ""
+	}
+	return assets
+}
+
+func renderReportJSON(ds *reportDataset) ([]byte, error) {
+	doc := struct {
+		reportSummary
+		Assets   []model.Asset         `json:"assets"`
+		Vuls     []model.Vul           `json:"vuls"`
+		DirScans []model.DirScanResult `json:"dirScans"`
+	}{ds.summary(), ds.exportAssets(), ds.Vuls, ds.DirScans}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// reportRecord JSONL 中的一行，type 为 summary/asset/vul/dirscan
+type reportRecord struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+func renderReportJSONL(ds *reportDataset) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(reportRecord{Type: "summary", Data: ds.summary()}); err != nil {
+		return nil, err
+	}
+	for _, a := range ds.exportAssets() {
+		if err := enc.Encode(reportRecord{Type: "asset", Data: a}); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range ds.Vuls {
+		if err := enc.Encode(reportRecord{Type: "vul", Data: v}); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range ds.DirScans {
+		if err := enc.Encode(reportRecord{Type: "dirscan", Data: d}); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// ==================== SARIF 2.1.0 ====================
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	Id               string                 `json:"id"`
+	Name             string                 `json:"name,omitempty"`
+	ShortDescription *sarifMessage          `json:"shortDescription,omitempty"`
+	Help             *sarifMessage          `json:"help,omitempty"`
+	HelpUri          string                 `json:"helpUri,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifResult struct {
+	RuleId              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifSecuritySeverity 严重级别对应的 security-severity 分值（无CVSS时使用）
+var sarifSecuritySeverity = map[string]float64{
+	"critical": 9.5,
+	"high":     8.0,
+	"medium":   5.5,
+	"low":      2.0,
+}
+
+func sarifLevel(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	}
+	return "note"
+}
+
+func renderReportSARIF(ds *reportDataset) ([]byte, error) {
+	rules := make([]sarifRule, 0)
+	ruleIndex := make(map[string]int)
+	results := make([]sarifResult, 0, len(ds.Vuls))
+
+	for _, v := range ds.Vuls {
+		ruleId := v.PocFile
+		if ruleId == "" {
+			ruleId = v.VulName
+		}
+		if ruleId == "" {
+			ruleId = "unknown"
+		}
+		name := v.VulName
+		if name == "" {
+			name = ruleId
+		}
+
+		idx, ok := ruleIndex[ruleId]
+		if !ok {
+			score := sarifSecuritySeverity[strings.ToLower(v.Severity)]
+			if v.CvssScore > 0 {
+				score = v.CvssScore
+			}
+			rule := sarifRule{
+				Id:               ruleId,
+				Name:             name,
+				ShortDescription: &sarifMessage{Text: name},
+				Properties: map[string]interface{}{
+					"security-severity": fmt.Sprintf("%.1f", score),
+					"tags":              append([]string{"security"}, v.Tags...),
+				},
+			}
+			if v.Remediation != "" {
+				rule.Help = &sarifMessage{Text: v.Remediation}
+			}
+			if len(v.References) > 0 {
+				rule.HelpUri = v.References[0]
+			}
+			idx = len(rules)
+			ruleIndex[ruleId] = idx
+			rules = append(rules, rule)
+		}
+
+		uri := v.Url
+		if uri == "" {
+			uri = v.Authority
+		}
+		fp := sha1.Sum([]byte(v.Authority + "|" + v.PocFile))
+
+		props := map[string]interface{}{
+			"severity": v.Severity,
+			"host":     v.Host,
+			"port":     v.Port,
+		}
+		if v.Status != "" {
+			props["status"] = v.Status
+		}
+		if v.Assignee != "" {
+			props["assignee"] = v.Assignee
+		}
+		if v.CveId != "" {
+			props["cve"] = v.CveId
+		}
+		if v.CvssScore > 0 {
+			props["cvss"] = v.CvssScore
+		}
+
+		results = append(results, sarifResult{
+			RuleId:    ruleId,
+			RuleIndex: idx,
+			Level:     sarifLevel(v.Severity),
+			Message:   sarifMessage{Text: fmt.Sprintf("%s: %s", name, uri)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}},
+			}},
+			PartialFingerprints: map[string]string{"cscanVul/v1": hex.EncodeToString(fp[:])},
+			Properties:          props,
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "cscan", Rules: rules}},
+			Results: results,
+		}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+// ==================== HTML ====================
+
+var screenshotBase64Re = regexp.MustCompile(`^[A-Za-z0-9+/]+=*$`)
+
+// screenshotDataURL 将资产截图转换为可内嵌的 data URL，非法内容返回空
+func screenshotDataURL(screenshot string) template.URL {
+	if screenshot == "" {
+		return ""
+	}
+	if strings.HasPrefix(screenshot, "data:image/") {
+		if i := strings.Index(screenshot, ";base64,"); i > 0 && screenshotBase64Re.MatchString(screenshot[i+8:]) {
+			return template.URL(screenshot)
+		}
+		return ""
+	}
+	if !screenshotBase64Re.MatchString(screenshot) {
+		return ""
+	}
+	return template.URL("data:image/png;base64," + screenshot)
+}
+
+func formatReportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(reportTimeLayout)
+}
+
+var reportHTMLTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"screenshot": screenshotDataURL,
+	"timefmt":    formatReportTime,
+	"join":       strings.Join,
+	"lower":      strings.ToLower,
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>扫描报告 - {{.Summary.Name}}</title>
+<style>
+body{font-family:-apple-system,"Segoe UI","PingFang SC","Microsoft YaHei",sans-serif;margin:24px;color:#303133;font-size:14px}
+h1{font-size:22px}h2{font-size:18px;margin-top:32px;border-bottom:1px solid #dcdfe6;padding-bottom:6px}
+table{border-collapse:collapse;width:100%;margin-top:8px}
+th,td{border:1px solid #ebeef5;padding:6px 8px;text-align:left;vertical-align:top;word-break:break-all}
+th{background:#f5f7fa}
+.overview td:first-child{width:160px;background:#fafafa}
+.sev{display:inline-block;padding:0 6px;border-radius:3px;color:#fff;background:#909399}
+.sev-critical{background:#a8071a}.sev-high{background:#f56c6c}.sev-medium{background:#e6a23c}.sev-low{background:#409eff}
+.shot{max-width:320px;max-height:200px;border:1px solid #ebeef5}
+pre{white-space:pre-wrap;margin:0;max-height:200px;overflow:auto}
+</style>
+</head>
+<body>
+<h1>扫描报告</h1>
+<table class="overview">
+<tr><td>名称</td><td>{{.Summary.Name}}</td></tr>
+<tr><td>{{if .Summary.TaskId}}扫描目标{{else}}导出范围{{end}}</td><td>{{.Summary.Target}}</td></tr>
+{{if .Summary.TaskId}}<tr><td>任务状态</td><td>{{.Summary.Status}}</td></tr>
+<tr><td>创建时间</td><td>{{.Summary.CreateTime}}</td></tr>{{end}}
+<tr><td>生成时间</td><td>{{timefmt .GenerateTime}}</td></tr>
+<tr><td>资产数量</td><td>{{.Summary.AssetCount}}</td></tr>
+<tr><td>漏洞数量</td><td>{{.Summary.VulCount}}（严重 {{index .Summary.VulStats "critical"}} / 高危 {{index .Summary.VulStats "high"}} / 中危 {{index .Summary.VulStats "medium"}} / 低危 {{index .Summary.VulStats "low"}} / 信息 {{index .Summary.VulStats "info"}}）</td></tr>
+<tr><td>目录扫描数量</td><td>{{.Summary.DirScanCount}}</td></tr>
+</table>
+
+<h2>漏洞列表</h2>
+{{if .Vuls}}<table>
+<tr><th>严重级别</th><th>漏洞</th><th>地址</th><th>处置状态</th><th>结果</th><th>发现时间</th></tr>
+{{range .Vuls}}<tr>
+<td><span class="sev sev-{{lower .Severity}}">{{.Severity}}</span></td>
+<td>{{if .VulName}}{{.VulName}}<br>{{end}}<small>{{.PocFile}}</small></td>
+<td>{{if .Url}}{{.Url}}{{else}}{{.Authority}}{{end}}</td>
+<td>{{or .Status "new"}}</td>
+<td><pre>{{.Result}}</pre></td>
+<td>{{timefmt .CreateTime}}</td>
+</tr>{{end}}
+</table>{{else}}<p>无</p>{{end}}
+
+<h2>资产列表</h2>
+{{if .Assets}}<table>
+<tr><th>地址</th><th>服务</th><th>标题</th><th>应用</th><th>状态码</th><th>截图</th><th>发现时间</th></tr>
+{{range .Assets}}<tr>
+<td>{{.Authority}}</td>
+<td>{{.Service}}</td>
+<td>{{.Title}}</td>
+<td>{{join .App ", "}}</td>
+<td>{{.HttpStatus}}</td>
+<td>{{with screenshot .Screenshot}}<img class="shot" src="{{.}}" alt="screenshot">{{end}}</td>
+<td>{{timefmt .CreateTime}}</td>
+</tr>{{end}}
+</table>{{else}}<p>无</p>{{end}}
+
+<h2>目录扫描</h2>
+{{if .DirScans}}<table>
+<tr><th>URL</th><th>状态码</th><th>大小</th><th>类型</th><th>标题</th><th>发现时间</th></tr>
+{{range .DirScans}}<tr>
+<td>{{.URL}}</td>
+<td>{{.StatusCode}}</td>
+<td>{{.ContentLength}}</td>
+<td>{{.ContentType}}</td>
+<td>{{.Title}}</td>
+<td>{{timefmt .CreateTime}}</td>
+</tr>{{end}}
+</table>{{else}}<p>无</p>{{end}}
+</body>
+</html>
+`))
+
+// renderReportHTML 生成自包含的HTML报告，截图以 data URL 内嵌
+func renderReportHTML(ds *reportDataset) ([]byte, error) {
+	var buf bytes.Buffer
+	err := reportHTMLTemplate.Execute(&buf, map[string]interface{}{
+		"Summary":      ds.summary(),
+		"GenerateTime": ds.GenerateTime,
+		"Assets":       ds.Assets,
+		"Vuls":         ds.Vuls,
+		"DirScans":     ds.DirScans,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
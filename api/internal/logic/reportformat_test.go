@@ -0,0 +1,306 @@
+package logic
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cscan/model"
+)
+
+func reportTestDataset() *reportDataset {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	return &reportDataset{
+		Name:         "t",
+		GenerateTime: created,
+		Assets: []model.Asset{
+			{Authority: "a.example.com:443", Host: "a.example.com", Port: 443, Service: "https", Title: "A, \"quoted\"", App: []string{"nginx", "php"}, HttpStatus: "200", CreateTime: created},
+		},
+		Vuls: []model.Vul{
+			{Authority: "a.example.com:443", Host: "a.example.com", Port: 443, Url: "https://a.example.com/login", PocFile: "weak-login", VulName: "Weak Login", Severity: "high", Tags: []string{"auth"}, Remediation: "Change the password", References: []string{"https://example.com/weak-login"}, Status: model.VulStatusConfirmed, CreateTime: created},
+			{Authority: "b.example.com:80", Host: "b.example.com", Port: 80, PocFile: "weak-login", VulName: "Weak Login", Severity: "high", CreateTime: created},
+			{Authority: "c.example.com:80", Host: "c.example.com", Port: 80, VulName: "Exposed Panel", Severity: "medium", CveId: "CVE-2024-0001", CvssScore: 6.1, CreateTime: created},
+			{Authority: "d.example.com:80", Host: "d.example.com", Port: 80, Severity: "info", CreateTime: created},
+		},
+		DirScans: []model.DirScanResult{
+			{Authority: "a.example.com:443", URL: "https://a.example.com/admin", Path: "/admin", StatusCode: 403, ContentLength: 120, ContentType: "text/html", Title: "Forbidden", CreateTime: created},
+		},
+	}
+}
+
+// sarifGolden 与 reportTestDataset 对应的 SARIF 输出
+// 同一POC的漏洞共用一条规则，无POC时以漏洞名称作为规则ID，两者都为空时归入 unknown
+const sarifGolden = `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "cscan",
+          "rules": [
+            {
+              "id": "weak-login",
+              "name": "Weak Login",
+              "shortDescription": {
+                "text": "Weak Login"
+              },
+              "help": {
+                "text": "Change the password"
+              },
+              "helpUri": "https://example.com/weak-login",
+              "properties": {
+                "security-severity": "8.0",
+                "tags": [
+                  "security",
+                  "auth"
+                ]
+              }
+            },
+            {
+              "id": "Exposed Panel",
+              "name": "Exposed Panel",
+              "shortDescription": {
+                "text": "Exposed Panel"
+              },
+              "properties": {
+                "security-severity": "6.1",
+                "tags": [
+                  "security"
+                ]
+              }
+            },
+            {
+              "id": "unknown",
+              "name": "unknown",
+              "shortDescription": {
+                "text": "unknown"
+              },
+              "properties": {
+                "security-severity": "0.0",
+                "tags": [
+                  "security"
+                ]
+              }
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "weak-login",
+          "ruleIndex": 0,
+          "level": "error",
+          "message": {
+            "text": "Weak Login: https://a.example.com/login"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "https://a.example.com/login"
+                }
+              }
+            }
+          ],
+          "partialFingerprints": {
+            "cscanVul/v1": "54413470c9e09743b1aedeff733723c3af561c2c"
+          },
+          "properties": {
+            "host": "a.example.com",
+            "port": 443,
+            "severity": "high",
+            "status": "confirmed"
+          }
+        },
+        {
+          "ruleId": "weak-login",
+          "ruleIndex": 0,
+          "level": "error",
+          "message": {
+            "text": "Weak Login: b.example.com:80"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "b.example.com:80"
+                }
+              }
+            }
+          ],
+          "partialFingerprints": {
+            "cscanVul/v1": "a56bf620def54154eb7154da84fcc5eebfff026e"
+          },
+          "properties": {
+            "host": "b.example.com",
+            "port": 80,
+            "severity": "high"
+          }
+        },
+        {
+          "ruleId": "Exposed Panel",
+          "ruleIndex": 1,
+          "level": "warning",
+          "message": {
+            "text": "Exposed Panel: c.example.com:80"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "c.example.com:80"
+                }
+              }
+            }
+          ],
+          "partialFingerprints": {
+            "cscanVul/v1": "4715d33c06df41efd279a63c26727b1c7efb3667"
+          },
+          "properties": {
+            "cve": "CVE-2024-0001",
+            "cvss": 6.1,
+            "host": "c.example.com",
+            "port": 80,
+            "severity": "medium"
+          }
+        },
+        {
+          "ruleId": "unknown",
+          "ruleIndex": 2,
+          "level": "note",
+          "message": {
+            "text": "unknown: d.example.com:80"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "d.example.com:80"
+                }
+              }
+            }
+          ],
+          "partialFingerprints": {
+            "cscanVul/v1": "52a7281d5360cf78056ca4bb0f83dcc5bd547630"
+          },
+          "properties": {
+            "host": "d.example.com",
+            "port": 80,
+            "severity": "info"
+          }
+        }
+      ]
+    }
+  ]
+}`
+
+func TestRenderReportSARIF(t *testing.T) {
+	data, err := renderReportSARIF(reportTestDataset())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(sarifGolden), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SARIF output does not match golden structure, got:\n%s", data)
+	}
+}
+
+// readReportCSV 解析CSV，要求以 UTF-8 BOM 开头
+func readReportCSV(t *testing.T, data []byte) [][]string {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("\xEF\xBB\xBF")) {
+		t.Fatal("CSV must start with a UTF-8 BOM")
+	}
+	records, err := csv.NewReader(bytes.NewReader(data[3:])).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestRenderReportCSVSheets(t *testing.T) {
+	ds := reportTestDataset()
+	created := ds.GenerateTime.Format(reportTimeLayout)
+	tests := []struct {
+		sheet string
+		want  [][]string
+	}{
+		{"asset", [][]string{
+			{"地址", "主机", "端口", "服务", "标题", "应用", "状态码", "Server", "IconHash", "发现时间"},
+			{"a.example.com:443", "a.example.com", "443", "https", "A, \"quoted\"", "nginx, php", "200", "", "", created},
+		}},
+		{"vul", [][]string{
+			{"地址", "URL", "POC", "漏洞名称", "严重级别", "处置状态", "结果", "发现时间"},
+			{"a.example.com:443", "https://a.example.com/login", "weak-login", "Weak Login", "high", "confirmed", "", created},
+			{"b.example.com:80", "", "weak-login", "Weak Login", "high", "new", "", created},
+			{"c.example.com:80", "", "", "Exposed Panel", "medium", "new", "", created},
+			{"d.example.com:80", "", "", "", "info", "new", "", created},
+		}},
+		{"dirscan", [][]string{
+			{"目标", "URL", "路径", "状态码", "大小", "类型", "标题", "发现时间"},
+			{"a.example.com:443", "https://a.example.com/admin", "/admin", "403", "120", "text/html", "Forbidden", created},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sheet, func(t *testing.T) {
+			f, err := renderReport(ds, ReportFormatCSV, tt.sheet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "report_t_20240102030405_" + tt.sheet + ".csv"; f.Filename != want {
+				t.Errorf("Filename = %q, want %q", f.Filename, want)
+			}
+			if !strings.HasPrefix(f.ContentType, "text/csv") {
+				t.Errorf("ContentType = %q", f.ContentType)
+			}
+			if got := readReportCSV(t, f.Data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := renderReport(ds, ReportFormatCSV, "missing"); err == nil {
+		t.Error("unknown sheet should be rejected")
+	}
+}
+
+func TestRenderReportCSVZip(t *testing.T) {
+	f, err := renderReport(reportTestDataset(), ReportFormatCSV, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Filename != "report_t_20240102030405_csv.zip" || f.ContentType != "application/zip" {
+		t.Errorf("got %s (%s), want the zip of all sheets", f.Filename, f.ContentType)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(f.Data), int64(len(f.Data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := map[string]int{}
+	for _, zf := range zr.File {
+		rc, err := zf.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		rows[zf.Name] = len(readReportCSV(t, data)) - 1
+	}
+	if want := map[string]int{"asset.csv": 1, "vul.csv": 4, "dirscan.csv": 1}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("data rows per file = %v, want %v", rows, want)
+	}
+}
@@ -1,17 +1,17 @@
 package logic
 
 import (
-	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"strings"
 	"time"
 
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
 
-	"github.com/xuri/excelize/v2"
 	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -267,193 +267,268 @@ func NewReportExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Repo
 	}
 }
 
-func (l *ReportExportLogic) ReportExport(req *types.ReportExportReq, workspaceId string) ([]byte, string, error) {
-	// 获取任务信息
-	// 当 workspaceId 为 "all" 或空时，需要遍历所有工作空间查找任务
-	var task *model.MainTask
-	var err error
-	var actualWorkspaceId string
-	
-	if workspaceId == "" || workspaceId == "all" {
-		// 获取所有工作空间
-		wsModel := model.NewWorkspaceModel(l.svcCtx.MongoDB)
-		workspaces, _ := wsModel.FindAll(l.ctx)
-		
-		// 先尝试 default 工作空间
-		wsIds := []string{"default"}
-		for _, ws := range workspaces {
-			wsIds = append(wsIds, ws.Id.Hex())
+// ReportFile 导出的报告文件
+type ReportFile struct {
+	Data        []byte
+	Filename    string
+	ContentType string
+}
+
+// ReportExport 按任务或范围（当前工作空间/组织/时间）导出报告
+func (l *ReportExportLogic) ReportExport(req *types.ReportExportReq, workspaceId string) (*ReportFile, error) {
+	format := normalizeReportFormat(req.Format)
+	if format == "" {
+		return nil, fmt.Errorf("不支持的导出格式: %s", req.Format)
+	}
+
+	ds, err := l.loadDataset(req, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if format == ReportFormatHTML {
+		l.embedScreenshots(ds)
+	}
+	return renderReport(ds, format, req.Sheet)
+}
+
+// embedScreenshots 将资产中的截图存储键替换为图片内容的 data URL，HTML 报告内嵌截图后可离线查看
+// 读取失败的截图不内嵌
+func (l *ReportExportLogic) embedScreenshots(ds *reportDataset) {
+	if l.svcCtx.Screenshots == nil {
+		return
+	}
+	for i := range ds.Assets {
+		key := ds.Assets[i].Screenshot
+		if !svc.IsScreenshotKey(key) {
+			continue
 		}
-		
-		// 遍历所有工作空间查找任务
-		for _, wsId := range wsIds {
-			taskModel := l.svcCtx.GetMainTaskModel(wsId)
-			task, err = taskModel.FindById(l.ctx, req.TaskId)
-			if err == nil && task != nil {
-				actualWorkspaceId = wsId
-				break
-			}
+		data, contentType, err := l.svcCtx.Screenshots.Open(l.ctx, key)
+		if err != nil {
+			l.Errorf("[ReportExport] load screenshot %s failed: %v", key, err)
+			ds.Assets[i].Screenshot = ""
+			continue
 		}
-		
+		ds.Assets[i].Screenshot = "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data)
+	}
+}
+
+// loadDataset 查询导出所需的资产、漏洞和目录扫描结果
+func (l *ReportExportLogic) loadDataset(req *types.ReportExportReq, workspaceId string) (*reportDataset, error) {
+	ds := &reportDataset{
+		Assets:       []model.Asset{},
+		Vuls:         []model.Vul{},
+		DirScans:     []model.DirScanResult{},
+		GenerateTime: time.Now(),
+	}
+
+	// 时间范围，按发现时间过滤
+	timeFilter := bson.M{}
+	if req.StartTime != "" {
+		t, err := parseReportTime(req.StartTime, false)
+		if err != nil {
+			return nil, fmt.Errorf("开始时间格式错误: %s", req.StartTime)
+		}
+		timeFilter["$gte"] = t
+	}
+	if req.EndTime != "" {
+		t, err := parseReportTime(req.EndTime, true)
+		if err != nil {
+			return nil, fmt.Errorf("结束时间格式错误: %s", req.EndTime)
+		}
+		timeFilter["$lte"] = t
+	}
+
+	// 按组织导出时包含全部下级组织
+	var orgIds []string
+	if req.OrgId != "" {
+		ids, err := l.svcCtx.AssetAggregation.OrgScope(l.ctx, req.OrgId, true)
+		if err != nil {
+			return nil, fmt.Errorf("查询组织失败: %v", err)
+		}
+		orgIds = ids
+	}
+
+	var wsIds []string
+	var task *model.MainTask
+	if req.TaskId != "" {
+		var wsId string
+		task, wsId = l.findTask(req.TaskId, workspaceId)
 		if task == nil {
-			return nil, "", fmt.Errorf("任务不存在")
+			return nil, fmt.Errorf("任务不存在")
 		}
+		wsIds = []string{wsId}
+		ds.TaskId = req.TaskId
+		ds.Name = task.Name
+		ds.Target = task.Target
+		ds.Status = task.Status
+		ds.CreateTime = task.CreateTime
 	} else {
-		actualWorkspaceId = workspaceId
-		taskModel := l.svcCtx.GetMainTaskModel(workspaceId)
-		task, err = taskModel.FindById(l.ctx, req.TaskId)
+		wsIds = common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId)
+		ds.Name, ds.Target = l.describeScope(req, workspaceId)
+	}
+
+	for _, wsId := range wsIds {
+		// 资产保存时使用的是 task.Id.Hex() (ObjectID) 作为 taskId，同时兼容UUID和子任务ID
+		assetFilter := bson.M{}
+		vulFilter := bson.M{}
+		dirScanFilter := bson.M{}
+		if task != nil {
+			assetFilter = reportTaskFilter("taskId", task)
+			vulFilter = reportTaskFilter("task_id", task)
+			// 目录扫描结果已经通过 main_task_id 关联到任务，不限制 workspace_id
+			dirScanFilter = reportTaskFilter("main_task_id", task)
+		} else {
+			dirScanFilter["workspace_id"] = wsId
+		}
+		if len(timeFilter) > 0 {
+			assetFilter["create_time"] = timeFilter
+			vulFilter["create_time"] = timeFilter
+			dirScanFilter["create_time"] = timeFilter
+		}
+		if len(orgIds) > 0 {
+			assetFilter["org_id"] = bson.M{"$in": orgIds}
+		}
+
+		// HTML 报告内嵌截图，需要查询截图字段
+		findAssets := l.svcCtx.GetAssetModel(wsId).Find
+		if normalizeReportFormat(req.Format) == ReportFormatHTML {
+			findAssets = l.svcCtx.GetAssetModel(wsId).FindWithScreenshot
+		}
+		assets, err := findAssets(l.ctx, assetFilter, 0, 0)
 		if err != nil {
-			return nil, "", fmt.Errorf("任务不存在")
+			l.Errorf("查询资产失败: workspace=%s, %v", wsId, err)
+			continue
+		}
+		ds.Assets = append(ds.Assets, assets...)
+
+		// 漏洞和目录扫描结果没有组织字段，按该组织资产的地址关联
+		if req.OrgId != "" {
+			authorities := make([]string, 0, len(assets))
+			seen := make(map[string]bool, len(assets))
+			for _, a := range assets {
+				if a.Authority != "" && !seen[a.Authority] {
+					seen[a.Authority] = true
+					authorities = append(authorities, a.Authority)
+				}
+			}
+			if len(authorities) == 0 {
+				continue
+			}
+			vulFilter["authority"] = bson.M{"$in": authorities}
+			dirScanFilter["authority"] = bson.M{"$in": authorities}
 		}
+
+		vuls, err := l.svcCtx.GetVulModel(wsId).Find(l.ctx, vulFilter, 0, 0)
+		if err != nil {
+			l.Errorf("查询漏洞失败: workspace=%s, %v", wsId, err)
+		}
+		ds.Vuls = append(ds.Vuls, vuls...)
+
+		dirScans, err := l.svcCtx.GetDirScanResultModel().FindByFilter(l.ctx, dirScanFilter, 0, 0)
+		if err != nil {
+			l.Errorf("查询目录扫描结果失败: workspace=%s, %v", wsId, err)
+		}
+		ds.DirScans = append(ds.DirScans, dirScans...)
 	}
 
-	// 资产保存时使用的是 task.Id.Hex() (ObjectID) 作为 taskId
-	queryTaskId := task.Id.Hex()
+	return ds, nil
+}
 
-	// 获取资产列表（匹配主任务ID或子任务ID，同时兼容UUID和ObjectID格式）
-	assetModel := l.svcCtx.GetAssetModel(actualWorkspaceId)
-	assetFilter := bson.M{
-		"$or": []bson.M{
-			{"taskId": queryTaskId},
-			{"taskId": bson.M{"$regex": "^" + queryTaskId + "-\\d+$"}},
-			{"taskId": task.TaskId},
-			{"taskId": bson.M{"$regex": "^" + task.TaskId + "-\\d+$"}},
-		},
+// findTask 查找任务及其所在工作空间
+// 当 workspaceId 为 "all" 或空时，需要遍历所有工作空间查找任务
+func (l *ReportExportLogic) findTask(taskId, workspaceId string) (*model.MainTask, string) {
+	if workspaceId != "" && workspaceId != "all" {
+		task, err := l.svcCtx.GetMainTaskModel(workspaceId).FindById(l.ctx, taskId)
+		if err != nil {
+			return nil, ""
+		}
+		return task, workspaceId
 	}
-	assets, _ := assetModel.Find(l.ctx, assetFilter, 0, 0)
 
-	// 获取漏洞列表（匹配主任务ID或子任务ID，同时兼容UUID和ObjectID格式）
-	vulModel := l.svcCtx.GetVulModel(actualWorkspaceId)
-	vulFilter := bson.M{
-		"$or": []bson.M{
-			{"task_id": queryTaskId},
-			{"task_id": bson.M{"$regex": "^" + queryTaskId + "-\\d+$"}},
-			{"task_id": task.TaskId},
-			{"task_id": bson.M{"$regex": "^" + task.TaskId + "-\\d+$"}},
-		},
+	// 先尝试 default 工作空间
+	wsIds := []string{"default"}
+	workspaces, _ := l.svcCtx.WorkspaceModel.FindAll(l.ctx)
+	for _, ws := range workspaces {
+		wsIds = append(wsIds, ws.Id.Hex())
+	}
+	for _, wsId := range wsIds {
+		task, err := l.svcCtx.GetMainTaskModel(wsId).FindById(l.ctx, taskId)
+		if err == nil && task != nil {
+			return task, wsId
+		}
 	}
-	vuls, _ := vulModel.Find(l.ctx, vulFilter, 0, 0)
+	return nil, ""
+}
 
-	// 获取目录扫描结果
-	dirScanModel := l.svcCtx.GetDirScanResultModel()
-	// 构建查询条件：只按 main_task_id 查询，不限制 workspace_id
-	dirScanFilter := bson.M{
-		"$or": []bson.M{
-			{"main_task_id": queryTaskId},
-			{"main_task_id": bson.M{"$regex": "^" + queryTaskId + "-\\d+$"}},
-			{"main_task_id": task.TaskId},
-			{"main_task_id": bson.M{"$regex": "^" + task.TaskId + "-\\d+$"}},
-		},
+// describeScope 生成范围导出的报告名称和范围描述
+func (l *ReportExportLogic) describeScope(req *types.ReportExportReq, workspaceId string) (name, target string) {
+	switch workspaceId {
+	case "", "all":
+		name = "全部工作空间"
+	case "default":
+		name = "默认工作空间"
+	default:
+		name = workspaceId
+		if ws, err := l.svcCtx.WorkspaceModel.FindById(l.ctx, workspaceId); err == nil && ws != nil {
+			name = ws.Name
+		}
+	}
+	parts := []string{"工作空间: " + name}
+
+	if req.OrgId != "" {
+		orgName := req.OrgId
+		var contacts []string
+		if org, err := l.svcCtx.OrganizationModel.FindById(l.ctx, req.OrgId); err == nil && org != nil {
+			orgName = org.Name
+			for _, c := range org.Contacts {
+				contact := c.Name
+				if c.Email != "" {
+					contact += "(" + c.Email + ")"
+				}
+				contacts = append(contacts, contact)
+			}
+		}
+		name += "_" + orgName
+		parts = append(parts, "组织: "+orgName+"（含下级组织）")
+		if len(contacts) > 0 {
+			parts = append(parts, "联系人: "+strings.Join(contacts, ", "))
+		}
+	}
+	if req.StartTime != "" || req.EndTime != "" {
+		parts = append(parts, fmt.Sprintf("时间: %s ~ %s", req.StartTime, req.EndTime))
+	}
+	return name, strings.Join(parts, "; ")
+}
+
+// reportTaskFilter 匹配主任务ID或子任务ID（{id}-{index}），同时兼容UUID和ObjectID格式
+func reportTaskFilter(field string, task *model.MainTask) bson.M {
+	or := make([]bson.M, 0, 4)
+	for _, id := range []string{task.Id.Hex(), task.TaskId} {
+		if id == "" {
+			continue
+		}
+		or = append(or,
+			bson.M{field: id},
+			bson.M{field: bson.M{"$regex": "^" + id + "-\\d+$"}},
+		)
+	}
+	return bson.M{"$or": or}
+}
+
+// parseReportTime 解析导出时间范围，支持 RFC3339、日期时间和日期，仅日期的结束时间包含当天
+func parseReportTime(s string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return t, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1).Add(-time.Millisecond)
 	}
-	dirScans, _ := dirScanModel.FindByFilter(l.ctx, dirScanFilter, 1, 10000)
-
-	// 创建Excel文件
-	f := excelize.NewFile()
-	defer f.Close()
-
-	// 概览Sheet
-	f.SetSheetName("Sheet1", "概览")
-	f.SetCellValue("概览", "A1", "扫描报告")
-	f.SetCellValue("概览", "A3", "任务名称")
-	f.SetCellValue("概览", "B3", task.Name)
-	f.SetCellValue("概览", "A4", "扫描目标")
-	f.SetCellValue("概览", "B4", task.Target)
-	f.SetCellValue("概览", "A5", "任务状态")
-	f.SetCellValue("概览", "B5", task.Status)
-	f.SetCellValue("概览", "A6", "创建时间")
-	f.SetCellValue("概览", "B6", task.CreateTime.Local().Format("2006-01-02 15:04:05"))
-	f.SetCellValue("概览", "A7", "资产数量")
-	f.SetCellValue("概览", "B7", len(assets))
-	f.SetCellValue("概览", "A8", "漏洞数量")
-	f.SetCellValue("概览", "B8", len(vuls))
-	f.SetCellValue("概览", "A9", "目录扫描数量")
-	f.SetCellValue("概览", "B9", len(dirScans))
-
-	// 设置概览样式
-	titleStyle, _ := f.NewStyle(&excelize.Style{
-		Font:      &excelize.Font{Bold: true, Size: 16},
-		Alignment: &excelize.Alignment{Horizontal: "center"},
-	})
-	f.SetCellStyle("概览", "A1", "A1", titleStyle)
-	f.MergeCell("概览", "A1", "B1")
-
-	// 资产Sheet
-	f.NewSheet("资产列表")
-	assetHeaders := []string{"地址", "主机", "端口", "服务", "标题", "应用", "状态码", "Server", "IconHash", "发现时间"}
-	for i, h := range assetHeaders {
-		cell := fmt.Sprintf("%c1", 'A'+i)
-		f.SetCellValue("资产列表", cell, h)
-	}
-	for i, a := range assets {
-		row := i + 2
-		f.SetCellValue("资产列表", fmt.Sprintf("A%d", row), a.Authority)
-		f.SetCellValue("资产列表", fmt.Sprintf("B%d", row), a.Host)
-		f.SetCellValue("资产列表", fmt.Sprintf("C%d", row), a.Port)
-		f.SetCellValue("资产列表", fmt.Sprintf("D%d", row), a.Service)
-		f.SetCellValue("资产列表", fmt.Sprintf("E%d", row), a.Title)
-		f.SetCellValue("资产列表", fmt.Sprintf("F%d", row), strings.Join(a.App, ", "))
-		f.SetCellValue("资产列表", fmt.Sprintf("G%d", row), a.HttpStatus)
-		f.SetCellValue("资产列表", fmt.Sprintf("H%d", row), a.Server)
-		f.SetCellValue("资产列表", fmt.Sprintf("I%d", row), a.IconHash)
-		f.SetCellValue("资产列表", fmt.Sprintf("J%d", row), a.CreateTime.Local().Format("2006-01-02 15:04:05"))
-	}
-
-	// 漏洞Sheet
-	f.NewSheet("漏洞列表")
-	vulHeaders := []string{"地址", "URL", "POC", "严重级别", "结果", "发现时间"}
-	for i, h := range vulHeaders {
-		cell := fmt.Sprintf("%c1", 'A'+i)
-		f.SetCellValue("漏洞列表", cell, h)
-	}
-	for i, v := range vuls {
-		row := i + 2
-		f.SetCellValue("漏洞列表", fmt.Sprintf("A%d", row), v.Authority)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("B%d", row), v.Url)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("C%d", row), v.PocFile)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("D%d", row), v.Severity)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("E%d", row), v.Result)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("F%d", row), v.CreateTime.Local().Format("2006-01-02 15:04:05"))
-	}
-
-	// 目录扫描Sheet
-	f.NewSheet("目录扫描")
-	dirScanHeaders := []string{"目标", "URL", "路径", "状态码", "大小", "类型", "标题", "发现时间"}
-	for i, h := range dirScanHeaders {
-		cell := fmt.Sprintf("%c1", 'A'+i)
-		f.SetCellValue("目录扫描", cell, h)
-	}
-	for i, ds := range dirScans {
-		row := i + 2
-		f.SetCellValue("目录扫描", fmt.Sprintf("A%d", row), ds.Authority)
-		f.SetCellValue("目录扫描", fmt.Sprintf("B%d", row), ds.URL)
-		f.SetCellValue("目录扫描", fmt.Sprintf("C%d", row), ds.Path)
-		f.SetCellValue("目录扫描", fmt.Sprintf("D%d", row), ds.StatusCode)
-		f.SetCellValue("目录扫描", fmt.Sprintf("E%d", row), ds.ContentLength)
-		f.SetCellValue("目录扫描", fmt.Sprintf("F%d", row), ds.ContentType)
-		f.SetCellValue("目录扫描", fmt.Sprintf("G%d", row), ds.Title)
-		f.SetCellValue("目录扫描", fmt.Sprintf("H%d", row), ds.CreateTime.Local().Format("2006-01-02 15:04:05"))
-	}
-
-	// 设置列宽
-	f.SetColWidth("资产列表", "A", "A", 30)
-	f.SetColWidth("资产列表", "B", "B", 15)
-	f.SetColWidth("资产列表", "E", "E", 40)
-	f.SetColWidth("资产列表", "F", "F", 30)
-	f.SetColWidth("漏洞列表", "A", "A", 30)
-	f.SetColWidth("漏洞列表", "B", "B", 50)
-	f.SetColWidth("漏洞列表", "C", "C", 40)
-	f.SetColWidth("漏洞列表", "E", "E", 50)
-	f.SetColWidth("目录扫描", "A", "A", 25)
-	f.SetColWidth("目录扫描", "B", "B", 50)
-	f.SetColWidth("目录扫描", "C", "C", 30)
-	f.SetColWidth("目录扫描", "G", "G", 30)
-
-	// 写入buffer
-	var buf bytes.Buffer
-	if err := f.Write(&buf); err != nil {
-		return nil, "", err
-	}
-
-	filename := fmt.Sprintf("report_%s_%s.xlsx", task.Name, time.Now().Format("20060102150405"))
-	return buf.Bytes(), filename, nil
+	return t, nil
 }
@@ -0,0 +1,70 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"cscan/api/internal/config"
+	"cscan/api/internal/svc"
+	"cscan/model"
+)
+
+func testScreenshotPNG(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for x := 0; x < 8; x++ {
+		img.Set(x, x, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReportExportHTMLEmbedsStoredScreenshot(t *testing.T) {
+	screenshots := svc.NewScreenshotService(config.BlobStoreConfig{Type: "local", Dir: t.TempDir()}, "test-secret")
+	data := testScreenshotPNG(t)
+	encoded := base64.StdEncoding.EncodeToString(data)
+	key, _, err := screenshots.Save(context.Background(), encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !svc.IsScreenshotKey(key) {
+		t.Fatalf("Save returned %q, want a storage key", key)
+	}
+
+	missing := strings.Repeat("0", 64) + ".png"
+	ds := &reportDataset{
+		Name: "t",
+		Assets: []model.Asset{
+			{Authority: "a.example.com:443", Host: "a.example.com", Port: 443, Screenshot: key},
+			{Authority: "b.example.com:443", Host: "b.example.com", Port: 443, Screenshot: missing},
+		},
+	}
+	l := NewReportExportLogic(context.Background(), &svc.ServiceContext{Screenshots: screenshots})
+	l.embedScreenshots(ds)
+
+	if want := "data:image/png;base64," + encoded; ds.Assets[0].Screenshot != want {
+		t.Errorf("stored screenshot not embedded: %.60q", ds.Assets[0].Screenshot)
+	}
+	if ds.Assets[1].Screenshot != "" {
+		t.Errorf("missing screenshot should be dropped, got %q", ds.Assets[1].Screenshot)
+	}
+
+	out, err := renderReportHTML(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `src="data:image/png;base64,`+encoded+`"`) {
+		t.Error("HTML report does not embed the stored screenshot")
+	}
+	if strings.Contains(string(out), key) || strings.Contains(string(out), missing) {
+		t.Error("HTML report must not reference storage keys")
+	}
+}
@@ -0,0 +1,138 @@
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"cscan/api/internal/logic/common"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ScanScopeLogic 工作空间扫描范围
+type ScanScopeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// NewScanScopeLogic 创建扫描范围逻辑
+func NewScanScopeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ScanScopeLogic {
+	return &ScanScopeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// resolveWorkspace 请求中指定的工作空间优先，否则使用当前工作空间
+func (l *ScanScopeLogic) resolveWorkspace(reqWorkspaceId, workspaceId string) string {
+	if reqWorkspaceId != "" {
+		workspaceId = reqWorkspaceId
+	}
+	return common.GetDefaultWorkspaceId(l.ctx, l.svcCtx, workspaceId)
+}
+
+// ScanScopeGet 获取工作空间扫描范围
+func (l *ScanScopeLogic) ScanScopeGet(req *types.ScanScopeGetReq, workspaceId string) (*types.ScanScopeResp, error) {
+	workspaceId = l.resolveWorkspace(req.WorkspaceId, workspaceId)
+	doc, err := l.svcCtx.ScanScopeModel.GetByWorkspace(l.ctx, workspaceId)
+	if err != nil {
+		l.Errorf("ScanScopeGet error: %v", err)
+		return &types.ScanScopeResp{Code: 500, Msg: "获取扫描范围失败"}, nil
+	}
+
+	data := &types.ScanScope{WorkspaceId: workspaceId}
+	if doc != nil {
+		data.Enabled = doc.Enabled
+		data.Cidrs = doc.Cidrs
+		data.Domains = doc.Domains
+		data.Hosts = doc.Hosts
+		data.Timezone = doc.Timezone
+		data.StrictIP = doc.StrictIP
+		data.UpdateTime = doc.UpdateTime.Local().Format("2006-01-02 15:04:05")
+		for _, w := range doc.Windows {
+			data.Windows = append(data.Windows, types.ScanScopeWindow{Days: w.Days, Start: w.Start, End: w.End})
+		}
+	}
+	return &types.ScanScopeResp{Code: 0, Msg: "success", Data: data}, nil
+}
+
+// ScanScopeSave 保存工作空间扫描范围
+func (l *ScanScopeLogic) ScanScopeSave(req *types.ScanScope, workspaceId string) (*types.BaseResp, error) {
+	doc := &model.ScanScope{
+		WorkspaceId: l.resolveWorkspace(req.WorkspaceId, workspaceId),
+		Enabled:     req.Enabled,
+		Cidrs:       trimList(req.Cidrs),
+		Domains:     trimList(req.Domains),
+		Hosts:       trimList(req.Hosts),
+		Timezone:    strings.TrimSpace(req.Timezone),
+		StrictIP:    req.StrictIP,
+	}
+	for _, w := range req.Windows {
+		doc.Windows = append(doc.Windows, model.ScanScopeWindow{Days: w.Days, Start: w.Start, End: w.End})
+	}
+
+	matcher, err := utils.NewScopeMatcher(common.ScopeRules(doc))
+	if err != nil {
+		return &types.BaseResp{Code: 400, Msg: "扫描范围配置错误: " + err.Error()}, nil
+	}
+	if doc.Enabled && matcher.IsEmpty() {
+		return &types.BaseResp{Code: 400, Msg: "启用扫描范围时至少需要配置一条网段、域名或主机"}, nil
+	}
+
+	if err := l.svcCtx.ScanScopeModel.Save(l.ctx, doc); err != nil {
+		l.Errorf("ScanScopeSave error: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存扫描范围失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+}
+
+// OutOfScopeList 范围外目标列表
+func (l *ScanScopeLogic) OutOfScopeList(req *types.OutOfScopeListReq, workspaceId string) (*types.OutOfScopeListResp, error) {
+	workspaceId = l.resolveWorkspace(req.WorkspaceId, workspaceId)
+	filter := bson.M{}
+	if req.MainTaskId != "" {
+		filter["main_task_id"] = req.MainTaskId
+	}
+
+	m := l.svcCtx.GetOutOfScopeModel(workspaceId)
+	total, err := m.Count(l.ctx, filter)
+	if err != nil {
+		return &types.OutOfScopeListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+	docs, err := m.Find(l.ctx, filter, req.Page, req.PageSize)
+	if err != nil {
+		return &types.OutOfScopeListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.OutOfScope, 0, len(docs))
+	for _, d := range docs {
+		list = append(list, types.OutOfScope{
+			Id:         d.Id.Hex(),
+			MainTaskId: d.MainTaskId,
+			Target:     d.Target,
+			Host:       d.Host,
+			Phase:      d.Phase,
+			Reason:     d.Reason,
+			CreateTime: d.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+	return &types.OutOfScopeListResp{Code: 0, Msg: "success", Total: total, List: list}, nil
+}
+
+// trimList 去除空白项
+func trimList(items []string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -5,7 +5,9 @@ import (
 	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
+	"cscan/pkg/imagehash"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -157,7 +159,8 @@ func (l *ScreenshotsLogic) Screenshots(req *types.ScreenshotsReq, workspaceId st
 				Status:       asset.HttpStatus,
 				StatusText:   statusText,
 				Title:        asset.Title,
-				Screenshot:   asset.Screenshot,
+				Screenshot:   l.svcCtx.Screenshots.SignRef(asset.Screenshot),
+				Hash:         asset.ScreenshotHash,
 				LastUpdated:  formatScreenshotTime(asset.UpdateTime),
 				Technologies: technologies,
 				HttpHeader:   asset.HttpHeader,
@@ -172,6 +175,33 @@ func (l *ScreenshotsLogic) Screenshots(req *types.ScreenshotsReq, workspaceId st
 	// 排序
 	sortScreenshots(allScreenshots, req.SortBy)
 
+	// 按截图相似度聚类，分页对象为分组
+	if req.GroupBy == "phash" {
+		maxDistance := req.MaxDistance
+		if maxDistance <= 0 {
+			maxDistance = defaultScreenshotDistance
+		}
+		groups := groupScreenshots(allScreenshots, maxDistance)
+		total := len(groups)
+		start := (req.Page - 1) * req.PageSize
+		end := start + req.PageSize
+		if start >= total {
+			groups = []types.ScreenshotGroup{}
+		} else {
+			if end > total {
+				end = total
+			}
+			groups = groups[start:end]
+		}
+		return &types.ScreenshotsResp{
+			Code:   0,
+			Msg:    "success",
+			Total:  total,
+			List:   []types.ScreenshotItem{},
+			Groups: groups,
+		}, nil
+	}
+
 	// 分页
 	total := len(allScreenshots)
 	start := (req.Page - 1) * req.PageSize
@@ -209,6 +239,62 @@ func sortScreenshots(screenshots []types.ScreenshotItem, sortBy string) {
 	// 默认按时间排序（已经是最新的在前）
 }
 
+// defaultScreenshotDistance 默认聚类阈值，64位差异哈希相差不超过6位视为同一页面
+const defaultScreenshotDistance = 6
+
+// groupScreenshots 按感知哈希聚类，每组以第一张截图的哈希为中心，组按数量降序排列
+// 没有哈希的旧截图各自成组
+func groupScreenshots(items []types.ScreenshotItem, maxDistance int) []types.ScreenshotGroup {
+	groups := make([]types.ScreenshotGroup, 0)
+	centers := make([]imagehash.Hash, 0)
+	hashed := make([]bool, 0)
+	exact := make(map[imagehash.Hash]int)
+
+	for _, item := range items {
+		member := types.ScreenshotGroupMember{
+			Id:          item.Id,
+			WorkspaceId: item.WorkspaceId,
+			Authority:   item.Authority,
+			Title:       item.Title,
+		}
+		h, err := imagehash.Parse(item.Hash)
+		if err == nil {
+			idx, ok := exact[h]
+			if !ok {
+				best := maxDistance + 1
+				for i, c := range centers {
+					if !hashed[i] {
+						continue
+					}
+					if d := imagehash.Distance(c, h); d < best {
+						idx, best, ok = i, d, true
+					}
+				}
+			}
+			if ok {
+				member.Distance = imagehash.Distance(centers[idx], h)
+				groups[idx].Count++
+				groups[idx].Members = append(groups[idx].Members, member)
+				continue
+			}
+			exact[h] = len(groups)
+		}
+		groups = append(groups, types.ScreenshotGroup{
+			Hash:    item.Hash,
+			Count:   1,
+			Sample:  item,
+			Members: []types.ScreenshotGroupMember{member},
+		})
+		centers = append(centers, h)
+		hashed = append(hashed, err == nil)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	return groups
+}
+
 // formatScreenshotTime 格式化截图时间
 func formatScreenshotTime(t time.Time) string {
 	now := time.Now()
@@ -0,0 +1,140 @@
+package logic
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SecretRuleLogic 爬虫敏感信息规则管理
+type SecretRuleLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSecretRuleLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SecretRuleLogic {
+	return &SecretRuleLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func convertSecretRule(doc *model.SecretRule) types.SecretRule {
+	return types.SecretRule{
+		Id:          doc.Id.Hex(),
+		Name:        doc.Name,
+		Pattern:     doc.Pattern,
+		Severity:    doc.Severity,
+		Description: doc.Description,
+		Enabled:     doc.Enabled,
+		IsBuiltin:   doc.IsBuiltin,
+		CreateTime:  doc.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		UpdateTime:  doc.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+}
+
+// SecretRuleList 敏感信息规则列表
+func (l *SecretRuleLogic) SecretRuleList(req *types.SecretRuleListReq) (*types.SecretRuleListResp, error) {
+	filter := bson.M{}
+	if req.Keyword != "" {
+		q := regexp.QuoteMeta(req.Keyword)
+		filter["$or"] = []bson.M{
+			{"name": bson.M{"$regex": q, "$options": "i"}},
+			{"description": bson.M{"$regex": q, "$options": "i"}},
+		}
+	}
+	if req.Severity != "" {
+		filter["severity"] = strings.ToLower(req.Severity)
+	}
+	if req.Enabled != nil {
+		filter["enabled"] = *req.Enabled
+	}
+
+	total, err := l.svcCtx.SecretRuleModel.Count(l.ctx, filter)
+	if err != nil {
+		l.Errorf("查询敏感信息规则失败: %v", err)
+		return &types.SecretRuleListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+	docs, err := l.svcCtx.SecretRuleModel.FindWithSort(l.ctx, filter, req.Page, req.PageSize, "name", 1)
+	if err != nil {
+		l.Errorf("查询敏感信息规则失败: %v", err)
+		return &types.SecretRuleListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.SecretRule, 0, len(docs))
+	for i := range docs {
+		list = append(list, convertSecretRule(&docs[i]))
+	}
+	return &types.SecretRuleListResp{Code: 0, Msg: "success", Total: total, List: list}, nil
+}
+
+// SecretRuleSave 新建或更新敏感信息规则
+func (l *SecretRuleLogic) SecretRuleSave(req *types.SecretRuleSaveReq) (*types.BaseResp, error) {
+	rule := &scanner.SecretRule{
+		Name:        strings.TrimSpace(req.Name),
+		Pattern:     strings.TrimSpace(req.Pattern),
+		Severity:    strings.ToLower(strings.TrimSpace(req.Severity)),
+		Description: req.Description,
+	}
+	if rule.Pattern == "" {
+		return &types.BaseResp{Code: 400, Msg: "匹配规则不能为空"}, nil
+	}
+	if err := rule.Validate(); err != nil {
+		return &types.BaseResp{Code: 400, Msg: err.Error()}, nil
+	}
+
+	existing, err := l.svcCtx.SecretRuleModel.FindByName(l.ctx, rule.Name)
+	if err != nil {
+		l.Errorf("查询敏感信息规则失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存失败"}, nil
+	}
+	if existing != nil && existing.Id.Hex() != req.Id {
+		return &types.BaseResp{Code: 400, Msg: "规则名称已存在"}, nil
+	}
+
+	doc := &model.SecretRule{
+		Name:        rule.Name,
+		Pattern:     rule.Pattern,
+		Severity:    rule.Severity,
+		Description: rule.Description,
+		Enabled:     req.Enabled,
+	}
+	if req.Id == "" {
+		err = l.svcCtx.SecretRuleModel.Create(l.ctx, doc)
+	} else {
+		err = l.svcCtx.SecretRuleModel.Update(l.ctx, req.Id, doc)
+	}
+	if err != nil {
+		l.Errorf("保存敏感信息规则失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+}
+
+// SecretRuleDelete 删除敏感信息规则
+func (l *SecretRuleLogic) SecretRuleDelete(req *types.SecretRuleDeleteReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.SecretRuleModel.DeleteById(l.ctx, req.Id); err != nil {
+		l.Errorf("删除敏感信息规则失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
+// SecretRuleUpdateEnabled 启用或禁用敏感信息规则
+func (l *SecretRuleLogic) SecretRuleUpdateEnabled(req *types.SecretRuleUpdateEnabledReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.SecretRuleModel.UpdateById(l.ctx, req.Id, bson.M{"enabled": req.Enabled}); err != nil {
+		l.Errorf("更新敏感信息规则状态失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "更新失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "更新成功"}, nil
+}
@@ -11,6 +11,7 @@ import (
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
+	"cscan/pkg/utils"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -37,6 +38,11 @@ func (l *SiteLogic) SiteList(req *types.SiteListReq, workspaceId string) (*types
 		return resp, nil
 	}
 
+	queryFilter, err := common.CompileQuery(common.SiteQuerySchema, req.Query)
+	if err != nil {
+		return &types.SiteListResp{Code: 400, Msg: err.Error()}, nil
+	}
+
 	orgMap := common.LoadOrgMap(l.ctx, l.svcCtx)
 
 	var allSites []types.Site
@@ -61,16 +67,9 @@ func (l *SiteLogic) SiteList(req *types.SiteListReq, workspaceId string) (*types
 		filter := bson.M{}
 		conditions := []bson.M{webFilter}
 
-		// 通用 Query 模糊搜索（当未指定具体字段时）
-		if req.Query != "" && req.Site == "" && req.Title == "" && req.App == "" {
-			q := regexp.QuoteMeta(req.Query)
-			conditions = append(conditions, bson.M{
-				"$or": []bson.M{
-					{"authority": bson.M{"$regex": q, "$options": "i"}},
-					{"host": bson.M{"$regex": q, "$options": "i"}},
-					{"title": bson.M{"$regex": q, "$options": "i"}},
-				},
-			})
+		// 查询语法（关键词模糊匹配 authority/host/title）
+		if queryFilter != nil {
+			conditions = append(conditions, queryFilter)
 		}
 
 		if req.Site != "" {
@@ -145,7 +144,7 @@ func (l *SiteLogic) SiteList(req *types.SiteListReq, workspaceId string) (*types
 			if asset.Authority != "" {
 				site.Site = fmt.Sprintf("%s://%s", scheme, asset.Authority)
 			} else {
-				site.Site = fmt.Sprintf("%s://%s:%d", scheme, asset.Host, asset.Port)
+				site.Site = fmt.Sprintf("%s://%s", scheme, utils.BuildTargetWithPort(asset.Host, asset.Port))
 			}
 
 			// 获取位置信息
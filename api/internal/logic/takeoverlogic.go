@@ -0,0 +1,155 @@
+package logic
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TakeoverSignatureLogic 子域接管签名管理
+type TakeoverSignatureLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewTakeoverSignatureLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TakeoverSignatureLogic {
+	return &TakeoverSignatureLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func convertTakeoverSignature(doc *model.TakeoverSignature) types.TakeoverSignature {
+	return types.TakeoverSignature{
+		Id:           doc.Id.Hex(),
+		Service:      doc.Service,
+		RecordType:   doc.RecordType,
+		Patterns:     doc.Patterns,
+		Fingerprints: doc.Fingerprints,
+		HttpStatus:   doc.HttpStatus,
+		NXDomain:     doc.NXDomain,
+		Refused:      doc.Refused,
+		Severity:     doc.Severity,
+		Description:  doc.Description,
+		Enabled:      doc.Enabled,
+		IsBuiltin:    doc.IsBuiltin,
+		CreateTime:   doc.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		UpdateTime:   doc.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+}
+
+// TakeoverSignatureList 子域接管签名列表
+func (l *TakeoverSignatureLogic) TakeoverSignatureList(req *types.TakeoverSignatureListReq) (*types.TakeoverSignatureListResp, error) {
+	filter := bson.M{}
+	if req.Keyword != "" {
+		q := regexp.QuoteMeta(req.Keyword)
+		filter["$or"] = []bson.M{
+			{"service": bson.M{"$regex": q, "$options": "i"}},
+			{"patterns": bson.M{"$regex": q, "$options": "i"}},
+		}
+	}
+	if req.RecordType != "" {
+		filter["record_type"] = strings.ToUpper(req.RecordType)
+	}
+	if req.Enabled != nil {
+		filter["enabled"] = *req.Enabled
+	}
+
+	total, err := l.svcCtx.TakeoverSignatureModel.Count(l.ctx, filter)
+	if err != nil {
+		l.Errorf("查询子域接管签名失败: %v", err)
+		return &types.TakeoverSignatureListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+	docs, err := l.svcCtx.TakeoverSignatureModel.FindWithSort(l.ctx, filter, req.Page, req.PageSize, "service", 1)
+	if err != nil {
+		l.Errorf("查询子域接管签名失败: %v", err)
+		return &types.TakeoverSignatureListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.TakeoverSignature, 0, len(docs))
+	for i := range docs {
+		list = append(list, convertTakeoverSignature(&docs[i]))
+	}
+	return &types.TakeoverSignatureListResp{Code: 0, Msg: "success", Total: total, List: list}, nil
+}
+
+// TakeoverSignatureSave 新建或更新子域接管签名
+func (l *TakeoverSignatureLogic) TakeoverSignatureSave(req *types.TakeoverSignatureSaveReq) (*types.BaseResp, error) {
+	sig := &scanner.TakeoverSignature{
+		Service:      strings.TrimSpace(req.Service),
+		RecordType:   strings.ToUpper(strings.TrimSpace(req.RecordType)),
+		Patterns:     trimList(req.Patterns),
+		Fingerprints: trimList(req.Fingerprints),
+		HttpStatus:   req.HttpStatus,
+		NXDomain:     req.NXDomain,
+		Refused:      req.Refused,
+		Severity:     strings.ToLower(strings.TrimSpace(req.Severity)),
+		Description:  req.Description,
+	}
+	if sig.RecordType == "" {
+		sig.RecordType = scanner.TakeoverRecordCNAME
+	}
+	if err := sig.Validate(); err != nil {
+		return &types.BaseResp{Code: 400, Msg: err.Error()}, nil
+	}
+
+	existing, err := l.svcCtx.TakeoverSignatureModel.FindByService(l.ctx, sig.Service)
+	if err != nil {
+		l.Errorf("查询子域接管签名失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存失败"}, nil
+	}
+	if existing != nil && existing.Id.Hex() != req.Id {
+		return &types.BaseResp{Code: 400, Msg: "服务名称已存在"}, nil
+	}
+
+	doc := &model.TakeoverSignature{
+		Service:      sig.Service,
+		RecordType:   sig.RecordType,
+		Patterns:     sig.Patterns,
+		Fingerprints: sig.Fingerprints,
+		HttpStatus:   sig.HttpStatus,
+		NXDomain:     sig.NXDomain,
+		Refused:      sig.Refused,
+		Severity:     sig.Severity,
+		Description:  sig.Description,
+		Enabled:      req.Enabled,
+	}
+	if req.Id == "" {
+		err = l.svcCtx.TakeoverSignatureModel.Create(l.ctx, doc)
+	} else {
+		err = l.svcCtx.TakeoverSignatureModel.Update(l.ctx, req.Id, doc)
+	}
+	if err != nil {
+		l.Errorf("保存子域接管签名失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+}
+
+// TakeoverSignatureDelete 删除子域接管签名
+func (l *TakeoverSignatureLogic) TakeoverSignatureDelete(req *types.TakeoverSignatureDeleteReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.TakeoverSignatureModel.DeleteById(l.ctx, req.Id); err != nil {
+		l.Errorf("删除子域接管签名失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
+// TakeoverSignatureUpdateEnabled 启用或禁用子域接管签名
+func (l *TakeoverSignatureLogic) TakeoverSignatureUpdateEnabled(req *types.TakeoverSignatureUpdateEnabledReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.TakeoverSignatureModel.UpdateById(l.ctx, req.Id, bson.M{"enabled": req.Enabled}); err != nil {
+		l.Errorf("更新子域接管签名状态失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "更新失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "更新成功"}, nil
+}
@@ -0,0 +1,133 @@
+package logic
+
+import (
+	"context"
+
+	"cscan/api/internal/logic/common"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// TaskDiffLogic 定时任务两次执行结果对比
+type TaskDiffLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewTaskDiffLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TaskDiffLogic {
+	return &TaskDiffLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// TaskDiff 对比本次执行与基准执行，未指定基准时取同一定时任务的上一次成功执行
+func (l *TaskDiffLogic) TaskDiff(req *types.TaskDiffReq, workspaceId string) (*types.TaskDiffResp, error) {
+	if req.TaskId == "" {
+		return &types.TaskDiffResp{Code: 400, Msg: "任务ID不能为空"}, nil
+	}
+
+	var task *model.MainTask
+	for _, wsId := range common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId) {
+		if t, err := l.svcCtx.GetMainTaskModel(wsId).FindById(l.ctx, req.TaskId); err == nil && t != nil {
+			task = t
+			workspaceId = wsId
+			break
+		}
+	}
+	if task == nil {
+		return &types.TaskDiffResp{Code: 404, Msg: "任务不存在"}, nil
+	}
+	taskModel := l.svcCtx.GetMainTaskModel(workspaceId)
+
+	var base *model.MainTask
+	if req.BaseTaskId != "" {
+		b, err := taskModel.FindById(l.ctx, req.BaseTaskId)
+		if err != nil || b == nil {
+			return &types.TaskDiffResp{Code: 404, Msg: "基准任务不存在"}, nil
+		}
+		base = b
+	} else {
+		if !task.IsCron {
+			return &types.TaskDiffResp{Code: 400, Msg: "非定时任务请指定对比的基准任务"}, nil
+		}
+		b, err := taskModel.FindPreviousCronRun(l.ctx, task)
+		if err != nil {
+			l.Errorf("查询上一次执行失败: %v", err)
+			return &types.TaskDiffResp{Code: 500, Msg: "查询上一次执行失败"}, nil
+		}
+		if b == nil {
+			return &types.TaskDiffResp{Code: 404, Msg: "没有可对比的上一次执行"}, nil
+		}
+		base = b
+	}
+
+	diff, err := l.svcCtx.HistoryService.CompareTaskRuns(l.ctx, &svc.CompareTaskRunsReq{
+		WorkspaceId: workspaceId,
+		BaseTaskId:  base.Id.Hex(),
+		TaskId:      task.Id.Hex(),
+	})
+	if err != nil {
+		l.Errorf("对比任务执行结果失败: %v", err)
+		return &types.TaskDiffResp{Code: 400, Msg: "基准执行没有结果快照，无法对比"}, nil
+	}
+
+	return &types.TaskDiffResp{
+		Code: 0,
+		Msg:  "success",
+		Data: &types.TaskDiffData{
+			BaseTaskId:   base.Id.Hex(),
+			BaseTaskName: base.Name,
+			BaseTime:     taskRunTime(base),
+			TaskId:       task.Id.Hex(),
+			TaskName:     task.Name,
+			Time:         taskRunTime(task),
+			NewAssets:    diff.NewAssets,
+			GoneAssets:   diff.GoneAssets,
+			NewPorts:     diff.NewPorts,
+			ClosedPorts:  diff.ClosedPorts,
+			Changes:      convertTaskDiffChanges(diff.Changes),
+			NewVuls:      convertTaskDiffVuls(diff.NewVuls),
+			ResolvedVuls: convertTaskDiffVuls(diff.ResolvedVuls),
+		},
+	}, nil
+}
+
+func taskRunTime(t *model.MainTask) string {
+	if t.EndTime != nil {
+		return t.EndTime.Local().Format("2006-01-02 15:04:05")
+	}
+	return t.CreateTime.Local().Format("2006-01-02 15:04:05")
+}
+
+func convertTaskDiffChanges(changes []model.TaskRunChange) []types.TaskDiffChange {
+	list := make([]types.TaskDiffChange, 0, len(changes))
+	for _, c := range changes {
+		list = append(list, types.TaskDiffChange{
+			Authority: c.Authority,
+			Field:     c.Field,
+			OldValue:  c.OldValue,
+			NewValue:  c.NewValue,
+		})
+	}
+	return list
+}
+
+func convertTaskDiffVuls(vuls []model.TaskRunVul) []types.TaskDiffVul {
+	list := make([]types.TaskDiffVul, 0, len(vuls))
+	for _, v := range vuls {
+		list = append(list, types.TaskDiffVul{
+			Authority: v.Authority,
+			Url:       v.Url,
+			PocFile:   v.PocFile,
+			VulName:   v.VulName,
+			Severity:  v.Severity,
+		})
+	}
+	return list
+}
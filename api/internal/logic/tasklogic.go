@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"cscan/api/internal/logic/common"
+	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
@@ -282,6 +283,9 @@ func (l *MainTaskCreateLogic) MainTaskCreate(req *types.MainTaskCreateReq, works
 	if wsId == "" {
 		return &types.BaseRespWithId{Code: 400, Msg: "workspaceId不能为空"}, nil
 	}
+	if !canManageTask(l.ctx, wsId) {
+		return &types.BaseRespWithId{Code: 403, Msg: "无权管理该工作空间的任务"}, nil
+	}
 
 	l.Logger.Infof("MainTaskCreate: name=%s, reqWorkspaceId=%s, headerWorkspaceId=%s, using=%s",
 		req.Name, req.WorkspaceId, workspaceId, wsId)
@@ -294,6 +298,16 @@ func (l *MainTaskCreateLogic) MainTaskCreate(req *types.MainTaskCreateReq, works
 		return &types.BaseRespWithId{Code: 400, Msg: common.FormatValidationErrors(validationErrors)}, nil
 	}
 
+	// 校验目标是否在工作空间扫描范围内
+	scopeMatcher, err := common.LoadScopeMatcher(l.ctx, l.svcCtx, wsId)
+	if err != nil {
+		l.Logger.Errorf("MainTaskCreate: load scan scope failed: %v", err)
+		return &types.BaseRespWithId{Code: 500, Msg: "加载扫描范围失败"}, nil
+	}
+	if outOfScope := common.TargetsOutOfScope(scopeMatcher, req.Target); len(outOfScope) > 0 {
+		return &types.BaseRespWithId{Code: 400, Msg: "以下目标不在工作空间扫描范围内: " + strings.Join(outOfScope, ", ")}, nil
+	}
+
 	taskModel := l.svcCtx.GetMainTaskModel(wsId)
 
 	// 构建任务配置
@@ -359,6 +373,19 @@ func (l *MainTaskCreateLogic) MainTaskCreate(req *types.MainTaskCreateReq, works
 	taskConfig = common.InjectPocConfig(l.ctx, l.svcCtx, taskConfig, l.Logger)
 	configBytes, _ := json.Marshal(taskConfig)
 
+	// 校验声明式工作流（阶段依赖必须构成无环图）
+	if _, ok := taskConfig["workflow"]; ok {
+		parsed, err := scheduler.ParseTaskConfig(string(configBytes))
+		if err != nil {
+			return &types.BaseRespWithId{Code: 400, Msg: "工作流配置解析失败: " + err.Error()}, nil
+		}
+		if parsed.Workflow != nil {
+			if err := parsed.Workflow.Validate(); err != nil {
+				return &types.BaseRespWithId{Code: 400, Msg: "工作流配置错误: " + err.Error()}, nil
+			}
+		}
+	}
+
 	configStr := string(configBytes)
 	logLen := len(configStr)
 	if logLen > 500 {
@@ -522,6 +549,9 @@ func (l *MainTaskDeleteLogic) MainTaskDelete(req *types.MainTaskDeleteReq, works
 	if wsId == "" || wsId == "all" {
 		return &types.BaseResp{Code: 400, Msg: "删除任务需要指定工作空间"}, nil
 	}
+	if !canDeleteTask(l.ctx, wsId) {
+		return &types.BaseResp{Code: 403, Msg: "无权删除该工作空间的任务"}, nil
+	}
 
 	taskModel := l.svcCtx.GetMainTaskModel(wsId)
 
@@ -574,6 +604,9 @@ func (l *MainTaskBatchDeleteLogic) MainTaskBatchDelete(req *types.MainTaskBatchD
 	if wsId == "" || wsId == "all" {
 		return &types.BaseResp{Code: 400, Msg: "删除任务需要指定工作空间"}, nil
 	}
+	if !canDeleteTask(l.ctx, wsId) {
+		return &types.BaseResp{Code: 403, Msg: "无权删除该工作空间的任务"}, nil
+	}
 
 	taskModel := l.svcCtx.GetMainTaskModel(wsId)
 
@@ -651,6 +684,10 @@ func (l *MainTaskRetryLogic) MainTaskRetry(req *types.MainTaskRetryReq, workspac
 		}
 	}
 
+	if !canManageTask(l.ctx, actualWorkspaceId) {
+		return &types.BaseRespWithId{Code: 403, Msg: "无权管理该工作空间的任务"}, nil
+	}
+
 	// 使用实际的工作空间ID
 	taskModel := l.svcCtx.GetMainTaskModel(actualWorkspaceId)
 	workspaceId = actualWorkspaceId
@@ -781,6 +818,9 @@ func (l *MainTaskStartLogic) MainTaskStart(req *types.MainTaskControlReq, worksp
 			return &types.BaseResp{Code: 400, Msg: "任务不存在"}, nil
 		}
 	}
+	if !canManageTask(l.ctx, wsId) {
+		return &types.BaseResp{Code: 403, Msg: "无权管理该工作空间的任务"}, nil
+	}
 
 	fmt.Printf("[MainTaskStart] using workspaceId='%s'\n", wsId)
 	l.Logger.Infof("MainTaskStart: using workspaceId='%s'", wsId)
@@ -874,6 +914,9 @@ func (l *MainTaskPauseLogic) MainTaskPause(req *types.MainTaskControlReq, worksp
 			return &types.BaseResp{Code: 400, Msg: "任务不存在"}, nil
 		}
 	}
+	if !canManageTask(l.ctx, wsId) {
+		return &types.BaseResp{Code: 403, Msg: "无权管理该工作空间的任务"}, nil
+	}
 
 	l.Logger.Infof("MainTaskPause: found task, id=%s, taskId=%s, status='%s', progress=%d, subTaskCount=%d, subTaskDone=%d",
 		req.Id, task.TaskId, task.Status, task.Progress, task.SubTaskCount, task.SubTaskDone)
@@ -972,6 +1015,9 @@ func (l *MainTaskResumeLogic) MainTaskResume(req *types.MainTaskControlReq, work
 			return &types.BaseResp{Code: 400, Msg: "任务不存在"}, nil
 		}
 	}
+	if !canManageTask(l.ctx, wsId) {
+		return &types.BaseResp{Code: 403, Msg: "无权管理该工作空间的任务"}, nil
+	}
 
 	// 避免 taskModel 未使用的编译错误
 	_ = taskModel
@@ -1152,6 +1198,9 @@ func (l *MainTaskStopLogic) MainTaskStop(req *types.MainTaskControlReq, workspac
 			return &types.BaseResp{Code: 400, Msg: "任务不存在"}, nil
 		}
 	}
+	if !canManageTask(l.ctx, wsId) {
+		return &types.BaseResp{Code: 403, Msg: "无权管理该工作空间的任务"}, nil
+	}
 
 	// 检查状态：STARTED, PAUSED, PENDING, CREATED 或空状态可以停止
 	canStop := task.Status == model.TaskStatusStarted ||
@@ -1296,6 +1345,10 @@ func NewMainTaskUpdateLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Ma
 }
 
 func (l *MainTaskUpdateLogic) MainTaskUpdate(req *types.MainTaskUpdateReq, workspaceId string) (resp *types.BaseResp, err error) {
+	if !canManageTask(l.ctx, workspaceId) {
+		return &types.BaseResp{Code: 403, Msg: "无权管理该工作空间的任务"}, nil
+	}
+
 	taskModel := l.svcCtx.GetMainTaskModel(workspaceId)
 
 	// 如果更新了目标，校验目标格式
@@ -1499,3 +1552,14 @@ func getMainTaskIdFromLog(taskId string) string {
 	}
 	return taskId
 }
+
+// canManageTask 按业务逻辑实际操作的工作空间再次校验任务管理权限，
+// 防止请求头与请求体的工作空间不一致，或在全部空间中查找到无权管理的任务
+func canManageTask(ctx context.Context, workspaceId string) bool {
+	return middleware.AuthorizeWorkspace(ctx, workspaceId, model.PermTaskManage)
+}
+
+// canDeleteTask 按任务实际所在的工作空间再次校验任务删除权限
+func canDeleteTask(ctx context.Context, workspaceId string) bool {
+	return middleware.AuthorizeWorkspace(ctx, workspaceId, model.PermTaskDelete)
+}
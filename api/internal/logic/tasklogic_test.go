@@ -0,0 +1,55 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"cscan/api/internal/middleware"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+)
+
+// withTaskManager 模拟权限中间件：只允许管理 allowed 工作空间的任务
+func withTaskManager(allowed string) context.Context {
+	return context.WithValue(context.Background(), middleware.AuthorizerKey, middleware.WorkspaceAuthorizer(
+		func(ctx context.Context, workspaceId string, perm model.Permission) bool {
+			return workspaceId == allowed && perm == model.PermTaskManage
+		}))
+}
+
+func TestMainTaskCreateRejectsBodyWorkspaceMismatch(t *testing.T) {
+	l := NewMainTaskCreateLogic(withTaskManager("ws-b"), &svc.ServiceContext{})
+
+	resp, err := l.MainTaskCreate(&types.MainTaskCreateReq{Name: "t", Target: "example.com", WorkspaceId: "ws-a"}, "ws-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Code != 403 {
+		t.Fatalf("code = %d, want 403", resp.Code)
+	}
+}
+
+func TestMainTaskDeleteRejectsBodyWorkspaceMismatch(t *testing.T) {
+	l := NewMainTaskDeleteLogic(withTaskManager("ws-b"), &svc.ServiceContext{})
+
+	resp, err := l.MainTaskDelete(&types.MainTaskDeleteReq{Id: "x", WorkspaceId: "ws-a"}, "ws-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Code != 403 {
+		t.Fatalf("code = %d, want 403", resp.Code)
+	}
+}
+
+func TestMainTaskLogicWithoutAuthorizerIsDenied(t *testing.T) {
+	l := NewMainTaskUpdateLogic(context.Background(), &svc.ServiceContext{})
+
+	resp, err := l.MainTaskUpdate(&types.MainTaskUpdateReq{Id: "x"}, "ws-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Code != 403 {
+		t.Fatalf("code = %d, want 403", resp.Code)
+	}
+}
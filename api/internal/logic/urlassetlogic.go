@@ -0,0 +1,99 @@
+package logic
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"cscan/api/internal/logic/common"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// URLAssetLogic 爬虫URL端点查询
+type URLAssetLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewURLAssetLogic(ctx context.Context, svcCtx *svc.ServiceContext) *URLAssetLogic {
+	return &URLAssetLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// URLAssetList URL端点列表，多个工作空间按顺序拼接后分页
+func (l *URLAssetLogic) URLAssetList(req *types.URLAssetListReq, workspaceId string) (*types.URLAssetListResp, error) {
+	if req.WorkspaceId != "" {
+		workspaceId = req.WorkspaceId
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 50
+	}
+
+	filter := bson.M{}
+	if req.Authority != "" {
+		filter["authority"] = strings.TrimSpace(req.Authority)
+	}
+	if req.Type != "" {
+		filter["type"] = strings.ToLower(req.Type)
+	}
+	if req.Keyword != "" {
+		filter["url"] = bson.M{"$regex": regexp.QuoteMeta(req.Keyword), "$options": "i"}
+	}
+
+	var total int64
+	skip := int64((req.Page - 1) * req.PageSize)
+	remaining := int64(req.PageSize)
+	list := []types.URLAsset{}
+	for _, wsId := range common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId) {
+		m := l.svcCtx.GetURLAssetModel(wsId)
+		count, err := m.Count(l.ctx, filter)
+		if err != nil {
+			l.Errorf("查询URL端点失败: %v", err)
+			return &types.URLAssetListResp{Code: 500, Msg: "查询失败"}, nil
+		}
+		total += count
+		if remaining <= 0 || count == 0 {
+			continue
+		}
+		if skip >= count {
+			skip -= count
+			continue
+		}
+		docs, err := m.FindRange(l.ctx, filter, skip, remaining)
+		if err != nil {
+			l.Errorf("查询URL端点失败: %v", err)
+			return &types.URLAssetListResp{Code: 500, Msg: "查询失败"}, nil
+		}
+		skip = 0
+		remaining -= int64(len(docs))
+		for _, doc := range docs {
+			list = append(list, types.URLAsset{
+				Id:          doc.Id.Hex(),
+				Authority:   doc.Authority,
+				URL:         doc.URL,
+				Path:        doc.Path,
+				Method:      doc.Method,
+				Type:        doc.Type,
+				Params:      doc.Params,
+				StatusCode:  doc.StatusCode,
+				ContentType: doc.ContentType,
+				Source:      doc.Source,
+				FirstSeen:   doc.FirstSeen.Local().Format("2006-01-02 15:04:05"),
+				LastSeen:    doc.LastSeen.Local().Format("2006-01-02 15:04:05"),
+			})
+		}
+	}
+
+	return &types.URLAssetListResp{Code: 0, Msg: "success", Total: total, List: list}, nil
+}
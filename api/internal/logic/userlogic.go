@@ -44,9 +44,11 @@ func (l *UserListLogic) UserList(req *types.PageReq) (resp *types.UserListResp,
 	list := make([]types.UserInfo, 0, len(users))
 	for _, u := range users {
 		list = append(list, types.UserInfo{
-			Id:       u.Id.Hex(),
-			Username: u.Username,
-			Status:   u.Status,
+			Id:             u.Id.Hex(),
+			Username:       u.Username,
+			Status:         u.Status,
+			Role:           u.RoleFor(""),
+			WorkspaceRoles: u.WorkspaceRoles,
 		})
 	}
 
@@ -84,11 +86,22 @@ func (l *UserCreateLogic) UserCreate(req *types.UserCreateReq) (resp *types.Base
 		return &types.BaseResp{Code: 400, Msg: "用户名已存在"}, nil
 	}
 
+	// 新用户默认只读，需要管理员显式授权
+	role := req.Role
+	if role == "" {
+		role = model.RoleViewer
+	}
+	if msg := validateRoles(role, req.WorkspaceRoles); msg != "" {
+		return &types.BaseResp{Code: 400, Msg: msg}, nil
+	}
+
 	// 创建用户
 	user := &model.User{
-		Username: req.Username,
-		Password: req.Password, // 在model层会自动MD5加密
-		Status:   req.Status,
+		Username:       req.Username,
+		Password:       req.Password, // 在model层会自动MD5加密
+		Status:         req.Status,
+		Role:           role,
+		WorkspaceRoles: req.WorkspaceRoles,
 	}
 
 	err = l.svcCtx.UserModel.Insert(l.ctx, user)
@@ -138,12 +151,22 @@ func (l *UserUpdateLogic) UserUpdate(req *types.UserUpdateReq) (resp *types.Base
 		}
 	}
 
+	if msg := validateRoles(req.Role, req.WorkspaceRoles); msg != "" {
+		return &types.BaseResp{Code: 400, Msg: msg}, nil
+	}
+
 	// 更新用户信息
 	updateData := bson.M{
 		"username": req.Username,
 		"status":   req.Status,
 		"update_time": time.Now(),
 	}
+	if req.Role != "" {
+		updateData["role"] = req.Role
+	}
+	if req.WorkspaceRoles != nil {
+		updateData["workspace_roles"] = req.WorkspaceRoles
+	}
 
 	err = l.svcCtx.UserModel.UpdateById(l.ctx, req.Id, updateData)
 	if err != nil {
@@ -154,6 +177,19 @@ func (l *UserUpdateLogic) UserUpdate(req *types.UserUpdateReq) (resp *types.Base
 	return &types.BaseResp{Code: 0, Msg: "更新成功"}, nil
 }
 
+// validateRoles 校验全局角色和工作空间角色，返回错误信息
+func validateRoles(role string, workspaceRoles map[string]string) string {
+	if role != "" && !model.IsValidRole(role) {
+		return "无效的角色: " + role
+	}
+	for wsId, wsRole := range workspaceRoles {
+		if !model.IsValidRole(wsRole) {
+			return "工作空间 " + wsId + " 的角色无效: " + wsRole
+		}
+	}
+	return ""
+}
+
 // UserDeleteLogic 删除用户逻辑
 type UserDeleteLogic struct {
 	logx.Logger
@@ -253,6 +289,10 @@ func (l *ScanConfigLogic) SaveScanConfig(r *http.Request, req *types.SaveScanCon
 	if userId == "" {
 		return &types.BaseResp{Code: 401, Msg: "未登录"}, nil
 	}
+	// 扫描配置属于用户个人设置，只读Token也能访问本接口，不允许用Token修改
+	if _, ok := middleware.GetApiTokenScopes(r.Context()); ok {
+		return &types.BaseResp{Code: 403, Msg: "不能使用API Token修改扫描配置"}, nil
+	}
 
 	err = l.svcCtx.UserModel.UpdateScanConfig(l.ctx, userId, req.Config)
 	if err != nil {
@@ -9,12 +9,14 @@ import (
 	"time"
 
 	"cscan/api/internal/logic/common"
+	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type VulListLogic struct {
@@ -34,16 +36,13 @@ func NewVulListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VulListLo
 func (l *VulListLogic) VulList(req *types.VulListReq, workspaceId string) (resp *types.VulListResp, err error) {
 	// 构建查询条件
 	filter := bson.M{}
-	// 如果提供了通用 Query 且未显式指定 Authority/Host，则按多个字段模糊匹配
-	if req.Query != "" && req.Authority == "" && req.Host == "" {
-		q := regexp.QuoteMeta(req.Query)
-		filter["$or"] = []bson.M{
-			{"authority": bson.M{"$regex": q, "$options": "i"}},
-			{"host": bson.M{"$regex": q, "$options": "i"}},
-			{"url": bson.M{"$regex": q, "$options": "i"}},
-			{"pocfile": bson.M{"$regex": q, "$options": "i"}},
-			{"vul_name": bson.M{"$regex": q, "$options": "i"}},
-		}
+	// 查询语法（关键词模糊匹配 authority/host/url/pocfile/vul_name）
+	queryFilter, err := common.CompileQuery(common.VulQuerySchema, req.Query)
+	if err != nil {
+		return &types.VulListResp{Code: 400, Msg: err.Error()}, nil
+	}
+	if queryFilter != nil {
+		filter["$and"] = []bson.M{queryFilter}
 	}
 	if req.Authority != "" {
 		authQuery := req.Authority
@@ -68,6 +67,18 @@ func (l *VulListLogic) VulList(req *types.VulListReq, workspaceId string) (resp
 	if req.Port > 0 {
 		filter["port"] = req.Port
 	}
+	if req.Status != "" {
+		filter["status"] = model.VulStatusFilter(req.Status)
+	}
+	if req.Assignee != "" {
+		filter["assignee"] = req.Assignee
+	}
+	if req.Overdue {
+		filter["due_time"] = bson.M{"$lt": time.Now()}
+		if req.Status == "" {
+			filter["status"] = bson.M{"$in": model.VulOpenStatuses}
+		}
+	}
 
 	var total int64
 	var vuls []model.Vul
@@ -142,6 +153,7 @@ func (l *VulListLogic) VulList(req *types.VulListReq, workspaceId string) (resp
 		if !v.LastSeenTime.IsZero() {
 			vul.LastSeenTime = v.LastSeenTime.Local().Format("2006-01-02 15:04:05")
 		}
+		vul.Status, vul.Assignee, vul.DueTime, vul.Overdue = convertVulTriage(&v)
 		list = append(list, vul)
 	}
 
@@ -153,6 +165,22 @@ func (l *VulListLogic) VulList(req *types.VulListReq, workspaceId string) (resp
 	}, nil
 }
 
+// convertVulTriage 转换处置字段，历史数据没有状态时视为 new
+func convertVulTriage(v *model.Vul) (status, assignee, dueTime string, overdue bool) {
+	status = v.Status
+	if status == "" {
+		status = model.VulStatusNew
+	}
+	if !v.DueTime.IsZero() {
+		dueTime = v.DueTime.Local().Format("2006-01-02 15:04:05")
+		switch status {
+		case model.VulStatusNew, model.VulStatusConfirmed, model.VulStatusReopened:
+			overdue = time.Now().After(v.DueTime)
+		}
+	}
+	return status, v.Assignee, dueTime, overdue
+}
+
 // VulLogic 漏洞管理逻辑
 type VulLogic struct {
 	logx.Logger
@@ -232,6 +260,105 @@ func (l *VulLogic) VulClear(workspaceId string) (resp *types.BaseResp, err error
 	return &types.BaseResp{Code: 0, Msg: "成功清空 " + strconv.FormatInt(totalDeleted, 10) + " 条漏洞"}, nil
 }
 
+// vulModels 返回需要操作的漏洞模型，全部空间模式下按工作空间逐个查找
+func (l *VulLogic) vulModels(workspaceId string) []*model.VulModel {
+	if workspaceId != "" && workspaceId != "all" {
+		return []*model.VulModel{l.svcCtx.GetVulModel(workspaceId)}
+	}
+	wsIds := common.GetWorkspaceIds(l.ctx, l.svcCtx, "all")
+	models := make([]*model.VulModel, 0, len(wsIds))
+	for _, wsId := range wsIds {
+		models = append(models, l.svcCtx.GetVulModel(wsId))
+	}
+	return models
+}
+
+// eachVul 对每个漏洞ID执行操作，返回成功数量；漏洞不存在的工作空间会被跳过
+func (l *VulLogic) eachVul(ids []string, workspaceId string, fn func(vulModel *model.VulModel, id string) error) (int, error) {
+	models := l.vulModels(workspaceId)
+	var done int
+	for _, id := range ids {
+		for _, vulModel := range models {
+			err := fn(vulModel, id)
+			if err == mongo.ErrNoDocuments {
+				continue
+			}
+			if err != nil {
+				return done, err
+			}
+			done++
+			break
+		}
+	}
+	return done, nil
+}
+
+// VulTriage 变更漏洞处置状态
+func (l *VulLogic) VulTriage(req *types.VulTriageReq, workspaceId string) (resp *types.BaseResp, err error) {
+	if len(req.Ids) == 0 {
+		return &types.BaseResp{Code: 400, Msg: "请选择漏洞"}, nil
+	}
+	if !model.IsValidVulStatus(req.Status) {
+		return &types.BaseResp{Code: 400, Msg: "无效的处置状态: " + req.Status}, nil
+	}
+	operator := middleware.GetUsername(l.ctx)
+	var changed int
+	_, err = l.eachVul(req.Ids, workspaceId, func(vulModel *model.VulModel, id string) error {
+		ok, err := vulModel.UpdateStatus(l.ctx, id, req.Status, operator, req.Comment)
+		if ok {
+			changed++
+		}
+		return err
+	})
+	if err != nil {
+		return &types.BaseResp{Code: 500, Msg: "处置失败: " + err.Error()}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "成功更新 " + strconv.Itoa(changed) + " 条漏洞状态"}, nil
+}
+
+// VulAssign 指派漏洞负责人
+func (l *VulLogic) VulAssign(req *types.VulAssignReq, workspaceId string) (resp *types.BaseResp, err error) {
+	if len(req.Ids) == 0 {
+		return &types.BaseResp{Code: 400, Msg: "请选择漏洞"}, nil
+	}
+	var dueTime time.Time
+	if req.DueTime != "" {
+		dueTime, err = time.ParseInLocation("2006-01-02", req.DueTime, time.Local)
+		if err != nil {
+			return &types.BaseResp{Code: 400, Msg: "截止日期格式错误，应为 YYYY-MM-DD"}, nil
+		}
+		// 截止到当天结束
+		dueTime = dueTime.Add(24*time.Hour - time.Second)
+	}
+	operator := middleware.GetUsername(l.ctx)
+	assigned, err := l.eachVul(req.Ids, workspaceId, func(vulModel *model.VulModel, id string) error {
+		return vulModel.Assign(l.ctx, id, req.Assignee, operator, dueTime)
+	})
+	if err != nil {
+		return &types.BaseResp{Code: 500, Msg: "指派失败: " + err.Error()}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "成功指派 " + strconv.Itoa(assigned) + " 条漏洞"}, nil
+}
+
+// VulComment 添加漏洞评论
+func (l *VulLogic) VulComment(req *types.VulCommentReq, workspaceId string) (resp *types.BaseResp, err error) {
+	content := strings.TrimSpace(req.Content)
+	if req.Id == "" || content == "" {
+		return &types.BaseResp{Code: 400, Msg: "漏洞ID和评论内容不能为空"}, nil
+	}
+	operator := middleware.GetUsername(l.ctx)
+	count, err := l.eachVul([]string{req.Id}, workspaceId, func(vulModel *model.VulModel, id string) error {
+		return vulModel.AddComment(l.ctx, id, operator, content)
+	})
+	if err != nil {
+		return &types.BaseResp{Code: 500, Msg: "评论失败: " + err.Error()}, nil
+	}
+	if count == 0 {
+		return &types.BaseResp{Code: 404, Msg: "漏洞不存在"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "评论成功"}, nil
+}
+
 // VulStatLogic 漏洞统计逻辑
 type VulStatLogic struct {
 	logx.Logger
@@ -357,6 +484,20 @@ func (l *VulDetailLogic) VulDetail(req *types.VulDetailReq, workspaceId string)
 		detail.LastSeenTime = vul.LastSeenTime.Local().Format("2006-01-02 15:04:05")
 	}
 
+	// 处置流程
+	detail.Status, detail.Assignee, detail.DueTime, detail.Overdue = convertVulTriage(vul)
+	detail.Activities = make([]types.VulActivity, 0, len(vul.Activities))
+	for _, a := range vul.Activities {
+		detail.Activities = append(detail.Activities, types.VulActivity{
+			Type:     a.Type,
+			From:     a.From,
+			To:       a.To,
+			Content:  a.Content,
+			Operator: a.Operator,
+			Time:     a.Time.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+
 	// 证据链
 	if vul.MatcherName != "" || len(vul.ExtractedResults) > 0 || vul.CurlCommand != "" || vul.Request != "" || vul.Response != "" {
 		detail.Evidence = &types.VulEvidence{
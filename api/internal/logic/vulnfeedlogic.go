@@ -0,0 +1,104 @@
+package logic
+
+import (
+	"context"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/pkg/vulnfeed"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// VulnFeedLogic 离线漏洞情报库管理
+type VulnFeedLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewVulnFeedLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VulnFeedLogic {
+	return &VulnFeedLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// VulnFeedImport 导入 NVD/OSV 离线情报，同一数据源下编号相同的条目会被覆盖
+func (l *VulnFeedLogic) VulnFeedImport(req *types.VulnFeedImportReq, fileName string, content []byte) (*types.VulnFeedImportResp, error) {
+	source := strings.ToLower(strings.TrimSpace(req.Source))
+	if source != "" && source != vulnfeed.SourceNVD && source != vulnfeed.SourceOSV {
+		return &types.VulnFeedImportResp{Code: 400, Msg: "不支持的数据源，仅支持 nvd 和 osv"}, nil
+	}
+
+	entries, err := vulnfeed.Parse(fileName, content)
+	if err != nil {
+		return &types.VulnFeedImportResp{Code: 400, Msg: "情报文件解析失败: " + err.Error()}, nil
+	}
+	if source != "" {
+		filtered := entries[:0]
+		for _, e := range entries {
+			if e.Source == source {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+	if len(entries) == 0 {
+		return &types.VulnFeedImportResp{Code: 400, Msg: "情报文件中没有可导入的条目"}, nil
+	}
+
+	inserted, updated, err := l.svcCtx.VulnFeedModel.BulkUpsert(l.ctx, entries)
+	if err != nil {
+		l.Errorf("导入漏洞情报失败: %v", err)
+		return &types.VulnFeedImportResp{Code: 500, Msg: "导入失败"}, nil
+	}
+	l.Infof("导入漏洞情报 %s: 共%d条, 新增%d条, 更新%d条", fileName, len(entries), inserted, updated)
+
+	return &types.VulnFeedImportResp{
+		Code:     0,
+		Msg:      "导入成功",
+		Total:    len(entries),
+		Inserted: inserted,
+		Updated:  updated,
+	}, nil
+}
+
+// VulnFeedStats 情报库统计
+func (l *VulnFeedLogic) VulnFeedStats() (*types.VulnFeedStatsResp, error) {
+	stats, err := l.svcCtx.VulnFeedModel.Stats(l.ctx)
+	if err != nil {
+		l.Errorf("统计漏洞情报失败: %v", err)
+		return &types.VulnFeedStatsResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	resp := &types.VulnFeedStatsResp{
+		Code:       0,
+		Msg:        "success",
+		Total:      stats.Total,
+		BySource:   stats.BySource,
+		BySeverity: stats.BySeverity,
+	}
+	if stats.LastUpdate != nil {
+		resp.LastUpdate = stats.LastUpdate.Local().Format("2006-01-02 15:04:05")
+	}
+	return resp, nil
+}
+
+// VulnFeedClear 清空指定数据源的情报，已关联到资产的潜在漏洞在下次扫描时刷新
+func (l *VulnFeedLogic) VulnFeedClear(req *types.VulnFeedClearReq) (*types.BaseResp, error) {
+	source := strings.ToLower(strings.TrimSpace(req.Source))
+	if source != "" && source != vulnfeed.SourceNVD && source != vulnfeed.SourceOSV {
+		return &types.BaseResp{Code: 400, Msg: "不支持的数据源，仅支持 nvd 和 osv"}, nil
+	}
+
+	deleted, err := l.svcCtx.VulnFeedModel.DeleteBySource(l.ctx, source)
+	if err != nil {
+		l.Errorf("清空漏洞情报失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "清空失败"}, nil
+	}
+	l.Infof("清空漏洞情报 source=%q: %d条", source, deleted)
+	return &types.BaseResp{Code: 0, Msg: "清空成功"}, nil
+}
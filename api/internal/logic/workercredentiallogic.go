@@ -0,0 +1,133 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// WorkerCredentialLogic Worker凭证管理
+type WorkerCredentialLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewWorkerCredentialLogic(ctx context.Context, svcCtx *svc.ServiceContext) *WorkerCredentialLogic {
+	return &WorkerCredentialLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// workerReenrollWindow 管理员批准重新注册后的有效期
+const workerReenrollWindow = 30 * time.Minute
+
+func convertWorkerCredential(c *model.WorkerCredential) *types.WorkerCredential {
+	item := &types.WorkerCredential{
+		Id:           c.Id.Hex(),
+		WorkerName:   c.WorkerName,
+		TokenHint:    c.TokenHint,
+		IP:           c.IP,
+		OS:           c.OS,
+		Arch:         c.Arch,
+		Revoked:      c.Revoked,
+		RevokeReason: c.RevokeReason,
+		LastUsedIp:   c.LastUsedIP,
+		CreateTime:   c.CreateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+	if c.RevokeTime != nil {
+		item.RevokeTime = c.RevokeTime.Local().Format("2006-01-02 15:04:05")
+	}
+	if c.LastUsedTime != nil {
+		item.LastUsedTime = c.LastUsedTime.Local().Format("2006-01-02 15:04:05")
+	}
+	if !c.Revoked && c.ReenrollApproved(time.Now()) {
+		item.ReenrollUntil = c.ReenrollUntil.Local().Format("2006-01-02 15:04:05")
+	}
+	return item
+}
+
+// WorkerCredentialList 全部Worker凭证，包含已吊销的记录
+func (l *WorkerCredentialLogic) WorkerCredentialList() (*types.WorkerCredentialListResp, error) {
+	creds, err := l.svcCtx.WorkerCredentialModel.FindAll(l.ctx)
+	if err != nil {
+		l.Errorf("查询Worker凭证失败: %v", err)
+		return &types.WorkerCredentialListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.WorkerCredential, 0, len(creds))
+	for i := range creds {
+		item := convertWorkerCredential(&creds[i])
+		if !item.Revoked {
+			n, _ := l.svcCtx.RedisClient.Exists(l.ctx, fmt.Sprintf("cscan:worker:%s", item.WorkerName)).Result()
+			item.Online = n > 0
+		}
+		list = append(list, *item)
+	}
+	return &types.WorkerCredentialListResp{Code: 0, Msg: "success", List: list}, nil
+}
+
+// WorkerCredentialRevoke 吊销Worker凭证并断开该Worker的WebSocket连接
+// 吊销后该Worker的结果上报、心跳和终端等请求都会被拒绝，需要重新注册才能恢复
+func (l *WorkerCredentialLogic) WorkerCredentialRevoke(req *types.WorkerCredentialRevokeReq) (*types.BaseResp, error) {
+	if req.Id == "" {
+		return &types.BaseResp{Code: 400, Msg: "凭证ID不能为空"}, nil
+	}
+	reason := req.Reason
+	if reason == "" {
+		reason = "管理员吊销"
+	}
+
+	cred, err := l.svcCtx.WorkerCredentialModel.Revoke(l.ctx, req.Id, reason)
+	if err != nil {
+		l.Errorf("吊销Worker凭证失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "吊销失败"}, nil
+	}
+	if cred == nil {
+		return &types.BaseResp{Code: 404, Msg: "凭证不存在或已吊销"}, nil
+	}
+
+	publishWorkerCredentialRevoke(l.ctx, l.svcCtx, cred)
+
+	l.Infof("[WorkerCredential] Revoked credential %s of worker %s: %s", cred.Id.Hex(), cred.WorkerName, reason)
+	return &types.BaseResp{Code: 0, Msg: "凭证已吊销，如密钥可能泄露请同时刷新安装密钥"}, nil
+}
+
+// WorkerCredentialApproveReenroll 批准使用同名重新注册替换指定凭证
+// 安装密钥可以重复使用，未经批准时同名注册会被拒绝，防止持有密钥者冒充已有Worker
+func (l *WorkerCredentialLogic) WorkerCredentialApproveReenroll(req *types.WorkerCredentialApproveReq) (*types.BaseResp, error) {
+	if req.Id == "" {
+		return &types.BaseResp{Code: 400, Msg: "凭证ID不能为空"}, nil
+	}
+
+	cred, err := l.svcCtx.WorkerCredentialModel.ApproveReenroll(l.ctx, req.Id, time.Now().Add(workerReenrollWindow))
+	if err != nil {
+		l.Errorf("批准Worker重新注册失败: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "操作失败"}, nil
+	}
+	if cred == nil {
+		return &types.BaseResp{Code: 404, Msg: "凭证不存在或已吊销"}, nil
+	}
+
+	l.Infof("[WorkerCredential] Approved re-enrollment of worker %s until %s", cred.WorkerName, cred.ReenrollUntil.Format(time.RFC3339))
+	return &types.BaseResp{Code: 0, Msg: fmt.Sprintf("已批准，请在%d分钟内重新注册该Worker", int(workerReenrollWindow.Minutes()))}, nil
+}
+
+// publishWorkerCredentialRevoke 通知所有API实例断开使用该凭证的Worker连接
+func publishWorkerCredentialRevoke(ctx context.Context, svcCtx *svc.ServiceContext, cred *model.WorkerCredential) {
+	revokeMsg, _ := json.Marshal(map[string]string{
+		"action":       "revoke",
+		"workerName":   cred.WorkerName,
+		"credentialId": cred.Id.Hex(),
+	})
+	svcCtx.RedisClient.Publish(ctx, "cscan:worker:control", string(revokeMsg))
+}
@@ -10,6 +10,8 @@ import (
 
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/utils"
 
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -86,7 +88,7 @@ func (l *WorkerInstallLogic) GetInstallCommand(req *types.WorkerInstallCommandRe
 				}
 			}
 		}
-		serverAddrClean = fmt.Sprintf("%s:%d", host, apiPort)
+		serverAddrClean = utils.BuildTargetWithPort(host, apiPort)
 	}
 
 	// 生成各平台的安装命令（单端口通信，只需 -k 和 -s 参数）
@@ -196,6 +198,83 @@ func (l *WorkerInstallLogic) ValidateInstallKey(req *types.WorkerValidateKeyReq)
 	}, nil
 }
 
+// EnrollWorker Worker注册，使用安装密钥换取该Worker独立的凭证
+// 同名Worker重新注册需管理员批准，旧凭证被吊销并断开连接，保证每个Worker名称只有一个有效凭证
+func (l *WorkerInstallLogic) EnrollWorker(req *types.WorkerEnrollReq) (*types.WorkerEnrollResp, error) {
+	valid, err := l.ValidateInstallKey(&types.WorkerValidateKeyReq{
+		InstallKey: req.InstallKey,
+		WorkerName: req.WorkerName,
+		WorkerIP:   req.WorkerIP,
+		WorkerOS:   req.WorkerOS,
+		WorkerArch: req.WorkerArch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !valid.Valid {
+		return &types.WorkerEnrollResp{Code: valid.Code, Msg: valid.Msg}, nil
+	}
+	if req.WorkerName == "" {
+		return &types.WorkerEnrollResp{Code: 400, Msg: "Worker名称不能为空"}, nil
+	}
+
+	plain, hash, err := model.GenerateWorkerCredential()
+	if err != nil {
+		l.Logger.Errorf("[WorkerInstall] Generate credential failed: %v", err)
+		return &types.WorkerEnrollResp{Code: 500, Msg: "生成凭证失败"}, nil
+	}
+
+	// 安装密钥可重复使用，同名Worker已有有效凭证时必须经管理员批准才能替换，防止冒充已有Worker
+	existing, err := l.svcCtx.WorkerCredentialModel.FindActiveByWorkerName(l.ctx, req.WorkerName)
+	if err != nil {
+		l.Logger.Errorf("[WorkerInstall] Query credentials of %s failed: %v", req.WorkerName, err)
+		return &types.WorkerEnrollResp{Code: 500, Msg: "注册失败"}, nil
+	}
+	now := time.Now()
+	for i := range existing {
+		if !existing[i].ReenrollApproved(now) {
+			l.Logger.Infof("[WorkerInstall] Rejected re-enrollment of %s from %s: not approved", req.WorkerName, req.WorkerIP)
+			return &types.WorkerEnrollResp{Code: 409, Msg: "该Worker名称已有有效凭证，需管理员批准重新注册或先吊销旧凭证"}, nil
+		}
+	}
+	for i := range existing {
+		cred, err := l.svcCtx.WorkerCredentialModel.Revoke(l.ctx, existing[i].Id.Hex(), "重新注册")
+		if err != nil {
+			l.Logger.Errorf("[WorkerInstall] Revoke previous credential of %s failed: %v", req.WorkerName, err)
+			return &types.WorkerEnrollResp{Code: 500, Msg: "注册失败"}, nil
+		}
+		if cred == nil {
+			// 并发的注册请求已经替换了该凭证
+			return &types.WorkerEnrollResp{Code: 409, Msg: "该Worker正在被重新注册，请稍后重试"}, nil
+		}
+		publishWorkerCredentialRevoke(l.ctx, l.svcCtx, cred)
+		l.Logger.Infof("[WorkerInstall] Replaced credential %s of %s", cred.Id.Hex(), req.WorkerName)
+	}
+
+	doc := &model.WorkerCredential{
+		WorkerName: req.WorkerName,
+		TokenHash:  hash,
+		TokenHint:  plain[:len(model.WorkerCredentialPrefix)+6],
+		IP:         req.WorkerIP,
+		OS:         req.WorkerOS,
+		Arch:       req.WorkerArch,
+	}
+	if err := l.svcCtx.WorkerCredentialModel.Create(l.ctx, doc); err != nil {
+		l.Logger.Errorf("[WorkerInstall] Save credential failed: %v", err)
+		return &types.WorkerEnrollResp{Code: 500, Msg: "注册失败"}, nil
+	}
+
+	l.Logger.Infof("[WorkerInstall] Worker enrolled: name=%s, credential=%s", req.WorkerName, doc.Id.Hex())
+
+	return &types.WorkerEnrollResp{
+		Code:         0,
+		Msg:          "注册成功",
+		CredentialId: doc.Id.Hex(),
+		WorkerName:   doc.WorkerName,
+		Token:        plain,
+	}, nil
+}
+
 // GetWorkerBinaryInfo 获取Worker二进制文件信息
 func (l *WorkerInstallLogic) GetWorkerBinaryInfo(osType, arch string) (*types.WorkerBinaryInfoResp, error) {
 	// 默认值
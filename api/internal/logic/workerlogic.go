@@ -253,7 +253,12 @@ func (l *WorkerRenameLogic) WorkerRename(req *types.WorkerRenameReq) (resp *type
 	rdb.SRem(l.ctx, "cscan:workers", req.OldName)
 	rdb.SAdd(l.ctx, "cscan:workers", req.NewName)
 
-	// 7. 发送重命名命令给Worker（让Worker更新自己的名称）
+	// 7. 凭证绑定的名称同步更新，Worker重连时以凭证记录的名称认证
+	if err := l.svcCtx.WorkerCredentialModel.RenameWorker(l.ctx, req.OldName, req.NewName); err != nil {
+		l.Logger.Errorf("[WorkerRename] Rename worker credential failed: %v", err)
+	}
+
+	// 8. 发送重命名命令给Worker（让Worker更新自己的名称）
 	renameMsg := fmt.Sprintf(`{"action":"rename","workerName":"%s","newName":"%s"}`, req.OldName, req.NewName)
 	rdb.Publish(l.ctx, "cscan:worker:control", renameMsg)
 
@@ -5,26 +5,44 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
+
+	"cscan/model"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ContextKey string
 
 const (
-	UserIdKey      ContextKey = "userId"
-	UsernameKey    ContextKey = "username"
-	RoleKey        ContextKey = "role"
-	WorkspaceIdKey ContextKey = "workspaceId"
+	UserIdKey         ContextKey = "userId"
+	UsernameKey       ContextKey = "username"
+	RoleKey           ContextKey = "role"
+	WorkspaceIdKey    ContextKey = "workspaceId"
+	ApiTokenScopesKey ContextKey = "apiTokenScopes"
+	AuthorizerKey     ContextKey = "workspaceAuthorizer"
 )
 
+// apiTokenTouchInterval 最近使用时间的更新间隔，避免每个请求都写库
+const apiTokenTouchInterval = time.Minute
+
+// ApiTokenStore API Token 查询接口，由 model.ApiTokenModel 实现
+type ApiTokenStore interface {
+	FindActiveByToken(ctx context.Context, token string) (*model.ApiToken, error)
+	Touch(ctx context.Context, id primitive.ObjectID, ip string) error
+}
+
 type AuthMiddleware struct {
-	AccessSecret string
+	AccessSecret  string
+	ApiTokenModel ApiTokenStore
 }
 
-func NewAuthMiddleware(accessSecret string) *AuthMiddleware {
+func NewAuthMiddleware(accessSecret string, apiTokenModel ApiTokenStore) *AuthMiddleware {
 	return &AuthMiddleware{
-		AccessSecret: accessSecret,
+		AccessSecret:  accessSecret,
+		ApiTokenModel: apiTokenModel,
 	}
 }
 
@@ -52,6 +70,12 @@ func (m *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		// API Token（CI/自动化使用）
+		if model.IsApiToken(tokenStr) {
+			m.handleApiToken(w, r, tokenStr, next)
+			return
+		}
+
 		// 验证Token
 		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
 			return []byte(m.AccessSecret), nil
@@ -88,6 +112,51 @@ func (m *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// handleApiToken 校验API Token，只允许访问Token绑定的工作空间
+func (m *AuthMiddleware) handleApiToken(w http.ResponseWriter, r *http.Request, tokenStr string, next http.HandlerFunc) {
+	if m.ApiTokenModel == nil {
+		unauthorized(w, "不支持API Token认证")
+		return
+	}
+	token, err := m.ApiTokenModel.FindActiveByToken(r.Context(), tokenStr)
+	if err != nil {
+		logx.Errorf("[Auth] find api token failed: %v", err)
+		unauthorized(w, "Token校验失败")
+		return
+	}
+	if token == nil {
+		unauthorized(w, "Token无效、已吊销或已过期")
+		return
+	}
+
+	// 请求头、查询参数和请求体中的工作空间都必须与Token绑定的一致，
+	// 业务逻辑会优先使用请求体中的 workspaceId
+	for _, workspaceId := range []string{r.Header.Get("X-Workspace-Id"), RequestWorkspaceId(r)} {
+		if workspaceId != "" && workspaceId != token.WorkspaceId {
+			forbidden(w, "该Token仅限工作空间 "+token.WorkspaceId+" 使用")
+			return
+		}
+	}
+
+	if token.LastUsedTime == nil || time.Since(*token.LastUsedTime) > apiTokenTouchInterval {
+		clientIP := getClientIPFromRequest(r)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := m.ApiTokenModel.Touch(ctx, token.Id, clientIP); err != nil {
+				logx.Errorf("[Auth] update api token last used time failed: %v", err)
+			}
+		}()
+	}
+
+	ctx := r.Context()
+	ctx = context.WithValue(ctx, UserIdKey, token.UserId)
+	ctx = context.WithValue(ctx, UsernameKey, token.Username)
+	ctx = context.WithValue(ctx, WorkspaceIdKey, token.WorkspaceId)
+	ctx = context.WithValue(ctx, ApiTokenScopesKey, token.Scopes)
+	next(w, r.WithContext(ctx))
+}
+
 func unauthorized(w http.ResponseWriter, msg string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusUnauthorized)
@@ -129,11 +198,17 @@ func GetWorkspaceId(ctx context.Context) string {
 	return ""
 }
 
+// GetApiTokenScopes 获取API Token的权限范围，非API Token认证时 ok 为 false
+func GetApiTokenScopes(ctx context.Context) (scopes []string, ok bool) {
+	scopes, ok = ctx.Value(ApiTokenScopesKey).([]string)
+	return scopes, ok
+}
+
 // RequireAdmin 管理员权限中间件，需要先经过认证中间件
 func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		role := GetRole(r.Context())
-		if role != "admin" {
+		if role != model.RoleAdmin {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusForbidden)
 			json.NewEncoder(w).Encode(map[string]interface{}{
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cscan/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memTokenStore 内存中的 API Token 存储，按 model.ApiTokenModel 的规则只返回有效Token
+type memTokenStore map[string]*model.ApiToken
+
+func (s memTokenStore) FindActiveByToken(ctx context.Context, token string) (*model.ApiToken, error) {
+	doc, ok := s[token]
+	if !ok || !doc.Active(time.Now()) {
+		return nil, nil
+	}
+	return doc, nil
+}
+
+func (s memTokenStore) Touch(ctx context.Context, id primitive.ObjectID, ip string) error {
+	return nil
+}
+
+func newTokenTest(t *testing.T) (*AuthMiddleware, *RBACMiddleware) {
+	t.Helper()
+	user := testUser(model.RoleOperator, nil)
+	past := time.Now().Add(-time.Hour)
+	newToken := func(scopes ...string) *model.ApiToken {
+		return &model.ApiToken{Id: primitive.NewObjectID(), UserId: user.Id.Hex(), Username: user.Username, WorkspaceId: "ws-a", Scopes: scopes}
+	}
+
+	store := memTokenStore{
+		"cst_read": newToken(model.ApiTokenScopeRead),
+		"cst_task": newToken(model.ApiTokenScopeTask),
+	}
+	revoked := newToken(model.ApiTokenScopeTask)
+	revoked.Revoked = true
+	store["cst_revoked"] = revoked
+	expired := newToken(model.ApiTokenScopeTask)
+	expired.ExpireTime = &past
+	store["cst_expired"] = expired
+
+	return NewAuthMiddleware("secret", store), newTestRBAC(user)
+}
+
+// serveToken 以 API Token 认证调用 Require 包装后的处理函数
+func serveToken(auth *AuthMiddleware, rbac *RBACMiddleware, perm model.Permission, token, url, headerWs, body string, next http.HandlerFunc) int {
+	r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Authorization", "Bearer "+token)
+	if headerWs != "" {
+		r.Header.Set("X-Workspace-Id", headerWs)
+	}
+	w := httptest.NewRecorder()
+	auth.Handle(rbac.Require(perm, next))(w, r)
+	return w.Code
+}
+
+func TestApiTokenRevokedOrExpired(t *testing.T) {
+	auth, rbac := newTokenTest(t)
+	for _, token := range []string{"cst_revoked", "cst_expired", "cst_unknown"} {
+		if code := serveToken(auth, rbac, model.PermView, token, "/api/v1/test", "", "{}", okHandler); code != http.StatusUnauthorized {
+			t.Errorf("%s: status %d, want 401", token, code)
+		}
+	}
+}
+
+func TestApiTokenScope(t *testing.T) {
+	auth, rbac := newTokenTest(t)
+	if code := serveToken(auth, rbac, model.PermTaskManage, "cst_read", "/api/v1/test", "", "{}", okHandler); code != http.StatusForbidden {
+		t.Errorf("read token managing tasks: status %d, want 403", code)
+	}
+	if code := serveToken(auth, rbac, model.PermTaskManage, "cst_task", "/api/v1/test", "", "{}", okHandler); code != http.StatusOK {
+		t.Errorf("task token managing tasks: status %d, want 200", code)
+	}
+	if code := serveToken(auth, rbac, model.PermAssetDelete, "cst_task", "/api/v1/test", "", "{}", okHandler); code != http.StatusForbidden {
+		t.Errorf("task token deleting assets: status %d, want 403", code)
+	}
+	if code := serveToken(auth, rbac, model.PermTaskDelete, "cst_task", "/api/v1/test", "", "{}", okHandler); code != http.StatusForbidden {
+		t.Errorf("task token deleting tasks: status %d, want 403", code)
+	}
+}
+
+func TestApiTokenWorkspaceBinding(t *testing.T) {
+	auth, rbac := newTokenTest(t)
+
+	cases := []struct {
+		name     string
+		url      string
+		headerWs string
+		body     string
+		want     int
+	}{
+		{"bound workspace", "/api/v1/test", "ws-a", `{"workspaceId":"ws-a"}`, http.StatusOK},
+		{"no workspace uses binding", "/api/v1/test", "", `{}`, http.StatusOK},
+		{"header mismatch", "/api/v1/test", "ws-b", `{}`, http.StatusForbidden},
+		{"body mismatch", "/api/v1/test", "ws-a", `{"workspaceId":"ws-b"}`, http.StatusForbidden},
+		{"query mismatch", "/api/v1/test?workspaceId=ws-b", "", `{}`, http.StatusForbidden},
+		{"all workspaces", "/api/v1/test", "", `{"workspaceId":"all"}`, http.StatusForbidden},
+	}
+	for _, c := range cases {
+		var gotWs string
+		code := serveToken(auth, rbac, model.PermTaskManage, "cst_task", c.url, c.headerWs, c.body, func(w http.ResponseWriter, r *http.Request) {
+			gotWs = GetWorkspaceId(r.Context())
+		})
+		if code != c.want {
+			t.Errorf("%s: status %d, want %d", c.name, code, c.want)
+		}
+		if code == http.StatusOK && gotWs != "ws-a" {
+			t.Errorf("%s: workspace %q, want ws-a", c.name, gotWs)
+		}
+	}
+
+	// 业务逻辑在其他工作空间中找到的对象也不能被Token操作
+	var inA, inB bool
+	serveToken(auth, rbac, model.PermTaskManage, "cst_task", "/api/v1/test", "", "{}", func(w http.ResponseWriter, r *http.Request) {
+		inA = AuthorizeWorkspace(r.Context(), "ws-a", model.PermTaskManage)
+		inB = AuthorizeWorkspace(r.Context(), "ws-b", model.PermTaskManage)
+	})
+	if !inA || inB {
+		t.Errorf("AuthorizeWorkspace ws-a=%v ws-b=%v, want true/false", inA, inB)
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"net/http"
 	"time"
 
+	"cscan/model"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
-// ConsoleAuthMiddleware 控制台权限中间件（仅管理员可访问）
+// ConsoleAuthMiddleware 控制台权限中间件（需要 worker:console 权限）
 type ConsoleAuthMiddleware struct {
 	RedisClient *redis.Client
 }
@@ -25,11 +27,11 @@ func NewConsoleAuthMiddleware(redisClient *redis.Client) *ConsoleAuthMiddleware
 // Handle 控制台权限检查处理
 func (m *ConsoleAuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// 从Context获取用户角色（需要先经过AuthMiddleware）
+		// 从Context获取用户角色（需要先经过AuthMiddleware/RBACMiddleware）
 		role := GetRole(r.Context())
-		if role != "admin" {
-			logx.Errorf("[ConsoleAuth] Access denied for non-admin user, role: %s, path: %s", role, r.URL.Path)
-			consoleForbidden(w, "需要管理员权限访问控制台")
+		if !model.HasPermission(role, model.PermWorkerConsole) {
+			logx.Errorf("[ConsoleAuth] Access denied for role without console permission, role: %s, path: %s", role, r.URL.Path)
+			consoleForbidden(w, "没有访问控制台的权限")
 			return
 		}
 
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"cscan/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// roleCacheTTL 用户角色缓存时间，修改角色后最迟在该时间后生效
+const roleCacheTTL = 30 * time.Second
+
+type cachedUser struct {
+	user     *model.User
+	expireAt time.Time
+}
+
+// RBACMiddleware 基于工作空间角色的权限中间件，需要先经过认证中间件
+type RBACMiddleware struct {
+	UserModel *model.UserModel
+
+	mu    sync.Mutex
+	cache map[string]cachedUser
+}
+
+// NewRBACMiddleware 创建权限中间件
+func NewRBACMiddleware(userModel *model.UserModel) *RBACMiddleware {
+	return &RBACMiddleware{
+		UserModel: userModel,
+		cache:     make(map[string]cachedUser),
+	}
+}
+
+// Require 要求当前用户在请求实际操作的工作空间拥有指定权限。
+// 请求体或查询参数中的 workspaceId 优先于请求头，与业务逻辑选择工作空间的方式一致；
+// 全局权限只按用户的全局角色判断。
+// 通过后将鉴权的工作空间、生效角色和工作空间鉴权函数写入 Context，
+// 从 Context 读取工作空间的处理函数只会操作已鉴权的工作空间
+func (m *RBACMiddleware) Require(perm model.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		userId := GetUserId(ctx)
+		workspaceId := GetWorkspaceId(ctx)
+		if reqWorkspaceId := RequestWorkspaceId(r); reqWorkspaceId != "" {
+			workspaceId = reqWorkspaceId
+		}
+
+		role, err := m.ResolveRole(ctx, userId, workspaceId, perm)
+		if err != nil {
+			logx.Errorf("[RBAC] resolve role failed, user=%s: %v", userId, err)
+			forbidden(w, "无法获取用户权限")
+			return
+		}
+		if !model.HasPermission(role, perm) {
+			logx.Infof("[RBAC] permission denied: user=%s role=%s workspace=%s perm=%s path=%s", GetUsername(ctx), role, workspaceId, perm, r.URL.Path)
+			forbidden(w, "权限不足，需要 "+string(perm)+" 权限")
+			return
+		}
+		// API Token 还受自身权限范围限制
+		if scopes, ok := GetApiTokenScopes(ctx); ok && !model.ApiTokenScopeAllows(scopes, perm) {
+			forbidden(w, "API Token 权限范围不足，需要 "+string(perm)+" 权限")
+			return
+		}
+
+		// API Token 只能访问绑定的工作空间，认证中间件已把它写入 Context
+		tokenScopes, isToken := GetApiTokenScopes(ctx)
+		tokenWorkspaceId := GetWorkspaceId(ctx)
+
+		ctx = context.WithValue(ctx, WorkspaceIdKey, workspaceId)
+		ctx = context.WithValue(ctx, RoleKey, role)
+		ctx = context.WithValue(ctx, AuthorizerKey, WorkspaceAuthorizer(func(ctx context.Context, workspaceId string, perm model.Permission) bool {
+			if isToken && (workspaceId != tokenWorkspaceId || !model.ApiTokenScopeAllows(tokenScopes, perm)) {
+				return false
+			}
+			role, err := m.ResolveRole(ctx, userId, workspaceId, perm)
+			if err != nil {
+				logx.Errorf("[RBAC] resolve role failed, user=%s: %v", userId, err)
+				return false
+			}
+			return model.HasPermission(role, perm)
+		}))
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// ResolveRole 查询判断权限时用户的生效角色：全局权限使用全局角色，其余使用工作空间角色。
+// 用户不存在或已禁用时返回空角色
+func (m *RBACMiddleware) ResolveRole(ctx context.Context, userId, workspaceId string, perm model.Permission) (string, error) {
+	if userId == "" {
+		return "", nil
+	}
+	user, err := m.loadUser(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	if user == nil || user.Status != model.StatusEnable {
+		return "", nil
+	}
+	return user.RoleForPermission(workspaceId, perm), nil
+}
+
+// WorkspaceAuthorizer 判断当前用户在指定工作空间下是否拥有权限
+type WorkspaceAuthorizer func(ctx context.Context, workspaceId string, perm model.Permission) bool
+
+// AuthorizeWorkspace 业务逻辑确定实际操作的工作空间后（如在全部空间中查找任务）再次鉴权。
+// 未经过权限中间件的请求一律拒绝
+func AuthorizeWorkspace(ctx context.Context, workspaceId string, perm model.Permission) bool {
+	authorize, ok := ctx.Value(AuthorizerKey).(WorkspaceAuthorizer)
+	if !ok {
+		return false
+	}
+	return authorize(ctx, workspaceId, perm)
+}
+
+// RequestWorkspaceId 返回查询参数或 JSON 请求体中的 workspaceId，读取后恢复请求体
+func RequestWorkspaceId(r *http.Request) string {
+	if ws := r.URL.Query().Get("workspaceId"); ws != "" {
+		return ws
+	}
+	if r.Body == nil || r.Body == http.NoBody || !strings.Contains(r.Header.Get("Content-Type"), "json") {
+		return ""
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var req struct {
+		WorkspaceId string `json:"workspaceId"`
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return ""
+	}
+	return req.WorkspaceId
+}
+
+func (m *RBACMiddleware) loadUser(ctx context.Context, userId string) (*model.User, error) {
+	now := time.Now()
+	m.mu.Lock()
+	if c, ok := m.cache[userId]; ok && now.Before(c.expireAt) {
+		m.mu.Unlock()
+		return c.user, nil
+	}
+	m.mu.Unlock()
+
+	user, err := m.UserModel.FindById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.cache[userId] = cachedUser{user: user, expireAt: now.Add(roleCacheTTL)}
+	m.mu.Unlock()
+	return user, nil
+}
+
+func forbidden(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code": 403,
+		"msg":  msg,
+	})
+}
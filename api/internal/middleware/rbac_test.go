@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cscan/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTestRBAC 创建使用预置用户缓存的权限中间件，不访问数据库
+func newTestRBAC(users ...*model.User) *RBACMiddleware {
+	m := NewRBACMiddleware(nil)
+	for _, u := range users {
+		if u.Status == "" {
+			u.Status = model.StatusEnable
+		}
+		m.cache[u.Id.Hex()] = cachedUser{user: u, expireAt: time.Now().Add(time.Hour)}
+	}
+	return m
+}
+
+func testUser(role string, workspaceRoles map[string]string) *model.User {
+	return &model.User{Id: primitive.NewObjectID(), Username: "tester", Role: role, WorkspaceRoles: workspaceRoles}
+}
+
+// serve 以指定用户和请求头工作空间调用 Require 包装后的处理函数
+func serve(m *RBACMiddleware, perm model.Permission, user *model.User, headerWs, body string, next http.HandlerFunc) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/test", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(r.Context(), UserIdKey, user.Id.Hex())
+	ctx = context.WithValue(ctx, WorkspaceIdKey, headerWs)
+	w := httptest.NewRecorder()
+	m.Require(perm, next)(w, r.WithContext(ctx))
+	return w
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+func TestRequireGlobalPermissionIgnoresWorkspaceRole(t *testing.T) {
+	user := testUser(model.RoleViewer, map[string]string{"ws-a": model.RoleAdmin})
+	m := newTestRBAC(user)
+
+	for _, perm := range []model.Permission{model.PermUserManage, model.PermSettings, model.PermWorkerConsole, model.PermWorkerManage} {
+		if w := serve(m, perm, user, "ws-a", "{}", okHandler); w.Code != http.StatusForbidden {
+			t.Errorf("%s via workspace admin role: status %d, want 403", perm, w.Code)
+		}
+	}
+	if w := serve(m, model.PermTaskManage, user, "ws-a", "{}", okHandler); w.Code != http.StatusOK {
+		t.Errorf("workspace permission in admin workspace: status %d, want 200", w.Code)
+	}
+}
+
+func TestRequireChecksBodyWorkspace(t *testing.T) {
+	user := testUser(model.RoleViewer, map[string]string{"ws-b": model.RoleOperator})
+	m := newTestRBAC(user)
+
+	// 请求头是有权限的工作空间，请求体指向只读的工作空间
+	w := serve(m, model.PermTaskManage, user, "ws-b", `{"workspaceId":"ws-a","name":"t"}`, okHandler)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("header ws-b body ws-a: status %d, want 403", w.Code)
+	}
+
+	// 反过来，请求体中的工作空间有权限时放行，请求体可以被后续处理函数完整读取，
+	// 且处理函数从 Context 读到的是已鉴权的请求体工作空间而不是请求头
+	body := `{"workspaceId":"ws-b","name":"t"}`
+	var got, gotWs string
+	w = serve(m, model.PermTaskManage, user, "ws-a", body, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = string(b)
+		gotWs = GetWorkspaceId(r.Context())
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("header ws-a body ws-b: status %d, want 200", w.Code)
+	}
+	if got != body {
+		t.Errorf("body not restored: %q", got)
+	}
+	if gotWs != "ws-b" {
+		t.Errorf("context workspace = %q, want the authorized ws-b", gotWs)
+	}
+}
+
+func TestRequireAllWorkspacesUsesLowestRole(t *testing.T) {
+	// 全局 operator 在 ws-a 被降级为 viewer，不能通过 all 操作 ws-a 的数据
+	user := testUser(model.RoleOperator, map[string]string{"ws-a": model.RoleViewer})
+	m := newTestRBAC(user)
+
+	for _, perm := range []model.Permission{model.PermAssetDelete, model.PermVulTriage, model.PermTaskManage} {
+		if w := serve(m, perm, user, "all", "{}", okHandler); w.Code != http.StatusForbidden {
+			t.Errorf("%s via all: status %d, want 403", perm, w.Code)
+		}
+		if w := serve(m, perm, user, "", "{}", okHandler); w.Code != http.StatusForbidden {
+			t.Errorf("%s via empty workspace: status %d, want 403", perm, w.Code)
+		}
+	}
+	if w := serve(m, model.PermView, user, "all", "{}", okHandler); w.Code != http.StatusOK {
+		t.Errorf("view via all: status %d, want 200", w.Code)
+	}
+	// POC 等不属于工作空间的数据仍按全局角色判断
+	if w := serve(m, model.PermPocManage, user, "all", "{}", okHandler); w.Code != http.StatusOK {
+		t.Errorf("poc manage via all: status %d, want 200", w.Code)
+	}
+	// 未单独配置的工作空间沿用全局角色
+	if w := serve(m, model.PermTaskManage, user, "ws-b", "{}", okHandler); w.Code != http.StatusOK {
+		t.Errorf("task manage in unlisted workspace: status %d, want 200", w.Code)
+	}
+}
+
+func TestRequireEmptyRoleIsLeastPrivilege(t *testing.T) {
+	user := testUser("", nil)
+	m := newTestRBAC(user)
+
+	if w := serve(m, model.PermAssetDelete, user, "default", "{}", okHandler); w.Code != http.StatusForbidden {
+		t.Errorf("asset delete without role: status %d, want 403", w.Code)
+	}
+	if w := serve(m, model.PermView, user, "default", "{}", okHandler); w.Code != http.StatusOK {
+		t.Errorf("view without role: status %d, want 200", w.Code)
+	}
+}
+
+func TestAuthorizeWorkspace(t *testing.T) {
+	user := testUser(model.RoleOperator, map[string]string{"ws-a": model.RoleViewer})
+	m := newTestRBAC(user)
+
+	var inA, inB bool
+	w := serve(m, model.PermView, user, "all", "{}", func(w http.ResponseWriter, r *http.Request) {
+		inA = AuthorizeWorkspace(r.Context(), "ws-a", model.PermTaskManage)
+		inB = AuthorizeWorkspace(r.Context(), "ws-b", model.PermTaskManage)
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", w.Code)
+	}
+	if inA || !inB {
+		t.Errorf("AuthorizeWorkspace ws-a=%v ws-b=%v, want false/true", inA, inB)
+	}
+
+	if AuthorizeWorkspace(context.Background(), "ws-b", model.PermView) {
+		t.Error("requests without RBAC middleware must be denied")
+	}
+}
@@ -1,65 +1,142 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
+	"time"
+
+	"cscan/model"
 
-	"github.com/redis/go-redis/v9"
 	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // WorkerNameKey Context key for worker name
 const WorkerNameKey ContextKey = "workerName"
 
+// WorkerCredentialIdKey Context key for worker credential id
+const WorkerCredentialIdKey ContextKey = "workerCredentialId"
+
+// workerCredentialTouchInterval 凭证最近使用时间的更新间隔，Worker 请求频繁，避免每个请求都写库
+const workerCredentialTouchInterval = time.Minute
+
+// WorkerCredentialStore Worker凭证查询接口，由 model.WorkerCredentialModel 实现
+type WorkerCredentialStore interface {
+	FindActiveByToken(ctx context.Context, token string) (*model.WorkerCredential, error)
+	Touch(ctx context.Context, id primitive.ObjectID, ip string) error
+}
+
 // WorkerAuthMiddleware Worker认证中间件
+// 安装密钥只用于注册，这里只接受注册后签发的 Worker 凭证，Worker 名称以凭证记录为准
 type WorkerAuthMiddleware struct {
-	RedisClient *redis.Client
+	CredentialModel WorkerCredentialStore
+	// TaskWorker 返回任务被分配到的Worker名称，未知时返回空
+	TaskWorker func(ctx context.Context, taskId string) string
 }
 
 // NewWorkerAuthMiddleware 创建Worker认证中间件
-func NewWorkerAuthMiddleware(redisClient *redis.Client) *WorkerAuthMiddleware {
+func NewWorkerAuthMiddleware(credentialModel WorkerCredentialStore) *WorkerAuthMiddleware {
 	return &WorkerAuthMiddleware{
-		RedisClient: redisClient,
+		CredentialModel: credentialModel,
 	}
 }
 
+// workerBindingFields 请求体中标识Worker和任务的字段
+type workerBindingFields struct {
+	WorkerName string `json:"workerName"`
+	Worker     string `json:"worker"`
+	TaskId     string `json:"taskId"`
+}
+
 // Handle Worker认证处理
 func (m *WorkerAuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// 从请求头获取Install Key
-		installKey := r.Header.Get("X-Worker-Key")
-		if installKey == "" {
-			workerUnauthorized(w, "未提供Worker认证密钥")
-			logx.Errorf("[WorkerAuth] Missing X-Worker-Key header from %s", r.RemoteAddr)
+		// 从请求头获取Worker凭证
+		token := r.Header.Get("X-Worker-Token")
+		if token == "" {
+			if r.Header.Get("X-Worker-Key") != "" {
+				workerUnauthorized(w, "安装密钥仅用于注册，请升级Worker后重新注册")
+				logx.Errorf("[WorkerAuth] Install key used as credential from %s", r.RemoteAddr)
+				return
+			}
+			workerUnauthorized(w, "未提供Worker凭证")
+			logx.Errorf("[WorkerAuth] Missing X-Worker-Token header from %s", r.RemoteAddr)
 			return
 		}
 
-		// 从Redis获取存储的Install Key
-		installKeyKey := "cscan:worker:install_key"
-		storedKey, err := m.RedisClient.Get(r.Context(), installKeyKey).Result()
-		if err != nil || storedKey == "" {
-			workerUnauthorized(w, "服务端未配置安装密钥")
-			logx.Errorf("[WorkerAuth] Install key not configured in Redis")
+		cred, err := m.VerifyCredential(r.Context(), token)
+		if err != nil {
+			workerUnauthorized(w, "Worker凭证校验失败")
+			logx.Errorf("[WorkerAuth] Verify credential failed: %v", err)
 			return
 		}
-
-		// 验证Install Key
-		if installKey != storedKey {
-			workerUnauthorized(w, "Worker认证密钥无效")
-			logx.Errorf("[WorkerAuth] Invalid install key attempt: %s from %s", installKey, r.RemoteAddr)
+		if cred == nil {
+			workerUnauthorized(w, "Worker凭证无效或已吊销")
+			logx.Errorf("[WorkerAuth] Invalid or revoked credential from %s", r.RemoteAddr)
 			return
 		}
 
-		// 可选：从请求头获取Worker名称并存入Context
-		workerName := r.Header.Get("X-Worker-Name")
-		if workerName != "" {
-			ctx := context.WithValue(r.Context(), WorkerNameKey, workerName)
-			r = r.WithContext(ctx)
+		if cred.LastUsedTime == nil || time.Since(*cred.LastUsedTime) > workerCredentialTouchInterval {
+			clientIP := getClientIPFromRequest(r)
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := m.CredentialModel.Touch(ctx, cred.Id, clientIP); err != nil {
+					logx.Errorf("[WorkerAuth] update credential last used time failed: %v", err)
+				}
+			}()
+		}
+
+		if msg := m.checkBinding(r, cred.WorkerName); msg != "" {
+			workerForbidden(w, msg)
+			logx.Errorf("[WorkerAuth] %s, credential worker %s, path %s", msg, cred.WorkerName, r.URL.Path)
+			return
 		}
 
-		next(w, r)
+		ctx := context.WithValue(r.Context(), WorkerNameKey, cred.WorkerName)
+		ctx = context.WithValue(ctx, WorkerCredentialIdKey, cred.Id.Hex())
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// VerifyCredential 校验Worker凭证，凭证无效或已吊销时返回 nil
+func (m *WorkerAuthMiddleware) VerifyCredential(ctx context.Context, token string) (*model.WorkerCredential, error) {
+	if m.CredentialModel == nil || !model.IsWorkerCredential(token) {
+		return nil, nil
 	}
+	return m.CredentialModel.FindActiveByToken(ctx, token)
+}
+
+// checkBinding 凭证只能以绑定的Worker名称上报，且只能上报分配给该Worker的任务，不通过时返回原因
+// 请求体读取后会重新放回，处理函数照常解析
+func (m *WorkerAuthMiddleware) checkBinding(r *http.Request, workerName string) string {
+	if workerName == "" || r.Body == nil || r.Method != http.MethodPost {
+		return ""
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var fields workerBindingFields
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	for _, name := range []string{fields.WorkerName, fields.Worker} {
+		if name != "" && name != workerName {
+			return "workerName与Worker凭证不匹配"
+		}
+	}
+	if fields.TaskId != "" && m.TaskWorker != nil {
+		if owner := m.TaskWorker(r.Context(), fields.TaskId); owner != "" && owner != workerName {
+			return "任务未分配给当前Worker"
+		}
+	}
+	return ""
 }
 
 // workerUnauthorized 返回401未授权响应
@@ -72,6 +149,16 @@ func workerUnauthorized(w http.ResponseWriter, msg string) {
 	})
 }
 
+// workerForbidden 返回403禁止访问响应
+func workerForbidden(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code": 403,
+		"msg":  msg,
+	})
+}
+
 // GetWorkerName 从Context获取Worker名称
 func GetWorkerName(ctx context.Context) string {
 	if v := ctx.Value(WorkerNameKey); v != nil {
@@ -79,3 +166,11 @@ func GetWorkerName(ctx context.Context) string {
 	}
 	return ""
 }
+
+// GetWorkerCredentialId 从Context获取Worker凭证ID
+func GetWorkerCredentialId(ctx context.Context) string {
+	if v := ctx.Value(WorkerCredentialIdKey); v != nil {
+		return v.(string)
+	}
+	return ""
+}
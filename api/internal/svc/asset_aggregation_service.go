@@ -334,3 +334,185 @@ func (s *AssetAggregationService) GetAssetStatsByWorkspace(ctx context.Context,
 
 	return results[0], nil
 }
+
+// OrgScope 组织统计范围，includeChildren 为 true 时包含全部下级组织
+func (s *AssetAggregationService) OrgScope(ctx context.Context, orgId string, includeChildren bool) ([]string, error) {
+	if !includeChildren {
+		return []string{orgId}, nil
+	}
+	return model.NewOrganizationModel(s.db).DescendantIds(ctx, orgId)
+}
+
+// NameCount 分组计数
+type NameCount struct {
+	Name  string `bson:"_id" json:"name"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+// OrgAssetStats 组织资产看板统计
+type OrgAssetStats struct {
+	Total            int64            `json:"total"`
+	NewAssets        int64            `json:"newAssets"`
+	ByRiskLevel      map[string]int64 `json:"byRiskLevel"`
+	ByService        []NameCount      `json:"byService"`
+	ByOrg            map[string]int64 `json:"byOrg"`          // 按直接归属组织统计
+	PotentialVulns   map[string]int64 `json:"potentialVulns"` // 版本匹配的潜在漏洞，按严重程度统计
+	VulBySeverity    map[string]int64 `json:"vulBySeverity"`  // 已验证漏洞，按严重程度统计
+	VulCount         int64            `json:"vulCount"`
+	HighRiskVulCount int64            `json:"highRiskVulCount"`
+}
+
+// orgVulChunk 按资产地址关联漏洞时每批查询的地址数量
+const orgVulChunk = 5000
+
+// GetOrgAssetStats 统计工作空间内属于指定组织的资产和漏洞
+// 漏洞没有组织字段，按这些资产的地址关联
+func (s *AssetAggregationService) GetOrgAssetStats(ctx context.Context, workspaceId string, orgIds []string) (*OrgAssetStats, error) {
+	assetColl := s.db.Collection(fmt.Sprintf("%s_asset", workspaceId))
+
+	countFacet := func(field string) mongo.Pipeline {
+		return mongo.Pipeline{
+			{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$" + field},
+				{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			}}},
+		}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "org_id", Value: bson.D{{Key: "$in", Value: orgIds}}}}}},
+		{{Key: "$facet", Value: bson.D{
+			{Key: "total", Value: mongo.Pipeline{
+				{{Key: "$count", Value: "count"}},
+			}},
+			{Key: "new_assets", Value: mongo.Pipeline{
+				{{Key: "$match", Value: bson.D{{Key: "new", Value: true}}}},
+				{{Key: "$count", Value: "count"}},
+			}},
+			{Key: "by_risk_level", Value: countFacet("risk_level")},
+			{Key: "by_org", Value: countFacet("org_id")},
+			{Key: "by_service", Value: mongo.Pipeline{
+				{{Key: "$match", Value: bson.D{{Key: "service", Value: bson.D{{Key: "$nin", Value: bson.A{"", nil}}}}}}},
+				{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: "$service"},
+					{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+				}}},
+				{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+				{{Key: "$limit", Value: 10}},
+			}},
+			{Key: "potential_vulns", Value: mongo.Pipeline{
+				{{Key: "$unwind", Value: "$potential_vulns"}},
+				{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: "$potential_vulns.severity"},
+					{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+				}}},
+			}},
+			{Key: "authorities", Value: mongo.Pipeline{
+				{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: nil},
+					{Key: "list", Value: bson.D{{Key: "$addToSet", Value: "$authority"}}},
+				}}},
+			}},
+		}}},
+	}
+
+	cursor, err := assetColl.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate org assets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		NewAssets []struct {
+			Count int64 `bson:"count"`
+		} `bson:"new_assets"`
+		ByRiskLevel    []NameCount `bson:"by_risk_level"`
+		ByOrg          []NameCount `bson:"by_org"`
+		ByService      []NameCount `bson:"by_service"`
+		PotentialVulns []NameCount `bson:"potential_vulns"`
+		Authorities    []struct {
+			List []string `bson:"list"`
+		} `bson:"authorities"`
+	}
+	if err = cursor.All(ctx, &facets); err != nil {
+		return nil, fmt.Errorf("decode org stats: %w", err)
+	}
+
+	stats := &OrgAssetStats{
+		ByRiskLevel:    map[string]int64{},
+		ByService:      []NameCount{},
+		ByOrg:          map[string]int64{},
+		PotentialVulns: map[string]int64{},
+		VulBySeverity:  map[string]int64{},
+	}
+	if len(facets) == 0 {
+		return stats, nil
+	}
+	f := facets[0]
+	if len(f.Total) > 0 {
+		stats.Total = f.Total[0].Count
+	}
+	if len(f.NewAssets) > 0 {
+		stats.NewAssets = f.NewAssets[0].Count
+	}
+	for _, c := range f.ByRiskLevel {
+		name := c.Name
+		if name == "" {
+			name = "unknown"
+		}
+		stats.ByRiskLevel[name] += c.Count
+	}
+	for _, c := range f.ByOrg {
+		stats.ByOrg[c.Name] = c.Count
+	}
+	for _, c := range f.PotentialVulns {
+		stats.PotentialVulns[c.Name] = c.Count
+	}
+	stats.ByService = append(stats.ByService, f.ByService...)
+	var authorities []string
+	if len(f.Authorities) > 0 {
+		authorities = f.Authorities[0].List
+	}
+
+	if err := s.countOrgVuls(ctx, workspaceId, authorities, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// countOrgVuls 按资产地址分批统计漏洞
+func (s *AssetAggregationService) countOrgVuls(ctx context.Context, workspaceId string, authorities []string, stats *OrgAssetStats) error {
+	vulColl := s.db.Collection(fmt.Sprintf("%s_vul", workspaceId))
+	for i := 0; i < len(authorities); i += orgVulChunk {
+		end := i + orgVulChunk
+		if end > len(authorities) {
+			end = len(authorities)
+		}
+		cursor, err := vulColl.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.D{{Key: "authority", Value: bson.D{{Key: "$in", Value: authorities[i:end]}}}}}},
+			{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$severity"},
+				{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			}}},
+		})
+		if err != nil {
+			return fmt.Errorf("aggregate org vuls: %w", err)
+		}
+		var counts []NameCount
+		err = cursor.All(ctx, &counts)
+		cursor.Close(ctx)
+		if err != nil {
+			return fmt.Errorf("decode org vuls: %w", err)
+		}
+		for _, c := range counts {
+			stats.VulBySeverity[c.Name] += c.Count
+			stats.VulCount += c.Count
+			if c.Name == "critical" || c.Name == "high" {
+				stats.HighRiskVulCount += c.Count
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package svc
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"cscan/model"
+	"cscan/pkg/geoip"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// GeoIPService API 主机侧的IP归属地查询，数据来自已启用的上传数据集和本地配置目录
+type GeoIPService struct {
+	datasetModel *model.GeoIPDatasetModel
+	dir          string
+
+	mu       sync.Mutex
+	resolver *geoip.Resolver
+	loaded   bool
+}
+
+// NewGeoIPService creates a new GeoIPService
+func NewGeoIPService(datasetModel *model.GeoIPDatasetModel, dir string) *GeoIPService {
+	return &GeoIPService{
+		datasetModel: datasetModel,
+		dir:          dir,
+	}
+}
+
+// Resolver 返回当前的查询器，首次调用时加载数据集；没有可用数据集时返回 nil
+func (s *GeoIPService) Resolver(ctx context.Context) *geoip.Resolver {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		s.resolver = s.load(ctx)
+		s.loaded = true
+	}
+	if s.resolver.Len() == 0 {
+		return nil
+	}
+	return s.resolver
+}
+
+// Invalidate 数据集变更后调用，下次查询时重新加载
+func (s *GeoIPService) Invalidate() {
+	s.mu.Lock()
+	s.resolver = nil
+	s.loaded = false
+	s.mu.Unlock()
+}
+
+func (s *GeoIPService) load(ctx context.Context) *geoip.Resolver {
+	var readers []geoip.Reader
+
+	datasets, err := s.datasetModel.FindEnabled(ctx)
+	if err != nil {
+		logx.Errorf("[GeoIP] load datasets failed: %v", err)
+	}
+	for i := range datasets {
+		r, err := s.openDataset(&datasets[i])
+		if err != nil {
+			logx.Errorf("[GeoIP] open dataset %s failed: %v", datasets[i].Name, err)
+			continue
+		}
+		readers = append(readers, r)
+	}
+
+	if s.dir != "" {
+		local, err := geoip.OpenDir(s.dir)
+		if err != nil {
+			logx.Errorf("[GeoIP] %v", err)
+		}
+		if local.Len() > 0 {
+			readers = append(readers, local)
+		}
+	}
+
+	logx.Infof("[GeoIP] loaded %d datasets", len(readers))
+	return geoip.NewResolver(readers...)
+}
+
+func (s *GeoIPService) openDataset(doc *model.GeoIPDataset) (geoip.Reader, error) {
+	f, err := s.datasetModel.OpenFile(doc)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return geoip.NewReader(doc.Type, buf)
+}
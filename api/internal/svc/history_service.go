@@ -77,6 +77,13 @@ type CompareVersionsResp struct {
 	ComparisonDetail string
 }
 
+// CompareTaskRunsReq represents a request to compare two runs of a task
+type CompareTaskRunsReq struct {
+	WorkspaceId string
+	BaseTaskId  string
+	TaskId      string
+}
+
 // ==================== Service Methods ====================
 
 // ArchiveCurrentResults moves existing scan results to the asset_history collection
@@ -325,3 +332,67 @@ func (s *HistoryService) CompareVersions(ctx context.Context, req *CompareVersio
 		ComparisonDetail: comparisonDetail,
 	}, nil
 }
+
+// ArchiveTaskRun snapshots the assets and vulnerabilities produced by a task run.
+// Existing snapshots of the same run are replaced.
+func (s *HistoryService) ArchiveTaskRun(ctx context.Context, workspaceId, taskId string) ([]model.ScanResultHistory, error) {
+	if workspaceId == "" {
+		return nil, fmt.Errorf("workspace_id is required")
+	}
+	if taskId == "" {
+		return nil, fmt.Errorf("task_id is required")
+	}
+
+	assets, err := model.NewAssetModel(s.db, workspaceId).FindByTaskId(ctx, taskId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assets: %w", err)
+	}
+	vuls, err := model.NewVulModel(s.db, workspaceId).Find(ctx, bson.M{
+		"task_id": taskId,
+		"status":  bson.M{"$ne": model.VulStatusFalsePositive},
+	}, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vulnerabilities: %w", err)
+	}
+
+	historyModel := model.NewScanResultHistoryModel(s.db, workspaceId)
+	return historyModel.ArchiveTaskRun(ctx, workspaceId, taskId, assets, vuls)
+}
+
+// CompareTaskRuns compares the snapshots of two runs of the same task.
+// The base run must have been archived when it finished; the current run is
+// archived on demand if it has no snapshot yet.
+func (s *HistoryService) CompareTaskRuns(ctx context.Context, req *CompareTaskRunsReq) (*model.TaskRunDiff, error) {
+	if req.WorkspaceId == "" {
+		return nil, fmt.Errorf("workspace_id is required")
+	}
+	if req.BaseTaskId == "" || req.TaskId == "" {
+		return nil, fmt.Errorf("both task IDs are required")
+	}
+
+	historyModel := model.NewScanResultHistoryModel(s.db, req.WorkspaceId)
+
+	base, err := historyModel.FindByTaskId(ctx, req.WorkspaceId, req.BaseTaskId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch base run: %w", err)
+	}
+	if len(base) == 0 {
+		return nil, fmt.Errorf("base run %s has no archived snapshot", req.BaseTaskId)
+	}
+
+	current, err := historyModel.FindByTaskId(ctx, req.WorkspaceId, req.TaskId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch current run: %w", err)
+	}
+	if len(current) == 0 {
+		current, err = s.ArchiveTaskRun(ctx, req.WorkspaceId, req.TaskId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive current run: %w", err)
+		}
+	}
+
+	diff := model.DiffTaskRuns(base, current)
+	diff.BaseTaskId = req.BaseTaskId
+	diff.TaskId = req.TaskId
+	return diff, nil
+}
@@ -0,0 +1,83 @@
+package svc
+
+import (
+	"context"
+	"time"
+
+	"cscan/model"
+	"cscan/pkg/notify"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// NotifyRouteService 按通知路由规则分发 API 侧产生的事件，并定期发送到期的摘要
+type NotifyRouteService struct {
+	routeModel  *model.NotifyRouteModel
+	configModel *model.NotifyConfigModel
+	digestModel *model.NotifyDigestModel
+	orgModel    *model.OrganizationModel
+}
+
+// NewNotifyRouteService creates a new NotifyRouteService
+func NewNotifyRouteService(routeModel *model.NotifyRouteModel, configModel *model.NotifyConfigModel, digestModel *model.NotifyDigestModel, orgModel *model.OrganizationModel) *NotifyRouteService {
+	return &NotifyRouteService{
+		routeModel:  routeModel,
+		configModel: configModel,
+		digestModel: digestModel,
+		orgModel:    orgModel,
+	}
+}
+
+// router 加载已启用的规则，没有规则时返回 nil
+func (s *NotifyRouteService) router(ctx context.Context) (*notify.Router, error) {
+	rules, err := s.routeModel.LoadRules(ctx, s.orgModel)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	targets, err := s.configModel.Targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return notify.NewRouter(rules, targets, s.digestModel), nil
+}
+
+// DispatchAsync 异步分发事件，不阻塞请求处理
+func (s *NotifyRouteService) DispatchAsync(events ...*notify.Event) {
+	if s == nil || len(events) == 0 {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logx.Errorf("[NotifyRoute] dispatch panic: %v", r)
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		router, err := s.router(ctx)
+		if err != nil {
+			logx.Errorf("[NotifyRoute] load rules failed: %v", err)
+			return
+		}
+		if router == nil {
+			return
+		}
+		if err := router.Dispatch(ctx, events); err != nil {
+			logx.Errorf("[NotifyRoute] %v", err)
+		}
+	}()
+}
+
+// Flush 发送到期的摘要。没有启用的规则时也会取出到期摘要并丢弃
+func (s *NotifyRouteService) Flush(ctx context.Context) error {
+	router, err := s.router(ctx)
+	if err != nil {
+		return err
+	}
+	if router == nil {
+		router = notify.NewRouter(nil, nil, s.digestModel)
+	}
+	return router.Flush(ctx)
+}
@@ -0,0 +1,125 @@
+package svc
+
+import (
+	"context"
+	"sync"
+
+	"cscan/model"
+	"cscan/pkg/orgattr"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// OrgAttributionService 按归属规则为资产自动分配组织，规则在首次使用时加载并缓存
+type OrgAttributionService struct {
+	ruleModel *model.OrgAttributionRuleModel
+	orgModel  *model.OrganizationModel
+
+	mu      sync.Mutex
+	matcher *orgattr.Matcher
+	loaded  bool
+}
+
+// NewOrgAttributionService creates a new OrgAttributionService
+func NewOrgAttributionService(ruleModel *model.OrgAttributionRuleModel, orgModel *model.OrganizationModel) *OrgAttributionService {
+	return &OrgAttributionService{
+		ruleModel: ruleModel,
+		orgModel:  orgModel,
+	}
+}
+
+// Matcher 返回当前规则集，没有可用规则时返回 nil
+func (s *OrgAttributionService) Matcher(ctx context.Context) *orgattr.Matcher {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		s.matcher = s.load(ctx)
+		s.loaded = true
+	}
+	if s.matcher.Len() == 0 {
+		return nil
+	}
+	return s.matcher
+}
+
+// Invalidate 规则或组织变更后调用，下次匹配时重新加载
+func (s *OrgAttributionService) Invalidate() {
+	s.mu.Lock()
+	s.matcher = nil
+	s.loaded = false
+	s.mu.Unlock()
+}
+
+// Attribute 返回资产按规则应归属的组织ID，未命中返回空
+func (s *OrgAttributionService) Attribute(ctx context.Context, asset *model.Asset) string {
+	if s == nil || asset == nil {
+		return ""
+	}
+	m := s.Matcher(ctx)
+	if m == nil {
+		return ""
+	}
+	if r := m.Match(AttributionSubject(asset)); r != nil {
+		return r.OrgId
+	}
+	return ""
+}
+
+// AttributionSubject 提取资产参与归属匹配的属性
+func AttributionSubject(asset *model.Asset) *orgattr.Subject {
+	subject := &orgattr.Subject{
+		Host:         asset.Host,
+		Domain:       asset.Domain,
+		ICP:          asset.ICP,
+		CloudAccount: asset.CloudAccount,
+	}
+	for _, ip := range asset.Ip.IpV4 {
+		subject.IPs = append(subject.IPs, ip.IPName)
+	}
+	for _, ip := range asset.Ip.IpV6 {
+		subject.IPs = append(subject.IPs, ip.IPName)
+	}
+	if asset.CertInfo != nil {
+		subject.CertOrgs = orgattr.CertOrganizations(asset.CertInfo.Subject)
+	}
+	return subject
+}
+
+// load 加载已启用的规则，已停用或已删除组织的规则不参与匹配
+func (s *OrgAttributionService) load(ctx context.Context) *orgattr.Matcher {
+	docs, err := s.ruleModel.FindEnabled(ctx)
+	if err != nil {
+		logx.Errorf("[OrgAttribution] load rules failed: %v", err)
+		return nil
+	}
+	orgs, err := s.orgModel.Find(ctx, bson.M{"status": bson.M{"$ne": "disable"}}, 0, 0)
+	if err != nil {
+		logx.Errorf("[OrgAttribution] load organizations failed: %v", err)
+		return nil
+	}
+	active := make(map[string]bool, len(orgs))
+	for _, o := range orgs {
+		active[o.Id.Hex()] = true
+	}
+
+	rules := make([]orgattr.Rule, 0, len(docs))
+	for _, d := range docs {
+		if !active[d.OrgId] {
+			continue
+		}
+		rules = append(rules, orgattr.Rule{
+			Id:       d.Id.Hex(),
+			OrgId:    d.OrgId,
+			Type:     d.Type,
+			Pattern:  d.Pattern,
+			Priority: d.Priority,
+		})
+	}
+	matcher, errs := orgattr.NewMatcher(rules)
+	for _, err := range errs {
+		logx.Errorf("[OrgAttribution] %v", err)
+	}
+	logx.Infof("[OrgAttribution] loaded %d rules", matcher.Len())
+	return matcher
+}
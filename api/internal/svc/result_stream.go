@@ -0,0 +1,11 @@
+package svc
+
+// TaskResultStreamKey 任务实时结果事件的 Redis Stream 键，Worker 批次入库后写入，SSE 接口读取最近事件
+func TaskResultStreamKey(mainTaskId string) string {
+	return "cscan:task:results:" + mainTaskId
+}
+
+// TaskResultStreamChannel 任务实时结果事件的 Pub/Sub 频道
+func TaskResultStreamChannel(mainTaskId string) string {
+	return "cscan:task:results:realtime:" + mainTaskId
+}
@@ -0,0 +1,141 @@
+package svc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"cscan/api/internal/config"
+	"cscan/pkg/blobstore"
+	"cscan/pkg/imagehash"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// screenshotKeyRegex 截图存储键：内容 SHA256 + 扩展名，相同截图只保存一份
+var screenshotKeyRegex = regexp.MustCompile(`^[0-9a-f]{64}\.(png|jpg)$`)
+
+// screenshotURLWindow 截图地址签名的有效期粒度，同一窗口内签出的地址相同，便于浏览器缓存
+const screenshotURLWindow = 12 * time.Hour
+
+// ScreenshotService 截图存储，图片写入文件存储，资产中只保存存储键和感知哈希
+type ScreenshotService struct {
+	store  blobstore.Store
+	secret []byte
+}
+
+// NewScreenshotService 创建截图存储服务，存储初始化失败时截图仍以 base64 保存在资产中
+// secret 用于签名截图地址，截图文件接口只接受签名有效且未过期的请求
+func NewScreenshotService(c config.BlobStoreConfig, secret string) *ScreenshotService {
+	store, err := blobstore.New(blobstore.Config{
+		Type:         c.Type,
+		Dir:          c.Dir,
+		Endpoint:     c.Endpoint,
+		Region:       c.Region,
+		Bucket:       c.Bucket,
+		AccessKey:    c.AccessKey,
+		SecretKey:    c.SecretKey,
+		UsePathStyle: c.UsePathStyle,
+	})
+	if err != nil {
+		logx.Errorf("[Screenshot] init blob store failed, screenshots will be stored inline: %v", err)
+		return &ScreenshotService{secret: []byte(secret)}
+	}
+	return &ScreenshotService{store: store, secret: []byte(secret)}
+}
+
+// IsScreenshotKey 判断资产中的截图字段是否为存储键（旧数据为 base64）
+func IsScreenshotKey(s string) bool {
+	return screenshotKeyRegex.MatchString(s)
+}
+
+// Save 保存 Worker 上报的 base64 截图，返回存储键和感知哈希
+// 存储不可用时返回原 base64 作为截图字段值；已经是存储键的值原样返回
+func (s *ScreenshotService) Save(ctx context.Context, screenshot string) (ref, phash string, err error) {
+	if screenshot == "" || IsScreenshotKey(screenshot) {
+		return screenshot, "", nil
+	}
+	raw := screenshot
+	if i := strings.Index(raw, ","); strings.HasPrefix(raw, "data:") && i > 0 {
+		raw = raw[i+1:]
+	}
+	data, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return "", "", err
+	}
+
+	if h, err := imagehash.FromBytes(data); err == nil {
+		phash = h.String()
+	}
+	if s.store == nil {
+		return screenshot, phash, nil
+	}
+
+	ext := ".png"
+	if http.DetectContentType(data) == "image/jpeg" {
+		ext = ".jpg"
+	}
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:]) + ext
+	if err := s.store.Put(ctx, screenshotPath(key), data); err != nil {
+		return screenshot, phash, err
+	}
+	return key, phash, nil
+}
+
+// SignRef 为接口返回的截图存储键附加过期时间和签名：<key>?exp=<unix>&sig=<hmac>
+// 只有通过工作空间鉴权的接口才会签出地址；base64 等非存储键原样返回
+func (s *ScreenshotService) SignRef(ref string) string {
+	if s == nil || !IsScreenshotKey(ref) {
+		return ref
+	}
+	exp := time.Now().Truncate(screenshotURLWindow).Add(2 * screenshotURLWindow).Unix()
+	return ref + "?exp=" + strconv.FormatInt(exp, 10) + "&sig=" + s.sign(ref, exp)
+}
+
+// Verify 校验截图地址的签名和过期时间
+func (s *ScreenshotService) Verify(key, exp, sig string) bool {
+	expAt, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expAt {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(s.sign(key, expAt)))
+}
+
+func (s *ScreenshotService) sign(key string, exp int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "|" + strconv.FormatInt(exp, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Open 读取截图内容，返回图片数据和 Content-Type
+func (s *ScreenshotService) Open(ctx context.Context, key string) ([]byte, string, error) {
+	if !IsScreenshotKey(key) {
+		return nil, "", errors.New("invalid screenshot key")
+	}
+	if s.store == nil {
+		return nil, "", blobstore.ErrNotFound
+	}
+	data, err := s.store.Get(ctx, screenshotPath(key))
+	if err != nil {
+		return nil, "", err
+	}
+	contentType := "image/png"
+	if strings.HasSuffix(key, ".jpg") {
+		contentType = "image/jpeg"
+	}
+	return data, contentType, nil
+}
+
+// screenshotPath 按哈希前两位分目录，避免单目录文件过多
+func screenshotPath(key string) string {
+	return "screenshot/" + key[:2] + "/" + key
+}
@@ -0,0 +1,46 @@
+package svc
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cscan/api/internal/config"
+)
+
+func TestScreenshotSignRef(t *testing.T) {
+	s := NewScreenshotService(config.BlobStoreConfig{Type: "local", Dir: t.TempDir()}, "secret")
+	key := strings.Repeat("a", 64) + ".png"
+
+	ref := s.SignRef(key)
+	path, rawQuery, ok := strings.Cut(ref, "?")
+	if !ok || path != key {
+		t.Fatalf("SignRef(%q) = %q", key, ref)
+	}
+	q, _ := url.ParseQuery(rawQuery)
+	if !s.Verify(key, q.Get("exp"), q.Get("sig")) {
+		t.Fatal("signed ref should verify")
+	}
+
+	other := strings.Repeat("b", 64) + ".png"
+	if s.Verify(other, q.Get("exp"), q.Get("sig")) {
+		t.Error("signature must be bound to the key")
+	}
+	later := strconv.FormatInt(time.Now().Add(365*24*time.Hour).Unix(), 10)
+	if s.Verify(key, later, q.Get("sig")) {
+		t.Error("signature must be bound to the expiry")
+	}
+	past := time.Now().Add(-time.Hour).Unix()
+	if s.Verify(key, strconv.FormatInt(past, 10), s.sign(key, past)) {
+		t.Error("expired ref must be rejected")
+	}
+	if s.Verify(key, "", "") {
+		t.Error("unsigned ref must be rejected")
+	}
+
+	if got := s.SignRef("iVBORw0KGgo="); got != "iVBORw0KGgo=" {
+		t.Errorf("legacy base64 screenshot changed: %q", got)
+	}
+}
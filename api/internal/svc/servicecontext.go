@@ -38,8 +38,20 @@ type ServiceContext struct {
 	ActiveFingerprintModel   *model.ActiveFingerprintModel
 	CommandHistoryModel      *model.CommandHistoryModel
 	AuditLogModel            *model.AuditLogModel
+	ApiTokenModel            *model.ApiTokenModel
 	NotifyConfigModel        *model.NotifyConfigModel
 	ScanTemplateModel        *model.ScanTemplateModel
+	ExternalScannerModel     *model.ExternalScannerModel
+	ScanScopeModel           *model.ScanScopeModel
+	TakeoverSignatureModel   *model.TakeoverSignatureModel
+	CDNProviderModel         *model.CDNProviderModel
+	GeoIPDatasetModel        *model.GeoIPDatasetModel
+	SecretRuleModel          *model.SecretRuleModel
+	WorkerCredentialModel    *model.WorkerCredentialModel
+	VulnFeedModel            *model.VulnFeedModel
+	OrgAttributionRuleModel  *model.OrgAttributionRuleModel
+	NotifyRouteModel         *model.NotifyRouteModel
+	NotifyDigestModel        *model.NotifyDigestModel
 
 	// 调度器
 	Scheduler *scheduler.Scheduler
@@ -51,6 +63,21 @@ type ServiceContext struct {
 	ScanResultService *ScanResultService
 	HistoryService    *HistoryService
 
+	// IP归属地查询
+	GeoIP *GeoIPService
+
+	// 资产组织归属规则
+	OrgAttribution *OrgAttributionService
+
+	// 资产聚合统计（组织看板、导出）
+	AssetAggregation *AssetAggregationService
+
+	// 通知路由与摘要发送
+	NotifyRoute *NotifyRouteService
+
+	// 截图文件存储
+	Screenshots *ScreenshotService
+
 	// 缓存的模板元数据
 	TemplateCategories []string
 	TemplateTags       []string
@@ -136,8 +163,21 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		ActiveFingerprintModel:   model.NewActiveFingerprintModel(mongoDB),
 		CommandHistoryModel:      model.NewCommandHistoryModel(mongoDB),
 		AuditLogModel:            model.NewAuditLogModel(mongoDB),
+		ApiTokenModel:            model.NewApiTokenModel(mongoDB),
 		NotifyConfigModel:        model.NewNotifyConfigModel(mongoDB),
 		ScanTemplateModel:        model.NewScanTemplateModel(mongoDB),
+		ExternalScannerModel:     model.NewExternalScannerModel(mongoDB),
+		ScanScopeModel:           model.NewScanScopeModel(mongoDB),
+		TakeoverSignatureModel:   model.NewTakeoverSignatureModel(mongoDB),
+		CDNProviderModel:         model.NewCDNProviderModel(mongoDB),
+		GeoIPDatasetModel:        model.NewGeoIPDatasetModel(mongoDB),
+		SecretRuleModel:          model.NewSecretRuleModel(mongoDB),
+		WorkerCredentialModel:    model.NewWorkerCredentialModel(mongoDB),
+		VulnFeedModel:            model.NewVulnFeedModel(mongoDB),
+		OrgAttributionRuleModel:  model.NewOrgAttributionRuleModel(mongoDB),
+		NotifyRouteModel:         model.NewNotifyRouteModel(mongoDB),
+		NotifyDigestModel:        model.NewNotifyDigestModel(mongoDB),
+		AssetAggregation:         NewAssetAggregationService(mongoDB),
 		Scheduler:               scheduler.NewScheduler(rdb),
 		ScanResultService:       NewScanResultService(mongoDB),
 		HistoryService:          NewHistoryService(mongoDB),
@@ -165,6 +205,37 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	// 初始化内置扫描模板
 	sync.InitBuiltinTemplates(svcCtx.ScanTemplateModel)
 
+	// 初始化内置子域接管签名
+	sync.InitBuiltinTakeoverSignatures(svcCtx.TakeoverSignatureModel)
+
+	// 初始化内置CDN识别规则
+	sync.InitBuiltinCDNProviders(svcCtx.CDNProviderModel)
+
+	// 初始化内置敏感信息规则
+	sync.InitBuiltinSecretRules(svcCtx.SecretRuleModel)
+
+	// 初始化内置弱口令字典
+	sync.InitBuiltinBruteforceDicts(model.NewBruteforceDictModel(svcCtx.MongoDB))
+
+	// 为启用权限控制前创建的用户写入明确角色（只执行一次）
+	if n, err := svcCtx.UserModel.MigrateRoles(context.Background()); err != nil {
+		logx.Errorf("Migrate user roles failed: %v", err)
+	} else if n > 0 {
+		logx.Infof("Migrated roles for %d users", n)
+	}
+
+	// IP归属地查询，数据集在首次使用时加载
+	svcCtx.GeoIP = NewGeoIPService(svcCtx.GeoIPDatasetModel, c.GeoIP.Dir)
+
+	// 资产组织归属，规则在首次使用时加载
+	svcCtx.OrgAttribution = NewOrgAttributionService(svcCtx.OrgAttributionRuleModel, svcCtx.OrganizationModel)
+
+	// 通知路由，每次分发时读取最新规则
+	svcCtx.NotifyRoute = NewNotifyRouteService(svcCtx.NotifyRouteModel, svcCtx.NotifyConfigModel, svcCtx.NotifyDigestModel, svcCtx.OrganizationModel)
+
+	// 截图存储，图片不写入资产文档
+	svcCtx.Screenshots = NewScreenshotService(c.BlobStore, c.Auth.AccessSecret)
+
 	return svcCtx
 }
 
@@ -200,6 +271,30 @@ func (s *ServiceContext) GetAssetHistoryModel(workspaceId string) *model.AssetHi
 	return model.NewAssetHistoryModel(s.MongoDB, workspaceId)
 }
 
+// GetOutOfScopeModel 根据workspaceId获取范围外目标模型
+func (s *ServiceContext) GetOutOfScopeModel(workspaceId string) *model.OutOfScopeModel {
+	if workspaceId == "" {
+		workspaceId = "default"
+	}
+	return model.NewOutOfScopeModel(s.MongoDB, workspaceId)
+}
+
+// GetDNSRecordModel 根据workspaceId获取DNS记录历史模型
+func (s *ServiceContext) GetDNSRecordModel(workspaceId string) *model.DNSRecordModel {
+	if workspaceId == "" {
+		workspaceId = "default"
+	}
+	return model.NewDNSRecordModel(s.MongoDB, workspaceId)
+}
+
+// GetURLAssetModel 根据workspaceId获取URL端点模型
+func (s *ServiceContext) GetURLAssetModel(workspaceId string) *model.URLAssetModel {
+	if workspaceId == "" {
+		workspaceId = "default"
+	}
+	return model.NewURLAssetModel(s.MongoDB, workspaceId)
+}
+
 // GetDirScanResultModel 获取目录扫描结果模型
 func (s *ServiceContext) GetDirScanResultModel() *model.DirScanResultModel {
 	return model.NewDirScanResultModel(s.MongoDB)
@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// InitBuiltinBruteforceDicts 初始化内置弱口令字典，每个协议一个字典
+// 字典库中已有内置字典时跳过，用户对内置字典的修改和删除不会被覆盖
+func InitBuiltinBruteforceDicts(dictModel *model.BruteforceDictModel) {
+	ctx := context.Background()
+
+	count, err := dictModel.CountBuiltin(ctx)
+	if err == nil && count > 0 {
+		logx.Infof("[BruteforceDictInit] Found %d builtin dicts, skip init", count)
+		return
+	}
+
+	defaults := scanner.DefaultBruteforceCredentials()
+	total := 0
+	for _, service := range scanner.BruteforceServices() {
+		creds := defaults[service]
+		if len(creds) == 0 {
+			continue
+		}
+		lines := make([]string, 0, len(creds))
+		for _, c := range creds {
+			if c.Username == "" {
+				lines = append(lines, c.Password)
+			} else {
+				lines = append(lines, c.Username+":"+c.Password)
+			}
+		}
+		doc := &model.BruteforceDict{
+			Name:        fmt.Sprintf("builtin-%s", service),
+			Description: fmt.Sprintf("内置 %s 弱口令字典", service),
+			Service:     service,
+			Content:     strings.Join(lines, "\n"),
+			CredCount:   len(creds),
+			Enabled:     true,
+			IsBuiltin:   true,
+		}
+		if err := dictModel.Insert(ctx, doc); err != nil {
+			logx.Errorf("[BruteforceDictInit] Failed to insert dict %s: %v", doc.Name, err)
+			continue
+		}
+		total++
+	}
+
+	logx.Infof("[BruteforceDictInit] Builtin bruteforce dicts initialized, total: %d", total)
+}
@@ -0,0 +1,41 @@
+package sync
+
+import (
+	"context"
+
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// InitBuiltinCDNProviders 初始化内置CDN/WAF/云厂商识别规则
+// 识别库中已有内置规则时跳过，用户对内置规则的修改和删除不会被覆盖
+func InitBuiltinCDNProviders(providerModel *model.CDNProviderModel) {
+	ctx := context.Background()
+
+	count, err := providerModel.CountBuiltin(ctx)
+	if err == nil && count > 0 {
+		logx.Infof("[CDNInit] Found %d builtin providers, skip init", count)
+		return
+	}
+
+	providers := scanner.DefaultCDNProviders()
+	for _, p := range providers {
+		doc := &model.CDNProvider{
+			Name:         p.Name,
+			Type:         p.Type,
+			CNAMEs:       p.CNAMEs,
+			CIDRs:        p.CIDRs,
+			Headers:      p.Headers,
+			BodyPatterns: p.BodyPatterns,
+			Enabled:      true,
+			IsBuiltin:    true,
+		}
+		if err := providerModel.Create(ctx, doc); err != nil {
+			logx.Errorf("[CDNInit] Failed to insert provider %s: %v", p.Name, err)
+		}
+	}
+
+	logx.Infof("[CDNInit] Builtin CDN providers initialized, total: %d", len(providers))
+}
@@ -0,0 +1,39 @@
+package sync
+
+import (
+	"context"
+
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// InitBuiltinSecretRules 初始化内置敏感信息规则
+// 规则库中已有内置规则时跳过，用户对内置规则的修改和删除不会被覆盖
+func InitBuiltinSecretRules(ruleModel *model.SecretRuleModel) {
+	ctx := context.Background()
+
+	count, err := ruleModel.CountBuiltin(ctx)
+	if err == nil && count > 0 {
+		logx.Infof("[SecretRuleInit] Found %d builtin rules, skip init", count)
+		return
+	}
+
+	rules := scanner.DefaultSecretRules()
+	for _, rule := range rules {
+		doc := &model.SecretRule{
+			Name:        rule.Name,
+			Pattern:     rule.Pattern,
+			Severity:    rule.Severity,
+			Description: rule.Description,
+			Enabled:     true,
+			IsBuiltin:   true,
+		}
+		if err := ruleModel.Create(ctx, doc); err != nil {
+			logx.Errorf("[SecretRuleInit] Failed to insert rule %s: %v", rule.Name, err)
+		}
+	}
+
+	logx.Infof("[SecretRuleInit] Builtin secret rules initialized, total: %d", len(rules))
+}
@@ -0,0 +1,44 @@
+package sync
+
+import (
+	"context"
+
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// InitBuiltinTakeoverSignatures 初始化内置子域接管签名
+// 签名库中已有内置签名时跳过，用户对内置签名的修改和删除不会被覆盖
+func InitBuiltinTakeoverSignatures(signatureModel *model.TakeoverSignatureModel) {
+	ctx := context.Background()
+
+	count, err := signatureModel.CountBuiltin(ctx)
+	if err == nil && count > 0 {
+		logx.Infof("[TakeoverInit] Found %d builtin signatures, skip init", count)
+		return
+	}
+
+	signatures := scanner.DefaultTakeoverSignatures()
+	for _, sig := range signatures {
+		doc := &model.TakeoverSignature{
+			Service:      sig.Service,
+			RecordType:   sig.RecordType,
+			Patterns:     sig.Patterns,
+			Fingerprints: sig.Fingerprints,
+			HttpStatus:   sig.HttpStatus,
+			NXDomain:     sig.NXDomain,
+			Refused:      sig.Refused,
+			Severity:     sig.Severity,
+			Description:  sig.Description,
+			Enabled:      true,
+			IsBuiltin:    true,
+		}
+		if err := signatureModel.Create(ctx, doc); err != nil {
+			logx.Errorf("[TakeoverInit] Failed to insert signature %s: %v", sig.Service, err)
+		}
+	}
+
+	logx.Infof("[TakeoverInit] Builtin takeover signatures initialized, total: %d", len(signatures))
+}
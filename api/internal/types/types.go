@@ -35,12 +35,16 @@ type LoginResp struct {
 	Username    string `json:"username"`
 	Role        string `json:"role"`
 	WorkspaceId string `json:"workspaceId"`
+	// 按工作空间覆盖的角色
+	WorkspaceRoles map[string]string `json:"workspaceRoles,omitempty"`
 }
 
 type UserInfo struct {
-	Id       string `json:"id"`
-	Username string `json:"username"`
-	Status   string `json:"status"`
+	Id             string            `json:"id"`
+	Username       string            `json:"username"`
+	Status         string            `json:"status"`
+	Role           string            `json:"role"`
+	WorkspaceRoles map[string]string `json:"workspaceRoles,omitempty"`
 }
 
 type UserListResp struct {
@@ -52,15 +56,19 @@ type UserListResp struct {
 
 // ==================== 用户管理 ====================
 type UserCreateReq struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Status   string `json:"status"`
+	Username       string            `json:"username"`
+	Password       string            `json:"password"`
+	Status         string            `json:"status"`
+	Role           string            `json:"role,optional"`           // 全局角色: viewer/analyst/operator/admin，默认 viewer
+	WorkspaceRoles map[string]string `json:"workspaceRoles,optional"` // 工作空间ID -> 角色
 }
 
 type UserUpdateReq struct {
-	Id       string `json:"id"`
-	Username string `json:"username"`
-	Status   string `json:"status"`
+	Id             string            `json:"id"`
+	Username       string            `json:"username"`
+	Status         string            `json:"status"`
+	Role           string            `json:"role,optional"`           // 为空保持不变
+	WorkspaceRoles map[string]string `json:"workspaceRoles,optional"` // 为nil保持不变
 }
 
 type UserDeleteReq struct {
@@ -98,13 +106,79 @@ type WorkspaceDeleteReq struct {
 	Id string `json:"id"`
 }
 
+// ScanScopeWindow 允许扫描的时间窗口
+type ScanScopeWindow struct {
+	Days  []int  `json:"days,optional"` // 星期几，0=周日，为空表示每天
+	Start string `json:"start"`         // HH:MM
+	End   string `json:"end"`           // HH:MM，小于开始时间表示跨天
+}
+
+// ScanScope 工作空间扫描范围
+type ScanScope struct {
+	WorkspaceId string            `json:"workspaceId,optional"`
+	Enabled     bool              `json:"enabled"`
+	Cidrs       []string          `json:"cidrs,optional"`
+	Domains     []string          `json:"domains,optional"`
+	Hosts       []string          `json:"hosts,optional"`
+	Windows     []ScanScopeWindow `json:"windows,optional"`
+	Timezone    string            `json:"timezone,optional"`
+	StrictIP    bool              `json:"strictIp,optional"`
+	UpdateTime  string            `json:"updateTime,optional"`
+}
+
+type ScanScopeGetReq struct {
+	WorkspaceId string `json:"workspaceId,optional"`
+}
+
+type ScanScopeResp struct {
+	Code int        `json:"code"`
+	Msg  string     `json:"msg"`
+	Data *ScanScope `json:"data,omitempty"`
+}
+
+type OutOfScopeListReq struct {
+	Page        int    `json:"page,default=1"`
+	PageSize    int    `json:"pageSize,default=20"`
+	WorkspaceId string `json:"workspaceId,optional"`
+	MainTaskId  string `json:"mainTaskId,optional"`
+}
+
+type OutOfScope struct {
+	Id         string `json:"id"`
+	MainTaskId string `json:"mainTaskId"`
+	Target     string `json:"target"`
+	Host       string `json:"host"`
+	Phase      string `json:"phase"`
+	Reason     string `json:"reason"`
+	CreateTime string `json:"createTime"`
+}
+
+type OutOfScopeListResp struct {
+	Code  int          `json:"code"`
+	Msg   string       `json:"msg"`
+	Total int64        `json:"total"`
+	List  []OutOfScope `json:"list"`
+}
+
 // ==================== 组织管理 ====================
 type Organization struct {
-	Id          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	CreateTime  string `json:"createTime"`
+	Id          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Status      string       `json:"status"`
+	ParentId    string       `json:"parentId,omitempty"` // 上级组织ID，为空表示顶级组织
+	Contacts    []OrgContact `json:"contacts,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	CreateTime  string       `json:"createTime"`
+}
+
+// OrgContact 组织负责人/整改联系人
+type OrgContact struct {
+	Name  string `json:"name"`
+	Role  string `json:"role,optional"` // owner/security/ops 等
+	Email string `json:"email,optional"`
+	Phone string `json:"phone,optional"`
+	IM    string `json:"im,optional"` // 企业微信/钉钉/飞书账号
 }
 
 type OrganizationListResp struct {
@@ -115,10 +189,13 @@ type OrganizationListResp struct {
 }
 
 type OrganizationSaveReq struct {
-	Id          string `json:"id,optional"`
-	Name        string `json:"name"`
-	Description string `json:"description,optional"`
-	Status      string `json:"status,optional"`
+	Id          string       `json:"id,optional"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,optional"`
+	Status      string       `json:"status,optional"`
+	ParentId    string       `json:"parentId,optional"`
+	Contacts    []OrgContact `json:"contacts,optional"`
+	Tags        []string     `json:"tags,optional"`
 }
 
 type OrganizationDeleteReq struct {
@@ -130,18 +207,107 @@ type OrganizationUpdateStatusReq struct {
 	Status string `json:"status"`
 }
 
+// OrgDashboardReq 组织看板请求
+type OrgDashboardReq struct {
+	Id              string `json:"id"`
+	IncludeChildren bool   `json:"includeChildren,default=true"` // 是否包含下级组织
+}
+
+// OrgDashboardResp 组织看板响应，统计范围为当前工作空间（all 为全部）
+type OrgDashboardResp struct {
+	Code             int              `json:"code"`
+	Msg              string           `json:"msg"`
+	Organization     *Organization    `json:"organization"`
+	Children         []Organization   `json:"children"` // 直接下级组织
+	Total            int64            `json:"total"`
+	NewAssets        int64            `json:"newAssets"`
+	ByRiskLevel      map[string]int64 `json:"byRiskLevel"`
+	ByService        []StatItem       `json:"byService"`
+	ByOrg            []StatItem       `json:"byOrg"` // 按直接归属组织统计，name 为组织名称
+	PotentialVulns   map[string]int64 `json:"potentialVulns"`
+	VulBySeverity    map[string]int64 `json:"vulBySeverity"`
+	VulCount         int64            `json:"vulCount"`
+	HighRiskVulCount int64            `json:"highRiskVulCount"`
+}
+
+// OrgAttributionRule 资产归属规则
+type OrgAttributionRule struct {
+	Id          string `json:"id"`
+	OrgId       string `json:"orgId"`
+	OrgName     string `json:"orgName"`
+	Type        string `json:"type"` // domain/cidr/icp/cert_org/cloud_account
+	Pattern     string `json:"pattern"`
+	Priority    int    `json:"priority"`
+	Enabled     bool   `json:"enabled"`
+	Description string `json:"description"`
+	CreateTime  string `json:"createTime"`
+}
+
+// OrgAttributionRuleListReq 归属规则列表请求
+type OrgAttributionRuleListReq struct {
+	OrgId string `json:"orgId,optional"`
+	Type  string `json:"type,optional"`
+}
+
+// OrgAttributionRuleListResp 归属规则列表响应
+type OrgAttributionRuleListResp struct {
+	Code  int                  `json:"code"`
+	Msg   string               `json:"msg"`
+	Total int                  `json:"total"`
+	List  []OrgAttributionRule `json:"list"`
+}
+
+// OrgAttributionRuleSaveReq 保存归属规则请求
+type OrgAttributionRuleSaveReq struct {
+	Id          string `json:"id,optional"`
+	OrgId       string `json:"orgId"`
+	Type        string `json:"type"`
+	Pattern     string `json:"pattern"`
+	Priority    int    `json:"priority,optional"`
+	Enabled     bool   `json:"enabled,optional"`
+	Description string `json:"description,optional"`
+}
+
+// OrgAttributionRuleDeleteReq 删除归属规则请求
+type OrgAttributionRuleDeleteReq struct {
+	Id string `json:"id"`
+}
+
+// OrgAttributionApplyReq 对已有资产重新执行归属规则
+type OrgAttributionApplyReq struct {
+	Overwrite bool `json:"overwrite,optional"` // 为 false 时只处理未归属组织的资产
+}
+
+// OrgAttributionApplyResp 重新执行归属规则响应
+type OrgAttributionApplyResp struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	Scanned int    `json:"scanned"`
+	Updated int    `json:"updated"`
+}
+
 // ==================== 资产管理 ====================
 
 // IPV4Info IPv4地址信息
 type IPV4Info struct {
 	IP       string `json:"ip"`
 	Location string `json:"location,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Region   string `json:"region,omitempty"`
+	City     string `json:"city,omitempty"`
+	ASN      int    `json:"asn,omitempty"`
+	Org      string `json:"org,omitempty"`
 }
 
 // IPV6Info IPv6地址信息
 type IPV6Info struct {
 	IP       string `json:"ip"`
 	Location string `json:"location,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Region   string `json:"region,omitempty"`
+	City     string `json:"city,omitempty"`
+	ASN      int    `json:"asn,omitempty"`
+	Org      string `json:"org,omitempty"`
 }
 
 // IPInfo IP地址信息
@@ -155,6 +321,7 @@ type Asset struct {
 	Authority            string   `json:"authority"`
 	Host                 string   `json:"host"`
 	Port                 int      `json:"port"`
+	Transport            string   `json:"transport,omitempty"` // 传输层协议: tcp/udp
 	Category             string   `json:"category"`
 	Service              string   `json:"service"`
 	Title                string   `json:"title"`
@@ -170,6 +337,9 @@ type Asset struct {
 	IP                   *IPInfo  `json:"ip,omitempty"` // IP地址信息
 	IsCDN                bool     `json:"isCdn"`
 	IsCloud              bool     `json:"isCloud"`
+	CDNProvider          string   `json:"cdnProvider,omitempty"`
+	CloudProvider        string   `json:"cloudProvider,omitempty"`
+	WAF                  string   `json:"waf,omitempty"`
 	IsNew                bool     `json:"isNew"`
 	IsUpdated            bool     `json:"isUpdated"`
 	CreateTime           string   `json:"createTime"`
@@ -182,6 +352,21 @@ type Asset struct {
 	// 风险评分
 	RiskScore float64 `json:"riskScore,omitempty"`
 	RiskLevel string  `json:"riskLevel,omitempty"`
+	// 版本识别与离线情报匹配
+	CPE            []string        `json:"cpe,omitempty"`
+	PotentialVulns []PotentialVuln `json:"potentialVulns,omitempty"`
+}
+
+// PotentialVuln 按CPE匹配离线情报得到的潜在漏洞（未经验证）
+type PotentialVuln struct {
+	VulnId     string   `json:"vulnId"`
+	CveId      string   `json:"cveId,omitempty"`
+	Source     string   `json:"source"`
+	CPE        string   `json:"cpe"`
+	CVSS       float64  `json:"cvss"`
+	Severity   string   `json:"severity"`
+	Summary    string   `json:"summary"`
+	References []string `json:"references,omitempty"`
 }
 
 type AssetListReq struct {
@@ -200,6 +385,10 @@ type AssetListReq struct {
 	OnlyUpdated  bool   `json:"onlyUpdated,optional"`
 	ExcludeCdn   bool   `json:"excludeCdn,optional"`
 	SortByUpdate bool   `json:"sortByUpdate,optional"`
+	ASN          int    `json:"asn,optional"`
+	Country      string `json:"country,optional"`
+	GroupBy      string `json:"groupBy,optional"`   // asn/country，返回按ASN或国家的分组统计
+	Transport    string `json:"transport,optional"` // tcp/udp，按传输层协议筛选
 	// 新增字段 - 按风险评分排序
 	SortByRisk bool `json:"sortByRisk,optional"`
 	// 新增字段 - 时间范围筛选（最近N天内更新的资产）
@@ -207,10 +396,11 @@ type AssetListReq struct {
 }
 
 type AssetListResp struct {
-	Code  int     `json:"code"`
-	Msg   string  `json:"msg"`
-	Total int     `json:"total"`
-	List  []Asset `json:"list"`
+	Code   int        `json:"code"`
+	Msg    string     `json:"msg"`
+	Total  int        `json:"total"`
+	List   []Asset    `json:"list"`
+	Groups []StatItem `json:"groups,omitempty"` // GroupBy 分组统计
 }
 
 type AssetStatResp struct {
@@ -252,7 +442,9 @@ type AssetBatchDeleteReq struct {
 
 // AssetImportReq 资产导入请求
 type AssetImportReq struct {
-	Targets []string `json:"targets"` // 目标列表，支持 IP:端口 或 URL 格式
+	Targets      []string `json:"targets"`               // 目标列表，支持 IP:端口 或 URL 格式
+	OrgId        string   `json:"orgId,optional"`        // 指定归属组织，为空时按归属规则分配
+	CloudAccount string   `json:"cloudAccount,optional"` // 导入云账号下的资产时填写，用于归属规则匹配
 }
 
 // AssetImportResp 资产导入响应
@@ -405,6 +597,66 @@ type DomainBatchDeleteReq struct {
 	Ids []string `json:"ids"`
 }
 
+type DomainDNSRecordsReq struct {
+	Domain      string `json:"domain"`
+	Type        string `json:"type,optional"` // 记录类型，为空返回全部
+	WorkspaceId string `json:"workspaceId,optional"`
+}
+
+// DNSRecord 域名DNS记录历史
+type DNSRecord struct {
+	Domain      string `json:"domain"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Value       string `json:"value"`
+	TTL         int64  `json:"ttl"`
+	Current     bool   `json:"current"` // 最近一次查询仍存在
+	FirstSeen   string `json:"firstSeen"`
+	LastSeen    string `json:"lastSeen"`
+	WorkspaceId string `json:"workspaceId"`
+}
+
+type DomainDNSRecordsResp struct {
+	Code  int         `json:"code"`
+	Msg   string      `json:"msg"`
+	Total int         `json:"total"`
+	List  []DNSRecord `json:"list"`
+}
+
+// URLAssetListReq 爬虫URL端点列表请求
+type URLAssetListReq struct {
+	Page        int    `json:"page,default=1"`
+	PageSize    int    `json:"pageSize,default=50"`
+	Authority   string `json:"authority,optional"` // host:port
+	Type        string `json:"type,optional"`      // page/form/api/js
+	Keyword     string `json:"keyword,optional"`   // URL关键字
+	WorkspaceId string `json:"workspaceId,optional"`
+}
+
+// URLAsset 爬虫发现的URL端点
+type URLAsset struct {
+	Id          string   `json:"id"`
+	Authority   string   `json:"authority"`
+	URL         string   `json:"url"`
+	Path        string   `json:"path"`
+	Method      string   `json:"method"`
+	Type        string   `json:"type"`
+	Params      []string `json:"params"`
+	StatusCode  int      `json:"statusCode"`
+	ContentType string   `json:"contentType"`
+	Source      string   `json:"source"`
+	FirstSeen   string   `json:"firstSeen"`
+	LastSeen    string   `json:"lastSeen"`
+}
+
+// URLAssetListResp 爬虫URL端点列表响应
+type URLAssetListResp struct {
+	Code  int        `json:"code"`
+	Msg   string     `json:"msg"`
+	Total int64      `json:"total"`
+	List  []URLAsset `json:"list"`
+}
+
 // ==================== 资产分组管理 ====================
 type AssetGroupsReq struct {
 	Page     int    `json:"page,default=1"`
@@ -502,6 +754,8 @@ type ScreenshotsReq struct {
 	SortBy        string   `json:"sortBy,optional"`        // 排序字段: time/name
 	Domain        string   `json:"domain,optional"`        // 域名过滤
 	HasScreenshot bool     `json:"hasScreenshot,optional"` // 只显示有截图的
+	GroupBy       string   `json:"groupBy,optional"`       // 分组方式: phash 按截图相似度聚类
+	MaxDistance   int      `json:"maxDistance,optional"`   // 聚类时感知哈希的最大汉明距离，默认6
 }
 
 type ScreenshotItem struct {
@@ -514,7 +768,8 @@ type ScreenshotItem struct {
 	Status       string       `json:"status"`       // HTTP状态码
 	StatusText   string       `json:"statusText"`   // 状态文本
 	Title        string       `json:"title"`        // 页面标题
-	Screenshot   string       `json:"screenshot"`   // 截图URL
+	Screenshot   string       `json:"screenshot"`   // 截图存储键，旧数据为 base64
+	Hash         string       `json:"hash"`         // 截图感知哈希
 	LastUpdated  string       `json:"lastUpdated"`  // 最后更新时间
 	Technologies []Technology `json:"technologies"` // 技术栈
 	HttpHeader   string       `json:"httpHeader"`   // HTTP响应头
@@ -527,11 +782,28 @@ type Technology struct {
 	Name string `json:"name"`
 }
 
+// ScreenshotGroup 相似截图分组，Sample 为组内代表截图
+type ScreenshotGroup struct {
+	Hash    string                  `json:"hash"`
+	Count   int                     `json:"count"`
+	Sample  ScreenshotItem          `json:"sample"`
+	Members []ScreenshotGroupMember `json:"members"`
+}
+
+type ScreenshotGroupMember struct {
+	Id          string `json:"id"`
+	WorkspaceId string `json:"workspaceId"`
+	Authority   string `json:"authority"`
+	Title       string `json:"title"`
+	Distance    int    `json:"distance"` // 与分组哈希的汉明距离
+}
+
 type ScreenshotsResp struct {
-	Code  int              `json:"code"`
-	Msg   string           `json:"msg"`
-	Total int              `json:"total"`
-	List  []ScreenshotItem `json:"list"`
+	Code   int               `json:"code"`
+	Msg    string            `json:"msg"`
+	Total  int               `json:"total"`            // 按相似度分组时为分组数
+	List   []ScreenshotItem  `json:"list"`
+	Groups []ScreenshotGroup `json:"groups,omitempty"` // 按相似度分组时返回
 }
 
 // ==================== IP管理 ====================
@@ -544,6 +816,9 @@ Query    string `json:"query,optional"`
 	Service  string `json:"service,optional"`
 	Location string `json:"location,optional"`
 	OrgId    string `json:"orgId,optional"`
+	ASN      int    `json:"asn,optional"`
+	Country  string `json:"country,optional"`
+	GroupBy  string `json:"groupBy,optional"` // asn/country，返回按ASN或国家的分组统计
 }
 
 type PortInfo struct {
@@ -555,6 +830,7 @@ type IPAsset struct {
 	Id          string     `json:"id"`
 	IP          string     `json:"ip"`
 	Location    string     `json:"location"`
+	Country     string     `json:"country,omitempty"`
 	ASN         string     `json:"asn,omitempty"`
 	ISP         string     `json:"isp,omitempty"`
 	Ports       []PortInfo `json:"ports"`
@@ -567,10 +843,11 @@ type IPAsset struct {
 }
 
 type IPListResp struct {
-	Code  int       `json:"code"`
-	Msg   string    `json:"msg"`
-	Total int       `json:"total"`
-	List  []IPAsset `json:"list"`
+	Code   int        `json:"code"`
+	Msg    string     `json:"msg"`
+	Total  int        `json:"total"`
+	List   []IPAsset  `json:"list"`
+	Groups []StatItem `json:"groups,omitempty"` // GroupBy 分组统计
 }
 
 type IPStatResp struct {
@@ -731,6 +1008,21 @@ type Vul struct {
 	FirstSeenTime string `json:"firstSeenTime,omitempty"`
 	LastSeenTime  string `json:"lastSeenTime,omitempty"`
 	ScanCount     int    `json:"scanCount,omitempty"`
+	// 处置流程
+	Status   string `json:"status"`
+	Assignee string `json:"assignee,omitempty"`
+	DueTime  string `json:"dueTime,omitempty"`
+	Overdue  bool   `json:"overdue,omitempty"`
+}
+
+// VulActivity 漏洞处置记录
+type VulActivity struct {
+	Type     string `json:"type"` // status/assign/comment
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Operator string `json:"operator"`
+	Time     string `json:"time"`
 }
 
 // VulEvidence 漏洞证据链
@@ -769,6 +1061,12 @@ type VulDetail struct {
 	FirstSeenTime string `json:"firstSeenTime,omitempty"`
 	LastSeenTime  string `json:"lastSeenTime,omitempty"`
 	ScanCount     int    `json:"scanCount,omitempty"`
+	// 处置流程
+	Status     string        `json:"status"`
+	Assignee   string        `json:"assignee,omitempty"`
+	DueTime    string        `json:"dueTime,omitempty"`
+	Overdue    bool          `json:"overdue,omitempty"`
+	Activities []VulActivity `json:"activities"`
 }
 
 // VulDetailReq 漏洞详情请求
@@ -792,6 +1090,9 @@ type VulListReq struct {
 	Source    string `json:"source,optional"`
 	Host      string `json:"host,optional"`
 	Port      int    `json:"port,optional"`
+	Status    string `json:"status,optional"`   // 处置状态
+	Assignee  string `json:"assignee,optional"` // 负责人
+	Overdue   bool   `json:"overdue,optional"`  // 仅显示超过SLA未处理的漏洞
 }
 
 type VulListResp struct {
@@ -809,6 +1110,26 @@ type VulBatchDeleteReq struct {
 	Ids []string `json:"ids"`
 }
 
+// VulTriageReq 漏洞处置状态变更请求
+type VulTriageReq struct {
+	Ids     []string `json:"ids"`
+	Status  string   `json:"status"` // new/confirmed/false_positive/accepted_risk/fixed/reopened
+	Comment string   `json:"comment,optional"`
+}
+
+// VulAssignReq 漏洞指派请求
+type VulAssignReq struct {
+	Ids      []string `json:"ids"`
+	Assignee string   `json:"assignee,optional"` // 为空表示取消指派
+	DueTime  string   `json:"dueTime,optional"`  // 截止日期 2006-01-02，为空保持不变
+}
+
+// VulCommentReq 漏洞评论请求
+type VulCommentReq struct {
+	Id      string `json:"id"`
+	Content string `json:"content"`
+}
+
 // VulStatResp 漏洞统计响应
 type VulStatResp struct {
 	Code     int    `json:"code"`
@@ -1660,8 +1981,12 @@ type ReportDetailResp struct {
 }
 
 type ReportExportReq struct {
-	TaskId string `json:"taskId"`
-	Format string `json:"format,optional"` // excel, pdf (默认excel)
+	TaskId    string `json:"taskId,optional"`    // 为空时按当前工作空间（all 为全部）/组织/时间范围导出
+	Format    string `json:"format,optional"`    // xlsx(默认), sarif, json, jsonl, csv, html
+	Sheet     string `json:"sheet,optional"`     // csv 格式下指定 asset/vul/dirscan，为空时打包全部
+	OrgId     string `json:"orgId,optional"`     // 按组织导出
+	StartTime string `json:"startTime,optional"` // 发现时间起（RFC3339 或 2006-01-02）
+	EndTime   string `json:"endTime,optional"`   // 发现时间止
 }
 
 // ==================== 用户扫描配置 ====================
@@ -1675,6 +2000,44 @@ type GetScanConfigResp struct {
 	Config string `json:"config"` // 扫描配置JSON
 }
 
+// ==================== API Token ====================
+type ApiToken struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	TokenHint    string   `json:"tokenHint"`
+	WorkspaceId  string   `json:"workspaceId"`
+	Scopes       []string `json:"scopes"`
+	ExpireTime   string   `json:"expireTime"` // 为空表示永不过期
+	Revoked      bool     `json:"revoked"`
+	LastUsedTime string   `json:"lastUsedTime"`
+	LastUsedIp   string   `json:"lastUsedIp"`
+	CreateTime   string   `json:"createTime"`
+}
+
+type ApiTokenListResp struct {
+	Code int        `json:"code"`
+	Msg  string     `json:"msg"`
+	List []ApiToken `json:"list"`
+}
+
+type ApiTokenCreateReq struct {
+	Name        string   `json:"name"`
+	WorkspaceId string   `json:"workspaceId,optional"` // 为空时使用当前工作空间
+	Scopes      []string `json:"scopes"`               // read / task
+	ExpireDays  int      `json:"expireDays,optional"`  // 0 表示永不过期
+}
+
+type ApiTokenCreateResp struct {
+	Code  int       `json:"code"`
+	Msg   string    `json:"msg"`
+	Token string    `json:"token"` // 明文Token，仅在创建时返回一次
+	Data  *ApiToken `json:"data"`
+}
+
+type ApiTokenRevokeReq struct {
+	Id string `json:"id"`
+}
+
 // ==================== Subfinder数据源配置 ====================
 type SubfinderProvider struct {
 	Id          string   `json:"id"`
@@ -1801,6 +2164,61 @@ type WorkerValidateKeyResp struct {
 	Valid bool   `json:"valid"` // 是否有效
 }
 
+// WorkerEnrollReq Worker注册请求（Worker使用安装密钥换取独立凭证）
+type WorkerEnrollReq struct {
+	InstallKey string `json:"installKey"` // 安装密钥
+	WorkerName string `json:"workerName"` // Worker名称
+	WorkerIP   string `json:"workerIP"`   // Worker IP
+	WorkerOS   string `json:"workerOS"`   // 操作系统
+	WorkerArch string `json:"workerArch"` // 架构
+}
+
+// WorkerEnrollResp Worker注册响应
+type WorkerEnrollResp struct {
+	Code         int    `json:"code"`
+	Msg          string `json:"msg"`
+	CredentialId string `json:"credentialId"`
+	WorkerName   string `json:"workerName"`
+	Token        string `json:"token"` // 明文凭证，仅在注册时返回一次
+}
+
+// WorkerCredential Worker凭证
+type WorkerCredential struct {
+	Id           string `json:"id"`
+	WorkerName   string `json:"workerName"`
+	TokenHint    string `json:"tokenHint"`
+	IP           string `json:"ip"`
+	OS           string `json:"os"`
+	Arch         string `json:"arch"`
+	Online       bool   `json:"online"`
+	Revoked      bool   `json:"revoked"`
+	RevokeReason string `json:"revokeReason"`
+	RevokeTime   string `json:"revokeTime"`
+	LastUsedTime string `json:"lastUsedTime"`
+	LastUsedIp   string `json:"lastUsedIp"`
+	CreateTime   string `json:"createTime"`
+	// ReenrollUntil 批准同名重新注册的截止时间，未批准时为空
+	ReenrollUntil string `json:"reenrollUntil"`
+}
+
+// WorkerCredentialListResp Worker凭证列表响应
+type WorkerCredentialListResp struct {
+	Code int                `json:"code"`
+	Msg  string             `json:"msg"`
+	List []WorkerCredential `json:"list"`
+}
+
+// WorkerCredentialRevokeReq 吊销Worker凭证请求
+type WorkerCredentialRevokeReq struct {
+	Id     string `json:"id"`
+	Reason string `json:"reason,optional"`
+}
+
+// WorkerCredentialApproveReq 批准同名重新注册请求
+type WorkerCredentialApproveReq struct {
+	Id string `json:"id"`
+}
+
 // WorkerBinaryInfoResp Worker二进制文件信息响应
 type WorkerBinaryInfoResp struct {
 	Code     int    `json:"code"`
@@ -2039,6 +2457,369 @@ type SubdomainDictSimple struct {
 	IsBuiltin bool   `json:"isBuiltin"`
 }
 
+// ==================== 弱口令字典 ====================
+
+// BruteforceDict 弱口令字典
+type BruteforceDict struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`        // 字典名称
+	Description string `json:"description"` // 描述
+	Service     string `json:"service"`     // 适用协议，all 表示全部协议
+	Content     string `json:"content"`     // 字典内容（每行一个 username:password）
+	CredCount   int    `json:"credCount"`   // 凭据数量
+	Enabled     bool   `json:"enabled"`     // 是否启用
+	IsBuiltin   bool   `json:"isBuiltin"`   // 是否内置字典
+	CreateTime  string `json:"createTime"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+// BruteforceDictListReq 弱口令字典列表请求
+type BruteforceDictListReq struct {
+	Page     int `json:"page,default=1"`
+	PageSize int `json:"pageSize,default=20"`
+}
+
+// BruteforceDictListResp 弱口令字典列表响应
+type BruteforceDictListResp struct {
+	Code     int              `json:"code"`
+	Msg      string           `json:"msg"`
+	Total    int              `json:"total"`
+	List     []BruteforceDict `json:"list"`
+	Services []string         `json:"services"` // 支持的协议
+}
+
+// BruteforceDictSaveReq 保存弱口令字典请求
+type BruteforceDictSaveReq struct {
+	Id          string `json:"id,optional"`
+	Name        string `json:"name"`
+	Description string `json:"description,optional"`
+	Service     string `json:"service"`
+	Content     string `json:"content"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// BruteforceDictDeleteReq 删除弱口令字典请求
+type BruteforceDictDeleteReq struct {
+	Id string `json:"id"`
+}
+
+// BruteforceDictClearResp 清空弱口令字典响应
+type BruteforceDictClearResp struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	Deleted int    `json:"deleted"` // 删除数量
+}
+
+// BruteforceDictEnabledListResp 启用的弱口令字典列表响应（用于任务创建时选择）
+type BruteforceDictEnabledListResp struct {
+	Code int                    `json:"code"`
+	Msg  string                 `json:"msg"`
+	List []BruteforceDictSimple `json:"list"`
+}
+
+// BruteforceDictSimple 简化的弱口令字典信息（用于选择列表）
+type BruteforceDictSimple struct {
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	Service   string `json:"service"`
+	CredCount int    `json:"credCount"`
+	IsBuiltin bool   `json:"isBuiltin"`
+}
+
+// ==================== 外部扫描器 ====================
+
+// ExternalScanner 外部扫描器声明
+type ExternalScanner struct {
+	Id          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Binary      string            `json:"binary"`      // Worker 上的可执行文件
+	Args        []string          `json:"args"`        // 参数，支持 {{input}}、{{output}} 占位符
+	Env         map[string]string `json:"env"`         // 额外环境变量
+	InputFormat string            `json:"inputFormat"` // hostports/hosts/urls/targets/jsonl
+	ResultType  string            `json:"resultType"`  // 空/asset/vul
+	FieldMap    map[string]string `json:"fieldMap"`    // 结果字段 -> 输出JSON路径
+	Timeout     int               `json:"timeout"`     // 超时(秒)
+	Enabled     bool              `json:"enabled"`
+	CreateTime  string            `json:"createTime"`
+	UpdateTime  string            `json:"updateTime"`
+}
+
+// ExternalScannerListResp 外部扫描器列表响应
+type ExternalScannerListResp struct {
+	Code int               `json:"code"`
+	Msg  string            `json:"msg"`
+	List []ExternalScanner `json:"list"`
+}
+
+// ExternalScannerSaveReq 保存外部扫描器请求
+type ExternalScannerSaveReq struct {
+	Id          string            `json:"id,optional"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,optional"`
+	Binary      string            `json:"binary"`
+	Args        []string          `json:"args,optional"`
+	Env         map[string]string `json:"env,optional"`
+	InputFormat string            `json:"inputFormat,optional"`
+	ResultType  string            `json:"resultType,optional"`
+	FieldMap    map[string]string `json:"fieldMap,optional"`
+	Timeout     int               `json:"timeout,optional"`
+	Enabled     bool              `json:"enabled"`
+}
+
+// ExternalScannerDeleteReq 删除外部扫描器请求
+type ExternalScannerDeleteReq struct {
+	Id string `json:"id"`
+}
+
+// ==================== 子域接管签名 ====================
+
+// TakeoverSignature 子域接管签名
+type TakeoverSignature struct {
+	Id           string   `json:"id"`
+	Service      string   `json:"service"`
+	RecordType   string   `json:"recordType"`   // CNAME/A/NS/MX
+	Patterns     []string `json:"patterns"`     // 记录值后缀或关键字，A 记录为IP/CIDR
+	Fingerprints []string `json:"fingerprints"` // HTTP响应体指纹
+	HttpStatus   int      `json:"httpStatus"`   // 指纹命中时要求的状态码，0 不限制
+	NXDomain     bool     `json:"nxdomain"`     // 记录值 NXDOMAIN 即确认
+	Refused      bool     `json:"refused"`      // NS 服务器拒绝解析即确认
+	Severity     string   `json:"severity"`
+	Description  string   `json:"description"`
+	Enabled      bool     `json:"enabled"`
+	IsBuiltin    bool     `json:"isBuiltin"`
+	CreateTime   string   `json:"createTime"`
+	UpdateTime   string   `json:"updateTime"`
+}
+
+// TakeoverSignatureListReq 子域接管签名列表请求
+type TakeoverSignatureListReq struct {
+	Page       int    `json:"page,default=1"`
+	PageSize   int    `json:"pageSize,default=50"`
+	Keyword    string `json:"keyword,optional"`
+	RecordType string `json:"recordType,optional"`
+	Enabled    *bool  `json:"enabled,optional"`
+}
+
+// TakeoverSignatureListResp 子域接管签名列表响应
+type TakeoverSignatureListResp struct {
+	Code  int                 `json:"code"`
+	Msg   string              `json:"msg"`
+	Total int64               `json:"total"`
+	List  []TakeoverSignature `json:"list"`
+}
+
+// TakeoverSignatureSaveReq 保存子域接管签名请求
+type TakeoverSignatureSaveReq struct {
+	Id           string   `json:"id,optional"`
+	Service      string   `json:"service"`
+	RecordType   string   `json:"recordType,optional"`
+	Patterns     []string `json:"patterns,optional"`
+	Fingerprints []string `json:"fingerprints,optional"`
+	HttpStatus   int      `json:"httpStatus,optional"`
+	NXDomain     bool     `json:"nxdomain,optional"`
+	Refused      bool     `json:"refused,optional"`
+	Severity     string   `json:"severity,optional"`
+	Description  string   `json:"description,optional"`
+	Enabled      bool     `json:"enabled"`
+}
+
+// TakeoverSignatureDeleteReq 删除子域接管签名请求
+type TakeoverSignatureDeleteReq struct {
+	Id string `json:"id"`
+}
+
+// TakeoverSignatureUpdateEnabledReq 更新子域接管签名启用状态请求
+type TakeoverSignatureUpdateEnabledReq struct {
+	Id      string `json:"id"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ==================== CDN识别规则 ====================
+
+// CDNProvider CDN/WAF/云厂商识别规则
+type CDNProvider struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`         // cdn/waf/cloud
+	CNAMEs       []string `json:"cnames"`       // CNAME 后缀
+	CIDRs        []string `json:"cidrs"`        // 服务商IP段
+	Headers      []string `json:"headers"`      // 响应头特征
+	BodyPatterns []string `json:"bodyPatterns"` // 拦截页特征
+	Enabled      bool     `json:"enabled"`
+	IsBuiltin    bool     `json:"isBuiltin"`
+	CreateTime   string   `json:"createTime"`
+	UpdateTime   string   `json:"updateTime"`
+}
+
+// CDNProviderListReq CDN识别规则列表请求
+type CDNProviderListReq struct {
+	Page     int    `json:"page,default=1"`
+	PageSize int    `json:"pageSize,default=50"`
+	Keyword  string `json:"keyword,optional"`
+	Type     string `json:"type,optional"`
+	Enabled  *bool  `json:"enabled,optional"`
+}
+
+// CDNProviderListResp CDN识别规则列表响应
+type CDNProviderListResp struct {
+	Code  int           `json:"code"`
+	Msg   string        `json:"msg"`
+	Total int64         `json:"total"`
+	List  []CDNProvider `json:"list"`
+}
+
+// CDNProviderSaveReq 保存CDN识别规则请求
+type CDNProviderSaveReq struct {
+	Id           string   `json:"id,optional"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	CNAMEs       []string `json:"cnames,optional"`
+	CIDRs        []string `json:"cidrs,optional"`
+	Headers      []string `json:"headers,optional"`
+	BodyPatterns []string `json:"bodyPatterns,optional"`
+	Enabled      bool     `json:"enabled"`
+}
+
+// CDNProviderDeleteReq 删除CDN识别规则请求
+type CDNProviderDeleteReq struct {
+	Id string `json:"id"`
+}
+
+// CDNProviderUpdateEnabledReq 更新CDN识别规则启用状态请求
+type CDNProviderUpdateEnabledReq struct {
+	Id      string `json:"id"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ==================== 敏感信息规则 ====================
+
+// SecretRule 爬虫敏感信息匹配规则
+type SecretRule struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"` // 正则表达式，含捕获组时取第一个捕获组作为泄露值
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	IsBuiltin   bool   `json:"isBuiltin"`
+	CreateTime  string `json:"createTime"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+// SecretRuleListReq 敏感信息规则列表请求
+type SecretRuleListReq struct {
+	Page     int    `json:"page,default=1"`
+	PageSize int    `json:"pageSize,default=50"`
+	Keyword  string `json:"keyword,optional"`
+	Severity string `json:"severity,optional"`
+	Enabled  *bool  `json:"enabled,optional"`
+}
+
+// SecretRuleListResp 敏感信息规则列表响应
+type SecretRuleListResp struct {
+	Code  int          `json:"code"`
+	Msg   string       `json:"msg"`
+	Total int64        `json:"total"`
+	List  []SecretRule `json:"list"`
+}
+
+// SecretRuleSaveReq 保存敏感信息规则请求
+type SecretRuleSaveReq struct {
+	Id          string `json:"id,optional"`
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"`
+	Severity    string `json:"severity,optional"`
+	Description string `json:"description,optional"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// SecretRuleDeleteReq 删除敏感信息规则请求
+type SecretRuleDeleteReq struct {
+	Id string `json:"id"`
+}
+
+// SecretRuleUpdateEnabledReq 更新敏感信息规则启用状态请求
+type SecretRuleUpdateEnabledReq struct {
+	Id      string `json:"id"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ==================== IP归属地数据集 ====================
+
+// GeoIPDataset IP归属地/ASN数据集
+type GeoIPDataset struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"` // mmdb/ip2region
+	FileName    string `json:"fileName"`
+	Size        int64  `json:"size"`
+	Sha256      string `json:"sha256"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	CreateTime  string `json:"createTime"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+// GeoIPDatasetListResp IP归属地数据集列表响应
+type GeoIPDatasetListResp struct {
+	Code  int            `json:"code"`
+	Msg   string         `json:"msg"`
+	Total int            `json:"total"`
+	List  []GeoIPDataset `json:"list"`
+}
+
+// GeoIPDatasetUploadReq 上传IP归属地数据集请求（multipart 表单，文件字段为 file）
+type GeoIPDatasetUploadReq struct {
+	Name        string `form:"name"`
+	Type        string `form:"type,optional"` // 为空时按文件扩展名识别
+	Description string `form:"description,optional"`
+	Enabled     bool   `form:"enabled,optional"`
+}
+
+// GeoIPDatasetDeleteReq 删除IP归属地数据集请求
+type GeoIPDatasetDeleteReq struct {
+	Id string `json:"id"`
+}
+
+// GeoIPDatasetUpdateEnabledReq 更新IP归属地数据集启用状态请求
+type GeoIPDatasetUpdateEnabledReq struct {
+	Id      string `json:"id"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ==================== 漏洞情报库 ====================
+
+// VulnFeedImportReq 导入离线漏洞情报请求（multipart 表单，文件字段为 file）
+// 支持 NVD 2.0 JSON 和 OSV JSON，可为 .gz 或 .zip 压缩包
+type VulnFeedImportReq struct {
+	Source string `form:"source,optional"` // nvd/osv，为空时按文件内容识别
+}
+
+// VulnFeedImportResp 导入离线漏洞情报响应
+type VulnFeedImportResp struct {
+	Code     int    `json:"code"`
+	Msg      string `json:"msg"`
+	Total    int    `json:"total"`
+	Inserted int    `json:"inserted"`
+	Updated  int    `json:"updated"`
+}
+
+// VulnFeedStatsResp 漏洞情报库统计响应
+type VulnFeedStatsResp struct {
+	Code       int              `json:"code"`
+	Msg        string           `json:"msg"`
+	Total      int64            `json:"total"`
+	BySource   map[string]int64 `json:"bySource"`
+	BySeverity map[string]int64 `json:"bySeverity"`
+	LastUpdate string           `json:"lastUpdate"`
+}
+
+// VulnFeedClearReq 清空漏洞情报请求
+type VulnFeedClearReq struct {
+	Source string `json:"source,optional"` // 为空时清空全部
+}
+
 // ==================== 通知配置 ====================
 
 // NotifyConfig 通知配置
@@ -2062,6 +2843,7 @@ type HighRiskFilter struct {
 	HighRiskPorts         []int    `json:"highRiskPorts"`         // 高危端口列表
 	HighRiskPocSeverities []string `json:"highRiskPocSeverities"` // 高危POC严重级别: critical, high
 	NewAssetNotify        bool     `json:"newAssetNotify"`        // 新资产发现时通知
+	DiffOnly              bool     `json:"diffOnly,optional"`     // 定时任务仅在与上次执行有差异时通知
 }
 
 // NotifyConfigListResp 通知配置列表响应
@@ -2095,6 +2877,57 @@ type NotifyConfigTestReq struct {
 	MessageTemplate string `json:"messageTemplate,optional"`
 }
 
+// NotifyRoute 通知路由规则
+type NotifyRoute struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	Events       []string `json:"events"`
+	WorkspaceIds []string `json:"workspaceIds"`
+	OrgIds       []string `json:"orgIds"`
+	MinSeverity  string   `json:"minSeverity"`
+	Tags         []string `json:"tags"`
+	Labels       []string `json:"labels"`
+	ConfigIds    []string `json:"configIds"`
+	Template     string   `json:"template"`
+	QuietStart   string   `json:"quietStart"`
+	QuietEnd     string   `json:"quietEnd"`
+	Digest       string   `json:"digest"`
+	Enabled      bool     `json:"enabled"`
+	Description  string   `json:"description"`
+	CreateTime   string   `json:"createTime"`
+}
+
+// NotifyRouteListResp 通知路由规则列表响应
+type NotifyRouteListResp struct {
+	Code int           `json:"code"`
+	Msg  string        `json:"msg"`
+	List []NotifyRoute `json:"list"`
+}
+
+// NotifyRouteSaveReq 保存通知路由规则请求
+type NotifyRouteSaveReq struct {
+	Id           string   `json:"id,optional"`
+	Name         string   `json:"name"`
+	Events       []string `json:"events,optional"`
+	WorkspaceIds []string `json:"workspaceIds,optional"`
+	OrgIds       []string `json:"orgIds,optional"`
+	MinSeverity  string   `json:"minSeverity,optional"`
+	Tags         []string `json:"tags,optional"`
+	Labels       []string `json:"labels,optional"`
+	ConfigIds    []string `json:"configIds"`
+	Template     string   `json:"template,optional"`
+	QuietStart   string   `json:"quietStart,optional"`
+	QuietEnd     string   `json:"quietEnd,optional"`
+	Digest       string   `json:"digest,optional"`
+	Enabled      bool     `json:"enabled,optional"`
+	Description  string   `json:"description,optional"`
+}
+
+// NotifyRouteDeleteReq 删除通知路由规则请求
+type NotifyRouteDeleteReq struct {
+	Id string `json:"id"`
+}
+
 // NotifyProvider 通知提供者信息
 type NotifyProvider struct {
 	Id           string              `json:"id"`
@@ -2515,6 +3348,55 @@ type CompareVersionsResp struct {
 	ComparisonDetail string            `json:"comparisonDetail"`
 }
 
+// ==================== 定时任务执行对比 ====================
+
+// TaskDiffReq 对比任务两次执行结果请求
+type TaskDiffReq struct {
+	TaskId     string `json:"taskId"`              // 本次执行的任务ID
+	BaseTaskId string `json:"baseTaskId,optional"` // 对比基准，为空时取同一定时任务的上一次成功执行
+}
+
+// TaskDiffChange 资产字段变化
+type TaskDiffChange struct {
+	Authority string `json:"authority"`
+	Field     string `json:"field"` // service/title/app/status/cert
+	OldValue  string `json:"oldValue"`
+	NewValue  string `json:"newValue"`
+}
+
+// TaskDiffVul 新增或已修复的漏洞
+type TaskDiffVul struct {
+	Authority string `json:"authority"`
+	Url       string `json:"url"`
+	PocFile   string `json:"pocFile"`
+	VulName   string `json:"vulName"`
+	Severity  string `json:"severity"`
+}
+
+// TaskDiffData 两次执行的差异
+type TaskDiffData struct {
+	BaseTaskId   string           `json:"baseTaskId"`
+	BaseTaskName string           `json:"baseTaskName"`
+	BaseTime     string           `json:"baseTime"`
+	TaskId       string           `json:"taskId"`
+	TaskName     string           `json:"taskName"`
+	Time         string           `json:"time"`
+	NewAssets    []string         `json:"newAssets"`    // 新出现的主机
+	GoneAssets   []string         `json:"goneAssets"`   // 消失的主机
+	NewPorts     []string         `json:"newPorts"`     // 已有主机新开放的端口
+	ClosedPorts  []string         `json:"closedPorts"`  // 已有主机关闭的端口
+	Changes      []TaskDiffChange `json:"changes"`      // 标题、应用、证书等变化
+	NewVuls      []TaskDiffVul    `json:"newVuls"`      // 新增漏洞
+	ResolvedVuls []TaskDiffVul    `json:"resolvedVuls"` // 已修复漏洞
+}
+
+// TaskDiffResp 对比任务两次执行结果响应
+type TaskDiffResp struct {
+	Code int           `json:"code"`
+	Msg  string        `json:"msg"`
+	Data *TaskDiffData `json:"data,omitempty"`
+}
+
 // ==================== 任务分片管理 ====================
 
 // ChunkProgressReq 分片进度查询请求
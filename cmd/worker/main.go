@@ -1,19 +1,13 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"os/signal"
-	"runtime"
 	"strconv"
 	"strings"
 	"syscall"
-	"time"
 
 	"cscan/worker"
 
@@ -26,7 +20,10 @@ var (
 	serverAddr  = flag.String("s", getEnvOrDefault("CSCAN_SERVER", "http://localhost:8888"), "API server address (e.g., http://192.168.1.100:8888)")
 	workerName  = flag.String("n", getEnvOrDefault("CSCAN_NAME", ""), "worker name (default: hostname-pid)")
 	concurrency = flag.Int("c", getEnvIntOrDefault("CSCAN_CONCURRENCY", 5), "concurrency")
-	installKey  = flag.String("k", getEnvOrDefault("CSCAN_KEY", ""), "install key for authentication")
+	installKey  = flag.String("k", getEnvOrDefault("CSCAN_KEY", ""), "install key for enrollment (only needed until a credential is saved)")
+	credFile    = flag.String("cred", getEnvOrDefault("CSCAN_CREDENTIAL", "worker_credential.json"), "worker credential file written after enrollment")
+	externalDef = flag.String("e", getEnvOrDefault("CSCAN_EXTERNAL_SCANNERS", ""), "external scanner definition file (yaml/json)")
+	geoipDir    = flag.String("g", getEnvOrDefault("CSCAN_GEOIP_DIR", "geoip"), "geoip database dir (*.mmdb/*.xdb), empty to disable")
 )
 
 // getEnvOrDefault 获取环境变量，如果不存在则返回默认值
@@ -47,41 +44,35 @@ func getEnvIntOrDefault(key string, defaultVal int) int {
 	return defaultVal
 }
 
-// validateInstallKey 验证安装密钥
-func validateInstallKey(apiServer, key, name string) error {
-	reqBody := map[string]string{
-		"installKey": key,
-		"workerName": name,
-		"workerIP":   worker.GetLocalIP(),
-		"workerOS":   runtime.GOOS,
-		"workerArch": runtime.GOARCH,
+// loadOrEnroll 优先使用本地保存的Worker凭证，没有可用凭证时使用安装密钥注册并保存
+// 凭证被吊销后不会自动重新注册，需要管理员确认后删除凭证文件再使用安装密钥注册
+func loadOrEnroll(apiServer, name string) (*worker.Credential, error) {
+	cred, err := worker.LoadCredential(*credFile)
+	if err != nil {
+		return nil, fmt.Errorf("load credential %s failed: %w", *credFile, err)
+	}
+	if cred != nil && cred.ServerAddr == apiServer && (name == "" || name == cred.WorkerName) {
+		logx.Infof("🔑 Using saved credential: %s", *credFile)
+		return cred, nil
 	}
-	jsonData, _ := json.Marshal(reqBody)
-
-	// 构建API地址
-	url := fmt.Sprintf("%s/api/v1/worker/validate", apiServer)
-
-	// 发送验证请求，带重试
-	for i := 0; i < 3; i++ {
-		resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-		if err != nil {
-			logx.Infof("⚠️  Validation attempt %d failed: %v, retrying...", i+1, err)
-			time.Sleep(time.Duration(i+1) * time.Second)
-			continue
-		}
-		defer resp.Body.Close()
 
-		body, _ := io.ReadAll(resp.Body)
-		var result struct {
-			Code  int    `json:"code"`
-			Msg   string `json:"msg"`
-			Valid bool   `json:"valid"`
-		}
-		json.Unmarshal(body, &result)
-		if result.Code != 0 || !result.Valid { return fmt.Errorf("validation failed: %s", result.Msg) }
-		return nil
+	// 强制要求安装密钥
+	if *installKey == "" {
+		return nil, fmt.Errorf("install key is required (-k flag) for the first enrollment, please get it from the admin panel")
+	}
+	if name == "" {
+		name = worker.GetWorkerName()
+	}
+	logx.Infof("🔑 Enrolling worker: %s", name)
+	cred, err = worker.EnrollWorker(apiServer, *installKey, name)
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("validation failed after 3 attempts")
+	if err := worker.SaveCredential(*credFile, cred); err != nil {
+		return nil, fmt.Errorf("save credential %s failed: %w", *credFile, err)
+	}
+	logx.Infof("🔑 Credential saved to %s", *credFile)
+	return cred, nil
 }
 
 func main() {
@@ -107,19 +98,6 @@ func main() {
 	fmt.Println("---------------------------------------------------------")
 	logx.Info("🚀 Initializing CScan Worker Node...")
 
-	// 生成Worker名称
-	name := *workerName
-	if name == "" {
-		name = worker.GetWorkerName()
-	}
-
-	// 强制要求安装密钥
-	if *installKey == "" {
-		logx.Error("❌ Error: install key is required (-k flag)")
-		logx.Error("   Please get the install key from the admin panel")
-		os.Exit(1)
-	}
-
 	// 确定API服务器地址
 	apiServer := *serverAddr
 	// 确保地址有协议前缀
@@ -129,24 +107,28 @@ func main() {
 
 	fmt.Println("---------------------------------------------------------")
 	logx.Infof("🔗 Connecting to API Server: %s", apiServer)
-	logx.Infof("🔑 Validating Identity for: %s", name)
 
-	// 验证安装密钥
-	if err := validateInstallKey(apiServer, *installKey, name); err != nil {
-	logx.Errorf("❌ Authentication failed: %v", err)
+	// 获取Worker凭证，Worker名称以凭证绑定的名称为准
+	cred, err := loadOrEnroll(apiServer, *workerName)
+	if err != nil {
+		logx.Errorf("❌ Authentication failed: %v", err)
 		os.Exit(1)
 	}
-	logx.Info("✅ Identity verified successfully")
+	name := cred.WorkerName
+	logx.Infof("✅ Identity verified successfully: %s", name)
 	// 获取本机IP
 	ip := worker.GetLocalIP()
 
 	config := worker.WorkerConfig{
-		Name:        name,
-		IP:          ip,
-		ServerAddr:  apiServer,
-		InstallKey:  *installKey,
-		Concurrency: *concurrency,
-		Timeout:     3600,
+		Name:                name,
+		IP:                  ip,
+		ServerAddr:          apiServer,
+		Token:               cred.Token,
+		CredentialFile:      *credFile,
+		Concurrency:         *concurrency,
+		Timeout:             3600,
+		ExternalScannerFile: *externalDef,
+		GeoIPDir:            *geoipDir,
 	}
 
 	w, err := worker.NewWorker(config)
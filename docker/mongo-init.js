@@ -16,7 +16,7 @@ var defaultWorkspaceId = workspaceResult.insertedId.toString();
 db.user.insertOne({
     username: "admin",
     password: "$2a$10$Y/T1J1j6tEB9KQI2FlpyNOK3DY2eT54Ml1ukG.dMrbCjMt5Ic7MwK", // 123456的bcrypt哈希
-    role: "superadmin",
+    role: "admin",
     status: "enable",
     workspace_ids: [defaultWorkspaceId],
     create_time: new Date(),
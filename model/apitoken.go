@@ -0,0 +1,173 @@
+package model
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ApiTokenPrefix API Token 前缀，用于和JWT区分
+const ApiTokenPrefix = "cst_"
+
+// API Token 权限范围
+const (
+	ApiTokenScopeRead = "read" // 只读
+	ApiTokenScopeTask = "task" // 只读 + 创建/启停任务，不能删除
+)
+
+// apiTokenScopePermissions 权限范围允许的权限，最终权限还受用户角色限制
+// 删除类操作（PermTaskDelete、PermAssetDelete）不授予任何权限范围
+var apiTokenScopePermissions = map[string]map[Permission]bool{
+	ApiTokenScopeRead: permissionSet(PermView),
+	ApiTokenScopeTask: permissionSet(PermView, PermTaskManage),
+}
+
+// IsValidApiTokenScope 检查权限范围是否合法
+func IsValidApiTokenScope(scope string) bool {
+	_, ok := apiTokenScopePermissions[scope]
+	return ok
+}
+
+// ApiTokenScopeAllows 判断权限范围是否包含指定权限
+func ApiTokenScopeAllows(scopes []string, perm Permission) bool {
+	for _, scope := range scopes {
+		if apiTokenScopePermissions[scope][perm] {
+			return true
+		}
+	}
+	return false
+}
+
+// ApiToken 个人访问令牌，只保存哈希
+type ApiToken struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserId       string             `bson:"user_id" json:"userId"`
+	Username     string             `bson:"username" json:"username"`
+	Name         string             `bson:"name" json:"name"`
+	TokenHash    string             `bson:"token_hash" json:"-"`
+	TokenHint    string             `bson:"token_hint" json:"tokenHint"` // 明文前几位，便于识别
+	WorkspaceId  string             `bson:"workspace_id" json:"workspaceId"`
+	Scopes       []string           `bson:"scopes" json:"scopes"`
+	ExpireTime   *time.Time         `bson:"expire_time,omitempty" json:"expireTime"` // 为空表示永不过期
+	Revoked      bool               `bson:"revoked" json:"revoked"`
+	LastUsedTime *time.Time         `bson:"last_used_time,omitempty" json:"lastUsedTime"`
+	LastUsedIP   string             `bson:"last_used_ip,omitempty" json:"lastUsedIp"`
+	CreateTime   time.Time          `bson:"create_time" json:"createTime"`
+}
+
+// ApiTokenModel API Token 模型
+type ApiTokenModel struct {
+	*BaseModel[ApiToken]
+}
+
+// NewApiTokenModel 创建 API Token 模型
+func NewApiTokenModel(db *mongo.Database) *ApiTokenModel {
+	coll := db.Collection("api_token")
+	m := &ApiTokenModel{
+		BaseModel: NewBaseModel[ApiToken](coll),
+	}
+
+	ctx := context.Background()
+	m.EnsureIndexes(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+	})
+
+	return m
+}
+
+// GenerateApiToken 生成新的明文Token及其哈希
+func GenerateApiToken() (token, hash string, err error) {
+	buf := make([]byte, 24)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = ApiTokenPrefix + hex.EncodeToString(buf)
+	return token, HashApiToken(token), nil
+}
+
+// HashApiToken 计算Token哈希。Token本身是高熵随机串，SHA-256 即可，且支持按哈希直接查找
+func HashApiToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsApiToken 判断凭证是否为 API Token
+func IsApiToken(token string) bool {
+	return strings.HasPrefix(token, ApiTokenPrefix)
+}
+
+// Create 创建Token
+func (m *ApiTokenModel) Create(ctx context.Context, doc *ApiToken) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	doc.CreateTime = time.Now()
+	return m.Insert(ctx, doc)
+}
+
+// Active 判断Token在指定时间是否有效（未吊销、未过期）
+func (t *ApiToken) Active(now time.Time) bool {
+	if t.Revoked {
+		return false
+	}
+	return t.ExpireTime == nil || now.Before(*t.ExpireTime)
+}
+
+// FindActiveByToken 根据明文Token查找有效（未吊销、未过期）的记录，不存在返回 nil
+func (m *ApiTokenModel) FindActiveByToken(ctx context.Context, token string) (*ApiToken, error) {
+	doc, err := m.FindOne(ctx, bson.M{"token_hash": HashApiToken(token), "revoked": false})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !doc.Active(time.Now()) {
+		return nil, nil
+	}
+	return doc, nil
+}
+
+// FindByUser 查询用户的全部Token
+func (m *ApiTokenModel) FindByUser(ctx context.Context, userId string) ([]ApiToken, error) {
+	return m.FindWithSort(ctx, bson.M{"user_id": userId}, 0, 0, "create_time", -1)
+}
+
+// Revoke 吊销用户自己的Token
+func (m *ApiTokenModel) Revoke(ctx context.Context, userId, id string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+	res, err := m.Coll.UpdateOne(ctx,
+		bson.M{"_id": oid, "user_id": userId},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// Touch 记录最近使用时间和IP
+func (m *ApiTokenModel) Touch(ctx context.Context, id primitive.ObjectID, ip string) error {
+	_, err := m.Coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"last_used_time": time.Now(),
+		"last_used_ip":   ip,
+	}})
+	return err
+}
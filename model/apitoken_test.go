@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApiTokenActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	cases := []struct {
+		name  string
+		token ApiToken
+		want  bool
+	}{
+		{"never expires", ApiToken{}, true},
+		{"not yet expired", ApiToken{ExpireTime: &future}, true},
+		{"expired", ApiToken{ExpireTime: &past}, false},
+		{"revoked", ApiToken{Revoked: true}, false},
+		{"revoked before expiry", ApiToken{Revoked: true, ExpireTime: &future}, false},
+	}
+	for _, c := range cases {
+		if got := c.token.Active(now); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestApiTokenScopeAllows(t *testing.T) {
+	if !ApiTokenScopeAllows([]string{ApiTokenScopeRead}, PermView) {
+		t.Error("read scope should allow view")
+	}
+	if ApiTokenScopeAllows([]string{ApiTokenScopeRead}, PermTaskManage) {
+		t.Error("read scope must not allow task management")
+	}
+	if !ApiTokenScopeAllows([]string{ApiTokenScopeTask}, PermTaskManage) {
+		t.Error("task scope should allow task management")
+	}
+	for _, perm := range []Permission{PermAssetDelete, PermTaskDelete, PermUserManage, PermSettings, PermWorkerConsole} {
+		if ApiTokenScopeAllows([]string{ApiTokenScopeRead, ApiTokenScopeTask}, perm) {
+			t.Errorf("no scope should allow %s", perm)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"time"
 
+	"cscan/pkg/utils"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,11 +17,21 @@ type IPV4 struct {
 	IPName   string `bson:"ip" json:"ip"`
 	IPInt    uint32 `bson:"uint32" json:"uint32"`
 	Location string `bson:"location" json:"location"`
+	Country  string `bson:"country,omitempty" json:"country,omitempty"`
+	Region   string `bson:"region,omitempty" json:"region,omitempty"`
+	City     string `bson:"city,omitempty" json:"city,omitempty"`
+	ASN      int    `bson:"asn,omitempty" json:"asn,omitempty"`
+	Org      string `bson:"org,omitempty" json:"org,omitempty"` // ASN 所属组织/运营商
 }
 
 type IPV6 struct {
 	IPName   string `bson:"ip" json:"ip"`
 	Location string `bson:"location" json:"location"`
+	Country  string `bson:"country,omitempty" json:"country,omitempty"`
+	Region   string `bson:"region,omitempty" json:"region,omitempty"`
+	City     string `bson:"city,omitempty" json:"city,omitempty"`
+	ASN      int    `bson:"asn,omitempty" json:"asn,omitempty"`
+	Org      string `bson:"org,omitempty" json:"org,omitempty"`
 }
 
 type IP struct {
@@ -27,11 +39,18 @@ type IP struct {
 	IpV6 []IPV6 `bson:"ipv6,omitempty" json:"ipv6,omitempty"`
 }
 
+// 资产传输层协议
+const (
+	TransportTCP = "tcp"
+	TransportUDP = "udp"
+)
+
 type Asset struct {
 	Id                   primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Authority            string             `bson:"authority" json:"authority"`
 	Host                 string             `bson:"host" json:"host"`
 	Port                 int                `bson:"port" json:"port"`
+	Transport            string             `bson:"transport,omitempty" json:"transport,omitempty"` // 传输层协议，为空表示tcp
 	Category             string             `bson:"category" json:"category"`
 	Ip                   IP                 `bson:"ip" json:"ip"`
 	Domain               string             `bson:"domain,omitempty" json:"domain"`
@@ -45,17 +64,24 @@ type Asset struct {
 	HttpHeader           string             `bson:"header,omitempty" json:"httpHeader"`
 	HttpBody             string             `bson:"body,omitempty" json:"httpBody"`
 	Cert                 string             `bson:"cert,omitempty" json:"cert"`
+	CertInfo             *CertInfo          `bson:"cert_info,omitempty" json:"certInfo,omitempty"` // TLS证书结构化信息
 	IconHash             string             `bson:"icon_hash,omitempty" json:"iconHash"`
 	IconHashFile         string             `bson:"icon_hash_file,omitempty" json:"iconHashFile"`
 	IconHashBytes        []byte             `bson:"icon_hash_bytes,omitempty" json:"-"`
-	Screenshot           string             `bson:"screenshot,omitempty" json:"screenshot"`
-	Labels               []string           `bson:"labels,omitempty" json:"labels"` // 自定义标签
+	Screenshot           string             `bson:"screenshot,omitempty" json:"screenshot"`                     // 截图存储键，旧数据为 base64
+	ScreenshotHash       string             `bson:"screenshot_phash,omitempty" json:"screenshotHash,omitempty"` // 截图感知哈希，用于相似页面聚类
+	Labels               []string           `bson:"labels,omitempty" json:"labels"`                             // 自定义标签
 	OrgId                string             `bson:"org_id,omitempty" json:"orgId"`
+	ICP                  string             `bson:"icp,omitempty" json:"icp,omitempty"`                    // ICP备案号，来自在线测绘导入
+	CloudAccount         string             `bson:"cloud_account,omitempty" json:"cloudAccount,omitempty"` // 所属云账号，来自云资产导入
 	ColorTag             string             `bson:"color,omitempty" json:"colorTag"`
 	Memo                 string             `bson:"memo,omitempty" json:"memo"`
 	IsCDN                bool               `bson:"cdn,omitempty" json:"isCdn"`
 	CName                string             `bson:"cname,omitempty" json:"cname"`
 	IsCloud              bool               `bson:"cloud,omitempty" json:"isCloud"`
+	CDNProvider          string             `bson:"cdn_provider,omitempty" json:"cdnProvider,omitempty"`     // CDN服务商
+	CloudProvider        string             `bson:"cloud_provider,omitempty" json:"cloudProvider,omitempty"` // 云厂商
+	WAF                  string             `bson:"waf,omitempty" json:"waf,omitempty"`                      // WAF服务商
 	IsHTTP               bool               `bson:"is_http" json:"isHttp"`
 	IsNewAsset           bool               `bson:"new" json:"isNew"`
 	IsUpdated            bool               `bson:"update" json:"isUpdated"`
@@ -70,6 +96,10 @@ type Asset struct {
 	// 新增字段 - 风险评分
 	RiskScore float64 `bson:"risk_score,omitempty" json:"riskScore,omitempty"` // 0-100
 	RiskLevel string  `bson:"risk_level,omitempty" json:"riskLevel,omitempty"` // critical/high/medium/low/info/unknown
+
+	// 指纹版本转换的 CPE 及据此匹配到的潜在漏洞
+	CPE            []string        `bson:"cpe,omitempty" json:"cpe,omitempty"`
+	PotentialVulns []PotentialVuln `bson:"potential_vulns,omitempty" json:"potentialVulns,omitempty"`
 }
 
 type AssetModel struct {
@@ -143,9 +173,36 @@ func (m *AssetModel) FindByAuthorityOnly(ctx context.Context, authority string)
 	return &doc, nil
 }
 
+// FindByHostPort 按host+port查找TCP资产（UDP资产不参与匹配）
 func (m *AssetModel) FindByHostPort(ctx context.Context, host string, port int) (*Asset, error) {
 	var doc Asset
-	filter := bson.M{"host": host, "port": port}
+	err := m.coll.FindOne(ctx, tcpHostPortFilter(host, port)).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// NormalizeAuthority 将IPv6主机统一为压缩格式，authority统一为 [v6]:port，避免同一资产因写法不同重复入库
+func (a *Asset) NormalizeAuthority() {
+	a.Host = utils.NormalizeHost(a.Host)
+	if strings.Contains(a.Host, ":") {
+		a.Authority = utils.BuildTargetWithPort(a.Host, a.Port)
+	}
+}
+
+// tcpHostPortFilter 按host+port匹配TCP资产，兼容未记录transport的历史数据
+func tcpHostPortFilter(host string, port int) bson.M {
+	return bson.M{"host": host, "port": port, "transport": bson.M{"$ne": TransportUDP}}
+}
+
+// FindByHostPortTransport 按host+port+传输层协议查找资产
+func (m *AssetModel) FindByHostPortTransport(ctx context.Context, host string, port int, transport string) (*Asset, error) {
+	if transport != TransportUDP {
+		return m.FindByHostPort(ctx, host, port)
+	}
+	var doc Asset
+	filter := bson.M{"host": host, "port": port, "transport": TransportUDP}
 	err := m.coll.FindOne(ctx, filter).Decode(&doc)
 	if err != nil {
 		return nil, err
@@ -386,6 +443,21 @@ func (m *AssetModel) CountNewByTaskId(ctx context.Context, taskId string) (int64
 	return m.coll.CountDocuments(ctx, bson.M{"taskId": taskId, "new": true})
 }
 
+// FindAuthoritiesFirstSeenIn 查询在指定主任务中首次发现的资产 authority
+func (m *AssetModel) FindAuthoritiesFirstSeenIn(ctx context.Context, mainTaskId string) ([]string, error) {
+	values, err := m.coll.Distinct(ctx, "authority", bson.M{"first_seen_task_id": mainTaskId})
+	if err != nil {
+		return nil, err
+	}
+	authorities := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			authorities = append(authorities, s)
+		}
+	}
+	return authorities, nil
+}
+
 // FindByTaskId 根据任务ID查找资产列表
 func (m *AssetModel) FindByTaskId(ctx context.Context, taskId string) ([]Asset, error) {
 	return m.Find(ctx, bson.M{"taskId": taskId}, 0, 0)
@@ -401,6 +473,28 @@ func (m *AssetModel) Update(ctx context.Context, id string, update bson.M) error
 	return err
 }
 
+// UpdateScreenshotHash 更新资产截图的感知哈希
+func (m *AssetModel) UpdateScreenshotHash(ctx context.Context, host string, port int, phash string) error {
+	_, err := m.coll.UpdateOne(ctx, tcpHostPortFilter(host, port), bson.M{
+		"$set": bson.M{"screenshot_phash": phash},
+	})
+	return err
+}
+
+// UpdatePotentialVulns 写入资产的 CPE 和潜在漏洞，cpes 为空时清除旧结果
+func (m *AssetModel) UpdatePotentialVulns(ctx context.Context, host string, port int, transport string, cpes []string, vulns []PotentialVuln) error {
+	filter := tcpHostPortFilter(host, port)
+	if transport == TransportUDP {
+		filter = bson.M{"host": host, "port": port, "transport": TransportUDP}
+	}
+	update := bson.M{"$set": bson.M{"cpe": cpes, "potential_vulns": vulns}}
+	if len(cpes) == 0 {
+		update = bson.M{"$unset": bson.M{"cpe": "", "potential_vulns": ""}}
+	}
+	_, err := m.coll.UpdateOne(ctx, filter, update)
+	return err
+}
+
 // UpdateLabels 更新资产标签
 func (m *AssetModel) UpdateLabels(ctx context.Context, id string, labels []string) error {
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -808,6 +902,8 @@ func (m *AssetHistoryModel) ExistsByAssetIdAndTaskId(ctx context.Context, assetI
 
 // Upsert 插入或更新资产
 func (m *AssetModel) Upsert(ctx context.Context, doc *Asset) error {
+	doc.NormalizeAuthority()
+
 	// 仅按 authority 匹配，忽略 taskId，确保同一资产被合并
 	filter := bson.M{"authority": doc.Authority}
 
@@ -869,6 +965,15 @@ func (m *AssetModel) Upsert(ctx context.Context, doc *Asset) error {
 	if doc.Ip.IpV4 != nil || doc.Ip.IpV6 != nil {
 		setFields["ip"] = doc.Ip
 	}
+	if doc.OrgId != "" {
+		setFields["org_id"] = doc.OrgId
+	}
+	if doc.ICP != "" {
+		setFields["icp"] = doc.ICP
+	}
+	if doc.CloudAccount != "" {
+		setFields["cloud_account"] = doc.CloudAccount
+	}
 
 	// 如果有标签，使用 $addToSet 批量添加，避免覆盖原有标签
 	// 注意：由于 setFields 是 $set 操作，如果直接放 labels 会覆盖。
@@ -25,6 +25,11 @@ type ScanResultHistory struct {
 	ChangesSummary  string             `bson:"changes_summary,omitempty" json:"changesSummary,omitempty"`
 	ArchivedAt      time.Time          `bson:"archived_at" json:"archivedAt"`
 	CreateTime      time.Time          `bson:"create_time" json:"createTime"`
+
+	// Task run snapshot fields, set when a whole task run is archived (see ArchiveTaskRun)
+	TaskId string         `bson:"task_id,omitempty" json:"taskId,omitempty"`
+	Asset  *AssetSnapshot `bson:"asset,omitempty" json:"asset,omitempty"`
+	Vuls   []VulSnapshot  `bson:"vuls,omitempty" json:"vuls,omitempty"`
 }
 
 // ScanResultHistoryModel provides database operations for ScanResultHistory
@@ -59,6 +64,8 @@ func NewScanResultHistoryModel(db *mongo.Database, workspaceId string) *ScanResu
 		{Keys: bson.D{{Key: "archived_at", Value: -1}}},
 		// Index for version_id
 		{Keys: bson.D{{Key: "version_id", Value: 1}}},
+		// Index for task run snapshots
+		{Keys: bson.D{{Key: "task_id", Value: 1}}},
 	}
 	coll.Indexes().CreateMany(ctx, indexes)
 
@@ -247,3 +254,45 @@ func (m *ScanResultHistoryModel) Clear(ctx context.Context, workspaceId string)
 	}
 	return result.DeletedCount, nil
 }
+
+// FindByTaskId retrieves the snapshot records archived for a task run
+func (m *ScanResultHistoryModel) FindByTaskId(ctx context.Context, workspaceId, taskId string) ([]ScanResultHistory, error) {
+	filter := bson.M{
+		"workspace_id": workspaceId,
+		"task_id":      taskId,
+	}
+	return m.findWithFilter(ctx, filter, 0)
+}
+
+// ArchiveTaskRun stores one version per authority for a finished task run.
+// Archiving the same run again replaces its previous snapshot. A run without
+// any result is stored as a single marker record with an empty authority so
+// that it can still be told apart from a run that was never archived.
+func (m *ScanResultHistoryModel) ArchiveTaskRun(ctx context.Context, workspaceId, taskId string, assets []Asset, vuls []Vul) ([]ScanResultHistory, error) {
+	now := time.Now()
+	docs := BuildTaskRunSnapshot(workspaceId, taskId, assets, vuls, now)
+	if len(docs) == 0 {
+		docs = []ScanResultHistory{{
+			WorkspaceId:   workspaceId,
+			TaskId:        taskId,
+			VersionId:     primitive.NewObjectID().Hex(),
+			ScanTimestamp: now,
+		}}
+	}
+
+	if _, err := m.coll.DeleteMany(ctx, bson.M{"workspace_id": workspaceId, "task_id": taskId}); err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, len(docs))
+	for i := range docs {
+		docs[i].Id = primitive.NewObjectID()
+		docs[i].ArchivedAt = now
+		docs[i].CreateTime = now
+		items[i] = docs[i]
+	}
+	if _, err := m.coll.InsertMany(ctx, items); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
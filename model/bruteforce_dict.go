@@ -0,0 +1,170 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BruteforceDict 弱口令字典
+type BruteforceDict struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`               // 字典名称
+	Description string             `bson:"description" json:"description"` // 描述
+	Service     string             `bson:"service" json:"service"`         // 适用协议，all 表示全部协议
+	Content     string             `bson:"content" json:"content"`         // 字典内容（每行一个 username:password）
+	CredCount   int                `bson:"cred_count" json:"credCount"`    // 凭据数量
+	Enabled     bool               `bson:"enabled" json:"enabled"`         // 是否启用
+	IsBuiltin   bool               `bson:"is_builtin" json:"isBuiltin"`    // 是否内置字典
+	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// BruteforceDictModel 弱口令字典模型
+type BruteforceDictModel struct {
+	coll *mongo.Collection
+}
+
+func NewBruteforceDictModel(db *mongo.Database) *BruteforceDictModel {
+	return &BruteforceDictModel{
+		coll: db.Collection("bruteforce_dict"),
+	}
+}
+
+func (m *BruteforceDictModel) Insert(ctx context.Context, doc *BruteforceDict) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	_, err := m.coll.InsertOne(ctx, doc)
+	return err
+}
+
+func (m *BruteforceDictModel) FindAll(ctx context.Context, page, pageSize int) ([]BruteforceDict, error) {
+	opts := options.Find()
+	if page > 0 && pageSize > 0 {
+		opts.SetSkip(int64((page - 1) * pageSize))
+		opts.SetLimit(int64(pageSize))
+	}
+	opts.SetSort(bson.D{{Key: "is_builtin", Value: -1}, {Key: "create_time", Value: -1}})
+
+	cursor, err := m.coll.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []BruteforceDict
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (m *BruteforceDictModel) Count(ctx context.Context) (int64, error) {
+	return m.coll.CountDocuments(ctx, bson.M{})
+}
+
+func (m *BruteforceDictModel) FindEnabled(ctx context.Context) ([]BruteforceDict, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "is_builtin", Value: -1}, {Key: "service", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := m.coll.Find(ctx, bson.M{"enabled": true}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []BruteforceDict
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (m *BruteforceDictModel) FindById(ctx context.Context, id string) (*BruteforceDict, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var doc BruteforceDict
+	err = m.coll.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc)
+	return &doc, err
+}
+
+func (m *BruteforceDictModel) FindByIds(ctx context.Context, ids []string) ([]BruteforceDict, error) {
+	var oids []primitive.ObjectID
+	for _, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		oids = append(oids, oid)
+	}
+	if len(oids) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := m.coll.Find(ctx, bson.M{"_id": bson.M{"$in": oids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []BruteforceDict
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (m *BruteforceDictModel) FindByName(ctx context.Context, name string) (*BruteforceDict, error) {
+	var doc BruteforceDict
+	err := m.coll.FindOne(ctx, bson.M{"name": name}).Decode(&doc)
+	return &doc, err
+}
+
+func (m *BruteforceDictModel) Update(ctx context.Context, id string, doc *BruteforceDict) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	update := bson.M{
+		"name":        doc.Name,
+		"description": doc.Description,
+		"service":     doc.Service,
+		"content":     doc.Content,
+		"cred_count":  doc.CredCount,
+		"enabled":     doc.Enabled,
+		"update_time": time.Now(),
+	}
+	_, err = m.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": update})
+	return err
+}
+
+func (m *BruteforceDictModel) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = m.coll.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+// CountBuiltin 统计内置字典数量
+func (m *BruteforceDictModel) CountBuiltin(ctx context.Context) (int64, error) {
+	return m.coll.CountDocuments(ctx, bson.M{"is_builtin": true})
+}
+
+// DeleteNonBuiltin 删除所有非内置字典
+func (m *BruteforceDictModel) DeleteNonBuiltin(ctx context.Context) (int64, error) {
+	result, err := m.coll.DeleteMany(ctx, bson.M{"is_builtin": bson.M{"$ne": true}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CDNProvider CDN/WAF/云厂商识别规则
+type CDNProvider struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string             `bson:"name" json:"name"`                  // 服务商名称，唯一
+	Type         string             `bson:"type" json:"type"`                  // cdn/waf/cloud
+	CNAMEs       []string           `bson:"cnames" json:"cnames"`              // CNAME 后缀
+	CIDRs        []string           `bson:"cidrs" json:"cidrs"`                // 服务商IP段
+	Headers      []string           `bson:"headers" json:"headers"`            // 响应头特征
+	BodyPatterns []string           `bson:"body_patterns" json:"bodyPatterns"` // 拦截页特征
+	Enabled      bool               `bson:"enabled" json:"enabled"`
+	IsBuiltin    bool               `bson:"is_builtin" json:"isBuiltin"`
+	CreateTime   time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime   time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// CDNProviderModel CDN识别规则模型
+type CDNProviderModel struct {
+	*BaseModel[CDNProvider]
+}
+
+// NewCDNProviderModel 创建CDN识别规则模型
+func NewCDNProviderModel(db *mongo.Database) *CDNProviderModel {
+	coll := db.Collection("cdn_provider")
+	m := &CDNProviderModel{
+		BaseModel: NewBaseModel[CDNProvider](coll),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "enabled", Value: 1}}},
+	})
+
+	return m
+}
+
+// Create 新建识别规则
+func (m *CDNProviderModel) Create(ctx context.Context, doc *CDNProvider) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	return m.Insert(ctx, doc)
+}
+
+// Update 更新识别规则
+func (m *CDNProviderModel) Update(ctx context.Context, id string, doc *CDNProvider) error {
+	return m.UpdateById(ctx, id, bson.M{
+		"name":          doc.Name,
+		"type":          doc.Type,
+		"cnames":        doc.CNAMEs,
+		"cidrs":         doc.CIDRs,
+		"headers":       doc.Headers,
+		"body_patterns": doc.BodyPatterns,
+		"enabled":       doc.Enabled,
+	})
+}
+
+// FindByName 按名称查找，不存在返回 nil
+func (m *CDNProviderModel) FindByName(ctx context.Context, name string) (*CDNProvider, error) {
+	doc, err := m.FindOne(ctx, bson.M{"name": name})
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// FindEnabled 查询全部已启用的识别规则
+func (m *CDNProviderModel) FindEnabled(ctx context.Context) ([]CDNProvider, error) {
+	return m.FindWithSort(ctx, bson.M{"enabled": true}, 0, 0, "name", 1)
+}
+
+// CountBuiltin 内置识别规则数量
+func (m *CDNProviderModel) CountBuiltin(ctx context.Context) (int64, error) {
+	return m.Count(ctx, bson.M{"is_builtin": true})
+}
+
+// UpdateCDNInfo 更新资产的CDN/WAF/云厂商标记，port 为 0 时按 authority 匹配
+func (m *AssetModel) UpdateCDNInfo(ctx context.Context, authority, host string, port int, isCDN, isCloud bool, cdnProvider, cloudProvider, waf string) error {
+	filter := bson.M{"authority": authority}
+	if port > 0 {
+		filter = bson.M{"host": host, "port": port}
+	}
+	_, err := m.coll.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"cdn":            isCDN,
+			"cloud":          isCloud,
+			"cdn_provider":   cdnProvider,
+			"cloud_provider": cloudProvider,
+			"waf":            waf,
+		},
+	})
+	return err
+}
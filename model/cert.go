@@ -0,0 +1,46 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CertDetail 单张证书信息
+type CertDetail struct {
+	Subject            string    `bson:"subject" json:"subject"`
+	CommonName         string    `bson:"common_name" json:"commonName"`
+	SANs               []string  `bson:"sans,omitempty" json:"sans,omitempty"`
+	Issuer             string    `bson:"issuer" json:"issuer"`
+	IssuerCommonName   string    `bson:"issuer_common_name" json:"issuerCommonName"`
+	SerialNumber       string    `bson:"serial_number" json:"serialNumber"`
+	NotBefore          time.Time `bson:"not_before" json:"notBefore"`
+	NotAfter           time.Time `bson:"not_after" json:"notAfter"`
+	KeyType            string    `bson:"key_type" json:"keyType"`
+	KeyBits            int       `bson:"key_bits" json:"keyBits"`
+	SignatureAlgorithm string    `bson:"signature_algorithm" json:"signatureAlgorithm"`
+	SHA256             string    `bson:"sha256" json:"sha256"`
+	SPKISHA256         string    `bson:"spki_sha256" json:"spkiSha256"`
+	IsCA               bool      `bson:"is_ca" json:"isCa"`
+}
+
+// CertInfo TLS服务证书信息，叶子证书字段在顶层，Chain 为服务端发送的完整证书链
+type CertInfo struct {
+	CertDetail         `bson:",inline"`
+	Chain              []CertDetail `bson:"chain" json:"chain"`
+	TLSVersion         string       `bson:"tls_version" json:"tlsVersion"`
+	CipherSuite        string       `bson:"cipher_suite" json:"cipherSuite"`
+	SelfSigned         bool         `bson:"self_signed" json:"selfSigned"`
+	HostnameMatch      bool         `bson:"hostname_match" json:"hostnameMatch"`
+	DeprecatedVersions []string     `bson:"deprecated_versions,omitempty" json:"deprecatedVersions,omitempty"`
+	WeakCiphers        []string     `bson:"weak_ciphers,omitempty" json:"weakCiphers,omitempty"`
+}
+
+// UpdateCertInfo 更新资产的证书信息
+func (m *AssetModel) UpdateCertInfo(ctx context.Context, host string, port int, cert string, info *CertInfo) error {
+	_, err := m.coll.UpdateOne(ctx, tcpHostPortFilter(host, port), bson.M{
+		"$set": bson.M{"cert": cert, "cert_info": info},
+	})
+	return err
+}
@@ -10,7 +10,14 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// 命令历史类型
+const (
+	CommandHistoryTypeCommand = "command" // 单条命令
+	CommandHistoryTypeSession = "session" // 终端会话录像
+)
+
 // CommandHistory 命令执行历史
+// Type 为 session 时记录整个交互式终端会话，Recording 保存 asciicast v2 录像供审计回放
 type CommandHistory struct {
 	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	WorkerName string             `bson:"worker_name" json:"workerName"`   // Worker名称
@@ -23,6 +30,9 @@ type CommandHistory struct {
 	Operator   string             `bson:"operator" json:"operator"`        // 操作人
 	ClientIP   string             `bson:"client_ip" json:"clientIp"`       // 客户端IP
 	CreateTime time.Time          `bson:"create_time" json:"createTime"`   // 创建时间
+	Type       string             `bson:"type,omitempty" json:"type"`      // 类型，为空时等同于 command
+	Recording  string             `bson:"recording,omitempty" json:"-"`    // asciicast v2 录像，仅会话记录有
+	Truncated  bool               `bson:"truncated,omitempty" json:"truncated"` // 录像超过大小上限被截断
 }
 
 // CommandHistoryModel 命令历史模型
@@ -72,6 +82,40 @@ func (m *CommandHistoryModel) RecordCommand(ctx context.Context, history *Comman
 	return m.Insert(ctx, history)
 }
 
+// RecordSession 保存终端会话录像
+func (m *CommandHistoryModel) RecordSession(ctx context.Context, history *CommandHistory) error {
+	history.Type = CommandHistoryTypeSession
+	return m.RecordCommand(ctx, history)
+}
+
+// GetRecording 获取会话录像，记录不存在或没有录像时返回 nil
+func (m *CommandHistoryModel) GetRecording(ctx context.Context, id string) (*CommandHistory, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := m.FindOne(ctx, bson.M{"_id": oid, "type": CommandHistoryTypeSession})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+// GetSessionRecording 根据 Worker 和会话ID获取会话录像，不存在时返回 nil
+func (m *CommandHistoryModel) GetSessionRecording(ctx context.Context, workerName, sessionId string) (*CommandHistory, error) {
+	doc, err := m.FindOne(ctx, bson.M{"worker_name": workerName, "session_id": sessionId, "type": CommandHistoryTypeSession})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
 // GetByWorker 获取Worker的命令历史
 func (m *CommandHistoryModel) GetByWorker(ctx context.Context, workerName string, page, pageSize int) ([]CommandHistory, int64, error) {
 	filter := bson.M{"worker_name": workerName}
@@ -81,7 +125,11 @@ func (m *CommandHistoryModel) GetByWorker(ctx context.Context, workerName string
 		return nil, 0, err
 	}
 
-	histories, err := m.FindWithSort(ctx, filter, page, pageSize, "create_time", -1)
+	opts := options.Find().SetSort(bson.D{{Key: "create_time", Value: -1}})
+	if page > 0 && pageSize > 0 {
+		opts.SetSkip(int64((page - 1) * pageSize)).SetLimit(int64(pageSize))
+	}
+	histories, err := m.findWithoutRecording(ctx, filter, opts)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -92,7 +140,7 @@ func (m *CommandHistoryModel) GetByWorker(ctx context.Context, workerName string
 // GetBySession 获取会话的命令历史
 func (m *CommandHistoryModel) GetBySession(ctx context.Context, sessionId string) ([]CommandHistory, error) {
 	filter := bson.M{"session_id": sessionId}
-	return m.FindWithSort(ctx, filter, 0, 0, "create_time", 1)
+	return m.findWithoutRecording(ctx, filter, options.Find().SetSort(bson.D{{Key: "create_time", Value: 1}}))
 }
 
 // GetRecent 获取最近的命令历史
@@ -100,8 +148,14 @@ func (m *CommandHistoryModel) GetRecent(ctx context.Context, limit int) ([]Comma
 	opts := options.Find().
 		SetSort(bson.D{{Key: "create_time", Value: -1}}).
 		SetLimit(int64(limit))
+	return m.findWithoutRecording(ctx, bson.M{}, opts)
+}
+
+// findWithoutRecording 列表查询不返回录像内容，录像通过 GetRecording 单独获取
+func (m *CommandHistoryModel) findWithoutRecording(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]CommandHistory, error) {
+	opts.SetProjection(bson.M{"recording": 0})
 
-	cursor, err := m.Coll.Find(ctx, bson.M{}, opts)
+	cursor, err := m.Coll.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DNSRecord 域名DNS记录历史，同一记录再次出现时只更新最后发现时间
+type DNSRecord struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Domain     string             `bson:"domain" json:"domain"`           // 资产域名
+	Name       string             `bson:"name" json:"name"`               // 记录所有者名称，DMARC 记录为 _dmarc.<domain>
+	Type       string             `bson:"type" json:"type"`               // A/AAAA/CNAME/MX/NS/TXT/SOA/CAA
+	Value      string             `bson:"value" json:"value"`             // 记录值
+	TTL        int64              `bson:"ttl" json:"ttl"`                 // 最后一次查询到的TTL
+	MainTaskId string             `bson:"main_task_id" json:"mainTaskId"` // 最后一次发现该记录的任务
+	FirstSeen  time.Time          `bson:"first_seen" json:"firstSeen"`
+	LastSeen   time.Time          `bson:"last_seen" json:"lastSeen"`
+}
+
+// DNSRecordModel DNS记录历史模型
+type DNSRecordModel struct {
+	*BaseModel[DNSRecord]
+}
+
+// NewDNSRecordModel 创建DNS记录历史模型
+func NewDNSRecordModel(db *mongo.Database, workspaceId string) *DNSRecordModel {
+	coll := db.Collection(workspaceId + "_dns_record")
+	m := &DNSRecordModel{
+		BaseModel: NewBaseModel[DNSRecord](coll),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "domain", Value: 1}, {Key: "name", Value: 1}, {Key: "type", Value: 1}, {Key: "value", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "domain", Value: 1}, {Key: "last_seen", Value: -1}}},
+	})
+
+	return m
+}
+
+// BatchUpsert 批量写入DNS记录，新记录设置首次发现时间，已有记录更新最后发现时间和TTL
+func (m *DNSRecordModel) BatchUpsert(ctx context.Context, mainTaskId string, docs []*DNSRecord) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"domain": doc.Domain, "name": doc.Name, "type": doc.Type, "value": doc.Value}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"ttl":          doc.TTL,
+					"main_task_id": mainTaskId,
+					"last_seen":    now,
+				},
+				"$setOnInsert": bson.M{"first_seen": now},
+			}).
+			SetUpsert(true))
+	}
+	_, err := m.Coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// FindByDomain 查询域名的DNS记录历史，按类型和最后发现时间排序
+func (m *DNSRecordModel) FindByDomain(ctx context.Context, domain, recordType string) ([]DNSRecord, error) {
+	filter := bson.M{"domain": domain}
+	if recordType != "" {
+		filter["type"] = recordType
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "type", Value: 1}, {Key: "last_seen", Value: -1}, {Key: "value", Value: 1}})
+	cursor, err := m.Coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []DNSRecord
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ExternalScanner 外部扫描器声明，Worker 以子进程方式运行并解析 JSONL 输出
+type ExternalScanner struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"` // 唯一名称，任务配置中按名称引用
+	Description string             `bson:"description" json:"description"`
+	Binary      string             `bson:"binary" json:"binary"`                // Worker 上的可执行文件
+	Args        []string           `bson:"args" json:"args"`                    // 参数，支持 {{input}}、{{output}} 占位符
+	Env         map[string]string  `bson:"env,omitempty" json:"env"`            // 额外环境变量
+	InputFormat string             `bson:"input_format" json:"inputFormat"`     // hostports/hosts/urls/targets/jsonl
+	ResultType  string             `bson:"result_type" json:"resultType"`       // 空/asset/vul
+	FieldMap    map[string]string  `bson:"field_map,omitempty" json:"fieldMap"` // 结果字段 -> 输出JSON路径
+	Timeout     int                `bson:"timeout" json:"timeout"`              // 超时(秒)
+	Enabled     bool               `bson:"enabled" json:"enabled"`
+	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// ExternalScannerModel 外部扫描器模型
+type ExternalScannerModel struct {
+	*BaseModel[ExternalScanner]
+}
+
+// NewExternalScannerModel 创建外部扫描器模型
+func NewExternalScannerModel(db *mongo.Database) *ExternalScannerModel {
+	coll := db.Collection("external_scanner")
+	m := &ExternalScannerModel{
+		BaseModel: NewBaseModel[ExternalScanner](coll),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+
+	return m
+}
+
+// Create 新建外部扫描器
+func (m *ExternalScannerModel) Create(ctx context.Context, doc *ExternalScanner) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	return m.Insert(ctx, doc)
+}
+
+// Update 更新外部扫描器
+func (m *ExternalScannerModel) Update(ctx context.Context, id string, doc *ExternalScanner) error {
+	return m.UpdateById(ctx, id, bson.M{
+		"name":         doc.Name,
+		"description":  doc.Description,
+		"binary":       doc.Binary,
+		"args":         doc.Args,
+		"env":          doc.Env,
+		"input_format": doc.InputFormat,
+		"result_type":  doc.ResultType,
+		"field_map":    doc.FieldMap,
+		"timeout":      doc.Timeout,
+		"enabled":      doc.Enabled,
+	})
+}
+
+// FindByName 按名称查找，不存在返回 nil
+func (m *ExternalScannerModel) FindByName(ctx context.Context, name string) (*ExternalScanner, error) {
+	doc, err := m.FindOne(ctx, bson.M{"name": name})
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// FindEnabledByNames 查询指定名称中已启用的扫描器
+func (m *ExternalScannerModel) FindEnabledByNames(ctx context.Context, names []string) ([]ExternalScanner, error) {
+	return m.Find(ctx, bson.M{"name": bson.M{"$in": names}, "enabled": true}, 0, 0)
+}
+
+// FindEnabled 查询全部已启用的扫描器
+func (m *ExternalScannerModel) FindEnabled(ctx context.Context) ([]ExternalScanner, error) {
+	return m.FindWithSort(ctx, bson.M{"enabled": true}, 0, 0, "name", 1)
+}
@@ -247,6 +247,8 @@ type Fingerprint struct {
 	Implies    []string  `bson:"implies" json:"implies"`       // 隐含的其他技术
 	Excludes   []string  `bson:"excludes" json:"excludes"`     // 排除的技术
 	CPE        string    `bson:"cpe" json:"cpe"`               // CPE标识
+	// 版本提取 - 命中指纹后从响应中提取版本，用于生成带版本的 CPE
+	Extractors []VersionExtractor `bson:"extractors,omitempty" json:"extractors,omitempty"`
 	Source     string    `bson:"source" json:"source"`         // 来源: wappalyzer, arl, custom
 	IsBuiltin  bool      `bson:"is_builtin" json:"isBuiltin"`  // 是否内置指纹
 	Enabled    bool      `bson:"enabled" json:"enabled"`       // 是否启用
@@ -254,6 +256,13 @@ type Fingerprint struct {
 	UpdateTime time.Time `bson:"update_time" json:"updateTime"`
 }
 
+// VersionExtractor 版本提取规则，用于 ARL 等不支持 \;version: 语法的指纹
+type VersionExtractor struct {
+	Part    string `bson:"part" json:"part"`                           // 提取位置: header, body, title, server, banner
+	Regex   string `bson:"regex" json:"regex"`                         // 提取正则，版本位于捕获组
+	Version string `bson:"version,omitempty" json:"version,omitempty"` // 版本模板，如 \1.\2，为空时取第一个捕获组
+}
+
 // FingerprintModel 指纹模型
 type FingerprintModel struct {
 	coll *mongo.Collection
@@ -331,6 +340,29 @@ func (m *FingerprintModel) FindEnabled(ctx context.Context) ([]Fingerprint, erro
 	return m.Find(ctx, bson.M{"enabled": true}, 0, 0)
 }
 
+// FindVersionRules 查询配置了CPE或版本提取规则的指纹，只返回 _id、cpe 和 extractors
+// Worker 指纹下发经由 RPC，RPC 消息不含这两个字段，由 API 按 ID 补充
+func (m *FingerprintModel) FindVersionRules(ctx context.Context) ([]Fingerprint, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"cpe": bson.M{"$nin": []interface{}{"", nil}}},
+			{"extractors.0": bson.M{"$exists": true}},
+		},
+	}
+	opts := options.Find().SetProjection(bson.M{"cpe": 1, "extractors": 1})
+	cursor, err := m.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []Fingerprint
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
 // FindPassiveEnabled 查询启用的被动指纹（用于默认指纹扫描）
 func (m *FingerprintModel) FindPassiveEnabled(ctx context.Context) ([]Fingerprint, error) {
 	// 被动指纹：type为空或为passive
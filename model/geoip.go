@@ -0,0 +1,185 @@
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GeoIPDataset IP归属地/ASN数据库文件，文件内容存放在 GridFS
+type GeoIPDataset struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`               // 数据集名称，唯一
+	Type        string             `bson:"type" json:"type"`               // mmdb/ip2region
+	FileName    string             `bson:"file_name" json:"fileName"`      // 原始文件名
+	Size        int64              `bson:"size" json:"size"`               // 文件大小（字节）
+	Sha256      string             `bson:"sha256" json:"sha256"`           // 文件摘要，Worker 据此判断是否需要重新下载
+	FileId      primitive.ObjectID `bson:"file_id" json:"-"`               // GridFS 文件ID
+	Description string             `bson:"description" json:"description"` // 描述
+	Enabled     bool               `bson:"enabled" json:"enabled"`
+	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// GeoIPDatasetModel IP归属地数据集模型
+type GeoIPDatasetModel struct {
+	*BaseModel[GeoIPDataset]
+	bucket *gridfs.Bucket
+}
+
+// NewGeoIPDatasetModel 创建IP归属地数据集模型
+func NewGeoIPDatasetModel(db *mongo.Database) *GeoIPDatasetModel {
+	coll := db.Collection("geoip_dataset")
+	bucket, _ := gridfs.NewBucket(db, options.GridFSBucket().SetName("geoip"))
+	m := &GeoIPDatasetModel{
+		BaseModel: NewBaseModel[GeoIPDataset](coll),
+		bucket:    bucket,
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "enabled", Value: 1}}},
+	})
+
+	return m
+}
+
+// Create 上传数据集文件并新建记录，Size 和 Sha256 由上传内容计算
+func (m *GeoIPDatasetModel) Create(ctx context.Context, doc *GeoIPDataset, content io.Reader) error {
+	hasher := sha256.New()
+	fileId, err := m.bucket.UploadFromStream(doc.FileName, io.TeeReader(content, hasher))
+	if err != nil {
+		return err
+	}
+
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	doc.FileId = fileId
+	doc.Sha256 = hex.EncodeToString(hasher.Sum(nil))
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+
+	var file struct {
+		Length int64 `bson:"length"`
+	}
+	if err := m.bucket.GetFilesCollection().FindOne(ctx, bson.M{"_id": fileId}).Decode(&file); err == nil {
+		doc.Size = file.Length
+	}
+
+	if err := m.Insert(ctx, doc); err != nil {
+		m.bucket.Delete(fileId)
+		return err
+	}
+	return nil
+}
+
+// OpenFile 打开数据集文件内容
+func (m *GeoIPDatasetModel) OpenFile(doc *GeoIPDataset) (io.ReadCloser, error) {
+	return m.bucket.OpenDownloadStream(doc.FileId)
+}
+
+// Delete 删除数据集记录及其文件
+func (m *GeoIPDatasetModel) Delete(ctx context.Context, doc *GeoIPDataset) error {
+	if err := m.DeleteById(ctx, doc.Id.Hex()); err != nil {
+		return err
+	}
+	if err := m.bucket.Delete(doc.FileId); err != nil && err != gridfs.ErrFileNotFound {
+		return err
+	}
+	return nil
+}
+
+// FindByName 按名称查找，不存在返回 nil
+func (m *GeoIPDatasetModel) FindByName(ctx context.Context, name string) (*GeoIPDataset, error) {
+	doc, err := m.FindOne(ctx, bson.M{"name": name})
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// FindEnabled 查询全部已启用的数据集，按名称排序（靠前的数据集字段优先）
+func (m *GeoIPDatasetModel) FindEnabled(ctx context.Context) ([]GeoIPDataset, error) {
+	return m.FindWithSort(ctx, bson.M{"enabled": true}, 0, 0, "name", 1)
+}
+
+// UpdateIPGeo 写入资产IP的结构化归属地/ASN信息，port 为 0 时按 authority 匹配
+func (m *AssetModel) UpdateIPGeo(ctx context.Context, authority, host string, port int, ipv4 []IPV4, ipv6 []IPV6) error {
+	filter := bson.M{"authority": authority}
+	if port > 0 {
+		filter = bson.M{"host": host, "port": port}
+	}
+	set := bson.M{}
+	if len(ipv4) > 0 {
+		set["ip.ipv4"] = ipv4
+	}
+	if len(ipv6) > 0 {
+		set["ip.ipv6"] = ipv6
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	_, err := m.coll.UpdateOne(ctx, filter, bson.M{"$set": set})
+	return err
+}
+
+// GeoStatResult 按ASN或国家分组的资产数量
+type GeoStatResult struct {
+	Key   interface{} `bson:"_id"` // ASN(int) 或国家名称
+	Org   string      `bson:"org"` // 按ASN分组时为ASN所属组织
+	Count int         `bson:"count"`
+}
+
+// AggregateIPGeo 按IP的 asn 或 country 字段分组统计匹配 filter 的资产数量，同一资产的同一分组只计一次
+func (m *AssetModel) AggregateIPGeo(ctx context.Context, filter bson.M, field string, limit int) ([]GeoStatResult, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "ips", Value: bson.D{{Key: "$concatArrays", Value: bson.A{
+				bson.D{{Key: "$ifNull", Value: bson.A{"$ip.ipv4", bson.A{}}}},
+				bson.D{{Key: "$ifNull", Value: bson.A{"$ip.ipv6", bson.A{}}}},
+			}}}},
+		}}},
+		{{Key: "$unwind", Value: "$ips"}},
+		{{Key: "$match", Value: bson.D{{Key: "ips." + field, Value: bson.D{{Key: "$nin", Value: bson.A{nil, "", 0}}}}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "asset", Value: "$_id"}, {Key: "key", Value: "$ips." + field}}},
+			{Key: "org", Value: bson.D{{Key: "$first", Value: "$ips.org"}}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$_id.key"},
+			{Key: "org", Value: bson.D{{Key: "$first", Value: "$org"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := m.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []GeoStatResult
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,226 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cscan/pkg/notify"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotifyRoute 通知路由规则，按事件类型、工作空间、组织、严重级别和标签选择通知配置
+type NotifyRoute struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string             `bson:"name" json:"name"`
+	Events       []string           `bson:"events" json:"events"`
+	WorkspaceIds []string           `bson:"workspace_ids" json:"workspaceIds"`
+	OrgIds       []string           `bson:"org_ids" json:"orgIds"` // 包含下级组织
+	MinSeverity  string             `bson:"min_severity" json:"minSeverity"`
+	Tags         []string           `bson:"tags" json:"tags"`
+	Labels       []string           `bson:"labels" json:"labels"`
+	ConfigIds    []string           `bson:"config_ids" json:"configIds"`
+	Template     string             `bson:"template" json:"template"`
+	QuietStart   string             `bson:"quiet_start" json:"quietStart"` // HH:MM
+	QuietEnd     string             `bson:"quiet_end" json:"quietEnd"`
+	Digest       string             `bson:"digest" json:"digest"` // 空/hourly/daily
+	Enabled      bool               `bson:"enabled" json:"enabled"`
+	Description  string             `bson:"description" json:"description"`
+	CreateTime   time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime   time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// GetId 实现 Identifiable 接口
+func (r *NotifyRoute) GetId() primitive.ObjectID {
+	return r.Id
+}
+
+// SetId 实现 Identifiable 接口
+func (r *NotifyRoute) SetId(id primitive.ObjectID) {
+	r.Id = id
+}
+
+// SetCreateTime 实现 Timestamped 接口
+func (r *NotifyRoute) SetCreateTime(t time.Time) {
+	r.CreateTime = t
+}
+
+// SetUpdateTime 实现 Timestamped 接口
+func (r *NotifyRoute) SetUpdateTime(t time.Time) {
+	r.UpdateTime = t
+}
+
+// RouteRule 转换为路由引擎使用的规则
+func (r *NotifyRoute) RouteRule() notify.RouteRule {
+	return notify.RouteRule{
+		Id:           r.Id.Hex(),
+		Name:         r.Name,
+		Events:       r.Events,
+		WorkspaceIds: r.WorkspaceIds,
+		OrgIds:       r.OrgIds,
+		MinSeverity:  r.MinSeverity,
+		Tags:         r.Tags,
+		Labels:       r.Labels,
+		ConfigIds:    r.ConfigIds,
+		Template:     r.Template,
+		QuietStart:   r.QuietStart,
+		QuietEnd:     r.QuietEnd,
+		Digest:       r.Digest,
+	}
+}
+
+// NotifyRouteModel 通知路由规则模型
+type NotifyRouteModel struct {
+	*BaseModel[NotifyRoute]
+}
+
+// NewNotifyRouteModel 创建通知路由规则模型
+func NewNotifyRouteModel(db *mongo.Database) *NotifyRouteModel {
+	m := &NotifyRouteModel{
+		BaseModel: NewBaseModel[NotifyRoute](db.Collection("notify_route")),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "enabled", Value: 1}}},
+	})
+
+	return m
+}
+
+// LoadRules 加载已启用的路由规则，组织条件展开为组织及其全部下级组织
+func (m *NotifyRouteModel) LoadRules(ctx context.Context, orgModel *OrganizationModel) ([]notify.RouteRule, error) {
+	docs, err := m.FindWithSort(ctx, bson.M{"enabled": true}, 0, 0, "create_time", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants := make(map[string][]string)
+	rules := make([]notify.RouteRule, 0, len(docs))
+	for i := range docs {
+		rule := docs[i].RouteRule()
+		if len(rule.OrgIds) > 0 && orgModel != nil {
+			var orgIds []string
+			for _, id := range rule.OrgIds {
+				ids, ok := descendants[id]
+				if !ok {
+					if ids, err = orgModel.DescendantIds(ctx, id); err != nil {
+						return nil, err
+					}
+					descendants[id] = ids
+				}
+				orgIds = append(orgIds, ids...)
+			}
+			rule.OrgIds = orgIds
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Targets 返回已启用的通知配置，以配置ID为键
+func (m *NotifyConfigModel) Targets(ctx context.Context) (map[string]notify.ConfigItem, error) {
+	docs, err := m.FindEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets := make(map[string]notify.ConfigItem, len(docs))
+	for _, c := range docs {
+		targets[c.Id.Hex()] = notify.ConfigItem{
+			Provider: c.Provider,
+			Config:   c.Config,
+			Status:   c.Status,
+			WebURL:   c.WebURL,
+		}
+	}
+	return targets, nil
+}
+
+// NotifyDigest 等待合并发送的事件，同一规则同一发送时间的事件合并为一条
+type NotifyDigest struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	RuleId     string             `bson:"rule_id"`
+	DeliverAt  time.Time          `bson:"deliver_at"`
+	Events     []notify.Event     `bson:"events"`
+	Counts     map[string]int     `bson:"counts"`
+	Attempts   int                `bson:"attempts,omitempty"` // 已失败的发送次数
+	CreateTime time.Time          `bson:"create_time"`
+}
+
+// NotifyDigestModel 通知摘要队列，实现 notify.DigestStore
+type NotifyDigestModel struct {
+	coll *mongo.Collection
+}
+
+// NewNotifyDigestModel 创建通知摘要队列模型
+func NewNotifyDigestModel(db *mongo.Database) *NotifyDigestModel {
+	coll := db.Collection("notify_digest")
+	coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "rule_id", Value: 1}, {Key: "deliver_at", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "deliver_at", Value: 1}}},
+	})
+	return &NotifyDigestModel{coll: coll}
+}
+
+// Add 追加事件到摘要，明细最多保留 notify.DigestMaxEvents 条，计数不受限制
+func (m *NotifyDigestModel) Add(ctx context.Context, ruleId string, deliverAt time.Time, events []notify.Event) error {
+	inc := bson.M{}
+	for typ, n := range notify.CountEvents(events) {
+		inc["counts."+typ] = n
+	}
+	update := bson.M{
+		"$push": bson.M{"events": bson.M{"$each": events, "$slice": notify.DigestMaxEvents}},
+		"$inc":  inc,
+		"$setOnInsert": bson.M{
+			"create_time": time.Now(),
+		},
+	}
+	_, err := m.coll.UpdateOne(ctx, bson.M{"rule_id": ruleId, "deliver_at": deliverAt}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// TakeDue 逐条取出并删除到期的摘要，多个实例同时调用时每条摘要只会被取出一次
+func (m *NotifyDigestModel) TakeDue(ctx context.Context, now time.Time) ([]notify.Digest, error) {
+	var digests []notify.Digest
+	filter := bson.M{"deliver_at": bson.M{"$lte": now}}
+	for {
+		var doc NotifyDigest
+		err := m.coll.FindOneAndDelete(ctx, filter).Decode(&doc)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return digests, nil
+		}
+		if err != nil {
+			return digests, err
+		}
+		digests = append(digests, notify.Digest{RuleId: doc.RuleId, Events: doc.Events, Counts: doc.Counts, Attempts: doc.Attempts})
+	}
+}
+
+// Requeue 将发送失败的摘要放回队列，与同一规则同一发送时间的摘要合并
+func (m *NotifyDigestModel) Requeue(ctx context.Context, digest notify.Digest, deliverAt time.Time) error {
+	inc := bson.M{}
+	for typ, n := range digest.Counts {
+		inc["counts."+typ] = n
+	}
+	update := bson.M{
+		"$push": bson.M{"events": bson.M{"$each": digest.Events, "$slice": notify.DigestMaxEvents}},
+		"$max":  bson.M{"attempts": digest.Attempts},
+		"$setOnInsert": bson.M{
+			"create_time": time.Now(),
+		},
+	}
+	if len(inc) > 0 {
+		update["$inc"] = inc
+	}
+	_, err := m.coll.UpdateOne(ctx, bson.M{"rule_id": digest.RuleId, "deliver_at": deliverAt}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// DeleteByRuleId 删除规则尚未发送的摘要
+func (m *NotifyDigestModel) DeleteByRuleId(ctx context.Context, ruleId string) error {
+	_, err := m.coll.DeleteMany(ctx, bson.M{"rule_id": ruleId})
+	return err
+}
@@ -10,16 +10,28 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// Organization 组织
+// Organization 组织，可通过 ParentId 组成事业部/子公司/团队的多级结构
 type Organization struct {
 	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name        string             `bson:"name" json:"name"`
 	Description string             `bson:"description" json:"description"`
-	Status      string             `bson:"status" json:"status"` // enable, disable
+	Status      string             `bson:"status" json:"status"`                          // enable, disable
+	ParentId    string             `bson:"parent_id,omitempty" json:"parentId,omitempty"` // 上级组织ID，为空表示顶级组织
+	Contacts    []OrgContact       `bson:"contacts,omitempty" json:"contacts,omitempty"`  // 负责人及整改联系人
+	Tags        []string           `bson:"tags,omitempty" json:"tags,omitempty"`
 	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
 	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
 }
 
+// OrgContact 组织联系人
+type OrgContact struct {
+	Name  string `bson:"name" json:"name"`
+	Role  string `bson:"role,omitempty" json:"role,omitempty"` // owner/security/ops 等
+	Email string `bson:"email,omitempty" json:"email,omitempty"`
+	Phone string `bson:"phone,omitempty" json:"phone,omitempty"`
+	IM    string `bson:"im,omitempty" json:"im,omitempty"` // 企业微信/钉钉/飞书账号
+}
+
 type OrganizationModel struct {
 	coll *mongo.Collection
 }
@@ -97,3 +109,34 @@ func (m *OrganizationModel) Delete(ctx context.Context, id string) error {
 	_, err = m.coll.DeleteOne(ctx, bson.M{"_id": oid})
 	return err
 }
+
+// CountChildren 统计直接下级组织数量
+func (m *OrganizationModel) CountChildren(ctx context.Context, id string) (int64, error) {
+	return m.coll.CountDocuments(ctx, bson.M{"parent_id": id})
+}
+
+// DescendantIds 返回组织自身及全部下级组织的ID
+func (m *OrganizationModel) DescendantIds(ctx context.Context, id string) ([]string, error) {
+	orgs, err := m.Find(ctx, bson.M{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[string][]string, len(orgs))
+	for _, o := range orgs {
+		if o.ParentId != "" {
+			children[o.ParentId] = append(children[o.ParentId], o.Id.Hex())
+		}
+	}
+
+	ids := []string{id}
+	seen := map[string]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OrgAttributionRule 资产归属规则，保存结果时按规则自动设置资产的 OrgId
+type OrgAttributionRule struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OrgId       string             `bson:"org_id" json:"orgId"`
+	Type        string             `bson:"type" json:"type"`         // domain/cidr/icp/cert_org/cloud_account
+	Pattern     string             `bson:"pattern" json:"pattern"`   // 域名后缀、IP段、备案号、证书组织或云账号
+	Priority    int                `bson:"priority" json:"priority"` // 数值越大越优先
+	Enabled     bool               `bson:"enabled" json:"enabled"`
+	Description string             `bson:"description" json:"description"`
+	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// GetId 实现 Identifiable 接口
+func (r *OrgAttributionRule) GetId() primitive.ObjectID {
+	return r.Id
+}
+
+// SetId 实现 Identifiable 接口
+func (r *OrgAttributionRule) SetId(id primitive.ObjectID) {
+	r.Id = id
+}
+
+// SetCreateTime 实现 Timestamped 接口
+func (r *OrgAttributionRule) SetCreateTime(t time.Time) {
+	r.CreateTime = t
+}
+
+// SetUpdateTime 实现 Timestamped 接口
+func (r *OrgAttributionRule) SetUpdateTime(t time.Time) {
+	r.UpdateTime = t
+}
+
+// OrgAttributionRuleModel 资产归属规则模型
+type OrgAttributionRuleModel struct {
+	*BaseModel[OrgAttributionRule]
+}
+
+// NewOrgAttributionRuleModel 创建资产归属规则模型
+func NewOrgAttributionRuleModel(db *mongo.Database) *OrgAttributionRuleModel {
+	m := &OrgAttributionRuleModel{
+		BaseModel: NewBaseModel[OrgAttributionRule](db.Collection("org_attribution_rule")),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "org_id", Value: 1}}},
+		{Keys: bson.D{{Key: "enabled", Value: 1}}},
+	})
+
+	return m
+}
+
+// FindEnabled 查询全部已启用的规则
+func (m *OrgAttributionRuleModel) FindEnabled(ctx context.Context) ([]OrgAttributionRule, error) {
+	return m.FindWithSort(ctx, bson.M{"enabled": true}, 0, 0, "priority", -1)
+}
+
+// DeleteByOrgIds 删除指定组织的全部规则
+func (m *OrgAttributionRuleModel) DeleteByOrgIds(ctx context.Context, orgIds []string) (int64, error) {
+	return m.DeleteMany(ctx, bson.M{"org_id": bson.M{"$in": orgIds}})
+}
+
+// UpdateOrgId 按 host+port+传输层协议 设置资产归属组织，port 为 0 时按 authority 匹配
+func (m *AssetModel) UpdateOrgId(ctx context.Context, authority, host string, port int, transport, orgId string) error {
+	filter := bson.M{"authority": authority}
+	if port > 0 {
+		filter = tcpHostPortFilter(host, port)
+		if transport == TransportUDP {
+			filter = bson.M{"host": host, "port": port, "transport": TransportUDP}
+		}
+	}
+	_, err := m.coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"org_id": orgId}})
+	return err
+}
@@ -0,0 +1,117 @@
+package model
+
+// AllWorkspaces 表示全部工作空间的工作空间ID
+const AllWorkspaces = "all"
+
+// 用户角色，按权限从低到高排列
+const (
+	RoleViewer   = "viewer"   // 只读
+	RoleAnalyst  = "analyst"  // 只读 + 漏洞处置、资产标注
+	RoleOperator = "operator" // 分析员 + 任务下发、POC/指纹维护
+	RoleAdmin    = "admin"    // 全部权限
+)
+
+// Permission 接口权限
+type Permission string
+
+const (
+	PermView          Permission = "view"            // 查看资产、漏洞、任务等数据
+	PermVulTriage     Permission = "vul:triage"      // 漏洞处置、指派、评论
+	PermAssetManage   Permission = "asset:manage"    // 资产标注、导入、组织归属
+	PermAssetDelete   Permission = "asset:delete"    // 删除/清空资产、漏洞、扫描结果
+	PermTaskManage    Permission = "task:manage"     // 创建/启停任务、定时任务、扫描模板
+	PermTaskDelete    Permission = "task:delete"     // 删除任务、定时任务、扫描模板和任务配置
+	PermPocManage     Permission = "poc:manage"      // POC、指纹、字典维护
+	PermWorkerManage  Permission = "worker:manage"   // Worker 管理、安装密钥
+	PermWorkerConsole Permission = "worker:console"  // Worker 控制台（文件、终端）
+	PermNotifyManage  Permission = "notify:manage"   // 通知配置
+	PermSettings      Permission = "settings:manage" // 全局配置（API密钥、黑名单、AI、主题等）
+	PermUserManage    Permission = "user:manage"     // 用户、工作空间管理
+)
+
+// globalPermissions 全局权限，只按用户的全局角色判断，不受工作空间角色覆盖影响
+var globalPermissions = permissionSet(PermUserManage, PermWorkerManage, PermWorkerConsole, PermSettings)
+
+// workspaceDataPermissions 操作工作空间内数据的权限。在全部工作空间（all）上操作时，
+// 业务逻辑会遍历每个工作空间，因此按用户在各工作空间中最低的角色判断
+var workspaceDataPermissions = permissionSet(PermView, PermVulTriage, PermAssetManage, PermAssetDelete, PermTaskManage, PermTaskDelete)
+
+// roleLevels 角色等级，数值越大权限越高
+var roleLevels = map[string]int{RoleViewer: 1, RoleAnalyst: 2, RoleOperator: 3, RoleAdmin: 4}
+
+// rolePermissions 角色权限表，admin 拥有全部权限，不在表中
+var rolePermissions = map[string]map[Permission]bool{
+	RoleViewer:   permissionSet(PermView),
+	RoleAnalyst:  permissionSet(PermView, PermVulTriage, PermAssetManage),
+	RoleOperator: permissionSet(PermView, PermVulTriage, PermAssetManage, PermTaskManage, PermTaskDelete, PermPocManage),
+}
+
+func permissionSet(perms ...Permission) map[Permission]bool {
+	set := make(map[Permission]bool, len(perms))
+	for _, p := range perms {
+		set[p] = true
+	}
+	return set
+}
+
+// IsValidRole 检查角色是否合法
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleViewer, RoleAnalyst, RoleOperator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// HasPermission 判断角色是否拥有指定权限，未知角色没有任何权限
+func HasPermission(role string, perm Permission) bool {
+	if role == RoleAdmin {
+		return true
+	}
+	return rolePermissions[role][perm]
+}
+
+// IsGlobalPermission 判断是否为全局权限
+func IsGlobalPermission(perm Permission) bool {
+	return globalPermissions[perm]
+}
+
+// GlobalRole 返回用户的全局角色，没有角色字段时按最低权限处理
+func (u *User) GlobalRole() string {
+	if u.Role == "" {
+		return RoleViewer
+	}
+	return u.Role
+}
+
+// RoleFor 返回用户在指定工作空间下的角色。
+// 工作空间没有成员列表，所有用户都能访问全部工作空间，未单独配置的工作空间沿用全局角色
+func (u *User) RoleFor(workspaceId string) string {
+	if role, ok := u.WorkspaceRoles[workspaceId]; ok && workspaceId != "" {
+		return role
+	}
+	return u.GlobalRole()
+}
+
+// LowestRole 返回用户在所有工作空间中最低的角色，即全局角色和各工作空间单独配置角色中最低的一个
+func (u *User) LowestRole() string {
+	lowest := u.GlobalRole()
+	for _, role := range u.WorkspaceRoles {
+		if roleLevels[role] < roleLevels[lowest] {
+			lowest = role
+		}
+	}
+	return lowest
+}
+
+// RoleForPermission 返回判断指定权限时使用的角色：全局权限只看全局角色；
+// 在全部工作空间（空或 all）上操作工作空间数据时使用最低角色，避免绕过单个工作空间的降级；其余按工作空间角色
+func (u *User) RoleForPermission(workspaceId string, perm Permission) string {
+	if IsGlobalPermission(perm) {
+		return u.GlobalRole()
+	}
+	if (workspaceId == "" || workspaceId == AllWorkspaces) && workspaceDataPermissions[perm] {
+		return u.LowestRole()
+	}
+	return u.RoleFor(workspaceId)
+}
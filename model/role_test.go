@@ -0,0 +1,80 @@
+package model
+
+import "testing"
+
+func TestUserRoleForPermission(t *testing.T) {
+	u := &User{
+		Role:           RoleViewer,
+		WorkspaceRoles: map[string]string{"ws-a": RoleAdmin, "ws-b": RoleOperator},
+	}
+
+	cases := []struct {
+		name        string
+		workspaceId string
+		perm        Permission
+		want        bool
+	}{
+		{"workspace admin manages tasks", "ws-a", PermTaskManage, true},
+		{"workspace admin cannot manage users", "ws-a", PermUserManage, false},
+		{"workspace admin cannot change settings", "ws-a", PermSettings, false},
+		{"workspace admin cannot open console", "ws-a", PermWorkerConsole, false},
+		{"workspace admin cannot manage workers", "ws-a", PermWorkerManage, false},
+		{"operator workspace", "ws-b", PermTaskManage, true},
+		{"unlisted workspace uses global role", "ws-c", PermTaskManage, false},
+		{"all workspaces can view", "", PermView, true},
+		{"all workspaces uses lowest role", AllWorkspaces, PermTaskManage, false},
+	}
+	for _, c := range cases {
+		if got := HasPermission(u.RoleForPermission(c.workspaceId, c.perm), c.perm); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	admin := &User{Role: RoleAdmin, WorkspaceRoles: map[string]string{"ws-a": RoleViewer}}
+	if !HasPermission(admin.RoleForPermission("ws-a", PermUserManage), PermUserManage) {
+		t.Error("global admin should keep global permissions in any workspace")
+	}
+	if HasPermission(admin.RoleForPermission("ws-a", PermTaskManage), PermTaskManage) {
+		t.Error("workspace override should still restrict workspace permissions")
+	}
+}
+
+func TestUserWithoutRoleIsViewer(t *testing.T) {
+	u := &User{}
+	if role := u.RoleFor("ws-a"); role != RoleViewer {
+		t.Fatalf("RoleFor = %q, want %q", role, RoleViewer)
+	}
+	if HasPermission(u.GlobalRole(), PermAssetDelete) || HasPermission(u.GlobalRole(), PermUserManage) {
+		t.Error("user without role must not get write permissions")
+	}
+	if !HasPermission(u.GlobalRole(), PermView) {
+		t.Error("user without role should keep read access")
+	}
+}
+
+func TestUserRoleForAllWorkspaces(t *testing.T) {
+	u := &User{Role: RoleOperator, WorkspaceRoles: map[string]string{"ws-a": RoleAdmin, "ws-b": RoleAnalyst}}
+	if role := u.LowestRole(); role != RoleAnalyst {
+		t.Fatalf("LowestRole = %q, want %q", role, RoleAnalyst)
+	}
+	for _, ws := range []string{"", AllWorkspaces} {
+		if HasPermission(u.RoleForPermission(ws, PermTaskManage), PermTaskManage) {
+			t.Errorf("workspace %q: task manage must follow the lowest workspace role", ws)
+		}
+		if !HasPermission(u.RoleForPermission(ws, PermVulTriage), PermVulTriage) {
+			t.Errorf("workspace %q: analyst in every workspace should triage", ws)
+		}
+		if !HasPermission(u.RoleForPermission(ws, PermPocManage), PermPocManage) {
+			t.Errorf("workspace %q: poc manage is not workspace data and uses the global role", ws)
+		}
+	}
+
+	// 工作空间没有成员列表，未单独配置的工作空间沿用全局角色
+	if role := u.RoleFor("ws-c"); role != RoleOperator {
+		t.Errorf("RoleFor unlisted workspace = %q, want global %q", role, RoleOperator)
+	}
+	// 单独配置更高角色不会提升全部工作空间的角色
+	if role := (&User{Role: RoleViewer, WorkspaceRoles: map[string]string{"ws-a": RoleAdmin}}).LowestRole(); role != RoleViewer {
+		t.Errorf("LowestRole = %q, want %q", role, RoleViewer)
+	}
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ScanScope 工作空间扫描范围（白名单），启用后只允许扫描范围内的目标
+type ScanScope struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceId string             `bson:"workspace_id" json:"workspaceId"`
+	Enabled     bool               `bson:"enabled" json:"enabled"`
+	Cidrs       []string           `bson:"cidrs" json:"cidrs"`        // 允许的IP/网段
+	Domains     []string           `bson:"domains" json:"domains"`    // 允许的域名后缀
+	Hosts       []string           `bson:"hosts" json:"hosts"`        // 明确允许的主机
+	Windows     []ScanScopeWindow  `bson:"windows" json:"windows"`    // 允许扫描的时间窗口
+	Timezone    string             `bson:"timezone" json:"timezone"`  // 时间窗口时区
+	StrictIP    bool               `bson:"strict_ip" json:"strictIp"` // 域名解析出的IP和CNAME也必须在范围内
+	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// ScanScopeWindow 允许扫描的时间窗口
+type ScanScopeWindow struct {
+	Days  []int  `bson:"days" json:"days"`   // 星期几，0=周日，为空表示每天
+	Start string `bson:"start" json:"start"` // HH:MM
+	End   string `bson:"end" json:"end"`     // HH:MM，小于开始时间表示跨天
+}
+
+// ScanScopeModel 扫描范围模型，每个工作空间一条记录
+type ScanScopeModel struct {
+	*BaseModel[ScanScope]
+}
+
+// NewScanScopeModel 创建扫描范围模型
+func NewScanScopeModel(db *mongo.Database) *ScanScopeModel {
+	coll := db.Collection("scan_scope")
+	m := &ScanScopeModel{
+		BaseModel: NewBaseModel[ScanScope](coll),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "workspace_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+
+	return m
+}
+
+// GetByWorkspace 获取工作空间的扫描范围，未配置时返回 nil
+func (m *ScanScopeModel) GetByWorkspace(ctx context.Context, workspaceId string) (*ScanScope, error) {
+	doc, err := m.FindOne(ctx, bson.M{"workspace_id": workspaceId})
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// Save 保存工作空间的扫描范围
+func (m *ScanScopeModel) Save(ctx context.Context, doc *ScanScope) error {
+	return m.Upsert(ctx, bson.M{"workspace_id": doc.WorkspaceId}, bson.M{
+		"workspace_id": doc.WorkspaceId,
+		"enabled":      doc.Enabled,
+		"cidrs":        doc.Cidrs,
+		"domains":      doc.Domains,
+		"hosts":        doc.Hosts,
+		"windows":      doc.Windows,
+		"timezone":     doc.Timezone,
+		"strict_ip":    doc.StrictIP,
+	})
+}
+
+// OutOfScope 任务中遇到的范围外目标，只记录不扫描
+type OutOfScope struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MainTaskId string             `bson:"main_task_id" json:"mainTaskId"`
+	TaskId     string             `bson:"task_id" json:"taskId"`
+	Target     string             `bson:"target" json:"target"` // 目标或资产 host:port
+	Host       string             `bson:"host" json:"host"`
+	Phase      string             `bson:"phase" json:"phase"`   // 发现该目标的阶段，target 表示任务目标
+	Reason     string             `bson:"reason" json:"reason"` // 不在范围内的原因
+	CreateTime time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// OutOfScopeModel 范围外目标模型
+type OutOfScopeModel struct {
+	*BaseModel[OutOfScope]
+}
+
+// NewOutOfScopeModel 创建范围外目标模型
+func NewOutOfScopeModel(db *mongo.Database, workspaceId string) *OutOfScopeModel {
+	coll := db.Collection(workspaceId + "_out_of_scope")
+	m := &OutOfScopeModel{
+		BaseModel: NewBaseModel[OutOfScope](coll),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "main_task_id", Value: 1}, {Key: "target", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "create_time", Value: -1}}},
+	})
+
+	return m
+}
+
+// BatchRecord 批量记录范围外目标，同一任务内相同目标只记录一次
+func (m *OutOfScopeModel) BatchRecord(ctx context.Context, docs []*OutOfScope) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"main_task_id": doc.MainTaskId, "target": doc.Target}).
+			SetUpdate(bson.M{
+				"$set": bson.M{"update_time": now},
+				"$setOnInsert": bson.M{
+					"task_id":     doc.TaskId,
+					"host":        doc.Host,
+					"phase":       doc.Phase,
+					"reason":      doc.Reason,
+					"create_time": now,
+				},
+			}).
+			SetUpsert(true))
+	}
+	_, err := m.Coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
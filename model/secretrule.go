@@ -0,0 +1,88 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SecretRule 爬虫敏感信息匹配规则
+type SecretRule struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`       // 规则名称，唯一
+	Pattern     string             `bson:"pattern" json:"pattern"` // 正则表达式，含捕获组时取第一个捕获组作为泄露值
+	Severity    string             `bson:"severity" json:"severity"`
+	Description string             `bson:"description" json:"description"`
+	Enabled     bool               `bson:"enabled" json:"enabled"`
+	IsBuiltin   bool               `bson:"is_builtin" json:"isBuiltin"`
+	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// SecretRuleModel 敏感信息规则模型
+type SecretRuleModel struct {
+	*BaseModel[SecretRule]
+}
+
+// NewSecretRuleModel 创建敏感信息规则模型
+func NewSecretRuleModel(db *mongo.Database) *SecretRuleModel {
+	coll := db.Collection("secret_rule")
+	m := &SecretRuleModel{
+		BaseModel: NewBaseModel[SecretRule](coll),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "enabled", Value: 1}}},
+	})
+
+	return m
+}
+
+// Create 新建规则
+func (m *SecretRuleModel) Create(ctx context.Context, doc *SecretRule) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	return m.Insert(ctx, doc)
+}
+
+// Update 更新规则
+func (m *SecretRuleModel) Update(ctx context.Context, id string, doc *SecretRule) error {
+	return m.UpdateById(ctx, id, bson.M{
+		"name":        doc.Name,
+		"pattern":     doc.Pattern,
+		"severity":    doc.Severity,
+		"description": doc.Description,
+		"enabled":     doc.Enabled,
+	})
+}
+
+// FindByName 按名称查找，不存在返回 nil
+func (m *SecretRuleModel) FindByName(ctx context.Context, name string) (*SecretRule, error) {
+	doc, err := m.FindOne(ctx, bson.M{"name": name})
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// FindEnabled 查询全部已启用的规则
+func (m *SecretRuleModel) FindEnabled(ctx context.Context) ([]SecretRule, error) {
+	return m.FindWithSort(ctx, bson.M{"enabled": true}, 0, 0, "name", 1)
+}
+
+// CountBuiltin 内置规则数量
+func (m *SecretRuleModel) CountBuiltin(ctx context.Context) (int64, error) {
+	return m.Count(ctx, bson.M{"is_builtin": true})
+}
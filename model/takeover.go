@@ -0,0 +1,98 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TakeoverSignature 子域接管签名
+type TakeoverSignature struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Service      string             `bson:"service" json:"service"`           // 服务名称，唯一
+	RecordType   string             `bson:"record_type" json:"recordType"`    // CNAME/A/NS/MX
+	Patterns     []string           `bson:"patterns" json:"patterns"`         // 记录值后缀或关键字，A 记录为IP/CIDR，为空匹配任意值
+	Fingerprints []string           `bson:"fingerprints" json:"fingerprints"` // HTTP响应体指纹
+	HttpStatus   int                `bson:"http_status" json:"httpStatus"`    // 指纹命中时要求的状态码
+	NXDomain     bool               `bson:"nxdomain" json:"nxdomain"`         // 记录值 NXDOMAIN 即确认
+	Refused      bool               `bson:"refused" json:"refused"`           // NS 服务器拒绝解析即确认
+	Severity     string             `bson:"severity" json:"severity"`
+	Description  string             `bson:"description" json:"description"`
+	Enabled      bool               `bson:"enabled" json:"enabled"`
+	IsBuiltin    bool               `bson:"is_builtin" json:"isBuiltin"`
+	CreateTime   time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime   time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// TakeoverSignatureModel 子域接管签名模型
+type TakeoverSignatureModel struct {
+	*BaseModel[TakeoverSignature]
+}
+
+// NewTakeoverSignatureModel 创建子域接管签名模型
+func NewTakeoverSignatureModel(db *mongo.Database) *TakeoverSignatureModel {
+	coll := db.Collection("takeover_signature")
+	m := &TakeoverSignatureModel{
+		BaseModel: NewBaseModel[TakeoverSignature](coll),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "service", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "enabled", Value: 1}}},
+	})
+
+	return m
+}
+
+// Create 新建签名
+func (m *TakeoverSignatureModel) Create(ctx context.Context, doc *TakeoverSignature) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	return m.Insert(ctx, doc)
+}
+
+// Update 更新签名
+func (m *TakeoverSignatureModel) Update(ctx context.Context, id string, doc *TakeoverSignature) error {
+	return m.UpdateById(ctx, id, bson.M{
+		"service":      doc.Service,
+		"record_type":  doc.RecordType,
+		"patterns":     doc.Patterns,
+		"fingerprints": doc.Fingerprints,
+		"http_status":  doc.HttpStatus,
+		"nxdomain":     doc.NXDomain,
+		"refused":      doc.Refused,
+		"severity":     doc.Severity,
+		"description":  doc.Description,
+		"enabled":      doc.Enabled,
+	})
+}
+
+// FindByService 按服务名称查找，不存在返回 nil
+func (m *TakeoverSignatureModel) FindByService(ctx context.Context, service string) (*TakeoverSignature, error) {
+	doc, err := m.FindOne(ctx, bson.M{"service": service})
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// FindEnabled 查询全部已启用的签名
+func (m *TakeoverSignatureModel) FindEnabled(ctx context.Context) ([]TakeoverSignature, error) {
+	return m.FindWithSort(ctx, bson.M{"enabled": true}, 0, 0, "service", 1)
+}
+
+// CountBuiltin 内置签名数量
+func (m *TakeoverSignatureModel) CountBuiltin(ctx context.Context) (int64, error) {
+	return m.Count(ctx, bson.M{"is_builtin": true})
+}
@@ -125,6 +125,29 @@ func (m *MainTaskModel) FindByTaskId(ctx context.Context, taskId string) (*MainT
 	return &doc, err
 }
 
+// FindPreviousCronRun 查找同一定时任务在指定任务之前最近一次成功的执行，不存在返回 nil
+func (m *MainTaskModel) FindPreviousCronRun(ctx context.Context, task *MainTask) (*MainTask, error) {
+	if !task.IsCron || task.CronRule == "" {
+		return nil, nil
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "create_time", Value: -1}})
+	var doc MainTask
+	err := m.coll.FindOne(ctx, bson.M{
+		"is_cron":     true,
+		"cron_rule":   task.CronRule,
+		"_id":         bson.M{"$ne": task.Id},
+		"create_time": bson.M{"$lt": task.CreateTime},
+		"status":      TaskStatusSuccess,
+	}, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
 func (m *MainTaskModel) Find(ctx context.Context, filter bson.M, page, pageSize int) ([]MainTask, error) {
 	opts := options.Find()
 	if page > 0 && pageSize > 0 {
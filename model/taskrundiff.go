@@ -0,0 +1,313 @@
+package model
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AssetSnapshot 任务执行结束时资产的关键字段，用于两次执行之间的对比
+type AssetSnapshot struct {
+	Service    string   `bson:"service,omitempty" json:"service,omitempty"`
+	Title      string   `bson:"title,omitempty" json:"title,omitempty"`
+	App        []string `bson:"app,omitempty" json:"app,omitempty"`
+	HttpStatus string   `bson:"status,omitempty" json:"httpStatus,omitempty"`
+	CertHash   string   `bson:"cert_hash,omitempty" json:"certHash,omitempty"` // 证书内容的SHA-1，只用于判断是否变化
+}
+
+// VulSnapshot 任务执行结束时的漏洞
+type VulSnapshot struct {
+	PocFile  string `bson:"pocfile" json:"pocFile"`
+	VulName  string `bson:"vul_name,omitempty" json:"vulName,omitempty"`
+	Severity string `bson:"severity" json:"severity"`
+	Url      string `bson:"url,omitempty" json:"url,omitempty"`
+}
+
+func (v VulSnapshot) key() string {
+	if v.PocFile != "" {
+		return v.PocFile
+	}
+	return v.VulName
+}
+
+// BuildTaskRunSnapshot 将一次任务执行的资产和漏洞按地址(authority)整理为历史版本
+func BuildTaskRunSnapshot(workspaceId, taskId string, assets []Asset, vuls []Vul, scanTime time.Time) []ScanResultHistory {
+	index := make(map[string]int)
+	docs := make([]ScanResultHistory, 0, len(assets))
+
+	record := func(authority, host string, port int) *ScanResultHistory {
+		if i, ok := index[authority]; ok {
+			return &docs[i]
+		}
+		index[authority] = len(docs)
+		docs = append(docs, ScanResultHistory{
+			WorkspaceId:   workspaceId,
+			TaskId:        taskId,
+			Authority:     authority,
+			Host:          host,
+			Port:          port,
+			VersionId:     primitive.NewObjectID().Hex(),
+			ScanTimestamp: scanTime,
+		})
+		return &docs[len(docs)-1]
+	}
+
+	for _, a := range assets {
+		if a.Authority == "" {
+			continue
+		}
+		doc := record(a.Authority, a.Host, a.Port)
+		doc.AssetId = a.Id.Hex()
+		snap := &AssetSnapshot{
+			Service:    a.Service,
+			Title:      a.Title,
+			App:        a.App,
+			HttpStatus: a.HttpStatus,
+		}
+		if a.Cert != "" {
+			sum := sha1.Sum([]byte(a.Cert))
+			snap.CertHash = hex.EncodeToString(sum[:])
+		}
+		doc.Asset = snap
+	}
+
+	for _, v := range vuls {
+		if v.Authority == "" {
+			continue
+		}
+		doc := record(v.Authority, v.Host, v.Port)
+		doc.Vuls = append(doc.Vuls, VulSnapshot{
+			PocFile:  v.PocFile,
+			VulName:  v.VulName,
+			Severity: v.Severity,
+			Url:      v.Url,
+		})
+	}
+
+	for i := range docs {
+		docs[i].ChangesSummary = fmt.Sprintf("Task run %s: %d vulnerabilities", taskId, len(docs[i].Vuls))
+	}
+	return docs
+}
+
+// TaskRunChange 同一资产在两次执行之间的字段变化
+type TaskRunChange struct {
+	Authority string `json:"authority"`
+	Field     string `json:"field"` // service/title/app/status/cert
+	OldValue  string `json:"oldValue"`
+	NewValue  string `json:"newValue"`
+}
+
+// TaskRunVul 差异中的漏洞
+type TaskRunVul struct {
+	Authority string `json:"authority"`
+	VulSnapshot
+}
+
+// TaskRunDiff 同一任务两次执行结果的差异
+type TaskRunDiff struct {
+	BaseTaskId   string          `json:"baseTaskId"`
+	TaskId       string          `json:"taskId"`
+	NewAssets    []string        `json:"newAssets"`    // 新出现主机的地址
+	GoneAssets   []string        `json:"goneAssets"`   // 本次未再出现主机的地址
+	NewPorts     []string        `json:"newPorts"`     // 已有主机上新开放的端口
+	ClosedPorts  []string        `json:"closedPorts"`  // 已有主机上关闭的端口
+	Changes      []TaskRunChange `json:"changes"`      // 标题、应用、证书等变化
+	NewVuls      []TaskRunVul    `json:"newVuls"`      // 新增漏洞
+	ResolvedVuls []TaskRunVul    `json:"resolvedVuls"` // 本次扫描到该地址但未再发现的漏洞
+}
+
+// HasChanges 是否存在任何差异
+func (d *TaskRunDiff) HasChanges() bool {
+	return len(d.NewAssets) > 0 || len(d.GoneAssets) > 0 || len(d.NewPorts) > 0 || len(d.ClosedPorts) > 0 ||
+		len(d.Changes) > 0 || len(d.NewVuls) > 0 || len(d.ResolvedVuls) > 0
+}
+
+// Summary 生成适合放在通知里的文字摘要，每类最多列出 limit 条，limit<=0 时只输出统计
+func (d *TaskRunDiff) Summary(limit int) string {
+	if !d.HasChanges() {
+		return "与上次执行相比无变化"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "与上次执行相比: 新增资产 %d, 消失资产 %d, 新开放端口 %d, 关闭端口 %d, 变更 %d, 新增漏洞 %d, 已修复漏洞 %d",
+		len(d.NewAssets), len(d.GoneAssets), len(d.NewPorts), len(d.ClosedPorts),
+		len(d.Changes), len(d.NewVuls), len(d.ResolvedVuls))
+
+	writeList := func(title string, items []string) {
+		if len(items) == 0 || limit <= 0 {
+			return
+		}
+		sb.WriteString("\n" + title + ": ")
+		if len(items) > limit {
+			sb.WriteString(strings.Join(items[:limit], ", "))
+			fmt.Fprintf(&sb, " 等%d个", len(items))
+			return
+		}
+		sb.WriteString(strings.Join(items, ", "))
+	}
+	vulNames := func(vuls []TaskRunVul) []string {
+		names := make([]string, 0, len(vuls))
+		for _, v := range vuls {
+			names = append(names, fmt.Sprintf("%s [%s] %s", v.Authority, v.Severity, v.key()))
+		}
+		return names
+	}
+	changes := make([]string, 0, len(d.Changes))
+	for _, c := range d.Changes {
+		changes = append(changes, fmt.Sprintf("%s %s: %s -> %s", c.Authority, c.Field, c.OldValue, c.NewValue))
+	}
+
+	writeList("新增漏洞", vulNames(d.NewVuls))
+	writeList("新增资产", d.NewAssets)
+	writeList("新开放端口", d.NewPorts)
+	writeList("关闭端口", d.ClosedPorts)
+	writeList("消失资产", d.GoneAssets)
+	writeList("已修复漏洞", vulNames(d.ResolvedVuls))
+	writeList("变更", changes)
+	return sb.String()
+}
+
+// DiffTaskRuns 对比两次执行的快照，base 为较早的一次
+func DiffTaskRuns(base, current []ScanResultHistory) *TaskRunDiff {
+	diff := &TaskRunDiff{
+		NewAssets:    []string{},
+		GoneAssets:   []string{},
+		NewPorts:     []string{},
+		ClosedPorts:  []string{},
+		Changes:      []TaskRunChange{},
+		NewVuls:      []TaskRunVul{},
+		ResolvedVuls: []TaskRunVul{},
+	}
+	if len(base) > 0 {
+		diff.BaseTaskId = base[0].TaskId
+	}
+	if len(current) > 0 {
+		diff.TaskId = current[0].TaskId
+	}
+
+	baseByAuth, baseHosts := indexTaskRun(base)
+	curByAuth, curHosts := indexTaskRun(current)
+
+	for auth, cur := range curByAuth {
+		old, existed := baseByAuth[auth]
+		if cur.Asset != nil && (!existed || old.Asset == nil) {
+			if baseHosts[cur.Host] {
+				diff.NewPorts = append(diff.NewPorts, auth)
+			} else {
+				diff.NewAssets = append(diff.NewAssets, auth)
+			}
+		}
+		if existed && old.Asset != nil && cur.Asset != nil {
+			diff.Changes = append(diff.Changes, diffAssetSnapshot(auth, old.Asset, cur.Asset)...)
+		}
+
+		oldVuls := make(map[string]bool)
+		if existed {
+			for _, v := range old.Vuls {
+				oldVuls[v.key()] = true
+			}
+		}
+		curVuls := make(map[string]bool)
+		for _, v := range cur.Vuls {
+			curVuls[v.key()] = true
+			if !oldVuls[v.key()] {
+				diff.NewVuls = append(diff.NewVuls, TaskRunVul{Authority: auth, VulSnapshot: v})
+			}
+		}
+		// 只有本次扫描到了该地址，才能判断漏洞已修复
+		if existed {
+			for _, v := range old.Vuls {
+				if !curVuls[v.key()] {
+					diff.ResolvedVuls = append(diff.ResolvedVuls, TaskRunVul{Authority: auth, VulSnapshot: v})
+				}
+			}
+		}
+	}
+
+	for auth, old := range baseByAuth {
+		if old.Asset == nil {
+			continue
+		}
+		if cur, ok := curByAuth[auth]; ok && cur.Asset != nil {
+			continue
+		}
+		if curHosts[old.Host] {
+			diff.ClosedPorts = append(diff.ClosedPorts, auth)
+		} else {
+			diff.GoneAssets = append(diff.GoneAssets, auth)
+		}
+	}
+
+	sort.Strings(diff.NewAssets)
+	sort.Strings(diff.GoneAssets)
+	sort.Strings(diff.NewPorts)
+	sort.Strings(diff.ClosedPorts)
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		if diff.Changes[i].Authority != diff.Changes[j].Authority {
+			return diff.Changes[i].Authority < diff.Changes[j].Authority
+		}
+		return diff.Changes[i].Field < diff.Changes[j].Field
+	})
+	sortTaskRunVuls(diff.NewVuls)
+	sortTaskRunVuls(diff.ResolvedVuls)
+	return diff
+}
+
+// indexTaskRun 按地址索引快照，并返回出现过资产的主机集合
+func indexTaskRun(docs []ScanResultHistory) (map[string]*ScanResultHistory, map[string]bool) {
+	byAuth := make(map[string]*ScanResultHistory, len(docs))
+	hosts := make(map[string]bool)
+	for i := range docs {
+		if docs[i].Authority == "" {
+			continue
+		}
+		byAuth[docs[i].Authority] = &docs[i]
+		if docs[i].Asset != nil {
+			hosts[docs[i].Host] = true
+		}
+	}
+	return byAuth, hosts
+}
+
+func diffAssetSnapshot(authority string, old, cur *AssetSnapshot) []TaskRunChange {
+	var changes []TaskRunChange
+	add := func(field, o, n string) {
+		if o != n {
+			changes = append(changes, TaskRunChange{Authority: authority, Field: field, OldValue: o, NewValue: n})
+		}
+	}
+	add("service", old.Service, cur.Service)
+	add("title", old.Title, cur.Title)
+	add("status", old.HttpStatus, cur.HttpStatus)
+	add("app", joinSorted(old.App), joinSorted(cur.App))
+	add("cert", shortHash(old.CertHash), shortHash(cur.CertHash))
+	return changes
+}
+
+func joinSorted(items []string) string {
+	sorted := append([]string(nil), items...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
+
+func sortTaskRunVuls(vuls []TaskRunVul) {
+	sort.Slice(vuls, func(i, j int) bool {
+		if vuls[i].Authority != vuls[j].Authority {
+			return vuls[i].Authority < vuls[j].Authority
+		}
+		return vuls[i].key() < vuls[j].key()
+	})
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffTaskRuns(t *testing.T) {
+	now := time.Now()
+	base := BuildTaskRunSnapshot("ws", "run1", []Asset{
+		{Authority: "a.com:80", Host: "a.com", Port: 80, Title: "Old", App: []string{"nginx"}},
+		{Authority: "a.com:22", Host: "a.com", Port: 22, Service: "ssh"},
+		{Authority: "b.com:443", Host: "b.com", Port: 443},
+	}, []Vul{
+		{Authority: "a.com:80", PocFile: "poc-fixed", Severity: "high"},
+		{Authority: "a.com:80", PocFile: "poc-kept", Severity: "medium"},
+		{Authority: "b.com:443", PocFile: "poc-gone-host", Severity: "low"},
+	}, now)
+	current := BuildTaskRunSnapshot("ws", "run2", []Asset{
+		{Authority: "a.com:80", Host: "a.com", Port: 80, Title: "New", App: []string{"nginx"}},
+		{Authority: "a.com:8080", Host: "a.com", Port: 8080},
+		{Authority: "c.com:80", Host: "c.com", Port: 80},
+	}, []Vul{
+		{Authority: "a.com:80", PocFile: "poc-kept", Severity: "medium"},
+		{Authority: "c.com:80", PocFile: "poc-new", Severity: "critical"},
+	}, now)
+
+	diff := DiffTaskRuns(base, current)
+
+	if diff.BaseTaskId != "run1" || diff.TaskId != "run2" {
+		t.Fatalf("task ids = %s/%s", diff.BaseTaskId, diff.TaskId)
+	}
+	check := func(name string, got, want []string) {
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+	check("NewAssets", diff.NewAssets, []string{"c.com:80"})
+	check("GoneAssets", diff.GoneAssets, []string{"b.com:443"})
+	check("NewPorts", diff.NewPorts, []string{"a.com:8080"})
+	check("ClosedPorts", diff.ClosedPorts, []string{"a.com:22"})
+
+	if len(diff.Changes) != 1 || diff.Changes[0].Field != "title" || diff.Changes[0].NewValue != "New" {
+		t.Errorf("Changes = %+v", diff.Changes)
+	}
+	if len(diff.NewVuls) != 1 || diff.NewVuls[0].PocFile != "poc-new" {
+		t.Errorf("NewVuls = %+v", diff.NewVuls)
+	}
+	// b.com 本次未扫描到，其漏洞不能算作已修复
+	if len(diff.ResolvedVuls) != 1 || diff.ResolvedVuls[0].PocFile != "poc-fixed" {
+		t.Errorf("ResolvedVuls = %+v", diff.ResolvedVuls)
+	}
+	if !diff.HasChanges() {
+		t.Error("HasChanges() = false")
+	}
+}
+
+func TestDiffTaskRunsUnchanged(t *testing.T) {
+	assets := []Asset{{Authority: "a.com:80", Host: "a.com", Port: 80, Title: "T", Cert: "cert"}}
+	vuls := []Vul{{Authority: "a.com:80", PocFile: "poc", Severity: "high"}}
+	diff := DiffTaskRuns(
+		BuildTaskRunSnapshot("ws", "run1", assets, vuls, time.Now()),
+		BuildTaskRunSnapshot("ws", "run2", assets, vuls, time.Now()),
+	)
+	if diff.HasChanges() {
+		t.Errorf("unexpected changes: %s", diff.Summary(10))
+	}
+}
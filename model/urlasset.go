@@ -0,0 +1,113 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// URLAsset 爬虫发现的URL端点，同一方法和URL再次出现时更新最后发现时间
+type URLAsset struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Authority   string             `bson:"authority" json:"authority"` // host:port
+	Host        string             `bson:"host" json:"host"`
+	Port        int                `bson:"port" json:"port"`
+	URL         string             `bson:"url" json:"url"`
+	Path        string             `bson:"path" json:"path"`
+	Method      string             `bson:"method" json:"method"`
+	Type        string             `bson:"type" json:"type"`     // page/form/api/js
+	Params      []string           `bson:"params" json:"params"` // 查询参数和表单字段名
+	StatusCode  int                `bson:"status_code" json:"statusCode"`
+	ContentType string             `bson:"content_type" json:"contentType"`
+	Source      string             `bson:"source" json:"source"`           // 发现该端点的页面或JS文件
+	MainTaskId  string             `bson:"main_task_id" json:"mainTaskId"` // 最后一次发现该端点的任务
+	FirstSeen   time.Time          `bson:"first_seen" json:"firstSeen"`
+	LastSeen    time.Time          `bson:"last_seen" json:"lastSeen"`
+}
+
+// URLAssetModel URL端点模型
+type URLAssetModel struct {
+	*BaseModel[URLAsset]
+}
+
+// NewURLAssetModel 创建URL端点模型
+func NewURLAssetModel(db *mongo.Database, workspaceId string) *URLAssetModel {
+	coll := db.Collection(workspaceId + "_url_asset")
+	m := &URLAssetModel{
+		BaseModel: NewBaseModel[URLAsset](coll),
+	}
+
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "url", Value: 1}, {Key: "method", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "authority", Value: 1}, {Key: "last_seen", Value: -1}}},
+		{Keys: bson.D{{Key: "type", Value: 1}}},
+	})
+
+	return m
+}
+
+// BatchUpsert 批量写入端点，新端点设置首次发现时间，已有端点更新最后发现时间并合并参数名
+func (m *URLAssetModel) BatchUpsert(ctx context.Context, mainTaskId string, docs []*URLAsset) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		set := bson.M{
+			"authority":    doc.Authority,
+			"host":         doc.Host,
+			"port":         doc.Port,
+			"path":         doc.Path,
+			"type":         doc.Type,
+			"source":       doc.Source,
+			"main_task_id": mainTaskId,
+			"last_seen":    now,
+		}
+		// 未渲染的端点不覆盖已有的响应信息
+		if doc.StatusCode != 0 {
+			set["status_code"] = doc.StatusCode
+			set["content_type"] = doc.ContentType
+		}
+		params := doc.Params
+		if params == nil {
+			params = []string{}
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"url": doc.URL, "method": doc.Method}).
+			SetUpdate(bson.M{
+				"$set":         set,
+				"$addToSet":    bson.M{"params": bson.M{"$each": params}},
+				"$setOnInsert": bson.M{"first_seen": now},
+			}).
+			SetUpsert(true))
+	}
+	_, err := m.Coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// FindRange 按最后发现时间倒序查询，跳过前 skip 条
+func (m *URLAssetModel) FindRange(ctx context.Context, filter bson.M, skip, limit int64) ([]URLAsset, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "last_seen", Value: -1}, {Key: "url", Value: 1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+	cursor, err := m.Coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []URLAsset
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
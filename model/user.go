@@ -17,15 +17,17 @@ const (
 )
 
 type User struct {
-	Id            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Username      string             `bson:"username" json:"username"`
-	Password      string             `bson:"password" json:"-"`
-	Status        string             `bson:"status" json:"status"`
-	WorkspaceIds  []string           `bson:"workspace_ids" json:"workspaceIds"`
-	ScanConfig    string             `bson:"scan_config" json:"scanConfig"` // 用户默认扫描配置JSON
-	LastLoginTime *time.Time         `bson:"last_login_time" json:"lastLoginTime"`
-	CreateTime    time.Time          `bson:"create_time" json:"createTime"`
-	UpdateTime    time.Time          `bson:"update_time" json:"updateTime"`
+	Id             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Username       string             `bson:"username" json:"username"`
+	Password       string             `bson:"password" json:"-"`
+	Status         string             `bson:"status" json:"status"`
+	WorkspaceIds   []string           `bson:"workspace_ids" json:"workspaceIds"`
+	Role           string             `bson:"role,omitempty" json:"role"`                      // 全局角色: viewer/analyst/operator/admin
+	WorkspaceRoles map[string]string  `bson:"workspace_roles,omitempty" json:"workspaceRoles"` // 按工作空间覆盖的角色
+	ScanConfig     string             `bson:"scan_config" json:"scanConfig"`                   // 用户默认扫描配置JSON
+	LastLoginTime  *time.Time         `bson:"last_login_time" json:"lastLoginTime"`
+	CreateTime     time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime     time.Time          `bson:"update_time" json:"updateTime"`
 }
 
 type UserModel struct {
@@ -194,6 +196,42 @@ func (m *UserModel) VerifyPassword(ctx context.Context, username, password strin
 	return user, true
 }
 
+// userRolesMigrationId 用户角色迁移标记，写入 migration 集合后不再重复执行
+const userRolesMigrationId = "user_roles_v1"
+
+// MigrateRoles 为启用权限控制前创建的用户写入明确的角色，只执行一次：
+// 默认管理员 admin 和旧的 superadmin 角色迁移为管理员，其余没有角色的用户迁移为操作员。
+// 迁移完成后没有角色字段的用户按最低权限（只读）处理。
+func (m *UserModel) MigrateRoles(ctx context.Context) (int64, error) {
+	markers := m.coll.Database().Collection("migration")
+	if err := markers.FindOne(ctx, bson.M{"_id": userRolesMigrationId}).Err(); err == nil {
+		return 0, nil
+	} else if err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+
+	noRole := bson.A{bson.M{"role": bson.M{"$exists": false}}, bson.M{"role": ""}}
+	now := time.Now()
+	admins, err := m.coll.UpdateMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"role": "superadmin"},
+		bson.M{"username": "admin", "$or": noRole},
+	}}, bson.M{"$set": bson.M{"role": RoleAdmin, "update_time": now}})
+	if err != nil {
+		return 0, err
+	}
+	others, err := m.coll.UpdateMany(ctx, bson.M{"$or": noRole},
+		bson.M{"$set": bson.M{"role": RoleOperator, "update_time": now}})
+	if err != nil {
+		return admins.ModifiedCount, err
+	}
+
+	_, err = markers.InsertOne(ctx, bson.M{"_id": userRolesMigrationId, "create_time": now})
+	if mongo.IsDuplicateKeyError(err) {
+		err = nil
+	}
+	return admins.ModifiedCount + others.ModifiedCount, err
+}
+
 func HashPassword(password string) string {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
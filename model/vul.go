@@ -46,6 +46,69 @@ type Vul struct {
 	FirstSeenTime time.Time `bson:"first_seen_time,omitempty" json:"firstSeenTime,omitempty"`
 	LastSeenTime  time.Time `bson:"last_seen_time,omitempty" json:"lastSeenTime,omitempty"`
 	ScanCount     int       `bson:"scan_count,omitempty" json:"scanCount,omitempty"`
+
+	// 处置流程字段
+	Status           string        `bson:"status,omitempty" json:"status,omitempty"`     // 处置状态: new/confirmed/false_positive/accepted_risk/fixed/reopened
+	Assignee         string        `bson:"assignee,omitempty" json:"assignee,omitempty"` // 负责人
+	DueTime          time.Time     `bson:"due_time,omitempty" json:"dueTime,omitempty"`  // SLA 截止时间
+	StatusUpdateTime time.Time     `bson:"status_update_time,omitempty" json:"statusUpdateTime,omitempty"`
+	Activities       []VulActivity `bson:"activities,omitempty" json:"activities,omitempty"` // 处置记录（状态变更/指派/评论）
+}
+
+// 漏洞处置状态
+const (
+	VulStatusNew           = "new"
+	VulStatusConfirmed     = "confirmed"
+	VulStatusFalsePositive = "false_positive"
+	VulStatusAcceptedRisk  = "accepted_risk"
+	VulStatusFixed         = "fixed"
+	VulStatusReopened      = "reopened"
+)
+
+// 漏洞处置记录类型
+const (
+	VulActivityStatus  = "status"
+	VulActivityAssign  = "assign"
+	VulActivityComment = "comment"
+)
+
+// VulActivity 漏洞处置记录
+type VulActivity struct {
+	Type     string    `bson:"type" json:"type"`
+	From     string    `bson:"from,omitempty" json:"from,omitempty"`
+	To       string    `bson:"to,omitempty" json:"to,omitempty"`
+	Content  string    `bson:"content,omitempty" json:"content,omitempty"`
+	Operator string    `bson:"operator" json:"operator"`
+	Time     time.Time `bson:"time" json:"time"`
+}
+
+// VulSLA 各严重级别的默认修复时限，未列出的级别不设截止时间
+var VulSLA = map[string]time.Duration{
+	"critical": 7 * 24 * time.Hour,
+	"high":     30 * 24 * time.Hour,
+	"medium":   90 * 24 * time.Hour,
+	"low":      180 * 24 * time.Hour,
+}
+
+// IsValidVulStatus 检查处置状态是否合法
+func IsValidVulStatus(status string) bool {
+	switch status {
+	case VulStatusNew, VulStatusConfirmed, VulStatusFalsePositive,
+		VulStatusAcceptedRisk, VulStatusFixed, VulStatusReopened:
+		return true
+	}
+	return false
+}
+
+// VulOpenStatuses 仍需处理的状态，历史数据没有 status 字段，视为 new
+var VulOpenStatuses = []interface{}{nil, VulStatusNew, VulStatusConfirmed, VulStatusReopened}
+
+// VulStatusFilter 构建处置状态过滤条件，new 同时匹配没有 status 字段的历史数据
+func VulStatusFilter(status string) interface{} {
+	if status == VulStatusNew {
+		return bson.M{"$in": []interface{}{nil, VulStatusNew}}
+	}
+	return status
 }
 
 type VulModel struct {
@@ -175,8 +238,8 @@ func (m *VulModel) DeleteByTaskId(ctx context.Context, taskId string) error {
 	return err
 }
 
-// Upsert 插入或更新漏洞（基于 host+port+pocFile+url 去重）
-func (m *VulModel) Upsert(ctx context.Context, doc *Vul) error {
+// Upsert 插入或更新漏洞（基于 host+port+pocFile+url 去重），返回是否为新发现的漏洞
+func (m *VulModel) Upsert(ctx context.Context, doc *Vul) (bool, error) {
 	now := time.Now()
 	filter := bson.M{
 		"host":    doc.Host,
@@ -219,13 +282,137 @@ func (m *VulModel) Upsert(ctx context.Context, doc *Vul) error {
 			"_id":             primitive.NewObjectID(),
 			"create_time":     now,
 			"first_seen_time": now, // 新增：首次发现时间
+			"status":          VulStatusNew,
 		},
 	}
+	if sla, ok := VulSLA[doc.Severity]; ok {
+		update["$setOnInsert"].(bson.M)["due_time"] = now.Add(sla)
+	}
 	opts := options.Update().SetUpsert(true)
-	_, err := m.coll.UpdateOne(ctx, filter, update, opts)
+	result, err := m.coll.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// UpdateStatus 变更处置状态并追加处置记录，状态未变化时返回 false
+func (m *VulModel) UpdateStatus(ctx context.Context, id, status, operator, comment string) (bool, error) {
+	doc, err := m.FindById(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	from := doc.Status
+	if from == "" {
+		from = VulStatusNew
+	}
+	if from == status {
+		return false, nil
+	}
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"status":             status,
+			"status_update_time": now,
+			"update_time":        now,
+		},
+		"$push": bson.M{"activities": VulActivity{
+			Type:     VulActivityStatus,
+			From:     from,
+			To:       status,
+			Content:  comment,
+			Operator: operator,
+			Time:     now,
+		}},
+	}
+	_, err = m.coll.UpdateOne(ctx, bson.M{"_id": doc.Id}, update)
+	return err == nil, err
+}
+
+// Assign 指派负责人，dueTime 非零时同时更新截止时间
+func (m *VulModel) Assign(ctx context.Context, id, assignee, operator string, dueTime time.Time) error {
+	doc, err := m.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	set := bson.M{
+		"assignee":    assignee,
+		"update_time": now,
+	}
+	activity := VulActivity{
+		Type:     VulActivityAssign,
+		From:     doc.Assignee,
+		To:       assignee,
+		Operator: operator,
+		Time:     now,
+	}
+	if !dueTime.IsZero() {
+		set["due_time"] = dueTime
+		activity.Content = "截止时间: " + dueTime.Local().Format("2006-01-02")
+	}
+	_, err = m.coll.UpdateOne(ctx, bson.M{"_id": doc.Id}, bson.M{
+		"$set":  set,
+		"$push": bson.M{"activities": activity},
+	})
 	return err
 }
 
+// AddComment 添加评论
+func (m *VulModel) AddComment(ctx context.Context, id, operator, content string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	res, err := m.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$set": bson.M{"update_time": now},
+		"$push": bson.M{"activities": VulActivity{
+			Type:     VulActivityComment,
+			Content:  content,
+			Operator: operator,
+			Time:     now,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ReopenFixed 将已标记修复但再次被发现的漏洞（相同 authority+pocfile）重新打开
+func (m *VulModel) ReopenFixed(ctx context.Context, authority, pocFile, taskId string) (int64, error) {
+	now := time.Now()
+	filter := bson.M{
+		"authority": authority,
+		"pocfile":   pocFile,
+		"status":    VulStatusFixed,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":             VulStatusReopened,
+			"status_update_time": now,
+			"update_time":        now,
+		},
+		"$push": bson.M{"activities": VulActivity{
+			Type:     VulActivityStatus,
+			From:     VulStatusFixed,
+			To:       VulStatusReopened,
+			Content:  "任务 " + taskId + " 再次发现该漏洞",
+			Operator: "system",
+			Time:     now,
+		}},
+	}
+	res, err := m.coll.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
 // BatchDelete 批量删除漏洞
 func (m *VulModel) BatchDelete(ctx context.Context, ids []string) (int64, error) {
 	oids := make([]primitive.ObjectID, 0, len(ids))
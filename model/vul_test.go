@@ -0,0 +1,137 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestVulStatusFilter(t *testing.T) {
+	for _, s := range []string{VulStatusNew, VulStatusConfirmed, VulStatusFalsePositive, VulStatusAcceptedRisk, VulStatusFixed, VulStatusReopened} {
+		if !IsValidVulStatus(s) {
+			t.Errorf("%s should be a valid status", s)
+		}
+	}
+	if IsValidVulStatus("closed") || IsValidVulStatus("") {
+		t.Error("unknown status accepted")
+	}
+	// 历史数据没有 status 字段，按 new 处理
+	if got, ok := VulStatusFilter(VulStatusNew).(bson.M); !ok || len(got["$in"].([]interface{})) != 2 {
+		t.Errorf("new filter should also match missing status, got %v", got)
+	}
+	if got := VulStatusFilter(VulStatusFixed); got != VulStatusFixed {
+		t.Errorf("fixed filter = %v", got)
+	}
+}
+
+// startedCommand 返回第 i 个发出的指定命令
+func startedCommand(mt *mtest.T, name string) bson.Raw {
+	mt.Helper()
+	for {
+		evt := mt.GetStartedEvent()
+		if evt == nil {
+			mt.Fatalf("no %s command sent", name)
+		}
+		if evt.CommandName == name {
+			return evt.Command
+		}
+	}
+}
+
+func updateSpec(mt *mtest.T, cmd bson.Raw) (filter, update bson.M) {
+	mt.Helper()
+	var body struct {
+		Updates []struct {
+			Q bson.M `bson:"q"`
+			U bson.M `bson:"u"`
+		} `bson:"updates"`
+	}
+	if err := bson.Unmarshal(cmd, &body); err != nil || len(body.Updates) != 1 {
+		mt.Fatalf("decode update command: %v", err)
+	}
+	return body.Updates[0].Q, body.Updates[0].U
+}
+
+func TestVulTriage(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	ctx := context.Background()
+
+	mt.Run("new vul starts as new with severity SLA", func(mt *mtest.T) {
+		m := NewVulModel(mt.DB, "ws")
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "upserted", Value: bson.A{bson.D{{Key: "index", Value: 0}, {Key: "_id", Value: primitive.NewObjectID()}}}}))
+		before := time.Now()
+		if _, err := m.Upsert(ctx, &Vul{Host: "10.0.0.1", Port: 443, PocFile: "cve.yaml", Severity: "critical"}); err != nil {
+			mt.Fatal(err)
+		}
+		_, update := updateSpec(mt, startedCommand(mt, "update"))
+		onInsert := update["$setOnInsert"].(bson.M)
+		if onInsert["status"] != VulStatusNew {
+			mt.Errorf("status on insert = %v, want new", onInsert["status"])
+		}
+		due := onInsert["due_time"].(primitive.DateTime).Time()
+		if want := before.Add(VulSLA["critical"]); due.Before(want.Add(-time.Second)) || due.After(want.Add(time.Minute)) {
+			mt.Errorf("due_time = %v, want about %v", due, want)
+		}
+	})
+
+	mt.Run("status change records activity", func(mt *mtest.T) {
+		m := NewVulModel(mt.DB, "ws")
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "db.ws_vul", mtest.FirstBatch, bson.D{{Key: "_id", Value: id}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		changed, err := m.UpdateStatus(ctx, id.Hex(), VulStatusFalsePositive, "alice", "测试环境")
+		if err != nil || !changed {
+			mt.Fatalf("UpdateStatus = %v, %v", changed, err)
+		}
+		_, update := updateSpec(mt, startedCommand(mt, "update"))
+		if update["$set"].(bson.M)["status"] != VulStatusFalsePositive {
+			mt.Errorf("status not set: %v", update)
+		}
+		activity := update["$push"].(bson.M)["activities"].(bson.M)
+		if activity["type"] != VulActivityStatus || activity["from"] != VulStatusNew || activity["to"] != VulStatusFalsePositive ||
+			activity["operator"] != "alice" || activity["content"] != "测试环境" {
+			mt.Errorf("unexpected activity %v", activity)
+		}
+	})
+
+	mt.Run("same status is a no-op", func(mt *mtest.T) {
+		m := NewVulModel(mt.DB, "ws")
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ws_vul", mtest.FirstBatch, bson.D{{Key: "_id", Value: id}, {Key: "status", Value: VulStatusConfirmed}}))
+		changed, err := m.UpdateStatus(ctx, id.Hex(), VulStatusConfirmed, "alice", "")
+		if err != nil || changed {
+			mt.Fatalf("UpdateStatus = %v, %v; want no change", changed, err)
+		}
+		for evt := mt.GetStartedEvent(); evt != nil; evt = mt.GetStartedEvent() {
+			if evt.CommandName == "update" {
+				mt.Error("no update should be sent when the status is unchanged")
+			}
+		}
+	})
+
+	mt.Run("rescan reopens fixed vuls only", func(mt *mtest.T) {
+		m := NewVulModel(mt.DB, "ws")
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+		n, err := m.ReopenFixed(ctx, "10.0.0.1:443", "cve.yaml", "task-2")
+		if err != nil || n != 2 {
+			mt.Fatalf("ReopenFixed = %d, %v", n, err)
+		}
+		filter, update := updateSpec(mt, startedCommand(mt, "update"))
+		if filter["authority"] != "10.0.0.1:443" || filter["pocfile"] != "cve.yaml" || filter["status"] != VulStatusFixed {
+			mt.Errorf("unexpected filter %v", filter)
+		}
+		if update["$set"].(bson.M)["status"] != VulStatusReopened {
+			mt.Errorf("status not reopened: %v", update)
+		}
+		activity := update["$push"].(bson.M)["activities"].(bson.M)
+		if activity["from"] != VulStatusFixed || activity["to"] != VulStatusReopened || activity["operator"] != "system" {
+			mt.Errorf("unexpected activity %v", activity)
+		}
+	})
+}
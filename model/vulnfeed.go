@@ -0,0 +1,342 @@
+package model
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"cscan/pkg/cpe"
+	"cscan/pkg/vulnfeed"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 资产上单条潜在漏洞保留的参考链接数量
+const maxPotentialVulnRefs = 10
+
+// maxPotentialVulns 单个资产最多记录的潜在漏洞数量，按 CVSS 从高到低保留
+const maxPotentialVulns = 200
+
+// VulnFeedEntry 离线导入的漏洞情报（NVD/OSV），用于按 CPE 版本匹配潜在漏洞
+type VulnFeedEntry struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	VulnId     string             `bson:"vuln_id" json:"vulnId"`
+	CveId      string             `bson:"cve_id,omitempty" json:"cveId"`
+	Source     string             `bson:"source" json:"source"` // nvd/osv
+	Summary    string             `bson:"summary" json:"summary"`
+	CVSS       float64            `bson:"cvss" json:"cvss"`
+	CVSSVector string             `bson:"cvss_vector,omitempty" json:"cvssVector"`
+	Severity   string             `bson:"severity" json:"severity"`
+	References []string           `bson:"references,omitempty" json:"references"`
+	Published  string             `bson:"published,omitempty" json:"published"`
+	Modified   string             `bson:"modified,omitempty" json:"modified"`
+	Affected   []VulnFeedAffected `bson:"affected" json:"affected"`
+	Products   []string           `bson:"products" json:"-"` // 受影响产品名，用于索引查询
+	CreateTime time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// VulnFeedAffected 受影响的产品及版本范围
+type VulnFeedAffected struct {
+	Part           string   `bson:"part,omitempty" json:"part"`
+	Vendor         string   `bson:"vendor" json:"vendor"`
+	Product        string   `bson:"product" json:"product"`
+	Ecosystem      string   `bson:"ecosystem,omitempty" json:"ecosystem,omitempty"`
+	Version        string   `bson:"version,omitempty" json:"version,omitempty"`
+	StartIncluding string   `bson:"start_including,omitempty" json:"startIncluding,omitempty"`
+	StartExcluding string   `bson:"start_excluding,omitempty" json:"startExcluding,omitempty"`
+	EndIncluding   string   `bson:"end_including,omitempty" json:"endIncluding,omitempty"`
+	EndExcluding   string   `bson:"end_excluding,omitempty" json:"endExcluding,omitempty"`
+	Versions       []string `bson:"versions,omitempty" json:"versions,omitempty"`
+}
+
+// PotentialVuln 资产指纹版本命中漏洞情报得到的潜在漏洞，仅基于版本推断，未发送任何验证请求
+type PotentialVuln struct {
+	VulnId     string   `bson:"vuln_id" json:"vulnId"`
+	CveId      string   `bson:"cve_id,omitempty" json:"cveId,omitempty"`
+	Source     string   `bson:"source" json:"source"`
+	CPE        string   `bson:"cpe" json:"cpe"` // 命中的资产 CPE
+	CVSS       float64  `bson:"cvss" json:"cvss"`
+	Severity   string   `bson:"severity" json:"severity"`
+	Summary    string   `bson:"summary" json:"summary"`
+	References []string `bson:"references,omitempty" json:"references,omitempty"`
+}
+
+// VulnFeedModel 漏洞情报模型
+type VulnFeedModel struct {
+	*BaseModel[VulnFeedEntry]
+}
+
+// NewVulnFeedModel 创建漏洞情报模型
+func NewVulnFeedModel(db *mongo.Database) *VulnFeedModel {
+	coll := db.Collection("vuln_feed")
+	m := &VulnFeedModel{
+		BaseModel: NewBaseModel[VulnFeedEntry](coll),
+	}
+
+	ctx := context.Background()
+	m.EnsureIndexes(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "source", Value: 1}, {Key: "vuln_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "products", Value: 1}}},
+		{Keys: bson.D{{Key: "cve_id", Value: 1}}},
+	})
+
+	return m
+}
+
+// BulkUpsert 按 数据源+编号 批量写入情报，已存在的条目整体替换
+// 返回: 新插入数量, 更新数量
+func (m *VulnFeedModel) BulkUpsert(ctx context.Context, entries []vulnfeed.Entry) (int, int, error) {
+	now := time.Now()
+	var inserted, updated int
+	batchSize := 500
+	for i := 0; i < len(entries); i += batchSize {
+		end := i + batchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		models := make([]mongo.WriteModel, 0, end-i)
+		for j := i; j < end; j++ {
+			doc := convertFeedEntry(&entries[j])
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"source": doc.Source, "vuln_id": doc.VulnId}).
+				SetUpdate(bson.M{
+					"$set":         doc,
+					"$currentDate": bson.M{"update_time": true},
+					"$setOnInsert": bson.M{"create_time": now},
+				}).
+				SetUpsert(true))
+		}
+
+		result, err := m.Coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return inserted, updated, err
+		}
+		inserted += int(result.UpsertedCount)
+		updated += int(result.MatchedCount)
+	}
+	return inserted, updated, nil
+}
+
+// convertFeedEntry 转换为存储结构，时间字段由 BulkUpsert 设置
+func convertFeedEntry(e *vulnfeed.Entry) *feedEntryUpdate {
+	doc := &feedEntryUpdate{
+		VulnId:     e.VulnId,
+		CveId:      e.CveId,
+		Source:     e.Source,
+		Summary:    e.Summary,
+		CVSS:       e.CVSS,
+		CVSSVector: e.CVSSVector,
+		Severity:   e.Severity,
+		References: e.References,
+		Published:  e.Published,
+		Modified:   e.Modified,
+		Affected:   make([]VulnFeedAffected, 0, len(e.Affected)),
+		Products:   []string{},
+	}
+	seen := make(map[string]bool)
+	for _, a := range e.Affected {
+		doc.Affected = append(doc.Affected, VulnFeedAffected{
+			Part:           a.Part,
+			Vendor:         a.Vendor,
+			Product:        a.Product,
+			Ecosystem:      a.Ecosystem,
+			Version:        a.Version,
+			StartIncluding: a.Range.StartIncluding,
+			StartExcluding: a.Range.StartExcluding,
+			EndIncluding:   a.Range.EndIncluding,
+			EndExcluding:   a.Range.EndExcluding,
+			Versions:       a.Range.Versions,
+		})
+		if !seen[a.Product] {
+			seen[a.Product] = true
+			doc.Products = append(doc.Products, a.Product)
+		}
+	}
+	return doc
+}
+
+// feedEntryUpdate 写入时使用的结构，不含 _id 和时间字段，避免 $set 覆盖
+type feedEntryUpdate struct {
+	VulnId     string             `bson:"vuln_id"`
+	CveId      string             `bson:"cve_id,omitempty"`
+	Source     string             `bson:"source"`
+	Summary    string             `bson:"summary"`
+	CVSS       float64            `bson:"cvss"`
+	CVSSVector string             `bson:"cvss_vector,omitempty"`
+	Severity   string             `bson:"severity"`
+	References []string           `bson:"references,omitempty"`
+	Published  string             `bson:"published,omitempty"`
+	Modified   string             `bson:"modified,omitempty"`
+	Affected   []VulnFeedAffected `bson:"affected"`
+	Products   []string           `bson:"products"`
+}
+
+// toAffected 转换为 vulnfeed.Affected 以复用版本匹配逻辑
+func (a *VulnFeedAffected) toAffected() *vulnfeed.Affected {
+	return &vulnfeed.Affected{
+		Part:      a.Part,
+		Vendor:    a.Vendor,
+		Product:   a.Product,
+		Ecosystem: a.Ecosystem,
+		Version:   a.Version,
+		Range: cpe.Range{
+			StartIncluding: a.StartIncluding,
+			StartExcluding: a.StartExcluding,
+			EndIncluding:   a.EndIncluding,
+			EndExcluding:   a.EndExcluding,
+			Versions:       a.Versions,
+		},
+	}
+}
+
+// Match 使用资产的 CPE 列表匹配漏洞情报，只有带具体版本的 CPE 参与匹配
+// 同一 CVE 同时存在于 NVD 和 OSV 时只保留一条（优先 NVD），结果按 CVSS 从高到低排序
+func (m *VulnFeedModel) Match(ctx context.Context, cpes []string) ([]PotentialVuln, error) {
+	var parsed []*cpe.CPE
+	var products []string
+	seenProduct := make(map[string]bool)
+	for _, s := range cpes {
+		c, err := cpe.Parse(s)
+		if err != nil || !c.HasVersion() {
+			continue
+		}
+		parsed = append(parsed, c)
+		if !seenProduct[c.Product] {
+			seenProduct[c.Product] = true
+			products = append(products, c.Product)
+		}
+	}
+	if len(parsed) == 0 {
+		return nil, nil
+	}
+
+	// 不取回 Products，减小传输量
+	opts := options.Find().SetProjection(bson.M{"products": 0})
+	cursor, err := m.Coll.Find(ctx, bson.M{"products": bson.M{"$in": products}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	byKey := make(map[string]PotentialVuln)
+	for cursor.Next(ctx) {
+		var entry VulnFeedEntry
+		if err := cursor.Decode(&entry); err != nil {
+			continue
+		}
+		matched := matchFeedEntry(&entry, parsed)
+		if matched == nil {
+			continue
+		}
+		key := entry.VulnId
+		if entry.CveId != "" {
+			key = entry.CveId
+		}
+		if exist, ok := byKey[key]; ok && (exist.Source == vulnfeed.SourceNVD || entry.Source != vulnfeed.SourceNVD) {
+			continue
+		}
+		refs := entry.References
+		if len(refs) > maxPotentialVulnRefs {
+			refs = refs[:maxPotentialVulnRefs]
+		}
+		byKey[key] = PotentialVuln{
+			VulnId:     entry.VulnId,
+			CveId:      entry.CveId,
+			Source:     entry.Source,
+			CPE:        matched.String(),
+			CVSS:       entry.CVSS,
+			Severity:   entry.Severity,
+			Summary:    entry.Summary,
+			References: refs,
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]PotentialVuln, 0, len(byKey))
+	for _, v := range byKey {
+		result = append(result, v)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CVSS != result[j].CVSS {
+			return result[i].CVSS > result[j].CVSS
+		}
+		return result[i].VulnId > result[j].VulnId
+	})
+	if len(result) > maxPotentialVulns {
+		result = result[:maxPotentialVulns]
+	}
+	return result, nil
+}
+
+// matchFeedEntry 返回命中该情报的第一个 CPE，未命中返回 nil
+func matchFeedEntry(entry *VulnFeedEntry, cpes []*cpe.CPE) *cpe.CPE {
+	for i := range entry.Affected {
+		affected := entry.Affected[i].toAffected()
+		for _, c := range cpes {
+			if affected.Matches(c) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// VulnFeedStats 漏洞情报统计
+type VulnFeedStats struct {
+	Total      int64            `json:"total"`
+	BySource   map[string]int64 `json:"bySource"`
+	BySeverity map[string]int64 `json:"bySeverity"`
+	LastUpdate *time.Time       `json:"lastUpdate,omitempty"`
+}
+
+// Stats 按数据源和严重程度统计情报数量
+func (m *VulnFeedModel) Stats(ctx context.Context) (*VulnFeedStats, error) {
+	stats := &VulnFeedStats{
+		BySource:   make(map[string]int64),
+		BySeverity: make(map[string]int64),
+	}
+	total, err := m.Count(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	stats.Total = total
+
+	for field, target := range map[string]map[string]int64{"source": stats.BySource, "severity": stats.BySeverity} {
+		counts, err := m.CountByField(ctx, field, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range counts {
+			if name, ok := c.Field.(string); ok {
+				target[name] = int64(c.Count)
+			}
+		}
+	}
+
+	latest, err := m.FindWithSort(ctx, bson.M{}, 1, 1, "update_time", -1)
+	if err != nil {
+		return nil, err
+	}
+	if len(latest) > 0 {
+		stats.LastUpdate = &latest[0].UpdateTime
+	}
+	return stats, nil
+}
+
+// DeleteBySource 删除指定数据源的情报，source 为空时清空全部
+func (m *VulnFeedModel) DeleteBySource(ctx context.Context, source string) (int64, error) {
+	if source == "" {
+		return m.Clear(ctx)
+	}
+	return m.DeleteMany(ctx, bson.M{"source": source})
+}
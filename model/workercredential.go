@@ -0,0 +1,175 @@
+package model
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WorkerCredentialPrefix Worker 凭证前缀，用于和安装密钥、API Token 区分
+const WorkerCredentialPrefix = "csw_"
+
+// WorkerCredential Worker 注册后获得的独立凭证，只保存哈希
+// 安装密钥仅用于注册，之后 Worker 的所有请求都使用该凭证认证，Worker 名称以凭证记录为准
+type WorkerCredential struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkerName   string             `bson:"worker_name" json:"workerName"`
+	TokenHash    string             `bson:"token_hash" json:"-"`
+	TokenHint    string             `bson:"token_hint" json:"tokenHint"` // 明文前几位，便于识别
+	IP           string             `bson:"ip" json:"ip"`                // 注册时的IP
+	OS           string             `bson:"os" json:"os"`
+	Arch         string             `bson:"arch" json:"arch"`
+	Revoked      bool               `bson:"revoked" json:"revoked"`
+	RevokeReason string             `bson:"revoke_reason,omitempty" json:"revokeReason"`
+	RevokeTime   *time.Time         `bson:"revoke_time,omitempty" json:"revokeTime"`
+	LastUsedTime *time.Time         `bson:"last_used_time,omitempty" json:"lastUsedTime"`
+	LastUsedIP   string             `bson:"last_used_ip,omitempty" json:"lastUsedIp"`
+	// ReenrollUntil 管理员批准在此时间前使用同名重新注册，替换该凭证
+	ReenrollUntil *time.Time `bson:"reenroll_until,omitempty" json:"reenrollUntil"`
+	CreateTime    time.Time  `bson:"create_time" json:"createTime"`
+}
+
+// ReenrollApproved 判断该凭证当前是否允许被同名重新注册替换
+func (c *WorkerCredential) ReenrollApproved(now time.Time) bool {
+	return c.ReenrollUntil != nil && now.Before(*c.ReenrollUntil)
+}
+
+// WorkerCredentialModel Worker 凭证模型
+type WorkerCredentialModel struct {
+	*BaseModel[WorkerCredential]
+}
+
+// NewWorkerCredentialModel 创建 Worker 凭证模型
+func NewWorkerCredentialModel(db *mongo.Database) *WorkerCredentialModel {
+	coll := db.Collection("worker_credential")
+	m := &WorkerCredentialModel{
+		BaseModel: NewBaseModel[WorkerCredential](coll),
+	}
+
+	ctx := context.Background()
+	m.EnsureIndexes(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "worker_name", Value: 1}, {Key: "revoked", Value: 1}},
+		},
+	})
+
+	return m
+}
+
+// GenerateWorkerCredential 生成新的明文凭证及其哈希，哈希算法与 API Token 相同
+func GenerateWorkerCredential() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = WorkerCredentialPrefix + hex.EncodeToString(buf)
+	return token, HashApiToken(token), nil
+}
+
+// IsWorkerCredential 判断凭证是否为 Worker 凭证
+func IsWorkerCredential(token string) bool {
+	return strings.HasPrefix(token, WorkerCredentialPrefix)
+}
+
+// Create 创建凭证
+func (m *WorkerCredentialModel) Create(ctx context.Context, doc *WorkerCredential) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	doc.CreateTime = time.Now()
+	return m.Insert(ctx, doc)
+}
+
+// FindActiveByToken 根据明文凭证查找未吊销的记录，不存在返回 nil
+func (m *WorkerCredentialModel) FindActiveByToken(ctx context.Context, token string) (*WorkerCredential, error) {
+	doc, err := m.FindOne(ctx, bson.M{"token_hash": HashApiToken(token), "revoked": false})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+// FindAll 查询全部凭证，按创建时间倒序
+func (m *WorkerCredentialModel) FindAll(ctx context.Context) ([]WorkerCredential, error) {
+	return m.FindWithSort(ctx, bson.M{}, 0, 0, "create_time", -1)
+}
+
+// Revoke 吊销指定凭证，返回被吊销的记录，不存在或已吊销返回 nil
+func (m *WorkerCredentialModel) Revoke(ctx context.Context, id, reason string) (*WorkerCredential, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var doc WorkerCredential
+	err = m.Coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": oid, "revoked": false},
+		bson.M{"$set": bson.M{"revoked": true, "revoke_reason": reason, "revoke_time": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// FindActiveByWorkerName 查询指定 Worker 名称下未吊销的凭证
+func (m *WorkerCredentialModel) FindActiveByWorkerName(ctx context.Context, workerName string) ([]WorkerCredential, error) {
+	return m.Find(ctx, bson.M{"worker_name": workerName, "revoked": false}, 0, 0)
+}
+
+// ApproveReenroll 批准在 until 之前使用同名重新注册替换指定凭证，不存在或已吊销返回 nil
+func (m *WorkerCredentialModel) ApproveReenroll(ctx context.Context, id string, until time.Time) (*WorkerCredential, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var doc WorkerCredential
+	err = m.Coll.FindOneAndUpdate(ctx,
+		bson.M{"_id": oid, "revoked": false},
+		bson.M{"$set": bson.M{"reenroll_until": until}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// RenameWorker Worker 重命名时同步更新有效凭证的名称
+func (m *WorkerCredentialModel) RenameWorker(ctx context.Context, oldName, newName string) error {
+	_, err := m.Coll.UpdateMany(ctx,
+		bson.M{"worker_name": oldName, "revoked": false},
+		bson.M{"$set": bson.M{"worker_name": newName}},
+	)
+	return err
+}
+
+// Touch 记录最近使用时间和IP
+func (m *WorkerCredentialModel) Touch(ctx context.Context, id primitive.ObjectID, ip string) error {
+	_, err := m.Coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"last_used_time": time.Now(),
+		"last_used_ip":   ip,
+	}})
+	return err
+}
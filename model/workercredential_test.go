@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkerCredentialReenrollApproved(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	if (&WorkerCredential{}).ReenrollApproved(now) {
+		t.Error("credential without approval must not be replaceable")
+	}
+	if (&WorkerCredential{ReenrollUntil: &past}).ReenrollApproved(now) {
+		t.Error("expired approval must not allow re-enrollment")
+	}
+	if !(&WorkerCredential{ReenrollUntil: &future}).ReenrollApproved(now) {
+		t.Error("approval within window should allow re-enrollment")
+	}
+}
@@ -0,0 +1,133 @@
+// Package asciicast 终端会话录像，输出 asciicast v2 格式，可直接用 asciinema / asciinema-player 回放
+// 格式说明: https://docs.asciinema.org/manual/asciicast/v2/
+package asciicast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// 事件类型
+const (
+	EventOutput = "o" // 终端输出
+	EventInput  = "i" // 用户输入
+	EventResize = "r" // 窗口大小变化，数据格式为 "{cols}x{rows}"
+)
+
+// DefaultMaxSize 录像默认大小上限，超过后停止记录，避免超出 MongoDB 单文档限制
+const DefaultMaxSize = 8 * 1024 * 1024
+
+// Header asciicast v2 头部
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder 终端会话录像器，并发安全
+type Recorder struct {
+	mu        sync.Mutex
+	start     time.Time
+	buf       bytes.Buffer
+	maxSize   int
+	truncated bool
+	now       func() time.Time
+}
+
+// NewRecorder 创建录像器并写入头部，maxSize<=0 时使用 DefaultMaxSize
+func NewRecorder(width, height int, title string, maxSize int) *Recorder {
+	return newRecorder(width, height, title, maxSize, time.Now)
+}
+
+func newRecorder(width, height int, title string, maxSize int, now func() time.Time) *Recorder {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	r := &Recorder{
+		start:   now(),
+		maxSize: maxSize,
+		now:     now,
+	}
+	header, _ := json.Marshal(Header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.start.Unix(),
+		Title:     title,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	})
+	r.buf.Write(header)
+	r.buf.WriteByte('\n')
+	return r
+}
+
+// Output 记录终端输出
+func (r *Recorder) Output(data []byte) {
+	r.event(EventOutput, data)
+}
+
+// Input 记录用户输入
+func (r *Recorder) Input(data []byte) {
+	r.event(EventInput, data)
+}
+
+// Resize 记录窗口大小变化
+func (r *Recorder) Resize(cols, rows int) {
+	r.event(EventResize, []byte(fmt.Sprintf("%dx%d", cols, rows)))
+}
+
+func (r *Recorder) event(kind string, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.truncated {
+		return
+	}
+
+	elapsed := r.now().Sub(r.start).Seconds()
+	// 事件数据必须是合法的 JSON 字符串，非法 UTF-8 字节替换为 U+FFFD
+	text, _ := json.Marshal(strings.ToValidUTF8(string(data), string(utf8.RuneError)))
+	line := fmt.Sprintf("[%.6f, %q, %s]\n", elapsed, kind, text)
+
+	if r.buf.Len()+len(line) > r.maxSize {
+		r.truncated = true
+		return
+	}
+	r.buf.WriteString(line)
+}
+
+// String 返回完整录像内容
+func (r *Recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buf.String()
+}
+
+// Truncated 录像是否因超过大小上限被截断
+func (r *Recorder) Truncated() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.truncated
+}
+
+// StartTime 录像开始时间
+func (r *Recorder) StartTime() time.Time {
+	return r.start
+}
+
+// Duration 从开始录像到现在的时长
+func (r *Recorder) Duration() time.Duration {
+	return r.now().Sub(r.start)
+}
@@ -0,0 +1,90 @@
+package asciicast
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fakeClock(start time.Time) (func() time.Time, func(time.Duration)) {
+	now := start
+	return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestRecorderFormat(t *testing.T) {
+	clock, advance := fakeClock(time.Unix(1700000000, 0))
+	r := newRecorder(120, 40, "worker-1", 0, clock)
+
+	advance(500 * time.Millisecond)
+	r.Input([]byte("ls\r"))
+	advance(250 * time.Millisecond)
+	r.Output([]byte("a.txt\r\n\x1b[0m"))
+	r.Resize(100, 30)
+	r.Output(nil)
+
+	lines := strings.Split(strings.TrimSuffix(r.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header + 3 events, got %d lines: %q", len(lines), lines)
+	}
+
+	var header Header
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("invalid header: %v", err)
+	}
+	if header.Version != 2 || header.Width != 120 || header.Height != 40 || header.Timestamp != 1700000000 || header.Title != "worker-1" {
+		t.Errorf("unexpected header: %+v", header)
+	}
+
+	expected := []struct {
+		time float64
+		kind string
+		data string
+	}{
+		{0.5, EventInput, "ls\r"},
+		{0.75, EventOutput, "a.txt\r\n\x1b[0m"},
+		{0.75, EventResize, "100x30"},
+	}
+	for i, want := range expected {
+		var ev []interface{}
+		if err := json.Unmarshal([]byte(lines[i+1]), &ev); err != nil {
+			t.Fatalf("invalid event %d: %v", i, err)
+		}
+		if len(ev) != 3 || ev[0].(float64) != want.time || ev[1].(string) != want.kind || ev[2].(string) != want.data {
+			t.Errorf("event %d = %v, want %v", i, ev, want)
+		}
+	}
+}
+
+func TestRecorderInvalidUTF8(t *testing.T) {
+	r := NewRecorder(80, 24, "", 0)
+	r.Output([]byte{'o', 'k', 0xff})
+
+	lines := strings.Split(strings.TrimSuffix(r.String(), "\n"), "\n")
+	var ev []interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &ev); err != nil {
+		t.Fatalf("invalid event: %v", err)
+	}
+	if ev[2].(string) != "ok�" {
+		t.Errorf("unexpected data %q", ev[2])
+	}
+}
+
+func TestRecorderTruncate(t *testing.T) {
+	r := NewRecorder(80, 24, "", 200)
+	headerLen := len(r.String())
+
+	r.Output([]byte(strings.Repeat("x", 300)))
+	if !r.Truncated() {
+		t.Fatal("expected recorder to be truncated")
+	}
+	if len(r.String()) != headerLen {
+		t.Errorf("oversized event should not be written")
+	}
+
+	// 截断后不再记录任何事件，保证回放时间线连续
+	r.Output([]byte("y"))
+	if len(r.String()) != headerLen {
+		t.Errorf("events after truncation should be dropped")
+	}
+}
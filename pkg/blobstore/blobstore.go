@@ -0,0 +1,64 @@
+// Package blobstore 截图等二进制文件存储，不写入数据库，支持本地目录和 S3 兼容对象存储
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 存储类型
+const (
+	TypeLocal = "local"
+	TypeS3    = "s3"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("blobstore: object not found")
+
+// Store 按键读写对象，键为斜杠分隔的相对路径，如 "screenshot/ab12.png"
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config 存储配置
+type Config struct {
+	Type string // local（默认）或 s3
+	Dir  string // 本地存储根目录
+
+	// S3 兼容存储（AWS S3、MinIO、OSS、COS 等）
+	Endpoint     string
+	Region       string
+	Bucket       string
+	AccessKey    string
+	SecretKey    string
+	UsePathStyle bool
+}
+
+// New 按配置创建存储
+func New(cfg Config) (Store, error) {
+	switch cfg.Type {
+	case "", TypeLocal:
+		return NewLocalStore(cfg.Dir)
+	case TypeS3:
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("blobstore: unknown store type %q", cfg.Type)
+	}
+}
+
+// ValidKey 判断键是否为规范的相对路径，不能跳出存储根目录
+func ValidKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 路径风格的最小 S3 模拟，支持 PUT、GET 和 DELETE
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func testStore(t *testing.T, s Store) {
+	t.Helper()
+	ctx := context.Background()
+	data := []byte("\x89PNG fake image")
+
+	if err := s.Put(ctx, "screenshot/ab/cd.png", data); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	got, err := s.Get(ctx, "screenshot/ab/cd.png")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("Get() = %q, want %q", got, data)
+	}
+	if err := s.Delete(ctx, "screenshot/ab/cd.png"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get(ctx, "screenshot/ab/cd.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "screenshot/ab/cd.png"); err != nil {
+		t.Errorf("Delete() missing key error = %v", err)
+	}
+	for _, key := range []string{"", "/abs.png", "../escape.png", "a/../../b.png", "dir/"} {
+		if err := s.Put(ctx, key, data); err == nil {
+			t.Errorf("Put(%q) should fail", key)
+		}
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	s, err := New(Config{Type: TypeLocal, Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte), types: make(map[string]string)}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s, err := New(Config{
+		Type:         TypeS3,
+		Endpoint:     srv.URL,
+		Bucket:       "cscan",
+		AccessKey:    "test",
+		SecretKey:    "test",
+		UsePathStyle: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+
+	if err := s.Put(context.Background(), "a.png", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if ct := fake.types["cscan/a.png"]; ct != "image/png" {
+		t.Errorf("content type = %q, want image/png", ct)
+	}
+}
@@ -0,0 +1,230 @@
+// Package cpe 提供 CPE 2.3 格式化字符串的解析、生成以及版本范围比较
+package cpe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 通配值：ANY 表示任意值，NA 表示不适用
+const (
+	Any = "*"
+	NA  = "-"
+)
+
+// 组件类型
+const (
+	PartApplication = "a"
+	PartOS          = "o"
+	PartHardware    = "h"
+)
+
+const prefix = "cpe:2.3:"
+
+// CPE CPE 2.3 名称，各字段保存未转义的值
+type CPE struct {
+	Part      string
+	Vendor    string
+	Product   string
+	Version   string
+	Update    string
+	Edition   string
+	Language  string
+	SwEdition string
+	TargetSw  string
+	TargetHw  string
+	Other     string
+}
+
+// Parse 解析 CPE 2.3 格式化字符串，缺省的尾部字段视为 *
+func Parse(s string) (*CPE, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(strings.ToLower(s), prefix) {
+		return nil, fmt.Errorf("invalid cpe 2.3 string: %s", s)
+	}
+	fields := splitFields(s[len(prefix):])
+	if len(fields) == 0 || len(fields) > 11 {
+		return nil, fmt.Errorf("invalid cpe 2.3 string: %s", s)
+	}
+	for len(fields) < 11 {
+		fields = append(fields, Any)
+	}
+	c := &CPE{
+		Part:      fields[0],
+		Vendor:    fields[1],
+		Product:   fields[2],
+		Version:   fields[3],
+		Update:    fields[4],
+		Edition:   fields[5],
+		Language:  fields[6],
+		SwEdition: fields[7],
+		TargetSw:  fields[8],
+		TargetHw:  fields[9],
+		Other:     fields[10],
+	}
+	if c.Part != PartApplication && c.Part != PartOS && c.Part != PartHardware && c.Part != Any {
+		return nil, fmt.Errorf("invalid cpe part: %s", c.Part)
+	}
+	if c.Product == "" || c.Product == Any {
+		return nil, fmt.Errorf("cpe product is empty: %s", s)
+	}
+	return c, nil
+}
+
+// New 根据厂商、产品和版本构造应用类 CPE，厂商未知时使用 *
+func New(vendor, product, version string) *CPE {
+	vendor = Normalize(vendor)
+	if vendor == "" {
+		vendor = Any
+	}
+	version = NormalizeVersion(version)
+	if version == "" {
+		version = Any
+	}
+	return &CPE{
+		Part:      PartApplication,
+		Vendor:    vendor,
+		Product:   Normalize(product),
+		Version:   version,
+		Update:    Any,
+		Edition:   Any,
+		Language:  Any,
+		SwEdition: Any,
+		TargetSw:  Any,
+		TargetHw:  Any,
+		Other:     Any,
+	}
+}
+
+// String 生成 CPE 2.3 格式化字符串
+func (c *CPE) String() string {
+	fields := []string{c.Part, c.Vendor, c.Product, c.Version, c.Update, c.Edition,
+		c.Language, c.SwEdition, c.TargetSw, c.TargetHw, c.Other}
+	var b strings.Builder
+	b.WriteString(prefix)
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(':')
+		}
+		if f == "" {
+			f = Any
+		}
+		b.WriteString(escape(f))
+	}
+	return b.String()
+}
+
+// WithVersion 返回替换版本后的副本
+func (c *CPE) WithVersion(version string) *CPE {
+	cp := *c
+	cp.Version = NormalizeVersion(version)
+	if cp.Version == "" {
+		cp.Version = Any
+	}
+	return &cp
+}
+
+// HasVersion 是否带有具体版本
+func (c *CPE) HasVersion() bool {
+	return c.Version != "" && c.Version != Any && c.Version != NA
+}
+
+// FullVersion 版本与更新字段合并后的版本，如 7.4 + p1 => 7.4p1，用于与指纹提取的版本比较
+func (c *CPE) FullVersion() string {
+	if !c.HasVersion() {
+		return c.Version
+	}
+	if c.Update == "" || c.Update == Any || c.Update == NA {
+		return c.Version
+	}
+	return c.Version + c.Update
+}
+
+// SameProduct 厂商和产品是否一致，任一方厂商为 * 时只比较产品
+func (c *CPE) SameProduct(o *CPE) bool {
+	if c.Product != o.Product {
+		return false
+	}
+	if c.Vendor == Any || o.Vendor == Any || c.Vendor == "" || o.Vendor == "" {
+		return true
+	}
+	return c.Vendor == o.Vendor
+}
+
+// Normalize 将名称转换为 CPE 字段的规范形式：小写，空白替换为下划线
+func Normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return ""
+	}
+	var b strings.Builder
+	lastUnderscore := false
+	for _, r := range s {
+		switch {
+		case r == ' ' || r == '\t' || r == '/':
+			if !lastUnderscore {
+				b.WriteByte('_')
+				lastUnderscore = true
+			}
+			continue
+		case r == '\\' || r == ':' || r == '*' || r == '?':
+			continue
+		}
+		b.WriteRune(r)
+		lastUnderscore = r == '_'
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+// NormalizeVersion 清理指纹提取到的版本，去掉前缀 v 和两端空白
+func NormalizeVersion(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) > 1 && (v[0] == 'v' || v[0] == 'V') && v[1] >= '0' && v[1] <= '9' {
+		v = v[1:]
+	}
+	return strings.TrimRight(v, ".-_ ")
+}
+
+// splitFields 按未转义的冒号切分并去除转义
+func splitFields(s string) []string {
+	var fields []string
+	var cur strings.Builder
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if escaped {
+			cur.WriteByte(ch)
+			escaped = false
+			continue
+		}
+		switch ch {
+		case '\\':
+			escaped = true
+		case ':':
+			fields = append(fields, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(ch)
+		}
+	}
+	return append(fields, cur.String())
+}
+
+// escape 转义除字母数字、下划线、连字符和点以外的字符，通配值保持原样
+func escape(s string) string {
+	if s == Any || s == NA {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') ||
+			ch == '_' || ch == '-' || ch == '.' || ch >= 0x80 {
+			b.WriteByte(ch)
+			continue
+		}
+		b.WriteByte('\\')
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
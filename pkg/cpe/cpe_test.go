@@ -0,0 +1,115 @@
+package cpe
+
+import "testing"
+
+func TestParseAndString(t *testing.T) {
+	c, err := Parse("cpe:2.3:a:f5:nginx:1.18.0:*:*:*:*:*:*:*")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if c.Part != PartApplication || c.Vendor != "f5" || c.Product != "nginx" || c.Version != "1.18.0" {
+		t.Fatalf("unexpected cpe: %+v", c)
+	}
+	if got := c.String(); got != "cpe:2.3:a:f5:nginx:1.18.0:*:*:*:*:*:*:*" {
+		t.Fatalf("round trip = %s", got)
+	}
+
+	// 转义的冒号不作为分隔符
+	c, err = Parse(`cpe:2.3:a:vendor:prod\:uct:1.0`)
+	if err != nil {
+		t.Fatalf("parse escaped: %v", err)
+	}
+	if c.Product != "prod:uct" || c.Update != Any {
+		t.Fatalf("unexpected escaped cpe: %+v", c)
+	}
+	if got := c.String(); got != `cpe:2.3:a:vendor:prod\:uct:1.0:*:*:*:*:*:*:*` {
+		t.Fatalf("escaped round trip = %s", got)
+	}
+
+	for _, bad := range []string{"", "cpe:/a:nginx:nginx", "cpe:2.3:x:a:b", "cpe:2.3:a:vendor:*"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+func TestNewAndNormalize(t *testing.T) {
+	c := New("", "Apache HTTP Server", "v2.4.49")
+	if got := c.String(); got != "cpe:2.3:a:*:apache_http_server:2.4.49:*:*:*:*:*:*:*" {
+		t.Fatalf("New = %s", got)
+	}
+	if !c.HasVersion() {
+		t.Fatal("expected version")
+	}
+	if New("", "nginx", "").HasVersion() {
+		t.Fatal("empty version should be *")
+	}
+
+	base, _ := Parse("cpe:2.3:a:apache:http_server:*:*:*:*:*:*:*:*")
+	if got := base.WithVersion("2.4.50").String(); got != "cpe:2.3:a:apache:http_server:2.4.50:*:*:*:*:*:*:*" {
+		t.Fatalf("WithVersion = %s", got)
+	}
+	if base.HasVersion() {
+		t.Fatal("base should not be modified")
+	}
+
+	ssh, _ := Parse("cpe:2.3:a:openbsd:openssh:7.4:p1:*:*:*:*:*:*")
+	if ssh.FullVersion() != "7.4p1" {
+		t.Fatalf("FullVersion = %s", ssh.FullVersion())
+	}
+	if !New("", "OpenSSH", "7.4p1").SameProduct(ssh) {
+		t.Fatal("wildcard vendor should match")
+	}
+	other, _ := Parse("cpe:2.3:a:other:openssh:*")
+	if ssh.SameProduct(other) {
+		t.Fatal("different vendor should not match")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.18.0", "1.18.0", 0},
+		{"1.18", "1.18.0", 0},
+		{"1.9.1", "1.18.0", -1},
+		{"2.4.49", "2.4.5", 1},
+		{"1.0.0-rc1", "1.0.0", -1},
+		{"1.0.0rc2", "1.0.0rc10", -1},
+		{"1.0.1", "1.0rc1", 1},
+		{"7.4p1", "7.4p2", -1},
+		{"v5.7.30", "5.7.31", -1},
+	}
+	for _, c := range cases {
+		if got := CompareVersions(c.a, c.b); got != c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+		if got := CompareVersions(c.b, c.a); got != -c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", c.b, c.a, got, -c.want)
+		}
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	r := Range{StartIncluding: "2.4.0", EndExcluding: "2.4.51"}
+	for v, want := range map[string]bool{"2.4.49": true, "2.4.0": true, "2.4.51": false, "2.3.9": false, "": false, "*": false} {
+		if got := r.Contains(v); got != want {
+			t.Errorf("Contains(%q) = %v, want %v", v, got, want)
+		}
+	}
+
+	r = Range{StartExcluding: "1.0", EndIncluding: "1.2"}
+	if r.Contains("1.0") || !r.Contains("1.2") || !r.Contains("1.1.5") {
+		t.Error("exclusive start / inclusive end mismatch")
+	}
+
+	r = Range{Versions: []string{"1.20.0", "1.20.1"}}
+	if !r.Contains("1.20.1") || r.Contains("1.20.2") {
+		t.Error("version list mismatch")
+	}
+
+	if !(Range{}).IsZero() || !(Range{}).Contains("1.0") {
+		t.Error("empty range should contain all versions")
+	}
+}
@@ -0,0 +1,142 @@
+package cpe
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions 比较两个版本号，a<b 返回 -1，相等返回 0，a>b 返回 1
+// 版本按数字段和字母段切分逐段比较，数字段按数值比较；
+// 一方先结束时，另一方剩余部分全为 0 视为相等，以字母开头（rc/beta 等预发布）视为更小
+func CompareVersions(a, b string) int {
+	ta := tokenize(a)
+	tb := tokenize(b)
+	for i := 0; i < len(ta) && i < len(tb); i++ {
+		if c := compareToken(ta[i], tb[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(ta) > len(tb):
+		return compareRest(ta[len(tb):])
+	case len(ta) < len(tb):
+		return -compareRest(tb[len(ta):])
+	}
+	return 0
+}
+
+type versionToken struct {
+	num     int64
+	str     string
+	numeric bool
+}
+
+func tokenize(v string) []versionToken {
+	v = strings.ToLower(NormalizeVersion(v))
+	var tokens []versionToken
+	i := 0
+	for i < len(v) {
+		ch := v[i]
+		switch {
+		case ch >= '0' && ch <= '9':
+			j := i
+			for j < len(v) && v[j] >= '0' && v[j] <= '9' {
+				j++
+			}
+			n, err := strconv.ParseInt(strings.TrimLeft(v[i:j], "0"), 10, 64)
+			if err != nil {
+				n = 0
+			}
+			tokens = append(tokens, versionToken{num: n, numeric: true})
+			i = j
+		case ch >= 'a' && ch <= 'z':
+			j := i
+			for j < len(v) && v[j] >= 'a' && v[j] <= 'z' {
+				j++
+			}
+			tokens = append(tokens, versionToken{str: v[i:j]})
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+func compareToken(a, b versionToken) int {
+	switch {
+	case a.numeric && b.numeric:
+		if a.num < b.num {
+			return -1
+		}
+		if a.num > b.num {
+			return 1
+		}
+		return 0
+	case a.numeric:
+		// 数字段大于字母段：1.0.1 > 1.0rc1
+		return 1
+	case b.numeric:
+		return -1
+	}
+	return strings.Compare(a.str, b.str)
+}
+
+// compareRest 较长版本多出的部分与"空"比较
+func compareRest(rest []versionToken) int {
+	if !rest[0].numeric {
+		return -1
+	}
+	for _, t := range rest {
+		if !t.numeric || t.num != 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Range 受影响的版本范围，边界为空表示不限；Versions 为逐个列出的受影响版本
+type Range struct {
+	StartIncluding string   `json:"startIncluding,omitempty"`
+	StartExcluding string   `json:"startExcluding,omitempty"`
+	EndIncluding   string   `json:"endIncluding,omitempty"`
+	EndExcluding   string   `json:"endExcluding,omitempty"`
+	Versions       []string `json:"versions,omitempty"`
+}
+
+// IsZero 是否不含任何约束（即全部版本受影响）
+func (r Range) IsZero() bool {
+	return r.StartIncluding == "" && r.StartExcluding == "" &&
+		r.EndIncluding == "" && r.EndExcluding == "" && len(r.Versions) == 0
+}
+
+// Contains 判断版本是否落在范围内
+func (r Range) Contains(version string) bool {
+	if version == "" || version == Any || version == NA {
+		return false
+	}
+	if len(r.Versions) > 0 {
+		for _, v := range r.Versions {
+			if CompareVersions(v, version) == 0 {
+				return true
+			}
+		}
+		// 只列出版本、没有区间边界时不再继续比较
+		if r.StartIncluding == "" && r.StartExcluding == "" && r.EndIncluding == "" && r.EndExcluding == "" {
+			return false
+		}
+	}
+	if r.StartIncluding != "" && CompareVersions(version, r.StartIncluding) < 0 {
+		return false
+	}
+	if r.StartExcluding != "" && CompareVersions(version, r.StartExcluding) <= 0 {
+		return false
+	}
+	if r.EndIncluding != "" && CompareVersions(version, r.EndIncluding) > 0 {
+		return false
+	}
+	if r.EndExcluding != "" && CompareVersions(version, r.EndExcluding) >= 0 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,189 @@
+// Package geoip IP 归属地查询，基于本地 MaxMind DB（mmdb）和 ip2region（xdb）数据文件解析国家、地区、城市、ASN 和组织
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 数据集类型
+const (
+	TypeMMDB      = "mmdb"
+	TypeIP2Region = "ip2region"
+)
+
+// Record IP 的地理位置和网络归属
+type Record struct {
+	Country string `json:"country,omitempty"`
+	Region  string `json:"region,omitempty"`
+	City    string `json:"city,omitempty"`
+	ASN     int    `json:"asn,omitempty"`
+	Org     string `json:"org,omitempty"`
+}
+
+// IsEmpty 判断是否所有字段都为空
+func (r *Record) IsEmpty() bool {
+	return r == nil || (r.Country == "" && r.Region == "" && r.City == "" && r.ASN == 0 && r.Org == "")
+}
+
+// Location 格式化为 "国家 地区 城市"，跳过空值和重复部分
+func (r *Record) Location() string {
+	if r == nil {
+		return ""
+	}
+	var parts []string
+	for _, p := range []string{r.Country, r.Region, r.City} {
+		if p != "" && (len(parts) == 0 || parts[len(parts)-1] != p) {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// merge 用 other 补全空字段
+func (r *Record) merge(other *Record) {
+	if other == nil {
+		return
+	}
+	if r.Country == "" {
+		r.Country = other.Country
+	}
+	if r.Region == "" {
+		r.Region = other.Region
+	}
+	if r.City == "" {
+		r.City = other.City
+	}
+	if r.ASN == 0 {
+		r.ASN = other.ASN
+	}
+	if r.Org == "" {
+		r.Org = other.Org
+	}
+}
+
+// Reader 单个数据集的查询，返回 nil 表示数据集未覆盖该 IP
+type Reader interface {
+	Record(ip net.IP) (*Record, error)
+}
+
+// DetectType 根据文件名判断数据集类型，无法识别时返回空
+func DetectType(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".mmdb":
+		return TypeMMDB
+	case ".xdb":
+		return TypeIP2Region
+	}
+	return ""
+}
+
+// NewReader 按类型解析内存中的数据集
+func NewReader(typ string, buf []byte) (Reader, error) {
+	switch typ {
+	case TypeMMDB:
+		return NewMMDBReader(buf)
+	case TypeIP2Region:
+		return NewXDBReader(buf)
+	}
+	return nil, fmt.Errorf("unsupported geoip dataset type %q", typ)
+}
+
+// Open 加载数据集文件，类型由扩展名判断
+func Open(path string) (Reader, error) {
+	typ := DetectType(path)
+	if typ == "" {
+		return nil, fmt.Errorf("unsupported geoip dataset file %s", path)
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewReader(typ, buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return r, nil
+}
+
+// Resolver 查询多个数据集并合并结果，每个字段以靠前的数据集为准
+type Resolver struct {
+	readers []Reader
+}
+
+// NewResolver 创建多数据集查询器
+func NewResolver(readers ...Reader) *Resolver {
+	return &Resolver{readers: readers}
+}
+
+// OpenDir 按文件名顺序加载目录下所有 .mmdb 和 .xdb 文件
+// 解析失败的文件记录在返回的错误中，不影响其余文件加载
+func OpenDir(dir string) (*Resolver, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && DetectType(e.Name()) != "" {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	res := &Resolver{}
+	var errs []string
+	for _, name := range names {
+		r, err := Open(filepath.Join(dir, name))
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		res.readers = append(res.readers, r)
+	}
+	if len(errs) > 0 {
+		return res, fmt.Errorf("load geoip datasets: %s", strings.Join(errs, "; "))
+	}
+	return res, nil
+}
+
+// Len 已加载的数据集数量
+func (r *Resolver) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.readers)
+}
+
+// Lookup 查询 IP 归属，所有数据集都未覆盖时返回 nil
+func (r *Resolver) Lookup(ip string) *Record {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return nil
+	}
+	rec, _ := r.Record(parsed)
+	return rec
+}
+
+// Record 实现 Reader，查询器可以嵌套使用
+func (r *Resolver) Record(ip net.IP) (*Record, error) {
+	if r == nil || len(r.readers) == 0 {
+		return nil, nil
+	}
+	rec := &Record{}
+	for _, reader := range r.readers {
+		found, err := reader.Record(ip)
+		if err != nil {
+			continue
+		}
+		rec.merge(found)
+	}
+	if rec.IsEmpty() {
+		return nil, nil
+	}
+	return rec, nil
+}
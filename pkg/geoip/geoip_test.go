@@ -0,0 +1,281 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// mmdbValue 按 MaxMind DB 数据格式编码值（仅测试用到的类型）
+func mmdbValue(v interface{}) []byte {
+	var buf bytes.Buffer
+	switch x := v.(type) {
+	case string:
+		if len(x) < 29 {
+			buf.WriteByte(byte(mmdbString<<5 | len(x)))
+		} else {
+			buf.WriteByte(mmdbString<<5 | 29)
+			buf.WriteByte(byte(len(x) - 29))
+		}
+		buf.WriteString(x)
+	case uint32:
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, x)
+		b = bytes.TrimLeft(b, "\x00")
+		buf.WriteByte(byte(mmdbUint32<<5 | len(b)))
+		buf.Write(b)
+	case []interface{}:
+		buf.WriteByte(byte(len(x)))
+		buf.WriteByte(mmdbArray - 7)
+		for _, item := range x {
+			buf.Write(mmdbValue(item))
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte(byte(mmdbMap<<5 | len(x)))
+		for _, k := range keys {
+			buf.Write(mmdbValue(k))
+			buf.Write(mmdbValue(x[k]))
+		}
+	}
+	return buf.Bytes()
+}
+
+type mmdbNetwork struct {
+	cidr string
+	data map[string]interface{}
+}
+
+// buildMMDB 构建 24 位记录的 IPv6 树，IPv4 网段位于 ::/96 之下
+func buildMMDB(t *testing.T, networks []mmdbNetwork) []byte {
+	t.Helper()
+	type node struct{ left, right int }
+	nodes := []*node{{-1, -1}}
+	const empty = -1
+
+	var data bytes.Buffer
+	leaves := map[[2]int]int{} // (node, bit) -> data offset
+	for _, n := range networks {
+		_, ipnet, err := net.ParseCIDR(n.cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ones, _ := ipnet.Mask.Size()
+		ip := ipnet.IP.To16()
+		if v4 := ipnet.IP.To4(); v4 != nil {
+			ip = append(make(net.IP, 12), v4...)
+			ones += 96
+		}
+		cur := 0
+		for i := 0; i < ones-1; i++ {
+			bit := (ip[i>>3] >> (7 - uint(i&7))) & 1
+			next := &nodes[cur].left
+			if bit == 1 {
+				next = &nodes[cur].right
+			}
+			if *next == empty {
+				nodes = append(nodes, &node{empty, empty})
+				*next = len(nodes) - 1
+			}
+			cur = *next
+		}
+		last := ones - 1
+		bit := int((ip[last>>3] >> (7 - uint(last&7))) & 1)
+		leaves[[2]int{cur, bit}] = data.Len()
+		data.Write(mmdbValue(n.data))
+	}
+
+	nodeCount := len(nodes)
+	record := func(idx, bit, child int) int {
+		if off, ok := leaves[[2]int{idx, bit}]; ok {
+			return nodeCount + 16 + off
+		}
+		if child == empty {
+			return nodeCount
+		}
+		return child
+	}
+	var out bytes.Buffer
+	for i, n := range nodes {
+		l, r := record(i, 0, n.left), record(i, 1, n.right)
+		out.Write([]byte{byte(l >> 16), byte(l >> 8), byte(l), byte(r >> 16), byte(r >> 8), byte(r)})
+	}
+	out.Write(make([]byte, 16))
+	out.Write(data.Bytes())
+	out.Write(mmdbMetadataMarker)
+	out.Write(mmdbValue(map[string]interface{}{
+		"node_count":    uint32(nodeCount),
+		"record_size":   uint32(24),
+		"ip_version":    uint32(6),
+		"database_type": "Test-City",
+	}))
+	return out.Bytes()
+}
+
+// buildXDB 按 "起始,结束,区域" 范围构建 ip2region xdb 文件
+func buildXDB(t *testing.T, ranges [][3]string) []byte {
+	t.Helper()
+	buf := make([]byte, xdbHeaderSize+xdbVectorIndexLen)
+	type seg struct {
+		start, end uint32
+		ptr        int
+		length     int
+	}
+	var segs []seg
+	for _, r := range ranges {
+		ptr := len(buf)
+		buf = append(buf, r[2]...)
+		segs = append(segs, seg{
+			start:  binary.BigEndian.Uint32(net.ParseIP(r[0]).To4()),
+			end:    binary.BigEndian.Uint32(net.ParseIP(r[1]).To4()),
+			ptr:    ptr,
+			length: len(r[2]),
+		})
+	}
+	segStart := len(buf)
+	for _, s := range segs {
+		e := make([]byte, xdbSegmentSize)
+		binary.LittleEndian.PutUint32(e[0:], s.start)
+		binary.LittleEndian.PutUint32(e[4:], s.end)
+		binary.LittleEndian.PutUint16(e[8:], uint16(s.length))
+		binary.LittleEndian.PutUint32(e[10:], uint32(s.ptr))
+		buf = append(buf, e...)
+	}
+	// 每个向量索引都覆盖全部分段，依赖二分查找定位
+	segEnd := len(buf) - xdbSegmentSize
+	for i := 0; i < xdbVectorIndexCols*xdbVectorIndexCols; i++ {
+		off := xdbHeaderSize + i*xdbVectorIndexSize
+		binary.LittleEndian.PutUint32(buf[off:], uint32(segStart))
+		binary.LittleEndian.PutUint32(buf[off+4:], uint32(segEnd))
+	}
+	return buf
+}
+
+func TestMMDBLookup(t *testing.T) {
+	buf := buildMMDB(t, []mmdbNetwork{
+		{cidr: "1.2.3.0/24", data: map[string]interface{}{
+			"country":      map[string]interface{}{"names": map[string]interface{}{"en": "Australia", "zh-CN": "澳大利亚"}},
+			"subdivisions": []interface{}{map[string]interface{}{"names": map[string]interface{}{"en": "Queensland"}}},
+			"city":         map[string]interface{}{"names": map[string]interface{}{"en": "Brisbane"}},
+		}},
+		{cidr: "8.8.8.0/24", data: map[string]interface{}{
+			"autonomous_system_number":       uint32(15169),
+			"autonomous_system_organization": "GOOGLE",
+		}},
+		{cidr: "2001:db8::/32", data: map[string]interface{}{
+			"country": map[string]interface{}{"names": map[string]interface{}{"en": "Example"}},
+		}},
+	})
+
+	r, err := NewMMDBReader(buf)
+	if err != nil {
+		t.Fatalf("NewMMDBReader() error: %v", err)
+	}
+	if r.DatabaseType() != "Test-City" {
+		t.Errorf("DatabaseType() = %q", r.DatabaseType())
+	}
+
+	tests := []struct {
+		ip   string
+		want *Record
+	}{
+		{"1.2.3.4", &Record{Country: "澳大利亚", Region: "Queensland", City: "Brisbane"}},
+		{"8.8.8.8", &Record{ASN: 15169, Org: "GOOGLE"}},
+		{"2001:db8::1", &Record{Country: "Example"}},
+		{"1.2.4.1", nil},
+		{"2001:db9::1", nil},
+	}
+	for _, tt := range tests {
+		got, err := r.Record(net.ParseIP(tt.ip))
+		if err != nil {
+			t.Errorf("Record(%s) error: %v", tt.ip, err)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("Record(%s) = %+v, want %+v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestXDBLookup(t *testing.T) {
+	r, err := NewXDBReader(buildXDB(t, [][3]string{
+		{"0.0.0.0", "1.0.0.255", "0|0|0|内网IP|内网IP"},
+		{"1.0.1.0", "1.0.3.255", "中国|0|福建省|福州市|电信"},
+		{"1.0.4.0", "1.0.7.255", "澳大利亚|维多利亚|墨尔本|0"},
+	}))
+	if err != nil {
+		t.Fatalf("NewXDBReader() error: %v", err)
+	}
+
+	got, err := r.Record(net.ParseIP("1.0.2.1"))
+	if err != nil || got == nil || *got != (Record{Country: "中国", Region: "福建省", City: "福州市", Org: "电信"}) {
+		t.Errorf("Record(1.0.2.1) = %+v, %v", got, err)
+	}
+	got, _ = r.Record(net.ParseIP("1.0.5.1"))
+	if got == nil || *got != (Record{Country: "澳大利亚", Region: "维多利亚", City: "墨尔本"}) {
+		t.Errorf("Record(1.0.5.1) = %+v", got)
+	}
+	if got, _ := r.Record(net.ParseIP("9.9.9.9")); got != nil {
+		t.Errorf("Record(9.9.9.9) = %+v, want nil", got)
+	}
+	if got, err := r.Record(net.ParseIP("2001:db8::1")); got != nil || err != nil {
+		t.Errorf("Record(ipv6) = %+v, %v", got, err)
+	}
+}
+
+func TestResolverMerge(t *testing.T) {
+	dir := t.TempDir()
+	city := buildMMDB(t, []mmdbNetwork{{cidr: "1.0.1.0/24", data: map[string]interface{}{
+		"country": map[string]interface{}{"names": map[string]interface{}{"en": "China"}},
+	}}})
+	asn := buildMMDB(t, []mmdbNetwork{{cidr: "1.0.0.0/16", data: map[string]interface{}{
+		"autonomous_system_number":       uint32(4134),
+		"autonomous_system_organization": "CHINANET",
+	}}})
+	region := buildXDB(t, [][3]string{{"1.0.1.0", "1.0.1.255", "中国|0|福建省|福州市|电信"}})
+	for name, b := range map[string][]byte{"a-city.mmdb": city, "b-asn.mmdb": asn, "c-region.xdb": region, "readme.txt": []byte("x")} {
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := OpenDir(dir)
+	if err != nil {
+		t.Fatalf("OpenDir() error: %v", err)
+	}
+	if res.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", res.Len())
+	}
+	got := res.Lookup("1.0.1.10")
+	want := Record{Country: "China", Region: "福建省", City: "福州市", ASN: 4134, Org: "CHINANET"}
+	if got == nil || *got != want {
+		t.Fatalf("Lookup() = %+v, want %+v", got, want)
+	}
+	if loc := got.Location(); loc != "China 福建省 福州市" {
+		t.Errorf("Location() = %q", loc)
+	}
+	if res.Lookup("10.0.0.1") != nil || res.Lookup("not-an-ip") != nil {
+		t.Error("uncovered ip should resolve to nil")
+	}
+}
+
+func TestMMDBDecodePointer(t *testing.T) {
+	// offset 0: "x"; offset 2: map{pointer->0: uint16(5)}
+	d := &mmdbDecoder{buf: []byte{0x41, 'x', 0xE1, 0x20, 0x00, 0xA1, 0x05}}
+	v, next, err := d.decode(2, 0)
+	if err != nil {
+		t.Fatalf("decode() error: %v", err)
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok || toUint64(m["x"]) != 5 || next != 7 {
+		t.Errorf("decode() = %v, next %d", v, next)
+	}
+}
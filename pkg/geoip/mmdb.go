@@ -0,0 +1,394 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+)
+
+// mmdbMetadataMarker MaxMind DB 文件元数据段的起始标记
+var mmdbMetadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// mmdb 数据段字段类型
+const (
+	mmdbExtended = iota
+	mmdbPointer
+	mmdbString
+	mmdbDouble
+	mmdbBytes
+	mmdbUint16
+	mmdbUint32
+	mmdbMap
+	mmdbInt32
+	mmdbUint64
+	mmdbUint128
+	mmdbArray
+	mmdbContainer
+	mmdbEndMarker
+	mmdbBool
+	mmdbFloat
+)
+
+// maxDecodeDepth 最大解码深度，防止畸形文件中的循环指针
+const maxDecodeDepth = 32
+
+// MMDBReader MaxMind DB 格式文件读取（GeoLite2/GeoIP2 City、Country、ASN 及兼容数据库）
+type MMDBReader struct {
+	buf          []byte
+	data         []byte
+	nodeCount    uint
+	recordSize   uint
+	nodeBytes    uint
+	ipVersion    uint
+	ipv4Start    uint
+	databaseType string
+}
+
+// NewMMDBReader 解析内存中的 MaxMind DB 文件
+func NewMMDBReader(buf []byte) (*MMDBReader, error) {
+	searchFrom := 0
+	if len(buf) > 128*1024 {
+		searchFrom = len(buf) - 128*1024
+	}
+	idx := bytes.LastIndex(buf[searchFrom:], mmdbMetadataMarker)
+	if idx < 0 {
+		return nil, errors.New("invalid mmdb file: metadata not found")
+	}
+	metaStart := searchFrom + idx + len(mmdbMetadataMarker)
+
+	d := &mmdbDecoder{buf: buf[metaStart:]}
+	v, _, err := d.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mmdb metadata: %w", err)
+	}
+	meta, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid mmdb metadata: not a map")
+	}
+
+	r := &MMDBReader{buf: buf}
+	r.nodeCount = uint(toUint64(meta["node_count"]))
+	r.recordSize = uint(toUint64(meta["record_size"]))
+	r.ipVersion = uint(toUint64(meta["ip_version"]))
+	r.databaseType, _ = meta["database_type"].(string)
+
+	switch r.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("unsupported mmdb record size %d", r.recordSize)
+	}
+	if r.ipVersion != 4 && r.ipVersion != 6 {
+		return nil, fmt.Errorf("unsupported mmdb ip version %d", r.ipVersion)
+	}
+	r.nodeBytes = r.recordSize / 4
+	treeSize := r.nodeCount * r.nodeBytes
+	if treeSize+16 > uint(searchFrom+idx) {
+		return nil, errors.New("invalid mmdb file: search tree exceeds file size")
+	}
+	r.data = buf[treeSize+16 : searchFrom+idx]
+
+	// IPv6 数据库中 IPv4 地址位于 ::/96 之下
+	if r.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < r.nodeCount; i++ {
+			node = r.readNode(node, 0)
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+// DatabaseType 元数据中的 database_type，如 GeoLite2-City
+func (r *MMDBReader) DatabaseType() string {
+	return r.databaseType
+}
+
+// Lookup 返回 IP 对应的解码数据，数据库中不存在时返回 nil
+func (r *MMDBReader) Lookup(ip net.IP) (map[string]interface{}, error) {
+	node, bits, err := r.startNode(ip)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(bits)*8 && node < r.nodeCount; i++ {
+		bit := (bits[i>>3] >> (7 - uint(i&7))) & 1
+		node = r.readNode(node, uint(bit))
+	}
+	if node == r.nodeCount {
+		return nil, nil
+	}
+	if node < r.nodeCount {
+		return nil, errors.New("invalid mmdb search tree")
+	}
+
+	offset := node - r.nodeCount - 16
+	if offset >= uint(len(r.data)) {
+		return nil, errors.New("invalid mmdb data pointer")
+	}
+	d := &mmdbDecoder{buf: r.data}
+	v, _, err := d.decode(offset, 0)
+	if err != nil {
+		return nil, err
+	}
+	m, _ := v.(map[string]interface{})
+	return m, nil
+}
+
+// Record 实现 Reader
+func (r *MMDBReader) Record(ip net.IP) (*Record, error) {
+	m, err := r.Lookup(ip)
+	if err != nil || m == nil {
+		return nil, err
+	}
+	rec := &Record{
+		Country: localizedName(m["country"]),
+		City:    localizedName(m["city"]),
+		ASN:     int(toUint64(m["autonomous_system_number"])),
+	}
+	if rec.Country == "" {
+		rec.Country = localizedName(m["registered_country"])
+	}
+	if subs, ok := m["subdivisions"].([]interface{}); ok && len(subs) > 0 {
+		rec.Region = localizedName(subs[0])
+	}
+	if org, ok := m["autonomous_system_organization"].(string); ok {
+		rec.Org = org
+	} else if org, ok := m["isp"].(string); ok {
+		rec.Org = org
+	} else if org, ok := m["organization"].(string); ok {
+		rec.Org = org
+	}
+	return rec, nil
+}
+
+func (r *MMDBReader) startNode(ip net.IP) (uint, []byte, error) {
+	if v4 := ip.To4(); v4 != nil {
+		if r.ipVersion == 4 {
+			return 0, v4, nil
+		}
+		return r.ipv4Start, v4, nil
+	}
+	v6 := ip.To16()
+	if v6 == nil {
+		return 0, nil, fmt.Errorf("invalid ip %v", ip)
+	}
+	if r.ipVersion == 4 {
+		return 0, nil, fmt.Errorf("ipv6 address %s in ipv4-only database", ip)
+	}
+	return 0, v6, nil
+}
+
+// readNode 读取节点的左(0)/右(1)记录
+func (r *MMDBReader) readNode(node, bit uint) uint {
+	b := r.buf[node*r.nodeBytes : (node+1)*r.nodeBytes]
+	switch r.recordSize {
+	case 24:
+		if bit == 0 {
+			return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3])<<16 | uint(b[4])<<8 | uint(b[5])
+	case 28:
+		if bit == 0 {
+			return (uint(b[3])&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return (uint(b[3])&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		if bit == 0 {
+			return uint(binary.BigEndian.Uint32(b[0:4]))
+		}
+		return uint(binary.BigEndian.Uint32(b[4:8]))
+	}
+}
+
+// mmdbDecoder 解码数据段，指针相对于 buf 起始位置
+type mmdbDecoder struct {
+	buf []byte
+}
+
+func (d *mmdbDecoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, errors.New("mmdb data nested too deep")
+	}
+	typ, size, offset, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typ == mmdbPointer {
+		ptr, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		v, _, err := d.decode(ptr, depth+1)
+		return v, next, err
+	}
+
+	switch typ {
+	case mmdbMap:
+		m := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			k, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, 0, errors.New("mmdb map key is not a string")
+			}
+			v, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[key] = v
+			offset = next
+		}
+		return m, offset, nil
+	case mmdbArray:
+		arr := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			v, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			arr = append(arr, v)
+			offset = next
+		}
+		return arr, offset, nil
+	case mmdbBool:
+		return size != 0, offset, nil
+	case mmdbContainer, mmdbEndMarker:
+		return nil, offset, nil
+	}
+
+	end := offset + size
+	if end > uint(len(d.buf)) {
+		return nil, 0, errors.New("mmdb data out of range")
+	}
+	b := d.buf[offset:end]
+	switch typ {
+	case mmdbString:
+		return string(b), end, nil
+	case mmdbBytes:
+		return append([]byte(nil), b...), end, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, errors.New("invalid mmdb double size")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), end, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, errors.New("invalid mmdb float size")
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), end, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64:
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return n, end, nil
+	case mmdbInt32:
+		var n uint32
+		for _, c := range b {
+			n = n<<8 | uint32(c)
+		}
+		return int32(n), end, nil
+	case mmdbUint128:
+		return new(big.Int).SetBytes(b), end, nil
+	}
+	return nil, 0, fmt.Errorf("unknown mmdb data type %d", typ)
+}
+
+// control 解析控制字节，返回类型、大小和数据起始位置
+func (d *mmdbDecoder) control(offset uint) (uint, uint, uint, error) {
+	if offset >= uint(len(d.buf)) {
+		return 0, 0, 0, errors.New("mmdb data out of range")
+	}
+	ctrl := d.buf[offset]
+	offset++
+	typ := uint(ctrl >> 5)
+	if typ == mmdbExtended {
+		if offset >= uint(len(d.buf)) {
+			return 0, 0, 0, errors.New("mmdb data out of range")
+		}
+		typ = uint(d.buf[offset]) + 7
+		offset++
+	}
+	size := uint(ctrl & 0x1F)
+	if typ == mmdbPointer {
+		return typ, size, offset, nil
+	}
+	if size >= 29 {
+		n := size - 28
+		if offset+n > uint(len(d.buf)) {
+			return 0, 0, 0, errors.New("mmdb data out of range")
+		}
+		var v uint
+		for _, c := range d.buf[offset : offset+n] {
+			v = v<<8 | uint(c)
+		}
+		switch size {
+		case 29:
+			size = 29 + v
+		case 30:
+			size = 285 + v
+		default:
+			size = 65821 + v
+		}
+		offset += n
+	}
+	return typ, size, offset, nil
+}
+
+// pointer 解析指针，size 为控制字节低5位
+func (d *mmdbDecoder) pointer(size, offset uint) (uint, uint, error) {
+	n := ((size >> 3) & 0x3) + 1
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, errors.New("mmdb data out of range")
+	}
+	var v uint
+	for _, c := range d.buf[offset : offset+n] {
+		v = v<<8 | uint(c)
+	}
+	switch n {
+	case 1:
+		v = (size&0x7)<<8 | v
+	case 2:
+		v = ((size&0x7)<<16 | v) + 2048
+	case 3:
+		v = ((size&0x7)<<24 | v) + 526336
+	}
+	return v, offset + n, nil
+}
+
+// localizedName 取 names 中的中文名称，缺失时回退英文
+func localizedName(v interface{}) string {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	names, ok := m["names"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, lang := range []string{"zh-CN", "en"} {
+		if s, ok := names[lang].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func toUint64(v interface{}) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int32:
+		if n > 0 {
+			return uint64(n)
+		}
+	}
+	return 0
+}
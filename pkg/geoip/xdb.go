@@ -0,0 +1,101 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ip2region xdb 文件结构：256 字节头部、256*256 向量索引，之后为 14 字节的分段索引
+const (
+	xdbHeaderSize      = 256
+	xdbVectorIndexCols = 256
+	xdbVectorIndexSize = 8
+	xdbSegmentSize     = 14
+	xdbVectorIndexLen  = xdbVectorIndexCols * xdbVectorIndexCols * xdbVectorIndexSize
+)
+
+// XDBReader ip2region xdb（IPv4）文件读取
+type XDBReader struct {
+	buf []byte
+}
+
+// NewXDBReader 解析内存中的 ip2region xdb 文件
+func NewXDBReader(buf []byte) (*XDBReader, error) {
+	if len(buf) < xdbHeaderSize+xdbVectorIndexLen {
+		return nil, errors.New("invalid xdb file: too small")
+	}
+	return &XDBReader{buf: buf}, nil
+}
+
+// Lookup 返回 IP 对应的原始区域字符串，如 中国|0|广东省|深圳市|电信
+func (r *XDBReader) Lookup(ip net.IP) (string, error) {
+	v4 := ip.To4()
+	if v4 == nil {
+		return "", fmt.Errorf("ipv6 address %s in ipv4-only database", ip)
+	}
+	n := binary.BigEndian.Uint32(v4)
+
+	idx := xdbHeaderSize + (int(v4[0])*xdbVectorIndexCols+int(v4[1]))*xdbVectorIndexSize
+	sPtr := int(binary.LittleEndian.Uint32(r.buf[idx:]))
+	ePtr := int(binary.LittleEndian.Uint32(r.buf[idx+4:]))
+	if ePtr < sPtr || ePtr+xdbSegmentSize > len(r.buf) {
+		return "", errors.New("invalid xdb vector index")
+	}
+
+	lo, hi := 0, (ePtr-sPtr)/xdbSegmentSize
+	for lo <= hi {
+		mid := (lo + hi) >> 1
+		p := sPtr + mid*xdbSegmentSize
+		seg := r.buf[p : p+xdbSegmentSize]
+		if n < binary.LittleEndian.Uint32(seg[0:]) {
+			hi = mid - 1
+		} else if n > binary.LittleEndian.Uint32(seg[4:]) {
+			lo = mid + 1
+		} else {
+			dataLen := int(binary.LittleEndian.Uint16(seg[8:]))
+			dataPtr := int(binary.LittleEndian.Uint32(seg[10:]))
+			if dataPtr+dataLen > len(r.buf) {
+				return "", errors.New("invalid xdb data pointer")
+			}
+			return string(r.buf[dataPtr : dataPtr+dataLen]), nil
+		}
+	}
+	return "", nil
+}
+
+// Record 实现 Reader，跳过 IPv6 地址
+func (r *XDBReader) Record(ip net.IP) (*Record, error) {
+	if ip.To4() == nil {
+		return nil, nil
+	}
+	region, err := r.Lookup(ip)
+	if err != nil || region == "" {
+		return nil, err
+	}
+	return parseRegion(region), nil
+}
+
+// parseRegion 解析 国家|区域|省份|城市|ISP 或 国家|省份|城市|ISP 格式，"0" 表示空
+func parseRegion(region string) *Record {
+	parts := strings.Split(region, "|")
+	for i, p := range parts {
+		if p == "0" {
+			parts[i] = ""
+		}
+	}
+	rec := &Record{}
+	switch len(parts) {
+	case 5:
+		rec.Country, rec.Region, rec.City, rec.Org = parts[0], parts[2], parts[3], parts[4]
+	case 4:
+		rec.Country, rec.Region, rec.City, rec.Org = parts[0], parts[1], parts[2], parts[3]
+	default:
+		if len(parts) > 0 {
+			rec.Country = parts[0]
+		}
+	}
+	return rec
+}
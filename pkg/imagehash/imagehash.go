@@ -0,0 +1,89 @@
+// Package imagehash 图片感知哈希，视觉上相似的截图哈希的汉明距离较小
+package imagehash
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/bits"
+	"strconv"
+)
+
+// Hash 64 位感知哈希
+type Hash uint64
+
+// String 格式化为 16 位十六进制字符串
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// Parse 解析 Hash.String 输出的哈希
+func Parse(s string) (Hash, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("imagehash: invalid hash %q", s)
+	}
+	return Hash(v), nil
+}
+
+// Distance 两个哈希不同的位数
+func Distance(a, b Hash) int {
+	return bits.OnesCount64(uint64(a) ^ uint64(b))
+}
+
+// FromBytes 解码 PNG 或 JPEG 图片并计算差异哈希
+func FromBytes(data []byte) (Hash, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("imagehash: decode: %w", err)
+	}
+	return DHash(img), nil
+}
+
+// DHash 计算差异哈希：图片按区域平均缩小为 9x8 灰度网格，每一位表示该格是否比右侧相邻格更亮。
+// 整页截图高度不一，只取顶部 16:9 区域（首屏）计算，否则长页面被压缩后与页头相同的短页面不再相似
+func DHash(img image.Image) Hash {
+	b := img.Bounds()
+	if b.Empty() {
+		return 0
+	}
+	if maxH := b.Dx() * 9 / 16; maxH > 0 && b.Dy() > maxH {
+		b.Max.Y = b.Min.Y + maxH
+	}
+
+	const w, h = 9, 8
+	var sum [h][w]float64
+	var cnt [h][w]int
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		gy := (y - b.Min.Y) * h / b.Dy()
+		for x := b.Min.X; x < b.Max.X; x++ {
+			gx := (x - b.Min.X) * w / b.Dx()
+			r, g, bl, _ := img.At(x, y).RGBA()
+			// ITU-R BT.601 亮度
+			sum[gy][gx] += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)
+			cnt[gy][gx]++
+		}
+	}
+
+	var grid [h][w]float64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if cnt[y][x] > 0 {
+				grid[y][x] = sum[y][x] / float64(cnt[y][x])
+			}
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w-1; x++ {
+			hash <<= 1
+			if grid[y][x] > grid[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return Hash(hash)
+}
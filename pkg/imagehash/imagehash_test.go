@@ -0,0 +1,75 @@
+package imagehash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// page 绘制模拟网页：背景上的页头栏和居中方块
+func page(width, height int, bg, box color.Color, boxX int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{bg}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, width, height/10), &image.Uniform{color.RGBA{30, 30, 60, 255}}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(boxX, height/3, boxX+width/4, height*2/3), &image.Uniform{box}, image.Point{}, draw.Src)
+	return img
+}
+
+func TestDHash(t *testing.T) {
+	white := color.RGBA{250, 250, 250, 255}
+	blue := color.RGBA{40, 90, 200, 255}
+
+	login := page(1920, 1080, white, blue, 720)
+	base := DHash(login)
+
+	// 同一页面缩放、重新编码为 JPEG，并在首屏以下追加内容
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, page(1280, 720, white, blue, 480), &jpeg.Options{Quality: 60}); err != nil {
+		t.Fatal(err)
+	}
+	resized, err := FromBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := Distance(base, resized); d > 4 {
+		t.Errorf("resized jpeg distance = %d, want <= 4", d)
+	}
+
+	long := image.NewRGBA(image.Rect(0, 0, 1920, 3000))
+	draw.Draw(long, long.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+	draw.Draw(long, login.Bounds(), login, image.Point{}, draw.Src)
+	if d := Distance(base, DHash(long)); d > 4 {
+		t.Errorf("long page distance = %d, want <= 4", d)
+	}
+
+	// 不同布局
+	other := page(1920, 1080, white, blue, 100)
+	if d := Distance(base, DHash(other)); d < 10 {
+		t.Errorf("different page distance = %d, want >= 10", d)
+	}
+}
+
+func TestParse(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, page(800, 600, color.White, color.Black, 200)); err != nil {
+		t.Fatal(err)
+	}
+	h, err := FromBytes(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Parse(h.String())
+	if err != nil || got != h {
+		t.Errorf("Parse(%s) = %v, %v", h, got, err)
+	}
+	if _, err := Parse("not-a-hash"); err == nil {
+		t.Error("Parse should reject invalid input")
+	}
+	if _, err := FromBytes([]byte("not an image")); err == nil {
+		t.Error("FromBytes should reject invalid image")
+	}
+}
@@ -37,6 +37,7 @@ type HighRiskFilter struct {
 	HighRiskPorts        []int    `json:"highRiskPorts"`        // 高危端口列表
 	HighRiskPocSeverities []string `json:"highRiskPocSeverities"` // 高危POC严重级别
 	NewAssetNotify       bool     `json:"newAssetNotify"`       // 新资产发现时通知
+	DiffOnly             bool     `json:"diffOnly"`             // 定时任务仅在与上次执行有差异时通知
 }
 
 // LoadConfigs 从配置列表加载提供者
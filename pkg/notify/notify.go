@@ -22,6 +22,31 @@ type NotifyResult struct {
 	ReportURL   string    `json:"reportUrl"` // 报告URL地址
 	// 高危检测结果
 	HighRiskInfo *HighRiskInfo `json:"highRiskInfo,omitempty"`
+	// 与同一定时任务上一次执行的差异，非定时任务或没有上一次执行时为空
+	Diff *ScanDiff `json:"diff,omitempty"`
+	// 路由规则发送的事件通知，Message 为已渲染的正文
+	EventType string `json:"eventType,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ScanDiff 两次执行之间的差异
+type ScanDiff struct {
+	BaseTaskId       string `json:"baseTaskId"`
+	NewAssetCount    int    `json:"newAssetCount"`
+	GoneAssetCount   int    `json:"goneAssetCount"`
+	NewPortCount     int    `json:"newPortCount"`
+	ClosedPortCount  int    `json:"closedPortCount"`
+	ChangeCount      int    `json:"changeCount"`
+	NewVulCount      int    `json:"newVulCount"`
+	ResolvedVulCount int    `json:"resolvedVulCount"`
+	Summary          string `json:"summary"` // 文字摘要
+}
+
+// HasChanges 是否存在差异
+func (d *ScanDiff) HasChanges() bool {
+	return d.NewAssetCount+d.GoneAssetCount+d.NewPortCount+d.ClosedPortCount+
+		d.ChangeCount+d.NewVulCount+d.ResolvedVulCount > 0
 }
 
 // HighRiskInfo 高危检测信息
@@ -95,6 +120,9 @@ func (n *Notifier) Send(ctx context.Context, result *NotifyResult) error {
 // FormatMessage 格式化通知消息
 func FormatMessage(result *NotifyResult, template string) string {
 	if template == "" {
+		if result.Message != "" {
+			return result.Message
+		}
 		template = DefaultTemplate
 	}
 
@@ -115,11 +143,29 @@ func FormatMessage(result *NotifyResult, template string) string {
 		"{{endTime}}", result.EndTime.Format("2006-01-02 15:04:05"),
 		"{{workspaceId}}", result.WorkspaceId,
 		"{{reportUrl}}", result.ReportURL,
+		"{{diffSummary}}", diffSummary(result),
+		"{{title}}", result.Title,
+		"{{message}}", result.Message,
 	)
 
 	return replacer.Replace(template)
 }
 
+// resultTitle 消息标题，事件通知使用事件标题
+func resultTitle(result *NotifyResult) string {
+	if result.Title != "" {
+		return result.Title
+	}
+	return fmt.Sprintf("扫描任务完成: %s", result.TaskName)
+}
+
+func diffSummary(result *NotifyResult) string {
+	if result.Diff == nil {
+		return ""
+	}
+	return result.Diff.Summary
+}
+
 // DefaultTemplate 默认消息模板
 const DefaultTemplate = `{{statusEmoji}} 扫描任务完成
 
@@ -130,7 +176,8 @@ const DefaultTemplate = `{{statusEmoji}} 扫描任务完成
 执行时长: {{duration}}
 开始时间: {{startTime}}
 结束时间: {{endTime}}
-报告地址: {{reportUrl}}`
+报告地址: {{reportUrl}}
+{{diffSummary}}`
 
 // MarkdownTemplate Markdown格式模板
 const MarkdownTemplate = `## {{statusEmoji}} 扫描任务完成
@@ -51,8 +51,8 @@ func (p *SMTPProvider) Send(ctx context.Context, result *NotifyResult) error {
 	}
 
 	subject := p.config.Subject
-	if subject == "" {
-		subject = fmt.Sprintf("扫描任务完成: %s", result.TaskName)
+	if subject == "" || result.Title != "" {
+		subject = resultTitle(result)
 	}
 
 	body := FormatMessage(result, p.config.MessageTemplate)
@@ -406,15 +406,20 @@ func (p *TeamsProvider) Send(ctx context.Context, result *NotifyResult) error {
 
 	content := FormatMessage(result, p.config.MessageTemplate)
 
+	summary, activityTitle := "扫描任务完成", "扫描任务完成通知"
+	if result.Title != "" {
+		summary, activityTitle = result.Title, result.Title
+	}
+
 	// Teams Adaptive Card 格式
 	payload := map[string]interface{}{
 		"@type":      "MessageCard",
 		"@context":   "http://schema.org/extensions",
 		"themeColor": "0076D7",
-		"summary":    "扫描任务完成",
+		"summary":    summary,
 		"sections": []map[string]interface{}{
 			{
-				"activityTitle": "扫描任务完成通知",
+				"activityTitle": activityTitle,
 				"text":          content,
 			},
 		},
@@ -460,7 +465,7 @@ func (p *GotifyProvider) Send(ctx context.Context, result *NotifyResult) error {
 	}
 
 	payload := map[string]interface{}{
-		"title":    fmt.Sprintf("扫描任务完成: %s", result.TaskName),
+		"title":    resultTitle(result),
 		"message":  content,
 		"priority": priority,
 	}
@@ -521,6 +526,10 @@ func (p *WebhookProvider) Send(ctx context.Context, result *NotifyResult) error
 				"reportUrl":  result.ReportURL,
 				"message":    FormatMessage(result, p.config.MessageTemplate),
 			}
+			if result.EventType != "" {
+				payload["eventType"] = result.EventType
+				payload["title"] = result.Title
+			}
 			data, _ := json.Marshal(payload)
 			body = bytes.NewReader(data)
 		}
@@ -0,0 +1,432 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 事件类型
+const (
+	EventTaskFinished  = "task_finished"
+	EventTaskFailed    = "task_failed"
+	EventNewVul        = "new_vul"
+	EventNewAsset      = "new_asset"
+	EventCertExpiring  = "cert_expiring"
+	EventWorkerOffline = "worker_offline"
+)
+
+// EventTypes 全部事件类型，摘要中按此顺序统计
+var EventTypes = []string{EventTaskFinished, EventTaskFailed, EventNewVul, EventNewAsset, EventCertExpiring, EventWorkerOffline}
+
+// eventNames 事件类型名称
+var eventNames = map[string]string{
+	EventTaskFinished:  "任务完成",
+	EventTaskFailed:    "任务失败",
+	EventNewVul:        "新漏洞",
+	EventNewAsset:      "新资产",
+	EventCertExpiring:  "证书过期",
+	EventWorkerOffline: "Worker离线",
+}
+
+// EventName 返回事件类型名称
+func EventName(typ string) string {
+	if name, ok := eventNames[typ]; ok {
+		return name
+	}
+	return typ
+}
+
+// 摘要发送周期
+const (
+	DigestNone   = ""
+	DigestHourly = "hourly"
+	DigestDaily  = "daily"
+)
+
+// DigestDailyHour 每日摘要的发送时刻（本地时间）
+const DigestDailyHour = 9
+
+// DigestMaxEvents 单个摘要保留的事件明细上限，超出部分只计数
+const DigestMaxEvents = 100
+
+// digestListLimit 摘要消息中列出的事件条数
+const digestListLimit = 20
+
+// severityRank 严重级别排序，未知级别视为 info
+var severityRank = map[string]int{
+	"info":     0,
+	"low":      1,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+// Event 通知事件
+type Event struct {
+	Type        string            `json:"type"`
+	WorkspaceId string            `json:"workspaceId"`
+	OrgId       string            `json:"orgId,omitempty"`
+	Severity    string            `json:"severity,omitempty"` // 漏洞等事件的严重级别
+	Tags        []string          `json:"tags,omitempty"`     // 任务或漏洞标签
+	Labels      []string          `json:"labels,omitempty"`   // 资产标签
+	Title       string            `json:"title"`
+	Target      string            `json:"target,omitempty"`
+	Detail      string            `json:"detail,omitempty"`
+	Fields      map[string]string `json:"fields,omitempty"` // 模板中可用的附加变量
+	Time        time.Time         `json:"time"`
+}
+
+// RouteRule 通知路由规则，条件为空表示不限制
+type RouteRule struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	Events       []string `json:"events"`
+	WorkspaceIds []string `json:"workspaceIds"`
+	OrgIds       []string `json:"orgIds"`
+	MinSeverity  string   `json:"minSeverity"` // 只约束带严重级别的事件
+	Tags         []string `json:"tags"`        // 命中任一事件标签
+	Labels       []string `json:"labels"`      // 命中任一资产标签
+	ConfigIds    []string `json:"configIds"`   // 发送到的通知配置
+	Template     string   `json:"template"`    // 单条事件的消息模板，为空时使用事件默认模板
+	QuietStart   string   `json:"quietStart"`  // 静默时段 HH:MM，可跨零点
+	QuietEnd     string   `json:"quietEnd"`
+	Digest       string   `json:"digest"` // 为空立即发送，hourly/daily 合并为摘要
+}
+
+// Validate 校验规则配置
+func (r *RouteRule) Validate() error {
+	for _, e := range r.Events {
+		if _, ok := eventNames[e]; !ok {
+			return fmt.Errorf("unknown event type: %s", e)
+		}
+	}
+	if _, ok := severityRank[r.MinSeverity]; r.MinSeverity != "" && !ok {
+		return fmt.Errorf("unknown severity: %s", r.MinSeverity)
+	}
+	switch r.Digest {
+	case DigestNone, DigestHourly, DigestDaily:
+	default:
+		return fmt.Errorf("unknown digest period: %s", r.Digest)
+	}
+	if (r.QuietStart == "") != (r.QuietEnd == "") {
+		return fmt.Errorf("quiet hours need both start and end")
+	}
+	if r.QuietStart != "" {
+		if _, err := parseClock(r.QuietStart); err != nil {
+			return err
+		}
+		if _, err := parseClock(r.QuietEnd); err != nil {
+			return err
+		}
+	}
+	if len(r.ConfigIds) == 0 {
+		return fmt.Errorf("no notify config selected")
+	}
+	return nil
+}
+
+// Match 判断事件是否命中规则
+func (r *RouteRule) Match(e *Event) bool {
+	if len(r.Events) > 0 && !containsFold(r.Events, e.Type) {
+		return false
+	}
+	if len(r.WorkspaceIds) > 0 && !containsFold(r.WorkspaceIds, e.WorkspaceId) {
+		return false
+	}
+	if len(r.OrgIds) > 0 && !containsFold(r.OrgIds, e.OrgId) {
+		return false
+	}
+	if r.MinSeverity != "" && e.Severity != "" && severityRank[strings.ToLower(e.Severity)] < severityRank[r.MinSeverity] {
+		return false
+	}
+	if len(r.Tags) > 0 && !intersectsFold(r.Tags, e.Tags) {
+		return false
+	}
+	if len(r.Labels) > 0 && !intersectsFold(r.Labels, e.Labels) {
+		return false
+	}
+	return true
+}
+
+// DeliverAt 返回 now 时刻产生的事件应在何时发送，立即发送时返回零值。
+// 摘要在整点或每日固定时刻发送，落在静默时段内的发送推迟到静默结束
+func (r *RouteRule) DeliverAt(now time.Time) time.Time {
+	var at time.Time
+	switch r.Digest {
+	case DigestHourly:
+		at = time.Date(now.Year(), now.Month(), now.Day(), now.Hour()+1, 0, 0, 0, now.Location())
+	case DigestDaily:
+		at = time.Date(now.Year(), now.Month(), now.Day(), DigestDailyHour, 0, 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+	}
+
+	check := now
+	if !at.IsZero() {
+		check = at
+	}
+	if end, ok := r.quietUntil(check); ok {
+		return end
+	}
+	return at
+}
+
+// quietUntil t 落在静默时段内时返回静默结束时间
+func (r *RouteRule) quietUntil(t time.Time) (time.Time, bool) {
+	if r.QuietStart == "" || r.QuietEnd == "" {
+		return time.Time{}, false
+	}
+	start, err1 := parseClock(r.QuietStart)
+	end, err2 := parseClock(r.QuietEnd)
+	if err1 != nil || err2 != nil || start == end {
+		return time.Time{}, false
+	}
+
+	m := t.Hour()*60 + t.Minute()
+	var quiet bool
+	if start < end {
+		quiet = m >= start && m < end
+	} else {
+		quiet = m >= start || m < end
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	until := time.Date(t.Year(), t.Month(), t.Day(), end/60, end%60, 0, 0, t.Location())
+	if !until.After(t) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
+
+// parseClock 解析 HH:MM，返回当天的分钟数
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if ok {
+		hour, err1 := strconv.Atoi(h)
+		minute, err2 := strconv.Atoi(m)
+		if err1 == nil && err2 == nil && hour >= 0 && hour < 24 && minute >= 0 && minute < 60 {
+			return hour*60 + minute, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time of day: %q", s)
+}
+
+// RoutedConfigIds 返回接收指定事件类型的通知配置。
+// 任务事件已由路由规则接管的配置不再按旧的高危过滤方式发送任务完成通知
+func RoutedConfigIds(rules []RouteRule, eventTypes ...string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, r := range rules {
+		covered := len(r.Events) == 0
+		for _, typ := range eventTypes {
+			if containsFold(r.Events, typ) {
+				covered = true
+			}
+		}
+		if !covered {
+			continue
+		}
+		for _, id := range r.ConfigIds {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+// TaskEvent 由任务通知结果构建任务完成或失败事件
+func TaskEvent(result *NotifyResult, orgId string, tags []string) *Event {
+	typ := EventTaskFinished
+	statusEmoji := "✅"
+	if result.Status == "FAILURE" {
+		typ = EventTaskFailed
+		statusEmoji = "❌"
+	}
+	return &Event{
+		Type:        typ,
+		WorkspaceId: result.WorkspaceId,
+		OrgId:       orgId,
+		Tags:        tags,
+		Title:       result.TaskName,
+		Target:      result.TaskId,
+		Detail:      diffSummary(result),
+		Time:        result.EndTime,
+		Fields: map[string]string{
+			"taskName":    result.TaskName,
+			"taskId":      result.TaskId,
+			"status":      result.Status,
+			"statusEmoji": statusEmoji,
+			"assetCount":  strconv.Itoa(result.AssetCount),
+			"vulCount":    strconv.Itoa(result.VulCount),
+			"duration":    result.Duration,
+			"startTime":   result.StartTime.Format("2006-01-02 15:04:05"),
+			"endTime":     result.EndTime.Format("2006-01-02 15:04:05"),
+			"reportUrl":   result.ReportURL,
+			"diffSummary": diffSummary(result),
+		},
+	}
+}
+
+// FormatEvent 按模板格式化单条事件，模板为空时使用事件默认模板
+func FormatEvent(e *Event, template string) string {
+	if template == "" {
+		template = DefaultEventTemplates[e.Type]
+	}
+	if template == "" {
+		template = genericEventTemplate
+	}
+
+	pairs := []string{
+		"{{event}}", EventName(e.Type),
+		"{{title}}", e.Title,
+		"{{target}}", e.Target,
+		"{{severity}}", e.Severity,
+		"{{detail}}", e.Detail,
+		"{{workspaceId}}", e.WorkspaceId,
+		"{{orgId}}", e.OrgId,
+		"{{tags}}", strings.Join(e.Tags, ", "),
+		"{{labels}}", strings.Join(e.Labels, ", "),
+		"{{time}}", e.Time.Local().Format("2006-01-02 15:04:05"),
+	}
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		pairs = append(pairs, "{{"+k+"}}", e.Fields[k])
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// RenderEvents 生成消息标题和正文。只有一条事件时使用规则模板，
+// 多条事件合并为摘要，按类型计数并列出前若干条
+func RenderEvents(rule *RouteRule, events []Event, counts map[string]int) (string, string) {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	if total <= 1 && len(events) == 1 {
+		e := &events[0]
+		return eventTitle(e), FormatEvent(e, rule.Template)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📬 通知摘要: 共 %d 条事件\n", total)
+	if rule.Name != "" {
+		fmt.Fprintf(&b, "路由规则: %s\n", rule.Name)
+	}
+	for _, typ := range EventTypes {
+		if n := counts[typ]; n > 0 {
+			fmt.Fprintf(&b, "%s: %d 条\n", EventName(typ), n)
+		}
+	}
+	b.WriteString("\n")
+
+	listed := len(events)
+	if listed > digestListLimit {
+		listed = digestListLimit
+	}
+	for i := 0; i < listed; i++ {
+		b.WriteString(eventLine(&events[i]))
+		b.WriteString("\n")
+	}
+	if total > listed {
+		fmt.Fprintf(&b, "... 另有 %d 条未列出\n", total-listed)
+	}
+	return fmt.Sprintf("通知摘要: %d 条事件", total), b.String()
+}
+
+// CountEvents 按事件类型计数
+func CountEvents(events []Event) map[string]int {
+	counts := make(map[string]int)
+	for _, e := range events {
+		counts[e.Type]++
+	}
+	return counts
+}
+
+func eventTitle(e *Event) string {
+	if e.Title != "" {
+		return EventName(e.Type) + ": " + e.Title
+	}
+	return EventName(e.Type) + ": " + e.Target
+}
+
+// eventLine 摘要中的单行事件描述
+func eventLine(e *Event) string {
+	var b strings.Builder
+	b.WriteString("- [" + EventName(e.Type) + "]")
+	if e.Severity != "" {
+		b.WriteString("[" + e.Severity + "]")
+	}
+	if e.Title != "" {
+		b.WriteString(" " + e.Title)
+	}
+	if e.Target != "" && e.Target != e.Title {
+		b.WriteString(" " + e.Target)
+	}
+	return b.String()
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func intersectsFold(a, b []string) bool {
+	for _, v := range b {
+		if containsFold(a, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultEventTemplates 各事件类型的默认消息模板
+var DefaultEventTemplates = map[string]string{
+	EventTaskFinished: DefaultTemplate,
+	EventTaskFailed: `❌ 扫描任务失败
+
+任务名称: {{taskName}}
+任务状态: {{status}}
+发现资产: {{assetCount}}
+发现漏洞: {{vulCount}}
+执行时长: {{duration}}
+开始时间: {{startTime}}
+结束时间: {{endTime}}`,
+	EventNewVul: `🚨 发现新漏洞
+
+漏洞名称: {{title}}
+严重级别: {{severity}}
+目标: {{target}}
+详情: {{detail}}
+发现时间: {{time}}`,
+	EventNewAsset: `🆕 发现新资产
+
+资产: {{target}}
+服务: {{service}}
+标题: {{title}}
+发现时间: {{time}}`,
+	EventCertExpiring: `⚠️ 证书即将过期
+
+目标: {{target}}
+详情: {{detail}}
+发现时间: {{time}}`,
+	EventWorkerOffline: `🔌 Worker离线
+
+Worker: {{target}}
+离线时间: {{time}}`,
+}
+
+const genericEventTemplate = `{{event}}: {{title}}
+目标: {{target}}
+时间: {{time}}`
@@ -0,0 +1,323 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRouteRuleMatch(t *testing.T) {
+	rule := RouteRule{
+		Events:       []string{EventNewVul, EventWorkerOffline},
+		WorkspaceIds: []string{"prod"},
+		MinSeverity:  "high",
+		Labels:       []string{"internet"},
+	}
+	cases := []struct {
+		name string
+		e    Event
+		want bool
+	}{
+		{"critical vul on labelled asset", Event{Type: EventNewVul, WorkspaceId: "prod", Severity: "critical", Labels: []string{"Internet"}}, true},
+		{"below min severity", Event{Type: EventNewVul, WorkspaceId: "prod", Severity: "medium", Labels: []string{"internet"}}, false},
+		{"other workspace", Event{Type: EventNewVul, WorkspaceId: "test", Severity: "critical", Labels: []string{"internet"}}, false},
+		{"missing label", Event{Type: EventNewVul, WorkspaceId: "prod", Severity: "critical"}, false},
+		{"event not selected", Event{Type: EventNewAsset, WorkspaceId: "prod", Labels: []string{"internet"}}, false},
+	}
+	for _, c := range cases {
+		if got := rule.Match(&c.e); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	// 不带严重级别的事件不受 MinSeverity 约束
+	noSeverity := RouteRule{MinSeverity: "critical", Tags: []string{"weekly"}}
+	if !noSeverity.Match(&Event{Type: EventTaskFailed, Tags: []string{"weekly"}}) {
+		t.Error("task event should ignore min severity")
+	}
+}
+
+func TestRouteRuleDeliverAt(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 3, day, hour, min, 0, 0, loc) }
+
+	cases := []struct {
+		name string
+		rule RouteRule
+		now  time.Time
+		want time.Time
+	}{
+		{"immediate", RouteRule{}, at(1, 10, 30), time.Time{}},
+		{"hourly", RouteRule{Digest: DigestHourly}, at(1, 10, 30), at(1, 11, 0)},
+		{"daily before send hour", RouteRule{Digest: DigestDaily}, at(1, 7, 0), at(1, DigestDailyHour, 0)},
+		{"daily after send hour", RouteRule{Digest: DigestDaily}, at(1, 10, 0), at(2, DigestDailyHour, 0)},
+		{"quiet across midnight", RouteRule{QuietStart: "22:00", QuietEnd: "07:30"}, at(1, 23, 15), at(2, 7, 30)},
+		{"quiet after midnight", RouteRule{QuietStart: "22:00", QuietEnd: "07:30"}, at(2, 3, 0), at(2, 7, 30)},
+		{"outside quiet", RouteRule{QuietStart: "22:00", QuietEnd: "07:30"}, at(2, 7, 30), time.Time{}},
+		{"hourly digest pushed past quiet", RouteRule{Digest: DigestHourly, QuietStart: "12:00", QuietEnd: "14:00"}, at(1, 11, 20), at(1, 14, 0)},
+	}
+	for _, c := range cases {
+		if got := c.rule.DeliverAt(c.now); !got.Equal(c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRouteRuleValidate(t *testing.T) {
+	ok := RouteRule{Events: []string{EventNewVul}, MinSeverity: "critical", Digest: DigestDaily, QuietStart: "22:00", QuietEnd: "08:00", ConfigIds: []string{"c1"}}
+	if err := ok.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bad := []RouteRule{
+		{Events: []string{"port_open"}, ConfigIds: []string{"c1"}},
+		{MinSeverity: "urgent", ConfigIds: []string{"c1"}},
+		{Digest: "weekly", ConfigIds: []string{"c1"}},
+		{QuietStart: "22:00", ConfigIds: []string{"c1"}},
+		{QuietStart: "25:00", QuietEnd: "08:00", ConfigIds: []string{"c1"}},
+		{},
+	}
+	for i, r := range bad {
+		if err := r.Validate(); err == nil {
+			t.Errorf("case %d should fail", i)
+		}
+	}
+}
+
+type recordProvider struct {
+	sent *[]*NotifyResult
+}
+
+func (p *recordProvider) Name() string { return "record" }
+
+func (p *recordProvider) Send(ctx context.Context, result *NotifyResult) error {
+	*p.sent = append(*p.sent, result)
+	return nil
+}
+
+type memDigestStore struct {
+	digests map[string]*Digest
+	times   map[string]time.Time
+}
+
+func (s *memDigestStore) Add(ctx context.Context, ruleId string, deliverAt time.Time, events []Event) error {
+	key := fmt.Sprintf("%s@%d", ruleId, deliverAt.Unix())
+	d, ok := s.digests[key]
+	if !ok {
+		d = &Digest{RuleId: ruleId, Counts: map[string]int{}}
+		s.digests[key] = d
+		s.times[key] = deliverAt
+	}
+	for _, e := range events {
+		if len(d.Events) < DigestMaxEvents {
+			d.Events = append(d.Events, e)
+		}
+		d.Counts[e.Type]++
+	}
+	return nil
+}
+
+func (s *memDigestStore) TakeDue(ctx context.Context, now time.Time) ([]Digest, error) {
+	var due []Digest
+	for key, d := range s.digests {
+		if !s.times[key].After(now) {
+			due = append(due, *d)
+			delete(s.digests, key)
+		}
+	}
+	return due, nil
+}
+
+func (s *memDigestStore) Requeue(ctx context.Context, digest Digest, deliverAt time.Time) error {
+	key := fmt.Sprintf("%s@%d", digest.RuleId, deliverAt.Unix())
+	s.digests[key] = &digest
+	s.times[key] = deliverAt
+	return nil
+}
+
+type failingProvider struct{}
+
+func (p *failingProvider) Name() string { return "failing" }
+
+func (p *failingProvider) Send(ctx context.Context, result *NotifyResult) error {
+	return errors.New("provider unavailable")
+}
+
+func newTestRouter(rules []RouteRule, store DigestStore, now *time.Time, sent *[]*NotifyResult) *Router {
+	r := NewRouter(rules, map[string]ConfigItem{
+		"c1":  {Provider: "webhook", Status: "enable"},
+		"off": {Provider: "webhook", Status: "disable"},
+	}, store)
+	r.now = func() time.Time { return *now }
+	r.newProvider = func(providerType, configJSON, messageTemplate string) (Provider, error) {
+		return &recordProvider{sent: sent}, nil
+	}
+	return r
+}
+
+func vulEvents(n int) []*Event {
+	events := make([]*Event, 0, n)
+	for i := 0; i < n; i++ {
+		events = append(events, &Event{Type: EventNewVul, WorkspaceId: "default", Severity: "critical", Title: "CVE-2026-0001", Target: fmt.Sprintf("10.0.0.%d:443", i)})
+	}
+	return events
+}
+
+func TestRouterDispatchBatchesImmediateEvents(t *testing.T) {
+	var sent []*NotifyResult
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	r := newTestRouter([]RouteRule{{Id: "r1", Name: "critical", MinSeverity: "critical", ConfigIds: []string{"c1", "off"}}}, nil, &now, &sent)
+
+	if err := r.Dispatch(context.Background(), vulEvents(500)); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("500 events should produce one message, got %d", len(sent))
+	}
+	msg := sent[0].Message
+	if !strings.Contains(msg, "共 500 条事件") || !strings.Contains(msg, "另有 480 条未列出") {
+		t.Errorf("unexpected digest message:\n%s", msg)
+	}
+	if FormatMessage(sent[0], "{{message}}") != msg {
+		t.Error("provider template should render the routed message")
+	}
+}
+
+func TestRouterSingleEventUsesTemplate(t *testing.T) {
+	var sent []*NotifyResult
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	r := newTestRouter([]RouteRule{{Id: "r1", Template: "{{event}} {{severity}} {{target}} {{service}}", ConfigIds: []string{"c1"}}}, nil, &now, &sent)
+
+	e := &Event{Type: EventNewAsset, Target: "a.example.com:443", Severity: "info", Fields: map[string]string{"service": "https"}}
+	if err := r.Dispatch(context.Background(), []*Event{e}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0].Message != "新资产 info a.example.com:443 https" {
+		t.Fatalf("unexpected message: %+v", sent)
+	}
+	if sent[0].Title != "新资产: a.example.com:443" {
+		t.Errorf("unexpected title %q", sent[0].Title)
+	}
+}
+
+func TestRouterDigestFlush(t *testing.T) {
+	var sent []*NotifyResult
+	store := &memDigestStore{digests: map[string]*Digest{}, times: map[string]time.Time{}}
+	now := time.Date(2026, 3, 1, 10, 5, 0, 0, time.Local)
+	r := newTestRouter([]RouteRule{{Id: "r1", Digest: DigestHourly, ConfigIds: []string{"c1"}}}, store, &now, &sent)
+
+	ctx := context.Background()
+	r.Dispatch(ctx, vulEvents(150))
+	now = now.Add(20 * time.Minute)
+	r.Dispatch(ctx, []*Event{{Type: EventWorkerOffline, Target: "worker-1"}})
+
+	r.Flush(ctx)
+	if len(sent) != 0 {
+		t.Fatalf("digest sent before due: %d", len(sent))
+	}
+
+	now = time.Date(2026, 3, 1, 11, 0, 0, 0, time.Local)
+	if err := r.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("expected one digest, got %d", len(sent))
+	}
+	msg := sent[0].Message
+	for _, want := range []string{"共 151 条事件", "新漏洞: 150 条", "Worker离线: 1 条"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("digest missing %q:\n%s", want, msg)
+		}
+	}
+	if sent[0].EventType != "digest" {
+		t.Errorf("unexpected event type %q", sent[0].EventType)
+	}
+}
+
+func TestRoutedConfigIds(t *testing.T) {
+	rules := []RouteRule{
+		{Events: []string{EventTaskFailed}, ConfigIds: []string{"a"}},
+		{Events: []string{EventWorkerOffline}, ConfigIds: []string{"b"}},
+		{ConfigIds: []string{"c"}},
+	}
+	got := RoutedConfigIds(rules, EventTaskFinished, EventTaskFailed)
+	if !got["a"] || got["b"] || !got["c"] {
+		t.Fatalf("unexpected routed configs: %v", got)
+	}
+}
+
+func TestTaskEvent(t *testing.T) {
+	e := TaskEvent(&NotifyResult{TaskId: "t1", TaskName: "weekly", Status: "FAILURE", AssetCount: 3}, "org", []string{"weekly"})
+	if e.Type != EventTaskFailed || e.Fields["assetCount"] != "3" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if msg := FormatEvent(e, ""); !strings.Contains(msg, "扫描任务失败") || !strings.Contains(msg, "任务名称: weekly") {
+		t.Errorf("unexpected message:\n%s", msg)
+	}
+}
+
+func TestRouterDigestRequeuedOnSendFailure(t *testing.T) {
+	var sent []*NotifyResult
+	store := &memDigestStore{digests: map[string]*Digest{}, times: map[string]time.Time{}}
+	now := time.Date(2026, 3, 1, 10, 5, 0, 0, time.Local)
+	r := newTestRouter([]RouteRule{{Id: "r1", Digest: DigestHourly, ConfigIds: []string{"c1"}}}, store, &now, &sent)
+	failing := true
+	r.newProvider = func(providerType, configJSON, messageTemplate string) (Provider, error) {
+		if failing {
+			return &failingProvider{}, nil
+		}
+		return &recordProvider{sent: &sent}, nil
+	}
+
+	ctx := context.Background()
+	r.Dispatch(ctx, vulEvents(150))
+	now = time.Date(2026, 3, 1, 11, 0, 0, 0, time.Local)
+	if err := r.Flush(ctx); err == nil {
+		t.Fatal("expected send error")
+	}
+	if len(store.digests) != 1 {
+		t.Fatalf("failed digest should be requeued, queue has %d", len(store.digests))
+	}
+
+	// 退避时间未到不重发
+	now = now.Add(30 * time.Second)
+	r.Flush(ctx)
+	if len(store.digests) != 1 {
+		t.Fatal("digest retried before backoff elapsed")
+	}
+
+	failing = false
+	now = now.Add(time.Minute)
+	if err := r.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || !strings.Contains(sent[0].Message, "共 150 条事件") {
+		t.Fatalf("requeued digest should keep full counts, sent %+v", sent)
+	}
+	if len(store.digests) != 0 {
+		t.Errorf("delivered digest left in queue")
+	}
+}
+
+func TestRouterDigestDroppedAfterMaxAttempts(t *testing.T) {
+	var sent []*NotifyResult
+	store := &memDigestStore{digests: map[string]*Digest{}, times: map[string]time.Time{}}
+	now := time.Date(2026, 3, 1, 10, 5, 0, 0, time.Local)
+	r := newTestRouter([]RouteRule{{Id: "r1", Digest: DigestHourly, ConfigIds: []string{"c1"}}}, store, &now, &sent)
+	r.newProvider = func(providerType, configJSON, messageTemplate string) (Provider, error) {
+		return &failingProvider{}, nil
+	}
+
+	ctx := context.Background()
+	r.Dispatch(ctx, vulEvents(1))
+	now = time.Date(2026, 3, 1, 11, 0, 0, 0, time.Local)
+	for i := 0; i < digestMaxAttempts; i++ {
+		r.Flush(ctx)
+		now = now.Add(time.Hour)
+	}
+	if len(store.digests) != 0 {
+		t.Errorf("digest should be dropped after %d failed attempts", digestMaxAttempts)
+	}
+}
@@ -0,0 +1,194 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 摘要发送失败后的重试：第 n 次失败后延迟 digestRetryDelay<<(n-1) 重新发送，失败 digestMaxAttempts 次后丢弃
+const (
+	digestRetryDelay  = time.Minute
+	digestMaxAttempts = 5
+)
+
+// Digest 到期待发送的摘要
+type Digest struct {
+	RuleId   string
+	Events   []Event        // 最多保留 DigestMaxEvents 条明细
+	Counts   map[string]int // 按事件类型的完整计数
+	Attempts int            // 已失败的发送次数
+}
+
+// DigestStore 保存等待合并发送的事件
+type DigestStore interface {
+	// Add 将事件追加到规则在 deliverAt 发送的摘要
+	Add(ctx context.Context, ruleId string, deliverAt time.Time, events []Event) error
+	// TakeDue 取出并移除 now 之前到期的摘要
+	TakeDue(ctx context.Context, now time.Time) ([]Digest, error)
+	// Requeue 将发送失败的摘要放回队列，在 deliverAt 重新发送，保留完整计数和失败次数
+	Requeue(ctx context.Context, digest Digest, deliverAt time.Time) error
+}
+
+// Router 按路由规则把事件发送到对应的通知配置
+type Router struct {
+	rules   []RouteRule
+	targets map[string]ConfigItem // 通知配置ID -> 配置
+	store   DigestStore
+
+	now         func() time.Time
+	newProvider func(providerType, configJSON, messageTemplate string) (Provider, error)
+}
+
+// NewRouter 创建路由器，store 为空时所有规则都立即发送
+func NewRouter(rules []RouteRule, targets map[string]ConfigItem, store DigestStore) *Router {
+	return &Router{
+		rules:       rules,
+		targets:     targets,
+		store:       store,
+		now:         time.Now,
+		newProvider: CreateProvider,
+	}
+}
+
+// Dispatch 分发一批事件。立即发送的规则把同一批命中的事件合并为一条消息，
+// 设置了摘要或处于静默时段的规则把事件存入摘要，到期后由 Flush 发送
+func (r *Router) Dispatch(ctx context.Context, events []*Event) error {
+	now := r.now()
+	for _, e := range events {
+		if e != nil && e.Time.IsZero() {
+			e.Time = now
+		}
+	}
+
+	var errs []string
+	for i := range r.rules {
+		rule := &r.rules[i]
+		var matched []Event
+		for _, e := range events {
+			if e != nil && rule.Match(e) {
+				matched = append(matched, *e)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		at := rule.DeliverAt(now)
+		if at.IsZero() || r.store == nil {
+			if err := r.send(ctx, rule, matched, CountEvents(matched)); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", rule.Name, err))
+			}
+			continue
+		}
+		if err := r.store.Add(ctx, rule.Id, at, matched); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", rule.Name, err))
+			continue
+		}
+		logx.Infof("[NotifyRoute] %d event(s) queued for rule %s, deliver at %s", len(matched), rule.Name, at.Format(time.RFC3339))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("notify route errors: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Flush 发送所有到期的摘要，规则已删除或停用的摘要直接丢弃
+// 发送失败的摘要按退避时间放回队列，避免通知渠道暂时不可用时丢失事件
+func (r *Router) Flush(ctx context.Context) error {
+	if r.store == nil {
+		return nil
+	}
+	digests, err := r.store.TakeDue(ctx, r.now())
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, d := range digests {
+		rule := r.rule(d.RuleId)
+		if rule == nil {
+			logx.Infof("[NotifyRoute] drop digest of missing rule %s", d.RuleId)
+			continue
+		}
+		if err := r.send(ctx, rule, d.Events, d.Counts); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", rule.Name, err))
+			if err := r.retry(ctx, rule, d); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: requeue: %v", rule.Name, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notify digest errors: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// retry 将发送失败的摘要放回队列，超过最大失败次数时丢弃
+func (r *Router) retry(ctx context.Context, rule *RouteRule, d Digest) error {
+	d.Attempts++
+	if d.Attempts >= digestMaxAttempts {
+		logx.Errorf("[NotifyRoute] drop digest of rule %s after %d failed attempts", rule.Name, d.Attempts)
+		return nil
+	}
+	at := r.now().Add(digestRetryDelay << (d.Attempts - 1))
+	logx.Infof("[NotifyRoute] digest of rule %s failed %d time(s), retry at %s", rule.Name, d.Attempts, at.Format(time.RFC3339))
+	return r.store.Requeue(ctx, d, at)
+}
+
+func (r *Router) rule(id string) *RouteRule {
+	for i := range r.rules {
+		if r.rules[i].Id == id {
+			return &r.rules[i]
+		}
+	}
+	return nil
+}
+
+// send 渲染消息并发送到规则选择的全部通知配置
+func (r *Router) send(ctx context.Context, rule *RouteRule, events []Event, counts map[string]int) error {
+	if len(events) == 0 {
+		return nil
+	}
+	title, message := RenderEvents(rule, events, counts)
+	result := &NotifyResult{
+		Title:       title,
+		Message:     message,
+		EventType:   events[0].Type,
+		WorkspaceId: events[0].WorkspaceId,
+	}
+	if len(counts) > 1 {
+		result.EventType = "digest"
+	}
+	if f := events[0].Fields; len(events) == 1 && f != nil {
+		result.TaskId = f["taskId"]
+		result.TaskName = f["taskName"]
+		result.Status = f["status"]
+		result.ReportURL = f["reportUrl"]
+	}
+
+	var errs []string
+	for _, id := range rule.ConfigIds {
+		cfg, ok := r.targets[id]
+		if !ok || cfg.Status != "enable" {
+			continue
+		}
+		// 消息已按规则模板渲染，通知配置自身的任务模板不再生效
+		provider, err := r.newProvider(cfg.Provider, cfg.Config, "{{message}}")
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", cfg.Provider, err))
+			continue
+		}
+		if err := provider.Send(ctx, result); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", provider.Name(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -110,6 +110,12 @@ func SendNotificationAsync(ctx context.Context, configs []ConfigItem, result *No
 func filterConfigsByHighRisk(configs []ConfigItem, result *NotifyResult) []ConfigItem {
 	var filtered []ConfigItem
 	for _, cfg := range configs {
+		// 定时任务与上次执行无差异时不通知
+		if cfg.HighRiskFilter != nil && cfg.HighRiskFilter.DiffOnly && result.Diff != nil && !result.Diff.HasChanges() {
+			logx.Infof("filterConfigsByHighRisk: skipping provider %s due to no change since last run", cfg.Provider)
+			continue
+		}
+
 		// 如果未启用高危过滤，直接添加
 		if cfg.HighRiskFilter == nil || !cfg.HighRiskFilter.Enabled {
 			filtered = append(filtered, cfg)
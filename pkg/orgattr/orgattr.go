@@ -0,0 +1,242 @@
+// Package orgattr 按归属规则把资产自动归入组织，规则类型包括域名后缀、IP段、ICP备案、证书组织和云账号
+package orgattr
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// 规则类型
+const (
+	TypeDomain       = "domain"        // 域名后缀，example.com 同时匹配自身和所有子域名
+	TypeCIDR         = "cidr"          // IP段或单个IP，支持IPv4和IPv6
+	TypeICP          = "icp"           // ICP备案号，京ICP备12345678号 同时匹配 京ICP备12345678号-1
+	TypeCertOrg      = "cert_org"      // 证书主题中的组织(O)，不区分大小写
+	TypeCloudAccount = "cloud_account" // 云账号，不区分大小写
+)
+
+// Types 全部规则类型
+var Types = []string{TypeDomain, TypeCIDR, TypeICP, TypeCertOrg, TypeCloudAccount}
+
+// Rule 归属规则
+type Rule struct {
+	Id       string
+	OrgId    string
+	Type     string
+	Pattern  string
+	Priority int // 数值越大越优先
+}
+
+// Subject 参与匹配的资产属性
+type Subject struct {
+	Host         string
+	Domain       string
+	IPs          []string
+	ICP          string
+	CertOrgs     []string
+	CloudAccount string
+}
+
+type compiledRule struct {
+	Rule
+	pattern     string
+	prefix      netip.Prefix
+	specificity int
+}
+
+// Matcher 编译后的规则集，可并发使用
+type Matcher struct {
+	rules []compiledRule
+}
+
+// NewMatcher 编译规则，无效的规则被跳过并在 errs 中返回
+// 匹配顺序：优先级高者优先，优先级相同时更精确的规则优先（更长的域名后缀、更小的IP段）
+func NewMatcher(rules []Rule) (*Matcher, []error) {
+	m := &Matcher{}
+	var errs []error
+	for _, r := range rules {
+		c, err := compile(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", r.Id, err))
+			continue
+		}
+		m.rules = append(m.rules, c)
+	}
+	sort.SliceStable(m.rules, func(i, j int) bool {
+		if m.rules[i].Priority != m.rules[j].Priority {
+			return m.rules[i].Priority > m.rules[j].Priority
+		}
+		return m.rules[i].specificity > m.rules[j].specificity
+	})
+	return m, errs
+}
+
+// Len 有效规则数量
+func (m *Matcher) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.rules)
+}
+
+// Match 返回第一条命中的规则，未命中返回 nil
+func (m *Matcher) Match(s *Subject) *Rule {
+	if m == nil || s == nil {
+		return nil
+	}
+
+	var names []string
+	var addrs []netip.Addr
+	for _, h := range []string{s.Host, s.Domain} {
+		h = strings.TrimSuffix(strings.ToLower(strings.Trim(strings.TrimSpace(h), "[]")), ".")
+		if h == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(h); err == nil {
+			addrs = append(addrs, addr.Unmap())
+		} else {
+			names = append(names, h)
+		}
+	}
+	for _, ip := range s.IPs {
+		if addr, err := netip.ParseAddr(strings.TrimSpace(ip)); err == nil {
+			addrs = append(addrs, addr.Unmap())
+		}
+	}
+	icp := strings.TrimSpace(s.ICP)
+
+	for i := range m.rules {
+		r := &m.rules[i]
+		switch r.Type {
+		case TypeDomain:
+			for _, name := range names {
+				if name == r.pattern || strings.HasSuffix(name, "."+r.pattern) {
+					return &r.Rule
+				}
+			}
+		case TypeCIDR:
+			for _, addr := range addrs {
+				if r.prefix.Contains(addr) {
+					return &r.Rule
+				}
+			}
+		case TypeICP:
+			if icp != "" && (icp == r.pattern || strings.HasPrefix(icp, r.pattern+"-")) {
+				return &r.Rule
+			}
+		case TypeCertOrg:
+			for _, org := range s.CertOrgs {
+				if strings.EqualFold(strings.TrimSpace(org), r.pattern) {
+					return &r.Rule
+				}
+			}
+		case TypeCloudAccount:
+			if strings.EqualFold(strings.TrimSpace(s.CloudAccount), r.pattern) {
+				return &r.Rule
+			}
+		}
+	}
+	return nil
+}
+
+// Validate 校验规则类型和内容
+func Validate(typ, pattern string) error {
+	_, err := compile(Rule{Type: typ, Pattern: pattern})
+	return err
+}
+
+func compile(r Rule) (compiledRule, error) {
+	c := compiledRule{Rule: r}
+	pattern := strings.TrimSpace(r.Pattern)
+	if pattern == "" {
+		return c, fmt.Errorf("empty pattern")
+	}
+
+	switch r.Type {
+	case TypeDomain:
+		pattern = strings.ToLower(pattern)
+		pattern = strings.TrimPrefix(pattern, "*.")
+		pattern = strings.Trim(pattern, ".")
+		if pattern == "" || strings.ContainsAny(pattern, " /:*") {
+			return c, fmt.Errorf("invalid domain suffix: %s", r.Pattern)
+		}
+		c.specificity = strings.Count(pattern, ".") + 1
+	case TypeCIDR:
+		prefix, err := parsePrefix(pattern)
+		if err != nil {
+			return c, err
+		}
+		c.prefix = prefix
+		// IPv4 与 IPv6 前缀长度换算到同一尺度比较
+		c.specificity = prefix.Bits()
+		if prefix.Addr().Is4() {
+			c.specificity += 96
+		}
+	case TypeICP, TypeCertOrg, TypeCloudAccount:
+	default:
+		return c, fmt.Errorf("unknown rule type: %s", r.Type)
+	}
+	c.pattern = pattern
+	return c, nil
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid ip or cidr: %s", s)
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid ip or cidr: %s", s)
+	}
+	if prefix.Addr().Is4In6() {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked(), nil
+}
+
+// CertOrganizations 从证书主题（如 "CN=a.example.com,O=Example Inc.,C=US"）中提取组织名
+func CertOrganizations(subject string) []string {
+	var orgs []string
+	for _, part := range splitDN(subject) {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || !strings.EqualFold(strings.TrimSpace(kv[0]), "O") {
+			continue
+		}
+		if org := strings.TrimSpace(kv[1]); org != "" {
+			orgs = append(orgs, org)
+		}
+	}
+	return orgs
+}
+
+// splitDN 按未转义的逗号拆分DN，并去掉值中的转义符
+func splitDN(dn string) []string {
+	var parts []string
+	var b strings.Builder
+	escaped := false
+	for _, ch := range dn {
+		switch {
+		case escaped:
+			b.WriteRune(ch)
+			escaped = false
+		case ch == '\\':
+			escaped = true
+		case ch == ',' || ch == ';':
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(ch)
+		}
+	}
+	if b.Len() > 0 {
+		parts = append(parts, b.String())
+	}
+	return parts
+}
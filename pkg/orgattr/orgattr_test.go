@@ -0,0 +1,78 @@
+package orgattr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	m, errs := NewMatcher([]Rule{
+		{Id: "1", OrgId: "group", Type: TypeDomain, Pattern: "example.com"},
+		{Id: "2", OrgId: "shop", Type: TypeDomain, Pattern: "*.shop.example.com"},
+		{Id: "3", OrgId: "dc", Type: TypeCIDR, Pattern: "10.0.0.0/8"},
+		{Id: "4", OrgId: "dc-web", Type: TypeCIDR, Pattern: "10.1.0.0/16"},
+		{Id: "5", OrgId: "v6", Type: TypeCIDR, Pattern: "2001:db8::/32"},
+		{Id: "6", OrgId: "icp", Type: TypeICP, Pattern: "京ICP备12345678号"},
+		{Id: "7", OrgId: "cert", Type: TypeCertOrg, Pattern: "Example Inc."},
+		{Id: "8", OrgId: "cloud", Type: TypeCloudAccount, Pattern: "aliyun:1234"},
+		{Id: "9", OrgId: "pinned", Type: TypeCIDR, Pattern: "192.0.2.10", Priority: 10},
+		{Id: "bad", OrgId: "x", Type: TypeCIDR, Pattern: "10.0.0.0/33"},
+		{Id: "bad2", OrgId: "x", Type: "asn", Pattern: "13335"},
+	})
+	if len(errs) != 2 || m.Len() != 9 {
+		t.Fatalf("expected 9 rules and 2 errors, got %d rules, errs=%v", m.Len(), errs)
+	}
+
+	cases := []struct {
+		name string
+		s    Subject
+		want string
+	}{
+		{"domain self", Subject{Host: "example.com"}, "group"},
+		{"subdomain", Subject{Host: "www.example.com"}, "group"},
+		{"longer suffix wins", Subject{Host: "api.shop.example.com"}, "shop"},
+		{"suffix must be label aligned", Subject{Host: "badexample.com"}, ""},
+		{"smaller cidr wins", Subject{Host: "10.1.2.3"}, "dc-web"},
+		{"cidr from resolved ips", Subject{Host: "intranet.local", IPs: []string{"10.9.9.9"}}, "dc"},
+		{"ipv6 bracketed", Subject{Host: "[2001:db8::1]"}, "v6"},
+		{"priority wins over domain", Subject{Host: "www.example.com", IPs: []string{"192.0.2.10"}}, "pinned"},
+		{"icp site suffix", Subject{ICP: "京ICP备12345678号-3"}, "icp"},
+		{"icp other number", Subject{ICP: "京ICP备123456789号"}, ""},
+		{"cert org", Subject{CertOrgs: []string{"example inc."}}, "cert"},
+		{"cloud account", Subject{CloudAccount: "Aliyun:1234"}, "cloud"},
+		{"no match", Subject{Host: "other.org", IPs: []string{"8.8.8.8"}}, ""},
+	}
+	for _, c := range cases {
+		got := ""
+		if r := m.Match(&c.s); r != nil {
+			got = r.OrgId
+		}
+		if got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, ok := range [][2]string{{TypeDomain, ".example.com"}, {TypeCIDR, "::ffff:10.0.0.0/104"}, {TypeCertOrg, "Acme"}} {
+		if err := Validate(ok[0], ok[1]); err != nil {
+			t.Errorf("%v: %v", ok, err)
+		}
+	}
+	for _, bad := range [][2]string{{TypeDomain, "*"}, {TypeDomain, "http://a.com"}, {TypeCIDR, "10.0.0"}, {TypeICP, " "}, {"asn", "1"}} {
+		if err := Validate(bad[0], bad[1]); err == nil {
+			t.Errorf("%v should fail", bad)
+		}
+	}
+}
+
+func TestCertOrganizations(t *testing.T) {
+	got := CertOrganizations(`CN=www.example.com,O=Example\, Inc.,O=Example Group,C=US`)
+	want := []string{"Example, Inc.", "Example Group"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if orgs := CertOrganizations("CN=localhost"); len(orgs) != 0 {
+		t.Fatalf("unexpected orgs: %v", orgs)
+	}
+}
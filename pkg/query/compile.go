@@ -0,0 +1,448 @@
+package query
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldType 字段类型，决定支持的运算符和值的匹配方式
+type FieldType int
+
+const (
+	// FieldString: = 包含（忽略大小写），== 精确匹配，!= 不包含，=~ 正则
+	FieldString FieldType = iota
+	// FieldKeyword: =/== 精确匹配，!= 不等于，=~ 正则
+	FieldKeyword
+	// FieldNumber: =, ==, !=, >, >=, <, <=；= 还支持范围 (80-90) 和列表 (80,443)
+	FieldNumber
+	// FieldIP: 同 FieldString，= 还支持 CIDR (10.0.0.0/8)
+	FieldIP
+	// FieldBool: = true/false
+	FieldBool
+)
+
+// Field 查询字段到一个或多个文档路径的映射
+// 多个路径时，肯定匹配任一路径满足即可，否定匹配 (!=) 需要所有路径都满足
+type Field struct {
+	Paths []string
+	Type  FieldType
+	// Transform 匹配前对原始值做规范化，可为空
+	Transform func(string) string
+}
+
+// Schema 集合可查询的字段表
+type Schema struct {
+	Fields map[string]Field
+	// TextFields 查询包含不带字段的搜索词时匹配的字段
+	TextFields []string
+}
+
+// Compile 解析并编译查询为 MongoDB 查询条件，空查询返回空条件
+func (s *Schema) Compile(input string) (bson.M, error) {
+	node, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return bson.M{}, nil
+	}
+	return s.CompileNode(node)
+}
+
+// CompileNode 编译已解析的语法树
+func (s *Schema) CompileNode(node Node) (bson.M, error) {
+	switch n := node.(type) {
+	case *BinaryExpr:
+		return s.compileBinary(n)
+	case *NotExpr:
+		inner, err := s.CompileNode(n.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$nor": []bson.M{inner}}, nil
+	case *Text:
+		return s.compileText(n), nil
+	case *Condition:
+		return s.compileCondition(n)
+	}
+	return nil, errorf(0, "未知的语法节点")
+}
+
+func (s *Schema) compileBinary(n *BinaryExpr) (bson.M, error) {
+	var parts []bson.M
+	if err := s.collect(n, n.Op, &parts); err != nil {
+		return nil, err
+	}
+	if n.Op == TokenOr {
+		return bson.M{"$or": parts}, nil
+	}
+	return mergeAnd(parts), nil
+}
+
+// collect 展开相同运算符的连续表达式，a && b && c 编译为一个 $and 而不是嵌套
+func (s *Schema) collect(node Node, op TokenType, parts *[]bson.M) error {
+	if b, ok := node.(*BinaryExpr); ok && b.Op == op {
+		if err := s.collect(b.Left, op, parts); err != nil {
+			return err
+		}
+		return s.collect(b.Right, op, parts)
+	}
+	compiled, err := s.CompileNode(node)
+	if err != nil {
+		return err
+	}
+	*parts = append(*parts, compiled)
+	return nil
+}
+
+// mergeAnd 键不冲突时将 $and 合并为单个文档，
+// port=80 && title=x 这类简单查询保持调用方和索引期望的扁平形式
+func mergeAnd(parts []bson.M) bson.M {
+	merged := bson.M{}
+	for _, part := range parts {
+		for k := range part {
+			if _, exists := merged[k]; exists {
+				return bson.M{"$and": parts}
+			}
+			merged[k] = part[k]
+		}
+	}
+	return merged
+}
+
+func (s *Schema) compileText(n *Text) bson.M {
+	pattern := regexp.QuoteMeta(n.Value)
+	conds := make([]bson.M, 0, len(s.TextFields))
+	for _, path := range s.TextFields {
+		conds = append(conds, bson.M{path: bson.M{"$regex": pattern, "$options": "i"}})
+	}
+	if len(conds) == 1 {
+		return conds[0]
+	}
+	return bson.M{"$or": conds}
+}
+
+func (s *Schema) compileCondition(c *Condition) (bson.M, error) {
+	field, ok := s.Fields[c.Field]
+	if !ok {
+		return nil, errorf(c.FieldPos, "未知字段 %q", c.Field)
+	}
+	value := c.Value
+	if field.Transform != nil {
+		value = field.Transform(value)
+	}
+
+	var (
+		match    interface{}
+		negative bool
+		err      error
+	)
+	switch field.Type {
+	case FieldString, FieldIP:
+		match, negative, err = compileString(c, value, field.Type == FieldIP)
+	case FieldKeyword:
+		match, negative, err = compileKeyword(c, value)
+	case FieldNumber:
+		match, negative, err = compileNumber(c, value)
+	case FieldBool:
+		match, negative, err = compileBool(c, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return expandPaths(field.Paths, match, negative), nil
+}
+
+// expandPaths 将单路径匹配应用到字段的所有路径
+// 否定匹配的值已包含取反，需要所有路径都满足；肯定匹配满足任一路径即可
+func expandPaths(paths []string, match interface{}, negative bool) bson.M {
+	if len(paths) == 1 {
+		return bson.M{paths[0]: match}
+	}
+	conds := make([]bson.M, 0, len(paths))
+	for _, path := range paths {
+		conds = append(conds, bson.M{path: match})
+	}
+	if negative {
+		return bson.M{"$and": conds}
+	}
+	return bson.M{"$or": conds}
+}
+
+func unsupported(c *Condition) *SyntaxError {
+	return errorf(c.FieldPos, "字段 %s 不支持运算符 %s", c.Field, c.Op)
+}
+
+func compileRegex(c *Condition, value string) (interface{}, error) {
+	if _, err := regexp.Compile(value); err != nil {
+		return nil, errorf(c.ValuePos, "正则表达式无效: %v", err)
+	}
+	return bson.M{"$regex": value, "$options": "i"}, nil
+}
+
+func compileString(c *Condition, value string, isIP bool) (interface{}, bool, error) {
+	switch c.Op {
+	case TokenEq:
+		if isIP && strings.Contains(value, "/") {
+			pattern, err := cidrPattern(c, value)
+			if err != nil {
+				return nil, false, err
+			}
+			return bson.M{"$regex": pattern}, false, nil
+		}
+		return bson.M{"$regex": regexp.QuoteMeta(value), "$options": "i"}, false, nil
+	case TokenEqExact:
+		return value, false, nil
+	case TokenNe:
+		if isIP && strings.Contains(value, "/") {
+			pattern, err := cidrPattern(c, value)
+			if err != nil {
+				return nil, false, err
+			}
+			return bson.M{"$not": primitive.Regex{Pattern: pattern}}, true, nil
+		}
+		return bson.M{"$not": primitive.Regex{Pattern: regexp.QuoteMeta(value), Options: "i"}}, true, nil
+	case TokenMatch:
+		m, err := compileRegex(c, value)
+		return m, false, err
+	}
+	return nil, false, unsupported(c)
+}
+
+func compileKeyword(c *Condition, value string) (interface{}, bool, error) {
+	switch c.Op {
+	case TokenEq, TokenEqExact:
+		return value, false, nil
+	case TokenNe:
+		return bson.M{"$ne": value}, true, nil
+	case TokenMatch:
+		m, err := compileRegex(c, value)
+		return m, false, err
+	}
+	return nil, false, unsupported(c)
+}
+
+func compileBool(c *Condition, value string) (interface{}, bool, error) {
+	var want bool
+	switch strings.ToLower(value) {
+	case "true", "1", "yes":
+		want = true
+	case "false", "0", "no":
+		want = false
+	default:
+		return nil, false, errorf(c.ValuePos, "字段 %s 的值必须是 true 或 false", c.Field)
+	}
+	switch c.Op {
+	case TokenEq, TokenEqExact:
+	case TokenNe:
+		want = !want
+	default:
+		return nil, false, unsupported(c)
+	}
+	// 布尔字段通常为 omitempty，false 也要匹配字段不存在的文档
+	if want {
+		return true, false, nil
+	}
+	return bson.M{"$ne": true}, true, nil
+}
+
+func parseNumber(c *Condition, value string) (interface{}, error) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if n >= -1<<31 && n < 1<<31 {
+			return int(n), nil
+		}
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f, nil
+	}
+	return nil, errorf(c.ValuePos, "字段 %s 需要数值，实际为 %q", c.Field, value)
+}
+
+func compileNumber(c *Condition, value string) (interface{}, bool, error) {
+	switch c.Op {
+	case TokenEq, TokenEqExact, TokenNe:
+		match, err := numberSet(c, value)
+		if err != nil {
+			return nil, false, err
+		}
+		if c.Op != TokenNe {
+			return match, false, nil
+		}
+		if m, ok := match.(bson.M); ok {
+			if in, ok := m["$in"]; ok {
+				return bson.M{"$nin": in}, true, nil
+			}
+			return bson.M{"$not": m}, true, nil
+		}
+		return bson.M{"$ne": match}, true, nil
+	case TokenGt, TokenGte, TokenLt, TokenLte:
+		n, err := parseNumber(c, value)
+		if err != nil {
+			return nil, false, err
+		}
+		op := map[TokenType]string{TokenGt: "$gt", TokenGte: "$gte", TokenLt: "$lt", TokenLte: "$lte"}[c.Op]
+		return bson.M{op: n}, false, nil
+	}
+	return nil, false, unsupported(c)
+}
+
+// numberSet 解析单个数字、范围 "a-b" 或列表 "a,b,c"
+func numberSet(c *Condition, value string) (interface{}, error) {
+	if strings.Contains(value, ",") {
+		var list []interface{}
+		for _, part := range strings.Split(value, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			n, err := parseNumber(c, part)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, n)
+		}
+		return bson.M{"$in": list}, nil
+	}
+	if idx := strings.Index(value, "-"); idx > 0 {
+		low, err := parseNumber(c, value[:idx])
+		if err != nil {
+			return nil, err
+		}
+		high, err := parseNumber(c, value[idx+1:])
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$gte": low, "$lte": high}, nil
+	}
+	return parseNumber(c, value)
+}
+
+// cidrPattern 将 CIDR 转换为匹配地址字符串的锚定正则
+// IPv4 不完整的字节展开为分支，任意前缀长度最多产生 256 个分支；IPv6 见 cidr6Pattern
+func cidrPattern(c *Condition, value string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(strings.TrimSpace(value))
+	if err != nil {
+		return "", errorf(c.ValuePos, "CIDR 格式无效: %s", value)
+	}
+	ip4 := ipNet.IP.To4()
+	if ip4 == nil {
+		return cidr6Pattern(c, ipNet)
+	}
+	ones, _ := ipNet.Mask.Size()
+
+	parts := make([]string, 0, 4)
+	for i := 0; i < 4; i++ {
+		bits := ones - i*8
+		switch {
+		case bits >= 8:
+			parts = append(parts, strconv.Itoa(int(ip4[i])))
+		case bits <= 0:
+			parts = append(parts, `\d{1,3}`)
+		default:
+			span := 1 << (8 - bits)
+			alts := make([]string, 0, span)
+			for v := int(ip4[i]); v < int(ip4[i])+span; v++ {
+				alts = append(alts, strconv.Itoa(v))
+			}
+			parts = append(parts, "(?:"+strings.Join(alts, "|")+")")
+		}
+	}
+	return "^" + strings.Join(parts, `\.`) + "$", nil
+}
+
+// IPv6 前缀不按 16 位对齐时，不完整的段最多展开的分支数
+const maxIPv6PartialHextets = 256
+
+// cidr6Pattern 将 IPv6 前缀转换为匹配压缩格式地址（如 2001:db8::1，与入库的 NormalizeHost 一致）的锚定正则
+// 前缀按 16 位段处理，不完整的段展开为分支（最多 256 个）
+func cidr6Pattern(c *Condition, ipNet *net.IPNet) (string, error) {
+	ones, _ := ipNet.Mask.Size()
+	if ones == 0 {
+		return ":", nil
+	}
+	full := ones / 16
+	hextets := make([]int, 8)
+	for i := range hextets {
+		hextets[i] = int(ipNet.IP[2*i])<<8 | int(ipNet.IP[2*i+1])
+	}
+
+	prefixes := [][]int{hextets[:full]}
+	if rest := ones % 16; rest != 0 {
+		span := 1 << (16 - rest)
+		if span > maxIPv6PartialHextets {
+			return "", errorf(c.ValuePos, "IPv6 CIDR 前缀 /%d 无法展开，请使用 16 位对齐或 /%d 以上的前缀", ones, full*16+8)
+		}
+		prefixes = make([][]int, 0, span)
+		for v := hextets[full]; v < hextets[full]+span; v++ {
+			p := append(append([]int{}, hextets[:full]...), v)
+			prefixes = append(prefixes, p)
+		}
+	}
+
+	var alts []string
+	seen := make(map[string]bool)
+	for _, p := range prefixes {
+		for _, alt := range hextetPrefixPatterns(p) {
+			if !seen[alt] {
+				seen[alt] = true
+				alts = append(alts, alt)
+			}
+		}
+	}
+	return "^(?:" + strings.Join(alts, "|") + ")", nil
+}
+
+// hextetPrefixPatterns 列出以给定若干段开头的压缩格式地址的所有写法：
+// 前缀内不压缩、压缩前缀内部的一段连续 0、或从前缀内某段起的连续 0 压缩到前缀之后
+func hextetPrefixPatterns(prefix []int) []string {
+	n := len(prefix)
+	const group = `[0-9a-f]{1,4}`
+	lit := func(hs []int) string {
+		parts := make([]string, len(hs))
+		for i, h := range hs {
+			parts[i] = strconv.FormatInt(int64(h), 16)
+		}
+		return strings.Join(parts, ":")
+	}
+	// 前缀之后恰好还有 k 段且不再含 ::
+	exact := func(k int) string {
+		if k == 0 {
+			return "$"
+		}
+		return "(?::" + group + "){" + strconv.Itoa(k) + "}$"
+	}
+
+	var patterns []string
+	if n == 8 {
+		patterns = append(patterns, lit(prefix)+"$")
+	} else {
+		patterns = append(patterns, lit(prefix)+":")
+	}
+	for i := 0; i < n; i++ {
+		if prefix[i] != 0 || (i > 0 && prefix[i-1] == 0) {
+			continue
+		}
+		j := i
+		for j < n && prefix[j] == 0 {
+			j++
+		}
+		head := lit(prefix[:i])
+		if j == n {
+			// 连续 0 延伸到前缀末尾，:: 之后最多还有 8-n 段
+			tail := "$"
+			if n < 8 {
+				tail = "(?:" + group + "(?::" + group + "){0," + strconv.Itoa(7-n) + "})?$"
+			}
+			patterns = append(patterns, head+"::"+tail)
+		} else if j-i >= 2 {
+			patterns = append(patterns, head+"::"+lit(prefix[j:])+exact(8-n))
+		}
+	}
+	return patterns
+}
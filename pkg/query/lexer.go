@@ -0,0 +1,199 @@
+// Package query 实现资产、漏洞和目录扫描列表使用的类 FOFA 查询语言
+// 查询先分词、解析为语法树，再按字段表编译为 MongoDB 查询条件
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TokenType 词法单元类型
+type TokenType int
+
+const (
+	TokenEOF     TokenType = iota
+	TokenIdent             // 无引号的词：字段名或值
+	TokenString            // 带引号的字符串
+	TokenAnd               // &&
+	TokenOr                // ||
+	TokenNot               // !
+	TokenLParen            // (
+	TokenRParen            // )
+	TokenEq                // =
+	TokenEqExact           // ==
+	TokenNe                // !=
+	TokenMatch             // =~
+	TokenGt                // >
+	TokenGte               // >=
+	TokenLt                // <
+	TokenLte               // <=
+)
+
+var tokenNames = map[TokenType]string{
+	TokenEOF:     "EOF",
+	TokenIdent:   "标识符",
+	TokenString:  "字符串",
+	TokenAnd:     "&&",
+	TokenOr:      "||",
+	TokenNot:     "!",
+	TokenLParen:  "(",
+	TokenRParen:  ")",
+	TokenEq:      "=",
+	TokenEqExact: "==",
+	TokenNe:      "!=",
+	TokenMatch:   "=~",
+	TokenGt:      ">",
+	TokenGte:     ">=",
+	TokenLt:      "<",
+	TokenLte:     "<=",
+}
+
+func (t TokenType) String() string {
+	if name, ok := tokenNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("token(%d)", int(t))
+}
+
+// IsComparison 判断是否为字段比较运算符
+func (t TokenType) IsComparison() bool {
+	return t >= TokenEq && t <= TokenLte
+}
+
+// Token 词法单元及其在查询中的字节偏移
+type Token struct {
+	Type  TokenType
+	Value string
+	Pos   int
+}
+
+// SyntaxError 查询语法错误，Pos 为出错位置的字节偏移，供前端定位
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("查询语法错误(位置 %d): %s", e.Pos, e.Msg)
+}
+
+func errorf(pos int, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Tokenize 将查询拆分为词法单元，结果总是以 TokenEOF 结尾
+func Tokenize(input string) ([]Token, error) {
+	var tokens []Token
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			i++
+			continue
+		}
+
+		start := i
+		two := ""
+		if i+1 < len(input) {
+			two = input[i : i+2]
+		}
+
+		switch {
+		case two == "&&":
+			tokens = append(tokens, Token{Type: TokenAnd, Value: two, Pos: start})
+			i += 2
+		case two == "||":
+			tokens = append(tokens, Token{Type: TokenOr, Value: two, Pos: start})
+			i += 2
+		case two == "==":
+			tokens = append(tokens, Token{Type: TokenEqExact, Value: two, Pos: start})
+			i += 2
+		case two == "!=":
+			tokens = append(tokens, Token{Type: TokenNe, Value: two, Pos: start})
+			i += 2
+		case two == "=~":
+			tokens = append(tokens, Token{Type: TokenMatch, Value: two, Pos: start})
+			i += 2
+		case two == ">=":
+			tokens = append(tokens, Token{Type: TokenGte, Value: two, Pos: start})
+			i += 2
+		case two == "<=":
+			tokens = append(tokens, Token{Type: TokenLte, Value: two, Pos: start})
+			i += 2
+		case c == '=':
+			tokens = append(tokens, Token{Type: TokenEq, Value: "=", Pos: start})
+			i++
+		case c == '>':
+			tokens = append(tokens, Token{Type: TokenGt, Value: ">", Pos: start})
+			i++
+		case c == '<':
+			tokens = append(tokens, Token{Type: TokenLt, Value: "<", Pos: start})
+			i++
+		case c == '!':
+			tokens = append(tokens, Token{Type: TokenNot, Value: "!", Pos: start})
+			i++
+		case c == '(':
+			tokens = append(tokens, Token{Type: TokenLParen, Value: "(", Pos: start})
+			i++
+		case c == ')':
+			tokens = append(tokens, Token{Type: TokenRParen, Value: ")", Pos: start})
+			i++
+		case c == '"' || c == '\'':
+			value, next, err := readString(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, Token{Type: TokenString, Value: value, Pos: start})
+			i = next
+		default:
+			next := readWord(input, i)
+			if next == i {
+				return nil, errorf(start, "无法识别的字符 %q", c)
+			}
+			tokens = append(tokens, Token{Type: TokenIdent, Value: input[i:next], Pos: start})
+			i = next
+		}
+	}
+	tokens = append(tokens, Token{Type: TokenEOF, Pos: len(input)})
+	return tokens, nil
+}
+
+// readString 读取从 input[start] 开始的带引号字符串
+// 反斜杠只转义引号和反斜杠本身，其余转义原样保留，正则表达式不受影响
+func readString(input string, start int) (string, int, error) {
+	quote := input[start]
+	var sb strings.Builder
+	i := start + 1
+	for i < len(input) {
+		c := input[i]
+		if c == '\\' && i+1 < len(input) && (input[i+1] == quote || input[i+1] == '\\') {
+			sb.WriteByte(input[i+1])
+			i += 2
+			continue
+		}
+		if c == quote {
+			return sb.String(), i + 1, nil
+		}
+		sb.WriteByte(c)
+		i++
+	}
+	return "", 0, errorf(start, "字符串缺少结束引号")
+}
+
+// readWord 读取无引号的词，遇到空白、括号、引号和运算符字符时结束
+// 因此 "port>8000" 会拆分为三个词法单元
+func readWord(input string, start int) int {
+	i := start
+	for i < len(input) {
+		c := input[i]
+		if c < 0x80 && (unicode.IsSpace(rune(c)) || strings.IndexByte("()=!<>\"'", c) >= 0) {
+			break
+		}
+		if (c == '&' || c == '|') && i+1 < len(input) && input[i+1] == c {
+			break
+		}
+		i++
+	}
+	return i
+}
@@ -0,0 +1,187 @@
+package query
+
+import "strings"
+
+// Node 查询语法树节点
+type Node interface {
+	node()
+}
+
+// BinaryExpr 用 && 或 || 连接的两个子表达式
+type BinaryExpr struct {
+	Op    TokenType // TokenAnd 或 TokenOr
+	Left  Node
+	Right Node
+}
+
+// NotExpr 取反的子表达式
+type NotExpr struct {
+	Expr Node
+	Pos  int
+}
+
+// Condition 单个字段比较，如 port>8000 或 title="login"
+type Condition struct {
+	Field    string
+	FieldPos int
+	Op       TokenType
+	Value    string
+	ValuePos int
+}
+
+// Text 不带字段的搜索词，在字段表的默认文本字段中匹配
+type Text struct {
+	Value string
+	Pos   int
+}
+
+func (*BinaryExpr) node() {}
+func (*NotExpr) node()    {}
+func (*Condition) node()  {}
+func (*Text) node()       {}
+
+// Parse 将查询解析为语法树，空查询返回 nil
+//
+// 语法：
+//
+//	expr    = or
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | primary
+//	primary = "(" expr ")" | field op value | value
+func Parse(input string) (Node, error) {
+	tokens, err := Tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().Type == TokenEOF {
+		return nil, nil
+	}
+	// 不含任何运算符的输入整体作为一个搜索词，"nginx login" 仍按短语搜索，与旧版行为一致
+	if isPlainText(tokens) {
+		return &Text{Value: strings.TrimSpace(input), Pos: tokens[0].Pos}, nil
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.Type != TokenEOF {
+		return nil, errorf(tok.Pos, "多余的 %s", describe(tok))
+	}
+	return node, nil
+}
+
+type parser struct {
+	tokens []Token
+	pos    int
+}
+
+func (p *parser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() Token {
+	tok := p.tokens[p.pos]
+	if tok.Type != TokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().Type == TokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: TokenOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().Type == TokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: TokenAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if tok := p.peek(); tok.Type == TokenNot {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{Expr: expr, Pos: tok.Pos}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.Type {
+	case TokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.Type != TokenRParen {
+			return nil, errorf(closing.Pos, "缺少右括号，位置 %d 的左括号未闭合", tok.Pos)
+		}
+		return expr, nil
+	case TokenString:
+		return &Text{Value: tok.Value, Pos: tok.Pos}, nil
+	case TokenIdent:
+		if !p.peek().Type.IsComparison() {
+			return &Text{Value: tok.Value, Pos: tok.Pos}, nil
+		}
+		op := p.next()
+		value := p.next()
+		if value.Type != TokenIdent && value.Type != TokenString {
+			return nil, errorf(value.Pos, "%s%s 后缺少值", tok.Value, op.Value)
+		}
+		return &Condition{
+			Field:    strings.ToLower(tok.Value),
+			FieldPos: tok.Pos,
+			Op:       op.Type,
+			Value:    value.Value,
+			ValuePos: value.Pos,
+		}, nil
+	case TokenEOF:
+		return nil, errorf(tok.Pos, "查询意外结束")
+	default:
+		return nil, errorf(tok.Pos, "此处不应出现 %s", describe(tok))
+	}
+}
+
+func isPlainText(tokens []Token) bool {
+	for _, tok := range tokens {
+		if tok.Type != TokenIdent && tok.Type != TokenEOF {
+			return false
+		}
+	}
+	return true
+}
+
+func describe(tok Token) string {
+	if tok.Type == TokenIdent || tok.Type == TokenString {
+		return "\"" + tok.Value + "\""
+	}
+	return "\"" + tok.Type.String() + "\""
+}
@@ -0,0 +1,197 @@
+package query
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var testSchema = &Schema{
+	Fields: map[string]Field{
+		"host":   {Paths: []string{"host"}, Type: FieldIP},
+		"ip":     {Paths: []string{"host", "ip.ipv4.ip"}, Type: FieldIP},
+		"port":   {Paths: []string{"port"}, Type: FieldNumber},
+		"title":  {Paths: []string{"title"}, Type: FieldString},
+		"status": {Paths: []string{"status"}, Type: FieldKeyword},
+		"cdn":    {Paths: []string{"cdn"}, Type: FieldBool},
+	},
+	TextFields: []string{"host", "title"},
+}
+
+func mustCompile(t *testing.T, q string) bson.M {
+	t.Helper()
+	filter, err := testSchema.Compile(q)
+	if err != nil {
+		t.Fatalf("compile %q: %v", q, err)
+	}
+	return filter
+}
+
+func TestCompile_FlatAndMerge(t *testing.T) {
+	got := mustCompile(t, `port=80 && title="admin login"`)
+	want := bson.M{
+		"port":  80,
+		"title": bson.M{"$regex": "admin login", "$options": "i"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCompile_OrNotAndGrouping(t *testing.T) {
+	got := mustCompile(t, `!(port>=8000 || status==200) && title!=test`)
+	want := bson.M{
+		"$nor": []bson.M{{"$or": []bson.M{
+			{"port": bson.M{"$gte": 8000}},
+			{"status": "200"},
+		}}},
+		"title": bson.M{"$not": primitive.Regex{Pattern: "test", Options: "i"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCompile_CollidingKeysUseAnd(t *testing.T) {
+	got := mustCompile(t, `port>80 && port<90`)
+	and, ok := got["$and"].([]bson.M)
+	if !ok || len(and) != 2 {
+		t.Fatalf("expected $and with 2 parts, got %v", got)
+	}
+}
+
+func TestCompile_PortRangeAndList(t *testing.T) {
+	if got := mustCompile(t, `port=8000-9000`); !reflect.DeepEqual(got["port"], bson.M{"$gte": 8000, "$lte": 9000}) {
+		t.Fatalf("unexpected range filter %v", got)
+	}
+	if got := mustCompile(t, `port!=80,443`); !reflect.DeepEqual(got["port"], bson.M{"$nin": []interface{}{80, 443}}) {
+		t.Fatalf("unexpected list filter %v", got)
+	}
+}
+
+func TestCompile_CIDR(t *testing.T) {
+	got := mustCompile(t, `host="10.0.0.0/8"`)
+	if !reflect.DeepEqual(got["host"], bson.M{"$regex": `^10\.\d{1,3}\.\d{1,3}\.\d{1,3}$`}) {
+		t.Fatalf("unexpected /8 filter %v", got)
+	}
+
+	got = mustCompile(t, `host="192.168.4.0/22"`)
+	if !reflect.DeepEqual(got["host"], bson.M{"$regex": `^192\.168\.(?:4|5|6|7)\.\d{1,3}$`}) {
+		t.Fatalf("unexpected /22 filter %v", got)
+	}
+
+	got = mustCompile(t, `ip="1.2.3.4/32"`)
+	or, ok := got["$or"].([]bson.M)
+	if !ok || len(or) != 2 {
+		t.Fatalf("expected multi-path $or, got %v", got)
+	}
+}
+
+func TestCompile_Bool(t *testing.T) {
+	if got := mustCompile(t, `cdn=true`); got["cdn"] != true {
+		t.Fatalf("unexpected bool filter %v", got)
+	}
+	if got := mustCompile(t, `cdn=false`); !reflect.DeepEqual(got["cdn"], bson.M{"$ne": true}) {
+		t.Fatalf("unexpected bool filter %v", got)
+	}
+}
+
+func TestCompile_PlainTextIsPhrase(t *testing.T) {
+	got := mustCompile(t, `nginx login`)
+	want := bson.M{"$or": []bson.M{
+		{"host": bson.M{"$regex": "nginx login", "$options": "i"}},
+		{"title": bson.M{"$regex": "nginx login", "$options": "i"}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCompile_ErrorsCarryPosition(t *testing.T) {
+	cases := []struct {
+		query string
+		pos   int
+	}{
+		{`port=80 && foo=bar`, 11},
+		{`(port=80`, 8},
+		{`title="abc`, 6},
+		{`port=abc`, 5},
+		{`title>3`, 0},
+		{`port=80 &&`, 10},
+		{`title=~"(["`, 7},
+	}
+	for _, tc := range cases {
+		_, err := testSchema.Compile(tc.query)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("%q: expected SyntaxError, got %v", tc.query, err)
+		}
+		if syntaxErr.Pos != tc.pos {
+			t.Fatalf("%q: expected error at %d, got %d (%s)", tc.query, tc.pos, syntaxErr.Pos, syntaxErr.Msg)
+		}
+	}
+}
+
+func TestCompile_IPv6CIDR(t *testing.T) {
+	got := mustCompile(t, `host="2001:db8::/32"`)
+	m, ok := got["host"].(bson.M)
+	if !ok {
+		t.Fatalf("unexpected filter %v", got)
+	}
+	re := regexp.MustCompile(m["$regex"].(string))
+	for _, addr := range []string{"2001:db8::1", "2001:db8:1::", "2001:db8:ffff:1:2:3:4:5"} {
+		if !re.MatchString(addr) {
+			t.Errorf("%s should match 2001:db8::/32", addr)
+		}
+	}
+	for _, addr := range []string{"2001:db9::1", "2001:db8a::1", "2001::db8:1", "10.0.0.1"} {
+		if re.MatchString(addr) {
+			t.Errorf("%s should not match 2001:db8::/32", addr)
+		}
+	}
+
+	if _, err := testSchema.Compile(`ip="2001:db8::/33"`); err == nil {
+		t.Fatal("expected error for unaligned wide IPv6 prefix")
+	}
+}
+
+// 用随机地址（偏向含 0 段）核对正则与 net.IPNet.Contains 的结果一致
+func TestCompile_IPv6CIDRMatchesContains(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randIP := func() net.IP {
+		ip := make(net.IP, net.IPv6len)
+		for i := 0; i < 8; i++ {
+			if rng.Intn(2) == 0 {
+				continue
+			}
+			v := rng.Intn(3)
+			ip[2*i], ip[2*i+1] = byte(v>>8), byte(v)
+		}
+		return ip
+	}
+	for i := 0; i < 200; i++ {
+		ones := []int{0, 16, 32, 48, 56, 64, 120, 128}[rng.Intn(8)]
+		_, ipNet, _ := net.ParseCIDR(randIP().String() + "/" + strconv.Itoa(ones))
+		pattern, err := cidr6Pattern(&Condition{}, ipNet)
+		if err != nil {
+			t.Fatalf("cidr6Pattern(%s): %v", ipNet, err)
+		}
+		re := regexp.MustCompile(pattern)
+		for k := 0; k < 50; k++ {
+			ip := randIP()
+			if k%2 == 0 {
+				copy(ip, ipNet.IP[:ones/8])
+			}
+			if got, want := re.MatchString(ip.String()), ipNet.Contains(ip); got != want {
+				t.Fatalf("%s against %s: regex=%v contains=%v (pattern %s)", ip, ipNet, got, want, pattern)
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"bytes"
+	"fmt"
 	"net"
 	"strings"
 )
@@ -148,15 +150,8 @@ func ExtractHostFromURL(url string) string {
 		url = url[:idx]
 	}
 
-	// 移除端口
-	if idx := strings.LastIndex(url, ":"); idx > 0 {
-		// 确保不是IPv6地址
-		if !strings.Contains(url, "[") {
-			url = url[:idx]
-		}
-	}
-
-	return url
+	host, _ := SplitHostPort(url)
+	return host
 }
 
 // ExtractPortFromURL 从URL中提取端口
@@ -172,21 +167,9 @@ func ExtractPortFromURL(url string) int {
 	}
 
 	// 提取端口
-	if idx := strings.LastIndex(url, ":"); idx > 0 {
-		// 确保不是IPv6地址
-		if !strings.Contains(url, "[") {
-			portStr := url[idx+1:]
-			var port int
-			for _, c := range portStr {
-				if c >= '0' && c <= '9' {
-					port = port*10 + int(c-'0')
-				} else {
-					break
-				}
-			}
-			if port > 0 && port <= 65535 {
-				return port
-			}
+	if _, portStr := SplitHostPort(url); portStr != "" {
+		if port := parsePort(portStr); port > 0 {
+			return port
 		}
 	}
 
@@ -211,6 +194,11 @@ func SplitHostPort(hostport string) (host string, port string) {
 		return hostport, ""
 	}
 
+	// 不带括号的IPv6地址没有端口
+	if strings.Count(hostport, ":") > 1 {
+		return hostport, ""
+	}
+
 	// IPv4/域名格式
 	if idx := strings.LastIndex(hostport, ":"); idx > 0 {
 		return hostport[:idx], hostport[idx+1:]
@@ -218,3 +206,116 @@ func SplitHostPort(hostport string) (host string, port string) {
 
 	return hostport, ""
 }
+
+// NormalizeHost 标准化主机：去掉IPv6方括号，IP地址转为规范形式（如 2001:DB8:0::1 -> 2001:db8::1），域名原样返回
+func NormalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+	return host
+}
+
+// IPv6前缀展开策略：主机位不超过 IPv6FullExpandBits 的前缀完整展开；
+// 不大于 /64 的前缀按低位地址抽样（::1 ~ ::100，常见的手工分配地址）；更大的前缀拒绝展开
+const (
+	IPv6FullExpandBits = 8    // /120 及更小的前缀完整展开，最多256个地址
+	IPv6MinPrefixLen   = 64   // 允许展开的最大IPv6前缀
+	IPv6SampleHosts    = 256  // 抽样时每个前缀取的低位地址数
+	IPv6MaxRangeSize   = 4096 // IPv6地址范围允许展开的最大地址数
+)
+
+// ExpandIPv6Prefix 按IPv6前缀展开策略展开前缀，前缀过大时返回错误
+func ExpandIPv6Prefix(ipnet *net.IPNet) ([]string, error) {
+	ones, bits := ipnet.Mask.Size()
+	if bits != net.IPv6len*8 {
+		return nil, fmt.Errorf("not an IPv6 prefix: %s", ipnet)
+	}
+	if ones < IPv6MinPrefixLen {
+		return nil, fmt.Errorf("IPv6前缀 /%d 过大，最大支持 /%d", ones, IPv6MinPrefixLen)
+	}
+
+	base := ipnet.IP.Mask(ipnet.Mask).To16()
+	var ips []string
+	if bits-ones <= IPv6FullExpandBits {
+		last := lastIP(ipnet)
+		for ip := cloneIP(base); ; incrementIP(ip) {
+			ips = append(ips, ip.String())
+			if ip.Equal(last) {
+				return ips, nil
+			}
+		}
+	}
+
+	// 抽样低位地址，主机位至少9位，可容纳 1 ~ IPv6SampleHosts
+	for i := 1; i <= IPv6SampleHosts; i++ {
+		ip := cloneIP(base)
+		ip[14] |= byte(i >> 8)
+		ip[15] |= byte(i)
+		ips = append(ips, ip.String())
+	}
+	return ips, nil
+}
+
+// ExpandIPRange 展开IP地址范围（含首尾），要求首尾地址族相同且起始不大于结束；
+// IPv6范围最多 IPv6MaxRangeSize 个地址，max > 0 时超过 max 截断并返回错误
+func ExpandIPRange(start, end net.IP, max int) ([]string, error) {
+	if start == nil || end == nil {
+		return nil, fmt.Errorf("无效的IP地址")
+	}
+	if (start.To4() == nil) != (end.To4() == nil) {
+		return nil, fmt.Errorf("起始IP和结束IP的地址族不一致")
+	}
+	if CompareIP(start, end) > 0 {
+		return nil, fmt.Errorf("起始IP不能大于结束IP")
+	}
+
+	isIPv6 := start.To4() == nil
+	var ips []string
+	for ip := cloneIP(start.To16()); ; incrementIP(ip) {
+		if isIPv6 && len(ips) >= IPv6MaxRangeSize {
+			return nil, fmt.Errorf("IPv6地址范围过大，最多支持%d个地址", IPv6MaxRangeSize)
+		}
+		if max > 0 && len(ips) >= max {
+			return ips, fmt.Errorf("IP范围包含的IP数量过多（>%d），已截断", max)
+		}
+		ips = append(ips, ip.String())
+		if ip.Equal(end) {
+			return ips, nil
+		}
+	}
+}
+
+// CompareIP 比较两个IP的大小，IPv4按IPv4映射地址比较
+func CompareIP(a, b net.IP) int {
+	return bytes.Compare(a.To16(), b.To16())
+}
+
+func cloneIP(ip net.IP) net.IP {
+	c := make(net.IP, len(ip))
+	copy(c, ip)
+	return c
+}
+
+func incrementIP(ip net.IP) {
+	for j := len(ip) - 1; j >= 0; j-- {
+		ip[j]++
+		if ip[j] > 0 {
+			break
+		}
+	}
+}
+
+// lastIP 返回前缀内的最后一个地址
+func lastIP(ipnet *net.IPNet) net.IP {
+	ip := cloneIP(ipnet.IP.To16())
+	mask := ipnet.Mask
+	off := len(ip) - len(mask)
+	for i := range mask {
+		ip[off+i] |= ^mask[i]
+	}
+	return ip
+}
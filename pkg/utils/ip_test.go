@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParseTargetIPv6(t *testing.T) {
+	cases := []struct {
+		target string
+		host   string
+		port   int
+		path   string
+	}{
+		{"2001:db8::1", "2001:db8::1", 0, ""},
+		{"2001:DB8:0::1", "2001:db8::1", 0, ""},
+		{"[2001:db8::1]", "2001:db8::1", 0, ""},
+		{"[2001:db8::1]:8080", "2001:db8::1", 8080, ""},
+		{"https://[::1]:8443/admin", "::1", 8443, "/admin"},
+		{"http://[::1]/", "::1", 0, "/"},
+		{"192.168.1.1:22", "192.168.1.1", 22, ""},
+		{"example.com:8080/x", "example.com", 8080, "/x"},
+	}
+	for _, c := range cases {
+		info := ParseTarget(c.target)
+		if info.Host != c.host || info.Port != c.port || info.Path != c.path || info.HasPort != (c.port > 0) {
+			t.Errorf("ParseTarget(%q) = host %q port %d path %q, want %q %d %q", c.target, info.Host, info.Port, info.Path, c.host, c.port, c.path)
+		}
+	}
+	if !ParseTarget("[2001:db8::1]:80").IsIP {
+		t.Errorf("bracketed IPv6 should be detected as IP")
+	}
+}
+
+func TestBuildTargetWithPortAndSplit(t *testing.T) {
+	cases := []struct {
+		host string
+		port int
+		want string
+	}{
+		{"2001:db8::1", 443, "[2001:db8::1]:443"},
+		{"[2001:db8::1]", 443, "[2001:db8::1]:443"},
+		{"2001:db8::1", 0, "2001:db8::1"},
+		{"10.0.0.1", 22, "10.0.0.1:22"},
+		{"example.com", 80, "example.com:80"},
+	}
+	for _, c := range cases {
+		if got := BuildTargetWithPort(c.host, c.port); got != c.want {
+			t.Errorf("BuildTargetWithPort(%q, %d) = %q, want %q", c.host, c.port, got, c.want)
+		}
+	}
+
+	if got := BracketHost("2001:db8::1"); got != "[2001:db8::1]" {
+		t.Errorf("BracketHost() = %q", got)
+	}
+	if got := BracketHost("example.com"); got != "example.com" {
+		t.Errorf("BracketHost() = %q", got)
+	}
+
+	splits := map[string][2]string{
+		"[::1]:8080":  {"::1", "8080"},
+		"[::1]":       {"::1", ""},
+		"2001:db8::1": {"2001:db8::1", ""},
+		"a.com:80":    {"a.com", "80"},
+	}
+	for in, want := range splits {
+		if host, port := SplitHostPort(in); host != want[0] || port != want[1] {
+			t.Errorf("SplitHostPort(%q) = %q, %q, want %q, %q", in, host, port, want[0], want[1])
+		}
+	}
+	if got := ExtractHostFromURL("https://[2001:db8::1]:8443/x"); got != "2001:db8::1" {
+		t.Errorf("ExtractHostFromURL() = %q", got)
+	}
+	if got := ExtractPortFromURL("https://[2001:db8::1]:8443/x"); got != 8443 {
+		t.Errorf("ExtractPortFromURL() = %d", got)
+	}
+}
+
+func TestExpandIPv6Prefix(t *testing.T) {
+	expand := func(cidr string) ([]string, error) {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ExpandIPv6Prefix(ipnet)
+	}
+
+	ips, err := expand("2001:db8::/126")
+	if err != nil || strings.Join(ips, ",") != "2001:db8::,2001:db8::1,2001:db8::2,2001:db8::3" {
+		t.Errorf("/126 = %v, %v", ips, err)
+	}
+	if ips, err := expand("2001:db8::ff00/120"); err != nil || len(ips) != 256 || ips[255] != "2001:db8::ffff" {
+		t.Errorf("/120 = %d ips, %v", len(ips), err)
+	}
+
+	ips, err = expand("2001:db8:1:2::/64")
+	if err != nil || len(ips) != IPv6SampleHosts {
+		t.Fatalf("/64 = %d ips, %v", len(ips), err)
+	}
+	if ips[0] != "2001:db8:1:2::1" || ips[len(ips)-1] != "2001:db8:1:2::100" {
+		t.Errorf("/64 samples = %s .. %s", ips[0], ips[len(ips)-1])
+	}
+
+	if _, err := expand("2001:db8::/48"); err == nil {
+		t.Errorf("/48 should be refused")
+	}
+}
+
+func TestExpandIPRange(t *testing.T) {
+	ips, err := ExpandIPRange(net.ParseIP("2001:db8::fe"), net.ParseIP("2001:db8::101"), 0)
+	if err != nil || strings.Join(ips, ",") != "2001:db8::fe,2001:db8::ff,2001:db8::100,2001:db8::101" {
+		t.Errorf("IPv6 range = %v, %v", ips, err)
+	}
+	if ips, err := ExpandIPRange(net.ParseIP("10.0.0.254"), net.ParseIP("10.0.1.1"), 0); err != nil || len(ips) != 4 || ips[3] != "10.0.1.1" {
+		t.Errorf("IPv4 range = %v, %v", ips, err)
+	}
+	if ips, err := ExpandIPRange(net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.100"), 10); err == nil || len(ips) != 10 {
+		t.Errorf("truncated range = %d ips, %v", len(ips), err)
+	}
+	if _, err := ExpandIPRange(net.ParseIP("2001:db8::"), net.ParseIP("2001:db8::1:0"), 0); err == nil {
+		t.Errorf("huge IPv6 range should be refused")
+	}
+	if _, err := ExpandIPRange(net.ParseIP("10.0.0.5"), net.ParseIP("10.0.0.1"), 0); err == nil {
+		t.Errorf("reversed range should be refused")
+	}
+	if _, err := ExpandIPRange(net.ParseIP("10.0.0.1"), net.ParseIP("::1"), 0); err == nil {
+		t.Errorf("mixed family range should be refused")
+	}
+}
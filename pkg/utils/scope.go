@@ -0,0 +1,298 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScopeWindow 允许扫描的时间窗口
+type ScopeWindow struct {
+	Days  []int  `json:"days,omitempty"` // 星期几，0=周日 ... 6=周六，为空表示每天
+	Start string `json:"start"`          // 开始时间 HH:MM
+	End   string `json:"end"`            // 结束时间 HH:MM，小于开始时间表示跨天
+}
+
+// ScopeRules 扫描范围定义（白名单）
+type ScopeRules struct {
+	Cidrs    []string      `json:"cidrs,omitempty"`    // 允许的IP/网段
+	Domains  []string      `json:"domains,omitempty"`  // 允许的域名后缀，example.com 同时匹配其子域名
+	Hosts    []string      `json:"hosts,omitempty"`    // 明确允许的主机（域名或IP）
+	Windows  []ScopeWindow `json:"windows,omitempty"`  // 允许扫描的时间窗口，为空表示任意时间
+	Timezone string        `json:"timezone,omitempty"` // 时间窗口所在时区，默认本地时区
+	StrictIP bool          `json:"strictIp,omitempty"` // 域名解析出的IP和CNAME也必须在范围内
+}
+
+// scopeWindow 解析后的时间窗口，时间以当天分钟数表示
+type scopeWindow struct {
+	days  map[time.Weekday]bool
+	start int
+	end   int
+}
+
+// ScopeMatcher 扫描范围匹配器
+// 与黑名单相反，只有命中规则的目标才允许扫描
+type ScopeMatcher struct {
+	hosts    map[string]bool
+	suffixes []string
+	networks []*net.IPNet
+	windows  []scopeWindow
+	location *time.Location
+	strictIP bool
+}
+
+// NewScopeMatcher 创建扫描范围匹配器
+func NewScopeMatcher(rules *ScopeRules) (*ScopeMatcher, error) {
+	m := &ScopeMatcher{
+		hosts:    make(map[string]bool),
+		location: time.Local,
+		strictIP: rules.StrictIP,
+	}
+
+	for _, c := range rules.Cidrs {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if !strings.Contains(c, "/") {
+			ip := net.ParseIP(c)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid cidr %q", c)
+			}
+			if ip.To4() != nil {
+				c += "/32"
+			} else {
+				c += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr %q", c)
+		}
+		m.networks = append(m.networks, ipNet)
+	}
+
+	for _, d := range rules.Domains {
+		d = strings.Trim(strings.ToLower(strings.TrimSpace(d)), ".")
+		d = strings.TrimPrefix(d, "*.")
+		if d != "" {
+			m.suffixes = append(m.suffixes, d)
+		}
+	}
+
+	for _, h := range rules.Hosts {
+		h = strings.ToLower(extractHost(strings.TrimSpace(h)))
+		if h != "" {
+			m.hosts[h] = true
+		}
+	}
+
+	if rules.Timezone != "" {
+		loc, err := time.LoadLocation(rules.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q", rules.Timezone)
+		}
+		m.location = loc
+	}
+
+	for _, w := range rules.Windows {
+		sw, err := parseScopeWindow(w)
+		if err != nil {
+			return nil, err
+		}
+		m.windows = append(m.windows, sw)
+	}
+
+	return m, nil
+}
+
+// parseScopeWindow 解析时间窗口
+func parseScopeWindow(w ScopeWindow) (scopeWindow, error) {
+	sw := scopeWindow{}
+	var err error
+	if sw.start, err = parseClock(w.Start); err != nil {
+		return sw, err
+	}
+	if sw.end, err = parseClock(w.End); err != nil {
+		return sw, err
+	}
+	if len(w.Days) > 0 {
+		sw.days = make(map[time.Weekday]bool, len(w.Days))
+		for _, d := range w.Days {
+			if d < 0 || d > 6 {
+				return sw, fmt.Errorf("invalid weekday %d", d)
+			}
+			sw.days[time.Weekday(d)] = true
+		}
+	}
+	return sw, nil
+}
+
+// parseClock 解析 HH:MM 为当天分钟数
+func parseClock(s string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", s)
+	}
+	h, err1 := strconv.Atoi(parts[0])
+	min, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || h < 0 || h > 24 || min < 0 || min > 59 || (h == 24 && min != 0) {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", s)
+	}
+	return h*60 + min, nil
+}
+
+// IsEmpty 是否没有任何目标规则（此时不限制目标）
+func (m *ScopeMatcher) IsEmpty() bool {
+	return m == nil || (len(m.hosts) == 0 && len(m.suffixes) == 0 && len(m.networks) == 0)
+}
+
+// InScope 检查目标是否在扫描范围内
+// target 可以是域名、IP、CIDR、host:port 或URL；CIDR 目标必须整体落在某个允许的网段内
+func (m *ScopeMatcher) InScope(target string) bool {
+	if m.IsEmpty() {
+		return true
+	}
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return false
+	}
+
+	if strings.Contains(target, "/") && !strings.Contains(target, "://") {
+		if _, ipNet, err := net.ParseCIDR(target); err == nil {
+			return m.containsNetwork(ipNet)
+		}
+	}
+	// IP范围 192.168.1.1-192.168.1.100
+	if idx := strings.Index(target, "-"); idx > 0 {
+		start, end := net.ParseIP(target[:idx]), net.ParseIP(target[idx+1:])
+		if start != nil && end != nil {
+			return m.matchIP(start) && m.matchIP(end) && m.sameNetwork(start, end)
+		}
+	}
+
+	return m.matchHost(strings.ToLower(extractHost(target)))
+}
+
+// AssetInScope 检查发现的资产是否在扫描范围内
+// 开启 StrictIP 时，域名资产解析出的IP和CNAME也必须在范围内
+func (m *ScopeMatcher) AssetInScope(host string, ips []string, cname string) bool {
+	if m.IsEmpty() {
+		return true
+	}
+	if !m.InScope(host) {
+		return false
+	}
+	if !m.strictIP {
+		return true
+	}
+	for _, ip := range ips {
+		if !m.InScope(ip) {
+			return false
+		}
+	}
+	if cname != "" && !m.InScope(strings.TrimSuffix(cname, ".")) {
+		return false
+	}
+	return true
+}
+
+// AllowedAt 检查给定时间是否在允许扫描的时间窗口内，未配置窗口时总是允许
+func (m *ScopeMatcher) AllowedAt(t time.Time) bool {
+	if m == nil || len(m.windows) == 0 {
+		return true
+	}
+	t = t.In(m.location)
+	minute := t.Hour()*60 + t.Minute()
+	yesterday := t.AddDate(0, 0, -1).Weekday()
+	for _, w := range m.windows {
+		if w.start <= w.end {
+			if minute >= w.start && minute < w.end && w.matchDay(t.Weekday()) {
+				return true
+			}
+			continue
+		}
+		// 跨天窗口，如 22:00-06:00，凌晨部分归属前一天
+		if minute >= w.start && w.matchDay(t.Weekday()) {
+			return true
+		}
+		if minute < w.end && w.matchDay(yesterday) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDay 星期是否匹配
+func (w scopeWindow) matchDay(d time.Weekday) bool {
+	return len(w.days) == 0 || w.days[d]
+}
+
+// FilterTargets 拆分目标列表为范围内和范围外两部分
+func (m *ScopeMatcher) FilterTargets(targets []string) (inScope, outOfScope []string) {
+	for _, target := range targets {
+		if m.InScope(target) {
+			inScope = append(inScope, target)
+		} else {
+			outOfScope = append(outOfScope, target)
+		}
+	}
+	return inScope, outOfScope
+}
+
+// matchHost 匹配主机（IP或域名）
+func (m *ScopeMatcher) matchHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	if m.hosts[host] {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return m.matchIP(ip)
+	}
+	host = strings.TrimSuffix(host, ".")
+	for _, suffix := range m.suffixes {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIP 匹配IP
+func (m *ScopeMatcher) matchIP(ip net.IP) bool {
+	if m.hosts[ip.String()] {
+		return true
+	}
+	for _, n := range m.networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsNetwork 网段是否整体落在某个允许的网段内
+func (m *ScopeMatcher) containsNetwork(target *net.IPNet) bool {
+	tOnes, tBits := target.Mask.Size()
+	for _, n := range m.networks {
+		ones, bits := n.Mask.Size()
+		if bits == tBits && ones <= tOnes && n.Contains(target.IP) {
+			return true
+		}
+	}
+	return tOnes == tBits && m.hosts[target.IP.String()]
+}
+
+// sameNetwork IP范围的起止地址是否落在同一个允许的网段内
+func (m *ScopeMatcher) sameNetwork(start, end net.IP) bool {
+	for _, n := range m.networks {
+		if n.Contains(start) && n.Contains(end) {
+			return true
+		}
+	}
+	return start.Equal(end)
+}
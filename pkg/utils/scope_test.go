@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScopeMatcherInScope(t *testing.T) {
+	m, err := NewScopeMatcher(&ScopeRules{
+		Cidrs:   []string{"10.0.0.0/16", "192.168.1.5"},
+		Domains: []string{"*.example.com"},
+		Hosts:   []string{"partner.org"},
+	})
+	if err != nil {
+		t.Fatalf("NewScopeMatcher() error: %v", err)
+	}
+
+	cases := map[string]bool{
+		"10.0.3.4":                     true,
+		"10.1.0.1":                     false,
+		"192.168.1.5:8080":             true,
+		"10.0.1.0/24":                  true,
+		"10.0.0.0/8":                   false,
+		"10.0.0.1-10.0.0.20":           true,
+		"example.com":                  true,
+		"https://api.example.com/path": true,
+		"badexample.com":               false,
+		"partner.org":                  true,
+		"www.partner.org":              false,
+	}
+	for target, want := range cases {
+		if got := m.InScope(target); got != want {
+			t.Errorf("InScope(%q) = %v, want %v", target, got, want)
+		}
+	}
+
+	if !m.AssetInScope("a.example.com", []string{"8.8.8.8"}, "a.cdn.net") {
+		t.Errorf("non-strict scope should ignore resolved IPs")
+	}
+	m.strictIP = true
+	if m.AssetInScope("a.example.com", []string{"10.0.0.1"}, "a.cdn.net") {
+		t.Errorf("strict scope should reject out-of-scope CNAME")
+	}
+	if !m.AssetInScope("a.example.com", []string{"10.0.0.1"}, "b.example.com.") {
+		t.Errorf("strict scope should accept in-scope IPs and CNAME")
+	}
+}
+
+func TestScopeMatcherAllowedAt(t *testing.T) {
+	m, err := NewScopeMatcher(&ScopeRules{
+		Timezone: "UTC",
+		Windows: []ScopeWindow{
+			{Days: []int{1, 2, 3, 4, 5}, Start: "22:00", End: "06:00"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewScopeMatcher() error: %v", err)
+	}
+
+	// 2024-01-01 为周一
+	cases := map[string]bool{
+		"2024-01-01T23:00:00Z": true,  // 周一夜间
+		"2024-01-02T05:59:00Z": true,  // 周一窗口延续到周二凌晨
+		"2024-01-02T06:00:00Z": false, // 窗口结束
+		"2024-01-01T03:00:00Z": false, // 周日窗口未配置
+		"2024-01-06T23:00:00Z": false, // 周六
+	}
+	for ts, want := range cases {
+		tm, _ := time.Parse(time.RFC3339, ts)
+		if got := m.AllowedAt(tm); got != want {
+			t.Errorf("AllowedAt(%s) = %v, want %v", ts, got, want)
+		}
+	}
+
+	if _, err := NewScopeMatcher(&ScopeRules{Windows: []ScopeWindow{{Start: "25:00", End: "06:00"}}}); err == nil {
+		t.Errorf("invalid window should fail")
+	}
+}
@@ -118,7 +118,8 @@ type TargetInfo struct {
 }
 
 // ParseTarget 解析单个目标，提取主机、端口、协议、路径等信息
-// 支持格式：http://example.com/admin/, example.com:8080/path, example.com
+// 支持格式：http://example.com/admin/, example.com:8080/path, example.com, [2001:db8::1]:8080, 2001:db8::1
+// IP地址主机统一为规范形式，IPv6不带方括号
 func ParseTarget(target string) *TargetInfo {
 	target = strings.TrimSpace(target)
 	info := &TargetInfo{Raw: target}
@@ -138,8 +139,17 @@ func ParseTarget(target string) *TargetInfo {
 		target = target[:idx]
 	}
 
-	// 解析端口
-	if idx := strings.LastIndex(target, ":"); idx > 0 {
+	// 解析端口，IPv6地址必须用方括号包裹才能带端口
+	if strings.HasPrefix(target, "[") {
+		host, portStr := SplitHostPort(target)
+		if port := parsePort(portStr); port > 0 {
+			info.Port = port
+			info.HasPort = true
+		}
+		target = NormalizeHost(host)
+	} else if net.ParseIP(target) != nil {
+		target = NormalizeHost(target)
+	} else if idx := strings.LastIndex(target, ":"); idx > 0 {
 		portStr := target[idx+1:]
 		if port := parsePort(portStr); port > 0 {
 			info.Port = port
@@ -198,10 +208,18 @@ func ParseTargetsWithPorts(targets string) (withPort []string, withoutPort []str
 	return
 }
 
-// BuildTargetWithPort 构建带端口的目标字符串
+// BuildTargetWithPort 构建带端口的目标字符串，IPv6地址使用方括号: [2001:db8::1]:8080
 func BuildTargetWithPort(host string, port int) string {
 	if port > 0 {
-		return host + ":" + portToString(port)
+		return BracketHost(host) + ":" + portToString(port)
+	}
+	return host
+}
+
+// BracketHost 为IPv6地址加方括号，用于拼接URL: 2001:db8::1 -> [2001:db8::1]
+func BracketHost(host string) string {
+	if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		return "[" + host + "]"
 	}
 	return host
 }
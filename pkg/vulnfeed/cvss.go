@@ -0,0 +1,85 @@
+package vulnfeed
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// CVSS3BaseScore 根据 CVSS v3.0/v3.1 向量计算基础分，OSV 只提供向量不提供分数
+func CVSS3BaseScore(vector string) (float64, error) {
+	if !strings.HasPrefix(vector, "CVSS:3.") {
+		return 0, fmt.Errorf("not a cvss v3 vector: %s", vector)
+	}
+	metrics := make(map[string]string)
+	for _, part := range strings.Split(vector, "/")[1:] {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) == 2 {
+			metrics[kv[0]] = kv[1]
+		}
+	}
+
+	weights := map[string]map[string]float64{
+		"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+		"AC": {"L": 0.77, "H": 0.44},
+		"UI": {"N": 0.85, "R": 0.62},
+		"C":  {"H": 0.56, "L": 0.22, "N": 0},
+		"I":  {"H": 0.56, "L": 0.22, "N": 0},
+		"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	}
+	values := make(map[string]float64)
+	for name, table := range weights {
+		w, ok := table[metrics[name]]
+		if !ok {
+			return 0, fmt.Errorf("invalid cvss metric %s in %s", name, vector)
+		}
+		values[name] = w
+	}
+
+	scope := metrics["S"]
+	if scope != "U" && scope != "C" {
+		return 0, fmt.Errorf("invalid cvss scope in %s", vector)
+	}
+	var pr float64
+	switch metrics["PR"] {
+	case "N":
+		pr = 0.85
+	case "L":
+		pr = 0.62
+		if scope == "C" {
+			pr = 0.68
+		}
+	case "H":
+		pr = 0.27
+		if scope == "C" {
+			pr = 0.5
+		}
+	default:
+		return 0, fmt.Errorf("invalid cvss metric PR in %s", vector)
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	var impact float64
+	if scope == "U" {
+		impact = 6.42 * iss
+	} else {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	exploitability := 8.22 * values["AV"] * values["AC"] * pr * values["UI"]
+	if impact <= 0 {
+		return 0, nil
+	}
+	if scope == "U" {
+		return roundUp(math.Min(impact+exploitability, 10)), nil
+	}
+	return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+}
+
+// roundUp CVSS v3.1 规范中的向上取整到一位小数，避免浮点误差
+func roundUp(x float64) float64 {
+	i := int64(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
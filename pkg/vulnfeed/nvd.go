@@ -0,0 +1,173 @@
+package vulnfeed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cscan/pkg/cpe"
+)
+
+// NVD 2.0 API / 数据下载的 JSON 结构（仅保留用到的字段）
+type nvdFeed struct {
+	Vulnerabilities []struct {
+		CVE nvdCVE `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+type nvdCVE struct {
+	Id           string `json:"id"`
+	Published    string `json:"published"`
+	LastModified string `json:"lastModified"`
+	VulnStatus   string `json:"vulnStatus"`
+	Descriptions []struct {
+		Lang  string `json:"lang"`
+		Value string `json:"value"`
+	} `json:"descriptions"`
+	Metrics struct {
+		CvssMetricV40 []nvdMetric `json:"cvssMetricV40"`
+		CvssMetricV31 []nvdMetric `json:"cvssMetricV31"`
+		CvssMetricV30 []nvdMetric `json:"cvssMetricV30"`
+		CvssMetricV2  []nvdMetric `json:"cvssMetricV2"`
+	} `json:"metrics"`
+	Configurations []struct {
+		Nodes []struct {
+			Operator string `json:"operator"`
+			Negate   bool   `json:"negate"`
+			CpeMatch []struct {
+				Vulnerable            bool   `json:"vulnerable"`
+				Criteria              string `json:"criteria"`
+				VersionStartIncluding string `json:"versionStartIncluding"`
+				VersionStartExcluding string `json:"versionStartExcluding"`
+				VersionEndIncluding   string `json:"versionEndIncluding"`
+				VersionEndExcluding   string `json:"versionEndExcluding"`
+			} `json:"cpeMatch"`
+		} `json:"nodes"`
+	} `json:"configurations"`
+	References []struct {
+		URL string `json:"url"`
+	} `json:"references"`
+}
+
+type nvdMetric struct {
+	Type     string `json:"type"`
+	CvssData struct {
+		VectorString string  `json:"vectorString"`
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+	BaseSeverity string `json:"baseSeverity"` // v2 的严重程度在外层
+}
+
+// ParseNVD 解析 NVD 2.0 JSON（API 响应或按年份下载的数据文件）
+// 仅保留 vulnerable=true 的 CPE 匹配条件，已拒绝（Rejected）的 CVE 会被跳过
+func ParseNVD(data []byte) ([]Entry, error) {
+	var feed nvdFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("parse nvd feed failed: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(feed.Vulnerabilities))
+	for _, v := range feed.Vulnerabilities {
+		c := v.CVE
+		if c.Id == "" || strings.EqualFold(c.VulnStatus, "Rejected") {
+			continue
+		}
+		e := Entry{
+			VulnId:    c.Id,
+			CveId:     c.Id,
+			Source:    SourceNVD,
+			Published: c.Published,
+			Modified:  c.LastModified,
+		}
+		for _, d := range c.Descriptions {
+			if d.Lang == "en" {
+				e.Summary = d.Value
+				break
+			}
+		}
+		if e.Summary == "" && len(c.Descriptions) > 0 {
+			e.Summary = c.Descriptions[0].Value
+		}
+
+		// 优先使用 CVSS v3.1，其次 v3.0、v4.0、v2；同一版本优先 NVD 的 Primary 评分
+		for _, metrics := range [][]nvdMetric{c.Metrics.CvssMetricV31, c.Metrics.CvssMetricV30, c.Metrics.CvssMetricV40, c.Metrics.CvssMetricV2} {
+			if m := pickMetric(metrics); m != nil {
+				e.CVSS = m.CvssData.BaseScore
+				e.CVSSVector = m.CvssData.VectorString
+				e.Severity = NormalizeSeverity(m.CvssData.BaseSeverity)
+				if e.Severity == SeverityUnknown {
+					e.Severity = NormalizeSeverity(m.BaseSeverity)
+				}
+				if e.Severity == SeverityUnknown {
+					e.Severity = SeverityFromScore(e.CVSS)
+				}
+				break
+			}
+		}
+		if e.Severity == "" {
+			e.Severity = SeverityUnknown
+		}
+
+		seenRef := make(map[string]bool)
+		for _, r := range c.References {
+			if r.URL != "" && !seenRef[r.URL] {
+				seenRef[r.URL] = true
+				e.References = append(e.References, r.URL)
+			}
+		}
+
+		seen := make(map[string]bool)
+		for _, conf := range c.Configurations {
+			for _, node := range conf.Nodes {
+				if node.Negate {
+					continue
+				}
+				for _, m := range node.CpeMatch {
+					if !m.Vulnerable {
+						continue
+					}
+					parsed, err := cpe.Parse(m.Criteria)
+					if err != nil || parsed.Part == cpe.PartHardware {
+						continue
+					}
+					a := Affected{
+						Part:    parsed.Part,
+						Vendor:  parsed.Vendor,
+						Product: parsed.Product,
+						Range: cpe.Range{
+							StartIncluding: m.VersionStartIncluding,
+							StartExcluding: m.VersionStartExcluding,
+							EndIncluding:   m.VersionEndIncluding,
+							EndExcluding:   m.VersionEndExcluding,
+						},
+					}
+					if parsed.HasVersion() {
+						a.Version = parsed.FullVersion()
+					}
+					key := a.Vendor + ":" + a.Product + ":" + a.Version + ":" + a.Range.StartIncluding + ":" +
+						a.Range.StartExcluding + ":" + a.Range.EndIncluding + ":" + a.Range.EndExcluding
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					e.Affected = append(e.Affected, a)
+				}
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func pickMetric(metrics []nvdMetric) *nvdMetric {
+	if len(metrics) == 0 {
+		return nil
+	}
+	for i := range metrics {
+		if metrics[i].Type == "Primary" {
+			return &metrics[i]
+		}
+	}
+	return &metrics[0]
+}
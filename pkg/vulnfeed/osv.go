@@ -0,0 +1,202 @@
+package vulnfeed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"cscan/pkg/cpe"
+)
+
+// OSV 格式（https://ossf.github.io/osv-schema/），仅保留用到的字段
+type osvVuln struct {
+	Id        string   `json:"id"`
+	Aliases   []string `json:"aliases"`
+	Summary   string   `json:"summary"`
+	Details   string   `json:"details"`
+	Published string   `json:"published"`
+	Modified  string   `json:"modified"`
+	Withdrawn string   `json:"withdrawn"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string              `json:"type"`
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+		Versions         []string       `json:"versions"`
+		DatabaseSpecific map[string]any `json:"database_specific"`
+	} `json:"affected"`
+	References []struct {
+		URL string `json:"url"`
+	} `json:"references"`
+	DatabaseSpecific map[string]any `json:"database_specific"`
+}
+
+// ParseOSV 解析单条 OSV 记录或 OSV 记录数组
+// GIT 类型的范围基于提交而非版本号，无法与指纹版本比较，会被忽略；已撤回的记录会被跳过
+func ParseOSV(data []byte) ([]Entry, error) {
+	var vulns []osvVuln
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &vulns); err != nil {
+			return nil, fmt.Errorf("parse osv feed failed: %w", err)
+		}
+	} else {
+		var v osvVuln
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("parse osv feed failed: %w", err)
+		}
+		vulns = append(vulns, v)
+	}
+
+	entries := make([]Entry, 0, len(vulns))
+	for i := range vulns {
+		v := &vulns[i]
+		if v.Id == "" || v.Withdrawn != "" {
+			continue
+		}
+		e := Entry{
+			VulnId:    v.Id,
+			Source:    SourceOSV,
+			Summary:   v.Summary,
+			Published: v.Published,
+			Modified:  v.Modified,
+			Severity:  SeverityUnknown,
+		}
+		if e.Summary == "" {
+			e.Summary = v.Details
+		}
+		if strings.HasPrefix(v.Id, "CVE-") {
+			e.CveId = v.Id
+		} else {
+			for _, alias := range v.Aliases {
+				if strings.HasPrefix(alias, "CVE-") {
+					e.CveId = alias
+					break
+				}
+			}
+		}
+
+		for _, s := range v.Severity {
+			if s.Type != "CVSS_V3" {
+				continue
+			}
+			if score, err := CVSS3BaseScore(s.Score); err == nil {
+				e.CVSS = score
+				e.CVSSVector = s.Score
+				e.Severity = SeverityFromScore(score)
+				break
+			}
+		}
+		if e.CVSS == 0 {
+			if sev, ok := v.DatabaseSpecific["severity"].(string); ok {
+				e.Severity = NormalizeSeverity(sev)
+			}
+		}
+
+		for _, r := range v.References {
+			if r.URL != "" {
+				e.References = append(e.References, r.URL)
+			}
+		}
+
+		for _, a := range v.Affected {
+			product := osvProduct(a.Package.Name)
+			if product == "" {
+				continue
+			}
+			base := Affected{
+				Part:      cpe.PartApplication,
+				Vendor:    cpe.Any,
+				Product:   product,
+				Ecosystem: a.Package.Ecosystem,
+			}
+			if e.CVSS == 0 && e.Severity == SeverityUnknown {
+				if sev, ok := a.DatabaseSpecific["severity"].(string); ok {
+					e.Severity = NormalizeSeverity(sev)
+				}
+			}
+			if len(a.Versions) > 0 {
+				item := base
+				item.Range.Versions = a.Versions
+				e.Affected = append(e.Affected, item)
+			}
+			for _, r := range a.Ranges {
+				if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+					continue
+				}
+				for _, rg := range osvRanges(r.Events) {
+					item := base
+					item.Range = rg
+					e.Affected = append(e.Affected, item)
+				}
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// osvRanges 将 OSV 事件序列转换为版本区间
+// introduced 开始一个区间，fixed/limit 以不含的方式结束，last_affected 以包含的方式结束
+func osvRanges(events []map[string]string) []cpe.Range {
+	var ranges []cpe.Range
+	var cur *cpe.Range
+	for _, ev := range events {
+		if v, ok := ev["introduced"]; ok {
+			if cur != nil {
+				ranges = append(ranges, *cur)
+			}
+			cur = &cpe.Range{}
+			if v != "0" {
+				cur.StartIncluding = v
+			}
+			continue
+		}
+		end, inclusive := "", false
+		if v, ok := ev["fixed"]; ok {
+			end = v
+		} else if v, ok := ev["limit"]; ok {
+			end = v
+		} else if v, ok := ev["last_affected"]; ok {
+			end, inclusive = v, true
+		}
+		if end == "" {
+			continue
+		}
+		if cur == nil {
+			cur = &cpe.Range{}
+		}
+		if inclusive {
+			cur.EndIncluding = end
+		} else {
+			cur.EndExcluding = end
+		}
+		ranges = append(ranges, *cur)
+		cur = nil
+	}
+	if cur != nil {
+		ranges = append(ranges, *cur)
+	}
+	return ranges
+}
+
+// osvProduct 将 OSV 包名转换为 CPE 产品名：Maven 取 artifactId，Go 模块取最后一段路径
+func osvProduct(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return cpe.Normalize(name)
+}
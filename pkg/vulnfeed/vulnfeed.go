@@ -0,0 +1,209 @@
+// Package vulnfeed 解析离线漏洞情报（NVD 2.0 JSON / OSV），转换为按产品和版本范围描述的统一条目
+package vulnfeed
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"cscan/pkg/cpe"
+)
+
+// 数据源格式
+const (
+	SourceNVD = "nvd"
+	SourceOSV = "osv"
+)
+
+// 严重程度
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityNone     = "none"
+	SeverityUnknown  = "unknown"
+)
+
+// maxUncompressedSize 单个压缩文件解压后的最大字节数，防止压缩炸弹
+const maxUncompressedSize = 1 << 30
+
+// Entry 一条漏洞情报
+type Entry struct {
+	VulnId     string     `json:"vulnId"` // 数据源中的编号，NVD 为 CVE 编号，OSV 为 GHSA/PYSEC 等
+	CveId      string     `json:"cveId"`  // 对应的 CVE 编号，没有时为空
+	Source     string     `json:"source"`
+	Summary    string     `json:"summary"`
+	CVSS       float64    `json:"cvss"`
+	CVSSVector string     `json:"cvssVector"`
+	Severity   string     `json:"severity"`
+	References []string   `json:"references"`
+	Published  string     `json:"published"`
+	Modified   string     `json:"modified"`
+	Affected   []Affected `json:"affected"`
+}
+
+// Affected 受影响的产品及版本
+// Version 非空时表示仅该版本受影响，否则按 Range 判断，两者都为空表示全部版本受影响
+type Affected struct {
+	Part      string    `json:"part"`
+	Vendor    string    `json:"vendor"` // 未知时为 *
+	Product   string    `json:"product"`
+	Ecosystem string    `json:"ecosystem,omitempty"` // OSV 生态，如 npm/PyPI/Debian
+	Version   string    `json:"version,omitempty"`
+	Range     cpe.Range `json:"range"`
+}
+
+// Matches 判断 CPE 是否命中该受影响条目，CPE 必须带有具体版本
+func (a *Affected) Matches(c *cpe.CPE) bool {
+	if !c.HasVersion() {
+		return false
+	}
+	target := &cpe.CPE{Vendor: a.Vendor, Product: a.Product}
+	if !c.SameProduct(target) {
+		return false
+	}
+	version := c.FullVersion()
+	if a.Version != "" {
+		return cpe.CompareVersions(a.Version, version) == 0
+	}
+	return a.Range.Contains(version)
+}
+
+// SeverityFromScore 根据 CVSS 分数计算严重程度（CVSS v3 定性等级）
+func SeverityFromScore(score float64) string {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityUnknown
+}
+
+// NormalizeSeverity 统一不同数据源的严重程度写法，如 GHSA 的 MODERATE
+func NormalizeSeverity(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "critical":
+		return SeverityCritical
+	case "high", "important":
+		return SeverityHigh
+	case "medium", "moderate":
+		return SeverityMedium
+	case "low":
+		return SeverityLow
+	case "none":
+		return SeverityNone
+	}
+	return SeverityUnknown
+}
+
+// Parse 解析漏洞情报文件，自动识别 gzip/zip 压缩以及 NVD/OSV 格式
+func Parse(fileName string, data []byte) ([]Entry, error) {
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("open gzip failed: %w", err)
+		}
+		defer zr.Close()
+		raw, err := readLimited(zr)
+		if err != nil {
+			return nil, err
+		}
+		return Parse(strings.TrimSuffix(fileName, ".gz"), raw)
+	}
+	if len(data) >= 4 && bytes.Equal(data[:4], []byte("PK\x03\x04")) {
+		return parseZip(data)
+	}
+	return ParseJSON(data)
+}
+
+// ParseJSON 解析单个 JSON 文档：NVD 2.0 的 vulnerabilities 列表、单条 OSV 或 OSV 数组
+func ParseJSON(data []byte) ([]Entry, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty feed")
+	}
+	if data[0] == '[' {
+		return ParseOSV(data)
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+	if _, ok := probe["vulnerabilities"]; ok {
+		return ParseNVD(data)
+	}
+	if _, ok := probe["CVE_Items"]; ok {
+		return nil, fmt.Errorf("NVD 1.1 feed is retired, please use the NVD 2.0 JSON format")
+	}
+	if _, ok := probe["affected"]; ok {
+		return ParseOSV(data)
+	}
+	if _, ok := probe["id"]; ok {
+		return ParseOSV(data)
+	}
+	return nil, fmt.Errorf("unrecognized feed format")
+}
+
+// parseZip 解析 zip 中的全部 JSON 文件，OSV 按生态导出的压缩包每个漏洞一个文件
+func parseZip(data []byte) ([]Entry, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open zip failed: %w", err)
+	}
+	var entries []Entry
+	var total int64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := strings.ToLower(f.Name)
+		if !strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".json.gz") {
+			continue
+		}
+		total += int64(f.UncompressedSize64)
+		if total > maxUncompressedSize {
+			return nil, fmt.Errorf("feed archive too large")
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s failed: %w", f.Name, err)
+		}
+		raw, err := readLimited(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		items, err := Parse(path.Base(f.Name), raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		entries = append(entries, items...)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no vulnerability found in archive")
+	}
+	return entries, nil
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, maxUncompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("decompress failed: %w", err)
+	}
+	if len(raw) > maxUncompressedSize {
+		return nil, fmt.Errorf("feed too large")
+	}
+	return raw, nil
+}
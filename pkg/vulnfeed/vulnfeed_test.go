@@ -0,0 +1,215 @@
+package vulnfeed
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"cscan/pkg/cpe"
+)
+
+const nvdSample = `{
+  "resultsPerPage": 2,
+  "vulnerabilities": [
+    {"cve": {
+      "id": "CVE-2021-41773",
+      "published": "2021-10-05T09:15:07.593",
+      "lastModified": "2023-11-07T03:39:08.310",
+      "vulnStatus": "Analyzed",
+      "descriptions": [{"lang": "es", "value": "..."}, {"lang": "en", "value": "Path traversal in Apache HTTP Server 2.4.49."}],
+      "metrics": {
+        "cvssMetricV31": [
+          {"source": "secondary@example.com", "type": "Secondary", "cvssData": {"version": "3.1", "vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N", "baseScore": 7.5, "baseSeverity": "HIGH"}},
+          {"source": "nvd@nist.gov", "type": "Primary", "cvssData": {"version": "3.1", "vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", "baseScore": 9.8, "baseSeverity": "CRITICAL"}}
+        ],
+        "cvssMetricV2": [{"type": "Primary", "cvssData": {"baseScore": 4.3}, "baseSeverity": "MEDIUM"}]
+      },
+      "configurations": [{"nodes": [{"operator": "OR", "negate": false, "cpeMatch": [
+        {"vulnerable": true, "criteria": "cpe:2.3:a:apache:http_server:2.4.49:*:*:*:*:*:*:*"},
+        {"vulnerable": true, "criteria": "cpe:2.3:a:apache:http_server:2.4.49:*:*:*:*:*:*:*"},
+        {"vulnerable": false, "criteria": "cpe:2.3:o:fedoraproject:fedora:34:*:*:*:*:*:*:*"}
+      ]}]}],
+      "references": [{"url": "https://httpd.apache.org/security/vulnerabilities_24.html"}, {"url": "https://httpd.apache.org/security/vulnerabilities_24.html"}]
+    }},
+    {"cve": {
+      "id": "CVE-2021-23017",
+      "vulnStatus": "Modified",
+      "descriptions": [{"lang": "en", "value": "A security issue in nginx resolver."}],
+      "metrics": {"cvssMetricV2": [{"type": "Primary", "cvssData": {"baseScore": 6.8}, "baseSeverity": "MEDIUM"}]},
+      "configurations": [{"nodes": [{"operator": "OR", "cpeMatch": [
+        {"vulnerable": true, "criteria": "cpe:2.3:a:f5:nginx:*:*:*:*:*:*:*:*", "versionStartIncluding": "0.6.18", "versionEndExcluding": "1.20.1"}
+      ]}]}]
+    }},
+    {"cve": {"id": "CVE-2020-0001", "vulnStatus": "Rejected"}}
+  ]
+}`
+
+func TestParseNVD(t *testing.T) {
+	entries, err := Parse("nvdcve-2.0-2021.json", []byte(nvdSample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries (rejected skipped), got %d", len(entries))
+	}
+
+	httpd := entries[0]
+	if httpd.CveId != "CVE-2021-41773" || httpd.Source != SourceNVD {
+		t.Fatalf("unexpected entry: %+v", httpd)
+	}
+	if httpd.Summary != "Path traversal in Apache HTTP Server 2.4.49." {
+		t.Errorf("summary = %q", httpd.Summary)
+	}
+	if httpd.CVSS != 9.8 || httpd.Severity != SeverityCritical {
+		t.Errorf("primary v3.1 metric expected, got %v %s", httpd.CVSS, httpd.Severity)
+	}
+	if len(httpd.References) != 1 {
+		t.Errorf("references should be deduplicated: %v", httpd.References)
+	}
+	if len(httpd.Affected) != 1 || httpd.Affected[0].Version != "2.4.49" || httpd.Affected[0].Vendor != "apache" {
+		t.Fatalf("unexpected affected: %+v", httpd.Affected)
+	}
+
+	nginx := entries[1]
+	if nginx.CVSS != 6.8 || nginx.Severity != SeverityMedium {
+		t.Errorf("v2 fallback expected, got %v %s", nginx.CVSS, nginx.Severity)
+	}
+	a := nginx.Affected[0]
+	for v, want := range map[string]bool{"1.18.0": true, "1.20.1": false, "0.6.17": false} {
+		if got := a.Matches(cpe.New("", "nginx", v)); got != want {
+			t.Errorf("nginx %s matches = %v, want %v", v, got, want)
+		}
+	}
+	if a.Matches(cpe.New("", "nginx", "")) {
+		t.Error("cpe without version must not match")
+	}
+	other, _ := cpe.Parse("cpe:2.3:a:igor_sysoev:nginx:1.18.0")
+	if a.Matches(other) {
+		t.Error("different vendor must not match")
+	}
+}
+
+const osvSample = `{
+  "id": "GHSA-jf85-cpcp-j695",
+  "aliases": ["CVE-2019-10744"],
+  "summary": "Prototype Pollution in lodash",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:H"}],
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "ranges": [
+      {"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.12"}]},
+      {"type": "GIT", "repo": "https://github.com/lodash/lodash", "events": [{"introduced": "0"}, {"fixed": "abc"}]}
+    ]
+  }, {
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.0"}, {"last_affected": "2.14.1"}, {"introduced": "2.16"}]}],
+    "versions": ["2.15.0"]
+  }],
+  "references": [{"type": "ADVISORY", "url": "https://nvd.nist.gov/vuln/detail/CVE-2019-10744"}],
+  "database_specific": {"severity": "CRITICAL"}
+}`
+
+func TestParseOSV(t *testing.T) {
+	entries, err := Parse("GHSA-jf85-cpcp-j695.json", []byte(osvSample))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.VulnId != "GHSA-jf85-cpcp-j695" || e.CveId != "CVE-2019-10744" || e.Source != SourceOSV {
+		t.Fatalf("unexpected ids: %+v", e)
+	}
+	if e.CVSS != 9.1 || e.Severity != SeverityCritical {
+		t.Errorf("cvss from vector expected 9.1 critical, got %v %s", e.CVSS, e.Severity)
+	}
+	// lodash 1 个 SEMVER 区间（GIT 忽略），log4j-core 1 个版本列表 + 2 个区间
+	if len(e.Affected) != 4 {
+		t.Fatalf("unexpected affected: %+v", e.Affected)
+	}
+
+	match := func(product, version string) bool {
+		c := cpe.New("", product, version)
+		for i := range e.Affected {
+			if e.Affected[i].Matches(c) {
+				return true
+			}
+		}
+		return false
+	}
+	cases := []struct {
+		product, version string
+		want             bool
+	}{
+		{"lodash", "4.17.11", true},
+		{"lodash", "4.17.12", false},
+		{"log4j-core", "2.14.1", true},
+		{"log4j-core", "2.15.0", true},
+		{"log4j-core", "2.15.1", false},
+		{"log4j-core", "2.17.0", true},
+		{"log4j-core", "1.2", false},
+	}
+	for _, c := range cases {
+		if got := match(c.product, c.version); got != c.want {
+			t.Errorf("%s %s matches = %v, want %v", c.product, c.version, got, c.want)
+		}
+	}
+}
+
+func TestParseCompressed(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(nvdSample))
+	zw.Close()
+	entries, err := Parse("nvdcve-2.0-2021.json.gz", gz.Bytes())
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("gzip parse: %v, %d entries", err, len(entries))
+	}
+
+	var zbuf bytes.Buffer
+	w := zip.NewWriter(&zbuf)
+	f, _ := w.Create("npm/GHSA-jf85-cpcp-j695.json")
+	f.Write([]byte(osvSample))
+	f, _ = w.Create("npm/README.txt")
+	f.Write([]byte("ignored"))
+	w.Close()
+	entries, err = Parse("npm.zip", zbuf.Bytes())
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("zip parse: %v, %d entries", err, len(entries))
+	}
+
+	if _, err := Parse("x.json", []byte(`{"CVE_Items": []}`)); err == nil {
+		t.Error("NVD 1.1 feed should be rejected")
+	}
+	if _, err := Parse("x.json", []byte(`{"foo": 1}`)); err == nil {
+		t.Error("unknown format should be rejected")
+	}
+}
+
+func TestCVSS3BaseScore(t *testing.T) {
+	cases := map[string]float64{
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H": 9.8,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H": 10.0,
+		"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N": 6.1,
+		"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H": 7.8,
+		"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N": 5.9,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N": 0,
+	}
+	for vector, want := range cases {
+		got, err := CVSS3BaseScore(vector)
+		if err != nil {
+			t.Errorf("%s: %v", vector, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s = %v, want %v", vector, got, want)
+		}
+	}
+	for _, bad := range []string{"AV:N/AC:L", "CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/C:H/I:H/A:H"} {
+		if _, err := CVSS3BaseScore(bad); err == nil {
+			t.Errorf("%s should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"cscan/model"
+	"cscan/pkg/notify"
+	"cscan/rpc/task/internal/svc"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// 已标记误报的漏洞不计入高危告警：查询条件排除 false_positive，命中的严重级别照常统计
+func TestCollectHighRiskInfoExcludesFalsePositive(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	configs := []notify.ConfigItem{{HighRiskFilter: &notify.HighRiskFilter{
+		Enabled:               true,
+		HighRiskPocSeverities: []string{"critical"},
+	}}}
+
+	collectors := map[string]func(*svc.ServiceContext) *notify.HighRiskInfo{
+		"IncrSubTaskDone": func(svcCtx *svc.ServiceContext) *notify.HighRiskInfo {
+			return NewIncrSubTaskDoneLogic(context.Background(), svcCtx).collectHighRiskInfo("ws", "task-1", configs)
+		},
+		"UpdateTask": func(svcCtx *svc.ServiceContext) *notify.HighRiskInfo {
+			return NewUpdateTaskLogic(context.Background(), svcCtx).collectHighRiskInfo("ws", "task-1", configs)
+		},
+	}
+	for name, collect := range collectors {
+		mt.Run(name, func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, "db.ws_vul", mtest.FirstBatch,
+				bson.D{{Key: "severity", Value: "critical"}, {Key: "status", Value: model.VulStatusConfirmed}},
+				bson.D{{Key: "severity", Value: "low"}},
+			))
+			info := collect(&svc.ServiceContext{MongoDB: mt.DB})
+			if info == nil || info.HighRiskVulCount != 1 || info.HighRiskVulSeverities["critical"] != 1 {
+				mt.Fatalf("unexpected high risk info %+v", info)
+			}
+
+			evt := mt.GetStartedEvent()
+			if evt == nil || evt.CommandName != "find" {
+				mt.Fatal("expected a find on the vul collection")
+			}
+			var cmd struct {
+				Filter bson.M `bson:"filter"`
+			}
+			if err := bson.Unmarshal(evt.Command, &cmd); err != nil {
+				mt.Fatal(err)
+			}
+			status, _ := cmd.Filter["status"].(bson.M)
+			if cmd.Filter["task_id"] != "task-1" || status["$ne"] != model.VulStatusFalsePositive {
+				mt.Errorf("filter should exclude false positives, got %v", cmd.Filter)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"cscan/model"
+	"cscan/pkg/notify"
+	"cscan/rpc/task/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// eventDetailLimit 事件详情最多保留的字符数
+const eventDetailLimit = 300
+
+// loadNotifyRouter 加载已启用的通知路由规则，没有规则时返回 nil
+func loadNotifyRouter(ctx context.Context, svcCtx *svc.ServiceContext) (*notify.Router, []notify.RouteRule, error) {
+	rules, err := svcCtx.NotifyRouteModel.LoadRules(ctx, svcCtx.OrganizationModel)
+	if err != nil || len(rules) == 0 {
+		return nil, nil, err
+	}
+	targets, err := svcCtx.NotifyConfigModel.Targets(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return notify.NewRouter(rules, targets, svcCtx.NotifyDigestModel), rules, nil
+}
+
+// routedTaskConfigIds 返回任务事件已由路由规则接管的通知配置
+func routedTaskConfigIds(ctx context.Context, svcCtx *svc.ServiceContext) map[string]bool {
+	_, rules, err := loadNotifyRouter(ctx, svcCtx)
+	if err != nil {
+		logx.Errorf("routedTaskConfigIds: load notify routes failed: %v", err)
+	}
+	return notify.RoutedConfigIds(rules, notify.EventTaskFinished, notify.EventTaskFailed)
+}
+
+// dispatchNotifyEvents 异步按通知路由规则分发事件，不阻塞结果保存
+func dispatchNotifyEvents(svcCtx *svc.ServiceContext, events []*notify.Event) {
+	if len(events) == 0 {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logx.Errorf("dispatchNotifyEvents panic: %v", r)
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		router, _, err := loadNotifyRouter(ctx, svcCtx)
+		if err != nil {
+			logx.Errorf("dispatchNotifyEvents: load notify routes failed: %v", err)
+			return
+		}
+		if router == nil {
+			return
+		}
+		if err := router.Dispatch(ctx, events); err != nil {
+			logx.Errorf("dispatchNotifyEvents: %v", err)
+		}
+	}()
+}
+
+// vulNotifyEvent 新发现漏洞的通知事件，证书过期类发现作为证书过期事件
+func vulNotifyEvent(workspaceId string, vul *model.Vul, asset *model.Asset) *notify.Event {
+	typ := notify.EventNewVul
+	if vul.PocFile == "tls-cert-expiring" || vul.PocFile == "tls-cert-expired" {
+		typ = notify.EventCertExpiring
+	}
+	title := vul.VulName
+	if title == "" {
+		title = vul.PocFile
+	}
+	target := vul.Url
+	if target == "" {
+		target = vul.Authority
+	}
+	e := &notify.Event{
+		Type:        typ,
+		WorkspaceId: workspaceId,
+		Severity:    vul.Severity,
+		Tags:        vul.Tags,
+		Title:       title,
+		Target:      target,
+		Detail:      truncateRunes(vul.Result, eventDetailLimit),
+		Fields: map[string]string{
+			"pocFile": vul.PocFile,
+			"cveId":   vul.CveId,
+			"taskId":  vul.TaskId,
+		},
+	}
+	if asset != nil {
+		e.OrgId = asset.OrgId
+		e.Labels = asset.Labels
+	}
+	return e
+}
+
+// assetNotifyEvent 新发现资产的通知事件
+func assetNotifyEvent(workspaceId string, asset *model.Asset) *notify.Event {
+	return &notify.Event{
+		Type:        notify.EventNewAsset,
+		WorkspaceId: workspaceId,
+		OrgId:       asset.OrgId,
+		Labels:      asset.Labels,
+		Title:       asset.Title,
+		Target:      asset.Authority,
+		Fields: map[string]string{
+			"host":    asset.Host,
+			"service": asset.Service,
+			"taskId":  asset.TaskId,
+		},
+	}
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "..."
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"cscan/model"
+	"cscan/pkg/notify"
 	"cscan/pkg/utils"
 	"cscan/rpc/task/internal/svc"
 	"cscan/rpc/task/pb"
@@ -137,6 +138,7 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 	assetModel := l.svcCtx.GetAssetModel(workspaceId)
 
 	var totalAsset, newAsset, updateAsset int32
+	var newAssetEvents []*notify.Event
 	now := time.Now()
 
 	for _, pbAsset := range in.Assets {
@@ -145,6 +147,7 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 			Authority:     pbAsset.Authority,
 			Host:          pbAsset.Host,
 			Port:          int(pbAsset.Port),
+			Transport:     pbAsset.Transport,
 			Category:      pbAsset.Category,
 			Service:       pbAsset.Service,
 			Title:         pbAsset.Title,
@@ -152,12 +155,15 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 			HttpStatus:    pbAsset.HttpStatus,
 			HttpHeader:    pbAsset.HttpHeader,
 			HttpBody:      pbAsset.HttpBody,
+			Cert:          pbAsset.Cert,
 			IconHash:      pbAsset.IconHash,
 			IconHashBytes: pbAsset.IconData,
 			Screenshot:    pbAsset.Screenshot,
 			Server:        pbAsset.Server,
 			Banner:        pbAsset.Banner,
 			IsHTTP:        pbAsset.IsHttp,
+			IsCDN:         pbAsset.IsCdn,
+			IsCloud:       pbAsset.IsCloud,
 			TaskId:        in.MainTaskId,
 			Source:        pbAsset.Source,
 			OrgId:         in.OrgId,
@@ -168,6 +174,14 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 			asset.Source = "scan"
 		}
 
+		// IPv6资产统一authority写法，保证与已有资产匹配
+		asset.NormalizeAuthority()
+
+		// 带端口的资产未标记传输层协议时为TCP
+		if asset.Port > 0 && asset.Transport == "" {
+			asset.Transport = model.TransportTCP
+		}
+
 		// 处理IP信息
 		if len(pbAsset.Ipv4) > 0 {
 			for _, ip := range pbAsset.Ipv4 {
@@ -228,8 +242,8 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 		var err error
 
 		if asset.Port > 0 {
-			// 有端口的资产，按host:port查找
-			existing, err = assetModel.FindByHostPort(l.ctx, asset.Host, asset.Port)
+			// 有端口的资产，按host:port查找，TCP与UDP同端口视为不同资产
+			existing, err = assetModel.FindByHostPortTransport(l.ctx, asset.Host, asset.Port, asset.Transport)
 		} else {
 			// 无端口的资产（如域名），按authority查找（不限制taskId）
 			existing, err = assetModel.FindByAuthorityOnly(l.ctx, asset.Authority)
@@ -251,6 +265,7 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 				continue
 			}
 			newAsset++
+			newAssetEvents = append(newAssetEvents, assetNotifyEvent(workspaceId, asset))
 		} else {
 			// 更新已存在的资产
 			// 判断是否是不同任务的更新
@@ -328,6 +343,11 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 			}
 			// 同一任务内的更新不改变 new/update 标签
 
+			// 更新证书，未采集到证书时保留原有值
+			if asset.Cert != "" {
+				updateFields["cert"] = asset.Cert
+			}
+
 			// 更新 IconData
 			if len(asset.IconHashBytes) > 0 {
 				updateFields["icon_hash_bytes"] = asset.IconHashBytes
@@ -363,6 +383,11 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 				updateFields["category"] = asset.Category
 			}
 
+			// 补全历史资产的传输层协议
+			if asset.Transport != "" {
+				updateFields["transport"] = asset.Transport
+			}
+
 			if err := assetModel.Update(l.ctx, existing.Id.Hex(), updateFields); err != nil {
 				l.Logger.Errorf("Update asset failed: %v", err)
 				continue
@@ -371,6 +396,9 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 		totalAsset++
 	}
 
+	// 新资产按通知路由规则分发，同一批结果合并通知
+	dispatchNotifyEvents(l.svcCtx, newAssetEvents)
+
 	l.Logger.Infof("SaveTaskResult: total=%d, new=%d, update=%d", totalAsset, newAsset, updateAsset)
 
 	return &pb.SaveTaskResultResp{
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"cscan/model"
+	"cscan/pkg/notify"
 	"cscan/rpc/task/internal/svc"
 	"cscan/rpc/task/pb"
 
@@ -49,6 +50,7 @@ func (l *SaveVulResultLogic) SaveVulResult(in *pb.SaveVulResultReq) (*pb.SaveVul
 
 	vulModel := l.svcCtx.GetVulModel(workspaceId)
 	var savedCount int32
+	var newVuls []*model.Vul
 
 	for _, pbVul := range in.Vuls {
 		vul := &model.Vul{
@@ -117,11 +119,22 @@ func (l *SaveVulResultLogic) SaveVulResult(in *pb.SaveVulResultReq) (*pb.SaveVul
 		// 使用Upsert避免重复
 		// Note: The Upsert method in VulModel already handles scan_count and timestamps
 		// which provides basic history tracking through first_seen_time and last_seen_time
-		if err := vulModel.Upsert(l.ctx, vul); err != nil {
+		isNew, err := vulModel.Upsert(l.ctx, vul)
+		if err != nil {
 			l.Logger.Errorf("SaveVulResult: failed to upsert vul: %v", err)
 			continue
 		}
 		savedCount++
+		if isNew {
+			newVuls = append(newVuls, vul)
+		}
+
+		// 已标记修复的漏洞再次出现时重新打开
+		if reopened, err := vulModel.ReopenFixed(l.ctx, vul.Authority, vul.PocFile, in.MainTaskId); err != nil {
+			l.Logger.Errorf("SaveVulResult: failed to reopen fixed vul: %v", err)
+		} else if reopened > 0 {
+			l.Logger.Infof("SaveVulResult: reopened %d fixed vul(s), authority=%s poc=%s", reopened, vul.Authority, vul.PocFile)
+		}
 	}
 
 	// Update assets with risk scores
@@ -180,7 +193,10 @@ func (l *SaveVulResultLogic) SaveVulResult(in *pb.SaveVulResultReq) (*pb.SaveVul
 		}
 	}
 
-	l.Logger.Infof("SaveVulResult: saved %d vulnerabilities", savedCount)
+	// 新发现的漏洞按通知路由规则分发，同一批结果合并通知
+	dispatchNotifyEvents(l.svcCtx, l.vulEvents(workspaceId, newVuls))
+
+	l.Logger.Infof("SaveVulResult: saved %d vulnerabilities, new=%d", savedCount, len(newVuls))
 
 	return &pb.SaveVulResultResp{
 		Success: true,
@@ -188,3 +204,23 @@ func (l *SaveVulResultLogic) SaveVulResult(in *pb.SaveVulResultReq) (*pb.SaveVul
 		Total:   savedCount,
 	}, nil
 }
+
+// vulEvents 构建新漏洞的通知事件，附带所属资产的组织和标签
+func (l *SaveVulResultLogic) vulEvents(workspaceId string, vuls []*model.Vul) []*notify.Event {
+	if len(vuls) == 0 {
+		return nil
+	}
+	assetModel := l.svcCtx.GetAssetModel(workspaceId)
+	assets := make(map[string]*model.Asset)
+	events := make([]*notify.Event, 0, len(vuls))
+	for _, vul := range vuls {
+		key := fmt.Sprintf("%s:%d", vul.Host, vul.Port)
+		asset, ok := assets[key]
+		if !ok {
+			asset, _ = assetModel.FindByHostPort(l.ctx, vul.Host, vul.Port)
+			assets[key] = asset
+		}
+		events = append(events, vulNotifyEvent(workspaceId, vul, asset))
+	}
+	return events
+}
@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+
+	"cscan/model"
+	"cscan/pkg/notify"
+	"cscan/rpc/task/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// diffSummaryLimit 通知摘要中每类差异最多列出的条数
+const diffSummaryLimit = 10
+
+// archiveTaskRun 任务成功结束时归档本次执行的结果快照。
+// 定时任务同时与上一次成功执行对比，返回的差异附加到通知中；非定时任务或没有上一次执行时返回 nil
+func archiveTaskRun(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId string, task *model.MainTask) *notify.ScanDiff {
+	mainTaskId := task.Id.Hex()
+
+	assets, err := svcCtx.GetAssetModel(workspaceId).FindByTaskId(ctx, mainTaskId)
+	if err != nil {
+		logx.Errorf("archiveTaskRun: query assets failed, mainTaskId=%s, error=%v", mainTaskId, err)
+		return nil
+	}
+	vuls, err := svcCtx.GetVulModel(workspaceId).Find(ctx, bson.M{
+		"task_id": mainTaskId,
+		"status":  bson.M{"$ne": model.VulStatusFalsePositive},
+	}, 0, 0)
+	if err != nil {
+		logx.Errorf("archiveTaskRun: query vuls failed, mainTaskId=%s, error=%v", mainTaskId, err)
+		return nil
+	}
+
+	historyModel := svcCtx.GetScanResultHistoryModel(workspaceId)
+	current, err := historyModel.ArchiveTaskRun(ctx, workspaceId, mainTaskId, assets, vuls)
+	if err != nil {
+		logx.Errorf("archiveTaskRun: archive failed, mainTaskId=%s, error=%v", mainTaskId, err)
+		return nil
+	}
+
+	prev, err := svcCtx.GetMainTaskModel(workspaceId).FindPreviousCronRun(ctx, task)
+	if err != nil || prev == nil {
+		return nil
+	}
+	base, err := historyModel.FindByTaskId(ctx, workspaceId, prev.Id.Hex())
+	if err != nil || len(base) == 0 {
+		// 上一次执行没有快照（功能上线前的执行），无法对比
+		return nil
+	}
+
+	diff := model.DiffTaskRuns(base, current)
+	logx.Infof("archiveTaskRun: mainTaskId=%s, baseTaskId=%s, %s", mainTaskId, prev.Id.Hex(), diff.Summary(0))
+	return &notify.ScanDiff{
+		BaseTaskId:       prev.Id.Hex(),
+		NewAssetCount:    len(diff.NewAssets),
+		GoneAssetCount:   len(diff.GoneAssets),
+		NewPortCount:     len(diff.NewPorts),
+		ClosedPortCount:  len(diff.ClosedPorts),
+		ChangeCount:      len(diff.Changes),
+		NewVulCount:      len(diff.NewVuls),
+		ResolvedVulCount: len(diff.ResolvedVuls),
+		Summary:          diff.Summary(diffSummaryLimit),
+	}
+}
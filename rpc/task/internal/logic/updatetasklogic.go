@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"cscan/model"
 	"cscan/pkg/notify"
 	"cscan/rpc/task/internal/svc"
 	"cscan/rpc/task/pb"
@@ -238,6 +239,12 @@ func (l *UpdateTaskLogic) sendTaskNotification(workspaceId, mainTaskId, status s
 	assetCount, _ := assetModel.CountByTaskId(l.ctx, mainTaskId)
 	vulCount, _ := vulModel.CountByTaskId(l.ctx, mainTaskId)
 
+	// 成功结束时归档结果快照，定时任务附带与上次执行的差异
+	var diff *notify.ScanDiff
+	if status == "SUCCESS" || status == "COMPLETED" {
+		diff = archiveTaskRun(l.ctx, l.svcCtx, workspaceId, task)
+	}
+
 	// 获取启用的通知配置
 	configs, err := l.svcCtx.NotifyConfigModel.FindEnabled(l.ctx)
 	if err != nil {
@@ -250,10 +257,18 @@ func (l *UpdateTaskLogic) sendTaskNotification(workspaceId, mainTaskId, status s
 		return
 	}
 
-	// 构建通知配置列表
+	// 构建通知配置列表，任务事件已由路由规则接管的配置不再单独发送
+	routed := routedTaskConfigIds(l.ctx, l.svcCtx)
 	var configItems []notify.ConfigItem
 	var webURL string // 用于生成报告URL
 	for _, c := range configs {
+		// 获取第一个配置的WebURL作为报告URL的基础
+		if webURL == "" && c.WebURL != "" {
+			webURL = c.WebURL
+		}
+		if routed[c.Id.Hex()] {
+			continue
+		}
 		item := notify.ConfigItem{
 			Provider:        c.Provider,
 			Config:          c.Config,
@@ -269,13 +284,10 @@ func (l *UpdateTaskLogic) sendTaskNotification(workspaceId, mainTaskId, status s
 				HighRiskPorts:        c.HighRiskFilter.HighRiskPorts,
 				HighRiskPocSeverities: c.HighRiskFilter.HighRiskPocSeverities,
 				NewAssetNotify:       c.HighRiskFilter.NewAssetNotify,
+				DiffOnly:             c.HighRiskFilter.DiffOnly,
 			}
 		}
 		configItems = append(configItems, item)
-		// 获取第一个配置的WebURL作为报告URL的基础
-		if webURL == "" && c.WebURL != "" {
-			webURL = c.WebURL
-		}
 	}
 
 	// 构建报告URL
@@ -295,6 +307,7 @@ func (l *UpdateTaskLogic) sendTaskNotification(workspaceId, mainTaskId, status s
 		VulCount:    int(vulCount),
 		WorkspaceId: workspaceId,
 		ReportURL:   reportURL,
+		Diff:        diff,
 	}
 
 	// 设置时间（处理指针类型）
@@ -317,6 +330,9 @@ func (l *UpdateTaskLogic) sendTaskNotification(workspaceId, mainTaskId, status s
 		}
 	}
 
+	// 按通知路由规则分发任务事件
+	dispatchNotifyEvents(l.svcCtx, []*notify.Event{notify.TaskEvent(result, task.OrgId, task.Tags)})
+
 	// 收集高危信息（用于高危过滤判断）
 	result.HighRiskInfo = l.collectHighRiskInfo(workspaceId, mainTaskId, configItems)
 
@@ -396,7 +412,11 @@ func (l *UpdateTaskLogic) collectHighRiskInfo(workspaceId, mainTaskId string, co
 	// 收集高危漏洞统计
 	if len(allSeverities) > 0 {
 		vulModel := l.svcCtx.GetVulModel(workspaceId)
-		vuls, err := vulModel.Find(l.ctx, bson.M{"task_id": mainTaskId}, 0, 0)
+		// 已标记误报的漏洞不再告警
+		vuls, err := vulModel.Find(l.ctx, bson.M{
+			"task_id": mainTaskId,
+			"status":  bson.M{"$ne": model.VulStatusFalsePositive},
+		}, 0, 0)
 		if err == nil {
 			severitySet := make(map[string]bool)
 			for _, s := range allSeverities {
@@ -27,6 +27,9 @@ type ServiceContext struct {
 	WorkspaceModel          *model.WorkspaceModel
 	SubfinderProviderModel  *model.SubfinderProviderModel
 	NotifyConfigModel       *model.NotifyConfigModel
+	NotifyRouteModel        *model.NotifyRouteModel
+	NotifyDigestModel       *model.NotifyDigestModel
+	OrganizationModel       *model.OrganizationModel
 	TaskRecoveryManager     *scheduler.TaskRecoveryManager // 任务恢复管理器
 }
 
@@ -79,6 +82,9 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		WorkspaceModel:          model.NewWorkspaceModel(mongoDB),
 		SubfinderProviderModel:  model.NewSubfinderProviderModel(mongoDB),
 		NotifyConfigModel:       model.NewNotifyConfigModel(mongoDB),
+		NotifyRouteModel:        model.NewNotifyRouteModel(mongoDB),
+		NotifyDigestModel:       model.NewNotifyDigestModel(mongoDB),
+		OrganizationModel:       model.NewOrganizationModel(mongoDB),
 		TaskRecoveryManager:     recoveryManager,
 	}
 }
@@ -117,3 +123,10 @@ func (s *ServiceContext) GetAssetHistoryModel(workspaceId string) *model.AssetHi
 	}
 	return model.NewAssetHistoryModel(s.MongoDB, workspaceId)
 }
+
+func (s *ServiceContext) GetScanResultHistoryModel(workspaceId string) *model.ScanResultHistoryModel {
+	if workspaceId == "" {
+		workspaceId = "default"
+	}
+	return model.NewScanResultHistoryModel(s.MongoDB, workspaceId)
+}
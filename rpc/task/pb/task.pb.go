@@ -460,8 +460,9 @@ type AssetDocument struct {
 	Ipv6          []*IPV6                `protobuf:"bytes,19,rep,name=ipv6,proto3" json:"ipv6,omitempty"`
 	Screenshot    string                 `protobuf:"bytes,20,opt,name=screenshot,proto3" json:"screenshot,omitempty"`
 	IsHttp        bool                   `protobuf:"varint,21,opt,name=isHttp,proto3" json:"isHttp,omitempty"`
-	Source        string                 `protobuf:"bytes,22,opt,name=source,proto3" json:"source,omitempty"`     // 资产来源: subfinder, portscan, etc.
-	IconData      []byte                 `protobuf:"bytes,23,opt,name=iconData,proto3" json:"iconData,omitempty"` // favicon 图片原始数据
+	Source        string                 `protobuf:"bytes,22,opt,name=source,proto3" json:"source,omitempty"`       // 资产来源: subfinder, portscan, etc.
+	IconData      []byte                 `protobuf:"bytes,23,opt,name=iconData,proto3" json:"iconData,omitempty"`   // favicon 图片原始数据
+	Transport     string                 `protobuf:"bytes,24,opt,name=transport,proto3" json:"transport,omitempty"` // 传输层协议: tcp/udp，为空表示tcp
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -657,6 +658,13 @@ func (x *AssetDocument) GetIconData() []byte {
 	return nil
 }
 
+func (x *AssetDocument) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
 type IPV4 struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
@@ -3843,7 +3851,7 @@ const file_rpc_task_task_proto_rawDesc = "" +
 	"\vworkspaceId\x18\x05 \x01(\tR\vworkspaceId\"A\n" +
 	"\vNewTaskResp\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\xe1\x04\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xff\x04\n" +
 	"\rAssetDocument\x12\x1c\n" +
 	"\tauthority\x18\x01 \x01(\tR\tauthority\x12\x12\n" +
 	"\x04host\x18\x02 \x01(\tR\x04host\x12\x12\n" +
@@ -3876,7 +3884,8 @@ const file_rpc_task_task_proto_rawDesc = "" +
 	"screenshot\x12\x16\n" +
 	"\x06isHttp\x18\x15 \x01(\bR\x06isHttp\x12\x16\n" +
 	"\x06source\x18\x16 \x01(\tR\x06source\x12\x1a\n" +
-	"\biconData\x18\x17 \x01(\fR\biconData\"H\n" +
+	"\biconData\x18\x17 \x01(\fR\biconData\x12\x1c\n" +
+	"\ttransport\x18\x18 \x01(\tR\ttransport\"H\n" +
 	"\x04IPV4\x12\x0e\n" +
 	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x14\n" +
 	"\x05ipInt\x18\x02 \x01(\rR\x05ipInt\x12\x1a\n" +
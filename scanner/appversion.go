@@ -0,0 +1,158 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+
+	"cscan/pkg/cpe"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// applyAppVersion 为检测结果补充版本，原始名称已带版本（如 httpx 的 Nginx:1.24.0）时保持不变
+func applyAppVersion(result *AppDetectionResult, version string) {
+	if result == nil || version == "" {
+		return
+	}
+	name := extractAppName(result.OriginalName)
+	if name == "" {
+		name = result.Name
+	}
+	if strings.Contains(name, ":") {
+		return
+	}
+	result.OriginalName = name + ":" + version
+}
+
+// finalizeAssets 指纹识别结束后的收尾：非HTTP资产按Banner识别版本，并为所有资产生成CPE
+func (s *FingerprintScanner) finalizeAssets(assets []*Asset, opts *FingerprintOptions, taskLog func(level, format string, args ...interface{})) {
+	for _, asset := range assets {
+		if opts.CustomEngine && s.customFingerprintEngine != nil && asset.Banner != "" && !isHttpAsset(asset) {
+			for _, m := range s.customFingerprintEngine.MatchBanner(asset.Banner) {
+				if appBaseIndex(asset.App, m.Name) >= 0 {
+					continue
+				}
+				asset.App = append(asset.App, fmt.Sprintf("%s:%s[custom(%s)]", m.Name, m.Version, m.Id))
+				if taskLog != nil {
+					taskLog("INFO", "发现应用指纹: %s:%d -> %s:%s (来源: banner)", asset.Host, asset.Port, m.Name, m.Version)
+				}
+			}
+		}
+		asset.CPE = s.buildAppCPEs(asset.App)
+		if len(asset.CPE) > 0 {
+			logx.Debugf("CPE for %s:%d: %v", asset.Host, asset.Port, asset.CPE)
+		}
+	}
+}
+
+// appBaseIndex 查找去掉来源标识和版本后名称相同的应用
+func appBaseIndex(apps []string, name string) int {
+	for i, app := range apps {
+		base, _ := splitAppVersion(app)
+		if strings.EqualFold(base, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitAppVersion 将 "Nginx:1.24.0[httpx]" 拆分为名称和版本
+func splitAppVersion(app string) (string, string) {
+	name := extractAppName(app)
+	if idx := strings.Index(name, ":"); idx > 0 {
+		return strings.TrimSpace(name[:idx]), strings.TrimSpace(name[idx+1:])
+	}
+	return strings.TrimSpace(name), ""
+}
+
+// cleanAppVersion 取版本的第一段并校验以数字开头，如 nmap 的 "8.2p1 Ubuntu 4ubuntu0.5" => "8.2p1"
+func cleanAppVersion(version string) string {
+	fields := strings.Fields(version)
+	if len(fields) == 0 {
+		return ""
+	}
+	v := cpe.NormalizeVersion(fields[0])
+	if v == "" || v[0] < '0' || v[0] > '9' {
+		return ""
+	}
+	return v
+}
+
+// buildAppCPEs 为带版本的应用生成CPE 2.3字符串
+// 厂商和产品优先取指纹规则或Wappalyzer库中配置的CPE，未配置时厂商为 *、产品为规范化的应用名
+func (s *FingerprintScanner) buildAppCPEs(apps []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, app := range apps {
+		name, version := splitAppVersion(app)
+		version = cleanAppVersion(version)
+		if name == "" || version == "" {
+			continue
+		}
+
+		var c *cpe.CPE
+		if base := s.lookupCPE(name); base != "" {
+			if parsed, err := cpe.Parse(base); err == nil {
+				c = parsed.WithVersion(version)
+			}
+		}
+		if c == nil {
+			c = cpe.New("", name, version)
+		}
+		if c.Product == "" {
+			continue
+		}
+		str := c.String()
+		if !seen[str] {
+			seen[str] = true
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+// knownProductCPEs 端口扫描（nmap/fingerprintx）常见产品名到CPE的映射，产品名与NVD不一致时使用
+var knownProductCPEs = map[string]string{
+	"apache httpd":                    "cpe:2.3:a:apache:http_server",
+	"apache tomcat":                   "cpe:2.3:a:apache:tomcat",
+	"apache tomcat/coyote jsp engine": "cpe:2.3:a:apache:tomcat",
+	"openssh":                         "cpe:2.3:a:openbsd:openssh",
+	"microsoft iis httpd":             "cpe:2.3:a:microsoft:internet_information_services",
+	"mysql":                           "cpe:2.3:a:oracle:mysql",
+	"postgresql db":                   "cpe:2.3:a:postgresql:postgresql",
+	"redis key-value store":           "cpe:2.3:a:redis:redis",
+	"vsftpd":                          "cpe:2.3:a:beasts:vsftpd",
+	"proftpd":                         "cpe:2.3:a:proftpd:proftpd",
+	"exim smtpd":                      "cpe:2.3:a:exim:exim",
+	"postfix smtpd":                   "cpe:2.3:a:postfix:postfix",
+	"isc bind":                        "cpe:2.3:a:isc:bind",
+	"dnsmasq":                         "cpe:2.3:a:thekelleys:dnsmasq",
+	"samba smbd":                      "cpe:2.3:a:samba:samba",
+	"jetty":                           "cpe:2.3:a:eclipse:jetty",
+	"lighttpd":                        "cpe:2.3:a:lighttpd:lighttpd",
+}
+
+// lookupCPE 按应用名查找CPE模板：自定义指纹 > Wappalyzer库 > 内置常见产品映射
+func (s *FingerprintScanner) lookupCPE(name string) string {
+	if c := s.customFingerprintEngine.CPEForName(name); c != "" {
+		return c
+	}
+	s.wappalyzerCPEOnce.Do(func() {
+		s.wappalyzerCPE = make(map[string]string)
+		if s.wappalyzerClient == nil {
+			return
+		}
+		if fps := s.wappalyzerClient.GetFingerprints(); fps != nil {
+			for appName, fp := range fps.Apps {
+				if fp != nil && fp.CPE != "" {
+					s.wappalyzerCPE[strings.ToLower(appName)] = fp.CPE
+				}
+			}
+		}
+	})
+	key := strings.ToLower(name)
+	if c := s.wappalyzerCPE[key]; c != "" {
+		return c
+	}
+	return knownProductCPEs[key]
+}
@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"cscan/model"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMatchPatternVersion(t *testing.T) {
+	ok, version := matchPatternVersion("nginx/1.18.0 (Ubuntu)", `nginx(?:/([\d.]+))?\;version:\1`)
+	if !ok || version != "1.18.0" {
+		t.Fatalf("got %v %q, want 1.18.0", ok, version)
+	}
+	ok, version = matchPatternVersion("nginx", `nginx(?:/([\d.]+))?\;version:\1`)
+	if !ok || version != "" {
+		t.Fatalf("match without version: got %v %q", ok, version)
+	}
+	if ok, _ := matchPatternVersion("apache", `nginx\;version:\1`); ok {
+		t.Fatal("should not match")
+	}
+	// 不带标记的模式保持原有行为
+	if ok, _ := matchPatternVersion("Powered by WordPress", "wordpress"); !ok {
+		t.Fatal("plain pattern should match case-insensitively")
+	}
+}
+
+func TestMatchWithIdVersion(t *testing.T) {
+	engine := NewCustomFingerprintEngine([]*model.Fingerprint{
+		{
+			Id:      primitive.NewObjectID(),
+			Name:    "Nginx",
+			Headers: map[string]string{"Server": `nginx(?:/([\d.]+))?\;version:\1`},
+			CPE:     "cpe:2.3:a:f5:nginx:*:*:*:*:*:*:*:*",
+			Enabled: true,
+		},
+		{
+			Id:      primitive.NewObjectID(),
+			Name:    "Grafana",
+			Rule:    `body="grafana-app"`,
+			Enabled: true,
+			Extractors: []model.VersionExtractor{
+				{Part: "body", Regex: `"version":"v?(\d+)\.(\d+)\.(\d+)"`, Version: `\1.\2.\3`},
+			},
+		},
+	})
+
+	data := &FingerprintData{
+		Body:    `<div class="grafana-app"></div><script>{"version":"v9.2.1"}</script>`,
+		Headers: http.Header{"Server": []string{"nginx/1.20.0"}},
+	}
+	got := map[string]MatchedFingerprint{}
+	for _, m := range engine.MatchWithId(data) {
+		got[m.Name] = m
+	}
+	if got["Nginx"].Version != "1.20.0" || got["Nginx"].CPE == "" {
+		t.Errorf("nginx: %+v", got["Nginx"])
+	}
+	if got["Grafana"].Version != "9.2.1" {
+		t.Errorf("grafana: %+v", got["Grafana"])
+	}
+}
+
+func TestMatchBanner(t *testing.T) {
+	engine := NewCustomFingerprintEngine([]*model.Fingerprint{
+		{
+			Id:         primitive.NewObjectID(),
+			Name:       "OpenSSH",
+			Enabled:    true,
+			Extractors: []model.VersionExtractor{{Part: "banner", Regex: `SSH-[\d.]+-OpenSSH_([\w.]+)`}},
+		},
+		{
+			Id:         primitive.NewObjectID(),
+			Name:       "Body Only",
+			Enabled:    true,
+			Extractors: []model.VersionExtractor{{Part: "body", Regex: `(\d+\.\d+)`}},
+		},
+	})
+	matched := engine.MatchBanner("SSH-2.0-OpenSSH_8.2p1 Ubuntu-4ubuntu0.5")
+	if len(matched) != 1 || matched[0].Name != "OpenSSH" || matched[0].Version != "8.2p1" {
+		t.Fatalf("unexpected banner match: %+v", matched)
+	}
+}
+
+func TestBuildAppCPEs(t *testing.T) {
+	s := &FingerprintScanner{
+		customFingerprintEngine: NewCustomFingerprintEngine([]*model.Fingerprint{
+			{Name: "Grafana", CPE: "cpe:2.3:a:grafana:grafana:*:*:*:*:*:*:*:*"},
+		}),
+	}
+	apps := []string{
+		"Grafana:9.2.1[custom(abc)]",
+		"OpenSSH:8.2p1 Ubuntu 4ubuntu0.5",
+		"Some App:v2.0[httpx]",
+		"jQuery[wappalyzer]",
+		"ssh:SSH-2.0-dropbear",
+		"Grafana:9.2.1[httpx]",
+	}
+	want := []string{
+		"cpe:2.3:a:grafana:grafana:9.2.1:*:*:*:*:*:*:*",
+		"cpe:2.3:a:openbsd:openssh:8.2p1:*:*:*:*:*:*:*",
+		"cpe:2.3:a:*:some_app:2.0:*:*:*:*:*:*:*",
+	}
+	if got := s.buildAppCPEs(apps); !reflect.DeepEqual(got, want) {
+		t.Fatalf("buildAppCPEs = %v, want %v", got, want)
+	}
+}
+
+func TestApplyAppVersion(t *testing.T) {
+	r := &AppDetectionResult{Name: "Nginx", OriginalName: "Nginx"}
+	applyAppVersion(r, "1.20.0")
+	if r.OriginalName != "Nginx:1.20.0" {
+		t.Fatalf("OriginalName = %s", r.OriginalName)
+	}
+	// httpx 已带版本时保持不变
+	r = &AppDetectionResult{Name: "Nginx", OriginalName: "Nginx:1.24.0[httpx]"}
+	applyAppVersion(r, "1.20.0")
+	if r.OriginalName != "Nginx:1.24.0[httpx]" {
+		t.Fatalf("OriginalName = %s", r.OriginalName)
+	}
+}
@@ -0,0 +1,568 @@
+package scanner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// 弱口令检测支持的协议
+const (
+	BruteSSH           = "ssh"
+	BruteFTP           = "ftp"
+	BruteTelnet        = "telnet"
+	BruteMySQL         = "mysql"
+	BrutePostgreSQL    = "postgresql"
+	BruteMSSQL         = "mssql"
+	BruteRedis         = "redis"
+	BruteMongoDB       = "mongodb"
+	BruteSMB           = "smb"
+	BruteMemcached     = "memcached"
+	BruteElasticsearch = "elasticsearch"
+)
+
+// lockoutSafeAttempts 常见账号锁定策略的协议（如域账号），每个账号默认最多尝试次数
+const lockoutSafeAttempts = 3
+
+// bruteMaxConnErrors 同一目标连续连接失败次数上限，超过后放弃该目标（不可达或已被封禁）
+const bruteMaxConnErrors = 3
+
+var (
+	// errBruteAuthFailed 认证失败，凭据错误或服务需要认证
+	errBruteAuthFailed = errors.New("authentication failed")
+	// errBruteLockedOut 账号已被锁定，立即停止该目标的尝试
+	errBruteLockedOut = errors.New("account locked out")
+)
+
+// Credential 登录凭据
+type Credential struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ParseCredentials 解析字典内容，每行一个 username:password，按第一个冒号分隔；
+// 没有冒号的行视为密码，使用协议默认用户名；空行和 # 开头的行忽略
+func ParseCredentials(content string) []Credential {
+	var creds []Credential
+	seen := make(map[Credential]bool)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		var cred Credential
+		if i := strings.Index(line, ":"); i >= 0 {
+			cred = Credential{Username: strings.TrimSpace(line[:i]), Password: line[i+1:]}
+		} else {
+			cred = Credential{Password: line}
+		}
+		if !seen[cred] {
+			seen[cred] = true
+			creds = append(creds, cred)
+		}
+	}
+	return creds
+}
+
+// bruteProtocol 协议的登录和未授权访问检测实现
+// login/unauth 成功时返回证明信息；凭据错误返回 errBruteAuthFailed，账号锁定返回 errBruteLockedOut，其他错误视为连接失败
+type bruteProtocol struct {
+	defaultUser string
+	lockout     bool // 常见账号锁定策略，每个账号最多尝试 lockoutSafeAttempts 次
+	login       func(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error)
+	unauth      func(ctx context.Context, host string, port int, timeout time.Duration) (string, error)
+	// 未授权访问漏洞等级
+	unauthSeverity string
+}
+
+// bruteServiceAliases 端口识别服务名到协议的映射
+var bruteServiceAliases = map[string]string{
+	"ssh":           BruteSSH,
+	"ftp":           BruteFTP,
+	"telnet":        BruteTelnet,
+	"mysql":         BruteMySQL,
+	"mariadb":       BruteMySQL,
+	"postgresql":    BrutePostgreSQL,
+	"postgres":      BrutePostgreSQL,
+	"ms-sql-s":      BruteMSSQL,
+	"mssql":         BruteMSSQL,
+	"redis":         BruteRedis,
+	"mongodb":       BruteMongoDB,
+	"mongod":        BruteMongoDB,
+	"microsoft-ds":  BruteSMB,
+	"smb":           BruteSMB,
+	"memcache":      BruteMemcached,
+	"memcached":     BruteMemcached,
+	"elasticsearch": BruteElasticsearch,
+}
+
+// bruteDefaultPorts 服务未识别时按默认端口推断协议
+var bruteDefaultPorts = map[int]string{
+	21:    BruteFTP,
+	22:    BruteSSH,
+	23:    BruteTelnet,
+	445:   BruteSMB,
+	1433:  BruteMSSQL,
+	3306:  BruteMySQL,
+	5432:  BrutePostgreSQL,
+	6379:  BruteRedis,
+	9200:  BruteElasticsearch,
+	11211: BruteMemcached,
+	27017: BruteMongoDB,
+}
+
+// bruteProtocolOf 根据资产的服务名、指纹和端口判断协议，不支持时返回空
+func bruteProtocolOf(asset *Asset) string {
+	// 登录实现均基于TCP，UDP资产不参与
+	if asset.Transport == TransportUDP {
+		return ""
+	}
+	service := strings.ToLower(strings.TrimSpace(asset.Service))
+	service = strings.TrimPrefix(service, "ssl/")
+	if proto, ok := bruteServiceAliases[service]; ok {
+		return proto
+	}
+	for _, app := range asset.App {
+		if strings.Contains(strings.ToLower(app), "elasticsearch") {
+			return BruteElasticsearch
+		}
+	}
+	switch service {
+	case "", "unknown", "tcpwrapped":
+		return bruteDefaultPorts[asset.Port]
+	}
+	return ""
+}
+
+// BruteforceOptions 弱口令检测选项
+type BruteforceOptions struct {
+	Services    []string                `json:"services"`    // 检测的协议，为空时检测全部支持的协议
+	Credentials map[string][]Credential `json:"credentials"` // 各协议字典，未配置的协议使用内置字典
+	Concurrency int                     `json:"concurrency"` // 并发目标数
+	Rate        int                     `json:"rate"`        // 全局每秒最多登录尝试次数
+	MaxAttempts int                     `json:"maxAttempts"` // 每个目标最多登录尝试次数
+	MaxPerUser  int                     `json:"maxPerUser"`  // 每个目标每个账号最多尝试次数，0 表示不限制（易锁定协议仍限制为3次）
+	Interval    int                     `json:"interval"`    // 同一目标两次尝试的间隔(毫秒)
+	Timeout     int                     `json:"timeout"`     // 单次连接超时(秒)
+}
+
+// Validate 验证选项
+func (o *BruteforceOptions) Validate() error {
+	if o.Concurrency < 0 || o.Rate < 0 || o.MaxAttempts < 0 || o.MaxPerUser < 0 || o.Interval < 0 || o.Timeout < 0 {
+		return fmt.Errorf("bruteforce options must not be negative")
+	}
+	for _, s := range o.Services {
+		if _, ok := bruteProtocols[s]; !ok {
+			return fmt.Errorf("unsupported bruteforce service: %s", s)
+		}
+	}
+	return nil
+}
+
+// BruteforceScanner 弱口令及未授权访问检测
+type BruteforceScanner struct {
+	BaseScanner
+	protocols map[string]*bruteProtocol
+}
+
+// NewBruteforceScanner 创建弱口令检测扫描器
+func NewBruteforceScanner() *BruteforceScanner {
+	return &BruteforceScanner{
+		BaseScanner: BaseScanner{name: "bruteforce"},
+		protocols:   bruteProtocols,
+	}
+}
+
+// bruteTarget 待检测的服务
+type bruteTarget struct {
+	authority string
+	host      string
+	port      int
+	protocol  string
+}
+
+// bruteRunner 单次扫描的共享状态
+type bruteRunner struct {
+	protocols   map[string]*bruteProtocol
+	credentials map[string][]Credential
+	limiter     *rate.Limiter
+	maxAttempts int
+	maxPerUser  int
+	interval    time.Duration
+	timeout     time.Duration
+	logf        func(level, format string, args ...interface{})
+
+	// 账号锁定协议的尝试次数在所有主机间共享（同一域账号在每台主机上失败都会计入锁定），按协议+用户名计数
+	accountMu       sync.Mutex
+	accountAttempts map[string]int
+}
+
+// Scan 对资产中识别出的服务进行未授权访问检测和弱口令爆破
+func (s *BruteforceScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	result := &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+	}
+
+	opts, _ := GetTypedOptions[*BruteforceOptions](config)
+	if opts == nil {
+		opts = &BruteforceOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 100
+	}
+	timeout := time.Duration(opts.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	interval := time.Duration(opts.Interval) * time.Millisecond
+	if opts.Interval == 0 {
+		interval = 100 * time.Millisecond
+	}
+	limit := rate.Limit(opts.Rate)
+	if opts.Rate <= 0 {
+		limit = 50
+	}
+
+	logf := func(level, format string, args ...interface{}) {
+		if config.TaskLogger != nil {
+			config.TaskLogger(level, format, args...)
+		}
+	}
+
+	enabled := make(map[string]bool)
+	for _, svc := range opts.Services {
+		enabled[svc] = true
+	}
+	var targets []*bruteTarget
+	seen := make(map[string]bool)
+	for _, asset := range config.Assets {
+		proto := bruteProtocolOf(asset)
+		if proto == "" || s.protocols[proto] == nil || (len(enabled) > 0 && !enabled[proto]) {
+			continue
+		}
+		addr := net.JoinHostPort(asset.Host, strconv.Itoa(asset.Port))
+		if seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		authority := asset.Authority
+		if authority == "" {
+			authority = addr
+		}
+		targets = append(targets, &bruteTarget{authority: authority, host: asset.Host, port: asset.Port, protocol: proto})
+	}
+	if len(targets) == 0 {
+		logf("INFO", "Bruteforce: no supported services")
+		return result, nil
+	}
+
+	credentials := DefaultBruteforceCredentials()
+	for proto, creds := range opts.Credentials {
+		if len(creds) > 0 {
+			credentials[proto] = creds
+		}
+	}
+
+	r := &bruteRunner{
+		protocols:   s.protocols,
+		credentials: credentials,
+		limiter:     rate.NewLimiter(limit, 1),
+		maxAttempts: maxAttempts,
+		maxPerUser:  opts.MaxPerUser,
+		interval:    interval,
+		timeout:     timeout,
+		logf:        logf,
+
+		accountAttempts: make(map[string]int),
+	}
+	logf("INFO", "Bruteforce: checking %d services, max %d attempts per service, %d attempts/s", len(targets), maxAttempts, int(limit))
+
+	vuls, _ := ExecuteGeneric(ctx, concurrency, targets, func(ctx context.Context, t *bruteTarget) (*Vulnerability, error) {
+		return r.run(ctx, t), nil
+	})
+	for _, v := range vuls {
+		if v != nil {
+			result.Vulnerabilities = append(result.Vulnerabilities, v)
+		}
+	}
+	logf("INFO", "Bruteforce: %d findings", len(result.Vulnerabilities))
+	return result, ctx.Err()
+}
+
+// wait 遵守全局速率和单目标间隔
+func (r *bruteRunner) wait(ctx context.Context, first bool) error {
+	if !first && r.interval > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.interval):
+		}
+	}
+	return r.limiter.Wait(ctx)
+}
+
+// run 检测单个服务：先检测未授权访问，再用随机凭据探测是否接受任意凭据，最后按字典顺序尝试登录
+func (r *bruteRunner) run(ctx context.Context, t *bruteTarget) *Vulnerability {
+	p := r.protocols[t.protocol]
+
+	if p.unauth != nil {
+		if err := r.wait(ctx, true); err != nil {
+			return nil
+		}
+		proof, err := p.unauth(ctx, t.host, t.port, r.timeout)
+		if err == nil {
+			r.logf("INFO", "Bruteforce: %s %s allows unauthenticated access", t.protocol, t.authority)
+			return unauthVul(t, p, proof)
+		}
+		if !errors.Is(err, errBruteAuthFailed) {
+			return nil
+		}
+	}
+	if p.login == nil {
+		return nil
+	}
+
+	// 接受任意凭据的服务（蜜罐、映射为来宾账号的SMB等）不做爆破，避免误报
+	if err := r.wait(ctx, p.unauth == nil); err != nil {
+		return nil
+	}
+	if _, err := p.login(ctx, t.host, t.port, randomCredential(), r.timeout); err == nil {
+		r.logf("WARN", "Bruteforce: %s %s accepts random credentials, skipped", t.protocol, t.authority)
+		return nil
+	} else if !errors.Is(err, errBruteAuthFailed) {
+		return nil
+	}
+
+	maxPerUser := r.maxPerUser
+	if p.lockout && (maxPerUser == 0 || maxPerUser > lockoutSafeAttempts) {
+		maxPerUser = lockoutSafeAttempts
+	}
+	perUser := make(map[string]int)
+	attempts, connErrors := 0, 0
+	for _, cred := range r.credentials[t.protocol] {
+		if attempts >= r.maxAttempts {
+			r.logf("INFO", "Bruteforce: %s %s reached max attempts %d", t.protocol, t.authority, r.maxAttempts)
+			break
+		}
+		if cred.Username == "" {
+			cred.Username = p.defaultUser
+		}
+		if p.lockout {
+			if !r.reserveAccount(t.protocol, cred.Username, maxPerUser) {
+				continue
+			}
+		} else if maxPerUser > 0 && perUser[cred.Username] >= maxPerUser {
+			continue
+		}
+		if err := r.wait(ctx, false); err != nil {
+			return nil
+		}
+		attempts++
+		perUser[cred.Username]++
+
+		proof, err := p.login(ctx, t.host, t.port, cred, r.timeout)
+		switch {
+		case err == nil:
+			r.logf("INFO", "Bruteforce: %s %s weak credential found for user %s", t.protocol, t.authority, cred.Username)
+			return weakCredentialVul(t, cred, proof)
+		case errors.Is(err, errBruteLockedOut):
+			r.logf("WARN", "Bruteforce: %s %s account %s locked out, stopped", t.protocol, t.authority, cred.Username)
+			if p.lockout {
+				r.exhaustAccount(t.protocol, cred.Username, maxPerUser)
+			}
+			return nil
+		case errors.Is(err, errBruteAuthFailed):
+			connErrors = 0
+		default:
+			connErrors++
+			if connErrors >= bruteMaxConnErrors {
+				r.logf("WARN", "Bruteforce: %s %s connection failed %d times, stopped: %v", t.protocol, t.authority, connErrors, err)
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// reserveAccount 占用账号锁定协议的一次尝试次数，所有主机的尝试合计达到上限后返回false
+func (r *bruteRunner) reserveAccount(protocol, username string, max int) bool {
+	key := protocol + "\x00" + username
+	r.accountMu.Lock()
+	defer r.accountMu.Unlock()
+	if max > 0 && r.accountAttempts[key] >= max {
+		return false
+	}
+	r.accountAttempts[key]++
+	return true
+}
+
+// exhaustAccount 账号已被锁定，其他主机不再尝试该账号
+func (r *bruteRunner) exhaustAccount(protocol, username string, max int) {
+	key := protocol + "\x00" + username
+	r.accountMu.Lock()
+	defer r.accountMu.Unlock()
+	if r.accountAttempts[key] < max {
+		r.accountAttempts[key] = max
+	}
+}
+
+// randomCredential 生成不可能存在的随机凭据
+func randomCredential() Credential {
+	b := make([]byte, 12)
+	rand.Read(b)
+	s := hex.EncodeToString(b)
+	return Credential{Username: "cscan" + s[:8], Password: s[8:]}
+}
+
+// weakCredentialVul 弱口令漏洞，凭据作为提取结果保存
+func weakCredentialVul(t *bruteTarget, cred Credential, proof string) *Vulnerability {
+	return &Vulnerability{
+		Authority:        t.authority,
+		Host:             t.host,
+		Port:             t.port,
+		Url:              t.protocol + "://" + t.authority,
+		PocFile:          "bruteforce-" + t.protocol,
+		VulName:          fmt.Sprintf("%s Weak Credential", bruteTitle(t.protocol)),
+		Source:           "bruteforce",
+		Severity:         "critical",
+		Result:           fmt.Sprintf("%s login succeeded with %s:%s", t.protocol, cred.Username, cred.Password),
+		Tags:             []string{"bruteforce", "weak-password", t.protocol},
+		MatcherName:      "weak-password",
+		ExtractedResults: []string{cred.Username + ":" + cred.Password},
+		Response:         proof,
+	}
+}
+
+// unauthVul 未授权访问漏洞
+func unauthVul(t *bruteTarget, p *bruteProtocol, proof string) *Vulnerability {
+	severity := p.unauthSeverity
+	if severity == "" {
+		severity = "critical"
+	}
+	return &Vulnerability{
+		Authority:   t.authority,
+		Host:        t.host,
+		Port:        t.port,
+		Url:         t.protocol + "://" + t.authority,
+		PocFile:     "unauth-" + t.protocol,
+		VulName:     fmt.Sprintf("%s Unauthorized Access", bruteTitle(t.protocol)),
+		Source:      "bruteforce",
+		Severity:    severity,
+		Result:      fmt.Sprintf("%s allows access without authentication", t.protocol),
+		Tags:        []string{"bruteforce", "unauth", t.protocol},
+		MatcherName: "unauthorized",
+		Response:    proof,
+	}
+}
+
+func bruteTitle(proto string) string {
+	switch proto {
+	case BruteSSH, BruteFTP, BruteSMB:
+		return strings.ToUpper(proto)
+	case BruteMySQL:
+		return "MySQL"
+	case BrutePostgreSQL:
+		return "PostgreSQL"
+	case BruteMSSQL:
+		return "MSSQL"
+	case BruteMongoDB:
+		return "MongoDB"
+	default:
+		return strings.ToUpper(proto[:1]) + proto[1:]
+	}
+}
+
+// BruteforceServices 支持的协议列表
+func BruteforceServices() []string {
+	return []string{BruteSSH, BruteFTP, BruteTelnet, BruteMySQL, BrutePostgreSQL, BruteMSSQL, BruteRedis, BruteMongoDB, BruteSMB, BruteMemcached, BruteElasticsearch}
+}
+
+// DefaultBruteforceCredentials 内置弱口令字典，用户名为空时使用协议默认用户名
+func DefaultBruteforceCredentials() map[string][]Credential {
+	return map[string][]Credential{
+		BruteSSH: ParseCredentials(`root:root
+root:123456
+root:password
+root:toor
+root:admin
+root:1qaz@WSX
+admin:admin
+admin:123456
+ubuntu:ubuntu
+test:test
+oracle:oracle
+pi:raspberry`),
+		BruteFTP: ParseCredentials(`anonymous:anonymous@example.com
+ftp:ftp
+admin:admin
+admin:123456
+root:root
+test:test
+user:user`),
+		BruteTelnet: ParseCredentials(`root:root
+root:
+root:123456
+admin:admin
+admin:
+admin:password
+admin:1234
+guest:guest
+user:user`),
+		BruteMySQL: ParseCredentials(`root:
+root:root
+root:123456
+root:password
+root:mysql
+root:admin
+admin:admin
+test:test`),
+		BrutePostgreSQL: ParseCredentials(`postgres:postgres
+postgres:
+postgres:123456
+postgres:password
+postgres:admin
+admin:admin`),
+		BruteMSSQL: ParseCredentials(`sa:
+sa:sa
+sa:123456
+sa:password
+sa:P@ssw0rd
+sa:Password123
+sa:admin`),
+		BruteRedis: ParseCredentials(`123456
+password
+redis
+foobared
+admin
+root
+12345678`),
+		BruteMongoDB: ParseCredentials(`admin:admin
+admin:123456
+admin:password
+root:root
+root:123456
+mongo:mongo`),
+		BruteSMB: ParseCredentials(`administrator:
+administrator:administrator
+administrator:123456
+administrator:password
+administrator:P@ssw0rd
+admin:admin
+guest:`),
+	}
+}
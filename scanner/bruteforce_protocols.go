@@ -0,0 +1,558 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	mssql "github.com/microsoft/go-mssqldb"
+	"github.com/projectdiscovery/go-smb2"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/ssh"
+)
+
+// bruteProofMaxSize 证明信息最大长度
+const bruteProofMaxSize = 2048
+
+// bruteProtocols 各协议的检测实现
+var bruteProtocols = map[string]*bruteProtocol{
+	BruteSSH:           {defaultUser: "root", login: sshLogin},
+	BruteFTP:           {defaultUser: "anonymous", login: ftpLogin},
+	BruteTelnet:        {defaultUser: "root", login: telnetLogin},
+	BruteMySQL:         {defaultUser: "root", login: mysqlLogin},
+	BrutePostgreSQL:    {defaultUser: "postgres", login: postgresLogin},
+	BruteMSSQL:         {defaultUser: "sa", login: mssqlLogin},
+	BruteRedis:         {login: redisLogin, unauth: redisUnauth},
+	BruteMongoDB:       {defaultUser: "admin", login: mongoLogin, unauth: mongoUnauth},
+	BruteSMB:           {defaultUser: "administrator", lockout: true, login: smbLogin},
+	BruteMemcached:     {unauth: memcachedUnauth, unauthSeverity: "high"},
+	BruteElasticsearch: {unauth: elasticsearchUnauth},
+}
+
+func bruteDial(ctx context.Context, host string, port int, timeout time.Duration) (net.Conn, error) {
+	d := &net.Dialer{Timeout: timeout}
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+	return conn, nil
+}
+
+func truncateProof(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > bruteProofMaxSize {
+		return s[:bruteProofMaxSize]
+	}
+	return s
+}
+
+// sshLogin 密码和键盘交互认证，不执行任何命令
+func sshLogin(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error) {
+	conn, err := bruteDial(ctx, host, port, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	cfg := &ssh.ClientConfig{
+		User: cred.Username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(cred.Password),
+			ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = cred.Password
+				}
+				return answers, nil
+			}),
+		},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         timeout,
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, conn.RemoteAddr().String(), cfg)
+	if err != nil {
+		if strings.Contains(err.Error(), "unable to authenticate") {
+			return "", errBruteAuthFailed
+		}
+		return "", err
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	defer client.Close()
+	return fmt.Sprintf("SSH login succeeded, server version: %s", client.ServerVersion()), nil
+}
+
+// ftpLogin USER/PASS 登录
+func ftpLogin(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error) {
+	conn, err := bruteDial(ctx, host, port, timeout)
+	if err != nil {
+		return "", err
+	}
+	tp := textproto.NewConn(conn)
+	defer tp.Close()
+
+	_, greeting, err := tp.ReadResponse(220)
+	if err != nil {
+		return "", err
+	}
+	code, msg, err := ftpCmd(tp, "USER %s", cred.Username)
+	if err != nil {
+		return "", err
+	}
+	if code == 331 {
+		code, msg, err = ftpCmd(tp, "PASS %s", cred.Password)
+		if err != nil {
+			return "", err
+		}
+	}
+	switch {
+	case code == 230:
+		tp.Cmd("QUIT")
+		return truncateProof(fmt.Sprintf("220 %s\n230 %s", greeting, msg)), nil
+	case code == 530 || code == 430:
+		return "", errBruteAuthFailed
+	default:
+		return "", fmt.Errorf("unexpected ftp response %d %s", code, msg)
+	}
+}
+
+func ftpCmd(tp *textproto.Conn, format string, args ...interface{}) (int, string, error) {
+	if _, err := tp.Cmd(format, args...); err != nil {
+		return 0, "", err
+	}
+	code, msg, err := tp.ReadResponse(0)
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code, protoErr.Msg, nil
+	}
+	return code, msg, err
+}
+
+// Telnet 协议控制字节
+const (
+	telnetIAC  = 255
+	telnetDONT = 254
+	telnetDO   = 253
+	telnetWONT = 252
+	telnetWILL = 251
+	telnetSB   = 250
+	telnetSE   = 240
+)
+
+// telnetSession 拒绝所有选项协商的最简 Telnet 客户端
+type telnetSession struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// readUntil 读取数据直到出现任一关键字（不区分大小写），返回已读取的文本和匹配的下标
+func (t *telnetSession) readUntil(patterns ...string) (string, int, error) {
+	var buf strings.Builder
+	for {
+		b, err := t.r.ReadByte()
+		if err != nil {
+			return buf.String(), -1, err
+		}
+		if b == telnetIAC {
+			if err := t.negotiate(); err != nil {
+				return buf.String(), -1, err
+			}
+			continue
+		}
+		buf.WriteByte(b)
+		text := strings.TrimRight(strings.ToLower(buf.String()), " \t")
+		for i, p := range patterns {
+			// 登录成功后的 "Last login:" 不是登录提示
+			if strings.HasSuffix(text, p) && !strings.HasSuffix(text, "last "+p) {
+				return buf.String(), i, nil
+			}
+		}
+		if buf.Len() > 16*1024 {
+			return buf.String(), -1, errors.New("telnet response too large")
+		}
+	}
+}
+
+// negotiate 处理 IAC 序列，对 DO 回复 WONT、对 WILL 回复 DONT，跳过子协商
+func (t *telnetSession) negotiate() error {
+	cmd, err := t.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	switch cmd {
+	case telnetDO, telnetDONT, telnetWILL, telnetWONT:
+		opt, err := t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if cmd == telnetDO {
+			_, err = t.conn.Write([]byte{telnetIAC, telnetWONT, opt})
+		} else if cmd == telnetWILL {
+			_, err = t.conn.Write([]byte{telnetIAC, telnetDONT, opt})
+		}
+		return err
+	case telnetSB:
+		for {
+			b, err := t.r.ReadByte()
+			if err != nil {
+				return err
+			}
+			if b == telnetIAC {
+				if next, err := t.r.ReadByte(); err != nil || next == telnetSE {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// telnetLogin 按登录提示依次输入用户名和密码，出现 shell 提示符视为成功
+func telnetLogin(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error) {
+	conn, err := bruteDial(ctx, host, port, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	t := &telnetSession{conn: conn, r: bufio.NewReader(conn)}
+
+	_, idx, err := t.readUntil("login:", "username:", "user:", "password:")
+	if err != nil {
+		return "", err
+	}
+	if idx != 3 {
+		if _, err := conn.Write([]byte(cred.Username + "\r\n")); err != nil {
+			return "", err
+		}
+		if _, _, err := t.readUntil("password:"); err != nil {
+			return "", err
+		}
+	}
+	if _, err := conn.Write([]byte(cred.Password + "\r\n")); err != nil {
+		return "", err
+	}
+	out, idx, err := t.readUntil("incorrect", "failed", "denied", "invalid", "login:", "username:", "password:", "$", "#", ">", "%")
+	if err != nil {
+		return "", err
+	}
+	if idx < 7 {
+		return "", errBruteAuthFailed
+	}
+	return truncateProof(out), nil
+}
+
+// sqlLogin 通过 database/sql 连接并执行版本查询
+func sqlLogin(ctx context.Context, db *sql.DB, query string, timeout time.Duration) (string, error) {
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var version string
+	if err := db.QueryRowContext(ctx, query).Scan(&version); err != nil {
+		return "", err
+	}
+	return "version: " + version, nil
+}
+
+func mysqlLogin(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = cred.Username
+	cfg.Passwd = cred.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(host, strconv.Itoa(port))
+	cfg.Timeout = timeout
+	cfg.ReadTimeout = timeout
+	cfg.WriteTimeout = timeout
+	cfg.Logger = log.New(io.Discard, "", 0)
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return "", err
+	}
+	proof, err := sqlLogin(ctx, sql.OpenDB(connector), "SELECT VERSION()", timeout)
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) && myErr.Number == 1045 {
+		return "", errBruteAuthFailed
+	}
+	return proof, err
+}
+
+func postgresLogin(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error) {
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cred.Username, cred.Password),
+		Host:     net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:     "/postgres",
+		RawQuery: url.Values{"sslmode": {"disable"}, "connect_timeout": {strconv.Itoa(int(timeout.Seconds()))}}.Encode(),
+	}
+	connector, err := pq.NewConnector(u.String())
+	if err != nil {
+		return "", err
+	}
+	proof, err := sqlLogin(ctx, sql.OpenDB(connector), "SELECT version()", timeout)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "28P01", "28000":
+			return "", errBruteAuthFailed
+		case "3D000":
+			// 认证已通过，只是 postgres 库不存在
+			return "authenticated, database postgres does not exist", nil
+		}
+	}
+	return proof, err
+}
+
+func mssqlLogin(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error) {
+	seconds := strconv.Itoa(int(timeout.Seconds()))
+	u := &url.URL{
+		Scheme: "sqlserver",
+		User:   url.UserPassword(cred.Username, cred.Password),
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		RawQuery: url.Values{
+			"dial timeout":           {seconds},
+			"connection timeout":     {seconds},
+			"TrustServerCertificate": {"true"},
+		}.Encode(),
+	}
+	connector, err := mssql.NewConnector(u.String())
+	if err != nil {
+		return "", err
+	}
+	proof, err := sqlLogin(ctx, sql.OpenDB(connector), "SELECT @@VERSION", timeout)
+	var msErr mssql.Error
+	if errors.As(err, &msErr) && msErr.Number == 18456 {
+		return "", errBruteAuthFailed
+	}
+	return proof, err
+}
+
+func newBruteRedisClient(host string, port int, cred *Credential, timeout time.Duration) *redis.Client {
+	opts := &redis.Options{
+		Addr:            net.JoinHostPort(host, strconv.Itoa(port)),
+		DialTimeout:     timeout,
+		ReadTimeout:     timeout,
+		WriteTimeout:    timeout,
+		MaxRetries:      -1,
+		PoolSize:        1,
+		Protocol:        2,
+		DisableIdentity: true,
+	}
+	if cred != nil {
+		opts.Username = cred.Username
+		opts.Password = cred.Password
+	}
+	return redis.NewClient(opts)
+}
+
+// redisAuthError 需要认证或凭据错误
+func redisAuthError(err error) bool {
+	msg := strings.ToUpper(err.Error())
+	return strings.Contains(msg, "NOAUTH") || strings.Contains(msg, "WRONGPASS") ||
+		strings.Contains(msg, "INVALID PASSWORD") || strings.Contains(msg, "INVALID USERNAME") ||
+		(strings.Contains(msg, "AUTH") && strings.Contains(msg, "ERR"))
+}
+
+func redisCheck(ctx context.Context, client *redis.Client) (string, error) {
+	defer client.Close()
+	if err := client.Ping(ctx).Err(); err != nil {
+		if redisAuthError(err) {
+			return "", errBruteAuthFailed
+		}
+		return "", err
+	}
+	info, err := client.Info(ctx, "server").Result()
+	if err != nil {
+		// INFO 可能被重命名，PING 成功已足够证明
+		return "PING: PONG", nil
+	}
+	var lines []string
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "redis_version:") || strings.HasPrefix(line, "os:") || strings.HasPrefix(line, "executable:") || strings.HasPrefix(line, "config_file:") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func redisUnauth(ctx context.Context, host string, port int, timeout time.Duration) (string, error) {
+	return redisCheck(ctx, newBruteRedisClient(host, port, nil, timeout))
+}
+
+func redisLogin(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error) {
+	return redisCheck(ctx, newBruteRedisClient(host, port, &cred, timeout))
+}
+
+func mongoCheck(ctx context.Context, host string, port int, cred *Credential, timeout time.Duration) (string, error) {
+	opts := options.Client().
+		SetHosts([]string{net.JoinHostPort(host, strconv.Itoa(port))}).
+		SetDirect(true).
+		SetConnectTimeout(timeout).
+		SetServerSelectionTimeout(timeout).
+		SetTimeout(timeout).
+		SetMaxPoolSize(1)
+	if cred != nil {
+		opts.SetAuth(options.Credential{Username: cred.Username, Password: cred.Password, AuthSource: "admin"})
+	}
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	defer client.Disconnect(context.Background())
+
+	names, err := client.ListDatabaseNames(ctx, bson.D{})
+	if err != nil {
+		msg := err.Error()
+		if strings.Contains(msg, "AuthenticationFailed") || strings.Contains(msg, "auth error") ||
+			strings.Contains(msg, "Unauthorized") || strings.Contains(msg, "requires authentication") {
+			return "", errBruteAuthFailed
+		}
+		return "", err
+	}
+	return truncateProof("databases: " + strings.Join(names, ", ")), nil
+}
+
+func mongoUnauth(ctx context.Context, host string, port int, timeout time.Duration) (string, error) {
+	return mongoCheck(ctx, host, port, nil, timeout)
+}
+
+func mongoLogin(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error) {
+	return mongoCheck(ctx, host, port, &cred, timeout)
+}
+
+// SMB NTSTATUS
+const (
+	ntStatusAccountLockedOut   = 0xC0000234
+	ntStatusPasswordExpired    = 0xC0000071
+	ntStatusPasswordMustChange = 0xC0000224
+)
+
+// smbLogin NTLM 认证，用户名可写为 DOMAIN\user
+func smbLogin(ctx context.Context, host string, port int, cred Credential, timeout time.Duration) (string, error) {
+	conn, err := bruteDial(ctx, host, port, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	initiator := &smb2.NTLMInitiator{User: cred.Username, Password: cred.Password}
+	if i := strings.Index(cred.Username, `\`); i > 0 {
+		initiator.Domain, initiator.User = cred.Username[:i], cred.Username[i+1:]
+	}
+	d := &smb2.Dialer{Initiator: initiator}
+	session, err := d.DialContext(ctx, conn)
+	if err != nil {
+		var respErr *smb2.ResponseError
+		if errors.As(err, &respErr) {
+			switch respErr.Code {
+			case ntStatusAccountLockedOut:
+				return "", errBruteLockedOut
+			case ntStatusPasswordExpired, ntStatusPasswordMustChange:
+				// 密码正确但已过期
+				return fmt.Sprintf("credential valid but password must be changed (%s)", respErr.Error()), nil
+			}
+			return "", errBruteAuthFailed
+		}
+		return "", err
+	}
+	defer session.Logoff()
+
+	shares, err := session.ListSharenames()
+	if err != nil {
+		return "SMB login succeeded", nil
+	}
+	return truncateProof("shares: " + strings.Join(shares, ", ")), nil
+}
+
+// memcachedUnauth 执行 stats 命令
+func memcachedUnauth(ctx context.Context, host string, port int, timeout time.Duration) (string, error) {
+	conn, err := bruteDial(ctx, host, port, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("stats\r\n")); err != nil {
+		return "", err
+	}
+	r := bufio.NewReader(conn)
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if err != nil && line == "" {
+			break
+		}
+		if line == "END" {
+			break
+		}
+		if !strings.HasPrefix(line, "STAT ") {
+			if strings.Contains(strings.ToLower(line), "auth") {
+				return "", errBruteAuthFailed
+			}
+			return "", fmt.Errorf("unexpected memcached response: %s", line)
+		}
+		if strings.HasPrefix(line, "STAT version") || strings.HasPrefix(line, "STAT pid") || strings.HasPrefix(line, "STAT curr_items") {
+			lines = append(lines, line)
+		}
+		if err != nil {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return "", errors.New("empty memcached stats")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// elasticsearchUnauth 请求根路径，返回集群信息即为未授权访问，依次尝试 HTTP 和 HTTPS
+func elasticsearchUnauth(ctx context.Context, host string, port int, timeout time.Duration) (string, error) {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	var lastErr error
+	for _, scheme := range []string{"http", "https"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+addr+"/", nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, bruteProofMaxSize))
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			return "", errBruteAuthFailed
+		case resp.StatusCode == http.StatusOK && strings.Contains(string(body), `"cluster_name"`):
+			return truncateProof(string(body)), nil
+		}
+		return "", fmt.Errorf("not an elasticsearch response: %d", resp.StatusCode)
+	}
+	return "", lastErr
+}
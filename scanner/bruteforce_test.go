@@ -0,0 +1,304 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseCredentials(t *testing.T) {
+	creds := ParseCredentials("# comment\nroot:toor\r\n\nadmin:pa:ss\nsecret\nroot:toor\nempty:\n")
+	want := []Credential{
+		{Username: "root", Password: "toor"},
+		{Username: "admin", Password: "pa:ss"},
+		{Password: "secret"},
+		{Username: "empty", Password: ""},
+	}
+	if len(creds) != len(want) {
+		t.Fatalf("ParseCredentials() = %v, want %v", creds, want)
+	}
+	for i := range want {
+		if creds[i] != want[i] {
+			t.Errorf("creds[%d] = %v, want %v", i, creds[i], want[i])
+		}
+	}
+}
+
+func TestBruteProtocolOf(t *testing.T) {
+	tests := []struct {
+		asset *Asset
+		want  string
+	}{
+		{&Asset{Port: 2222, Service: "ssh"}, BruteSSH},
+		{&Asset{Port: 3307, Service: "mariadb"}, BruteMySQL},
+		{&Asset{Port: 6379}, BruteRedis},
+		{&Asset{Port: 445, Service: "tcpwrapped"}, BruteSMB},
+		{&Asset{Port: 9201, Service: "http", App: []string{"Elasticsearch"}}, BruteElasticsearch},
+		{&Asset{Port: 22, Service: "http"}, ""},
+		{&Asset{Port: 8080}, ""},
+		{&Asset{Port: 11211, Service: "memcached", Transport: TransportUDP}, ""},
+	}
+	for _, tt := range tests {
+		if got := bruteProtocolOf(tt.asset); got != tt.want {
+			t.Errorf("bruteProtocolOf(%d/%s) = %q, want %q", tt.asset.Port, tt.asset.Service, got, tt.want)
+		}
+	}
+}
+
+// fakeBruteService 记录登录尝试，按 accept 中的凭据判定成功
+type fakeBruteService struct {
+	mu       sync.Mutex
+	attempts []Credential
+	accept   map[Credential]bool
+	anyCred  bool
+	lockout  string // 该账号的第一次尝试返回锁定
+	connErr  bool
+}
+
+func (f *fakeBruteService) login(_ context.Context, _ string, _ int, cred Credential, _ time.Duration) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts = append(f.attempts, cred)
+	switch {
+	case f.connErr:
+		return "", errors.New("connection refused")
+	case f.anyCred || f.accept[cred]:
+		return "welcome " + cred.Username, nil
+	case cred.Username == f.lockout:
+		return "", errBruteLockedOut
+	}
+	return "", errBruteAuthFailed
+}
+
+// dictAttempts 去掉第一次随机凭据探测后的尝试
+func (f *fakeBruteService) dictAttempts() []Credential {
+	if len(f.attempts) == 0 {
+		return nil
+	}
+	return f.attempts[1:]
+}
+
+func runBruteforce(t *testing.T, p *bruteProtocol, opts *BruteforceOptions) *ScanResult {
+	t.Helper()
+	s := NewBruteforceScanner()
+	s.protocols = map[string]*bruteProtocol{BruteSSH: p, BruteRedis: p, BruteSMB: p}
+	if opts.Interval == 0 {
+		opts.Interval = 1
+	}
+	result, err := s.Scan(context.Background(), &ScanConfig{
+		Assets:  []*Asset{{Authority: "10.0.0.1:22", Host: "10.0.0.1", Port: 22, Service: "ssh"}},
+		Options: opts,
+	})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	return result
+}
+
+func TestBruteforceScan(t *testing.T) {
+	t.Run("weak credential", func(t *testing.T) {
+		f := &fakeBruteService{accept: map[Credential]bool{{Username: "root", Password: "s3cret"}: true}}
+		result := runBruteforce(t, &bruteProtocol{defaultUser: "root", login: f.login}, &BruteforceOptions{
+			Credentials: map[string][]Credential{BruteSSH: ParseCredentials("admin:admin\ns3cret\nroot:other")},
+		})
+		if len(result.Vulnerabilities) != 1 {
+			t.Fatalf("got %d vulnerabilities, want 1", len(result.Vulnerabilities))
+		}
+		v := result.Vulnerabilities[0]
+		if v.Severity != "critical" || v.PocFile != "bruteforce-ssh" || v.Response != "welcome root" {
+			t.Errorf("unexpected vulnerability: %+v", v)
+		}
+		if len(v.ExtractedResults) != 1 || v.ExtractedResults[0] != "root:s3cret" {
+			t.Errorf("ExtractedResults = %v, want [root:s3cret]", v.ExtractedResults)
+		}
+		if n := len(f.dictAttempts()); n != 2 {
+			t.Errorf("attempts = %d, want 2 (stop after success)", n)
+		}
+	})
+
+	t.Run("accepts any credential", func(t *testing.T) {
+		f := &fakeBruteService{anyCred: true}
+		result := runBruteforce(t, &bruteProtocol{defaultUser: "root", login: f.login}, &BruteforceOptions{})
+		if len(result.Vulnerabilities) != 0 || len(f.attempts) != 1 {
+			t.Errorf("vulnerabilities = %d, attempts = %d, want 0 and 1", len(result.Vulnerabilities), len(f.attempts))
+		}
+	})
+
+	t.Run("unauthorized access", func(t *testing.T) {
+		f := &fakeBruteService{}
+		p := &bruteProtocol{
+			login: f.login,
+			unauth: func(context.Context, string, int, time.Duration) (string, error) {
+				return "redis_version:7.0.0", nil
+			},
+		}
+		result := runBruteforce(t, p, &BruteforceOptions{})
+		if len(result.Vulnerabilities) != 1 || result.Vulnerabilities[0].PocFile != "unauth-ssh" {
+			t.Fatalf("unexpected result: %+v", result.Vulnerabilities)
+		}
+		if len(f.attempts) != 0 {
+			t.Errorf("login attempted %d times after unauthorized access", len(f.attempts))
+		}
+	})
+
+	t.Run("lockout protocol per user cap", func(t *testing.T) {
+		f := &fakeBruteService{}
+		result := runBruteforce(t, &bruteProtocol{defaultUser: "administrator", lockout: true, login: f.login}, &BruteforceOptions{
+			Credentials: map[string][]Credential{BruteSSH: ParseCredentials("1\n2\n3\n4\n5\nguest:1\nguest:2")},
+			MaxPerUser:  10,
+		})
+		if len(result.Vulnerabilities) != 0 {
+			t.Fatalf("unexpected vulnerabilities: %+v", result.Vulnerabilities)
+		}
+		perUser := make(map[string]int)
+		for _, c := range f.dictAttempts() {
+			perUser[c.Username]++
+		}
+		if perUser["administrator"] != lockoutSafeAttempts || perUser["guest"] != 2 {
+			t.Errorf("attempts per user = %v", perUser)
+		}
+	})
+
+	t.Run("lockout protocol cap shared across hosts", func(t *testing.T) {
+		f := &fakeBruteService{}
+		s := NewBruteforceScanner()
+		s.protocols = map[string]*bruteProtocol{BruteSMB: {defaultUser: "administrator", lockout: true, login: f.login}}
+		var assets []*Asset
+		for _, host := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+			assets = append(assets, &Asset{Authority: host + ":445", Host: host, Port: 445, Service: "microsoft-ds"})
+		}
+		_, err := s.Scan(context.Background(), &ScanConfig{
+			Assets: assets,
+			Options: &BruteforceOptions{
+				Credentials: map[string][]Credential{BruteSMB: ParseCredentials("1\n2\n3\n4\n5")},
+				Interval:    1,
+			},
+		})
+		if err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		n := 0
+		for _, c := range f.attempts {
+			if c.Username == "administrator" {
+				n++
+			}
+		}
+		if n != lockoutSafeAttempts {
+			t.Errorf("administrator attempts across hosts = %d, want %d", n, lockoutSafeAttempts)
+		}
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		f := &fakeBruteService{}
+		runBruteforce(t, &bruteProtocol{defaultUser: "root", login: f.login}, &BruteforceOptions{
+			Credentials: map[string][]Credential{BruteSSH: ParseCredentials("1\n2\n3\n4\n5\n6")},
+			MaxAttempts: 4,
+		})
+		if n := len(f.dictAttempts()); n != 4 {
+			t.Errorf("attempts = %d, want 4", n)
+		}
+	})
+
+	t.Run("locked out", func(t *testing.T) {
+		f := &fakeBruteService{lockout: "root"}
+		runBruteforce(t, &bruteProtocol{defaultUser: "root", login: f.login}, &BruteforceOptions{
+			Credentials: map[string][]Credential{BruteSSH: ParseCredentials("1\n2\nadmin:3")},
+		})
+		if n := len(f.dictAttempts()); n != 1 {
+			t.Errorf("attempts = %d, want 1 (stop on lockout)", n)
+		}
+	})
+
+	t.Run("connection errors", func(t *testing.T) {
+		f := &fakeBruteService{connErr: true}
+		runBruteforce(t, &bruteProtocol{defaultUser: "root", login: f.login}, &BruteforceOptions{})
+		if len(f.attempts) != 1 {
+			t.Errorf("attempts = %d, want 1 (unreachable on canary)", len(f.attempts))
+		}
+	})
+}
+
+// serveFTP 启动只接受 admin/admin 的简易 FTP 服务
+func serveFTP(t *testing.T) (string, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				fmt.Fprint(conn, "220 test ftpd\r\n")
+				var user string
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					cmd, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+					switch cmd {
+					case "USER":
+						user = arg
+						fmt.Fprint(conn, "331 Password required\r\n")
+					case "PASS":
+						if user == "admin" && arg == "admin" {
+							fmt.Fprint(conn, "230 Login successful\r\n")
+						} else {
+							fmt.Fprint(conn, "530 Login incorrect\r\n")
+						}
+					case "QUIT":
+						fmt.Fprint(conn, "221 Goodbye\r\n")
+						return
+					default:
+						fmt.Fprint(conn, "500 Unknown command\r\n")
+					}
+				}
+			}(conn)
+		}
+	}()
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func TestFTPLogin(t *testing.T) {
+	host, port := serveFTP(t)
+	ctx := context.Background()
+
+	proof, err := ftpLogin(ctx, host, port, Credential{Username: "admin", Password: "admin"}, 2*time.Second)
+	if err != nil {
+		t.Fatalf("ftpLogin() error = %v", err)
+	}
+	if !strings.Contains(proof, "230 Login successful") {
+		t.Errorf("proof = %q", proof)
+	}
+	if _, err := ftpLogin(ctx, host, port, Credential{Username: "admin", Password: "wrong"}, 2*time.Second); !errors.Is(err, errBruteAuthFailed) {
+		t.Errorf("ftpLogin() wrong password error = %v, want errBruteAuthFailed", err)
+	}
+
+	s := NewBruteforceScanner()
+	result, err := s.Scan(ctx, &ScanConfig{
+		Assets: []*Asset{{Host: host, Port: port, Service: "ftp"}},
+		Options: &BruteforceOptions{
+			Credentials: map[string][]Credential{BruteFTP: ParseCredentials("anonymous:x\nadmin:admin")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Vulnerabilities) != 1 || result.Vulnerabilities[0].ExtractedResults[0] != "admin:admin" {
+		t.Errorf("unexpected result: %+v", result.Vulnerabilities)
+	}
+}
@@ -0,0 +1,491 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// CDN 识别库中的服务商类型
+const (
+	CDNTypeCDN   = "cdn"
+	CDNTypeWAF   = "waf"
+	CDNTypeCloud = "cloud"
+)
+
+// CDNProvider CDN/WAF/云厂商识别规则
+// 任一 CNAME 后缀、IP段、响应头或拦截页特征命中即认为资产属于该服务商
+type CDNProvider struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`                   // cdn/waf/cloud
+	CNAMEs       []string `json:"cnames,omitempty"`       // CNAME 后缀
+	CIDRs        []string `json:"cidrs,omitempty"`        // 服务商IP段
+	Headers      []string `json:"headers,omitempty"`      // 响应头，"Name" 匹配头存在，"Name: value" 匹配值包含
+	BodyPatterns []string `json:"bodyPatterns,omitempty"` // 拦截页或错误页特征
+}
+
+// Validate 校验识别规则
+func (p *CDNProvider) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch p.Type {
+	case CDNTypeCDN, CDNTypeWAF, CDNTypeCloud:
+	default:
+		return fmt.Errorf("unknown provider type %q", p.Type)
+	}
+	if len(p.CNAMEs) == 0 && len(p.CIDRs) == 0 && len(p.Headers) == 0 && len(p.BodyPatterns) == 0 {
+		return fmt.Errorf("provider %s has no rules", p.Name)
+	}
+	for _, c := range p.CIDRs {
+		if _, _, err := net.ParseCIDR(c); err != nil {
+			return fmt.Errorf("invalid cidr %q", c)
+		}
+	}
+	return nil
+}
+
+// DefaultCDNProviders 内置识别规则，用于初始化识别库
+// IP段仅收录服务商公开发布的主要网段，完整网段可在识别库中更新
+func DefaultCDNProviders() []*CDNProvider {
+	return []*CDNProvider{
+		{
+			Name:   "cloudflare",
+			Type:   CDNTypeCDN,
+			CNAMEs: []string{"cdn.cloudflare.net", "cloudflare.net"},
+			CIDRs: []string{
+				"173.245.48.0/20", "103.21.244.0/22", "103.22.200.0/22", "103.31.4.0/22",
+				"141.101.64.0/18", "108.162.192.0/18", "190.93.240.0/20", "188.114.96.0/20",
+				"197.234.240.0/22", "198.41.128.0/17", "162.158.0.0/15", "104.16.0.0/13",
+				"104.24.0.0/14", "172.64.0.0/13", "131.0.72.0/22",
+				"2400:cb00::/32", "2606:4700::/32", "2803:f800::/32", "2405:b500::/32",
+				"2405:8100::/32", "2a06:98c0::/29", "2c0f:f248::/32",
+			},
+			Headers: []string{"cf-ray", "server: cloudflare"},
+		},
+		{
+			Name:   "cloudfront",
+			Type:   CDNTypeCDN,
+			CNAMEs: []string{"cloudfront.net"},
+			CIDRs: []string{
+				"13.32.0.0/15", "13.224.0.0/14", "52.84.0.0/15", "54.182.0.0/16",
+				"54.192.0.0/16", "54.230.0.0/16", "54.239.128.0/18", "99.84.0.0/16",
+				"143.204.0.0/16", "205.251.192.0/19",
+			},
+			Headers: []string{"x-amz-cf-id", "via: cloudfront"},
+		},
+		{
+			Name:    "akamai",
+			Type:    CDNTypeCDN,
+			CNAMEs:  []string{"akamai.net", "akamaiedge.net", "akamaized.net", "edgekey.net", "edgesuite.net", "akamaitechnologies.com"},
+			CIDRs:   []string{"23.32.0.0/11", "23.192.0.0/11", "2.16.0.0/13", "104.64.0.0/10", "184.24.0.0/13"},
+			Headers: []string{"x-akamai-transformed", "server: akamaighost", "x-akamai-request-id"},
+		},
+		{
+			Name:   "fastly",
+			Type:   CDNTypeCDN,
+			CNAMEs: []string{"fastly.net", "fastlylb.net"},
+			CIDRs: []string{
+				"23.235.32.0/20", "43.249.72.0/22", "103.244.50.0/24", "103.245.222.0/23",
+				"103.245.224.0/24", "104.156.80.0/20", "140.248.64.0/18", "140.248.128.0/17",
+				"146.75.0.0/17", "151.101.0.0/16", "157.52.64.0/18", "167.82.0.0/17",
+				"172.111.64.0/18", "185.31.16.0/22", "199.27.72.0/21", "199.232.0.0/16",
+			},
+			Headers: []string{"x-fastly-request-id", "fastly-debug-digest"},
+		},
+		{Name: "azure-cdn", Type: CDNTypeCDN, CNAMEs: []string{"azureedge.net", "azurefd.net", "trafficmanager.net"}, Headers: []string{"x-azure-ref", "x-msedge-ref"}},
+		{Name: "google-cdn", Type: CDNTypeCDN, CNAMEs: []string{"googlehosted.com", "googleusercontent.com"}, Headers: []string{"via: 1.1 google"}},
+		{Name: "stackpath", Type: CDNTypeCDN, CNAMEs: []string{"stackpathdns.com", "stackpathcdn.com", "hwcdn.net"}, Headers: []string{"x-hw"}},
+		{Name: "cdn77", Type: CDNTypeCDN, CNAMEs: []string{"cdn77.org", "cdn77.net"}},
+		{Name: "bunnycdn", Type: CDNTypeCDN, CNAMEs: []string{"b-cdn.net"}, Headers: []string{"server: bunnycdn"}},
+		{Name: "aliyun-cdn", Type: CDNTypeCDN, CNAMEs: []string{"kunlunca.com", "kunlunsl.com", "alikunlun.com", "alikunlun.net", "aliyuncdn.com", "alicdn.com", "w.cdngslb.com"}, Headers: []string{"eagleid"}},
+		{Name: "tencent-cdn", Type: CDNTypeCDN, CNAMEs: []string{"cdn.dnsv1.com", "dsa.dnsv1.com", "tdnsv5.com", "tdnsv6.com", "qcloudcdn.com", "cdntip.com"}, Headers: []string{"x-nws-log-uuid"}},
+		{Name: "baidu-cdn", Type: CDNTypeCDN, CNAMEs: []string{"yunjiasu-cdn.net", "bdydns.com", "jomodns.com"}},
+		{Name: "huawei-cdn", Type: CDNTypeCDN, CNAMEs: []string{"cdnhwc1.com", "cdnhwc2.com", "cdnhwc3.com", "c.cdnhwc1.com"}},
+		{Name: "wangsu", Type: CDNTypeCDN, CNAMEs: []string{"wscdns.com", "wscloudcdn.com", "chinanetcenter.com", "lxdns.com", "ourwebcdn.com"}},
+		{Name: "qiniu", Type: CDNTypeCDN, CNAMEs: []string{"qiniudns.com", "qiniucdn.com"}},
+		{Name: "chinacache", Type: CDNTypeCDN, CNAMEs: []string{"ccgslb.com", "ccgslb.net", "chinacache.net"}},
+		{Name: "upyun", Type: CDNTypeCDN, CNAMEs: []string{"upaiyun.com", "upcdn.net"}},
+		{Name: "imperva", Type: CDNTypeWAF, CNAMEs: []string{"incapdns.net", "impervadns.net"}, Headers: []string{"x-iinfo", "x-cdn: incapsula"}, BodyPatterns: []string{"Incapsula incident ID", "_Incapsula_Resource"}},
+		{Name: "sucuri", Type: CDNTypeWAF, CNAMEs: []string{"sucuri.net", "sucuridns.com"}, Headers: []string{"x-sucuri-id", "server: sucuri"}, BodyPatterns: []string{"Sucuri WebSite Firewall - Access Denied"}},
+		{Name: "cloudflare-waf", Type: CDNTypeWAF, BodyPatterns: []string{"Attention Required! | Cloudflare", "cf-error-details"}},
+		{Name: "aws-waf", Type: CDNTypeWAF, Headers: []string{"x-amzn-waf-action"}, BodyPatterns: []string{"Request blocked. We can't connect to the server for this app or website at this time."}},
+		{Name: "akamai-waf", Type: CDNTypeWAF, BodyPatterns: []string{"errors.edgesuite.net"}},
+		{Name: "f5-asm", Type: CDNTypeWAF, BodyPatterns: []string{"The requested URL was rejected. Please consult with your administrator."}},
+		{Name: "modsecurity", Type: CDNTypeWAF, Headers: []string{"server: mod_security"}, BodyPatterns: []string{"This error was generated by Mod_Security", "Mod_Security"}},
+		{Name: "safeline", Type: CDNTypeWAF, BodyPatterns: []string{"safeline", "雷池"}},
+		{Name: "safedog", Type: CDNTypeWAF, Headers: []string{"x-powered-by: waf/2.0", "server: safedog"}, BodyPatterns: []string{"www.safedog.cn", "safedogsite"}},
+		{Name: "yunsuo", Type: CDNTypeWAF, Headers: []string{"set-cookie: yunsuo_session"}, BodyPatterns: []string{"yunsuologo"}},
+		{Name: "jiasule", Type: CDNTypeWAF, CNAMEs: []string{"jiashule.com", "jiasule.org"}, Headers: []string{"set-cookie: __jsluid", "server: jiasule"}, BodyPatterns: []string{"static.jiasule.com"}},
+		{Name: "btwaf", Type: CDNTypeWAF, BodyPatterns: []string{"btwaf", "宝塔网站防火墙"}},
+		{Name: "aliyun-waf", Type: CDNTypeWAF, CNAMEs: []string{"yundunwaf1.com", "yundunwaf2.com", "yundunwaf3.com", "aliyunwaf.com"}, BodyPatterns: []string{"errors.aliyun.com"}},
+		{Name: "aws", Type: CDNTypeCloud, CNAMEs: []string{"amazonaws.com", "elasticbeanstalk.com", "elb.amazonaws.com", "awsglobalaccelerator.com"}, Headers: []string{"x-amz-request-id", "x-amzn-requestid"}},
+		{Name: "azure", Type: CDNTypeCloud, CNAMEs: []string{"azurewebsites.net", "cloudapp.net", "cloudapp.azure.com", "blob.core.windows.net", "azure-api.net", "azurecontainer.io"}},
+		{Name: "gcp", Type: CDNTypeCloud, CNAMEs: []string{"appspot.com", "run.app", "cloudfunctions.net", "storage.googleapis.com"}, CIDRs: []string{"34.64.0.0/10", "35.184.0.0/13"}},
+		{Name: "aliyun", Type: CDNTypeCloud, CNAMEs: []string{"aliyuncs.com", "aliyun.com", "alibabacloud.com"}},
+		{Name: "tencent-cloud", Type: CDNTypeCloud, CNAMEs: []string{"myqcloud.com", "tencentcs.com", "tencentcloudapi.com"}},
+		{Name: "huawei-cloud", Type: CDNTypeCloud, CNAMEs: []string{"myhuaweicloud.com", "huaweicloud.com"}},
+		{Name: "digitalocean", Type: CDNTypeCloud, CNAMEs: []string{"digitaloceanspaces.com", "ondigitalocean.app"}},
+		{Name: "heroku", Type: CDNTypeCloud, CNAMEs: []string{"herokuapp.com", "herokudns.com"}, Headers: []string{"via: 1.1 vegur"}},
+		{Name: "vercel", Type: CDNTypeCloud, CNAMEs: []string{"vercel.app", "vercel-dns.com"}, Headers: []string{"x-vercel-id"}},
+		{Name: "netlify", Type: CDNTypeCloud, CNAMEs: []string{"netlify.app", "netlify.com"}, Headers: []string{"x-nf-request-id"}},
+	}
+}
+
+// CDNMatch 一条识别结果
+type CDNMatch struct {
+	Provider string `json:"provider"`
+	Type     string `json:"type"`
+	Evidence string `json:"evidence"` // cname/ip/header/body
+}
+
+type cdnNet struct {
+	provider *CDNProvider
+	ipNet    *net.IPNet
+}
+
+// CDNDetector 按识别规则匹配 CNAME、IP、响应头和响应体
+type CDNDetector struct {
+	providers []*CDNProvider
+	nets      []cdnNet
+}
+
+// NewCDNDetector 创建识别器，无效的 CIDR 被忽略
+func NewCDNDetector(providers []*CDNProvider) *CDNDetector {
+	d := &CDNDetector{providers: providers}
+	for _, p := range providers {
+		for _, c := range p.CIDRs {
+			if _, ipNet, err := net.ParseCIDR(c); err == nil {
+				d.nets = append(d.nets, cdnNet{provider: p, ipNet: ipNet})
+			}
+		}
+	}
+	return d
+}
+
+// MatchCNAME 按 CNAME 后缀匹配服务商
+func (d *CDNDetector) MatchCNAME(cname string) *CDNProvider {
+	cname = strings.ToLower(strings.TrimSuffix(cname, "."))
+	if cname == "" {
+		return nil
+	}
+	for _, p := range d.providers {
+		for _, suffix := range p.CNAMEs {
+			suffix = strings.ToLower(strings.TrimPrefix(suffix, "."))
+			if cname == suffix || strings.HasSuffix(cname, "."+suffix) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// MatchIP 按IP段匹配服务商
+func (d *CDNDetector) MatchIP(ip string) *CDNProvider {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil
+	}
+	for _, n := range d.nets {
+		if n.ipNet.Contains(parsed) {
+			return n.provider
+		}
+	}
+	return nil
+}
+
+// MatchHTTP 按响应头和响应体匹配服务商，返回全部命中的服务商
+func (d *CDNDetector) MatchHTTP(header, body string) []*CDNProvider {
+	if header == "" && body == "" {
+		return nil
+	}
+	headers := parseHeaderLines(header)
+	lowerBody := strings.ToLower(body)
+
+	var matched []*CDNProvider
+	for _, p := range d.providers {
+		if matchHeaderRules(headers, p.Headers) || matchBodyPatterns(lowerBody, p.BodyPatterns) {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
+
+// Detect 识别资产所属的服务商，每种类型只保留第一个命中
+func (d *CDNDetector) Detect(asset *Asset) []CDNMatch {
+	var matches []CDNMatch
+	seen := make(map[string]bool)
+	add := func(p *CDNProvider, evidence string) {
+		if p == nil || seen[p.Type] {
+			return
+		}
+		seen[p.Type] = true
+		matches = append(matches, CDNMatch{Provider: p.Name, Type: p.Type, Evidence: evidence})
+	}
+
+	add(d.MatchCNAME(asset.CName), "cname")
+	for _, ip := range assetIPs(asset) {
+		add(d.MatchIP(ip), "ip")
+	}
+	for _, p := range d.MatchHTTP(asset.HttpHeader, asset.HttpBody) {
+		add(p, "http")
+	}
+	return matches
+}
+
+// Tag 将识别结果写入资产，返回是否命中
+func (d *CDNDetector) Tag(asset *Asset) bool {
+	matches := d.Detect(asset)
+	for _, m := range matches {
+		switch m.Type {
+		case CDNTypeCDN:
+			asset.IsCDN = true
+			asset.CDNProvider = m.Provider
+		case CDNTypeWAF:
+			asset.WAF = m.Provider
+		case CDNTypeCloud:
+			asset.IsCloud = true
+			asset.CloudProvider = m.Provider
+		}
+	}
+	return len(matches) > 0
+}
+
+// assetIPs 资产的全部IP，主机为IP时包含主机本身
+func assetIPs(asset *Asset) []string {
+	var ips []string
+	if net.ParseIP(asset.Host) != nil {
+		ips = append(ips, asset.Host)
+	}
+	for _, ip := range asset.IPV4 {
+		ips = append(ips, ip.IP)
+	}
+	for _, ip := range asset.IPV6 {
+		ips = append(ips, ip.IP)
+	}
+	return ips
+}
+
+// parseHeaderLines 解析原始响应头，返回小写的 name -> values，状态行被忽略
+func parseHeaderLines(raw string) map[string][]string {
+	headers := make(map[string][]string)
+	for _, line := range strings.Split(raw, "\n") {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || strings.Contains(name, " ") {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		headers[name] = append(headers[name], strings.ToLower(strings.TrimSpace(value)))
+	}
+	return headers
+}
+
+func matchHeaderRules(headers map[string][]string, rules []string) bool {
+	for _, rule := range rules {
+		name, want, hasValue := strings.Cut(rule, ":")
+		values, ok := headers[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			continue
+		}
+		if !hasValue {
+			return true
+		}
+		want = strings.ToLower(strings.TrimSpace(want))
+		for _, v := range values {
+			if strings.Contains(v, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchBodyPatterns(lowerBody string, patterns []string) bool {
+	if lowerBody == "" {
+		return false
+	}
+	for _, p := range patterns {
+		if p != "" && strings.Contains(lowerBody, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CDNOptions CDN识别选项
+type CDNOptions struct {
+	Providers   []*CDNProvider `json:"providers"`   // 识别规则，为空时使用内置规则
+	Concurrency int            `json:"concurrency"` // 并发解析域名数
+}
+
+// Validate 验证选项
+func (o *CDNOptions) Validate() error {
+	if o.Concurrency < 0 {
+		return fmt.Errorf("concurrency must be non-negative")
+	}
+	for _, p := range o.Providers {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cdnResolver CDN识别使用的DNS查询，*net.Resolver 满足此接口
+type cdnResolver interface {
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// CDNScanner CDN/WAF/云厂商识别
+// 域名资产会补充解析 CNAME 和IP后再按识别规则匹配，命中的资产会被标记服务商
+type CDNScanner struct {
+	BaseScanner
+	resolver cdnResolver
+}
+
+// NewCDNScanner 创建CDN识别扫描器
+func NewCDNScanner() *CDNScanner {
+	return &CDNScanner{
+		BaseScanner: BaseScanner{name: "cdn"},
+		resolver:    net.DefaultResolver,
+	}
+}
+
+// cdnResolution 域名解析结果
+type cdnResolution struct {
+	host  string
+	cname string
+	ips   []string
+}
+
+// Scan 识别资产所属的CDN/WAF/云厂商，返回被标记的资产
+func (s *CDNScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	result := &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+	}
+	opts, _ := GetTypedOptions[*CDNOptions](config)
+	if opts == nil {
+		opts = &CDNOptions{}
+	}
+	detector := s.detector(opts)
+
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, asset := range config.Assets {
+		if asset.Host == "" || net.ParseIP(asset.Host) != nil || seen[asset.Host] {
+			continue
+		}
+		if asset.CName != "" && len(asset.IPV4)+len(asset.IPV6) > 0 {
+			continue
+		}
+		seen[asset.Host] = true
+		hosts = append(hosts, asset.Host)
+	}
+	resolved := s.resolveAll(ctx, hosts, opts.Concurrency)
+
+	for _, asset := range config.Assets {
+		probe := asset
+		if r, ok := resolved[asset.Host]; ok {
+			// 仅用于匹配的副本，解析到的IP不写回资产
+			cp := *asset
+			if cp.CName == "" {
+				cp.CName = r.cname
+				asset.CName = r.cname
+			}
+			for _, ip := range r.ips {
+				cp.IPV4 = append(cp.IPV4, IPInfo{IP: ip})
+			}
+			probe = &cp
+		}
+		if !detector.Tag(probe) {
+			continue
+		}
+		if probe != asset {
+			asset.IsCDN, asset.CDNProvider = probe.IsCDN, probe.CDNProvider
+			asset.IsCloud, asset.CloudProvider = probe.IsCloud, probe.CloudProvider
+			asset.WAF = probe.WAF
+		}
+		result.Assets = append(result.Assets, asset)
+	}
+
+	if config.TaskLogger != nil {
+		config.TaskLogger("INFO", "CDN: %d/%d assets matched CDN/WAF/cloud providers", len(result.Assets), len(config.Assets))
+	}
+	return result, ctx.Err()
+}
+
+// FilterTargets 过滤指向CDN边缘节点的目标，返回保留和跳过的目标
+// IP 目标按IP段匹配，域名目标按 CNAME 或全部解析IP匹配，CIDR 等其他目标原样保留
+func (s *CDNScanner) FilterTargets(ctx context.Context, targets []string, opts *CDNOptions) (kept, skipped []string) {
+	if opts == nil {
+		opts = &CDNOptions{}
+	}
+	detector := s.detector(opts)
+	isEdge := func(p *CDNProvider) bool {
+		return p != nil && p.Type != CDNTypeCloud
+	}
+
+	var domains []string
+	for _, t := range targets {
+		if net.ParseIP(t) == nil && !strings.Contains(t, "/") && !strings.Contains(t, ":") {
+			domains = append(domains, t)
+		}
+	}
+	resolved := s.resolveAll(ctx, domains, opts.Concurrency)
+
+	for _, t := range targets {
+		edge := false
+		if net.ParseIP(t) != nil {
+			edge = isEdge(detector.MatchIP(t))
+		} else if r, ok := resolved[t]; ok {
+			edge = isEdge(detector.MatchCNAME(r.cname))
+			if !edge && len(r.ips) > 0 {
+				edge = true
+				for _, ip := range r.ips {
+					if !isEdge(detector.MatchIP(ip)) {
+						edge = false
+						break
+					}
+				}
+			}
+		}
+		if edge {
+			skipped = append(skipped, t)
+		} else {
+			kept = append(kept, t)
+		}
+	}
+	return kept, skipped
+}
+
+func (s *CDNScanner) detector(opts *CDNOptions) *CDNDetector {
+	providers := opts.Providers
+	if len(providers) == 0 {
+		providers = DefaultCDNProviders()
+	}
+	return NewCDNDetector(providers)
+}
+
+// resolveAll 并发解析域名的 CNAME 和IP
+func (s *CDNScanner) resolveAll(ctx context.Context, hosts []string, concurrency int) map[string]*cdnResolution {
+	results, _ := ExecuteGeneric(ctx, concurrency, hosts, func(ctx context.Context, host string) (*cdnResolution, error) {
+		r := &cdnResolution{host: host}
+		if cname, err := s.resolver.LookupCNAME(ctx, host); err == nil {
+			cname = strings.TrimSuffix(cname, ".")
+			if !strings.EqualFold(cname, host) {
+				r.cname = cname
+			}
+		}
+		r.ips, _ = s.resolver.LookupHost(ctx, host)
+		return r, nil
+	})
+
+	resolved := make(map[string]*cdnResolution, len(results))
+	for _, r := range results {
+		resolved[r.host] = r
+	}
+	return resolved
+}
@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCDNScannerScan(t *testing.T) {
+	s := NewCDNScanner()
+	s.resolver = &fakeTakeoverResolver{
+		cname: map[string]string{"www.example.com": "www.example.com.cdn.cloudflare.net"},
+		hosts: map[string][]string{
+			"www.example.com.cdn.cloudflare.net": {"104.16.1.1"},
+			"www.example.com":                    {"104.16.1.1"},
+			"app.example.com":                    {"10.0.0.5"},
+		},
+	}
+
+	cdn := &Asset{Authority: "www.example.com", Host: "www.example.com", Category: "domain"}
+	edge := &Asset{Authority: "151.101.1.1:443", Host: "151.101.1.1", Port: 443}
+	waf := &Asset{Authority: "app.example.com:80", Host: "app.example.com", Port: 80,
+		HttpHeader: "HTTP/1.1 403 Forbidden\nServer: nginx\nX-Iinfo: 1-2-3",
+		HttpBody:   "Request unsuccessful. Incapsula incident ID: 123"}
+	cloud := &Asset{Authority: "shop.example.com", Host: "shop.example.com", CName: "shop.azurewebsites.net", IPV4: []IPInfo{{IP: "20.1.1.1"}}}
+	plain := &Asset{Authority: "10.0.0.5:22", Host: "10.0.0.5", Port: 22}
+
+	result, err := s.Scan(context.Background(), &ScanConfig{Assets: []*Asset{cdn, edge, waf, cloud, plain}})
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(result.Assets) != 4 {
+		t.Fatalf("tagged assets = %d, want 4", len(result.Assets))
+	}
+	if !cdn.IsCDN || cdn.CDNProvider != "cloudflare" || cdn.CName != "www.example.com.cdn.cloudflare.net" {
+		t.Errorf("cname asset = %+v", cdn)
+	}
+	if len(cdn.IPV4) != 0 {
+		t.Errorf("resolved IPs should not be written back: %v", cdn.IPV4)
+	}
+	if !edge.IsCDN || edge.CDNProvider != "fastly" {
+		t.Errorf("ip asset = %+v", edge)
+	}
+	if waf.WAF != "imperva" || waf.IsCDN {
+		t.Errorf("waf asset = %+v", waf)
+	}
+	if !cloud.IsCloud || cloud.CloudProvider != "azure" || cloud.IsCDN {
+		t.Errorf("cloud asset = %+v", cloud)
+	}
+	if plain.IsCDN || plain.IsCloud || plain.WAF != "" {
+		t.Errorf("plain asset tagged: %+v", plain)
+	}
+}
+
+func TestCDNScannerFilterTargets(t *testing.T) {
+	s := NewCDNScanner()
+	s.resolver = &fakeTakeoverResolver{
+		cname: map[string]string{"cdn.example.com": "cdn.example.com.edgekey.net"},
+		hosts: map[string][]string{
+			"cdn.example.com.edgekey.net": {"23.45.1.1"},
+			"cdn.example.com":             {"23.45.1.1"},
+			"ip.example.com":              {"104.16.2.2", "172.64.1.1"},
+			"mixed.example.com":           {"104.16.2.2", "1.2.3.4"},
+			"cloud.example.com":           {"34.64.1.1"},
+		},
+	}
+
+	targets := []string{"cdn.example.com", "ip.example.com", "mixed.example.com", "cloud.example.com", "104.16.0.1", "104.16.0.0/24", "8.8.8.8"}
+	kept, skipped := s.FilterTargets(context.Background(), targets, nil)
+
+	wantKept := []string{"mixed.example.com", "cloud.example.com", "104.16.0.0/24", "8.8.8.8"}
+	wantSkipped := []string{"cdn.example.com", "ip.example.com", "104.16.0.1"}
+	if !reflect.DeepEqual(kept, wantKept) {
+		t.Errorf("kept = %v, want %v", kept, wantKept)
+	}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("skipped = %v, want %v", skipped, wantSkipped)
+	}
+}
+
+func TestCDNProviderValidate(t *testing.T) {
+	for _, p := range DefaultCDNProviders() {
+		if err := p.Validate(); err != nil {
+			t.Errorf("default provider %s invalid: %v", p.Name, err)
+		}
+	}
+	invalid := []*CDNProvider{
+		{Type: CDNTypeCDN, CNAMEs: []string{"a.net"}},
+		{Name: "x", Type: "proxy", CNAMEs: []string{"a.net"}},
+		{Name: "x", Type: CDNTypeCDN},
+		{Name: "x", Type: CDNTypeCDN, CIDRs: []string{"1.2.3.4"}},
+	}
+	for i, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("invalid[%d] passed validation", i)
+		}
+	}
+}
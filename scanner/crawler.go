@@ -0,0 +1,812 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 爬虫发现的端点类型
+const (
+	CrawlEndpointPage = "page" // 页面链接
+	CrawlEndpointForm = "form" // 表单提交地址
+	CrawlEndpointAPI  = "api"  // XHR/fetch 请求或JS中的接口路径
+	CrawlEndpointJS   = "js"   // JS文件
+)
+
+// crawlMaxTextSize JS文件读取上限
+const crawlMaxTextSize = 2 << 20
+
+// crawlSettleTime 页面加载完成后等待异步请求发出的时间
+const crawlSettleTime = 2 * time.Second
+
+// SecretRule 敏感信息匹配规则
+// Pattern 含捕获组时取第一个捕获组作为泄露值，否则取整个匹配
+type SecretRule struct {
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"`
+	Severity    string `json:"severity,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// Validate 校验规则并编译正则
+func (r *SecretRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("rule %s: invalid pattern: %v", r.Name, err)
+	}
+	r.re = re
+	return nil
+}
+
+// find 返回内容中匹配的泄露值
+func (r *SecretRule) find(content string) []string {
+	if r.re == nil {
+		return nil
+	}
+	var values []string
+	for _, m := range r.re.FindAllStringSubmatch(content, -1) {
+		v := m[0]
+		if len(m) > 1 && m[1] != "" {
+			v = m[1]
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// DefaultSecretRules 内置敏感信息规则，用于初始化规则库
+func DefaultSecretRules() []*SecretRule {
+	return []*SecretRule{
+		{Name: "aws-access-key", Severity: "high", Pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+			Description: "AWS access key ID"},
+		{Name: "aws-secret-key", Severity: "high", Pattern: `(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']([A-Za-z0-9/+=]{40})["']`,
+			Description: "AWS secret access key"},
+		{Name: "aliyun-access-key", Severity: "high", Pattern: `\bLTAI[0-9A-Za-z]{12,20}\b`,
+			Description: "Alibaba Cloud AccessKey ID"},
+		{Name: "tencent-secret-id", Severity: "high", Pattern: `\bAKID[0-9A-Za-z]{13,40}\b`,
+			Description: "Tencent Cloud SecretId"},
+		{Name: "google-api-key", Severity: "medium", Pattern: `\bAIza[0-9A-Za-z_\-]{35}\b`,
+			Description: "Google API key"},
+		{Name: "github-token", Severity: "high", Pattern: `\b(?:ghp|gho|ghu|ghs|ghr)_[0-9A-Za-z]{36}\b`,
+			Description: "GitHub access token"},
+		{Name: "slack-token", Severity: "high", Pattern: `\bxox[abprs]-[0-9A-Za-z-]{10,72}\b`,
+			Description: "Slack token"},
+		{Name: "slack-webhook", Severity: "medium", Pattern: `https://hooks\.slack\.com/services/T[0-9A-Za-z_]+/B[0-9A-Za-z_]+/[0-9A-Za-z_]+`,
+			Description: "Slack incoming webhook"},
+		{Name: "stripe-secret-key", Severity: "high", Pattern: `\b(?:sk|rk)_live_[0-9A-Za-z]{24,}\b`,
+			Description: "Stripe live secret key"},
+		{Name: "private-key", Severity: "high", Pattern: `-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`,
+			Description: "PEM private key"},
+		{Name: "jwt", Severity: "medium", Pattern: `\beyJ[0-9A-Za-z_\-]{10,}\.eyJ[0-9A-Za-z_\-]{10,}\.[0-9A-Za-z_\-]{10,}`,
+			Description: "JSON Web Token"},
+		{Name: "generic-secret", Severity: "low", Pattern: `(?i)(?:api_?key|secret_?key|access_?token|client_?secret|app_?secret)["']?\s*[:=]\s*["']([0-9A-Za-z_\-+/=]{16,64})["']`,
+			Description: "Hard-coded API key or secret"},
+	}
+}
+
+// CrawlerOptions 爬虫扫描选项
+type CrawlerOptions struct {
+	MaxDepth    int           `json:"maxDepth"`    // 最大爬取深度，起始页为0
+	MaxPages    int           `json:"maxPages"`    // 每个站点最多渲染的页面数
+	Timeout     int           `json:"timeout"`     // 单个页面超时(秒)
+	Concurrency int           `json:"concurrency"` // 并发站点数
+	SecretRules []*SecretRule `json:"secretRules"` // 为 nil 时使用内置规则
+	// InScope 判断跨域主机是否在扫描范围内，为空时只收集同源端点
+	InScope func(host string) bool `json:"-"`
+}
+
+// Validate 验证 CrawlerOptions 配置是否有效
+// 实现 ScannerOptions 接口
+func (o *CrawlerOptions) Validate() error {
+	if o.MaxDepth < 0 {
+		return fmt.Errorf("maxDepth must be non-negative, got %d", o.MaxDepth)
+	}
+	if o.MaxPages < 0 {
+		return fmt.Errorf("maxPages must be non-negative, got %d", o.MaxPages)
+	}
+	if o.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative, got %d", o.Timeout)
+	}
+	if o.Concurrency < 0 {
+		return fmt.Errorf("concurrency must be non-negative, got %d", o.Concurrency)
+	}
+	for _, rule := range o.SecretRules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CrawlEndpoint 爬虫发现的端点
+type CrawlEndpoint struct {
+	URL         string   `json:"url"`
+	Method      string   `json:"method"`
+	Type        string   `json:"type"`                  // page/form/api/js
+	Params      []string `json:"params,omitempty"`      // 查询参数和表单字段名
+	StatusCode  int      `json:"statusCode,omitempty"`  // 仅渲染过的页面有值
+	ContentType string   `json:"contentType,omitempty"` // 仅渲染过的页面有值
+	Source      string   `json:"source,omitempty"`      // 发现该端点的页面或JS文件
+}
+
+// CrawlForm 页面中的表单
+type CrawlForm struct {
+	Action string   `json:"action"`
+	Method string   `json:"method"`
+	Inputs []string `json:"inputs"`
+}
+
+// CrawlRequest 页面加载过程中发出的XHR/fetch请求
+type CrawlRequest struct {
+	URL    string `json:"url"`
+	Method string `json:"method"`
+}
+
+// CrawlPage 浏览器渲染后的页面，链接均为绝对地址
+type CrawlPage struct {
+	URL         string // 跳转后的最终地址
+	StatusCode  int
+	ContentType string
+	HTML        string
+	Links       []string
+	Scripts     []string
+	Forms       []CrawlForm
+	Requests    []CrawlRequest
+}
+
+// crawlFetchFunc 渲染页面
+type crawlFetchFunc func(ctx context.Context, pageURL string, timeout time.Duration) (*CrawlPage, error)
+
+// CrawlerScanner 基于无头浏览器的爬虫扫描器
+// 从HTTP资产出发按深度爬取同源页面，收集链接、表单、接口和JS文件，并在页面和JS中匹配敏感信息
+type CrawlerScanner struct {
+	BaseScanner
+	// openBrowser 启动浏览器，返回页面渲染函数和关闭函数
+	openBrowser func(ctx context.Context) (crawlFetchFunc, func(), error)
+	// fetchText 获取JS文件内容
+	fetchText func(ctx context.Context, textURL string, timeout time.Duration) (string, error)
+}
+
+// NewCrawlerScanner 创建爬虫扫描器
+func NewCrawlerScanner() *CrawlerScanner {
+	return &CrawlerScanner{
+		BaseScanner: BaseScanner{name: "crawler"},
+		openBrowser: openChromeBrowser,
+		fetchText:   fetchCrawlText,
+	}
+}
+
+// crawlSiteResult 单个站点的爬取结果
+type crawlSiteResult struct {
+	endpoints []*CrawlEndpoint
+	vuls      []*Vulnerability
+}
+
+// Scan 爬取资产和目标中的HTTP站点
+func (s *CrawlerScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	result := &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+	}
+
+	opts, _ := GetTypedOptions[*CrawlerOptions](config)
+	if opts == nil {
+		opts = &CrawlerOptions{}
+	}
+	rules := opts.SecretRules
+	if rules == nil {
+		rules = DefaultSecretRules()
+	}
+	var validRules []*SecretRule
+	for _, rule := range rules {
+		if rule.Validate() == nil {
+			validRules = append(validRules, rule)
+		}
+	}
+	maxDepth := opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = 2
+	}
+	maxPages := opts.MaxPages
+	if maxPages <= 0 {
+		maxPages = 100
+	}
+	timeout := time.Duration(opts.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 2
+	}
+
+	logf := func(level, format string, args ...interface{}) {
+		if config.TaskLogger != nil {
+			config.TaskLogger(level, format, args...)
+		}
+	}
+
+	seeds := crawlSeeds(config)
+	if len(seeds) == 0 {
+		logf("INFO", "Crawler: no HTTP sites to crawl")
+		return result, nil
+	}
+
+	fetch, closeBrowser, err := s.openBrowser(ctx)
+	if err != nil {
+		return result, fmt.Errorf("start browser failed: %v", err)
+	}
+	defer closeBrowser()
+
+	logf("INFO", "Crawler: crawling %d sites, depth %d, max %d pages per site, %d secret rules", len(seeds), maxDepth, maxPages, len(validRules))
+
+	c := &siteCrawler{
+		scanner:  s,
+		fetch:    fetch,
+		maxDepth: maxDepth,
+		maxPages: maxPages,
+		timeout:  timeout,
+		rules:    validRules,
+		inScope:  opts.InScope,
+		logf:     logf,
+	}
+	sites, _ := ExecuteGeneric(ctx, concurrency, seeds, func(ctx context.Context, seed *url.URL) (*crawlSiteResult, error) {
+		return c.crawl(ctx, seed), nil
+	})
+
+	seenAsset := make(map[string]bool)
+	for _, site := range sites {
+		if site == nil {
+			continue
+		}
+		result.CrawlEndpoints = append(result.CrawlEndpoints, site.endpoints...)
+		result.Vulnerabilities = append(result.Vulnerabilities, site.vuls...)
+		for _, ep := range site.endpoints {
+			asset := ep.Asset()
+			if asset == nil {
+				continue
+			}
+			key := asset.Authority + asset.Path
+			if !seenAsset[key] {
+				seenAsset[key] = true
+				result.Assets = append(result.Assets, asset)
+			}
+		}
+	}
+	logf("INFO", "Crawler: %d endpoints, %d secrets", len(result.CrawlEndpoints), len(result.Vulnerabilities))
+	return result, ctx.Err()
+}
+
+// crawlSeeds 从HTTP资产和URL目标中提取待爬取的站点入口
+func crawlSeeds(config *ScanConfig) []*url.URL {
+	var seeds []*url.URL
+	seen := make(map[string]bool)
+	add := func(raw string) {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return
+		}
+		normalizeCrawlURL(u)
+		if !seen[u.String()] {
+			seen[u.String()] = true
+			seeds = append(seeds, u)
+		}
+	}
+	for _, asset := range config.Assets {
+		if raw := assetURL(asset); raw != "" {
+			add(raw)
+		}
+	}
+	parser := NewTargetParser()
+	for _, t := range parser.ParseMultiple(strings.Join(append([]string{config.Target}, config.Targets...), "\n")) {
+		if t.Type == TargetTypeURL {
+			add(t.Raw)
+		}
+	}
+	return seeds
+}
+
+// siteCrawler 站点爬取参数
+type siteCrawler struct {
+	scanner  *CrawlerScanner
+	fetch    crawlFetchFunc
+	maxDepth int
+	maxPages int
+	timeout  time.Duration
+	rules    []*SecretRule
+	inScope  func(host string) bool
+	logf     func(level, format string, args ...interface{})
+}
+
+// crawl 从入口按广度优先爬取同源页面，超出深度的链接只记录不渲染
+func (c *siteCrawler) crawl(ctx context.Context, seed *url.URL) *crawlSiteResult {
+	result := &crawlSiteResult{}
+	endpoints := make(map[string]*CrawlEndpoint)
+	secrets := make(map[string]bool)
+	visited := map[string]bool{crawlKey(http.MethodGet, seed): true}
+	scripts := make(map[string]bool)
+	var scriptQueue []*CrawlEndpoint
+
+	type queueItem struct {
+		url    string
+		depth  int
+		source string
+	}
+	queue := []queueItem{{url: seed.String()}}
+
+	allowed := func(u *url.URL) bool {
+		if sameOrigin(u, seed) {
+			return true
+		}
+		return c.inScope != nil && c.inScope(u.Hostname())
+	}
+	add := func(ep *CrawlEndpoint, u *url.URL) *CrawlEndpoint {
+		key := crawlKey(ep.Method, u)
+		if existing, ok := endpoints[key]; ok {
+			existing.Params = mergeParams(existing.Params, ep.Params)
+			if existing.StatusCode == 0 {
+				existing.StatusCode = ep.StatusCode
+				existing.ContentType = ep.ContentType
+			}
+			return existing
+		}
+		ep.Params = mergeParams(queryParams(u), ep.Params)
+		endpoints[key] = ep
+		result.endpoints = append(result.endpoints, ep)
+		return ep
+	}
+	addScript := func(u *url.URL, source string) {
+		ep := add(&CrawlEndpoint{URL: u.String(), Method: http.MethodGet, Type: CrawlEndpointJS, Source: source}, u)
+		if !scripts[ep.URL] {
+			scripts[ep.URL] = true
+			scriptQueue = append(scriptQueue, ep)
+		}
+	}
+	scanSecrets := func(location, content string) {
+		for _, v := range c.findSecrets(seed, location, content) {
+			key := v.MatcherName + "\x00" + v.ExtractedResults[0]
+			if !secrets[key] {
+				secrets[key] = true
+				result.vuls = append(result.vuls, v)
+			}
+		}
+	}
+
+	pages := 0
+	for len(queue) > 0 && pages < c.maxPages && ctx.Err() == nil {
+		item := queue[0]
+		queue = queue[1:]
+		pages++
+
+		page, err := c.fetch(ctx, item.url, c.timeout)
+		if err != nil {
+			c.logf("DEBUG", "Crawler: load %s failed: %v", item.url, err)
+			continue
+		}
+		pageURL := parseCrawlURL(seed, page.URL)
+		if pageURL == nil || !sameOrigin(pageURL, seed) {
+			// 跳转到站外的页面不再解析
+			continue
+		}
+		add(&CrawlEndpoint{
+			URL:         pageURL.String(),
+			Method:      http.MethodGet,
+			Type:        CrawlEndpointPage,
+			StatusCode:  page.StatusCode,
+			ContentType: page.ContentType,
+			Source:      item.source,
+		}, pageURL)
+		scanSecrets(pageURL.String(), page.HTML)
+
+		for _, link := range page.Links {
+			u := parseCrawlURL(pageURL, link)
+			if u == nil || !allowed(u) {
+				continue
+			}
+			if isScriptPath(u.Path) {
+				addScript(u, pageURL.String())
+				continue
+			}
+			if isStaticPath(u.Path) {
+				continue
+			}
+			add(&CrawlEndpoint{URL: u.String(), Method: http.MethodGet, Type: CrawlEndpointPage, Source: pageURL.String()}, u)
+			key := crawlKey(http.MethodGet, u)
+			if sameOrigin(u, seed) && item.depth < c.maxDepth && !visited[key] {
+				visited[key] = true
+				queue = append(queue, queueItem{url: u.String(), depth: item.depth + 1, source: pageURL.String()})
+			}
+		}
+		for _, form := range page.Forms {
+			u := parseCrawlURL(pageURL, form.Action)
+			if u == nil || !allowed(u) {
+				continue
+			}
+			method := strings.ToUpper(form.Method)
+			if method != http.MethodPost {
+				method = http.MethodGet
+			}
+			add(&CrawlEndpoint{URL: u.String(), Method: method, Type: CrawlEndpointForm, Params: form.Inputs, Source: pageURL.String()}, u)
+		}
+		for _, req := range page.Requests {
+			u := parseCrawlURL(pageURL, req.URL)
+			if u == nil || !allowed(u) {
+				continue
+			}
+			method := strings.ToUpper(req.Method)
+			if method == "" {
+				method = http.MethodGet
+			}
+			add(&CrawlEndpoint{URL: u.String(), Method: method, Type: CrawlEndpointAPI, Source: pageURL.String()}, u)
+		}
+		for _, src := range page.Scripts {
+			if u := parseCrawlURL(pageURL, src); u != nil && allowed(u) {
+				addScript(u, pageURL.String())
+			}
+		}
+	}
+
+	// JS文件最多读取与页面相同的数量
+	if len(scriptQueue) > c.maxPages {
+		scriptQueue = scriptQueue[:c.maxPages]
+	}
+	for _, js := range scriptQueue {
+		if ctx.Err() != nil {
+			break
+		}
+		text, err := c.scanner.fetchText(ctx, js.URL, c.timeout)
+		if err != nil {
+			c.logf("DEBUG", "Crawler: fetch %s failed: %v", js.URL, err)
+			continue
+		}
+		scanSecrets(js.URL, text)
+		jsURL, _ := url.Parse(js.URL)
+		for _, ref := range extractJSEndpoints(text) {
+			u := parseCrawlURL(jsURL, ref)
+			if u == nil || !allowed(u) || isStaticPath(u.Path) || isScriptPath(u.Path) {
+				continue
+			}
+			add(&CrawlEndpoint{URL: u.String(), Method: http.MethodGet, Type: CrawlEndpointAPI, Source: js.URL}, u)
+		}
+	}
+
+	c.logf("INFO", "Crawler: %s rendered %d pages, %d endpoints, %d secrets", seed.Host, pages, len(result.endpoints), len(result.vuls))
+	return result
+}
+
+// findSecrets 在内容中匹配敏感信息规则
+func (c *siteCrawler) findSecrets(seed *url.URL, location, content string) []*Vulnerability {
+	if content == "" {
+		return nil
+	}
+	host := seed.Hostname()
+	port := urlPort(seed)
+	var vuls []*Vulnerability
+	for _, rule := range c.rules {
+		for _, value := range rule.find(content) {
+			severity := strings.ToLower(rule.Severity)
+			if severity == "" {
+				severity = "medium"
+			}
+			vuls = append(vuls, &Vulnerability{
+				Authority:        net.JoinHostPort(host, strconv.Itoa(port)),
+				Host:             host,
+				Port:             port,
+				Url:              location,
+				PocFile:          "secret-" + rule.Name,
+				VulName:          fmt.Sprintf("Sensitive Information Leak (%s)", rule.Name),
+				Source:           "crawler",
+				Severity:         severity,
+				Result:           fmt.Sprintf("%s found in %s: %s", rule.Name, location, maskSecret(value)),
+				Tags:             []string{"secret", "exposure"},
+				MatcherName:      rule.Name,
+				ExtractedResults: []string{value},
+			})
+		}
+	}
+	return vuls
+}
+
+// Asset 转换为URL资产，JS文件和站点根路径不转换
+func (e *CrawlEndpoint) Asset() *Asset {
+	if e.Type == CrawlEndpointJS {
+		return nil
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil || u.Path == "" || u.Path == "/" {
+		return nil
+	}
+	port := urlPort(u)
+	return &Asset{
+		Authority:   net.JoinHostPort(u.Hostname(), strconv.Itoa(port)),
+		Host:        u.Hostname(),
+		Port:        port,
+		Category:    "url",
+		Service:     u.Scheme,
+		IsHTTP:      true,
+		Path:        u.Path,
+		HttpStatus:  statusText(e.StatusCode),
+		ContentType: e.ContentType,
+		Source:      "crawler",
+	}
+}
+
+func statusText(code int) string {
+	if code == 0 {
+		return ""
+	}
+	return strconv.Itoa(code)
+}
+
+// crawlJSPathRegex JS字符串中的绝对路径和完整URL
+var crawlJSPathRegex = regexp.MustCompile("[\"'`]((?:https?://[\\w.\\-]+(?::\\d+)?)?/[\\w\\-./{}:]*[\\w\\-}](?:\\?[^\"'`\\s<>]*)?)[\"'`]")
+
+// extractJSEndpoints 提取JS中引用的接口路径
+func extractJSEndpoints(text string) []string {
+	var refs []string
+	seen := make(map[string]bool)
+	for _, m := range crawlJSPathRegex.FindAllStringSubmatch(text, -1) {
+		ref := m[1]
+		// 排除协议相对地址和注释、正则字面量
+		if strings.HasPrefix(ref, "//") || strings.Contains(ref, "/*") || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// parseCrawlURL 相对于 base 解析链接，非HTTP链接返回 nil
+func parseCrawlURL(base *url.URL, ref string) *url.URL {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || base == nil {
+		return nil
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil
+	}
+	normalizeCrawlURL(u)
+	return u
+}
+
+// normalizeCrawlURL 去掉片段和默认端口，空路径补为根路径
+func normalizeCrawlURL(u *url.URL) {
+	u.Fragment = ""
+	if p := u.Port(); (u.Scheme == "http" && p == "80") || (u.Scheme == "https" && p == "443") {
+		u.Host = strings.TrimSuffix(u.Host, ":"+p)
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+}
+
+// crawlKey 端点去重键：方法、源、路径和排序后的参数名，参数值不同视为同一端点
+func crawlKey(method string, u *url.URL) string {
+	return method + " " + strings.ToLower(u.Scheme+"://"+u.Host) + u.Path + "?" + strings.Join(queryParams(u), "&")
+}
+
+// queryParams 排序后的查询参数名
+func queryParams(u *url.URL) []string {
+	var params []string
+	for name := range u.Query() {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	return params
+}
+
+// mergeParams 合并参数名，结果去重排序
+func mergeParams(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	set := make(map[string]bool, len(a)+len(b))
+	var merged []string
+	for _, p := range append(append([]string(nil), a...), b...) {
+		if p != "" && !set[p] {
+			set[p] = true
+			merged = append(merged, p)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && strings.EqualFold(a.Hostname(), b.Hostname()) && urlPort(a) == urlPort(b)
+}
+
+func urlPort(u *url.URL) int {
+	if p, err := strconv.Atoi(u.Port()); err == nil {
+		return p
+	}
+	if u.Scheme == "https" {
+		return 443
+	}
+	return 80
+}
+
+func isScriptPath(p string) bool {
+	ext := strings.ToLower(path.Ext(p))
+	return ext == ".js" || ext == ".mjs"
+}
+
+// isStaticPath 图片、样式、字体、媒体和文档等静态资源
+func isStaticPath(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".bmp", ".svg", ".ico", ".webp",
+		".css", ".map", ".woff", ".woff2", ".ttf", ".eot", ".otf",
+		".mp3", ".mp4", ".avi", ".webm", ".flv", ".wav",
+		".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".zip", ".rar", ".gz", ".7z":
+		return true
+	}
+	return false
+}
+
+// maskSecret 日志和结果描述中只显示泄露值的首尾
+func maskSecret(v string) string {
+	if len(v) <= 12 {
+		return v[:len(v)/3] + strings.Repeat("*", len(v)-len(v)/3)
+	}
+	return v[:6] + strings.Repeat("*", len(v)-10) + v[len(v)-4:]
+}
+
+// crawlExtractJS 提取渲染后页面的链接、脚本和表单
+const crawlExtractJS = `(() => {
+	const abs = u => { try { return new URL(u, document.baseURI).href } catch (e) { return "" } };
+	const attr = (sel, name) => Array.from(document.querySelectorAll(sel)).map(e => abs(e.getAttribute(name)));
+	return {
+		html: document.documentElement ? document.documentElement.outerHTML : "",
+		links: attr("a[href],area[href]", "href").concat(attr("iframe[src],frame[src]", "src")),
+		scripts: Array.from(document.scripts).filter(s => s.src).map(s => s.src),
+		forms: Array.from(document.forms).map(f => ({
+			action: abs(f.getAttribute("action") || document.URL),
+			method: f.getAttribute("method") || "GET",
+			inputs: Array.from(f.elements).map(e => e.name).filter(n => n)
+		}))
+	};
+})()`
+
+// openChromeBrowser 启动无头浏览器，每个页面在独立标签页中渲染
+func openChromeBrowser(ctx context.Context) (crawlFetchFunc, func(), error) {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("ignore-certificate-errors", true),
+		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
+	)
+	if chromePath := os.Getenv("CHROME_BIN"); chromePath != "" {
+		opts = append(opts, chromedp.ExecPath(chromePath))
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx,
+		chromedp.WithErrorf(func(format string, args ...interface{}) {
+			// 忽略 CookiePartitionKey 反序列化错误（Chrome 版本兼容性问题）
+			if msg := fmt.Sprintf(format, args...); !strings.Contains(msg, "CookiePartitionKey") {
+				logx.Errorf(format, args...)
+			}
+		}),
+	)
+	closeBrowser := func() {
+		browserCancel()
+		allocCancel()
+	}
+	if err := chromedp.Run(browserCtx); err != nil {
+		closeBrowser()
+		return nil, nil, err
+	}
+
+	fetch := func(ctx context.Context, pageURL string, timeout time.Duration) (*CrawlPage, error) {
+		tabCtx, tabCancel := chromedp.NewContext(browserCtx)
+		defer tabCancel()
+		tabCtx, cancel := context.WithTimeout(tabCtx, timeout)
+		defer cancel()
+		stop := context.AfterFunc(ctx, cancel)
+		defer stop()
+
+		page := &CrawlPage{URL: pageURL}
+		var mu sync.Mutex
+		var documentId network.RequestID
+		chromedp.ListenTarget(tabCtx, func(ev interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch e := ev.(type) {
+			case *network.EventRequestWillBeSent:
+				switch e.Type {
+				case network.ResourceTypeDocument:
+					if documentId == "" {
+						documentId = e.RequestID
+					}
+				case network.ResourceTypeXHR, network.ResourceTypeFetch:
+					page.Requests = append(page.Requests, CrawlRequest{URL: e.Request.URL, Method: e.Request.Method})
+				}
+			case *network.EventResponseReceived:
+				if e.RequestID == documentId && e.Response != nil {
+					page.URL = e.Response.URL
+					page.StatusCode = int(e.Response.Status)
+					page.ContentType = e.Response.MimeType
+				}
+			}
+		})
+
+		var data struct {
+			HTML    string      `json:"html"`
+			Links   []string    `json:"links"`
+			Scripts []string    `json:"scripts"`
+			Forms   []CrawlForm `json:"forms"`
+		}
+		err := chromedp.Run(tabCtx,
+			network.Enable(),
+			chromedp.Navigate(pageURL),
+			chromedp.Sleep(crawlSettleTime),
+			chromedp.Evaluate(crawlExtractJS, &data),
+		)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		page.HTML = data.HTML
+		page.Links = data.Links
+		page.Scripts = data.Scripts
+		page.Forms = data.Forms
+		// 标签页关闭前监听器仍可能追加请求，返回副本
+		page.Requests = append([]CrawlRequest(nil), page.Requests...)
+		return page, nil
+	}
+	return fetch, closeBrowser, nil
+}
+
+// fetchCrawlText 获取文本资源，超过上限的部分丢弃
+func fetchCrawlText(ctx context.Context, textURL string, timeout time.Duration) (string, error) {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, textURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, crawlMaxTextSize))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
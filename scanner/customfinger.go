@@ -9,8 +9,10 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 
 	"cscan/model"
+	wappalyzer "github.com/projectdiscovery/wappalyzergo"
 	"golang.org/x/text/encoding/simplifiedchinese"
 	"golang.org/x/text/transform"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -22,6 +24,9 @@ import (
 type CustomFingerprintEngine struct {
 	fingerprints       []*model.Fingerprint // 被动指纹
 	activeFingerprints []*model.Fingerprint // 主动指纹
+
+	cpeMu    sync.Mutex
+	cpeIndex map[string]string // 小写指纹名称 -> CPE，首次查询时构建
 }
 
 // NewCustomFingerprintEngine 创建自定义指纹引擎
@@ -42,6 +47,9 @@ func NewCustomFingerprintEngineWithActive(passiveFingerprints, activeFingerprint
 // SetActiveFingerprints 设置主动指纹
 func (e *CustomFingerprintEngine) SetActiveFingerprints(fingerprints []*model.Fingerprint) {
 	e.activeFingerprints = fingerprints
+	e.cpeMu.Lock()
+	e.cpeIndex = nil
+	e.cpeMu.Unlock()
 }
 
 // FingerprintData 用于指纹匹配的数据
@@ -56,12 +64,15 @@ type FingerprintData struct {
 	URL          string      // 请求URL
 	FaviconHash  string      // favicon的MMH3 hash（Shodan风格）
 	Cookies      string      // Set-Cookie头内容
+	Banner       string      // 非HTTP服务的Banner
 }
 
 // MatchedFingerprint 匹配到的指纹结果
 type MatchedFingerprint struct {
-	Name string // 指纹名称
-	Id   string // 指纹ID（MongoDB ObjectID）
+	Name    string // 指纹名称
+	Id      string // 指纹ID（MongoDB ObjectID）
+	Version string // 提取到的版本，未提取到为空
+	CPE     string // 指纹规则配置的CPE
 }
 
 // GetFingerprintCount 返回已加载的指纹数量
@@ -159,7 +170,7 @@ func (e *CustomFingerprintEngine) MatchActiveFingerprint(fp *model.Fingerprint,
 	}
 
 	// 使用Wappalyzer格式规则
-	if e.matchWappalyzerRules(fp, data) {
+	if ok, _ := e.matchWappalyzerRules(fp, data); ok {
 		logx.Debugf("Active fingerprint '%s' matched by Wappalyzer rules", fp.Name)
 		return true
 	}
@@ -206,46 +217,35 @@ func (e *CustomFingerprintEngine) MatchWithId(data *FingerprintData) []MatchedFi
 	}
 
 	for _, fp := range e.fingerprints {
-		if !fp.Enabled {
+		if !fp.Enabled || seen[fp.Name] {
 			continue
 		}
 
-		// 优先使用Rule字段（ARL格式规则语法）
+		// 优先使用Rule字段（ARL格式规则语法），其次ARL webapp.json格式规则（html/title/headers数组），最后Wappalyzer格式规则
+		var ok bool
+		var version string
 		if fp.Rule != "" {
-			if e.matchRule(fp.Rule, data) {
-				if !seen[fp.Name] {
-					matched = append(matched, MatchedFingerprint{
-						Name: fp.Name,
-						Id:   fp.Id.Hex(),
-					})
-					seen[fp.Name] = true
-				}
-			}
-			continue
+			ok = e.matchRule(fp.Rule, data)
+		} else if e.matchARLWebappRules(fp, data) {
+			ok = true
+		} else {
+			ok, version = e.matchWappalyzerRules(fp, data)
 		}
-
-		// 使用ARL webapp.json格式规则（html/title/headers数组）
-		if e.matchARLWebappRules(fp, data) {
-			if !seen[fp.Name] {
-				matched = append(matched, MatchedFingerprint{
-					Name: fp.Name,
-					Id:   fp.Id.Hex(),
-				})
-				seen[fp.Name] = true
-			}
+		if !ok {
 			continue
 		}
 
-		// 使用Wappalyzer格式规则
-		if e.matchWappalyzerRules(fp, data) {
-			if !seen[fp.Name] {
-				matched = append(matched, MatchedFingerprint{
-					Name: fp.Name,
-					Id:   fp.Id.Hex(),
-				})
-				seen[fp.Name] = true
-			}
+		// Wappalyzer规则未带版本时使用版本提取规则
+		if version == "" {
+			version = ExtractVersion(fp, data)
 		}
+		matched = append(matched, MatchedFingerprint{
+			Name:    fp.Name,
+			Id:      fp.Id.Hex(),
+			Version: version,
+			CPE:     fp.CPE,
+		})
+		seen[fp.Name] = true
 	}
 
 	return matched
@@ -603,12 +603,22 @@ func matchRegex(s, pattern string) bool {
 	return re.MatchString(s)
 }
 
-// matchWappalyzerRules 匹配Wappalyzer格式规则
+// matchWappalyzerRules 匹配Wappalyzer格式规则，同时返回 \;version: 标记提取到的版本
 // Wappalyzer的html、scripts、css等字段是正则表达式
-func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, data *FingerprintData) bool {
+func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, data *FingerprintData) (bool, string) {
 	hasRule := false
 	allMatch := true
 
+	// 匹配并记录第一个提取到的版本
+	var version string
+	match := func(text, pattern string) bool {
+		ok, v := matchPatternVersion(text, pattern)
+		if ok && version == "" {
+			version = v
+		}
+		return ok
+	}
+
 	// Headers匹配 - key需要大小写不敏感匹配
 	if len(fp.Headers) > 0 {
 		hasRule = true
@@ -624,7 +634,7 @@ func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, da
 						break
 					}
 					// pattern是正则表达式
-					if matchRegexOrContains(headerValue, pattern) {
+					if match(headerValue, pattern) {
 						headerMatch = true
 						break
 					}
@@ -644,7 +654,7 @@ func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, da
 		hasRule = true
 		htmlMatch := false
 		for _, pattern := range fp.HTML {
-			if matchRegexOrContains(data.Body, pattern) {
+			if match(data.Body, pattern) {
 				htmlMatch = true
 				break
 			}
@@ -659,7 +669,10 @@ func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, da
 		hasRule = true
 		metaMatch := false
 		for name, pattern := range fp.Meta {
-			if matchMetaTag(data.Body, name, pattern) {
+			if ok, v := matchMetaTag(data.Body, name, pattern); ok {
+				if version == "" {
+					version = v
+				}
 				metaMatch = true
 				break
 			}
@@ -678,7 +691,7 @@ func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, da
 		scriptSrcs := scriptSrcRe.FindAllStringSubmatch(data.Body, -1)
 		for _, pattern := range fp.Scripts {
 			for _, src := range scriptSrcs {
-				if len(src) > 1 && matchRegexOrContains(src[1], pattern) {
+				if len(src) > 1 && match(src[1], pattern) {
 					scriptMatch = true
 					break
 				}
@@ -700,7 +713,7 @@ func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, da
 		scriptSrcs := scriptSrcRe.FindAllStringSubmatch(data.Body, -1)
 		for _, pattern := range fp.ScriptSrc {
 			for _, src := range scriptSrcs {
-				if len(src) > 1 && matchRegexOrContains(src[1], pattern) {
+				if len(src) > 1 && match(src[1], pattern) {
 					scriptSrcMatch = true
 					break
 				}
@@ -719,7 +732,7 @@ func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, da
 		hasRule = true
 		cssMatch := false
 		for _, pattern := range fp.CSS {
-			if matchRegexOrContains(data.Body, pattern) {
+			if match(data.Body, pattern) {
 				cssMatch = true
 				break
 			}
@@ -740,7 +753,7 @@ func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, da
 		}
 		for name, pattern := range fp.Cookies {
 			if containsIgnoreCase(cookieStr, name) {
-				if pattern == "" || matchRegexOrContains(cookieStr, pattern) {
+				if pattern == "" || match(cookieStr, pattern) {
 					cookieMatch = true
 					break
 				}
@@ -756,7 +769,7 @@ func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, da
 		hasRule = true
 		urlMatch := false
 		for _, pattern := range fp.URL {
-			if matchRegexOrContains(data.URL, pattern) {
+			if match(data.URL, pattern) {
 				urlMatch = true
 				break
 			}
@@ -766,7 +779,10 @@ func (e *CustomFingerprintEngine) matchWappalyzerRules(fp *model.Fingerprint, da
 		}
 	}
 
-	return hasRule && allMatch
+	if !hasRule || !allMatch {
+		return false, ""
+	}
+	return true, version
 }
 
 // matchRegexOrContains 尝试正则匹配，如果正则无效则回退到字符串包含匹配
@@ -783,22 +799,166 @@ func matchRegexOrContains(text, pattern string) bool {
 	return re.MatchString(text)
 }
 
-// matchMetaTag 匹配Meta标签
-func matchMetaTag(body, name, pattern string) bool {
+// parsedPatterns 缓存解析后的带 \; 标记的Wappalyzer模式，解析失败时缓存 nil
+var parsedPatterns sync.Map
+
+// matchPatternVersion 匹配Wappalyzer模式并提取版本
+// 模式可带 \;version:\1 和 \;confidence:50 标记，由 wappalyzergo 负责解析；
+// 不带标记的模式与 matchRegexOrContains 行为一致
+func matchPatternVersion(text, pattern string) (bool, string) {
+	if !strings.Contains(pattern, "\\;") {
+		return matchRegexOrContains(text, pattern), ""
+	}
+	var parsed *wappalyzer.ParsedPattern
+	if cached, ok := parsedPatterns.Load(pattern); ok {
+		parsed, _ = cached.(*wappalyzer.ParsedPattern)
+	} else {
+		parsed, _ = wappalyzer.ParsePattern(pattern)
+		parsedPatterns.Store(pattern, parsed)
+	}
+	if parsed == nil {
+		// 正则无效，去掉标记后回退到字符串包含匹配
+		return matchRegexOrContains(text, strings.SplitN(pattern, "\\;", 2)[0]), ""
+	}
+	return parsed.Evaluate(text)
+}
+
+// ExtractVersion 按指纹的版本提取规则从响应或Banner中提取版本，未提取到返回空
+func ExtractVersion(fp *model.Fingerprint, data *FingerprintData) string {
+	for _, ex := range fp.Extractors {
+		if ex.Regex == "" {
+			continue
+		}
+		var text string
+		switch strings.ToLower(ex.Part) {
+		case "header":
+			text = data.HeaderString
+			if text == "" && data.Headers != nil {
+				text = formatHeadersToString(data.Headers)
+			}
+		case "title":
+			text = data.Title
+		case "server":
+			text = data.Server
+		case "banner":
+			text = data.Banner
+		default:
+			text = data.Body
+		}
+		if text == "" {
+			continue
+		}
+		if v := extractWithRegex(text, ex.Regex, ex.Version); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// extractorRegexps 缓存版本提取正则，编译失败时缓存 nil
+var extractorRegexps sync.Map
+
+// extractWithRegex 使用正则提取版本，模板中的 \1..\9 替换为对应捕获组，模板为空时取第一个捕获组
+func extractWithRegex(text, pattern, template string) string {
+	var re *regexp.Regexp
+	if cached, ok := extractorRegexps.Load(pattern); ok {
+		re, _ = cached.(*regexp.Regexp)
+	} else {
+		re, _ = regexp.Compile("(?i)" + pattern)
+		extractorRegexps.Store(pattern, re)
+	}
+	if re == nil {
+		return ""
+	}
+	m := re.FindStringSubmatch(text)
+	if len(m) < 2 {
+		return ""
+	}
+	if template == "" {
+		return strings.TrimSpace(m[1])
+	}
+	result := template
+	for i := len(m) - 1; i >= 1; i-- {
+		result = strings.ReplaceAll(result, fmt.Sprintf("\\%d", i), m[i])
+	}
+	return strings.TrimSpace(result)
+}
+
+// MatchBanner 使用带 banner 版本提取规则的指纹匹配非HTTP服务的Banner
+// 提取规则同时作为识别条件，只有提取到版本才视为命中
+func (e *CustomFingerprintEngine) MatchBanner(banner string) []MatchedFingerprint {
+	var matched []MatchedFingerprint
+	if e == nil || banner == "" {
+		return matched
+	}
+	data := &FingerprintData{Banner: banner}
+	seen := make(map[string]bool)
+	for _, fp := range e.fingerprints {
+		if !fp.Enabled || seen[fp.Name] || !hasBannerExtractor(fp) {
+			continue
+		}
+		if version := ExtractVersion(fp, data); version != "" {
+			matched = append(matched, MatchedFingerprint{
+				Name:    fp.Name,
+				Id:      fp.Id.Hex(),
+				Version: version,
+				CPE:     fp.CPE,
+			})
+			seen[fp.Name] = true
+		}
+	}
+	return matched
+}
+
+func hasBannerExtractor(fp *model.Fingerprint) bool {
+	for _, ex := range fp.Extractors {
+		if strings.EqualFold(ex.Part, "banner") {
+			return true
+		}
+	}
+	return false
+}
+
+// CPEForName 返回指纹名称对应的CPE（忽略大小写），未配置返回空
+func (e *CustomFingerprintEngine) CPEForName(name string) string {
+	if e == nil {
+		return ""
+	}
+	e.cpeMu.Lock()
+	defer e.cpeMu.Unlock()
+	if e.cpeIndex == nil {
+		e.cpeIndex = make(map[string]string)
+		for _, list := range [][]*model.Fingerprint{e.fingerprints, e.activeFingerprints} {
+			for _, fp := range list {
+				key := strings.ToLower(fp.Name)
+				if fp.CPE != "" && e.cpeIndex[key] == "" {
+					e.cpeIndex[key] = fp.CPE
+				}
+			}
+		}
+	}
+	return e.cpeIndex[strings.ToLower(name)]
+}
+
+// matchMetaTag 匹配Meta标签，同时返回提取到的版本
+func matchMetaTag(body, name, pattern string) (bool, string) {
 	// 简单的meta标签匹配
 	metaPattern := `(?i)<meta[^>]*name\s*=\s*["']?` + regexp.QuoteMeta(name) + `["']?[^>]*content\s*=\s*["']([^"']+)["']`
 	re, err := regexp.Compile(metaPattern)
 	if err != nil {
-		return false
+		return false, ""
 	}
 	matches := re.FindStringSubmatch(body)
 	if len(matches) < 2 {
-		return false
+		return false, ""
 	}
 	if pattern == "" {
-		return true
+		return true, ""
+	}
+	if !strings.Contains(pattern, "\\;") {
+		return matchRegex(matches[1], pattern), ""
 	}
-	return matchRegex(matches[1], pattern)
+	return matchPatternVersion(matches[1], pattern)
 }
 
 // ParseARLFingerYAML 解析ARL finger.yml格式
@@ -0,0 +1,439 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// 采集的DNS记录类型
+var dnsRecordTypes = []uint16{
+	dns.TypeA, dns.TypeAAAA, dns.TypeCNAME, dns.TypeMX,
+	dns.TypeNS, dns.TypeTXT, dns.TypeSOA, dns.TypeCAA,
+}
+
+// defaultDNSResolvers 无法读取系统DNS配置时使用的解析服务器
+var defaultDNSResolvers = []string{"223.5.5.5", "8.8.8.8"}
+
+// DNSRecord 域名的一条DNS记录
+type DNSRecord struct {
+	Domain string `json:"domain"` // 资产域名
+	Name   string `json:"name"`   // 记录所有者名称，DMARC 记录为 _dmarc.<domain>
+	Type   string `json:"type"`   // A/AAAA/CNAME/MX/NS/TXT/SOA/CAA
+	Value  string `json:"value"`
+	TTL    uint32 `json:"ttl"`
+}
+
+// DNSRecordOptions DNS记录采集选项
+type DNSRecordOptions struct {
+	Resolvers   []string `json:"resolvers"`   // DNS服务器 host[:port]，为空时使用系统配置
+	Concurrency int      `json:"concurrency"` // 并发域名数
+	Timeout     int      `json:"timeout"`     // 单次查询超时(秒)
+	AXFR        bool     `json:"axfr"`        // 对区域顶点的NS服务器尝试区域传送
+	// InScope 判断NS服务器及其解析地址是否在扫描范围内，范围外的服务器不发起区域传送，为空时不限制
+	InScope func(host string) bool `json:"-"`
+}
+
+// Validate 验证 DNSRecordOptions 配置是否有效
+// 实现 ScannerOptions 接口
+func (o *DNSRecordOptions) Validate() error {
+	if o.Concurrency < 0 {
+		return fmt.Errorf("concurrency must be non-negative, got %d", o.Concurrency)
+	}
+	if o.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative, got %d", o.Timeout)
+	}
+	return nil
+}
+
+// DNSRecordScanner DNS记录采集扫描器
+// 查询域名的 A/AAAA/CNAME/MX/NS/TXT/SOA/CAA 记录，区域顶点额外检查 SPF、DMARC 和区域传送，
+// 记录通过 ScanResult.DNSRecords 输出，配置问题以漏洞形式输出
+type DNSRecordScanner struct {
+	BaseScanner
+	// exchange 向解析服务器发送查询
+	exchange func(ctx context.Context, server string, msg *dns.Msg, timeout time.Duration) (*dns.Msg, error)
+	// transfer 向权威服务器发起 AXFR，返回传送的记录
+	transfer func(ctx context.Context, server, zone string, timeout time.Duration) ([]dns.RR, error)
+}
+
+// NewDNSRecordScanner 创建DNS记录采集扫描器
+func NewDNSRecordScanner() *DNSRecordScanner {
+	return &DNSRecordScanner{
+		BaseScanner: BaseScanner{name: "dnsrecord"},
+		exchange:    exchangeDNS,
+		transfer:    transferZone,
+	}
+}
+
+// dnsLookup 单个域名的查询结果
+type dnsLookup struct {
+	records []*DNSRecord
+	vuls    []*Vulnerability
+	skipped []string // 因不在扫描范围内未尝试区域传送的NS服务器
+}
+
+// Scan 采集资产和目标中域名的DNS记录
+func (s *DNSRecordScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	result := &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+	}
+
+	opts, _ := GetTypedOptions[*DNSRecordOptions](config)
+	if opts == nil {
+		opts = &DNSRecordOptions{}
+	}
+	timeout := time.Duration(opts.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 20
+	}
+	resolvers := normalizeResolvers(opts.Resolvers)
+	if len(resolvers) == 0 {
+		resolvers = systemResolvers()
+	}
+
+	logf := func(level, format string, args ...interface{}) {
+		if config.TaskLogger != nil {
+			config.TaskLogger(level, format, args...)
+		}
+	}
+
+	domains, _ := collectDomains(config)
+	if len(domains) == 0 {
+		logf("INFO", "DNSRecord: no domains to query")
+		return result, nil
+	}
+	logf("INFO", "DNSRecord: querying %d domains via %s", len(domains), strings.Join(resolvers, ","))
+
+	lookups, _ := ExecuteGeneric(ctx, concurrency, domains, func(ctx context.Context, domain string) (*dnsLookup, error) {
+		return s.lookupDomain(ctx, domain, resolvers, timeout, opts.AXFR, opts.InScope), nil
+	})
+
+	for _, l := range lookups {
+		if l == nil {
+			continue
+		}
+		result.DNSRecords = append(result.DNSRecords, l.records...)
+		for _, ns := range l.skipped {
+			logf("INFO", "DNSRecord: nameserver %s not in scope, zone transfer skipped", ns)
+		}
+		for _, vul := range l.vuls {
+			logf("WARN", "DNSRecord: %s %s", vul.Host, vul.Result)
+		}
+		result.Vulnerabilities = append(result.Vulnerabilities, l.vuls...)
+	}
+	logf("INFO", "DNSRecord: %d records, %d findings", len(result.DNSRecords), len(result.Vulnerabilities))
+	return result, ctx.Err()
+}
+
+// lookupDomain 查询单个域名的全部记录类型，区域顶点检查邮件安全配置和区域传送
+func (s *DNSRecordScanner) lookupDomain(ctx context.Context, domain string, resolvers []string, timeout time.Duration, axfr bool, inScope func(string) bool) *dnsLookup {
+	l := &dnsLookup{}
+	apex := false
+	var nameservers []string
+	var txts []string
+
+	for _, qtype := range dnsRecordTypes {
+		if ctx.Err() != nil {
+			return l
+		}
+		rrs, nx := s.query(ctx, resolvers, domain, qtype, timeout)
+		if nx {
+			// 域名不存在时不再查询其他类型
+			return l
+		}
+		for _, rr := range rrs {
+			rec := newDNSRecord(domain, rr)
+			if rec == nil {
+				continue
+			}
+			l.records = append(l.records, rec)
+			switch rec.Type {
+			case "SOA":
+				apex = true
+			case "NS":
+				nameservers = append(nameservers, rec.Value)
+			case "TXT":
+				txts = append(txts, rec.Value)
+			}
+		}
+	}
+
+	if !apex {
+		return l
+	}
+
+	// SPF
+	if spf := findSPF(txts); spf != "" {
+		if reason := permissiveSPF(spf); reason != "" {
+			l.vuls = append(l.vuls, dnsFinding(domain, "dns-spf-permissive", "Permissive SPF Record", "medium",
+				fmt.Sprintf("SPF record %s: %s", reason, spf), spf))
+		}
+	}
+
+	// DMARC
+	dmarcName := "_dmarc." + domain
+	rrs, _ := s.query(ctx, resolvers, dmarcName, dns.TypeTXT, timeout)
+	dmarc := ""
+	for _, rr := range rrs {
+		rec := newDNSRecord(domain, rr)
+		if rec == nil || rec.Type != "TXT" {
+			continue
+		}
+		l.records = append(l.records, rec)
+		if strings.HasPrefix(strings.ToLower(rec.Value), "v=dmarc1") {
+			dmarc = rec.Value
+		}
+	}
+	if dmarc == "" {
+		l.vuls = append(l.vuls, dnsFinding(domain, "dns-dmarc-missing", "Missing DMARC Record", "low",
+			fmt.Sprintf("no DMARC policy published at %s", dmarcName), dmarcName))
+	}
+
+	// 区域传送
+	if axfr {
+		var allowed []string
+		var count int
+		for _, ns := range nameservers {
+			// NS服务器可能由第三方托管，范围外的服务器不主动连接
+			if inScope != nil && !inScope(ns) {
+				l.skipped = append(l.skipped, ns)
+				continue
+			}
+			for _, server := range s.nameserverAddrs(ctx, resolvers, ns, timeout) {
+				// 范围内的NS域名也可能解析到范围外的地址
+				if ip, _, _ := net.SplitHostPort(server); inScope != nil && !inScope(ip) {
+					l.skipped = append(l.skipped, fmt.Sprintf("%s (%s)", ns, ip))
+					continue
+				}
+				rrs, err := s.transfer(ctx, server, domain, timeout)
+				if err != nil || len(rrs) == 0 {
+					continue
+				}
+				allowed = append(allowed, ns)
+				if len(rrs) > count {
+					count = len(rrs)
+				}
+				break
+			}
+		}
+		if len(allowed) > 0 {
+			v := dnsFinding(domain, "dns-zone-transfer", "DNS Zone Transfer Allowed", "high",
+				fmt.Sprintf("AXFR of %s allowed by %s, %d records transferred", domain, strings.Join(allowed, ", "), count), allowed...)
+			l.vuls = append(l.vuls, v)
+		}
+	}
+	return l
+}
+
+// query 依次向解析服务器查询，返回与查询类型一致的应答记录，nx 表示域名不存在
+func (s *DNSRecordScanner) query(ctx context.Context, resolvers []string, name string, qtype uint16, timeout time.Duration) (rrs []dns.RR, nx bool) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+
+	for _, server := range resolvers {
+		resp, err := s.exchange(ctx, server, msg, timeout)
+		if err != nil || resp == nil {
+			continue
+		}
+		if resp.Rcode == dns.RcodeNameError {
+			return nil, true
+		}
+		if resp.Rcode != dns.RcodeSuccess {
+			continue
+		}
+		for _, rr := range resp.Answer {
+			// A/AAAA 保留 CNAME 链最终解析到的地址，其他类型只保留属于该域名的记录
+			if rr.Header().Rrtype != qtype {
+				continue
+			}
+			if qtype == dns.TypeA || qtype == dns.TypeAAAA || strings.EqualFold(rr.Header().Name, dns.Fqdn(name)) {
+				rrs = append(rrs, rr)
+			}
+		}
+		return rrs, false
+	}
+	return nil, false
+}
+
+// nameserverAddrs 解析NS主机地址，用于直连发起区域传送
+func (s *DNSRecordScanner) nameserverAddrs(ctx context.Context, resolvers []string, host string, timeout time.Duration) []string {
+	if net.ParseIP(host) != nil {
+		return []string{net.JoinHostPort(host, "53")}
+	}
+	var addrs []string
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		rrs, _ := s.query(ctx, resolvers, host, qtype, timeout)
+		for _, rr := range rrs {
+			switch v := rr.(type) {
+			case *dns.A:
+				addrs = append(addrs, net.JoinHostPort(v.A.String(), "53"))
+			case *dns.AAAA:
+				addrs = append(addrs, net.JoinHostPort(v.AAAA.String(), "53"))
+			}
+		}
+	}
+	return addrs
+}
+
+// newDNSRecord 转换应答记录，不支持的类型返回 nil
+func newDNSRecord(domain string, rr dns.RR) *DNSRecord {
+	rec := &DNSRecord{
+		Domain: domain,
+		Name:   strings.ToLower(strings.TrimSuffix(rr.Header().Name, ".")),
+		TTL:    rr.Header().Ttl,
+	}
+	switch v := rr.(type) {
+	case *dns.A:
+		rec.Type, rec.Value = "A", v.A.String()
+	case *dns.AAAA:
+		rec.Type, rec.Value = "AAAA", v.AAAA.String()
+	case *dns.CNAME:
+		rec.Type, rec.Value = "CNAME", trimDot(v.Target)
+	case *dns.MX:
+		rec.Type, rec.Value = "MX", fmt.Sprintf("%d %s", v.Preference, trimDot(v.Mx))
+	case *dns.NS:
+		rec.Type, rec.Value = "NS", trimDot(v.Ns)
+	case *dns.TXT:
+		rec.Type, rec.Value = "TXT", strings.Join(v.Txt, "")
+	case *dns.SOA:
+		rec.Type, rec.Value = "SOA", fmt.Sprintf("%s %s %d %d %d %d %d",
+			trimDot(v.Ns), trimDot(v.Mbox), v.Serial, v.Refresh, v.Retry, v.Expire, v.Minttl)
+	case *dns.CAA:
+		rec.Type, rec.Value = "CAA", fmt.Sprintf("%d %s %q", v.Flag, v.Tag, v.Value)
+	default:
+		return nil
+	}
+	return rec
+}
+
+func trimDot(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// findSPF 返回 TXT 记录中的 SPF 策略
+func findSPF(txts []string) string {
+	for _, txt := range txts {
+		lower := strings.ToLower(strings.TrimSpace(txt))
+		if lower == "v=spf1" || strings.HasPrefix(lower, "v=spf1 ") {
+			return txt
+		}
+	}
+	return ""
+}
+
+// permissiveSPF 判断 SPF 策略是否允许任意主机发信，返回原因，安全时返回空
+// +all 和 ?all 不拒绝伪造邮件；没有 all 也没有 redirect 时默认结果为 neutral
+func permissiveSPF(spf string) string {
+	hasAll := false
+	for _, term := range strings.Fields(strings.ToLower(spf))[1:] {
+		switch term {
+		case "all", "+all":
+			return "allows all senders (+all)"
+		case "?all":
+			return "neutral for all senders (?all)"
+		case "-all", "~all":
+			hasAll = true
+		}
+		if strings.HasPrefix(term, "redirect=") {
+			hasAll = true
+		}
+	}
+	if !hasAll {
+		return "has no all mechanism"
+	}
+	return ""
+}
+
+// dnsFinding 生成DNS配置问题
+func dnsFinding(domain, poc, name, severity, detail string, extracted ...string) *Vulnerability {
+	return &Vulnerability{
+		Authority:        domain,
+		Host:             domain,
+		Url:              domain,
+		PocFile:          poc,
+		Source:           "dnsrecord",
+		Severity:         severity,
+		VulName:          name,
+		Result:           detail,
+		Tags:             []string{"dns", "misconfiguration"},
+		MatcherName:      poc,
+		ExtractedResults: extracted,
+	}
+}
+
+// normalizeResolvers 为解析服务器补全默认端口
+func normalizeResolvers(servers []string) []string {
+	var result []string
+	for _, server := range servers {
+		server = strings.TrimSpace(server)
+		if server == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+		}
+		result = append(result, server)
+	}
+	return result
+}
+
+// systemResolvers 读取系统DNS配置，失败时使用默认解析服务器
+func systemResolvers() []string {
+	if conf, err := dns.ClientConfigFromFile("/etc/resolv.conf"); err == nil && len(conf.Servers) > 0 {
+		servers := make([]string, 0, len(conf.Servers))
+		for _, s := range conf.Servers {
+			servers = append(servers, net.JoinHostPort(s, conf.Port))
+		}
+		return servers
+	}
+	return normalizeResolvers(defaultDNSResolvers)
+}
+
+// exchangeDNS 发送查询，应答被截断时改用TCP重试
+func exchangeDNS(ctx context.Context, server string, msg *dns.Msg, timeout time.Duration) (*dns.Msg, error) {
+	client := &dns.Client{Timeout: timeout}
+	resp, _, err := client.ExchangeContext(ctx, msg, server)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, server)
+	}
+	return resp, err
+}
+
+// transferZone 发起 AXFR，服务器拒绝时返回错误
+func transferZone(ctx context.Context, server, zone string, timeout time.Duration) ([]dns.RR, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	msg := new(dns.Msg)
+	msg.SetAxfr(dns.Fqdn(zone))
+	t := &dns.Transfer{DialTimeout: timeout, ReadTimeout: timeout, WriteTimeout: timeout}
+	ch, err := t.In(msg, server)
+	if err != nil {
+		return nil, err
+	}
+
+	var rrs []dns.RR
+	for env := range ch {
+		if env.Error != nil {
+			err = env.Error
+			continue
+		}
+		rrs = append(rrs, env.RR...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rrs, nil
+}
@@ -0,0 +1,209 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// fakeDNSZone 固定应答的DNS，未配置的名称返回 NXDOMAIN
+type fakeDNSZone struct {
+	records []string          // RFC 1035 格式的记录
+	axfr    map[string]string // 允许区域传送的服务器地址 -> 区域
+}
+
+func (z *fakeDNSZone) exchange(_ context.Context, _ string, msg *dns.Msg, _ time.Duration) (*dns.Msg, error) {
+	q := msg.Question[0]
+	resp := new(dns.Msg)
+	resp.SetReply(msg)
+	exists := false
+	for _, line := range z.records {
+		rr, err := dns.NewRR(line)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(rr.Header().Name, q.Name) {
+			continue
+		}
+		exists = true
+		if rr.Header().Rrtype == q.Qtype {
+			resp.Answer = append(resp.Answer, rr)
+		}
+	}
+	if !exists {
+		resp.Rcode = dns.RcodeNameError
+	}
+	return resp, nil
+}
+
+func (z *fakeDNSZone) transfer(_ context.Context, server, zone string, _ time.Duration) ([]dns.RR, error) {
+	if z.axfr[server] != dns.Fqdn(zone) {
+		return nil, errors.New("transfer refused")
+	}
+	var rrs []dns.RR
+	for _, line := range z.records {
+		rr, _ := dns.NewRR(line)
+		rrs = append(rrs, rr)
+	}
+	return rrs, nil
+}
+
+func TestDNSRecordScan(t *testing.T) {
+	zone := &fakeDNSZone{
+		records: []string{
+			"example.com. 300 IN SOA ns1.example.com. admin.example.com. 2024010101 3600 600 86400 300",
+			"example.com. 300 IN NS ns1.example.com.",
+			"example.com. 300 IN NS ns2.example.com.",
+			"example.com. 300 IN A 192.0.2.1",
+			"example.com. 300 IN MX 10 mail.example.com.",
+			`example.com. 300 IN TXT "v=spf1 include:_spf.example.net ?all"`,
+			`example.com. 300 IN CAA 0 issue "letsencrypt.org"`,
+			"ns1.example.com. 300 IN A 192.0.2.53",
+			"ns2.example.com. 300 IN A 192.0.2.54",
+			"www.example.com. 300 IN CNAME example.com.",
+			"secure.com. 300 IN SOA ns1.secure.com. admin.secure.com. 1 3600 600 86400 300",
+			`secure.com. 300 IN TXT "v=spf1 mx -all"`,
+			`_dmarc.secure.com. 300 IN TXT "v=DMARC1; p=reject"`,
+		},
+		axfr: map[string]string{"192.0.2.54:53": "example.com."},
+	}
+	s := NewDNSRecordScanner()
+	s.exchange = zone.exchange
+	s.transfer = zone.transfer
+
+	result, err := s.Scan(context.Background(), &ScanConfig{
+		Target:  "example.com\nsecure.com\nmissing.example.com",
+		Assets:  []*Asset{{Authority: "www.example.com:443", Host: "www.example.com", Port: 443}},
+		Options: &DNSRecordOptions{Resolvers: []string{"127.0.0.1"}, AXFR: true},
+	})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, r := range result.DNSRecords {
+		got[r.Domain+" "+r.Type+" "+r.Value] = true
+	}
+	for _, want := range []string{
+		"example.com SOA ns1.example.com admin.example.com 2024010101 3600 600 86400 300",
+		"example.com NS ns2.example.com",
+		"example.com A 192.0.2.1",
+		"example.com MX 10 mail.example.com",
+		"example.com TXT v=spf1 include:_spf.example.net ?all",
+		`example.com CAA 0 issue "letsencrypt.org"`,
+		"www.example.com CNAME example.com",
+		"secure.com TXT v=DMARC1; p=reject",
+	} {
+		if !got[want] {
+			t.Errorf("missing record %q", want)
+		}
+	}
+	for k := range got {
+		if strings.HasPrefix(k, "missing.example.com ") {
+			t.Errorf("unexpected record for NXDOMAIN: %q", k)
+		}
+	}
+
+	findings := make(map[string]*Vulnerability)
+	for _, v := range result.Vulnerabilities {
+		findings[v.Host+" "+v.PocFile] = v
+	}
+	if len(findings) != 3 {
+		t.Errorf("got %d findings, want 3: %v", len(findings), findings)
+	}
+	if _, ok := findings["example.com dns-spf-permissive"]; !ok {
+		t.Error("missing permissive SPF finding")
+	}
+	if _, ok := findings["example.com dns-dmarc-missing"]; !ok {
+		t.Error("missing DMARC finding")
+	}
+	if v, ok := findings["example.com dns-zone-transfer"]; !ok {
+		t.Error("missing zone transfer finding")
+	} else if len(v.ExtractedResults) != 1 || v.ExtractedResults[0] != "ns2.example.com" {
+		t.Errorf("zone transfer servers = %v, want [ns2.example.com]", v.ExtractedResults)
+	}
+}
+
+func TestPermissiveSPF(t *testing.T) {
+	tests := []struct {
+		spf        string
+		permissive bool
+	}{
+		{"v=spf1 -all", false},
+		{"v=spf1 include:_spf.google.com ~all", false},
+		{"v=spf1 redirect=_spf.example.com", false},
+		{"v=spf1 +all", true},
+		{"v=spf1 a mx all", true},
+		{"v=spf1 ip4:192.0.2.0/24 ?all", true},
+		{"v=spf1 ip4:192.0.2.0/24", true},
+	}
+	for _, tt := range tests {
+		if got := permissiveSPF(tt.spf) != ""; got != tt.permissive {
+			t.Errorf("permissiveSPF(%q) = %v, want %v", tt.spf, got, tt.permissive)
+		}
+	}
+}
+
+func TestDNSRecordScanSkipsOutOfScopeNameservers(t *testing.T) {
+	zone := &fakeDNSZone{
+		records: []string{
+			"example.com. 300 IN SOA ns1.example.com. admin.example.com. 1 3600 600 86400 300",
+			"example.com. 300 IN NS ns1.example.com.",
+			"example.com. 300 IN NS ns.provider.net.",
+			"example.com. 300 IN NS ns2.example.com.",
+			"ns1.example.com. 300 IN A 192.0.2.53",
+			"ns2.example.com. 300 IN A 203.0.113.53",
+			"ns.provider.net. 300 IN A 198.51.100.53",
+		},
+		axfr: map[string]string{"192.0.2.53:53": "example.com.", "198.51.100.53:53": "example.com.", "203.0.113.53:53": "example.com."},
+	}
+	var transferred []string
+	s := NewDNSRecordScanner()
+	s.exchange = zone.exchange
+	s.transfer = func(ctx context.Context, server, name string, timeout time.Duration) ([]dns.RR, error) {
+		transferred = append(transferred, server)
+		return zone.transfer(ctx, server, name, timeout)
+	}
+
+	var logs []string
+	result, err := s.Scan(context.Background(), &ScanConfig{
+		Target: "example.com",
+		Options: &DNSRecordOptions{
+			Resolvers: []string{"127.0.0.1"},
+			AXFR:      true,
+			InScope: func(host string) bool {
+				return strings.HasSuffix(host, "example.com") || strings.HasPrefix(host, "192.0.2.")
+			},
+		},
+		TaskLogger: func(level, format string, args ...interface{}) {
+			logs = append(logs, fmt.Sprintf(format, args...))
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if len(transferred) != 1 || transferred[0] != "192.0.2.53:53" {
+		t.Errorf("transfers attempted against %v, want only [192.0.2.53:53]", transferred)
+	}
+	for _, v := range result.Vulnerabilities {
+		if v.PocFile != "dns-zone-transfer" {
+			continue
+		}
+		if len(v.ExtractedResults) != 1 || v.ExtractedResults[0] != "ns1.example.com" {
+			t.Errorf("zone transfer servers = %v, want [ns1.example.com]", v.ExtractedResults)
+		}
+	}
+	joined := strings.Join(logs, "\n")
+	if !strings.Contains(joined, "ns.provider.net not in scope") {
+		t.Errorf("out-of-scope nameserver not logged: %v", logs)
+	}
+	if !strings.Contains(joined, "ns2.example.com (203.0.113.53) not in scope") {
+		t.Errorf("out-of-scope nameserver address not logged: %v", logs)
+	}
+}
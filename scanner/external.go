@@ -0,0 +1,550 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gopkg.in/yaml.v3"
+)
+
+// 外部扫描器输入格式
+const (
+	ExternalInputHostPorts = "hostports" // 每行一个 host:port（默认）
+	ExternalInputHosts     = "hosts"     // 每行一个主机，已去重
+	ExternalInputURLs      = "urls"      // 每行一个URL，只包含HTTP资产
+	ExternalInputTargets   = "targets"   // 任务原始目标
+	ExternalInputJSONL     = "jsonl"     // 每行一个 Asset JSON
+)
+
+// 外部扫描器结果类型
+const (
+	ExternalResultAuto  = ""      // 根据每行的 type 字段区分，缺省为资产
+	ExternalResultAsset = "asset" // 全部按资产解析
+	ExternalResultVul   = "vul"   // 全部按漏洞解析
+)
+
+// 参数占位符：出现 {{input}} 时输入写入文件，否则通过 stdin 传入；
+// 出现 {{output}} 时从文件读取结果，否则读取 stdout
+const (
+	ExternalInputPlaceholder  = "{{input}}"
+	ExternalOutputPlaceholder = "{{output}}"
+)
+
+const (
+	defaultExternalTimeout = 1800    // 默认超时(秒)
+	maxExternalLineSize    = 4 << 20 // 单行输出上限
+	maxExternalStderr      = 4096    // 保留的stderr长度
+)
+
+var externalNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ExternalScannerSpec 外部扫描器声明，可来自配置文件或数据库
+type ExternalScannerSpec struct {
+	Name        string            `json:"name" yaml:"name"`
+	Description string            `json:"description,omitempty" yaml:"description"`
+	Binary      string            `json:"binary" yaml:"binary"`                     // 可执行文件路径或 PATH 中的命令
+	Args        []string          `json:"args,omitempty" yaml:"args"`               // 命令行参数，支持 {{input}}、{{output}} 占位符
+	Env         map[string]string `json:"env,omitempty" yaml:"env"`                 // 额外环境变量
+	InputFormat string            `json:"inputFormat,omitempty" yaml:"inputFormat"` // hostports/hosts/urls/targets/jsonl
+	ResultType  string            `json:"resultType,omitempty" yaml:"resultType"`   // 空/asset/vul
+	FieldMap    map[string]string `json:"fieldMap,omitempty" yaml:"fieldMap"`       // 结果字段 -> 输出JSON中的路径（点号分隔）
+	Timeout     int               `json:"timeout,omitempty" yaml:"timeout"`         // 超时(秒)
+}
+
+// Validate 校验声明是否完整
+func (s *ExternalScannerSpec) Validate() error {
+	if !externalNamePattern.MatchString(s.Name) {
+		return fmt.Errorf("invalid external scanner name %q", s.Name)
+	}
+	if strings.TrimSpace(s.Binary) == "" {
+		return fmt.Errorf("external scanner %s: binary is required", s.Name)
+	}
+	switch s.InputFormat {
+	case "", ExternalInputHostPorts, ExternalInputHosts, ExternalInputURLs, ExternalInputTargets, ExternalInputJSONL:
+	default:
+		return fmt.Errorf("external scanner %s: unknown input format %q", s.Name, s.InputFormat)
+	}
+	switch s.ResultType {
+	case ExternalResultAuto, ExternalResultAsset, ExternalResultVul:
+	default:
+		return fmt.Errorf("external scanner %s: unknown result type %q", s.Name, s.ResultType)
+	}
+	if s.Timeout < 0 {
+		return fmt.Errorf("external scanner %s: timeout must be non-negative", s.Name)
+	}
+	return nil
+}
+
+// externalScannerFile 外部扫描器配置文件格式
+type externalScannerFile struct {
+	Scanners []*ExternalScannerSpec `yaml:"scanners"`
+}
+
+// LoadExternalScannerSpecs 从 YAML/JSON 文件加载外部扫描器声明
+func LoadExternalScannerSpecs(path string) ([]*ExternalScannerSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read external scanner file: %w", err)
+	}
+	var file externalScannerFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse external scanner file: %w", err)
+	}
+	for _, spec := range file.Scanners {
+		if err := spec.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return file.Scanners, nil
+}
+
+// ExternalScanOptions 外部扫描器运行选项
+// 命令行参数只能由管理员在声明中配置，任务只能调整超时
+type ExternalScanOptions struct {
+	Timeout int `json:"timeout"` // 覆盖声明中的超时(秒)，不能超过声明的值
+}
+
+// Validate 验证 ExternalScanOptions 配置是否有效
+// 实现 ScannerOptions 接口
+func (o *ExternalScanOptions) Validate() error {
+	if o.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative, got %d", o.Timeout)
+	}
+	return nil
+}
+
+// ExternalScanner 通过子进程运行的外部扫描器
+// 输入按 InputFormat 逐行写入，输出为 JSONL，每行映射为 Asset 或 Vulnerability
+type ExternalScanner struct {
+	BaseScanner
+	spec *ExternalScannerSpec
+}
+
+// NewExternalScanner 创建外部扫描器
+func NewExternalScanner(spec *ExternalScannerSpec) *ExternalScanner {
+	return &ExternalScanner{
+		BaseScanner: BaseScanner{name: spec.Name},
+		spec:        spec,
+	}
+}
+
+// Spec 返回扫描器声明
+func (s *ExternalScanner) Spec() *ExternalScannerSpec {
+	return s.spec
+}
+
+// timeout 返回本次运行的超时(秒)，任务只能缩短声明中的超时
+func (s *ExternalScanner) timeout(opts *ExternalScanOptions) int {
+	limit := s.spec.Timeout
+	if limit <= 0 {
+		limit = defaultExternalTimeout
+	}
+	if opts.Timeout > 0 && opts.Timeout < limit {
+		return opts.Timeout
+	}
+	return limit
+}
+
+// Scan 执行外部扫描
+func (s *ExternalScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	opts := &ExternalScanOptions{}
+	if o, ok := GetTypedOptions[*ExternalScanOptions](config); ok && o != nil {
+		opts = o
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+	}
+
+	lines := s.buildInput(config)
+	if len(lines) == 0 {
+		s.log(config, "warn", "External scanner %s: no input, skipped", s.spec.Name)
+		return result, nil
+	}
+
+	timeout := s.timeout(opts)
+	runCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	workDir, err := os.MkdirTemp("", "cscan-ext-")
+	if err != nil {
+		return nil, fmt.Errorf("create work dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	inputFile := filepath.Join(workDir, "input.txt")
+	outputFile := filepath.Join(workDir, "output.jsonl")
+	input := strings.Join(lines, "\n") + "\n"
+
+	args := append([]string{}, s.spec.Args...)
+	useInputFile, useOutputFile := false, false
+	for i, arg := range args {
+		if strings.Contains(arg, ExternalInputPlaceholder) {
+			useInputFile = true
+		}
+		if strings.Contains(arg, ExternalOutputPlaceholder) {
+			useOutputFile = true
+		}
+		arg = strings.ReplaceAll(arg, ExternalInputPlaceholder, inputFile)
+		args[i] = strings.ReplaceAll(arg, ExternalOutputPlaceholder, outputFile)
+	}
+
+	cmd := exec.CommandContext(runCtx, s.spec.Binary, args...)
+	cmd.Dir = workDir
+	cmd.Env = os.Environ()
+	for k, v := range s.spec.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	if useInputFile {
+		if err := os.WriteFile(inputFile, []byte(input), 0600); err != nil {
+			return nil, fmt.Errorf("write input file: %w", err)
+		}
+	} else {
+		cmd.Stdin = strings.NewReader(input)
+	}
+	stderr := &limitedBuffer{limit: maxExternalStderr}
+	cmd.Stderr = stderr
+
+	s.log(config, "info", "External scanner %s: %d inputs, command: %s %s", s.spec.Name, len(lines), s.spec.Binary, strings.Join(args, " "))
+	startTime := time.Now()
+
+	var runErr error
+	if useOutputFile {
+		runErr = cmd.Run()
+		if f, err := os.Open(outputFile); err == nil {
+			s.parseOutput(f, result, config)
+			f.Close()
+		} else if runErr == nil {
+			runErr = fmt.Errorf("read output file: %w", err)
+		}
+	} else {
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, fmt.Errorf("stdout pipe: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("start %s: %w", s.spec.Binary, err)
+		}
+		s.parseOutput(stdout, result, config)
+		runErr = cmd.Wait()
+	}
+
+	s.log(config, "info", "External scanner %s finished: assets=%d, vuls=%d (%.2fs)",
+		s.spec.Name, len(result.Assets), len(result.Vulnerabilities), time.Since(startTime).Seconds())
+
+	if runCtx.Err() == context.DeadlineExceeded {
+		return result, fmt.Errorf("external scanner %s timeout after %ds", s.spec.Name, timeout)
+	}
+	if runErr != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return result, fmt.Errorf("external scanner %s: %w: %s", s.spec.Name, runErr, msg)
+		}
+		return result, fmt.Errorf("external scanner %s: %w", s.spec.Name, runErr)
+	}
+	return result, nil
+}
+
+// buildInput 按输入格式生成输入行，没有资产时回退到任务目标
+func (s *ExternalScanner) buildInput(config *ScanConfig) []string {
+	targets := append([]string{}, config.Targets...)
+	for _, line := range strings.Split(config.Target, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			targets = append(targets, line)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var lines []string
+	add := func(line string) {
+		if line != "" && !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+
+	format := s.spec.InputFormat
+	if format == "" {
+		format = ExternalInputHostPorts
+	}
+	if format == ExternalInputTargets || len(config.Assets) == 0 {
+		for _, t := range targets {
+			if format == ExternalInputJSONL {
+				data, _ := json.Marshal(map[string]string{"target": t})
+				add(string(data))
+			} else {
+				add(t)
+			}
+		}
+		return lines
+	}
+
+	for _, asset := range config.Assets {
+		switch format {
+		case ExternalInputHosts:
+			add(asset.Host)
+		case ExternalInputURLs:
+			if u := assetURL(asset); u != "" {
+				add(u)
+			}
+		case ExternalInputJSONL:
+			data, err := json.Marshal(asset)
+			if err == nil {
+				add(string(data))
+			}
+		default:
+			if asset.Authority != "" {
+				add(asset.Authority)
+			} else if asset.Port > 0 {
+				add(net.JoinHostPort(asset.Host, strconv.Itoa(asset.Port)))
+			} else {
+				add(asset.Host)
+			}
+		}
+	}
+	return lines
+}
+
+// assetURL 拼接HTTP资产的URL，非HTTP资产返回空
+func assetURL(asset *Asset) string {
+	if !asset.IsHTTP && !IsHTTPService(asset.Service, asset.Port) {
+		return ""
+	}
+	scheme := "http"
+	if asset.Port == 443 || asset.Port == 8443 || strings.Contains(asset.Service, "https") || strings.Contains(asset.Service, "ssl") {
+		scheme = "https"
+	}
+	host := asset.Authority
+	if host == "" {
+		host = net.JoinHostPort(asset.Host, strconv.Itoa(asset.Port))
+	}
+	return scheme + "://" + host + asset.Path
+}
+
+// parseOutput 逐行解析JSONL输出，非JSON行忽略
+func (s *ExternalScanner) parseOutput(r io.Reader, result *ScanResult, config *ScanConfig) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxExternalLineSize)
+
+	invalid := 0
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var raw map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+			invalid++
+			continue
+		}
+		asset, vul := s.convert(raw)
+		if asset != nil {
+			result.Assets = append(result.Assets, asset)
+		}
+		if vul != nil {
+			result.Vulnerabilities = append(result.Vulnerabilities, vul)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		s.log(config, "warn", "External scanner %s: read output failed: %v", s.spec.Name, err)
+	}
+	if invalid > 0 {
+		s.log(config, "warn", "External scanner %s: skipped %d non-JSON output lines", s.spec.Name, invalid)
+	}
+}
+
+// convert 将一行输出映射为资产或漏洞，无法识别时均返回 nil
+func (s *ExternalScanner) convert(raw map[string]interface{}) (*Asset, *Vulnerability) {
+	fields := make(map[string]interface{}, len(raw)+len(s.spec.FieldMap))
+	for k, v := range raw {
+		fields[k] = v
+	}
+	for field, path := range s.spec.FieldMap {
+		if v, ok := lookupJSONPath(raw, path); ok {
+			fields[field] = v
+		}
+	}
+	coerceExternalFields(fields)
+
+	isVul := s.spec.ResultType == ExternalResultVul
+	if s.spec.ResultType == ExternalResultAuto {
+		switch strings.ToLower(fmt.Sprint(fields["type"])) {
+		case "vul", "vuln", "vulnerability":
+			isVul = true
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil
+	}
+	if isVul {
+		var vul Vulnerability
+		if err := json.Unmarshal(data, &vul); err != nil {
+			return nil, nil
+		}
+		return nil, s.normalizeVul(&vul)
+	}
+	var asset Asset
+	if err := json.Unmarshal(data, &asset); err != nil {
+		return nil, nil
+	}
+	return s.normalizeAsset(&asset), nil
+}
+
+func (s *ExternalScanner) normalizeAsset(asset *Asset) *Asset {
+	if asset.Host == "" && asset.Authority != "" {
+		asset.Host, asset.Port = splitAuthority(asset.Authority)
+	}
+	if asset.Host == "" {
+		return nil
+	}
+	if asset.Authority == "" {
+		asset.Authority = joinAuthority(asset.Host, asset.Port)
+	}
+	if asset.Category == "" {
+		asset.Category = getCategory(asset.Host)
+	}
+	if asset.Source == "" {
+		asset.Source = s.spec.Name
+	}
+	return asset
+}
+
+func (s *ExternalScanner) normalizeVul(vul *Vulnerability) *Vulnerability {
+	if vul.Host == "" && vul.Url != "" {
+		if u, err := url.Parse(vul.Url); err == nil && u.Hostname() != "" {
+			vul.Host = u.Hostname()
+			vul.Port, _ = strconv.Atoi(u.Port())
+			if vul.Port == 0 {
+				vul.Port = 80
+				if u.Scheme == "https" {
+					vul.Port = 443
+				}
+			}
+		}
+	}
+	if vul.Host == "" && vul.Authority != "" {
+		vul.Host, vul.Port = splitAuthority(vul.Authority)
+	}
+	if vul.Host == "" {
+		return nil
+	}
+	if vul.Authority == "" {
+		vul.Authority = joinAuthority(vul.Host, vul.Port)
+	}
+	if vul.PocFile == "" {
+		vul.PocFile = vul.VulName
+	}
+	if vul.PocFile == "" {
+		return nil
+	}
+	vul.Severity = strings.ToLower(vul.Severity)
+	if vul.Severity == "" {
+		vul.Severity = "info"
+	}
+	if vul.Source == "" {
+		vul.Source = s.spec.Name
+	}
+	return vul
+}
+
+func (s *ExternalScanner) log(config *ScanConfig, level, format string, args ...interface{}) {
+	if config.TaskLogger != nil {
+		config.TaskLogger(level, format, args...)
+		return
+	}
+	logx.Infof(format, args...)
+}
+
+// lookupJSONPath 按点号路径读取嵌套字段
+func lookupJSONPath(data map[string]interface{}, path string) (interface{}, bool) {
+	var cur interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// coerceExternalFields 兼容常见的类型差异：端口为字符串、列表字段为单个字符串、状态码为数字
+func coerceExternalFields(fields map[string]interface{}) {
+	if v, ok := fields["port"].(string); ok {
+		if port, err := strconv.Atoi(v); err == nil {
+			fields["port"] = port
+		} else {
+			delete(fields, "port")
+		}
+	}
+	for _, key := range []string{"app", "tags", "references", "extractedResults"} {
+		if v, ok := fields[key].(string); ok {
+			fields[key] = []string{v}
+		}
+	}
+	for _, key := range []string{"httpStatus", "title", "service", "severity", "pocFile", "vulName"} {
+		if v, ok := fields[key]; ok && v != nil {
+			if _, isString := v.(string); !isString {
+				fields[key] = fmt.Sprint(v)
+			}
+		}
+	}
+}
+
+func splitAuthority(authority string) (string, int) {
+	host, portStr, err := net.SplitHostPort(authority)
+	if err != nil {
+		return authority, 0
+	}
+	port, _ := strconv.Atoi(portStr)
+	return host, port
+}
+
+func joinAuthority(host string, port int) string {
+	if port <= 0 {
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// limitedBuffer 只保留前 limit 字节的缓冲区
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remain := b.limit - b.buf.Len(); remain > 0 {
+		if len(p) > remain {
+			b.buf.Write(p[:remain])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+)
+
+func TestExternalScannerScan(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	// 每个输入 host:port 输出一条资产，另输出一条漏洞和一行非JSON日志
+	s := NewExternalScanner(&ExternalScannerSpec{
+		Name:   "test-ext",
+		Binary: "sh",
+		Args: []string{"-c", `while read hp; do echo "{\"target\":\"$hp\",\"svc\":{\"name\":\"http\"}}"; done
+echo "scan finished"
+echo '{"type":"vuln","url":"https://a.com/admin","vulName":"Admin Exposed","severity":"HIGH"}'`},
+		FieldMap: map[string]string{"authority": "target", "service": "svc.name"},
+	})
+
+	result, err := s.Scan(context.Background(), &ScanConfig{
+		Assets: []*Asset{
+			{Authority: "a.com:80", Host: "a.com", Port: 80},
+			{Authority: "a.com:80", Host: "a.com", Port: 80},
+			{Authority: "b.com:8080", Host: "b.com", Port: 8080},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	if len(result.Assets) != 2 {
+		t.Fatalf("assets = %d, want 2", len(result.Assets))
+	}
+	got := result.Assets[1]
+	if got.Host != "b.com" || got.Port != 8080 || got.Service != "http" || got.Source != "test-ext" {
+		t.Errorf("asset = %+v", got)
+	}
+
+	if len(result.Vulnerabilities) != 1 {
+		t.Fatalf("vuls = %d, want 1", len(result.Vulnerabilities))
+	}
+	vul := result.Vulnerabilities[0]
+	if vul.Authority != "a.com:443" || vul.PocFile != "Admin Exposed" || vul.Severity != "high" {
+		t.Errorf("vul = %+v", vul)
+	}
+}
+
+func TestExternalScannerSpecValidate(t *testing.T) {
+	cases := []struct {
+		spec ExternalScannerSpec
+		ok   bool
+	}{
+		{ExternalScannerSpec{Name: "tool", Binary: "/usr/bin/tool"}, true},
+		{ExternalScannerSpec{Binary: "/usr/bin/tool"}, false},
+		{ExternalScannerSpec{Name: "tool"}, false},
+		{ExternalScannerSpec{Name: "tool", Binary: "tool", InputFormat: "xml"}, false},
+		{ExternalScannerSpec{Name: "tool", Binary: "tool", ResultType: "other"}, false},
+	}
+	for i, c := range cases {
+		if err := c.spec.Validate(); (err == nil) != c.ok {
+			t.Errorf("case %d: Validate() = %v, want ok=%v", i, err, c.ok)
+		}
+	}
+}
+
+func TestExternalScannerTimeoutCappedBySpec(t *testing.T) {
+	cases := []struct {
+		spec, task, want int
+	}{
+		{600, 0, 600},
+		{600, 60, 60},
+		{600, 3600, 600},
+		{0, 0, defaultExternalTimeout},
+		{0, 86400, defaultExternalTimeout},
+	}
+	for i, c := range cases {
+		s := NewExternalScanner(&ExternalScannerSpec{Name: "tool", Binary: "tool", Timeout: c.spec})
+		if got := s.timeout(&ExternalScanOptions{Timeout: c.task}); got != c.want {
+			t.Errorf("case %d: timeout = %d, want %d", i, got, c.want)
+		}
+	}
+}
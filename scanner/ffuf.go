@@ -9,6 +9,8 @@ import (
 	"sync"
 	"strconv"
 
+	"cscan/pkg/utils"
+
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
 	"github.com/ffuf/ffuf/v2/pkg/filter"
 	"github.com/ffuf/ffuf/v2/pkg/input"
@@ -311,6 +313,12 @@ func (s *FFufScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult
 
 		allAssets = append(allAssets, assets...)
 		logInfo("[FFuf] 目标 %s 发现 %d 个有效路径", target, len(assets))
+		// 单个目标扫描完成后依次回调该目标发现的全部路径
+		if config.OnResult != nil {
+			for _, asset := range assets {
+				config.OnResult(asset)
+			}
+		}
 
 		if onProgress != nil {
 			progress := (i + 1) * 100 / len(targets)
@@ -557,7 +565,7 @@ func (s *FFufScanner) collectTargets(config *ScanConfig, logInfo, logDebug func(
 				if (scheme == "http" && asset.Port == 80) || (scheme == "https" && asset.Port == 443) {
 					baseURL = fmt.Sprintf("%s://%s", scheme, asset.Host)
 				} else {
-					baseURL = fmt.Sprintf("%s://%s:%d", scheme, asset.Host, asset.Port)
+					baseURL = fmt.Sprintf("%s://%s", scheme, utils.BuildTargetWithPort(asset.Host, asset.Port))
 				}
 
 				if asset.Path != "" && asset.Path != "/" {
@@ -33,6 +33,9 @@ type FingerprintScanner struct {
 	client                  *http.Client
 	wappalyzerClient        *wappalyzer.Wappalyze
 	customFingerprintEngine *CustomFingerprintEngine
+
+	wappalyzerCPEOnce sync.Once
+	wappalyzerCPE     map[string]string // 小写应用名 -> Wappalyzer库中的CPE
 }
 
 // AppDetectionResult 应用检测结果，用于合并多个来源的识别结果
@@ -171,12 +174,29 @@ func (s *FingerprintScanner) Scan(ctx context.Context, config *ScanConfig) (*Sca
 		Assets:      make([]*Asset, 0),
 	}
 
+	// emit 资产识别完成后生成CPE并回调，每个资产只处理一次
+	// 启用主动指纹时资产在主动扫描结束后才完成识别
+	emitted := make(map[*Asset]bool)
+	emit := func(assets ...*Asset) {
+		for _, asset := range assets {
+			if emitted[asset] {
+				continue
+			}
+			emitted[asset] = true
+			s.finalizeAssets([]*Asset{asset}, opts, taskLog)
+			if config.OnResult != nil {
+				config.OnResult(asset)
+			}
+		}
+	}
+
 	// 过滤出HTTP/HTTPS相关的资产
 	httpAssets := filterHttpAssets(config.Assets)
 	if len(httpAssets) == 0 {
 		taskLog("INFO", "Fingerprint: no HTTP/HTTPS assets found, skipping")
-		// 返回所有原始资产，但不进行指纹识别
+		// 返回所有原始资产，非HTTP资产仍通过Banner识别版本
 		result.Assets = config.Assets
+		emit(result.Assets...)
 		return result, nil
 	}
 
@@ -215,6 +235,7 @@ func (s *FingerprintScanner) Scan(ctx context.Context, config *ScanConfig) (*Sca
 				}
 			}
 			taskLog("INFO", "Fingerprint: total %d assets preserved after timeout", len(result.Assets))
+			emit(result.Assets...)
 			return result, nil
 		default:
 			// 如果使用httpx且已获取到基本信息，只执行附加功能
@@ -238,6 +259,9 @@ func (s *FingerprintScanner) Scan(ctx context.Context, config *ScanConfig) (*Sca
 			}
 			processedSet[i] = true
 			result.Assets = append(result.Assets, asset)
+			if !opts.ActiveScan {
+				emit(asset)
+			}
 		}
 	}
 
@@ -267,6 +291,7 @@ func (s *FingerprintScanner) Scan(ctx context.Context, config *ScanConfig) (*Sca
 		taskLog("DEBUG", "Active fingerprint scan not enabled (activeScan=%v)", opts.ActiveScan)
 	}
 
+	emit(result.Assets...)
 	return result, nil
 }
 
@@ -411,12 +436,12 @@ func GetHttpServiceChecker() HttpServiceChecker {
 
 // runAdditionalFingerprint 执行额外的指纹识别功能（httpx已执行后）
 func (s *FingerprintScanner) runAdditionalFingerprint(ctx context.Context, asset *Asset, opts *FingerprintOptions, taskLog func(level, format string, args ...interface{})) {
-	targetUrl := fmt.Sprintf("%s://%s:%d", asset.Service, asset.Host, asset.Port)
+	targetUrl := fmt.Sprintf("%s://%s", asset.Service, utils.BuildTargetWithPort(asset.Host, asset.Port))
 	if asset.Service == "" {
 		if asset.Port == 443 || asset.Port == 8443 {
-			targetUrl = fmt.Sprintf("https://%s:%d", asset.Host, asset.Port)
+			targetUrl = fmt.Sprintf("https://%s", utils.BuildTargetWithPort(asset.Host, asset.Port))
 		} else {
-			targetUrl = fmt.Sprintf("http://%s:%d", asset.Host, asset.Port)
+			targetUrl = fmt.Sprintf("http://%s", utils.BuildTargetWithPort(asset.Host, asset.Port))
 		}
 	}
 
@@ -581,6 +606,7 @@ func (s *FingerprintScanner) runAdditionalFingerprint(ctx context.Context, asset
 					CustomIDs:    []string{customApp.Id},
 				}
 			}
+			applyAppVersion(appResults[appNameLower], customApp.Version)
 			if taskLog != nil {
 				taskLog("INFO", "发现应用指纹: %s:%d -> %s (来源: custom)", asset.Host, asset.Port, customApp.Name)
 			} else {
@@ -732,7 +758,7 @@ func (s *FingerprintScanner) fingerprint(ctx context.Context, asset *Asset, opts
 			return
 		}
 
-		targetUrl := fmt.Sprintf("%s://%s:%d", scheme, asset.Host, asset.Port)
+		targetUrl := fmt.Sprintf("%s://%s", scheme, utils.BuildTargetWithPort(asset.Host, asset.Port))
 		resp, err := s.client.Get(targetUrl)
 		if err != nil {
 			continue
@@ -826,6 +852,7 @@ func (s *FingerprintScanner) fingerprint(ctx context.Context, asset *Asset, opts
 						CustomIDs:    []string{customApp.Id},
 					}
 				}
+				applyAppVersion(appResults[appNameLower], customApp.Version)
 				if taskLog != nil {
 					taskLog("INFO", "发现应用指纹: %s:%d -> %s (来源: custom)", asset.Host, asset.Port, customApp.Name)
 				} else {
@@ -917,7 +944,7 @@ func (s *FingerprintScanner) runHttpx(ctx context.Context, assets []*Asset, opts
 	var targets []string
 	targetMap := make(map[string]*Asset)
 	for _, asset := range assets {
-		target := fmt.Sprintf("%s:%d", asset.Host, asset.Port)
+		target := utils.BuildTargetWithPort(asset.Host, asset.Port)
 		targets = append(targets, target)
 		targetMap[target] = asset
 	}
@@ -1605,7 +1632,7 @@ func (s *FingerprintScanner) RunActiveFingerprint(ctx context.Context, assets []
 				scheme = "http"
 			}
 		}
-		baseURL := fmt.Sprintf("%s://%s:%d", scheme, asset.Host, asset.Port)
+		baseURL := fmt.Sprintf("%s://%s", scheme, utils.BuildTargetWithPort(asset.Host, asset.Port))
 
 		for _, fp := range activeFingerprints {
 			if !fp.Enabled || len(fp.ActivePaths) == 0 {
@@ -1724,8 +1751,11 @@ func (s *FingerprintScanner) RunActiveFingerprint(ctx context.Context, assets []
 							logx.Debugf("Active fingerprint matched: %s -> %s (path: %s)", baseURL, fp.Name, path)
 						}
 
-						// 添加到资产的App列表
+						// 添加到资产的App列表，提取到版本时格式为 名称:版本
 						appName := fmt.Sprintf("%s[active(%s)]", fp.Name, fp.Id.Hex())
+						if version := ExtractVersion(fp, fpData); version != "" {
+							appName = fmt.Sprintf("%s:%s[active(%s)]", fp.Name, version, fp.Id.Hex())
+						}
 
 						// 检查是否已存在
 						exists := false
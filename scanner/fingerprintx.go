@@ -8,6 +8,8 @@ import (
 	"sync"
 	"time"
 
+	"cscan/pkg/utils"
+
 	"github.com/praetorian-inc/fingerprintx/pkg/plugins"
 	"github.com/praetorian-inc/fingerprintx/pkg/scan"
 	"github.com/zeromicro/go-zero/core/logx"
@@ -187,7 +189,7 @@ func (s *FingerprintxScanner) scanSingleAsset(
 	defer cancel()
 
 	// 解析 IP 地址和端口
-	addrPort, err := netip.ParseAddrPort(fmt.Sprintf("%s:%d", asset.Host, asset.Port))
+	addrPort, err := netip.ParseAddrPort(utils.BuildTargetWithPort(asset.Host, asset.Port))
 	if err != nil {
 		// 如果是域名，尝试解析
 		logx.Debugf("Failed to parse address %s:%d, trying as hostname: %v", asset.Host, asset.Port, err)
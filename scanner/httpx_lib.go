@@ -8,6 +8,8 @@ import (
 	"strings"
 	"sync"
 
+	"cscan/pkg/utils"
+
 	"github.com/projectdiscovery/httpx/runner"
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -49,12 +51,12 @@ func (s *FingerprintScanner) RunHttpxLib(ctx context.Context, assets []*Asset, o
 	var targets []string
 	targetMap := make(map[string]*Asset)
 	for _, asset := range assets {
-		target := fmt.Sprintf("%s:%d", asset.Host, asset.Port)
+		target := utils.BuildTargetWithPort(asset.Host, asset.Port)
 		targets = append(targets, target)
 		targetMap[target] = asset
 		// 同时添加带协议的目标，提高匹配率
-		targetMap[fmt.Sprintf("http://%s:%d", asset.Host, asset.Port)] = asset
-		targetMap[fmt.Sprintf("https://%s:%d", asset.Host, asset.Port)] = asset
+		targetMap[fmt.Sprintf("http://%s", utils.BuildTargetWithPort(asset.Host, asset.Port))] = asset
+		targetMap[fmt.Sprintf("https://%s", utils.BuildTargetWithPort(asset.Host, asset.Port))] = asset
 	}
 
 	// 结果处理锁
@@ -9,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 
+	"cscan/pkg/utils"
+
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -137,7 +139,7 @@ func (s *MasscanScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanRes
 	}
 
 	// 执行masscan扫描（传入阈值参数）
-	assets := s.runMasscan(ctx, targets, opts)
+	assets := s.runMasscan(ctx, targets, opts, config.OnResult)
 
 	return &ScanResult{
 		WorkspaceId: config.WorkspaceId,
@@ -146,8 +148,9 @@ func (s *MasscanScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanRes
 	}, nil
 }
 
-// runMasscan 运行masscan
-func (s *MasscanScanner) runMasscan(ctx context.Context, targets []string, opts *MasscanOptions) []*Asset {
+// runMasscan 运行masscan，发现的端口通过 onResult 实时回调
+// 设置了端口阈值时，超过阈值的扫描结果会被整体丢弃，此时在扫描正常结束后才回调
+func (s *MasscanScanner) runMasscan(ctx context.Context, targets []string, opts *MasscanOptions, onResult func(*Asset)) []*Asset {
 	var assets []*Asset
 
 	// 实时端口阈值检测：记录每个主机的开放端口数量和是否已超过阈值
@@ -243,12 +246,12 @@ func (s *MasscanScanner) runMasscan(ctx context.Context, targets []string, opts
 
 				// 如果原始目标是域名，使用域名作为Authority和Host
 				host := result.IP
-				authority := fmt.Sprintf("%s:%d", result.IP, port.Port)
+				authority := utils.BuildTargetWithPort(result.IP, port.Port)
 				category := getCategory(result.IP)
 
 				if domainTarget != "" {
 					host = domainTarget
-					authority = fmt.Sprintf("%s:%d", domainTarget, port.Port)
+					authority = utils.BuildTargetWithPort(domainTarget, port.Port)
 					category = "domain"
 				}
 
@@ -259,12 +262,20 @@ func (s *MasscanScanner) runMasscan(ctx context.Context, targets []string, opts
 					Category:  category,
 				}
 				assets = append(assets, asset)
+				if onResult != nil && opts.PortThreshold <= 0 {
+					onResult(asset)
+				}
 			}
 		}
 	}
 
 	cmd.Wait()
 
+	if onResult != nil && opts.PortThreshold > 0 {
+		for _, asset := range assets {
+			onResult(asset)
+		}
+	}
 	return assets
 }
 
@@ -184,7 +184,7 @@ func (s *NaabuScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResul
 	}
 
 	// 执行Naabu扫描
-	assets, thresholdExceeded := s.runNaabuWithLogger(ctx, targets, opts, logInfo, logWarn, onProgress)
+	assets, thresholdExceeded := s.runNaabuWithLogger(ctx, targets, opts, logInfo, logWarn, onProgress, config.OnResult)
 
 	if thresholdExceeded {
 		return &ScanResult{
@@ -209,8 +209,9 @@ type progressFunc func(progress int, message string)
 
 // runNaabuWithLogger 运行Naabu扫描（带日志回调）
 // 按单个目标拆分，串行执行，每个目标独立超时控制
+// 每个目标扫描完成且未超过端口阈值时，通过 onResult 回调其资产
 // 返回值: assets - 发现的资产, thresholdExceeded - 是否有任何目标超过端口阈值
-func (s *NaabuScanner) runNaabuWithLogger(ctx context.Context, targets []string, opts *NaabuOptions, logInfo, logWarn logFunc, onProgress progressFunc) ([]*Asset, bool) {
+func (s *NaabuScanner) runNaabuWithLogger(ctx context.Context, targets []string, opts *NaabuOptions, logInfo, logWarn logFunc, onProgress progressFunc, onResult func(*Asset)) ([]*Asset, bool) {
 	var allAssets []*Asset
 	anyThresholdExceeded := false // 记录是否有任何目标超过阈值
 
@@ -262,6 +263,11 @@ func (s *NaabuScanner) runNaabuWithLogger(ctx context.Context, targets []string,
 		}
 
 		allAssets = append(allAssets, assets...)
+		if onResult != nil {
+			for _, asset := range assets {
+				onResult(asset)
+			}
+		}
 	}
 
 	// 端口扫描完成，进度到30%
@@ -343,6 +349,11 @@ func (s *NaabuScanner) scanSingleTargetWithLogger(ctx context.Context, target, p
 		options.SkipHostDiscovery = true
 	}
 
+	// naabu 默认只保留 IPv4 结果，IPv6 目标需显式开启
+	if strings.Contains(target, ":") {
+		options.IPVersion = goflags.StringSlice{"4", "6"}
+	}
+
 	// 设置排除的目标
 	if opts.ExcludeHosts != "" {
 		options.ExcludeIps = opts.ExcludeHosts
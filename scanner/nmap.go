@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 
+	"cscan/pkg/utils"
+
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -366,7 +368,7 @@ func (s *NmapScanner) scanSinglePortWithLogger(ctx context.Context, targets []st
 
 		for _, nmapPort := range host.Ports.Ports {
 			if nmapPort.State.State == "open" {
-				authority := fmt.Sprintf("%s:%d", originalTarget, nmapPort.PortID)
+				authority := utils.BuildTargetWithPort(originalTarget, nmapPort.PortID)
 				hostStr := originalTarget
 				category := getCategory(originalTarget)
 
@@ -614,10 +614,10 @@ func (s *NucleiScanner) prepareTargets(assets []*Asset) []string {
 			if !strings.HasPrefix(path, "/") {
 				path = "/" + path
 			}
-			target = fmt.Sprintf("%s://%s:%d%s", scheme, asset.Host, asset.Port, path)
+			target = fmt.Sprintf("%s://%s%s", scheme, utils.BuildTargetWithPort(asset.Host, asset.Port), path)
 		} else {
 			// 无路径的情况：scheme://host:port
-			target = fmt.Sprintf("%s://%s:%d", scheme, asset.Host, asset.Port)
+			target = fmt.Sprintf("%s://%s", scheme, utils.BuildTargetWithPort(asset.Host, asset.Port))
 		}
 
 		if !seen[target] {
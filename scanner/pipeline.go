@@ -44,6 +44,32 @@ type StageOutput struct {
 	Message         string
 }
 
+// ScannerStage 将扫描器包装为管道阶段，可用于内置或外部扫描器
+func ScannerStage(s Scanner, weight int, optional bool) Stage {
+	return Stage{
+		Name:     s.Name(),
+		Weight:   weight,
+		Optional: optional,
+		Execute: func(ctx context.Context, input *StageInput) (*StageOutput, error) {
+			result, err := s.Scan(ctx, &ScanConfig{
+				Target:      input.Target,
+				Targets:     input.Targets,
+				Assets:      input.Assets,
+				Options:     input.Options,
+				WorkspaceId: input.WorkspaceId,
+				MainTaskId:  input.MainTaskId,
+			})
+			if result == nil {
+				return nil, err
+			}
+			return &StageOutput{
+				Assets:          result.Assets,
+				Vulnerabilities: result.Vulnerabilities,
+			}, err
+		},
+	}
+}
+
 // NewPipeline 创建扫描管道
 func NewPipeline(name string) *Pipeline {
 	return &Pipeline{
@@ -139,12 +165,12 @@ func (p *Pipeline) Execute(ctx context.Context, input *StageInput) (*ScanResult,
 		}
 
 		// 合并结果
+		// 阶段间的资产传递由阶段自行通过 Data 完成（如工作流按依赖关系取上游输出）
 		if len(output.Assets) > 0 {
-			// result.Assets = append(result.Assets, output.Assets...)
-			// input.Assets = append(input.Assets, output.Assets...) // 传递给下一阶段
+			result.Assets = append(result.Assets, output.Assets...)
 		}
 		if len(output.Vulnerabilities) > 0 {
-			// result.Vulnerabilities = append(result.Vulnerabilities, output.Vulnerabilities...)
+			result.Vulnerabilities = append(result.Vulnerabilities, output.Vulnerabilities...)
 		}
 
 		// 合并数据
@@ -144,7 +144,7 @@ func (s *PortScanner) scanPorts(ctx context.Context, targets []string, ports []i
 
 // isPortOpen 检查端口是否开放
 func isPortOpen(host string, port int, timeout int) bool {
-	address := fmt.Sprintf("%s:%d", host, port)
+	address := utils.BuildTargetWithPort(host, port)
 	conn, err := net.DialTimeout("tcp", address, time.Duration(timeout)*time.Second)
 	if err != nil {
 		return false
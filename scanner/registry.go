@@ -171,6 +171,11 @@ func (r *ScannerRegistry) RegisterBuiltins() {
 		return NewMasscanScanner(), nil
 	})
 
+	// UDP 端口扫描器
+	r.Register("udpscan", func(cfg *ScannerRegistryConfig) (Scanner, error) {
+		return NewUDPScanner(), nil
+	})
+
 	// URL Finder 扫描器
 	r.Register("urlfinder", func(cfg *ScannerRegistryConfig) (Scanner, error) {
 		return NewURLFinderScanner(), nil
@@ -185,6 +190,66 @@ func (r *ScannerRegistry) RegisterBuiltins() {
 	r.Register("fingerprintx", func(cfg *ScannerRegistryConfig) (Scanner, error) {
 		return NewFingerprintxScanner(), nil
 	})
+
+	// TLS证书扫描器
+	r.Register("tlscert", func(cfg *ScannerRegistryConfig) (Scanner, error) {
+		return NewTLSCertScanner(), nil
+	})
+
+	// 子域接管扫描器
+	r.Register("takeover", func(cfg *ScannerRegistryConfig) (Scanner, error) {
+		return NewTakeoverScanner(), nil
+	})
+
+	// DNS记录采集扫描器
+	r.Register("dnsrecord", func(cfg *ScannerRegistryConfig) (Scanner, error) {
+		return NewDNSRecordScanner(), nil
+	})
+
+	// 爬虫扫描器
+	r.Register("crawler", func(cfg *ScannerRegistryConfig) (Scanner, error) {
+		return NewCrawlerScanner(), nil
+	})
+
+	// 弱口令检测扫描器
+	r.Register("bruteforce", func(cfg *ScannerRegistryConfig) (Scanner, error) {
+		return NewBruteforceScanner(), nil
+	})
+
+	// CDN/WAF/云厂商识别
+	r.Register("cdn", func(cfg *ScannerRegistryConfig) (Scanner, error) {
+		return NewCDNScanner(), nil
+	})
+}
+
+// RegisterExternal 注册外部扫描器，名称不能与已注册的扫描器重复
+func (r *ScannerRegistry) RegisterExternal(spec *ExternalScannerSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	if r.Has(spec.Name) {
+		return fmt.Errorf("scanner %s already registered", spec.Name)
+	}
+	r.Register(spec.Name, func(cfg *ScannerRegistryConfig) (Scanner, error) {
+		return NewExternalScanner(spec), nil
+	})
+	return nil
+}
+
+// LoadExternalScanners 从配置文件加载并注册外部扫描器，返回注册成功的名称
+func (r *ScannerRegistry) LoadExternalScanners(path string) ([]string, error) {
+	specs, err := LoadExternalScannerSpecs(path)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		if err := r.RegisterExternal(spec); err != nil {
+			return names, err
+		}
+		names = append(names, spec.Name)
+	}
+	return names, nil
 }
 
 // ScannerInfo 扫描器信息
@@ -41,6 +41,8 @@ type ScanConfig struct {
 	TaskLogger func(level, format string, args ...interface{}) `json:"-"`
 	// OnProgress 进度回调，参数为当前进度(0-100)和描述
 	OnProgress func(progress int, message string) `json:"-"`
+	// OnResult 结果回调，扫描器确认资产后立即回调，用于扫描过程中流式上报
+	OnResult func(asset *Asset) `json:"-"`
 }
 
 // GetTypedOptions 从 ScanConfig 中提取类型安全的选项
@@ -64,6 +66,8 @@ type ScanResult struct {
 	MainTaskId      string           `json:"mainTaskId"`
 	Assets          []*Asset         `json:"assets"`
 	Vulnerabilities []*Vulnerability `json:"vulnerabilities"`
+	DNSRecords      []*DNSRecord     `json:"dnsRecords,omitempty"` // DNS记录采集结果
+	CrawlEndpoints  []*CrawlEndpoint `json:"crawlEndpoints,omitempty"` // 爬虫发现的端点
 }
 
 // Asset 资产
@@ -71,6 +75,7 @@ type Asset struct {
 	Authority  string   `json:"authority"`
 	Host       string   `json:"host"`
 	Port       int      `json:"port"`
+	Transport  string   `json:"transport,omitempty"` // 传输层协议: tcp/udp，为空表示tcp
 	Category   string   `json:"category"` // ipv4/ipv6/domain/url
 	Service    string   `json:"service"`
 	Server     string   `json:"server"`
@@ -81,13 +86,18 @@ type Asset struct {
 	HttpHeader string   `json:"httpHeader"`
 	HttpBody   string   `json:"httpBody"`
 	Cert       string   `json:"cert"`
+	CertInfo   *CertInfo `json:"certInfo,omitempty"` // TLS证书结构化信息
 	IconHash   string   `json:"iconHash"`
 	IconData   []byte   `json:"iconData,omitempty"` // favicon 图片原始数据
 	Screenshot string   `json:"screenshot"`
 	IsCDN      bool     `json:"isCdn"`
 	CName      string   `json:"cname"`
 	IsCloud    bool     `json:"isCloud"`
+	CDNProvider   string `json:"cdnProvider,omitempty"`   // CDN服务商
+	CloudProvider string `json:"cloudProvider,omitempty"` // 云厂商
+	WAF           string `json:"waf,omitempty"`           // WAF服务商
 	IsHTTP     bool     `json:"isHttp"`   // 是否为HTTP服务
+	CPE        []string `json:"cpe,omitempty"` // 带版本应用生成的CPE 2.3
 	IPV4       []IPInfo `json:"ipv4"`
 	IPV6       []IPInfo `json:"ipv6"`
 	Source     string   `json:"source"`   // 资产来源: subfinder, portscan, urlfinder, etc.
@@ -108,6 +118,11 @@ type Asset struct {
 type IPInfo struct {
 	IP       string `json:"ip"`
 	Location string `json:"location"`
+	Country  string `json:"country,omitempty"`
+	Region   string `json:"region,omitempty"`
+	City     string `json:"city,omitempty"`
+	ASN      int    `json:"asn,omitempty"`
+	Org      string `json:"org,omitempty"` // ASN 所属组织/运营商
 }
 
 // Vulnerability 漏洞
@@ -84,56 +84,6 @@ func (o *SubdomainBruteforceOptions) Validate() error {
 	return nil
 }
 
-// TakeoverResult 子域接管检测结果
-type TakeoverResult struct {
-	Subdomain   string `json:"subdomain"`
-	CName       string `json:"cname"`
-	Vulnerable  bool   `json:"vulnerable"`
-	Service     string `json:"service"`
-	Fingerprint string `json:"fingerprint"`
-}
-
-// 子域接管指纹库
-var takeoverFingerprints = map[string][]string{
-	"github":        {"There isn't a GitHub Pages site here", "For root URLs (like http://example.com/) you must provide an index.html file"},
-	"heroku":        {"No such app", "no-such-app.herokuapp.com"},
-	"amazonaws":     {"NoSuchBucket", "The specified bucket does not exist"},
-	"bitbucket":     {"Repository not found"},
-	"ghost":         {"The thing you were looking for is no longer here"},
-	"tumblr":        {"There's nothing here.", "Whatever you were looking for doesn't currently exist at this address"},
-	"shopify":       {"Sorry, this shop is currently unavailable", "Only one step left!"},
-	"wordpress":     {"Do you want to register"},
-	"teamwork":      {"Oops - We didn't find your site"},
-	"helpjuice":     {"We could not find what you're looking for"},
-	"helpscout":     {"No settings were found for this company"},
-	"cargo":         {"If you're moving your domain away from Cargo"},
-	"statuspage":    {"You are being redirected", "statuspage.io"},
-	"uservoice":     {"This UserVoice subdomain is currently available"},
-	"surge":         {"project not found"},
-	"intercom":      {"This page is reserved for artistic dogs", "Uh oh. That page doesn't exist"},
-	"webflow":       {"The page you are looking for doesn't exist or has been moved"},
-	"kajabi":        {"The page you were looking for doesn't exist"},
-	"thinkific":     {"You may have mistyped the address or the page may have moved"},
-	"tave":          {"Sorry, this page is no longer available"},
-	"wishpond":      {"https://www.wishpond.com/404?campaign=true"},
-	"aftership":     {"Oops.</h2><p class=\"text-muted text-tight\">The page you're looking for doesn't exist"},
-	"aha":           {"There is no portal here ... sending you back to Aha!"},
-	"tictail":       {"to target URL: <a href=\"https://tictail.com"},
-	"brightcove":    {"<p class=\"bc-gallery-error-code\">Error Code: 404</p>"},
-	"bigcartel":     {"<h1>Oops! We couldn&#8217;t find that page.</h1>"},
-	"acquia":        {"The site you are looking for could not be found"},
-	"fastly":        {"Fastly error: unknown domain"},
-	"pantheon":      {"The gods are wise, but do not know of the site which you seek"},
-	"zendesk":       {"Help Center Closed", "Oops, this help center no longer exists"},
-	"desk":          {"Sorry, We Couldn't Find That Page", "Please check the URL and try your request again"},
-	"unbounce":      {"The requested URL was not found on this server", "The page you're looking for doesn't exist"},
-	"pingdom":       {"Sorry, couldn't find the status page"},
-	"tilda":         {"Please renew your subscription"},
-	"smartling":     {"Domain is not configured"},
-	"campaignmonitor": {"Trying to access your account?", "Double check the URL"},
-	"azure":         {"404 Web Site not found"},
-}
-
 // Scan 执行子域名暴力破解扫描
 func (s *SubdomainBruteforceScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
 	result := &ScanResult{
@@ -762,136 +712,6 @@ func (s *SubdomainBruteforceScanner) extractSubdomainsFromJS(ctx context.Context
 	return subdomains
 }
 
-// checkSubdomainTakeover 检查子域接管漏洞
-func (s *SubdomainBruteforceScanner) checkSubdomainTakeover(ctx context.Context, assets []*Asset, opts *SubdomainBruteforceOptions, taskLog func(level, format string, args ...interface{})) {
-	var wg sync.WaitGroup
-
-	concurrent := opts.Concurrent
-	if concurrent <= 0 {
-		concurrent = 20
-	}
-
-	taskChan := make(chan *Asset, concurrent)
-
-	// HTTP客户端
-	client := &http.Client{
-		Timeout: time.Duration(opts.Timeout) * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
-
-	// 启动工作协程
-	for i := 0; i < concurrent; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for asset := range taskChan {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					result := s.checkTakeover(ctx, client, asset)
-					if result != nil && result.Vulnerable {
-						taskLog("WARN", "Takeover: %s is vulnerable! CNAME: %s, Service: %s", 
-							result.Subdomain, result.CName, result.Service)
-						// 在资产中标记接管风险
-						asset.TakeoverRisk = true
-						asset.TakeoverService = result.Service
-						asset.TakeoverCName = result.CName
-					}
-				}
-			}
-		}()
-	}
-
-	// 发送任务
-	for _, asset := range assets {
-		select {
-		case <-ctx.Done():
-			close(taskChan)
-			wg.Wait()
-			return
-		case taskChan <- asset:
-		}
-	}
-
-	close(taskChan)
-	wg.Wait()
-}
-
-// checkTakeover 检查单个子域名的接管风险
-func (s *SubdomainBruteforceScanner) checkTakeover(ctx context.Context, client *http.Client, asset *Asset) *TakeoverResult {
-	subdomain := asset.Host
-	if subdomain == "" {
-		return nil
-	}
-
-	result := &TakeoverResult{
-		Subdomain: subdomain,
-	}
-
-	// 获取CNAME记录
-	cname, err := net.LookupCNAME(subdomain)
-	if err != nil {
-		return nil
-	}
-	cname = strings.TrimSuffix(cname, ".")
-	result.CName = cname
-
-	// 检查CNAME是否指向已知的可接管服务
-	for service, fingerprints := range takeoverFingerprints {
-		if strings.Contains(strings.ToLower(cname), service) {
-			// 尝试访问并检查响应
-			for _, scheme := range []string{"https", "http"} {
-				url := fmt.Sprintf("%s://%s", scheme, subdomain)
-				req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-				if err != nil {
-					continue
-				}
-				req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-
-				resp, err := client.Do(req)
-				if err != nil {
-					// 连接失败可能意味着可接管
-					result.Vulnerable = true
-					result.Service = service
-					result.Fingerprint = "Connection failed - potential takeover"
-					return result
-				}
-				defer resp.Body.Close()
-
-				body, _ := io.ReadAll(io.LimitReader(resp.Body, 100*1024))
-				bodyStr := string(body)
-
-				// 检查指纹
-				for _, fp := range fingerprints {
-					if strings.Contains(bodyStr, fp) {
-						result.Vulnerable = true
-						result.Service = service
-						result.Fingerprint = fp
-						return result
-					}
-				}
-			}
-		}
-	}
-
-	// 检查NXDOMAIN（DNS记录存在但无法解析）
-	_, err = net.LookupIP(subdomain)
-	if err != nil {
-		// DNS解析失败，检查是否有CNAME指向外部服务
-		if cname != subdomain && cname != "" {
-			result.Vulnerable = true
-			result.Service = "unknown"
-			result.Fingerprint = "CNAME exists but target is unresolvable"
-			return result
-		}
-	}
-
-	return result
-}
-
 // detectWildcard 检测泛解析
 func (s *SubdomainBruteforceScanner) detectWildcard(domain string) map[string]bool {
 	wildcardIPs := make(map[string]bool)
@@ -0,0 +1,533 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 接管签名检查的DNS记录类型
+const (
+	TakeoverRecordCNAME = "CNAME"
+	TakeoverRecordA     = "A"
+	TakeoverRecordNS    = "NS"
+	TakeoverRecordMX    = "MX"
+)
+
+// TakeoverSignature 子域接管签名
+// Patterns 为空时匹配任意记录值，可用于通用的悬挂记录检查
+type TakeoverSignature struct {
+	Service      string   `json:"service"`
+	RecordType   string   `json:"recordType,omitempty"`   // CNAME(默认)/A/NS/MX
+	Patterns     []string `json:"patterns,omitempty"`     // 记录值后缀或关键字，A 记录为IP/CIDR
+	Fingerprints []string `json:"fingerprints,omitempty"` // HTTP响应体指纹，任一命中即确认
+	HttpStatus   int      `json:"httpStatus,omitempty"`   // 指纹命中时要求的状态码，0 不限制
+	NXDomain     bool     `json:"nxdomain,omitempty"`     // 记录值 NXDOMAIN 即确认
+	Refused      bool     `json:"refused,omitempty"`      // NS 记录指向的服务器拒绝解析该域名即确认
+	Severity     string   `json:"severity,omitempty"`
+	Description  string   `json:"description,omitempty"`
+}
+
+// Validate 校验签名
+func (s *TakeoverSignature) Validate() error {
+	if strings.TrimSpace(s.Service) == "" {
+		return fmt.Errorf("service is required")
+	}
+	switch s.recordType() {
+	case TakeoverRecordCNAME, TakeoverRecordA, TakeoverRecordNS, TakeoverRecordMX:
+	default:
+		return fmt.Errorf("unknown record type %q", s.RecordType)
+	}
+	if len(s.Fingerprints) == 0 && !s.NXDomain && !s.Refused {
+		return fmt.Errorf("signature %s has no confirmation check (fingerprints, nxdomain or refused)", s.Service)
+	}
+	if s.Refused && s.recordType() != TakeoverRecordNS {
+		return fmt.Errorf("refused check only applies to NS records")
+	}
+	if s.recordType() == TakeoverRecordA {
+		if s.NXDomain {
+			return fmt.Errorf("nxdomain check does not apply to A records")
+		}
+		for _, p := range s.Patterns {
+			if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+				return fmt.Errorf("invalid A record pattern %q, want IP or CIDR", p)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *TakeoverSignature) recordType() string {
+	if s.RecordType == "" {
+		return TakeoverRecordCNAME
+	}
+	return strings.ToUpper(s.RecordType)
+}
+
+// matchValue 记录值是否匹配签名
+func (s *TakeoverSignature) matchValue(value string) bool {
+	if len(s.Patterns) == 0 {
+		return true
+	}
+	value = strings.ToLower(strings.TrimSuffix(value, "."))
+	if s.recordType() == TakeoverRecordA {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return false
+		}
+		for _, p := range s.Patterns {
+			if _, ipNet, err := net.ParseCIDR(p); err == nil {
+				if ipNet.Contains(ip) {
+					return true
+				}
+			} else if pip := net.ParseIP(p); pip != nil && pip.Equal(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range s.Patterns {
+		if strings.Contains(value, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultTakeoverSignatures 内置接管签名，用于初始化签名库
+func DefaultTakeoverSignatures() []*TakeoverSignature {
+	cname := func(service string, patterns []string, fingerprints ...string) *TakeoverSignature {
+		return &TakeoverSignature{Service: service, RecordType: TakeoverRecordCNAME, Patterns: patterns, Fingerprints: fingerprints, Severity: "high"}
+	}
+	sigs := []*TakeoverSignature{
+		cname("github", []string{"github.io"}, "There isn't a GitHub Pages site here", "For root URLs (like http://example.com/) you must provide an index.html file"),
+		cname("heroku", []string{"herokuapp.com", "herokudns.com"}, "No such app", "no-such-app.herokuapp.com"),
+		cname("amazonaws", []string{"amazonaws.com"}, "NoSuchBucket", "The specified bucket does not exist"),
+		cname("bitbucket", []string{"bitbucket.io"}, "Repository not found"),
+		cname("ghost", []string{"ghost.io"}, "The thing you were looking for is no longer here"),
+		cname("tumblr", []string{"domains.tumblr.com"}, "There's nothing here.", "Whatever you were looking for doesn't currently exist at this address"),
+		cname("shopify", []string{"myshopify.com"}, "Sorry, this shop is currently unavailable", "Only one step left!"),
+		cname("wordpress", []string{"wordpress.com"}, "Do you want to register"),
+		cname("teamwork", []string{"teamwork.com"}, "Oops - We didn't find your site"),
+		cname("helpjuice", []string{"helpjuice.com"}, "We could not find what you're looking for"),
+		cname("helpscout", []string{"helpscoutdocs.com"}, "No settings were found for this company"),
+		cname("cargo", []string{"cargocollective.com"}, "If you're moving your domain away from Cargo"),
+		cname("statuspage", []string{"statuspage.io"}, "You are being redirected", "statuspage.io"),
+		cname("uservoice", []string{"uservoice.com"}, "This UserVoice subdomain is currently available"),
+		cname("surge", []string{"surge.sh"}, "project not found"),
+		cname("intercom", []string{"custom.intercom.help"}, "This page is reserved for artistic dogs", "Uh oh. That page doesn't exist"),
+		cname("webflow", []string{"proxy.webflow.com", "proxy-ssl.webflow.com"}, "The page you are looking for doesn't exist or has been moved"),
+		cname("kajabi", []string{"mykajabi.com"}, "The page you were looking for doesn't exist"),
+		cname("thinkific", []string{"thinkific.com"}, "You may have mistyped the address or the page may have moved"),
+		cname("tave", []string{"clientaccess.tave.com"}, "Sorry, this page is no longer available"),
+		cname("wishpond", []string{"wishpond.com"}, "https://www.wishpond.com/404?campaign=true"),
+		cname("aftership", []string{"aftership.com"}, "Oops.</h2><p class=\"text-muted text-tight\">The page you're looking for doesn't exist"),
+		cname("aha", []string{"ideas.aha.io"}, "There is no portal here ... sending you back to Aha!"),
+		cname("tictail", []string{"domains.tictail.com"}, "to target URL: <a href=\"https://tictail.com"),
+		cname("brightcove", []string{"bcvp0rtal.com", "brightcovegallery.com", "gallery.video"}, "<p class=\"bc-gallery-error-code\">Error Code: 404</p>"),
+		cname("bigcartel", []string{"bigcartel.com"}, "<h1>Oops! We couldn&#8217;t find that page.</h1>"),
+		cname("acquia", []string{"acquia-test.co"}, "The site you are looking for could not be found"),
+		cname("fastly", []string{"fastly.net"}, "Fastly error: unknown domain"),
+		cname("pantheon", []string{"pantheonsite.io"}, "The gods are wise, but do not know of the site which you seek"),
+		cname("zendesk", []string{"zendesk.com"}, "Help Center Closed", "Oops, this help center no longer exists"),
+		cname("desk", []string{"desk.com"}, "Sorry, We Couldn't Find That Page", "Please check the URL and try your request again"),
+		cname("unbounce", []string{"unbouncepages.com"}, "The requested URL was not found on this server", "The page you're looking for doesn't exist"),
+		cname("pingdom", []string{"stats.pingdom.com"}, "Sorry, couldn't find the status page"),
+		cname("tilda", []string{"tilda.ws"}, "Please renew your subscription"),
+		cname("smartling", []string{"smartling.com"}, "Domain is not configured"),
+		cname("campaignmonitor", []string{"createsend.com"}, "Trying to access your account?", "Double check the URL"),
+		cname("azure", []string{"azurewebsites.net"}, "404 Web Site not found"),
+		{Service: "azure-cloud", RecordType: TakeoverRecordCNAME, Severity: "high", NXDomain: true,
+			Patterns: []string{"cloudapp.net", "cloudapp.azure.com", "trafficmanager.net", "blob.core.windows.net", "azure-api.net", "azureedge.net"}},
+		{Service: "route53", RecordType: TakeoverRecordNS, Severity: "high", Refused: true, Patterns: []string{"awsdns"},
+			Description: "Delegated to Route 53 name servers that no longer host the zone"},
+		{Service: "azure-dns", RecordType: TakeoverRecordNS, Severity: "high", Refused: true, Patterns: []string{"azure-dns"}},
+		{Service: "digitalocean", RecordType: TakeoverRecordNS, Severity: "high", Refused: true, Patterns: []string{"digitalocean.com"}},
+		{Service: "dangling-cname", RecordType: TakeoverRecordCNAME, Severity: "medium", NXDomain: true,
+			Description: "CNAME target does not resolve"},
+		{Service: "dangling-ns", RecordType: TakeoverRecordNS, Severity: "high", NXDomain: true,
+			Description: "Name server host does not resolve, its domain may be registrable"},
+		{Service: "dangling-mx", RecordType: TakeoverRecordMX, Severity: "medium", NXDomain: true,
+			Description: "Mail exchanger host does not resolve"},
+	}
+	return sigs
+}
+
+// TakeoverOptions 子域接管检测选项
+type TakeoverOptions struct {
+	Signatures  []*TakeoverSignature `json:"signatures"`
+	Timeout     int                  `json:"timeout"`     // 单个请求超时(秒)
+	Concurrency int                  `json:"concurrency"` // 并发域名数
+}
+
+// Validate 验证 TakeoverOptions 配置是否有效
+// 实现 ScannerOptions 接口
+func (o *TakeoverOptions) Validate() error {
+	if o.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative, got %d", o.Timeout)
+	}
+	if o.Concurrency < 0 {
+		return fmt.Errorf("concurrency must be non-negative, got %d", o.Concurrency)
+	}
+	for _, sig := range o.Signatures {
+		if err := sig.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TakeoverFinding 确认的接管风险
+type TakeoverFinding struct {
+	Domain     string `json:"domain"`
+	Service    string `json:"service"`
+	RecordType string `json:"recordType"`
+	Record     string `json:"record"`   // 命中的记录值
+	Evidence   string `json:"evidence"` // 确认依据
+	Severity   string `json:"severity"`
+}
+
+// takeoverResolver 接管检测使用的DNS查询，*net.Resolver 满足此接口
+type takeoverResolver interface {
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// TakeoverScanner 子域接管扫描器
+// 按签名库检查域名的 CNAME/A/NS/MX 记录，确认的结果以漏洞形式输出
+type TakeoverScanner struct {
+	BaseScanner
+	resolver takeoverResolver
+	// fetch 获取域名的HTTP响应，先尝试HTTPS
+	fetch func(ctx context.Context, domain string, timeout time.Duration) (int, string, error)
+	// queryAt 向指定权威服务器查询域名
+	queryAt func(ctx context.Context, nameserver, domain string, timeout time.Duration) error
+}
+
+// NewTakeoverScanner 创建子域接管扫描器
+func NewTakeoverScanner() *TakeoverScanner {
+	return &TakeoverScanner{
+		BaseScanner: BaseScanner{name: "takeover"},
+		resolver:    net.DefaultResolver,
+		fetch:       fetchTakeoverPage,
+		queryAt:     queryNameserver,
+	}
+}
+
+// Scan 对资产和目标中的域名执行接管检测
+func (s *TakeoverScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	result := &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+	}
+
+	opts, _ := GetTypedOptions[*TakeoverOptions](config)
+	if opts == nil {
+		opts = &TakeoverOptions{}
+	}
+	signatures := opts.Signatures
+	if len(signatures) == 0 {
+		signatures = DefaultTakeoverSignatures()
+	}
+	// 指定了记录值模式的签名优先于通用的悬挂记录检查
+	signatures = append([]*TakeoverSignature(nil), signatures...)
+	sort.SliceStable(signatures, func(i, j int) bool {
+		return len(signatures[i].Patterns) > 0 && len(signatures[j].Patterns) == 0
+	})
+	timeout := time.Duration(opts.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 20
+	}
+
+	logf := func(level, format string, args ...interface{}) {
+		if config.TaskLogger != nil {
+			config.TaskLogger(level, format, args...)
+		}
+	}
+
+	domains, assetsByDomain := collectDomains(config)
+	if len(domains) == 0 {
+		logf("INFO", "Takeover: no domains to check")
+		return result, nil
+	}
+	logf("INFO", "Takeover: checking %d domains with %d signatures", len(domains), len(signatures))
+
+	findings, _ := ExecuteGeneric(ctx, concurrency, domains, func(ctx context.Context, domain string) ([]*TakeoverFinding, error) {
+		return s.checkDomain(ctx, domain, signatures, timeout), nil
+	})
+
+	for _, list := range findings {
+		for _, f := range list {
+			logf("WARN", "Takeover: %s is vulnerable! %s %s, service: %s", f.Domain, f.RecordType, f.Record, f.Service)
+			result.Vulnerabilities = append(result.Vulnerabilities, f.Vulnerability())
+			for _, asset := range assetsByDomain[f.Domain] {
+				asset.TakeoverRisk = true
+				asset.TakeoverService = f.Service
+				asset.TakeoverCName = f.Record
+				result.Assets = append(result.Assets, asset)
+			}
+			delete(assetsByDomain, f.Domain)
+		}
+	}
+	logf("INFO", "Takeover: %d findings", len(result.Vulnerabilities))
+	return result, ctx.Err()
+}
+
+// checkDomain 检查单个域名，每种记录类型最多输出一个结果
+func (s *TakeoverScanner) checkDomain(ctx context.Context, domain string, signatures []*TakeoverSignature, timeout time.Duration) []*TakeoverFinding {
+	var findings []*TakeoverFinding
+	nx := make(map[string]bool)
+	isNX := func(host string) bool {
+		if v, ok := nx[host]; ok {
+			return v
+		}
+		_, err := s.resolver.LookupHost(ctx, host)
+		nx[host] = isNotFound(err)
+		return nx[host]
+	}
+
+	// HTTP响应只获取一次
+	var fetched bool
+	var status int
+	var body string
+	page := func() (int, string) {
+		if !fetched {
+			fetched = true
+			status, body, _ = s.fetch(ctx, domain, timeout)
+		}
+		return status, body
+	}
+	matchPage := func(sig *TakeoverSignature) string {
+		if len(sig.Fingerprints) == 0 {
+			return ""
+		}
+		code, text := page()
+		if text == "" || (sig.HttpStatus != 0 && code != sig.HttpStatus) {
+			return ""
+		}
+		for _, fp := range sig.Fingerprints {
+			if strings.Contains(text, fp) {
+				return fp
+			}
+		}
+		return ""
+	}
+
+	// CNAME
+	if cname, err := s.resolver.LookupCNAME(ctx, domain); err == nil {
+		cname = strings.TrimSuffix(cname, ".")
+		if cname != "" && !strings.EqualFold(cname, domain) {
+			for _, sig := range signatures {
+				if sig.recordType() != TakeoverRecordCNAME || !sig.matchValue(cname) {
+					continue
+				}
+				evidence := ""
+				if sig.NXDomain && isNX(cname) {
+					evidence = "CNAME target is NXDOMAIN"
+				} else if fp := matchPage(sig); fp != "" {
+					evidence = "HTTP fingerprint: " + fp
+				}
+				if evidence != "" {
+					findings = append(findings, newTakeoverFinding(domain, sig, cname, evidence))
+					break
+				}
+			}
+		}
+	}
+
+	// A
+	if ips, err := s.resolver.LookupHost(ctx, domain); err == nil {
+	aLoop:
+		for _, sig := range signatures {
+			if sig.recordType() != TakeoverRecordA {
+				continue
+			}
+			for _, ip := range ips {
+				if !sig.matchValue(ip) {
+					continue
+				}
+				if fp := matchPage(sig); fp != "" {
+					findings = append(findings, newTakeoverFinding(domain, sig, ip, "HTTP fingerprint: "+fp))
+					break aLoop
+				}
+			}
+		}
+	}
+
+	// NS
+	if nss, err := s.resolver.LookupNS(ctx, domain); err == nil {
+	nsLoop:
+		for _, sig := range signatures {
+			if sig.recordType() != TakeoverRecordNS {
+				continue
+			}
+			for _, ns := range nss {
+				host := strings.TrimSuffix(ns.Host, ".")
+				if !sig.matchValue(host) {
+					continue
+				}
+				evidence := ""
+				if sig.NXDomain && isNX(host) {
+					evidence = "name server is NXDOMAIN"
+				} else if sig.Refused && s.queryAt(ctx, host, domain, timeout) != nil {
+					evidence = "name server refused to answer for the zone"
+				}
+				if evidence != "" {
+					findings = append(findings, newTakeoverFinding(domain, sig, host, evidence))
+					break nsLoop
+				}
+			}
+		}
+	}
+
+	// MX
+	if mxs, err := s.resolver.LookupMX(ctx, domain); err == nil {
+	mxLoop:
+		for _, sig := range signatures {
+			if sig.recordType() != TakeoverRecordMX {
+				continue
+			}
+			for _, mx := range mxs {
+				host := strings.TrimSuffix(mx.Host, ".")
+				if host == "" || !sig.matchValue(host) {
+					continue
+				}
+				if sig.NXDomain && isNX(host) {
+					findings = append(findings, newTakeoverFinding(domain, sig, host, "mail exchanger is NXDOMAIN"))
+					break mxLoop
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+func newTakeoverFinding(domain string, sig *TakeoverSignature, record, evidence string) *TakeoverFinding {
+	severity := strings.ToLower(sig.Severity)
+	if severity == "" {
+		severity = "high"
+	}
+	return &TakeoverFinding{
+		Domain:     domain,
+		Service:    sig.Service,
+		RecordType: sig.recordType(),
+		Record:     record,
+		Evidence:   evidence,
+		Severity:   severity,
+	}
+}
+
+// Vulnerability 转换为漏洞结果
+func (f *TakeoverFinding) Vulnerability() *Vulnerability {
+	return &Vulnerability{
+		Authority:        f.Domain,
+		Host:             f.Domain,
+		Url:              f.Domain,
+		PocFile:          "takeover-" + f.Service,
+		VulName:          fmt.Sprintf("Subdomain Takeover (%s)", f.Service),
+		Source:           "takeover",
+		Severity:         f.Severity,
+		Result:           fmt.Sprintf("%s %s -> %s: %s", f.Domain, f.RecordType, f.Record, f.Evidence),
+		Tags:             []string{"takeover", strings.ToLower(f.RecordType)},
+		MatcherName:      f.Service,
+		ExtractedResults: []string{f.Record},
+	}
+}
+
+// takeoverDomains 收集待检测的域名及其对应资产
+func collectDomains(config *ScanConfig) ([]string, map[string][]*Asset) {
+	var domains []string
+	assetsByDomain := make(map[string][]*Asset)
+	seen := make(map[string]bool)
+	add := func(host string, asset *Asset) {
+		host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+		if host == "" || net.ParseIP(host) != nil {
+			return
+		}
+		if !seen[host] {
+			seen[host] = true
+			domains = append(domains, host)
+		}
+		if asset != nil {
+			assetsByDomain[host] = append(assetsByDomain[host], asset)
+		}
+	}
+
+	for _, asset := range config.Assets {
+		add(asset.Host, asset)
+	}
+	parser := NewTargetParser()
+	for _, t := range parser.ParseMultiple(strings.Join(append([]string{config.Target}, config.Targets...), "\n")) {
+		if t.Type == TargetTypeDomain || t.Type == TargetTypeURL {
+			add(t.Host, nil)
+		}
+	}
+	return domains, assetsByDomain
+}
+
+// isNotFound 判断DNS查询结果是否为 NXDOMAIN
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// fetchTakeoverPage 获取域名首页，HTTPS失败时回退到HTTP
+func fetchTakeoverPage(ctx context.Context, domain string, timeout time.Duration) (int, string, error) {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	var lastErr error
+	for _, scheme := range []string{"https", "http"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+domain, nil)
+		if err != nil {
+			return 0, "", err
+		}
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 100*1024))
+		resp.Body.Close()
+		return resp.StatusCode, string(body), nil
+	}
+	return 0, "", lastErr
+}
+
+// queryNameserver 直接向权威服务器查询域名
+// 服务器返回 SERVFAIL/REFUSED 时返回错误，NXDOMAIN 或无记录视为正常应答
+func queryNameserver(ctx context.Context, nameserver, domain string, timeout time.Duration) error {
+	dialer := &net.Dialer{Timeout: timeout}
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, net.JoinHostPort(nameserver, "53"))
+		},
+	}
+	qctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, err := resolver.LookupHost(qctx, domain)
+	var dnsErr *net.DNSError
+	if err == nil || isNotFound(err) || (errors.As(err, &dnsErr) && dnsErr.IsTimeout) {
+		return nil
+	}
+	return err
+}
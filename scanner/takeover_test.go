@@ -0,0 +1,170 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeTakeoverResolver 固定应答的DNS，未配置的主机返回 NXDOMAIN
+type fakeTakeoverResolver struct {
+	cname map[string]string
+	hosts map[string][]string
+	ns    map[string][]string
+	mx    map[string][]string
+}
+
+func notFound(host string) error {
+	return &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r *fakeTakeoverResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	if c, ok := r.cname[host]; ok {
+		return c + ".", nil
+	}
+	if _, ok := r.hosts[host]; ok {
+		return host + ".", nil
+	}
+	return "", notFound(host)
+}
+
+func (r *fakeTakeoverResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if ips, ok := r.hosts[host]; ok {
+		return ips, nil
+	}
+	return nil, notFound(host)
+}
+
+func (r *fakeTakeoverResolver) LookupNS(_ context.Context, name string) ([]*net.NS, error) {
+	var list []*net.NS
+	for _, h := range r.ns[name] {
+		list = append(list, &net.NS{Host: h + "."})
+	}
+	if len(list) == 0 {
+		return nil, notFound(name)
+	}
+	return list, nil
+}
+
+func (r *fakeTakeoverResolver) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	var list []*net.MX
+	for _, h := range r.mx[name] {
+		list = append(list, &net.MX{Host: h + "."})
+	}
+	if len(list) == 0 {
+		return nil, notFound(name)
+	}
+	return list, nil
+}
+
+func TestTakeoverScannerScan(t *testing.T) {
+	s := NewTakeoverScanner()
+	s.resolver = &fakeTakeoverResolver{
+		cname: map[string]string{
+			"blog.example.com":  "example.github.io",
+			"shop.example.com":  "gone.cloudapp.net",
+			"safe.example.com":  "example.github.io",
+			"files.example.com": "bucket.s3.amazonaws.com",
+		},
+		hosts: map[string][]string{
+			"example.github.io":       {"185.199.108.153"},
+			"bucket.s3.amazonaws.com": {"52.216.0.1"},
+			"mail.example.com":        {"10.0.0.1"},
+			"dns.example.com":         {"10.0.0.2"},
+			"ns-1.awsdns-01.org":      {"205.251.192.1"},
+		},
+		ns: map[string][]string{
+			"dns.example.com": {"ns-1.awsdns-01.org"},
+		},
+		mx: map[string][]string{
+			"mail.example.com": {"mx.expired-provider.com"},
+		},
+	}
+	s.fetch = func(_ context.Context, domain string, _ time.Duration) (int, string, error) {
+		switch domain {
+		case "blog.example.com":
+			return 404, "There isn't a GitHub Pages site here.", nil
+		case "files.example.com":
+			return 200, "<html>ok</html>", nil
+		}
+		return 200, "welcome", nil
+	}
+	s.queryAt = func(_ context.Context, nameserver, domain string, _ time.Duration) error {
+		if nameserver == "ns-1.awsdns-01.org" && domain == "dns.example.com" {
+			return errors.New("server misbehaving")
+		}
+		return nil
+	}
+
+	result, err := s.Scan(context.Background(), &ScanConfig{
+		Target: "safe.example.com\nfiles.example.com\nmail.example.com\ndns.example.com\n10.0.0.1",
+		Assets: []*Asset{
+			{Authority: "blog.example.com:443", Host: "blog.example.com", Port: 443},
+			{Authority: "shop.example.com:80", Host: "shop.example.com", Port: 80},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	got := make(map[string]*Vulnerability)
+	for _, v := range result.Vulnerabilities {
+		got[v.Host] = v
+	}
+	want := map[string]string{
+		"blog.example.com": "takeover-github",
+		"shop.example.com": "takeover-azure-cloud",
+		"dns.example.com":  "takeover-route53",
+		"mail.example.com": "takeover-dangling-mx",
+	}
+	if len(got) != len(want) {
+		t.Errorf("vulnerabilities = %d, want %d: %v", len(got), len(want), got)
+	}
+	for host, poc := range want {
+		v, ok := got[host]
+		if !ok {
+			t.Errorf("%s: no vulnerability reported", host)
+			continue
+		}
+		if v.PocFile != poc || v.Source != "takeover" {
+			t.Errorf("%s: pocFile = %s source = %s, want %s", host, v.PocFile, v.Source, poc)
+		}
+	}
+
+	if len(result.Assets) != 2 {
+		t.Fatalf("assets = %d, want 2", len(result.Assets))
+	}
+	for _, a := range result.Assets {
+		if !a.TakeoverRisk || a.TakeoverService == "" || a.TakeoverCName == "" {
+			t.Errorf("asset %s not flagged: %+v", a.Host, a)
+		}
+	}
+}
+
+func TestTakeoverSignatureValidate(t *testing.T) {
+	tests := []struct {
+		sig     TakeoverSignature
+		wantErr bool
+	}{
+		{TakeoverSignature{Service: "github", Patterns: []string{"github.io"}, Fingerprints: []string{"x"}}, false},
+		{TakeoverSignature{Service: "dangling", RecordType: "mx", NXDomain: true}, false},
+		{TakeoverSignature{Service: "", NXDomain: true}, true},
+		{TakeoverSignature{Service: "none", Patterns: []string{"a.com"}}, true},
+		{TakeoverSignature{Service: "bad", RecordType: "TXT", NXDomain: true}, true},
+		{TakeoverSignature{Service: "cname-refused", Refused: true}, true},
+		{TakeoverSignature{Service: "ip", RecordType: "A", Patterns: []string{"10.0.0.0/8"}, Fingerprints: []string{"x"}}, false},
+		{TakeoverSignature{Service: "ip", RecordType: "A", Patterns: []string{"a.com"}, Fingerprints: []string{"x"}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.sig.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.sig, err, tt.wantErr)
+		}
+	}
+	for _, sig := range DefaultTakeoverSignatures() {
+		if err := sig.Validate(); err != nil {
+			t.Errorf("default signature %s: %v", sig.Service, err)
+		}
+	}
+}
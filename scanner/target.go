@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"cscan/pkg/utils"
 )
 
 // TargetType 目标类型
@@ -78,8 +80,8 @@ func (p *TargetParser) Parse(raw string) *Target {
 	}
 
 	// 单个IP或域名
-	target.Host = raw
-	target.Type = p.detectHostType(raw)
+	target.Host = utils.NormalizeHost(raw)
+	target.Type = p.detectHostType(target.Host)
 	return target
 }
 
@@ -120,7 +122,7 @@ func (t *Target) Expand() []string {
 	}
 	if t.Host != "" {
 		if t.Port > 0 {
-			return []string{fmt.Sprintf("%s:%d", t.Host, t.Port)}
+			return []string{utils.BuildTargetWithPort(t.Host, t.Port)}
 		}
 		return []string{t.Host}
 	}
@@ -149,7 +151,7 @@ func (p *TargetParser) parseURL(raw string) *Target {
 		target.Host = host
 		target.Port = port
 	} else {
-		target.Host = raw
+		target.Host = utils.NormalizeHost(raw)
 		if target.Protocol == "https" {
 			target.Port = 443
 		} else {
@@ -172,6 +174,12 @@ func (p *TargetParser) parseCIDR(raw string) *Target {
 		return target
 	}
 
+	// IPv6前缀按抽样策略展开
+	if ipnet.IP.To4() == nil {
+		target.IPs, _ = utils.ExpandIPv6Prefix(ipnet)
+		return target
+	}
+
 	// 展开CIDR
 	var ips []string
 	for ip := ipnet.IP.Mask(ipnet.Mask); ipnet.Contains(ip); incIPLocal(ip) {
@@ -224,15 +232,12 @@ func (p *TargetParser) parseIPRange(raw string) *Target {
 		return target
 	}
 
-	// 展开IP范围
-	var ips []string
-	ip := make(net.IP, len(startIP))
-	copy(ip, startIP)
-	for ; !ip.Equal(endIP); incIPLocal(ip) {
-		ips = append(ips, ip.String())
+	// 展开IP范围，地址族不一致、起始大于结束或IPv6范围过大时当作普通目标
+	ips, err := utils.ExpandIPRange(startIP, endIP, 0)
+	if err != nil {
+		target.Host = raw
+		return target
 	}
-	ips = append(ips, endIP.String())
-
 	target.IPs = ips
 	return target
 }
@@ -251,6 +256,11 @@ func (p *TargetParser) parseHostPort(raw string) (host string, port int, ok bool
 		return "", 0, false
 	}
 
+	// 不带方括号的IPv6地址没有端口
+	if strings.Count(raw, ":") > 1 {
+		return "", 0, false
+	}
+
 	// IPv4/域名格式: host:port
 	if idx := strings.LastIndex(raw, ":"); idx > 0 {
 		host = raw[:idx]
@@ -0,0 +1,392 @@
+package scanner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"cscan/pkg/utils"
+)
+
+// CertDetail 单张证书信息
+type CertDetail struct {
+	Subject            string    `json:"subject"`
+	CommonName         string    `json:"commonName"`
+	SANs               []string  `json:"sans,omitempty"`
+	Issuer             string    `json:"issuer"`
+	IssuerCommonName   string    `json:"issuerCommonName"`
+	SerialNumber       string    `json:"serialNumber"`
+	NotBefore          time.Time `json:"notBefore"`
+	NotAfter           time.Time `json:"notAfter"`
+	KeyType            string    `json:"keyType"` // RSA/ECDSA/Ed25519
+	KeyBits            int       `json:"keyBits"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm"`
+	SHA256             string    `json:"sha256"`     // 证书 DER 的 SHA-256
+	SPKISHA256         string    `json:"spkiSha256"` // 公钥的 SHA-256
+	IsCA               bool      `json:"isCa"`
+}
+
+// CertInfo TLS服务证书信息，叶子证书字段在顶层，Chain 为服务端发送的完整证书链（含叶子证书）
+type CertInfo struct {
+	CertDetail
+	Chain              []CertDetail `json:"chain"`
+	TLSVersion         string       `json:"tlsVersion"`                   // 协商的TLS版本
+	CipherSuite        string       `json:"cipherSuite"`                  // 协商的加密套件
+	SelfSigned         bool         `json:"selfSigned"`                   // 叶子证书自签名
+	HostnameMatch      bool         `json:"hostnameMatch"`                // 证书与访问的域名匹配
+	DeprecatedVersions []string     `json:"deprecatedVersions,omitempty"` // 服务端接受的已废弃协议版本
+	WeakCiphers        []string     `json:"weakCiphers,omitempty"`        // 服务端接受的弱加密套件
+}
+
+// Summary 证书的可读摘要，写入 Asset.Cert
+func (c *CertInfo) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Subject: %s\n", c.Subject)
+	fmt.Fprintf(&b, "Issuer: %s\n", c.Issuer)
+	if len(c.SANs) > 0 {
+		fmt.Fprintf(&b, "SANs: %s\n", strings.Join(c.SANs, ", "))
+	}
+	fmt.Fprintf(&b, "Validity: %s ~ %s\n", c.NotBefore.Format("2006-01-02"), c.NotAfter.Format("2006-01-02"))
+	fmt.Fprintf(&b, "Key: %s %d, %s\n", c.KeyType, c.KeyBits, c.SignatureAlgorithm)
+	fmt.Fprintf(&b, "Protocol: %s %s\n", c.TLSVersion, c.CipherSuite)
+	fmt.Fprintf(&b, "SHA256: %s", c.SHA256)
+	return b.String()
+}
+
+// TLSCertOptions TLS证书采集选项
+type TLSCertOptions struct {
+	Timeout     int  `json:"timeout"`     // 单次握手超时(秒)，默认5
+	Concurrency int  `json:"concurrency"` // 并发数，默认10
+	ExpiryDays  int  `json:"expiryDays"`  // 证书剩余有效期小于该天数时告警，默认30
+	SkipLegacy  bool `json:"skipLegacy"`  // 跳过旧版协议和弱加密套件探测
+}
+
+// Validate 验证 TLSCertOptions 配置是否有效
+// 实现 ScannerOptions 接口
+func (o *TLSCertOptions) Validate() error {
+	if o.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative, got %d", o.Timeout)
+	}
+	if o.Concurrency < 0 {
+		return fmt.Errorf("concurrency must be non-negative, got %d", o.Concurrency)
+	}
+	if o.ExpiryDays < 0 {
+		return fmt.Errorf("expiryDays must be non-negative, got %d", o.ExpiryDays)
+	}
+	return nil
+}
+
+// tlsPlaintextServices 确定不使用TLS的服务，跳过握手
+var tlsPlaintextServices = map[string]bool{
+	"http": true, "ssh": true, "telnet": true, "ftp": true, "smtp": true, "dns": true, "domain": true,
+	"mysql": true, "redis": true, "mongodb": true, "memcached": true, "vnc": true, "rdp": true,
+	"ms-wbt-server": true, "netbios-ssn": true, "microsoft-ds": true, "smb": true, "snmp": true,
+}
+
+// TLSCertScanner TLS证书采集与分析
+// 对所有非明文服务尝试TLS握手，采集证书链并输出证书问题
+type TLSCertScanner struct {
+	BaseScanner
+	// handshake 执行TLS握手并返回连接状态
+	handshake func(ctx context.Context, addr string, cfg *tls.Config, timeout time.Duration) (*tls.ConnectionState, error)
+	now       func() time.Time
+}
+
+// NewTLSCertScanner 创建TLS证书扫描器
+func NewTLSCertScanner() *TLSCertScanner {
+	return &TLSCertScanner{
+		BaseScanner: BaseScanner{name: "tlscert"},
+		handshake:   tlsHandshake,
+		now:         time.Now,
+	}
+}
+
+// Scan 采集资产的TLS证书，证书写入资产的 Cert/CertInfo 字段
+// 返回的 Assets 为采集到证书的资产，Vulnerabilities 为证书问题
+func (s *TLSCertScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	result := &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+	}
+
+	opts, _ := GetTypedOptions[*TLSCertOptions](config)
+	if opts == nil {
+		opts = &TLSCertOptions{}
+	}
+	timeout := time.Duration(opts.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	expiryDays := opts.ExpiryDays
+	if expiryDays <= 0 {
+		expiryDays = 30
+	}
+
+	var targets []*Asset
+	for _, asset := range config.Assets {
+		if asset.Port <= 0 || asset.Host == "" || tlsPlaintextServices[strings.ToLower(asset.Service)] {
+			continue
+		}
+		targets = append(targets, asset)
+	}
+	if len(targets) == 0 {
+		return result, nil
+	}
+
+	type certResult struct {
+		asset *Asset
+		vuls  []*Vulnerability
+	}
+	results, _ := ExecuteGeneric(ctx, concurrency, targets, func(ctx context.Context, asset *Asset) (*certResult, error) {
+		info, err := s.collect(ctx, asset.Host, asset.Port, timeout, opts.SkipLegacy)
+		if err != nil {
+			return nil, err
+		}
+		asset.CertInfo = info
+		asset.Cert = info.Summary()
+		return &certResult{asset: asset, vuls: CertFindings(asset, info, s.now(), expiryDays)}, nil
+	})
+
+	for _, r := range results {
+		result.Assets = append(result.Assets, r.asset)
+		result.Vulnerabilities = append(result.Vulnerabilities, r.vuls...)
+	}
+	if config.TaskLogger != nil {
+		config.TaskLogger("INFO", "TLS cert: %d/%d services presented certificates, %d findings",
+			len(result.Assets), len(targets), len(result.Vulnerabilities))
+	}
+	return result, ctx.Err()
+}
+
+// collect 握手采集证书链，并探测旧版协议和弱加密套件
+func (s *TLSCertScanner) collect(ctx context.Context, host string, port int, timeout time.Duration, skipLegacy bool) (*CertInfo, error) {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	serverName := ""
+	if net.ParseIP(host) == nil {
+		serverName = host
+	}
+
+	state, err := s.handshake(ctx, addr, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+	}, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("%s: no peer certificate", addr)
+	}
+
+	leaf := state.PeerCertificates[0]
+	info := &CertInfo{
+		CertDetail:  certDetail(leaf),
+		TLSVersion:  tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		SelfSigned:  isSelfSigned(leaf),
+	}
+	for _, cert := range state.PeerCertificates {
+		info.Chain = append(info.Chain, certDetail(cert))
+	}
+	info.HostnameMatch = leaf.VerifyHostname(host) == nil
+
+	if skipLegacy {
+		return info, nil
+	}
+	// 已废弃的协议版本
+	for _, v := range []uint16{tls.VersionTLS10, tls.VersionTLS11} {
+		if _, err := s.handshake(ctx, addr, &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true,
+			MinVersion:         v,
+			MaxVersion:         v,
+		}, timeout); err == nil {
+			info.DeprecatedVersions = append(info.DeprecatedVersions, tls.VersionName(v))
+		}
+	}
+	// 弱加密套件（仅 TLS 1.2 及以下可协商）
+	var weak []uint16
+	for _, cs := range tls.InsecureCipherSuites() {
+		weak = append(weak, cs.ID)
+	}
+	if st, err := s.handshake(ctx, addr, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+		MaxVersion:         tls.VersionTLS12,
+		CipherSuites:       weak,
+	}, timeout); err == nil {
+		info.WeakCiphers = append(info.WeakCiphers, tls.CipherSuiteName(st.CipherSuite))
+	}
+	return info, nil
+}
+
+// CertFindings 根据证书信息生成证书问题
+func CertFindings(asset *Asset, info *CertInfo, now time.Time, expiryDays int) []*Vulnerability {
+	var vuls []*Vulnerability
+	add := func(poc, name, severity, detail string) {
+		vuls = append(vuls, &Vulnerability{
+			Authority:        asset.Authority,
+			Host:             asset.Host,
+			Port:             asset.Port,
+			Url:              asset.Authority,
+			PocFile:          poc,
+			Source:           "tlscert",
+			Severity:         severity,
+			VulName:          name,
+			Result:           detail,
+			Tags:             []string{"tls", "ssl"},
+			MatcherName:      poc,
+			ExtractedResults: []string{info.SHA256},
+		})
+	}
+
+	switch {
+	case now.After(info.NotAfter):
+		add("tls-cert-expired", "TLS Certificate Expired", "medium",
+			fmt.Sprintf("certificate %s expired at %s", info.CommonName, info.NotAfter.Format(time.RFC3339)))
+	case info.NotAfter.Sub(now) < time.Duration(expiryDays)*24*time.Hour:
+		add("tls-cert-expiring", "TLS Certificate Expiring Soon", "low",
+			fmt.Sprintf("certificate %s expires at %s (within %d days)", info.CommonName, info.NotAfter.Format(time.RFC3339), expiryDays))
+	}
+	if info.SelfSigned {
+		add("tls-cert-self-signed", "Self-Signed TLS Certificate", "low",
+			fmt.Sprintf("certificate %s is self-signed", info.Subject))
+	}
+	if weakKey(info.KeyType, info.KeyBits) {
+		add("tls-cert-weak-key", "Weak TLS Certificate Key", "medium",
+			fmt.Sprintf("certificate key is %s %d bits", info.KeyType, info.KeyBits))
+	}
+	// 只对域名检查主机名匹配，IP访问的服务证书通常不包含IP
+	if !info.HostnameMatch && net.ParseIP(asset.Host) == nil {
+		add("tls-cert-hostname-mismatch", "TLS Certificate Hostname Mismatch", "low",
+			fmt.Sprintf("certificate for %s (SANs: %s) does not match %s", info.CommonName, strings.Join(info.SANs, ", "), asset.Host))
+	}
+	if len(info.DeprecatedVersions) > 0 {
+		add("tls-deprecated-version", "Deprecated TLS Version Supported", "low",
+			"server accepts "+strings.Join(info.DeprecatedVersions, ", "))
+	}
+	if len(info.WeakCiphers) > 0 {
+		add("tls-weak-cipher", "Weak TLS Cipher Suite Supported", "medium",
+			"server accepts "+strings.Join(info.WeakCiphers, ", "))
+	}
+	return vuls
+}
+
+// CertCandidateDomains 从证书 SAN 中提取候选域名
+// 域名资产只取与其同根域的 SAN，IP 资产取全部 SAN；通配符取其父域名
+func CertCandidateDomains(assets []*Asset) []string {
+	known := make(map[string]bool)
+	for _, asset := range assets {
+		known[strings.ToLower(asset.Host)] = true
+	}
+
+	seen := make(map[string]bool)
+	var domains []string
+	for _, asset := range assets {
+		if asset.CertInfo == nil {
+			continue
+		}
+		root := ""
+		if net.ParseIP(asset.Host) == nil {
+			root = utils.GetRootDomain(asset.Host)
+		}
+		for _, san := range asset.CertInfo.SANs {
+			d := strings.ToLower(strings.TrimPrefix(san, "*."))
+			if d == "" || net.ParseIP(d) != nil || !strings.Contains(d, ".") || known[d] || seen[d] {
+				continue
+			}
+			if root != "" && utils.GetRootDomain(d) != root {
+				continue
+			}
+			seen[d] = true
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+func certDetail(cert *x509.Certificate) CertDetail {
+	der := sha256.Sum256(cert.Raw)
+	spki := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	keyType, keyBits := publicKeyInfo(cert.PublicKey)
+
+	sans := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return CertDetail{
+		Subject:            cert.Subject.String(),
+		CommonName:         cert.Subject.CommonName,
+		SANs:               sans,
+		Issuer:             cert.Issuer.String(),
+		IssuerCommonName:   cert.Issuer.CommonName,
+		SerialNumber:       cert.SerialNumber.Text(16),
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		KeyType:            keyType,
+		KeyBits:            keyBits,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		SHA256:             hex.EncodeToString(der[:]),
+		SPKISHA256:         hex.EncodeToString(spki[:]),
+		IsCA:               cert.IsCA,
+	}
+}
+
+func publicKeyInfo(key interface{}) (string, int) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return "RSA", k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", k.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	default:
+		return "unknown", 0
+	}
+}
+
+// weakKey RSA 小于2048位、ECDSA 小于256位视为弱密钥
+func weakKey(keyType string, bits int) bool {
+	switch keyType {
+	case "RSA":
+		return bits < 2048
+	case "ECDSA":
+		return bits < 256
+	}
+	return false
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	if cert.Subject.String() != cert.Issuer.String() {
+		return false
+	}
+	return cert.CheckSignatureFrom(cert) == nil
+}
+
+func tlsHandshake(ctx context.Context, addr string, cfg *tls.Config, timeout time.Duration) (*tls.ConnectionState, error) {
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: timeout}, Config: cfg}
+	hctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	conn, err := dialer.DialContext(hctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	state := conn.(*tls.Conn).ConnectionState()
+	return &state, nil
+}
@@ -0,0 +1,114 @@
+package scanner
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTLSCertScannerScan(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	host, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	asset := &Asset{Authority: srv.Listener.Addr().String(), Host: host, Port: port, Service: "https"}
+	skipped := &Asset{Authority: "127.0.0.1:22", Host: "127.0.0.1", Port: 22, Service: "ssh"}
+
+	s := NewTLSCertScanner()
+	result, err := s.Scan(context.Background(), &ScanConfig{
+		Assets:  []*Asset{asset, skipped},
+		Options: &TLSCertOptions{Timeout: 3},
+	})
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(result.Assets) != 1 || asset.CertInfo == nil {
+		t.Fatalf("assets = %d, certInfo = %v", len(result.Assets), asset.CertInfo)
+	}
+	if skipped.CertInfo != nil {
+		t.Errorf("plaintext service should be skipped")
+	}
+
+	info := asset.CertInfo
+	if len(info.Chain) == 0 || info.SHA256 == "" || info.KeyType == "" || info.KeyBits == 0 || info.TLSVersion == "" {
+		t.Errorf("incomplete cert info: %+v", info)
+	}
+	if asset.Cert == "" {
+		t.Errorf("asset.Cert summary not set")
+	}
+	// httptest 使用自签名证书
+	var selfSigned bool
+	for _, v := range result.Vulnerabilities {
+		if v.PocFile == "tls-cert-self-signed" {
+			selfSigned = true
+		}
+		if v.Source != "tlscert" || v.Authority != asset.Authority {
+			t.Errorf("unexpected vulnerability: %+v", v)
+		}
+	}
+	if !selfSigned {
+		t.Errorf("self-signed certificate not reported: %+v", result.Vulnerabilities)
+	}
+}
+
+func TestCertFindings(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	asset := &Asset{Authority: "a.example.com:443", Host: "a.example.com", Port: 443}
+	info := &CertInfo{
+		CertDetail: CertDetail{
+			CommonName: "b.example.com",
+			SANs:       []string{"b.example.com"},
+			NotAfter:   now.Add(10 * 24 * time.Hour),
+			KeyType:    "RSA",
+			KeyBits:    1024,
+		},
+		HostnameMatch:      false,
+		DeprecatedVersions: []string{"TLS 1.0"},
+	}
+
+	got := make(map[string]bool)
+	for _, v := range CertFindings(asset, info, now, 30) {
+		got[v.PocFile] = true
+	}
+	for _, want := range []string{"tls-cert-expiring", "tls-cert-weak-key", "tls-cert-hostname-mismatch", "tls-deprecated-version"} {
+		if !got[want] {
+			t.Errorf("missing finding %s, got %v", want, got)
+		}
+	}
+	if got["tls-cert-expired"] || got["tls-cert-self-signed"] || got["tls-weak-cipher"] {
+		t.Errorf("unexpected findings: %v", got)
+	}
+
+	info.NotAfter = now.Add(-time.Hour)
+	findings := CertFindings(asset, info, now, 30)
+	if findings[0].PocFile != "tls-cert-expired" {
+		t.Errorf("expired certificate not reported first: %s", findings[0].PocFile)
+	}
+}
+
+func TestCertCandidateDomains(t *testing.T) {
+	assets := []*Asset{
+		{Host: "www.example.com", Port: 443, CertInfo: &CertInfo{CertDetail: CertDetail{
+			SANs: []string{"www.example.com", "*.api.example.com", "cdn.other.net", "10.0.0.1"},
+		}}},
+		{Host: "10.0.0.2", Port: 8443, CertInfo: &CertInfo{CertDetail: CertDetail{
+			SANs: []string{"internal.corp.local", "*.example.com"},
+		}}},
+		{Host: "mail.example.com", Port: 25},
+	}
+	got := CertCandidateDomains(assets)
+	want := []string{"api.example.com", "internal.corp.local", "example.com"}
+	if len(got) != len(want) {
+		t.Fatalf("CertCandidateDomains() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CertCandidateDomains()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
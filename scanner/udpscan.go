@@ -0,0 +1,572 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cscan/pkg/utils"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultUDPPorts 常见UDP服务端口，未指定端口时使用
+const DefaultUDPPorts = "53,69,123,137,161,500,1900,5353,11211"
+
+// TransportUDP UDP资产的传输层协议标记
+const TransportUDP = "udp"
+
+// UDPScanOptions UDP端口扫描选项
+type UDPScanOptions struct {
+	Ports       string `json:"ports"`       // 扫描端口，为空时使用 DefaultUDPPorts
+	Rate        int    `json:"rate"`        // 全局每秒最多发包数
+	Retries     int    `json:"retries"`     // 无响应时的重发次数
+	Timeout     int    `json:"timeout"`     // 每次探测等待响应的超时(秒)
+	Concurrency int    `json:"concurrency"` // 并发探测数
+}
+
+// Validate 验证选项
+func (o *UDPScanOptions) Validate() error {
+	if o.Rate < 0 || o.Retries < 0 || o.Timeout < 0 || o.Concurrency < 0 {
+		return fmt.Errorf("udp scan options must not be negative")
+	}
+	if o.Ports != "" && len(parsePorts(o.Ports)) == 0 {
+		return fmt.Errorf("invalid udp ports: %s", o.Ports)
+	}
+	return nil
+}
+
+// udpProbe 协议探测报文及响应解析
+type udpProbe struct {
+	service string
+	// payload 生成探测报文，每次重发都重新生成（随机事务ID）
+	payload func() []byte
+	// parse 校验响应是否属于该协议，返回banner摘要
+	parse func(req, resp []byte) (string, bool)
+	// newPort 服务从新的端口回复（如TFTP），需使用非连接套接字接收
+	newPort bool
+}
+
+// udpProbes 按端口选择的协议探测，未列出的端口发送空报文
+var udpProbes = map[int]*udpProbe{
+	53:    {service: "dns", payload: dnsVersionProbe, parse: parseDNSResponse},
+	69:    {service: "tftp", payload: tftpProbe, parse: parseTFTPResponse, newPort: true},
+	123:   {service: "ntp", payload: ntpProbe, parse: parseNTPResponse},
+	137:   {service: "netbios-ns", payload: netbiosProbe, parse: parseNetBIOSResponse},
+	161:   {service: "snmp", payload: snmpProbe, parse: parseSNMPResponse},
+	500:   {service: "isakmp", payload: ikeProbe, parse: parseIKEResponse},
+	1900:  {service: "ssdp", payload: ssdpProbe, parse: parseSSDPResponse},
+	5353:  {service: "mdns", payload: mdnsProbe, parse: parseMDNSResponse},
+	11211: {service: "memcached", payload: memcachedProbe, parse: parseMemcachedResponse},
+}
+
+// udpGenericProbe 未知端口的探测，收到任何响应即视为开放
+var udpGenericProbe = &udpProbe{payload: func() []byte { return nil }}
+
+// UDPScanner UDP端口扫描器
+// UDP无连接，端口开放只能通过服务响应确认：按端口发送协议探测报文，
+// 收到响应判定开放，收到ICMP端口不可达判定关闭，超时重发直到次数用尽
+type UDPScanner struct {
+	BaseScanner
+	probes map[int]*udpProbe
+}
+
+// NewUDPScanner 创建UDP端口扫描器
+func NewUDPScanner() *UDPScanner {
+	return &UDPScanner{
+		BaseScanner: BaseScanner{name: "udpscan"},
+		probes:      udpProbes,
+	}
+}
+
+// Scan 执行UDP端口扫描
+func (s *UDPScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	result := &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+		Assets:      []*Asset{},
+	}
+
+	opts, _ := GetTypedOptions[*UDPScanOptions](config)
+	if opts == nil {
+		opts = &UDPScanOptions{}
+	}
+	ports := parsePorts(opts.Ports)
+	if opts.Ports == "" {
+		ports = parsePorts(DefaultUDPPorts)
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 50
+	}
+	timeout := time.Duration(opts.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	retries := opts.Retries
+	if retries <= 0 {
+		retries = 2
+	}
+	limit := rate.Limit(opts.Rate)
+	if opts.Rate <= 0 {
+		limit = 100
+	}
+	limiter := rate.NewLimiter(limit, 1)
+
+	logf := func(level, format string, args ...interface{}) {
+		if config.TaskLogger != nil {
+			config.TaskLogger(level, format, args...)
+		}
+	}
+
+	targets := parseTargets(config.Target)
+	targets = append(targets, config.Targets...)
+	if len(targets) == 0 || len(ports) == 0 {
+		return result, nil
+	}
+	logf("INFO", "UDP scan: %d targets, %d ports, rate=%v/s, retries=%d", len(targets), len(ports), limit, retries)
+
+	type udpJob struct {
+		host string
+		port int
+	}
+	jobs := make(chan udpJob)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				probe := s.probes[job.port]
+				if probe == nil {
+					probe = udpGenericProbe
+				}
+				asset := probeUDP(ctx, job.host, job.port, probe, limiter, retries, timeout)
+				if asset == nil {
+					continue
+				}
+				logf("INFO", "发现UDP端口: %s:%d %s %s", job.host, job.port, asset.Service, asset.Banner)
+				mu.Lock()
+				result.Assets = append(result.Assets, asset)
+				mu.Unlock()
+				if config.OnResult != nil {
+					config.OnResult(asset)
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, host := range targets {
+		for _, port := range ports {
+			select {
+			case <-ctx.Done():
+				break feed
+			case jobs <- udpJob{host: host, port: port}:
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	logf("INFO", "UDP scan completed: %d open ports", len(result.Assets))
+	return result, nil
+}
+
+// probeUDP 探测单个UDP端口，开放时返回资产
+func probeUDP(ctx context.Context, host string, port int, probe *udpProbe, limiter *rate.Limiter, retries int, timeout time.Duration) *Asset {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	raddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil
+	}
+
+	// 连接套接字可以收到ICMP端口不可达(ECONNREFUSED)，但只接受原端口的回复
+	var conn *net.UDPConn
+	if probe.newPort {
+		conn, err = net.ListenUDP("udp", nil)
+	} else {
+		conn, err = net.DialUDP("udp", nil, raddr)
+	}
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 8192)
+	for attempt := 0; attempt <= retries; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil
+		}
+		req := probe.payload()
+		if probe.newPort {
+			_, err = conn.WriteToUDP(req, raddr)
+		} else {
+			_, err = conn.Write(req)
+		}
+		if err != nil {
+			return nil
+		}
+
+		resp, err := readUDPReply(ctx, conn, raddr, probe.newPort, buf, timeout)
+		if err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			// ECONNREFUSED 或其他错误，端口关闭
+			return nil
+		}
+
+		asset := &Asset{
+			Authority: utils.BuildTargetWithPort(host, port),
+			Host:      host,
+			Port:      port,
+			Transport: TransportUDP,
+			Category:  getCategory(host),
+			Source:    "udpscan",
+		}
+		asset.Banner = printableSummary(resp, 64)
+		if probe.parse != nil {
+			if banner, ok := probe.parse(req, resp); ok {
+				asset.Service = probe.service
+				asset.Banner = banner
+			}
+		}
+		return asset
+	}
+	return nil
+}
+
+// readUDPReply 在超时内读取目标的回复，非连接套接字丢弃其他主机的报文
+func readUDPReply(ctx context.Context, conn *net.UDPConn, raddr *net.UDPAddr, anyPort bool, buf []byte, timeout time.Duration) ([]byte, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetReadDeadline(deadline)
+	for {
+		if !anyPort {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return nil, err
+			}
+			return buf[:n], nil
+		}
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return nil, err
+		}
+		if from.IP.Equal(raddr.IP) {
+			return buf[:n], nil
+		}
+	}
+}
+
+// printableSummary 截取响应前max字节，不可打印字符替换为'.'
+func printableSummary(data []byte, max int) string {
+	if len(data) > max {
+		data = data[:max]
+	}
+	out := make([]byte, len(data))
+	for i, b := range data {
+		if b < 0x20 || b > 0x7e {
+			b = '.'
+		}
+		out[i] = b
+	}
+	return string(out)
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+// ==================== DNS / mDNS ====================
+
+// dnsQuery 构造单个问题的DNS查询
+func dnsQuery(id []byte, flags uint16, name string, qtype, qclass uint16) []byte {
+	msg := make([]byte, 12, 64)
+	copy(msg, id)
+	binary.BigEndian.PutUint16(msg[2:], flags)
+	binary.BigEndian.PutUint16(msg[4:], 1)
+	for _, label := range strings.Split(strings.Trim(name, "."), ".") {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	msg = binary.BigEndian.AppendUint16(msg, qclass)
+	return msg
+}
+
+// dnsVersionProbe 查询 version.bind CHAOS TXT，可获取部分DNS服务器版本
+func dnsVersionProbe() []byte {
+	return dnsQuery(randomBytes(2), 0x0100, "version.bind", 16, 3)
+}
+
+// skipDNSName 跳过报文中的域名（支持压缩指针），返回之后的偏移
+func skipDNSName(msg []byte, off int) int {
+	for off < len(msg) {
+		l := int(msg[off])
+		switch {
+		case l == 0:
+			return off + 1
+		case l&0xc0 == 0xc0:
+			return off + 2
+		default:
+			off += l + 1
+		}
+	}
+	return -1
+}
+
+func parseDNSResponse(req, resp []byte) (string, bool) {
+	if len(resp) < 12 || !bytes.Equal(resp[:2], req[:2]) || resp[2]&0x80 == 0 {
+		return "", false
+	}
+	rcode := resp[3] & 0x0f
+	if binary.BigEndian.Uint16(resp[6:]) == 0 {
+		return fmt.Sprintf("rcode=%d", rcode), true
+	}
+	// 跳过问题区，读取第一条TXT回答
+	off := skipDNSName(resp, 12)
+	if off < 0 {
+		return "", true
+	}
+	off = skipDNSName(resp, off+4)
+	if off < 0 || off+10 > len(resp) {
+		return "", true
+	}
+	rdlen := int(binary.BigEndian.Uint16(resp[off+8:]))
+	rdata := resp[off+10:]
+	if rdlen > len(rdata) || rdlen == 0 {
+		return "", true
+	}
+	rdata = rdata[:rdlen]
+	if txtLen := int(rdata[0]); txtLen < len(rdata) {
+		return "version: " + printableSummary(rdata[1:1+txtLen], 128), true
+	}
+	return "", true
+}
+
+// mdnsProbe 查询DNS-SD服务列表，设置QU位请求单播回复
+func mdnsProbe() []byte {
+	return dnsQuery([]byte{0, 0}, 0, "_services._dns-sd._udp.local", 12, 0x8001)
+}
+
+func parseMDNSResponse(_, resp []byte) (string, bool) {
+	if len(resp) < 12 || resp[2]&0x80 == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("answers=%d", binary.BigEndian.Uint16(resp[6:])), true
+}
+
+// ==================== TFTP ====================
+
+// tftpProbe 读取一个随机文件名，服务会返回错误或数据
+func tftpProbe() []byte {
+	msg := []byte{0, 1}
+	msg = append(msg, hex.EncodeToString(randomBytes(4))...)
+	msg = append(msg, 0)
+	msg = append(msg, "octet"...)
+	return append(msg, 0)
+}
+
+func parseTFTPResponse(_, resp []byte) (string, bool) {
+	if len(resp) < 4 || resp[0] != 0 {
+		return "", false
+	}
+	switch resp[1] {
+	case 3, 6: // DATA / OACK
+		return "", true
+	case 5: // ERROR
+		msg, _, _ := bytes.Cut(resp[4:], []byte{0})
+		return "error: " + printableSummary(msg, 64), true
+	}
+	return "", false
+}
+
+// ==================== NTP ====================
+
+// ntpProbe NTPv3 客户端请求
+func ntpProbe() []byte {
+	msg := make([]byte, 48)
+	msg[0] = 0x1b
+	return msg
+}
+
+func parseNTPResponse(_, resp []byte) (string, bool) {
+	if len(resp) < 48 || resp[0]&0x07 != 4 {
+		return "", false
+	}
+	return fmt.Sprintf("NTP v%d, stratum %d", resp[0]>>3&0x07, resp[1]), true
+}
+
+// ==================== NetBIOS ====================
+
+// netbiosProbe NBSTAT 查询通配名称 "*"，返回主机的NetBIOS名称表
+func netbiosProbe() []byte {
+	msg := make([]byte, 12, 50)
+	copy(msg, randomBytes(2))
+	binary.BigEndian.PutUint16(msg[4:], 1)
+	msg = append(msg, 0x20, 'C', 'K')
+	msg = append(msg, bytes.Repeat([]byte{'A'}, 30)...)
+	return append(msg, 0, 0, 0x21, 0, 1)
+}
+
+func parseNetBIOSResponse(req, resp []byte) (string, bool) {
+	if len(resp) < 12 || !bytes.Equal(resp[:2], req[:2]) || resp[2]&0x80 == 0 {
+		return "", false
+	}
+	// 头部12 + 名称34 + type/class/ttl/rdlength 10
+	const namesOff = 57
+	if len(resp) < namesOff {
+		return "", true
+	}
+	var host, group string
+	for i, n := 0, int(resp[namesOff-1]); i < n; i++ {
+		entry := resp[namesOff+i*18:]
+		if len(entry) < 18 {
+			break
+		}
+		name := strings.TrimSpace(string(entry[:15]))
+		if entry[15] != 0 {
+			continue
+		}
+		if entry[16]&0x80 == 0 && host == "" {
+			host = name
+		} else if entry[16]&0x80 != 0 && group == "" {
+			group = name
+		}
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s", host, group)), true
+}
+
+// ==================== SNMP ====================
+
+// snmpSysDescrOID 1.3.6.1.2.1.1.1.0
+var snmpSysDescrOID = []byte{0x06, 0x08, 0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x01, 0x00}
+
+func berTLV(tag byte, content ...[]byte) []byte {
+	body := bytes.Join(content, nil)
+	return append([]byte{tag, byte(len(body))}, body...)
+}
+
+// snmpProbe SNMPv2c public团体名 GetRequest sysDescr.0
+func snmpProbe() []byte {
+	varbind := berTLV(0x30, berTLV(0x30, snmpSysDescrOID, []byte{0x05, 0x00}))
+	pdu := berTLV(0xa0,
+		berTLV(0x02, randomBytes(4)),
+		[]byte{0x02, 0x01, 0x00},
+		[]byte{0x02, 0x01, 0x00},
+		varbind,
+	)
+	return berTLV(0x30, []byte{0x02, 0x01, 0x01}, berTLV(0x04, []byte("public")), pdu)
+}
+
+func parseSNMPResponse(_, resp []byte) (string, bool) {
+	if len(resp) < 2 || resp[0] != 0x30 {
+		return "", false
+	}
+	i := bytes.Index(resp, snmpSysDescrOID)
+	if i < 0 {
+		return "", true
+	}
+	val := resp[i+len(snmpSysDescrOID):]
+	if len(val) < 2 || val[0] != 0x04 {
+		return "", true
+	}
+	l, off := int(val[1]), 2
+	if val[1]&0x80 != 0 {
+		n := int(val[1] & 0x7f)
+		if n > 2 || len(val) < 2+n {
+			return "", true
+		}
+		l = 0
+		for _, b := range val[2 : 2+n] {
+			l = l<<8 | int(b)
+		}
+		off += n
+	}
+	if off+l > len(val) {
+		l = len(val) - off
+	}
+	return printableSummary(val[off:off+l], 256), true
+}
+
+// ==================== IKE ====================
+
+// ikeProbe IKEv1 主模式SA提议（3DES/SHA1/PSK/group2）
+func ikeProbe() []byte {
+	transform := []byte{
+		0, 0, 0, 36, 1, 1, 0, 0,
+		0x80, 0x01, 0x00, 0x05, // 加密 3DES
+		0x80, 0x02, 0x00, 0x02, // 哈希 SHA1
+		0x80, 0x03, 0x00, 0x01, // 认证 PSK
+		0x80, 0x04, 0x00, 0x02, // DH group 2
+		0x80, 0x0b, 0x00, 0x01, // 生存期单位: 秒
+		0x00, 0x0c, 0x00, 0x04, 0x00, 0x00, 0x70, 0x80, // 生存期 28800
+	}
+	proposal := append([]byte{0, 0, 0, 44, 1, 1, 0, 1}, transform...)
+	sa := append([]byte{0, 0, 0, 56, 0, 0, 0, 1, 0, 0, 0, 1}, proposal...)
+	header := make([]byte, 28)
+	copy(header, randomBytes(8))
+	header[16] = 1    // 下一个载荷: SA
+	header[17] = 0x10 // 版本 1.0
+	header[18] = 2    // 交换类型: 主模式
+	binary.BigEndian.PutUint32(header[24:], uint32(28+len(sa)))
+	return append(header, sa...)
+}
+
+func parseIKEResponse(req, resp []byte) (string, bool) {
+	if len(resp) < 28 || !bytes.Equal(resp[:8], req[:8]) {
+		return "", false
+	}
+	return fmt.Sprintf("IKEv%d, exchange=%d", resp[17]>>4, resp[18]), true
+}
+
+// ==================== SSDP ====================
+
+func ssdpProbe() []byte {
+	return []byte("M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\nMAN: \"ssdp:discover\"\r\nMX: 1\r\nST: ssdp:all\r\n\r\n")
+}
+
+func parseSSDPResponse(_, resp []byte) (string, bool) {
+	if !bytes.HasPrefix(bytes.ToUpper(resp), []byte("HTTP/1.1 200")) {
+		return "", false
+	}
+	for _, line := range strings.Split(string(resp), "\r\n") {
+		if k, v, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(k), "server") {
+			return strings.TrimSpace(v), true
+		}
+	}
+	return "", true
+}
+
+// ==================== memcached ====================
+
+// memcachedProbe UDP帧头(请求ID/序号/总数/保留) + version 命令
+func memcachedProbe() []byte {
+	msg := append(randomBytes(2), 0, 0, 0, 1, 0, 0)
+	return append(msg, "version\r\n"...)
+}
+
+func parseMemcachedResponse(req, resp []byte) (string, bool) {
+	if len(resp) < 8 || !bytes.Equal(resp[:2], req[:2]) || !bytes.HasPrefix(resp[8:], []byte("VERSION ")) {
+		return "", false
+	}
+	return strings.TrimSpace(string(resp[8:])), true
+}
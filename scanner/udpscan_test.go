@@ -0,0 +1,178 @@
+package scanner
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUDPProbeParsers(t *testing.T) {
+	dnsReq := dnsVersionProbe()
+	dnsResp := append([]byte{}, dnsReq...)
+	dnsResp[2] |= 0x80
+	binary.BigEndian.PutUint16(dnsResp[6:], 1)
+	dnsResp = append(dnsResp, 0xc0, 0x0c, 0, 16, 0, 3, 0, 0, 0, 0, 0, 7, 6)
+	dnsResp = append(dnsResp, "9.18.1"...)
+
+	ntpResp := make([]byte, 48)
+	ntpResp[0], ntpResp[1] = 0x24, 2
+
+	nbReq := netbiosProbe()
+	nbResp := make([]byte, 57)
+	copy(nbResp, nbReq[:2])
+	nbResp[2] = 0x84
+	nbResp[56] = 2
+	nbResp = append(nbResp, []byte("FILESRV        \x00\x04\x00")...)
+	nbResp = append(nbResp, []byte("WORKGROUP      \x00\x84\x00")...)
+
+	snmpReq := snmpProbe()
+	sysDescr := []byte("Linux router 5.10")
+	snmpResp := berTLV(0x30, []byte{0x02, 0x01, 0x01}, berTLV(0x04, []byte("public")),
+		berTLV(0xa2, berTLV(0x30, berTLV(0x30, snmpSysDescrOID, berTLV(0x04, sysDescr)))))
+
+	ikeReq := ikeProbe()
+	ikeResp := append(append([]byte{}, ikeReq[:8]...), make([]byte, 20)...)
+	ikeResp[17], ikeResp[18] = 0x10, 5
+
+	mcReq := memcachedProbe()
+	mcResp := append(append([]byte{}, mcReq[:8]...), "VERSION 1.6.21\r\n"...)
+
+	tests := []struct {
+		name      string
+		parse     func(req, resp []byte) (string, bool)
+		req, resp []byte
+		want      string
+		ok        bool
+	}{
+		{"dns version", parseDNSResponse, dnsReq, dnsResp, "version: 9.18.1", true},
+		{"dns wrong id", parseDNSResponse, dnsReq, append([]byte{dnsReq[0] ^ 0xff}, dnsResp[1:]...), "", false},
+		{"ntp", parseNTPResponse, ntpProbe(), ntpResp, "NTP v4, stratum 2", true},
+		{"ntp client mode", parseNTPResponse, ntpProbe(), ntpProbe(), "", false},
+		{"netbios", parseNetBIOSResponse, nbReq, nbResp, "FILESRV WORKGROUP", true},
+		{"snmp", parseSNMPResponse, snmpReq, snmpResp, "Linux router 5.10", true},
+		{"ike", parseIKEResponse, ikeReq, ikeResp, "IKEv1, exchange=5", true},
+		{"ssdp", parseSSDPResponse, ssdpProbe(), []byte("HTTP/1.1 200 OK\r\nSERVER: Linux UPnP/1.0 miniupnpd/2.2\r\n\r\n"), "Linux UPnP/1.0 miniupnpd/2.2", true},
+		{"memcached", parseMemcachedResponse, mcReq, mcResp, "VERSION 1.6.21", true},
+		{"tftp error", parseTFTPResponse, tftpProbe(), []byte("\x00\x05\x00\x01File not found\x00"), "error: File not found", true},
+	}
+	for _, tt := range tests {
+		got, ok := tt.parse(tt.req, tt.resp)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got (%q, %v), want (%q, %v)", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// serveUDP 启动本地UDP服务，reply 为空时不回复；fromNewPort 模拟TFTP从新端口回复
+func serveUDP(t *testing.T, reply func(req []byte) []byte, fromNewPort bool) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buf := make([]byte, 2048)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			resp := reply(buf[:n])
+			if resp == nil {
+				continue
+			}
+			if !fromNewPort {
+				conn.WriteTo(resp, addr)
+				continue
+			}
+			if c, err := net.ListenPacket("udp", "127.0.0.1:0"); err == nil {
+				c.WriteTo(resp, addr)
+				c.Close()
+			}
+		}
+	}()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// closedUDPPort 返回一个刚释放的本地UDP端口
+func closedUDPPort(t *testing.T) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	conn.Close()
+	return port
+}
+
+func TestUDPScan(t *testing.T) {
+	ntpPort := serveUDP(t, func(req []byte) []byte {
+		resp := make([]byte, 48)
+		resp[0], resp[1] = 0x1c, 3
+		return resp
+	}, false)
+	tftpPort := serveUDP(t, func(req []byte) []byte {
+		return []byte("\x00\x05\x00\x02Access violation\x00")
+	}, true)
+	genericPort := serveUDP(t, func(req []byte) []byte { return []byte("hello\x01") }, false)
+	var silentHits atomic.Int32
+	silentPort := serveUDP(t, func(req []byte) []byte { silentHits.Add(1); return nil }, false)
+	closedPort := closedUDPPort(t)
+
+	s := NewUDPScanner()
+	s.probes = map[int]*udpProbe{ntpPort: udpProbes[123], tftpPort: udpProbes[69]}
+	start := time.Now()
+	result, err := s.Scan(context.Background(), &ScanConfig{
+		Target: "127.0.0.1",
+		Options: &UDPScanOptions{
+			Ports:   joinPorts(ntpPort, tftpPort, genericPort, silentPort, closedPort),
+			Retries: 1,
+			Timeout: 1,
+			Rate:    1000,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("scan took %v", elapsed)
+	}
+
+	found := make(map[int]*Asset)
+	for _, a := range result.Assets {
+		if a.Transport != TransportUDP || a.Source != "udpscan" {
+			t.Errorf("unexpected asset: %+v", a)
+		}
+		found[a.Port] = a
+	}
+	if len(found) != 3 {
+		t.Fatalf("found ports %v, want ntp, tftp and generic", found)
+	}
+	if a := found[ntpPort]; a == nil || a.Service != "ntp" || a.Banner != "NTP v3, stratum 3" {
+		t.Errorf("ntp asset = %+v", a)
+	}
+	if a := found[tftpPort]; a == nil || a.Service != "tftp" || a.Banner != "error: Access violation" {
+		t.Errorf("tftp asset = %+v", a)
+	}
+	if a := found[genericPort]; a == nil || a.Service != "" || a.Banner != "hello." {
+		t.Errorf("generic asset = %+v", a)
+	}
+	if n := silentHits.Load(); n != 2 {
+		t.Errorf("silent port probed %d times, want 2 (1 retry)", n)
+	}
+}
+
+func joinPorts(ports ...int) string {
+	strs := make([]string, len(ports))
+	for i, p := range ports {
+		strs[i] = strconv.Itoa(p)
+	}
+	return strings.Join(strs, ",")
+}
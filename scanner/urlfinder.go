@@ -12,6 +12,8 @@ import (
 	"sync"
 	"time"
 
+	"cscan/pkg/utils"
+
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
@@ -126,7 +128,7 @@ func (s *URLFinderScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanR
 				if (scheme == "http" && asset.Port == 80) || (scheme == "https" && asset.Port == 443) {
 					baseURL = fmt.Sprintf("%s://%s", scheme, asset.Host)
 				} else {
-					baseURL = fmt.Sprintf("%s://%s:%d", scheme, asset.Host, asset.Port)
+					baseURL = fmt.Sprintf("%s://%s", scheme, utils.BuildTargetWithPort(asset.Host, asset.Port))
 				}
 				// 如果资产有 Path 字段，将其作为基础路径前缀
 				// 例如：用户输入 http://example.com/admin/，目录扫描应该扫描 /admin/login、/admin/config 等
@@ -384,6 +384,11 @@ func expandCIDR(cidr string) []string {
 	if err != nil {
 		return ips
 	}
+	// IPv6前缀按抽样策略展开，过大的前缀已在下发任务时拒绝
+	if ipnet.IP.To4() == nil {
+		ips, _ = utils.ExpandIPv6Prefix(ipnet)
+		return ips
+	}
 	count := 0
 	for ip := ipnet.IP.Mask(ipnet.Mask); ipnet.Contains(ip); incIP(ip) {
 		count++
@@ -420,15 +425,8 @@ func expandIPRange(ipRange string) []string {
 
 	startIP := net.ParseIP(strings.TrimSpace(parts[0]))
 	endIP := net.ParseIP(strings.TrimSpace(parts[1]))
-	if startIP == nil || endIP == nil {
-		return ips
-	}
-
-	for ip := startIP; !ip.Equal(endIP); incIP(ip) {
-		ips = append(ips, ip.String())
-	}
-	ips = append(ips, endIP.String())
-
+	// 地址族不一致、起始大于结束或IPv6范围过大时不展开
+	ips, _ = utils.ExpandIPRange(startIP, endIP, 0)
 	return ips
 }
 
@@ -205,6 +205,9 @@ func applyFingerprintDefaults(c *FingerprintConfig) {
 	if c.ActiveTimeout <= 0 {
 		c.ActiveTimeout = 10
 	}
+	if c.CertExpiryDays <= 0 {
+		c.CertExpiryDays = 30
+	}
 }
 
 func applyPocScanDefaults(c *PocScanConfig) {
@@ -241,6 +244,7 @@ func ValidateTaskConfig(config *TaskConfig) error {
 		v.OneOf("portscan.tool", config.PortScan.Tool, "tcp", "masscan", "nmap", "naabu")
 		v.NonNegative("portscan.rate", config.PortScan.Rate)
 		v.NonNegative("portscan.timeout", config.PortScan.Timeout)
+		v.NonNegative("portscan.udpRate", config.PortScan.UDPRate)
 	}
 
 	if config.PortIdentify != nil && config.PortIdentify.Enable {
@@ -254,10 +258,41 @@ func ValidateTaskConfig(config *TaskConfig) error {
 		v.NonNegative("domainscan.threads", config.DomainScan.Threads)
 	}
 
+	if config.Takeover != nil && config.Takeover.Enable {
+		v.NonNegative("takeover.concurrency", config.Takeover.Concurrency)
+		v.NonNegative("takeover.timeout", config.Takeover.Timeout)
+	}
+
+	if config.DNSRecord != nil && config.DNSRecord.Enable {
+		v.NonNegative("dnsrecord.concurrency", config.DNSRecord.Concurrency)
+		v.NonNegative("dnsrecord.timeout", config.DNSRecord.Timeout)
+	}
+
+	if config.Crawler != nil && config.Crawler.Enable {
+		v.NonNegative("crawler.maxDepth", config.Crawler.MaxDepth)
+		v.NonNegative("crawler.maxPages", config.Crawler.MaxPages)
+		v.NonNegative("crawler.timeout", config.Crawler.Timeout)
+		v.NonNegative("crawler.concurrency", config.Crawler.Concurrency)
+	}
+
+	if config.Bruteforce != nil && config.Bruteforce.Enable {
+		v.NonNegative("bruteforce.concurrency", config.Bruteforce.Concurrency)
+		v.NonNegative("bruteforce.rate", config.Bruteforce.Rate)
+		v.NonNegative("bruteforce.maxAttempts", config.Bruteforce.MaxAttempts)
+		v.NonNegative("bruteforce.maxPerUser", config.Bruteforce.MaxPerUser)
+		v.NonNegative("bruteforce.interval", config.Bruteforce.Interval)
+		v.NonNegative("bruteforce.timeout", config.Bruteforce.Timeout)
+	}
+
+	if config.CDN != nil && config.CDN.Enable {
+		v.NonNegative("cdn.concurrency", config.CDN.Concurrency)
+	}
+
 	if config.Fingerprint != nil && config.Fingerprint.Enable {
 		v.OneOf("fingerprint.tool", config.Fingerprint.Tool, "httpx", "builtin", "")
 		v.NonNegative("fingerprint.timeout", config.Fingerprint.Timeout)
 		v.NonNegative("fingerprint.concurrency", config.Fingerprint.Concurrency)
+		v.NonNegative("fingerprint.certExpiryDays", config.Fingerprint.CertExpiryDays)
 	}
 
 	if config.PocScan != nil && config.PocScan.Enable {
@@ -60,6 +60,12 @@ func (v *ConfigValidator) Validate(config *TaskConfig) error {
 		}
 	}
 
+	if config.Workflow != nil {
+		if err := config.Workflow.Validate(); err != nil {
+			errs = append(errs, xerr.NewConfigError("workflow", nil, err.Error()))
+		}
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -168,6 +174,10 @@ func (v *ConfigValidator) validateFingerprint(config *FingerprintConfig) error {
 		errs = append(errs, xerr.NewConfigError("fingerprint.concurrency", config.Concurrency, "concurrency must be non-negative"))
 	}
 
+	if config.CertExpiryDays < 0 {
+		errs = append(errs, xerr.NewConfigError("fingerprint.certExpiryDays", config.CertExpiryDays, "certExpiryDays must be non-negative"))
+	}
+
 	if len(errs) > 0 {
 		return errors.Join(errs...)
 	}
@@ -309,6 +319,9 @@ func (v *ConfigValidator) applyFingerprintDefaults(config *FingerprintConfig) {
 	if config.Concurrency == 0 {
 		config.Concurrency = 10
 	}
+	if config.CertExpiryDays == 0 {
+		config.CertExpiryDays = 30
+	}
 }
 
 // applyPocScanDefaults 应用POC扫描默认值
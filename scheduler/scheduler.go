@@ -438,7 +438,71 @@ type TaskConfig struct {
 	DomainScan   *DomainScanConfig   `json:"domainscan,omitempty"`
 	Fingerprint  *FingerprintConfig  `json:"fingerprint,omitempty"`
 	PocScan      *PocScanConfig      `json:"pocscan,omitempty"`
-	DirScan      *DirScanConfig      `json:"dirscan,omitempty"` // 目录扫描
+	DirScan      *DirScanConfig      `json:"dirscan,omitempty"`    // 目录扫描
+	External     *ExternalScanConfig `json:"external,omitempty"`   // 外部扫描器
+	Crawler      *CrawlerConfig      `json:"crawler,omitempty"`    // 爬虫
+	Bruteforce   *BruteforceConfig   `json:"bruteforce,omitempty"` // 弱口令检测
+	Takeover     *TakeoverConfig     `json:"takeover,omitempty"`   // 子域接管检测
+	DNSRecord    *DNSRecordConfig    `json:"dnsrecord,omitempty"`  // DNS记录采集
+	CDN          *CDNConfig          `json:"cdn,omitempty"`        // CDN/WAF/云厂商识别
+	Workflow     *WorkflowConfig     `json:"workflow,omitempty"`   // 声明式工作流，为空时按固定阶段顺序执行
+}
+
+// ExternalScanConfig 外部扫描器配置，在指纹识别之后、目录扫描之前执行，
+// 产出的资产会参与后续的目录扫描和POC扫描
+type ExternalScanConfig struct {
+	Enable   bool     `json:"enable"`
+	Scanners []string `json:"scanners"` // 外部扫描器名称，按顺序执行
+	Timeout  int      `json:"timeout"`  // 单个扫描器超时(秒)，0 表示使用声明中的配置，超过声明的值时按声明执行
+}
+
+// CrawlerConfig 爬虫配置，在外部扫描器之后、目录扫描之前执行，
+// 发现的端点保存为URL资产并参与后续的目录扫描和POC扫描，敏感信息规则从服务端规则库获取
+type CrawlerConfig struct {
+	Enable      bool `json:"enable"`
+	MaxDepth    int  `json:"maxDepth"`    // 最大爬取深度
+	MaxPages    int  `json:"maxPages"`    // 每个站点最多渲染的页面数
+	Timeout     int  `json:"timeout"`     // 单个页面超时(秒)
+	Concurrency int  `json:"concurrency"` // 并发站点数
+}
+
+// BruteforceConfig 弱口令检测配置，在爬虫之后、目录扫描之前执行，
+// 对端口识别出的服务检测未授权访问和弱口令，字典从服务端字典库获取，结果保存为漏洞
+type BruteforceConfig struct {
+	Enable      bool     `json:"enable"`
+	DictIds     []string `json:"dictIds"`     // 字典ID列表，为空时使用全部已启用的字典
+	Services    []string `json:"services"`    // 检测的协议，为空时检测全部支持的协议
+	Concurrency int      `json:"concurrency"` // 并发服务数
+	Rate        int      `json:"rate"`        // 每秒最多登录尝试次数
+	MaxAttempts int      `json:"maxAttempts"` // 每个服务最多登录尝试次数
+	MaxPerUser  int      `json:"maxPerUser"`  // 每个账号最多尝试次数，易锁定协议(SMB)最多3次
+	Interval    int      `json:"interval"`    // 同一服务两次尝试的间隔(毫秒)
+	Timeout     int      `json:"timeout"`     // 单次连接超时(秒)
+}
+
+// TakeoverConfig 子域接管检测配置，在子域名扫描之后执行，
+// 签名从服务端签名库获取，确认的结果保存为漏洞
+type TakeoverConfig struct {
+	Enable      bool `json:"enable"`
+	Concurrency int  `json:"concurrency"` // 并发域名数
+	Timeout     int  `json:"timeout"`     // 单个请求超时(秒)
+}
+
+// DNSRecordConfig DNS记录采集配置，在子域名扫描之后执行，
+// 记录写入DNS历史，缺失DMARC、宽松SPF和允许区域传送保存为漏洞
+type DNSRecordConfig struct {
+	Enable      bool     `json:"enable"`
+	Resolvers   []string `json:"resolvers"`   // DNS服务器，为空时使用Worker系统配置
+	Concurrency int      `json:"concurrency"` // 并发域名数
+	Timeout     int      `json:"timeout"`     // 单次查询超时(秒)
+	AXFR        bool     `json:"axfr"`        // 对发现的NS服务器尝试区域传送
+}
+
+// CDNConfig CDN/WAF/云厂商识别配置，不是独立阶段：
+// 子域名扫描、端口扫描和指纹识别产出的资产在保存前打标，端口扫描默认跳过CDN边缘节点
+type CDNConfig struct {
+	Enable      bool `json:"enable"`
+	Concurrency int  `json:"concurrency"` // 并发解析域名数
 }
 
 // DirScanConfig 目录扫描配置
@@ -474,10 +538,15 @@ type PortScanConfig struct {
 	SkipHostDiscovery bool   `json:"skipHostDiscovery"` // 跳过主机发现 (-Pn)
 	ExcludeCDN        bool   `json:"excludeCDN"`        // 排除 CDN/WAF，仅扫描 80,443 端口 (-ec)
 	ExcludeHosts      string `json:"excludeHosts"`      // 排除的目标，逗号分隔的 IP/CIDR
+	ForceCDN          bool   `json:"forceCDN"`          // 启用CDN识别时仍扫描CDN边缘节点
 	Retries           int    `json:"retries"`           // 重试次数，默认2，建议1-2
 	WarmUpTime        int    `json:"warmUpTime"`        // 扫描阶段间等待时间(秒)，默认1，建议0-1
 	Workers           int    `json:"workers"`           // Naabu内部工作线程，默认50，建议50-100
 	Verify            bool   `json:"verify"`            // TCP验证，默认false（禁用以提速）
+	UDP               bool   `json:"udp"`               // 同时扫描UDP端口（协议探测，结果标记为udp）
+	UDPPorts          string `json:"udpPorts"`          // UDP端口，为空时使用常见UDP服务端口
+	UDPRate           int    `json:"udpRate"`           // UDP每秒发包数，默认100
+	IPv6              bool   `json:"ipv6"`              // 域名目标同时扫描AAAA解析出的IPv6地址
 }
 
 // PortIdentifyConfig 端口识别配置（Nmap/Fingerprintx 服务识别）
@@ -520,20 +589,22 @@ type DomainScanConfig struct {
 }
 
 type FingerprintConfig struct {
-	Enable        bool   `json:"enable"`
-	Tool          string `json:"tool"`  // 探测工具: httpx, builtin (wappalyzer)
-	Httpx         bool   `json:"httpx"` // 已废弃，使用Tool字段
-	IconHash      bool   `json:"iconHash"`
-	Wappalyzer    bool   `json:"wappalyzer"`   // 已废弃，builtin模式自动启用
-	CustomEngine  bool   `json:"customEngine"` // 使用自定义指纹引擎（ARL格式）
-	Screenshot    bool   `json:"screenshot"`
-	ActiveScan    bool   `json:"activeScan"`    // 启用主动指纹扫描
-	ActiveTimeout int    `json:"activeTimeout"` // 主动指纹单个请求超时时间(秒)，默认10秒
-	Timeout       int    `json:"timeout"`       // 总超时时间(秒)，默认300秒
-	TargetTimeout int    `json:"targetTimeout"` // 单个目标超时时间(秒)，默认30秒
-	Concurrency   int    `json:"concurrency"`   // 指纹识别并发数，默认10
-	FilterMode    string `json:"filterMode"`    // 过滤模式: "http_mapping"(使用HTTP映射), "service_mapping"(使用服务映射过滤非HTTP)
-	ForceScan     bool   `json:"forceScan"`     // 强制扫描：无资产时直接使用目标
+	Enable         bool   `json:"enable"`
+	Tool           string `json:"tool"`  // 探测工具: httpx, builtin (wappalyzer)
+	Httpx          bool   `json:"httpx"` // 已废弃，使用Tool字段
+	IconHash       bool   `json:"iconHash"`
+	Wappalyzer     bool   `json:"wappalyzer"`   // 已废弃，builtin模式自动启用
+	CustomEngine   bool   `json:"customEngine"` // 使用自定义指纹引擎（ARL格式）
+	Screenshot     bool   `json:"screenshot"`
+	ActiveScan     bool   `json:"activeScan"`     // 启用主动指纹扫描
+	ActiveTimeout  int    `json:"activeTimeout"`  // 主动指纹单个请求超时时间(秒)，默认10秒
+	Timeout        int    `json:"timeout"`        // 总超时时间(秒)，默认300秒
+	TargetTimeout  int    `json:"targetTimeout"`  // 单个目标超时时间(秒)，默认30秒
+	Concurrency    int    `json:"concurrency"`    // 指纹识别并发数，默认10
+	FilterMode     string `json:"filterMode"`     // 过滤模式: "http_mapping"(使用HTTP映射), "service_mapping"(使用服务映射过滤非HTTP)
+	ForceScan      bool   `json:"forceScan"`      // 强制扫描：无资产时直接使用目标
+	CertScan       bool   `json:"certScan"`       // 采集TLS证书链并分析证书问题
+	CertExpiryDays int    `json:"certExpiryDays"` // 证书即将过期告警天数，默认30天
 }
 
 type PocScanConfig struct {
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net"
 	"strings"
+
+	"cscan/pkg/utils"
 )
 
 // ChunkConfig 分片配置
@@ -289,6 +291,11 @@ func (s *TaskSplitter) expandCIDR(cidr string) ([]string, error) {
 		return nil, fmt.Errorf("无效的CIDR格式: %v", err)
 	}
 
+	// IPv6前缀无法逐个展开，按抽样策略处理
+	if ipnet.IP.To4() == nil {
+		return utils.ExpandIPv6Prefix(ipnet)
+	}
+
 	// 限制CIDR展开的最大IP数量，防止内存溢出
 	maxIPs := 10000
 	count := 0
@@ -312,7 +319,6 @@ func (s *TaskSplitter) expandCIDR(cidr string) ([]string, error) {
 
 // expandIPRange 展开IP范围
 func (s *TaskSplitter) expandIPRange(ipRange string) ([]string, error) {
-	var ips []string
 	parts := strings.Split(ipRange, "-")
 	if len(parts) != 2 {
 		return nil, fmt.Errorf("无效的IP范围格式")
@@ -325,27 +331,7 @@ func (s *TaskSplitter) expandIPRange(ipRange string) ([]string, error) {
 	}
 
 	// 限制IP范围展开的最大数量
-	maxIPs := 10000
-	count := 0
-
-	// 复制起始IP，避免修改原始值
-	ip := make(net.IP, len(startIP))
-	copy(ip, startIP)
-
-	for ; !ip.Equal(endIP) && count < maxIPs; s.incIP(ip) {
-		ips = append(ips, ip.String())
-		count++
-	}
-	
-	if count < maxIPs {
-		ips = append(ips, endIP.String())
-	}
-
-	if count >= maxIPs {
-		return ips, fmt.Errorf("IP范围 %s 包含的IP数量过多（>%d），已截断", ipRange, maxIPs)
-	}
-
-	return ips, nil
+	return utils.ExpandIPRange(startIP, endIP, 10000)
 }
 
 // incIP IP自增
@@ -0,0 +1,234 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 工作流阶段的扇出方式
+const (
+	FanOutNone = ""     // 不拆分
+	FanOutPort = "port" // 按端口拆分，每个端口单独执行一次
+	FanOutHost = "host" // 按主机拆分，每个主机单独执行一次
+)
+
+// workflowPhases 工作流支持的阶段类型、显示名称及默认进度权重，顺序即默认线性工作流的顺序
+var workflowPhases = []struct {
+	Phase  string
+	Name   string
+	Weight int
+}{
+	{"domainscan", "子域名扫描", 10},
+	{"takeover", "子域接管检测", 5},
+	{"dnsrecord", "DNS记录采集", 5},
+	{"portscan", "端口扫描", 20},
+	{"portidentify", "端口识别", 10},
+	{"fingerprint", "指纹识别", 15},
+	{"external", "外部扫描", 5},
+	{"crawler", "爬虫", 5},
+	{"bruteforce", "弱口令检测", 5},
+	{"dirscan", "目录扫描", 10},
+	{"pocscan", "漏洞扫描", 20},
+}
+
+// WorkflowConfig 声明式扫描工作流，阶段之间通过 DependsOn 构成有向无环图
+// 未配置时按固定阶段顺序生成默认的线性工作流
+type WorkflowConfig struct {
+	Stages []*WorkflowStage `json:"stages"`
+}
+
+// WorkflowStage 工作流阶段
+// 阶段的输入是所有依赖阶段输出资产的并集（没有依赖时为任务目标），
+// 输出是输入资产加上本阶段产出的资产，Condition 只决定本阶段处理哪些资产，不影响向下游传递
+type WorkflowStage struct {
+	Id          string          `json:"id"`                    // 阶段唯一标识
+	Name        string          `json:"name,omitempty"`        // 显示名称
+	Phase       string          `json:"phase"`                 // 阶段类型，扫描参数取 TaskConfig 中对应阶段的配置
+	DependsOn   []string        `json:"dependsOn,omitempty"`   // 依赖的阶段ID
+	Condition   *StageCondition `json:"condition,omitempty"`   // 资产过滤条件
+	FanOut      string          `json:"fanOut,omitempty"`      // 扇出方式: port/host
+	Concurrency int             `json:"concurrency,omitempty"` // 扇出时的并发数，默认1
+	Weight      int             `json:"weight,omitempty"`      // 进度权重，0 使用阶段类型的默认权重
+	Optional    bool            `json:"optional,omitempty"`    // 失败时是否继续执行后续阶段
+}
+
+// StageCondition 阶段资产过滤条件，各项之间为“与”关系，同一项内为“或”关系
+type StageCondition struct {
+	Apps     []string `json:"apps,omitempty"`     // 应用名包含任一关键字（不区分大小写）
+	Services []string `json:"services,omitempty"` // 服务名等于任一（不区分大小写）
+	Ports    []int    `json:"ports,omitempty"`    // 端口为任一
+	HttpOnly bool     `json:"httpOnly,omitempty"` // 只处理HTTP资产
+	OnlyNew  bool     `json:"onlyNew,omitempty"`  // 只处理本次任务首次发现的资产
+}
+
+// DisplayName 阶段显示名称
+func (s *WorkflowStage) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Id
+}
+
+// EffectiveWeight 阶段进度权重
+func (s *WorkflowStage) EffectiveWeight() int {
+	if s.Weight > 0 {
+		return s.Weight
+	}
+	for _, p := range workflowPhases {
+		if p.Phase == s.Phase {
+			return p.Weight
+		}
+	}
+	return 1
+}
+
+// IsWorkflowPhase 判断是否为工作流支持的阶段类型
+func IsWorkflowPhase(phase string) bool {
+	for _, p := range workflowPhases {
+		if p.Phase == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 校验工作流：阶段ID唯一、阶段类型合法、依赖存在且无环
+func (c *WorkflowConfig) Validate() error {
+	if c == nil || len(c.Stages) == 0 {
+		return fmt.Errorf("workflow has no stages")
+	}
+	ids := make(map[string]bool, len(c.Stages))
+	for i, s := range c.Stages {
+		if s == nil {
+			return fmt.Errorf("workflow stage %d is empty", i)
+		}
+		if strings.TrimSpace(s.Id) == "" {
+			return fmt.Errorf("workflow stage %d: id is required", i)
+		}
+		if ids[s.Id] {
+			return fmt.Errorf("workflow stage %s: duplicate id", s.Id)
+		}
+		ids[s.Id] = true
+		if !IsWorkflowPhase(s.Phase) {
+			return fmt.Errorf("workflow stage %s: unknown phase %q", s.Id, s.Phase)
+		}
+		switch s.FanOut {
+		case FanOutNone, FanOutPort, FanOutHost:
+		default:
+			return fmt.Errorf("workflow stage %s: unknown fanOut %q", s.Id, s.FanOut)
+		}
+		if s.Concurrency < 0 || s.Weight < 0 {
+			return fmt.Errorf("workflow stage %s: concurrency and weight must be non-negative", s.Id)
+		}
+	}
+	for _, s := range c.Stages {
+		for _, dep := range s.DependsOn {
+			if !ids[dep] {
+				return fmt.Errorf("workflow stage %s: unknown dependency %q", s.Id, dep)
+			}
+			if dep == s.Id {
+				return fmt.Errorf("workflow stage %s: depends on itself", s.Id)
+			}
+		}
+	}
+	_, err := c.TopoOrder()
+	return err
+}
+
+// TopoOrder 按依赖关系返回阶段执行顺序，无依赖关系的阶段保持声明顺序
+func (c *WorkflowConfig) TopoOrder() ([]*WorkflowStage, error) {
+	indegree := make(map[string]int, len(c.Stages))
+	children := make(map[string][]string, len(c.Stages))
+	for _, s := range c.Stages {
+		indegree[s.Id] += 0
+		for _, dep := range s.DependsOn {
+			indegree[s.Id]++
+			children[dep] = append(children[dep], s.Id)
+		}
+	}
+
+	order := make([]*WorkflowStage, 0, len(c.Stages))
+	done := make(map[string]bool, len(c.Stages))
+	for len(order) < len(c.Stages) {
+		progressed := false
+		for _, s := range c.Stages {
+			if done[s.Id] || indegree[s.Id] > 0 {
+				continue
+			}
+			done[s.Id] = true
+			order = append(order, s)
+			for _, child := range children[s.Id] {
+				indegree[child]--
+			}
+			progressed = true
+			break
+		}
+		if !progressed {
+			var cyclic []string
+			for _, s := range c.Stages {
+				if !done[s.Id] {
+					cyclic = append(cyclic, s.Id)
+				}
+			}
+			return nil, fmt.Errorf("workflow has a dependency cycle among stages: %s", strings.Join(cyclic, ", "))
+		}
+	}
+	return order, nil
+}
+
+// DefaultWorkflow 将旧版配置映射为线性工作流：按固定顺序串联已启用的阶段
+// 阶段ID即阶段类型，与旧版暂停状态中的已完成阶段一致；阶段失败时继续执行后续阶段
+func DefaultWorkflow(config *TaskConfig) *WorkflowConfig {
+	wf := &WorkflowConfig{}
+	prev := ""
+	for _, p := range workflowPhases {
+		if !config.phaseEnabled(p.Phase) {
+			continue
+		}
+		stage := &WorkflowStage{Id: p.Phase, Name: p.Name, Phase: p.Phase, Weight: p.Weight, Optional: true}
+		if prev != "" {
+			stage.DependsOn = []string{prev}
+		}
+		wf.Stages = append(wf.Stages, stage)
+		prev = p.Phase
+	}
+	return wf
+}
+
+// GetWorkflow 获取任务的工作流，未声明时返回默认线性工作流
+func (c *TaskConfig) GetWorkflow() *WorkflowConfig {
+	if c.Workflow != nil && len(c.Workflow.Stages) > 0 {
+		return c.Workflow
+	}
+	return DefaultWorkflow(c)
+}
+
+// phaseEnabled 判断旧版配置中阶段是否启用
+func (c *TaskConfig) phaseEnabled(phase string) bool {
+	switch phase {
+	case "domainscan":
+		return c.DomainScan != nil && c.DomainScan.Enable
+	case "takeover":
+		return c.Takeover != nil && c.Takeover.Enable
+	case "dnsrecord":
+		return c.DNSRecord != nil && c.DNSRecord.Enable
+	case "portscan":
+		return c.PortScan != nil && c.PortScan.Enable
+	case "portidentify":
+		return c.PortIdentify != nil && c.PortIdentify.Enable
+	case "fingerprint":
+		return c.Fingerprint != nil && c.Fingerprint.Enable
+	case "external":
+		return c.External != nil && c.External.Enable
+	case "crawler":
+		return c.Crawler != nil && c.Crawler.Enable
+	case "bruteforce":
+		return c.Bruteforce != nil && c.Bruteforce.Enable
+	case "dirscan":
+		return c.DirScan != nil && c.DirScan.Enable
+	case "pocscan":
+		return c.PocScan != nil && c.PocScan.Enable
+	default:
+		return false
+	}
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func stageIds(stages []*WorkflowStage) []string {
+	ids := make([]string, 0, len(stages))
+	for _, s := range stages {
+		ids = append(ids, s.Id)
+	}
+	return ids
+}
+
+func TestWorkflowTopoOrder(t *testing.T) {
+	wf := &WorkflowConfig{Stages: []*WorkflowStage{
+		{Id: "poc", Phase: "pocscan", DependsOn: []string{"fp", "dir"}},
+		{Id: "dir", Phase: "dirscan", DependsOn: []string{"fp"}, Condition: &StageCondition{Apps: []string{"tomcat"}}},
+		{Id: "ports", Phase: "portscan"},
+		{Id: "fp", Phase: "fingerprint", DependsOn: []string{"ports"}},
+	}}
+	if err := wf.Validate(); err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	order, err := wf.TopoOrder()
+	if err != nil {
+		t.Fatalf("TopoOrder() error: %v", err)
+	}
+	if got, want := stageIds(order), []string{"ports", "fp", "dir", "poc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	cases := map[string]*WorkflowConfig{
+		"empty":     {},
+		"duplicate": {Stages: []*WorkflowStage{{Id: "a", Phase: "portscan"}, {Id: "a", Phase: "pocscan"}}},
+		"phase":     {Stages: []*WorkflowStage{{Id: "a", Phase: "unknown"}}},
+		"dep":       {Stages: []*WorkflowStage{{Id: "a", Phase: "portscan", DependsOn: []string{"b"}}}},
+		"fanout":    {Stages: []*WorkflowStage{{Id: "a", Phase: "portscan", FanOut: "vhost"}}},
+		"cycle": {Stages: []*WorkflowStage{
+			{Id: "a", Phase: "fingerprint", DependsOn: []string{"b"}},
+			{Id: "b", Phase: "pocscan", DependsOn: []string{"a"}},
+		}},
+	}
+	for name, wf := range cases {
+		if err := wf.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil, want error", name)
+		} else if name == "cycle" && !strings.Contains(err.Error(), "cycle") {
+			t.Errorf("cycle: unexpected error %v", err)
+		}
+	}
+}
+
+func TestDefaultWorkflow(t *testing.T) {
+	config := &TaskConfig{
+		PortScan:    &PortScanConfig{Enable: true},
+		Fingerprint: &FingerprintConfig{Enable: true},
+		DirScan:     &DirScanConfig{Enable: false},
+		PocScan:     &PocScanConfig{Enable: true},
+	}
+	wf := config.GetWorkflow()
+	if got, want := stageIds(wf.Stages), []string{"portscan", "fingerprint", "pocscan"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("stages = %v, want %v", got, want)
+	}
+	if len(wf.Stages[0].DependsOn) != 0 || !reflect.DeepEqual(wf.Stages[2].DependsOn, []string{"fingerprint"}) {
+		t.Errorf("linear dependencies not set: %v / %v", wf.Stages[0].DependsOn, wf.Stages[2].DependsOn)
+	}
+	if wf.Stages[0].DisplayName() != "端口扫描" || !wf.Stages[0].Optional {
+		t.Errorf("stage = %+v, want named optional stage", wf.Stages[0])
+	}
+	if err := wf.Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+
+	declared := &TaskConfig{Workflow: &WorkflowConfig{Stages: []*WorkflowStage{{Id: "poc", Phase: "pocscan"}}}}
+	if got := declared.GetWorkflow(); got != declared.Workflow {
+		t.Errorf("declared workflow should be used as is")
+	}
+}
@@ -25,6 +25,21 @@ export function testNotifyConfig(data) {
   return request.post('/notify/config/test', data)
 }
 
+// 获取通知路由规则列表
+export function getNotifyRouteList() {
+  return request.post('/notify/route/list', {})
+}
+
+// 保存通知路由规则
+export function saveNotifyRoute(data) {
+  return request.post('/notify/route/save', data)
+}
+
+// 删除通知路由规则
+export function deleteNotifyRoute(id) {
+  return request.post('/notify/route/delete', { id })
+}
+
 // 获取指纹列表（用于高危指纹选择）
 export function getFingerprintList(params = {}) {
   return request.post('/fingerprint/list', params)
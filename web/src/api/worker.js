@@ -74,6 +74,11 @@ export function getTerminalHistory(workerName, limit = 100) {
   return request.get('/worker/console/terminal/history', { params: { name: workerName, limit } })
 }
 
+// 终端会话录像（asciicast v2）
+export function getTerminalRecording(workerName, sessionId) {
+  return request.get('/worker/console/terminal/recording', { params: { workerName, sessionId } })
+}
+
 // 审计日志
 export function getAuditLogs(workerName, page = 1, pageSize = 20) {
   return request.get('/worker/console/audit', { params: { workerName, page, pageSize } })
@@ -158,6 +158,12 @@ const routes = [
         component: lazyLoad(() => import('@/views/HighRiskFilter.vue')),
         meta: { title: '高危过滤配置', icon: 'Warning' }
       },
+      {
+        path: 'notify-route',
+        name: 'NotifyRoute',
+        component: lazyLoad(() => import('@/views/NotifyRoute.vue')),
+        meta: { title: '通知路由规则', icon: 'Bell' }
+      },
       {
         path: 'worker/console/:name',
         name: 'WorkerConsole',
@@ -186,7 +192,7 @@ const routes = [
         path: 'user',
         name: 'User',
         redirect: '/settings?tab=user',
-        meta: { title: '用户管理', icon: 'User', roles: ['superadmin'], hidden: true }
+        meta: { title: '用户管理', icon: 'User', roles: ['admin'], hidden: true }
       },
       {
         path: 'organization',
@@ -10,7 +10,7 @@ export const useUserStore = defineStore('user', () => {
   const workspaceId = ref(localStorage.getItem('workspaceId') || '')
 
   const isLoggedIn = computed(() => !!token.value)
-  const isSuperAdmin = computed(() => role.value === 'superadmin')
+  const isSuperAdmin = computed(() => role.value === 'admin')
 
   async function login(loginForm) {
     const res = await loginApi(loginForm)
@@ -3,16 +3,33 @@
  * 处理截图数据的显示和格式化
  */
 
+// 截图存储键：内容 SHA256 + 扩展名，接口返回时附带签名参数（?exp=&sig=），图片通过 /api/screenshot/:key 读取
+const SCREENSHOT_KEY_RE = /^[0-9a-f]{64}\.(png|jpg)(\?exp=\d+&sig=[0-9a-f]+)?$/
+
+/**
+ * 判断截图字段是否为存储键（旧数据为 base64）
+ * @param {string} screenshot - 截图字段值
+ * @returns {boolean} 是否为存储键
+ */
+export function isScreenshotKey(screenshot) {
+  return typeof screenshot === 'string' && SCREENSHOT_KEY_RE.test(screenshot)
+}
+
 /**
  * 获取正确的截图 URL
- * @param {string} screenshot - 截图数据（可能是 base64 或完整的 data URI）
- * @returns {string} 正确格式的 data URI 或空字符串
+ * @param {string} screenshot - 截图数据（存储键、base64 或完整的 data URI）
+ * @returns {string} 截图文件地址、正确格式的 data URI 或空字符串
  */
 export function getScreenshotDataUrl(screenshot) {
   if (!screenshot) {
     return ''
   }
 
+  // 存储键通过截图文件接口读取
+  if (isScreenshotKey(screenshot)) {
+    return `/api/screenshot/${screenshot}`
+  }
+
   // 如果已经是完整的 data URI，直接返回
   if (screenshot.startsWith('data:image/')) {
     return screenshot
@@ -68,13 +85,18 @@ export function detectImageFormat(base64Data) {
 /**
  * 获取带正确格式的截图 URL
  * @param {string} screenshot - 截图数据
- * @returns {string} 完整的 data URI
+ * @returns {string} 截图文件地址或完整的 data URI
  */
 export function formatScreenshotUrl(screenshot) {
   if (!screenshot) {
     return ''
   }
 
+  // 存储键通过截图文件接口读取
+  if (isScreenshotKey(screenshot)) {
+    return `/api/screenshot/${screenshot}`
+  }
+
   // 如果已经是完整的 data URI
   if (screenshot.startsWith('data:image/')) {
     return screenshot
@@ -97,8 +119,8 @@ export function isValidScreenshot(screenshot) {
     return false
   }
 
-  // 检查是否是有效的 data URI
-  if (screenshot.startsWith('data:image/')) {
+  // 检查是否是有效的 data URI 或存储键
+  if (screenshot.startsWith('data:image/') || isScreenshotKey(screenshot)) {
     return true
   }
 
@@ -125,6 +147,7 @@ export function handleScreenshotError(event) {
 }
 
 export default {
+  isScreenshotKey,
   getScreenshotDataUrl,
   detectImageFormat,
   formatScreenshotUrl,
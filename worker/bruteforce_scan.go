@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// loadBruteforceCredentials 获取服务端弱口令字典并按协议合并，适用于全部协议的字典追加到每个协议
+// 获取失败或没有可用字典时返回 nil，由扫描器使用内置字典
+func (w *Worker) loadBruteforceCredentials(ctx context.Context, taskId string, dictIds []string) map[string][]scanner.Credential {
+	resp, err := w.httpClient.GetBruteforceDicts(ctx, dictIds)
+	if err != nil {
+		w.taskLog(taskId, LevelWarn, "Bruteforce: get dicts failed: %v, using built-in dicts", err)
+		return nil
+	}
+	if resp.Code != 0 {
+		w.taskLog(taskId, LevelWarn, "Bruteforce: get dicts failed: %s, using built-in dicts", resp.Msg)
+		return nil
+	}
+
+	credentials := make(map[string][]scanner.Credential)
+	var common []scanner.Credential
+	for _, d := range resp.Dicts {
+		if d.Service == "all" {
+			common = append(common, d.Credentials...)
+			continue
+		}
+		credentials[d.Service] = append(credentials[d.Service], d.Credentials...)
+	}
+	if len(common) > 0 {
+		for _, service := range scanner.BruteforceServices() {
+			credentials[service] = append(credentials[service], common...)
+		}
+	}
+	return credentials
+}
+
+// executeBruteforceScan 对端口识别出的服务执行未授权访问和弱口令检测，漏洞已保存
+func (w *Worker) executeBruteforceScan(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset, config *scheduler.BruteforceConfig) (vuls []*scanner.Vulnerability) {
+	// 添加 panic 恢复机制
+	defer func() {
+		if r := recover(); r != nil {
+			w.taskLog(task.TaskId, LevelError, "Bruteforce panic recovered: %v, stack: %s", r, string(getStackTrace()))
+		}
+	}()
+
+	if len(assets) == 0 {
+		w.taskLog(task.TaskId, LevelInfo, "Bruteforce: skipped (no assets)")
+		return nil
+	}
+
+	s := scanner.NewBruteforceScanner()
+	result, err := s.Scan(ctx, &scanner.ScanConfig{
+		Assets: assets,
+		Options: &scanner.BruteforceOptions{
+			Services:    config.Services,
+			Credentials: w.loadBruteforceCredentials(ctx, task.TaskId, config.DictIds),
+			Concurrency: config.Concurrency,
+			Rate:        config.Rate,
+			MaxAttempts: config.MaxAttempts,
+			MaxPerUser:  config.MaxPerUser,
+			Interval:    config.Interval,
+			Timeout:     config.Timeout,
+		},
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger: func(level, format string, args ...interface{}) {
+			w.taskLog(task.TaskId, level, format, args...)
+		},
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "Bruteforce: %v", err)
+	}
+	if result == nil {
+		return nil
+	}
+
+	if len(result.Vulnerabilities) > 0 {
+		w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, result.Vulnerabilities)
+	}
+	return result.Vulnerabilities
+}
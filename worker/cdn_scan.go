@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// loadCDNProviders 获取服务端已启用的CDN识别规则，获取失败时使用内置规则
+func (w *Worker) loadCDNProviders(ctx context.Context, taskId string) []*scanner.CDNProvider {
+	resp, err := w.httpClient.GetCDNProviders(ctx)
+	if err != nil {
+		w.taskLog(taskId, LevelWarn, "CDN: get providers failed: %v, using built-in providers", err)
+		return scanner.DefaultCDNProviders()
+	}
+	if resp.Code != 0 {
+		w.taskLog(taskId, LevelWarn, "CDN: get providers failed: %s, using built-in providers", resp.Msg)
+		return scanner.DefaultCDNProviders()
+	}
+
+	providers := make([]*scanner.CDNProvider, 0, len(resp.Providers))
+	for _, p := range resp.Providers {
+		if p == nil {
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			w.taskLog(taskId, LevelWarn, "CDN: invalid provider %s: %v", p.Name, err)
+			continue
+		}
+		providers = append(providers, p)
+	}
+	return providers
+}
+
+// cdnOptions 构建CDN识别选项，未启用时返回 nil
+func (w *Worker) cdnOptions(ctx context.Context, taskId string, config *scheduler.CDNConfig) *scanner.CDNOptions {
+	if config == nil || !config.Enable {
+		return nil
+	}
+	providers := w.loadCDNProviders(ctx, taskId)
+	if len(providers) == 0 {
+		w.taskLog(taskId, LevelWarn, "CDN: no enabled providers, skipped")
+		return nil
+	}
+	return &scanner.CDNOptions{Providers: providers, Concurrency: config.Concurrency}
+}
+
+// detectCDN 识别资产所属的CDN/WAF/云厂商并直接标记在资产上，由调用方保存
+func (w *Worker) detectCDN(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset, config *scheduler.CDNConfig) {
+	if len(assets) == 0 {
+		return
+	}
+	opts := w.cdnOptions(ctx, task.TaskId, config)
+	if opts == nil {
+		return
+	}
+
+	_, err := scanner.NewCDNScanner().Scan(ctx, &scanner.ScanConfig{
+		Assets:      assets,
+		Options:     opts,
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger: func(level, format string, args ...interface{}) {
+			w.taskLog(task.TaskId, level, format, args...)
+		},
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelWarn, "CDN: %v", err)
+	}
+}
+
+// skipCDNTargets 端口扫描前去除指向CDN边缘节点的目标，portScan.ForceCDN 时不过滤
+func (w *Worker) skipCDNTargets(ctx context.Context, task *scheduler.TaskInfo, targets []string, config *scheduler.CDNConfig, portScan *scheduler.PortScanConfig) []string {
+	if len(targets) == 0 || (portScan != nil && portScan.ForceCDN) {
+		return targets
+	}
+	opts := w.cdnOptions(ctx, task.TaskId, config)
+	if opts == nil {
+		return targets
+	}
+
+	kept, skipped := scanner.NewCDNScanner().FilterTargets(ctx, targets, opts)
+	if len(skipped) > 0 {
+		w.taskLog(task.TaskId, LevelInfo, "CDN: skipped %d CDN edge targets in port scan", len(skipped))
+	}
+	return kept
+}
@@ -0,0 +1,95 @@
+package worker
+
+import (
+	"context"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// loadSecretRules 获取服务端已启用的敏感信息规则，获取失败时使用内置规则
+func (w *Worker) loadSecretRules(ctx context.Context, taskId string) []*scanner.SecretRule {
+	resp, err := w.httpClient.GetSecretRules(ctx)
+	if err != nil {
+		w.taskLog(taskId, LevelWarn, "Crawler: get secret rules failed: %v, using built-in rules", err)
+		return scanner.DefaultSecretRules()
+	}
+	if resp.Code != 0 {
+		w.taskLog(taskId, LevelWarn, "Crawler: get secret rules failed: %s, using built-in rules", resp.Msg)
+		return scanner.DefaultSecretRules()
+	}
+
+	rules := make([]*scanner.SecretRule, 0, len(resp.Rules))
+	for _, rule := range resp.Rules {
+		if rule == nil {
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			w.taskLog(taskId, LevelWarn, "Crawler: invalid secret rule %s: %v", rule.Name, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// executeCrawlerScan 爬取HTTP资产，端点写入服务端URL资产，敏感信息保存为漏洞
+// 返回端点对应的URL资产供目录扫描和POC扫描使用，这些资产不写入主机资产表
+func (w *Worker) executeCrawlerScan(ctx context.Context, task *scheduler.TaskInfo, target string, assets []*scanner.Asset, config *scheduler.CrawlerConfig) (urlAssets []*scanner.Asset, vuls []*scanner.Vulnerability) {
+	// 添加 panic 恢复机制
+	defer func() {
+		if r := recover(); r != nil {
+			w.taskLog(task.TaskId, LevelError, "Crawler panic recovered: %v, stack: %s", r, string(getStackTrace()))
+		}
+	}()
+
+	opts := &scanner.CrawlerOptions{
+		MaxDepth:    config.MaxDepth,
+		MaxPages:    config.MaxPages,
+		Timeout:     config.Timeout,
+		Concurrency: config.Concurrency,
+		SecretRules: w.loadSecretRules(ctx, task.TaskId),
+	}
+	// 配置了扫描范围时允许收集范围内的跨域接口
+	if matcher := w.scanScopeOf(task.WorkspaceId); !matcher.IsEmpty() {
+		opts.InScope = matcher.InScope
+	}
+
+	s := scanner.NewCrawlerScanner()
+	result, err := s.Scan(ctx, &scanner.ScanConfig{
+		Target:      target,
+		Targets:     ParseTargets(target),
+		Assets:      assets,
+		Options:     opts,
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger: func(level, format string, args ...interface{}) {
+			w.taskLog(task.TaskId, level, format, args...)
+		},
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "Crawler: %v", err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+
+	if len(result.CrawlEndpoints) > 0 {
+		resp, err := w.httpClient.SaveURLAssets(ctx, &URLAssetResultReq{
+			WorkspaceId: task.WorkspaceId,
+			MainTaskId:  task.MainTaskId,
+			Endpoints:   result.CrawlEndpoints,
+		})
+		if err != nil {
+			w.taskLog(task.TaskId, LevelError, "Crawler: save endpoints failed: %v", err)
+		} else if resp.Code != 0 {
+			w.taskLog(task.TaskId, LevelError, "Crawler: save endpoints failed: %s", resp.Msg)
+		}
+	}
+	if len(result.Vulnerabilities) > 0 {
+		w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, result.Vulnerabilities)
+	}
+
+	urlAssets = w.filterAssetsByScope(task.WorkspaceId, task.MainTaskId, task.TaskId, "crawler", result.Assets)
+	return urlAssets, result.Vulnerabilities
+}
@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// Credential Worker 注册后获得的独立凭证，保存在本地文件，重启后无需再次使用安装密钥
+type Credential struct {
+	ServerAddr   string `json:"serverAddr"`
+	WorkerName   string `json:"workerName"`
+	CredentialId string `json:"credentialId"`
+	Token        string `json:"token"`
+	EnrollTime   string `json:"enrollTime"`
+}
+
+// LoadCredential 读取本地凭证文件，文件不存在时返回 nil
+func LoadCredential(path string) (*Credential, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var cred Credential
+	if err := json.Unmarshal(data, &cred); err != nil {
+		return nil, fmt.Errorf("parse credential file failed: %w", err)
+	}
+	if cred.Token == "" {
+		return nil, nil
+	}
+	return &cred, nil
+}
+
+// SaveCredential 保存凭证文件，仅当前用户可读写
+func SaveCredential(path string, cred *Credential) error {
+	data, err := json.MarshalIndent(cred, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// updateCredentialName Worker 重命名后更新本地凭证文件中的名称
+func (w *Worker) updateCredentialName(name string) {
+	if w.config.CredentialFile == "" {
+		return
+	}
+	cred, err := LoadCredential(w.config.CredentialFile)
+	if err != nil || cred == nil {
+		return
+	}
+	cred.WorkerName = name
+	if err := SaveCredential(w.config.CredentialFile, cred); err != nil {
+		w.logger.Error("Update credential file failed: %v", err)
+	}
+}
+
+// EnrollWorker 使用安装密钥注册，换取该 Worker 独立的凭证
+func EnrollWorker(serverAddr, installKey, name string) (*Credential, error) {
+	reqBody, _ := json.Marshal(map[string]string{
+		"installKey": installKey,
+		"workerName": name,
+		"workerIP":   GetLocalIP(),
+		"workerOS":   runtime.GOOS,
+		"workerArch": runtime.GOARCH,
+	})
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	var lastErr error
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(i) * time.Second)
+		}
+		resp, err := client.Post(serverAddr+"/api/v1/worker/enroll", "application/json", bytes.NewReader(reqBody))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var result struct {
+			Code         int    `json:"code"`
+			Msg          string `json:"msg"`
+			CredentialId string `json:"credentialId"`
+			WorkerName   string `json:"workerName"`
+			Token        string `json:"token"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("parse enroll response failed: %w", err)
+		}
+		if result.Code != 0 || result.Token == "" {
+			return nil, fmt.Errorf("enroll rejected: %s", result.Msg)
+		}
+		return &Credential{
+			ServerAddr:   serverAddr,
+			WorkerName:   result.WorkerName,
+			CredentialId: result.CredentialId,
+			Token:        result.Token,
+			EnrollTime:   time.Now().Format("2006-01-02 15:04:05"),
+		}, nil
+	}
+	return nil, fmt.Errorf("enroll failed after 3 attempts: %v", lastErr)
+}
@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"sync"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// executeDNSRecordScan 采集目标和资产中域名的DNS记录
+// 记录写入服务端DNS历史，返回的配置问题漏洞已保存
+func (w *Worker) executeDNSRecordScan(ctx context.Context, task *scheduler.TaskInfo, target string, assets []*scanner.Asset, config *scheduler.DNSRecordConfig) (records []*scanner.DNSRecord, vuls []*scanner.Vulnerability) {
+	// 添加 panic 恢复机制
+	defer func() {
+		if r := recover(); r != nil {
+			w.taskLog(task.TaskId, LevelError, "DNSRecord panic recovered: %v, stack: %s", r, string(getStackTrace()))
+		}
+	}()
+
+	opts := &scanner.DNSRecordOptions{
+		Resolvers:   config.Resolvers,
+		Concurrency: config.Concurrency,
+		Timeout:     config.Timeout,
+		AXFR:        config.AXFR,
+	}
+	// 区域传送会直连NS服务器，范围外的服务器跳过并记录
+	var mu sync.Mutex
+	var skipped []*OutOfScopeItem
+	seen := make(map[string]bool)
+	if matcher := w.scanScopeOf(task.WorkspaceId); !matcher.IsEmpty() {
+		opts.InScope = func(host string) bool {
+			if matcher.InScope(host) {
+				return true
+			}
+			mu.Lock()
+			if !seen[host] {
+				seen[host] = true
+				skipped = append(skipped, &OutOfScopeItem{Target: host, Host: host, Phase: "dnsrecord", Reason: "nameserver not in scope"})
+			}
+			mu.Unlock()
+			return false
+		}
+	}
+
+	s := scanner.NewDNSRecordScanner()
+	result, err := s.Scan(ctx, &scanner.ScanConfig{
+		Target:      target,
+		Targets:     ParseTargets(target),
+		Assets:      assets,
+		Options:     opts,
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger: func(level, format string, args ...interface{}) {
+			w.taskLog(task.TaskId, level, format, args...)
+		},
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "DNSRecord: %v", err)
+	}
+	if len(skipped) > 0 {
+		w.reportOutOfScope(task.WorkspaceId, task.MainTaskId, task.TaskId, skipped)
+	}
+	if result == nil {
+		return nil, nil
+	}
+
+	if len(result.DNSRecords) > 0 {
+		resp, err := w.httpClient.SaveDNSRecords(ctx, &DNSRecordResultReq{
+			WorkspaceId: task.WorkspaceId,
+			MainTaskId:  task.MainTaskId,
+			Records:     result.DNSRecords,
+		})
+		if err != nil {
+			w.taskLog(task.TaskId, LevelError, "DNSRecord: save records failed: %v", err)
+		} else if resp.Code != 0 {
+			w.taskLog(task.TaskId, LevelError, "DNSRecord: save records failed: %s", resp.Msg)
+		}
+	}
+	if len(result.Vulnerabilities) > 0 {
+		w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, result.Vulnerabilities)
+	}
+	return result.DNSRecords, result.Vulnerabilities
+}
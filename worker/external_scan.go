@@ -0,0 +1,151 @@
+package worker
+
+import (
+	"context"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// loadExternalScanners 加载本地声明文件中的外部扫描器
+// 本地声明优先于服务端下发的同名声明
+func (w *Worker) loadExternalScanners() {
+	if w.config.ExternalScannerFile == "" {
+		return
+	}
+
+	registry := scanner.DefaultRegistry()
+	names, err := registry.LoadExternalScanners(w.config.ExternalScannerFile)
+	if err != nil {
+		w.logger.Error("Load external scanners from %s failed: %v", w.config.ExternalScannerFile, err)
+	}
+	for _, name := range names {
+		if _, exists := w.scanners[name]; exists {
+			w.logger.Warn("External scanner %s conflicts with built-in scanner, skipped", name)
+			continue
+		}
+		s, err := registry.Get(name)
+		if err != nil {
+			continue
+		}
+		w.scanners[name] = s
+	}
+	if len(names) > 0 {
+		w.logger.Info("Loaded %d external scanners from %s", len(names), w.config.ExternalScannerFile)
+	}
+}
+
+// resolveExternalScanners 按名称解析外部扫描器，本地未声明的从服务端获取
+func (w *Worker) resolveExternalScanners(ctx context.Context, taskId string, names []string) []*scanner.ExternalScanner {
+	resolved := make(map[string]*scanner.ExternalScanner, len(names))
+	var missing []string
+	for _, name := range names {
+		if s, ok := w.scanners[name].(*scanner.ExternalScanner); ok {
+			resolved[name] = s
+		} else {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		resp, err := w.httpClient.GetExternalScanners(ctx, missing)
+		if err != nil {
+			w.taskLog(taskId, LevelError, "External scan: get scanners failed: %v", err)
+		} else if resp.Code != 0 {
+			w.taskLog(taskId, LevelError, "External scan: get scanners failed: %s", resp.Msg)
+		} else {
+			for _, spec := range resp.Scanners {
+				if spec == nil {
+					continue
+				}
+				if err := spec.Validate(); err != nil {
+					w.taskLog(taskId, LevelWarn, "External scan: invalid scanner %s: %v", spec.Name, err)
+					continue
+				}
+				resolved[spec.Name] = scanner.NewExternalScanner(spec)
+			}
+		}
+	}
+
+	// 保持任务配置中的顺序
+	list := make([]*scanner.ExternalScanner, 0, len(resolved))
+	for _, name := range names {
+		if s, ok := resolved[name]; ok {
+			list = append(list, s)
+			delete(resolved, name)
+		} else {
+			w.taskLog(taskId, LevelWarn, "External scan: scanner %s not found or disabled", name)
+		}
+	}
+	return list
+}
+
+// executeExternalScan 依次执行任务配置的外部扫描器
+// 返回输入中不存在的新资产和发现的漏洞，结果均已保存
+func (w *Worker) executeExternalScan(ctx context.Context, task *scheduler.TaskInfo, target string, assets []*scanner.Asset, config *scheduler.ExternalScanConfig, orgId string) (newAssets []*scanner.Asset, vuls []*scanner.Vulnerability) {
+	// 添加 panic 恢复机制
+	defer func() {
+		if r := recover(); r != nil {
+			w.taskLog(task.TaskId, LevelError, "External scan panic recovered: %v, stack: %s", r, string(getStackTrace()))
+		}
+	}()
+
+	if len(config.Scanners) == 0 {
+		w.taskLog(task.TaskId, LevelWarn, "External scan: no scanners configured")
+		return nil, nil
+	}
+
+	scanners := w.resolveExternalScanners(ctx, task.TaskId, config.Scanners)
+	if len(scanners) == 0 {
+		return nil, nil
+	}
+
+	taskLogger := func(level, format string, args ...interface{}) {
+		w.taskLog(task.TaskId, level, format, args...)
+	}
+
+	seen := make(map[string]bool, len(assets))
+	for _, asset := range assets {
+		seen[asset.Authority] = true
+	}
+
+	for _, s := range scanners {
+		if ctx.Err() != nil {
+			break
+		}
+
+		result, err := s.Scan(ctx, &scanner.ScanConfig{
+			Target:      target,
+			Targets:     ParseTargets(target),
+			Assets:      assets,
+			Options:     &scanner.ExternalScanOptions{Timeout: config.Timeout},
+			WorkspaceId: task.WorkspaceId,
+			MainTaskId:  task.MainTaskId,
+			TaskLogger:  taskLogger,
+		})
+		if err != nil {
+			w.taskLog(task.TaskId, LevelError, "External scan: %v", err)
+		}
+		if result == nil {
+			continue
+		}
+
+		// 超时或出错时仍保存已解析的结果，外部工具输出的范围外资产只记录
+		result.Assets = w.filterAssetsByScope(task.WorkspaceId, task.MainTaskId, task.TaskId, "external:"+s.Name(), result.Assets)
+		if len(result.Assets) > 0 {
+			w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, result.Assets)
+			for _, asset := range result.Assets {
+				if !seen[asset.Authority] {
+					seen[asset.Authority] = true
+					newAssets = append(newAssets, asset)
+				}
+			}
+		}
+		if len(result.Vulnerabilities) > 0 {
+			w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, result.Vulnerabilities)
+			vuls = append(vuls, result.Vulnerabilities...)
+		}
+	}
+
+	return newAssets, vuls
+}
@@ -0,0 +1,193 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cscan/pkg/geoip"
+	"cscan/scanner"
+)
+
+// geoIPRefreshInterval 与服务端同步数据集的间隔
+const geoIPRefreshInterval = 10 * time.Minute
+
+// geoIPDownloadTimeout 单个数据集的下载超时
+const geoIPDownloadTimeout = 10 * time.Minute
+
+// geoIPCache 缓存已加载的IP归属地查询器
+type geoIPCache struct {
+	mu       sync.Mutex
+	resolver *geoip.Resolver
+	datasets string // 已加载数据集的 sha256 列表，未变化时不重新加载
+	loadedAt time.Time
+}
+
+// geoIPResolver 返回IP归属地查询器，按间隔与服务端同步数据集，没有可用数据集时返回 nil
+func (w *Worker) geoIPResolver(ctx context.Context, taskId string) *geoip.Resolver {
+	if w.config.GeoIPDir == "" {
+		return nil
+	}
+
+	c := &w.geoIP
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.loadedAt.IsZero() && time.Since(c.loadedAt) < geoIPRefreshInterval {
+		return c.resolver
+	}
+	c.loadedAt = time.Now()
+
+	files, synced := w.syncGeoIPDatasets(ctx, taskId)
+	key := strings.Join(files, ",")
+	if c.resolver != nil && (!synced || key == c.datasets) {
+		return c.resolver
+	}
+
+	var readers []geoip.Reader
+	for _, f := range files {
+		r, err := geoip.Open(f)
+		if err != nil {
+			w.taskLog(taskId, LevelWarn, "GeoIP: %v", err)
+			continue
+		}
+		readers = append(readers, r)
+	}
+	// 本地手动放置的数据库文件排在服务端数据集之后
+	if local, err := geoip.OpenDir(w.config.GeoIPDir); err != nil && !os.IsNotExist(err) {
+		w.taskLog(taskId, LevelWarn, "GeoIP: %v", err)
+	} else if local.Len() > 0 {
+		readers = append(readers, local)
+	}
+
+	c.resolver = geoip.NewResolver(readers...)
+	c.datasets = key
+	if len(readers) > 0 {
+		w.taskLog(taskId, LevelInfo, "GeoIP: loaded %d datasets", len(readers))
+	}
+	return c.resolver
+}
+
+// syncGeoIPDatasets 下载服务端已启用的数据集到 GeoIPDir/datasets，删除已停用的缓存文件
+// 返回按服务端顺序排列的本地文件路径；获取列表失败时返回已有缓存文件且 synced 为 false
+func (w *Worker) syncGeoIPDatasets(ctx context.Context, taskId string) (files []string, synced bool) {
+	dir := filepath.Join(w.config.GeoIPDir, "datasets")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		w.taskLog(taskId, LevelWarn, "GeoIP: create dataset dir failed: %v", err)
+		return nil, false
+	}
+
+	resp, err := w.httpClient.GetGeoIPDatasets(ctx)
+	if err != nil || resp.Code != 0 {
+		if err == nil {
+			err = fmt.Errorf("%s", resp.Msg)
+		}
+		w.taskLog(taskId, LevelWarn, "GeoIP: get datasets failed: %v, using cached datasets", err)
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			if !e.IsDir() && geoip.DetectType(e.Name()) != "" {
+				files = append(files, filepath.Join(dir, e.Name()))
+			}
+		}
+		return files, false
+	}
+
+	keep := make(map[string]bool)
+	for _, d := range resp.Datasets {
+		ext := ".mmdb"
+		if d.Type == geoip.TypeIP2Region {
+			ext = ".xdb"
+		}
+		name := d.Sha256 + ext
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			if err := w.downloadGeoIPDataset(ctx, d, path); err != nil {
+				w.taskLog(taskId, LevelWarn, "GeoIP: download dataset %s failed: %v", d.Name, err)
+				continue
+			}
+			w.taskLog(taskId, LevelInfo, "GeoIP: downloaded dataset %s (%d bytes)", d.Name, d.Size)
+		}
+		keep[name] = true
+		files = append(files, path)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if !e.IsDir() && !keep[e.Name()] {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+	return files, true
+}
+
+// downloadGeoIPDataset 下载到临时文件并校验 sha256 后再改名，避免读到不完整的文件
+func (w *Worker) downloadGeoIPDataset(ctx context.Context, d GeoIPDatasetItem, path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	dlCtx, cancel := context.WithTimeout(ctx, geoIPDownloadTimeout)
+	defer cancel()
+	hasher := sha256.New()
+	err = w.httpClient.DownloadGeoIPDataset(dlCtx, d.Id, io.MultiWriter(tmp, hasher))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(hasher.Sum(nil)); sum != d.Sha256 {
+		return fmt.Errorf("sha256 mismatch: got %s, want %s", sum, d.Sha256)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// enrichGeo 为资产的每个IP补全国家、地区、城市、ASN和组织，host 为IP且未解析IP列表时一并补全
+func (w *Worker) enrichGeo(ctx context.Context, taskId string, assets []*scanner.Asset) {
+	resolver := w.geoIPResolver(ctx, taskId)
+	if resolver.Len() == 0 {
+		return
+	}
+
+	for _, asset := range assets {
+		if len(asset.IPV4) == 0 && len(asset.IPV6) == 0 {
+			if ip := net.ParseIP(asset.Host); ip != nil {
+				if ip.To4() != nil {
+					asset.IPV4 = []scanner.IPInfo{{IP: asset.Host}}
+				} else {
+					asset.IPV6 = []scanner.IPInfo{{IP: asset.Host}}
+				}
+			}
+		}
+		for i := range asset.IPV4 {
+			fillIPGeo(resolver, &asset.IPV4[i])
+		}
+		for i := range asset.IPV6 {
+			fillIPGeo(resolver, &asset.IPV6[i])
+		}
+	}
+}
+
+// fillIPGeo 查询单个IP，已有归属地信息时保留
+func fillIPGeo(resolver *geoip.Resolver, info *scanner.IPInfo) {
+	if info.Country != "" || info.ASN != 0 {
+		return
+	}
+	rec := resolver.Lookup(info.IP)
+	if rec == nil {
+		return
+	}
+	info.Country, info.Region, info.City, info.ASN, info.Org = rec.Country, rec.Region, rec.City, rec.ASN, rec.Org
+	if info.Location == "" {
+		info.Location = rec.Location()
+	}
+}
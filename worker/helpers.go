@@ -1,10 +1,10 @@
 package worker
 
-import "fmt"
+import "cscan/pkg/utils"
 
 func buildAuthority(host string, port int) string {
 	if port == 80 || port == 443 {
 		return host
 	}
-	return fmt.Sprintf("%s:%d", host, port)
+	return utils.BuildTargetWithPort(host, port)
 }
@@ -8,21 +8,25 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"cscan/model"
+	"cscan/pkg/utils"
+	"cscan/scanner"
 )
 
 // WorkerHTTPClient Worker HTTP 客户端
 type WorkerHTTPClient struct {
 	baseURL    string
-	installKey string
+	token      string // Worker凭证
 	httpClient *http.Client
 	workerName string
 }
 
 // NewWorkerHTTPClient 创建 Worker HTTP 客户端
-func NewWorkerHTTPClient(baseURL, installKey, workerName string) *WorkerHTTPClient {
+func NewWorkerHTTPClient(baseURL, token, workerName string) *WorkerHTTPClient {
 	return &WorkerHTTPClient{
 		baseURL:    baseURL,
-		installKey: installKey,
+		token:      token,
 		workerName: workerName,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
@@ -71,39 +75,55 @@ type IPV4Info struct {
 	IP       string `json:"ip"`
 	IPInt    uint32 `json:"ipInt"`
 	Location string `json:"location"`
+	Country  string `json:"country,omitempty"`
+	Region   string `json:"region,omitempty"`
+	City     string `json:"city,omitempty"`
+	ASN      int    `json:"asn,omitempty"`
+	Org      string `json:"org,omitempty"`
 }
 
 // IPV6Info IPv6信息
 type IPV6Info struct {
 	IP       string `json:"ip"`
 	Location string `json:"location"`
+	Country  string `json:"country,omitempty"`
+	Region   string `json:"region,omitempty"`
+	City     string `json:"city,omitempty"`
+	ASN      int    `json:"asn,omitempty"`
+	Org      string `json:"org,omitempty"`
 }
 
 // AssetDocument 资产文档
 type AssetDocument struct {
-	Authority  string     `json:"authority"`
-	Host       string     `json:"host"`
-	Port       int32      `json:"port"`
-	Category   string     `json:"category"`
-	Service    string     `json:"service"`
-	Server     string     `json:"server"`
-	Banner     string     `json:"banner"`
-	Title      string     `json:"title"`
-	App        []string   `json:"app"`
-	HttpStatus string     `json:"httpStatus"`
-	HttpHeader string     `json:"httpHeader"`
-	HttpBody   string     `json:"httpBody"`
-	Cert       string     `json:"cert"`
-	IconHash   string     `json:"iconHash"`
-	IsCdn      bool       `json:"isCdn"`
-	Cname      string     `json:"cname"`
-	IsCloud    bool       `json:"isCloud"`
-	Ipv4       []IPV4Info `json:"ipv4"`
-	Ipv6       []IPV6Info `json:"ipv6"`
-	Screenshot string     `json:"screenshot"`
-	IsHttp     bool       `json:"isHttp"`
-	Source     string     `json:"source"`
-	IconData   []byte     `json:"iconData"`
+	Authority     string            `json:"authority"`
+	Host          string            `json:"host"`
+	Port          int32             `json:"port"`
+	Transport     string            `json:"transport,omitempty"`
+	Category      string            `json:"category"`
+	Service       string            `json:"service"`
+	Server        string            `json:"server"`
+	Banner        string            `json:"banner"`
+	Title         string            `json:"title"`
+	App           []string          `json:"app"`
+	HttpStatus    string            `json:"httpStatus"`
+	HttpHeader    string            `json:"httpHeader"`
+	HttpBody      string            `json:"httpBody"`
+	Cert          string            `json:"cert"`
+	CertInfo      *scanner.CertInfo `json:"certInfo,omitempty"`
+	CDNProvider   string            `json:"cdnProvider,omitempty"`
+	CloudProvider string            `json:"cloudProvider,omitempty"`
+	WAF           string            `json:"waf,omitempty"`
+	CPE           []string          `json:"cpe,omitempty"` // 带版本应用生成的CPE，API据此匹配潜在漏洞
+	IconHash      string            `json:"iconHash"`
+	IsCdn         bool              `json:"isCdn"`
+	Cname         string            `json:"cname"`
+	IsCloud       bool              `json:"isCloud"`
+	Ipv4          []IPV4Info        `json:"ipv4"`
+	Ipv6          []IPV6Info        `json:"ipv6"`
+	Screenshot    string            `json:"screenshot"`
+	IsHttp        bool              `json:"isHttp"`
+	Source        string            `json:"source"`
+	IconData      []byte            `json:"iconData"`
 }
 
 // TaskResultReq 资产结果上报请求
@@ -167,6 +187,30 @@ type VulResultResp struct {
 	Total   int32  `json:"total"`
 }
 
+// TaskResultStreamReq 流式结果上报请求，streamId+seq 作为服务端幂等键
+type TaskResultStreamReq struct {
+	WorkspaceId string          `json:"workspaceId"`
+	MainTaskId  string          `json:"mainTaskId"`
+	TaskId      string          `json:"taskId"`
+	OrgId       string          `json:"orgId"`
+	Phase       string          `json:"phase"`
+	StreamId    string          `json:"streamId"`
+	Seq         int64           `json:"seq"`
+	Assets      []AssetDocument `json:"assets"`
+	Vuls        []VulDocument   `json:"-"` // 序列化时转换为与漏洞上报一致的格式
+}
+
+// TaskResultStreamResp 流式结果上报响应
+type TaskResultStreamResp struct {
+	Code        int    `json:"code"`
+	Msg         string `json:"msg"`
+	Success     bool   `json:"success"`
+	Duplicate   bool   `json:"duplicate"`
+	NewAsset    int32  `json:"newAsset"`
+	UpdateAsset int32  `json:"updateAsset"`
+	TotalVul    int32  `json:"totalVul"`
+}
+
 // HeartbeatReq 心跳请求
 type HeartbeatReq struct {
 	WorkerName         string  `json:"workerName"`
@@ -237,21 +281,23 @@ type FingerprintsReq struct {
 
 // FingerprintDocument 指纹文档
 type FingerprintDocument struct {
-	Id        string            `json:"id"`
-	Name      string            `json:"name"`
-	Category  string            `json:"category"`
-	Rule      string            `json:"rule"`
-	Source    string            `json:"source"`
-	Headers   map[string]string `json:"headers"`
-	Cookies   map[string]string `json:"cookies"`
-	Html      []string          `json:"html"`
-	Scripts   []string          `json:"scripts"`
-	ScriptSrc []string          `json:"scriptSrc"`
-	Meta      map[string]string `json:"meta"`
-	Css       []string          `json:"css"`
-	Url       []string          `json:"url"`
-	IsBuiltin bool              `json:"isBuiltin"`
-	Enabled   bool              `json:"enabled"`
+	Id         string                   `json:"id"`
+	Name       string                   `json:"name"`
+	Category   string                   `json:"category"`
+	Rule       string                   `json:"rule"`
+	Source     string                   `json:"source"`
+	Headers    map[string]string        `json:"headers"`
+	Cookies    map[string]string        `json:"cookies"`
+	Html       []string                 `json:"html"`
+	Scripts    []string                 `json:"scripts"`
+	ScriptSrc  []string                 `json:"scriptSrc"`
+	Meta       map[string]string        `json:"meta"`
+	Css        []string                 `json:"css"`
+	Url        []string                 `json:"url"`
+	CPE        string                   `json:"cpe,omitempty"`
+	Extractors []model.VersionExtractor `json:"extractors,omitempty"`
+	IsBuiltin  bool                     `json:"isBuiltin"`
+	Enabled    bool                     `json:"enabled"`
 }
 
 // FingerprintsResp 指纹获取响应
@@ -494,7 +540,7 @@ func (c *WorkerHTTPClient) doRequestOnce(ctx context.Context, method, path strin
 
 	// 设置请求头
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Worker-Key", c.installKey)
+	req.Header.Set("X-Worker-Token", c.token)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -508,7 +554,7 @@ func (c *WorkerHTTPClient) doRequestOnce(ctx context.Context, method, path strin
 	}
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("authentication failed: invalid install key")
+		return nil, fmt.Errorf("authentication failed: worker credential invalid or revoked")
 	}
 
 	if resp.StatusCode >= 400 {
@@ -580,56 +626,7 @@ func (c *WorkerHTTPClient) SaveVulResult(ctx context.Context, req *VulResultReq)
 	}
 
 	for _, vul := range req.Vuls {
-		item := map[string]interface{}{
-			"authority": vul.Authority,
-			"host":      vul.Host,
-			"port":      vul.Port,
-			"url":       vul.Url,
-			"pocFile":   vul.PocFile,
-			"source":    vul.Source,
-			"severity":  vul.Severity,
-			"extra":     vul.Extra,
-			"result":    vul.Result,
-			"taskId":    vul.TaskId,
-			"tags":      vul.Tags,
-		}
-		if vul.VulName != nil {
-			item["vulName"] = *vul.VulName
-		}
-		if vul.CvssScore != nil {
-			item["cvssScore"] = *vul.CvssScore
-		}
-		if vul.CveId != nil {
-			item["cveId"] = *vul.CveId
-		}
-		if vul.CweId != nil {
-			item["cweId"] = *vul.CweId
-		}
-		if vul.Remediation != nil {
-			item["remediation"] = *vul.Remediation
-		}
-		if len(vul.References) > 0 {
-			item["references"] = vul.References
-		}
-		if vul.MatcherName != nil {
-			item["matcherName"] = *vul.MatcherName
-		}
-		if len(vul.ExtractedResults) > 0 {
-			item["extractedResults"] = vul.ExtractedResults
-		}
-		if vul.CurlCommand != nil {
-			item["curlCommand"] = *vul.CurlCommand
-		}
-		if vul.Request != nil {
-			item["request"] = *vul.Request
-		}
-		if vul.Response != nil {
-			item["response"] = *vul.Response
-		}
-		if vul.ResponseTruncated != nil {
-			item["responseTruncated"] = *vul.ResponseTruncated
-		}
-		payload.Vuls = append(payload.Vuls, item)
+		payload.Vuls = append(payload.Vuls, vulPayload(vul))
 	}
 
 	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/task/vul", payload)
@@ -645,6 +642,89 @@ func (c *WorkerHTTPClient) SaveVulResult(ctx context.Context, req *VulResultReq)
 	return &resp, nil
 }
 
+// vulPayload 将漏洞文档转换为上报格式，可选字段为空时不传
+func vulPayload(vul VulDocument) map[string]interface{} {
+	item := map[string]interface{}{
+		"authority": vul.Authority,
+		"host":      vul.Host,
+		"port":      vul.Port,
+		"url":       vul.Url,
+		"pocFile":   vul.PocFile,
+		"source":    vul.Source,
+		"severity":  vul.Severity,
+		"extra":     vul.Extra,
+		"result":    vul.Result,
+		"taskId":    vul.TaskId,
+		"tags":      vul.Tags,
+	}
+	if vul.VulName != nil {
+		item["vulName"] = *vul.VulName
+	}
+	if vul.CvssScore != nil {
+		item["cvssScore"] = *vul.CvssScore
+	}
+	if vul.CveId != nil {
+		item["cveId"] = *vul.CveId
+	}
+	if vul.CweId != nil {
+		item["cweId"] = *vul.CweId
+	}
+	if vul.Remediation != nil {
+		item["remediation"] = *vul.Remediation
+	}
+	if len(vul.References) > 0 {
+		item["references"] = vul.References
+	}
+	if vul.MatcherName != nil {
+		item["matcherName"] = *vul.MatcherName
+	}
+	if len(vul.ExtractedResults) > 0 {
+		item["extractedResults"] = vul.ExtractedResults
+	}
+	if vul.CurlCommand != nil {
+		item["curlCommand"] = *vul.CurlCommand
+	}
+	if vul.Request != nil {
+		item["request"] = *vul.Request
+	}
+	if vul.Response != nil {
+		item["response"] = *vul.Response
+	}
+	if vul.ResponseTruncated != nil {
+		item["responseTruncated"] = *vul.ResponseTruncated
+	}
+	return item
+}
+
+// StreamTaskResult 流式上报一批资产和漏洞，重试时使用相同的 streamId+seq，服务端只入库一次
+func (c *WorkerHTTPClient) StreamTaskResult(ctx context.Context, req *TaskResultStreamReq) (*TaskResultStreamResp, error) {
+	payload := struct {
+		*TaskResultStreamReq
+		Vuls []map[string]interface{} `json:"vuls"`
+	}{
+		TaskResultStreamReq: req,
+		Vuls:                make([]map[string]interface{}, 0, len(req.Vuls)),
+	}
+	for _, vul := range req.Vuls {
+		payload.Vuls = append(payload.Vuls, vulPayload(vul))
+	}
+
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/task/result/stream", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskResultStreamResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+	if resp.Code != 0 {
+		return &resp, fmt.Errorf("stream result rejected: %s", resp.Msg)
+	}
+
+	return &resp, nil
+}
+
 // Heartbeat 心跳
 func (c *WorkerHTTPClient) Heartbeat(ctx context.Context, req *HeartbeatReq) (*HeartbeatResp, error) {
 	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/heartbeat", req)
@@ -860,6 +940,216 @@ func (c *WorkerHTTPClient) GetSubdomainDicts(ctx context.Context, dictIds []stri
 	return &resp, nil
 }
 
+// ==================== Bruteforce Dict ====================
+
+// BruteforceDictReq 弱口令字典获取请求
+type BruteforceDictReq struct {
+	DictIds []string `json:"dictIds"`
+}
+
+// BruteforceDictItem 弱口令字典项
+type BruteforceDictItem struct {
+	Id          string               `json:"id"`
+	Name        string               `json:"name"`
+	Service     string               `json:"service"` // 适用协议，all 表示全部协议
+	Credentials []scanner.Credential `json:"credentials"`
+}
+
+// BruteforceDictResp 弱口令字典获取响应
+type BruteforceDictResp struct {
+	Code  int                  `json:"code"`
+	Msg   string               `json:"msg"`
+	Dicts []BruteforceDictItem `json:"dicts"`
+	Count int                  `json:"count"`
+}
+
+// GetBruteforceDicts 获取弱口令字典，dictIds 为空时返回全部已启用的字典
+func (c *WorkerHTTPClient) GetBruteforceDicts(ctx context.Context, dictIds []string) (*BruteforceDictResp, error) {
+	req := &BruteforceDictReq{
+		DictIds: dictIds,
+	}
+
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/bruteforcedict", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp BruteforceDictResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ==================== External Scanners ====================
+
+// ExternalScannersReq 外部扫描器声明获取请求
+type ExternalScannersReq struct {
+	Names []string `json:"names"`
+}
+
+// ExternalScannersResp 外部扫描器声明获取响应
+type ExternalScannersResp struct {
+	Code     int                            `json:"code"`
+	Msg      string                         `json:"msg"`
+	Scanners []*scanner.ExternalScannerSpec `json:"scanners"`
+}
+
+// GetExternalScanners 获取外部扫描器声明（只返回服务端已启用的）
+func (c *WorkerHTTPClient) GetExternalScanners(ctx context.Context, names []string) (*ExternalScannersResp, error) {
+	req := &ExternalScannersReq{
+		Names: names,
+	}
+
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/externalscanners", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ExternalScannersResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ==================== Takeover Signatures ====================
+
+// TakeoverSignaturesResp 子域接管签名获取响应
+type TakeoverSignaturesResp struct {
+	Code       int                          `json:"code"`
+	Msg        string                       `json:"msg"`
+	Signatures []*scanner.TakeoverSignature `json:"signatures"`
+}
+
+// GetTakeoverSignatures 获取已启用的子域接管签名
+func (c *WorkerHTTPClient) GetTakeoverSignatures(ctx context.Context) (*TakeoverSignaturesResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/takeover", struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TakeoverSignaturesResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// SecretRulesResp 爬虫敏感信息规则获取响应
+type SecretRulesResp struct {
+	Code  int                   `json:"code"`
+	Msg   string                `json:"msg"`
+	Rules []*scanner.SecretRule `json:"rules"`
+}
+
+// GetSecretRules 获取已启用的爬虫敏感信息规则
+func (c *WorkerHTTPClient) GetSecretRules(ctx context.Context) (*SecretRulesResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/secretrules", struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp SecretRulesResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// CDNProvidersResp CDN识别规则获取响应
+type CDNProvidersResp struct {
+	Code      int                    `json:"code"`
+	Msg       string                 `json:"msg"`
+	Providers []*scanner.CDNProvider `json:"providers"`
+}
+
+// GetCDNProviders 获取已启用的CDN识别规则
+func (c *WorkerHTTPClient) GetCDNProviders(ctx context.Context) (*CDNProvidersResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/cdn", struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CDNProvidersResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// GeoIPDatasetItem IP归属地数据集描述
+type GeoIPDatasetItem struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"` // mmdb/ip2region
+	FileName string `json:"fileName"`
+	Size     int64  `json:"size"`
+	Sha256   string `json:"sha256"`
+}
+
+// GeoIPDatasetsResp IP归属地数据集列表响应
+type GeoIPDatasetsResp struct {
+	Code     int                `json:"code"`
+	Msg      string             `json:"msg"`
+	Datasets []GeoIPDatasetItem `json:"datasets"`
+}
+
+// GetGeoIPDatasets 获取已启用的IP归属地数据集列表
+func (c *WorkerHTTPClient) GetGeoIPDatasets(ctx context.Context) (*GeoIPDatasetsResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/geoip", struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp GeoIPDatasetsResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// DownloadGeoIPDataset 下载IP归属地数据集文件写入 dst
+// 数据集文件较大，不使用默认的30秒超时，由调用方通过 ctx 控制
+func (c *WorkerHTTPClient) DownloadGeoIPDataset(ctx context.Context, id string, dst io.Writer) error {
+	jsonData, err := json.Marshal(map[string]string{"id": id})
+	if err != nil {
+		return fmt.Errorf("marshal request body failed: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/worker/config/geoip/download", bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("create request failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Worker-Token", c.token)
+
+	client := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("authentication failed: worker credential invalid or revoked")
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if _, err := io.Copy(dst, resp.Body); err != nil {
+		return fmt.Errorf("read response body failed: %w", err)
+	}
+	return nil
+}
+
 // ==================== Active Fingerprints ====================
 
 // ActiveFingerprintsReq 主动指纹获取请求
@@ -993,6 +1283,41 @@ func (c *WorkerHTTPClient) GetTaskControlSignals(ctx context.Context, taskIds []
 	return &resp, nil
 }
 
+// ==================== Task New Assets ====================
+
+// TaskNewAssetsReq 本次任务新发现资产查询请求
+type TaskNewAssetsReq struct {
+	WorkspaceId string `json:"workspaceId"`
+	MainTaskId  string `json:"mainTaskId"`
+}
+
+// TaskNewAssetsResp 本次任务新发现资产查询响应
+type TaskNewAssetsResp struct {
+	Code        int      `json:"code"`
+	Msg         string   `json:"msg"`
+	Authorities []string `json:"authorities"`
+}
+
+// GetTaskNewAssets 查询在本次任务中首次发现的资产
+func (c *WorkerHTTPClient) GetTaskNewAssets(ctx context.Context, workspaceId, mainTaskId string) (*TaskNewAssetsResp, error) {
+	req := &TaskNewAssetsReq{
+		WorkspaceId: workspaceId,
+		MainTaskId:  mainTaskId,
+	}
+
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/task/newassets", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskNewAssetsResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
 // ==================== Dir Scan Result ====================
 
 // DirScanResultDocument 目录扫描结果文档
@@ -1042,6 +1367,52 @@ func (c *WorkerHTTPClient) SaveDirScanResult(ctx context.Context, req *DirScanRe
 	return &resp, nil
 }
 
+// ==================== DNS Record Result ====================
+
+// DNSRecordResultReq DNS记录上报请求
+type DNSRecordResultReq struct {
+	WorkspaceId string               `json:"workspaceId"`
+	MainTaskId  string               `json:"mainTaskId"`
+	Records     []*scanner.DNSRecord `json:"records"`
+}
+
+// SaveDNSRecords 保存DNS记录到工作空间的DNS历史
+func (c *WorkerHTTPClient) SaveDNSRecords(ctx context.Context, req *DNSRecordResultReq) (*TaskUpdateResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/task/dnsrecord", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskUpdateResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// URLAssetResultReq 爬虫URL端点上报请求
+type URLAssetResultReq struct {
+	WorkspaceId string                   `json:"workspaceId"`
+	MainTaskId  string                   `json:"mainTaskId"`
+	Endpoints   []*scanner.CrawlEndpoint `json:"endpoints"`
+}
+
+// SaveURLAssets 保存爬虫发现的端点到工作空间的URL资产
+func (c *WorkerHTTPClient) SaveURLAssets(ctx context.Context, req *URLAssetResultReq) (*TaskUpdateResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/task/urlasset", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskUpdateResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
 // BlacklistRulesResp 黑名单规则响应
 type BlacklistRulesResp struct {
 	Code  int      `json:"code"`
@@ -1064,6 +1435,67 @@ func (c *WorkerHTTPClient) GetBlacklistRules(ctx context.Context) (*BlacklistRul
 	return &resp, nil
 }
 
+// ==================== Scan Scope ====================
+
+// ScanScopeReq 扫描范围获取请求
+type ScanScopeReq struct {
+	WorkspaceId string `json:"workspaceId"`
+}
+
+// ScanScopeResp 扫描范围获取响应，Enabled 为 false 时不限制目标
+type ScanScopeResp struct {
+	Code    int               `json:"code"`
+	Msg     string            `json:"msg"`
+	Enabled bool              `json:"enabled"`
+	Rules   *utils.ScopeRules `json:"rules,omitempty"`
+}
+
+// GetScanScope 获取工作空间扫描范围
+func (c *WorkerHTTPClient) GetScanScope(ctx context.Context, workspaceId string) (*ScanScopeResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/scope", &ScanScopeReq{WorkspaceId: workspaceId})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ScanScopeResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// OutOfScopeItem 范围外目标
+type OutOfScopeItem struct {
+	Target string `json:"target"`
+	Host   string `json:"host"`
+	Phase  string `json:"phase"`
+	Reason string `json:"reason"`
+}
+
+// OutOfScopeReq 范围外目标上报请求
+type OutOfScopeReq struct {
+	WorkspaceId string            `json:"workspaceId"`
+	MainTaskId  string            `json:"mainTaskId"`
+	TaskId      string            `json:"taskId"`
+	Items       []*OutOfScopeItem `json:"items"`
+}
+
+// ReportOutOfScope 上报任务中遇到的范围外目标
+func (c *WorkerHTTPClient) ReportOutOfScope(ctx context.Context, req *OutOfScopeReq) (*TaskUpdateResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/task/outofscope", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskUpdateResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
 // ==================== Task Recovery ====================
 
 // TaskRecoveryReq 任务恢复请求
@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"cscan/pkg/utils"
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// appendIPv6Targets 端口扫描启用IPv6时，将域名目标的AAAA解析结果及子域名资产中的IPv6地址追加到扫描目标
+// 追加的地址与任务目标一样经过黑名单、排除目标、扫描范围和CDN边缘节点过滤
+func (w *Worker) appendIPv6Targets(ctx context.Context, task *scheduler.TaskInfo, targets []string, assets []*scanner.Asset, config *scheduler.PortScanConfig, cdn *scheduler.CDNConfig) []string {
+	if config == nil || !config.IPv6 {
+		return targets
+	}
+
+	seen := make(map[string]bool, len(targets))
+	for _, t := range targets {
+		seen[utils.NormalizeHost(t)] = true
+	}
+	var added []string
+	source := make(map[string]string) // IPv6地址 -> 解析出该地址的域名
+	add := func(ip, host string) {
+		ip = utils.NormalizeHost(ip)
+		if !utils.IsIPv6(ip) || seen[ip] {
+			return
+		}
+		seen[ip] = true
+		source[ip] = host
+		added = append(added, ip)
+	}
+
+	// 子域名扫描阶段已解析的AAAA记录
+	for _, asset := range assets {
+		for _, ip := range asset.IPV6 {
+			add(ip.IP, asset.Host)
+		}
+	}
+
+	// 直接下发的域名目标补充解析AAAA
+	resolver := &net.Resolver{}
+	for _, t := range targets {
+		host := utils.ParseTarget(t).Host
+		if host == "" || net.ParseIP(host) != nil || strings.Contains(host, "/") {
+			continue
+		}
+		lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ips, err := resolver.LookupIP(lookupCtx, "ip6", host)
+		cancel()
+		if err != nil {
+			continue
+		}
+		for _, ip := range ips {
+			add(ip.String(), host)
+		}
+	}
+
+	added = w.filterIPv6Targets(ctx, task, added, source, config, cdn)
+	if len(added) > 0 {
+		w.taskLog(task.TaskId, LevelInfo, "Port scan: added %d IPv6 targets from AAAA records", len(added))
+	}
+	return append(targets, added...)
+}
+
+// filterIPv6Targets 对AAAA记录追加的地址应用黑名单、端口扫描排除目标、扫描范围和CDN过滤
+// 范围外的地址上报记录，source 为解析出该地址的域名
+func (w *Worker) filterIPv6Targets(ctx context.Context, task *scheduler.TaskInfo, ips []string, source map[string]string, config *scheduler.PortScanConfig, cdn *scheduler.CDNConfig) []string {
+	if len(ips) == 0 {
+		return ips
+	}
+
+	if blacklistMatcher := w.getBlacklistMatcher(ctx, task.TaskId); blacklistMatcher != nil && !blacklistMatcher.IsEmpty() {
+		if skipped := blacklistMatcher.GetBlacklistedTargets(ips); len(skipped) > 0 {
+			w.taskLog(task.TaskId, LevelInfo, "Blacklist: filtered %d IPv6 targets", len(skipped))
+			ips = blacklistMatcher.FilterTargets(ips)
+		}
+	}
+
+	if excludeMatcher := utils.NewExcludeHostsMatcher(config.ExcludeHosts); excludeMatcher != nil && !excludeMatcher.IsEmpty() {
+		if skipped := excludeMatcher.GetBlacklistedTargets(ips); len(skipped) > 0 {
+			w.taskLog(task.TaskId, LevelInfo, "ExcludeHosts: filtered %d IPv6 targets", len(skipped))
+			ips = excludeMatcher.FilterTargets(ips)
+		}
+	}
+
+	if matcher := w.scanScopeOf(task.WorkspaceId); !matcher.IsEmpty() && len(ips) > 0 {
+		inScope, outOfScope := matcher.FilterTargets(ips)
+		if len(outOfScope) > 0 {
+			items := make([]*OutOfScopeItem, 0, len(outOfScope))
+			for _, ip := range outOfScope {
+				items = append(items, &OutOfScopeItem{Target: ip, Host: source[ip], Phase: "portscan", Reason: "IPv6 address not in scope"})
+			}
+			w.taskLog(task.TaskId, LevelInfo, "Scope: %d IPv6 targets out of scope, recorded and skipped", len(outOfScope))
+			w.reportOutOfScope(task.WorkspaceId, task.MainTaskId, task.TaskId, items)
+		}
+		ips = inScope
+	}
+
+	return w.skipCDNTargets(ctx, task, ips, cdn, config)
+}
@@ -6,35 +6,41 @@ import "cscan/scanner"
 // Used to consolidate mapping logic between result_sink.go and worker.go
 func ToAssetDocument(asset *scanner.Asset) AssetDocument {
 	doc := AssetDocument{
-		Authority:  asset.Authority,
-		Host:       asset.Host,
-		Port:       int32(asset.Port),
-		Category:   asset.Category,
-		Service:    asset.Service,
-		Title:      asset.Title,
-		App:        asset.App,
-		HttpStatus: asset.HttpStatus,
-		HttpHeader: asset.HttpHeader,
-		HttpBody:   asset.HttpBody,
-		Cert:       asset.Cert,
-		IconHash:   asset.IconHash,
-		IconData:   asset.IconData,
-		Screenshot: asset.Screenshot,
-		Server:     asset.Server,
-		Banner:     asset.Banner,
-		IsHttp:     asset.IsHTTP,
-		Cname:      asset.CName,
-		IsCdn:      asset.IsCDN,
-		IsCloud:    asset.IsCloud,
-		Source:     asset.Source,
+		Authority:     asset.Authority,
+		Host:          asset.Host,
+		Port:          int32(asset.Port),
+		Transport:     asset.Transport,
+		Category:      asset.Category,
+		Service:       asset.Service,
+		Title:         asset.Title,
+		App:           asset.App,
+		HttpStatus:    asset.HttpStatus,
+		HttpHeader:    asset.HttpHeader,
+		HttpBody:      asset.HttpBody,
+		Cert:          asset.Cert,
+		CertInfo:      asset.CertInfo,
+		IconHash:      asset.IconHash,
+		IconData:      asset.IconData,
+		Screenshot:    asset.Screenshot,
+		Server:        asset.Server,
+		Banner:        asset.Banner,
+		IsHttp:        asset.IsHTTP,
+		Cname:         asset.CName,
+		IsCdn:         asset.IsCDN,
+		IsCloud:       asset.IsCloud,
+		CDNProvider:   asset.CDNProvider,
+		CloudProvider: asset.CloudProvider,
+		WAF:           asset.WAF,
+		CPE:           asset.CPE,
+		Source:        asset.Source,
 	}
 
 	for _, ip := range asset.IPV4 {
-		doc.Ipv4 = append(doc.Ipv4, IPV4Info{IP: ip.IP, Location: ip.Location})
+		doc.Ipv4 = append(doc.Ipv4, IPV4Info{IP: ip.IP, Location: ip.Location, Country: ip.Country, Region: ip.Region, City: ip.City, ASN: ip.ASN, Org: ip.Org})
 	}
 
 	for _, ip := range asset.IPV6 {
-		doc.Ipv6 = append(doc.Ipv6, IPV6Info{IP: ip.IP, Location: ip.Location})
+		doc.Ipv6 = append(doc.Ipv6, IPV6Info{IP: ip.IP, Location: ip.Location, Country: ip.Country, Region: ip.Region, City: ip.City, ASN: ip.ASN, Org: ip.Org})
 	}
 
 	return doc
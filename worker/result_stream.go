@@ -0,0 +1,288 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+const (
+	// resultStreamBatchSize 缓冲的资产和漏洞达到该数量时立即上报，不超过服务端单批上限
+	resultStreamBatchSize = 50
+	// resultStreamInterval 缓冲未满时的最长上报间隔
+	resultStreamInterval = 3 * time.Second
+)
+
+// resultBatch 一个待上报批次，失败后以相同序号重试
+type resultBatch struct {
+	seq    int64
+	assets []AssetDocument
+	vuls   []VulDocument
+}
+
+// ResultStream 扫描过程中实时上报资产和漏洞
+// 结果先进入缓冲区，按数量或时间分批发送到 /worker/task/result/stream，
+// 每批带递增序号，服务端按 streamId+seq 幂等入库，任务中途停止或崩溃时已上报的结果不会丢失；
+// 关闭时仍未上报成功的批次改用批量接口保存
+type ResultStream struct {
+	w        *Worker
+	task     *scheduler.TaskInfo
+	orgId    string
+	phase    string
+	streamId string
+	prepare  func(assets []*scanner.Asset) // 资产发送前的补充处理，如CDN识别
+
+	mu       sync.Mutex
+	assets   []*scanner.Asset
+	vuls     []*scanner.Vulnerability
+	added    map[*scanner.Asset]bool // 已加入的资产，同一资产只上报一次
+	accepted []*scanner.Asset        // 通过范围过滤的资产
+	seq      int64
+	pending  []*resultBatch // 发送失败等待重试的批次
+
+	sendMu     sync.Mutex // 保证批次按序号顺序发送
+	sentAssets int
+	sentVuls   int
+
+	flushChan chan struct{}
+	stopChan  chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newResultStream 创建结果流并启动后台上报协程，使用完毕必须调用 Close
+// prepare 可为空，在资产通过范围过滤、补全归属地后、发送前调用
+func (w *Worker) newResultStream(task *scheduler.TaskInfo, orgId, phase string, prepare func(assets []*scanner.Asset)) *ResultStream {
+	s := &ResultStream{
+		w:         w,
+		task:      task,
+		orgId:     orgId,
+		phase:     phase,
+		streamId:  fmt.Sprintf("%s-%s-%d", task.TaskId, phase, time.Now().UnixNano()),
+		prepare:   prepare,
+		added:     make(map[*scanner.Asset]bool),
+		flushChan: make(chan struct{}, 1),
+		stopChan:  make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+// AddAsset 添加资产到缓冲区，已添加过的资产忽略
+// 扫描器实时回调的资产和阶段结束时补充的资产可以重复添加，不会重复上报
+func (s *ResultStream) AddAsset(asset *scanner.Asset) {
+	s.mu.Lock()
+	if s.added[asset] {
+		s.mu.Unlock()
+		return
+	}
+	s.added[asset] = true
+	s.assets = append(s.assets, asset)
+	full := len(s.assets)+len(s.vuls) >= resultStreamBatchSize
+	s.mu.Unlock()
+	if full {
+		s.notify()
+	}
+}
+
+// AddOpenPort 端口发现回调，标记HTTP服务后写入缓冲区
+func (s *ResultStream) AddOpenPort(asset *scanner.Asset) {
+	asset.IsHTTP = asset.Transport != scanner.TransportUDP && scanner.IsHTTPService(asset.Service, asset.Port)
+	s.AddAsset(asset)
+}
+
+// AddVul 添加漏洞到缓冲区，漏洞较少且重要，立即触发上报
+func (s *ResultStream) AddVul(vul *scanner.Vulnerability) {
+	s.mu.Lock()
+	s.vuls = append(s.vuls, vul)
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Close 停止后台协程并上报剩余结果，返回已成功上报的资产和漏洞数量
+// 重试后仍失败的批次改用批量接口保存，避免结果丢失
+func (s *ResultStream) Close() (int, int) {
+	s.closeOnce.Do(func() {
+		close(s.stopChan)
+		<-s.done
+		s.flush()
+
+		s.sendMu.Lock()
+		defer s.sendMu.Unlock()
+		s.mu.Lock()
+		pending := s.pending
+		s.pending = nil
+		s.mu.Unlock()
+		if len(pending) == 0 {
+			return
+		}
+		s.w.taskLog(s.task.TaskId, LevelWarn, "Result stream [%s]: %d batches failed to stream, falling back to bulk save", s.phase, len(pending))
+		dropped := 0
+		for _, batch := range pending {
+			if err := s.saveBulk(batch); err != nil {
+				s.w.taskLog(s.task.TaskId, LevelError, "Result stream [%s]: batch %d bulk save failed, %d assets and %d vuls lost: %v",
+					s.phase, batch.seq, len(batch.assets), len(batch.vuls), err)
+				dropped++
+			}
+		}
+		if dropped > 0 {
+			s.w.taskLog(s.task.TaskId, LevelError, "Result stream [%s]: %d batches failed to upload", s.phase, dropped)
+		}
+	})
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.sentAssets, s.sentVuls
+}
+
+// Assets 返回通过范围过滤的资产，Close 之后调用可获得完整结果
+func (s *ResultStream) Assets() []*scanner.Asset {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+func (s *ResultStream) notify() {
+	select {
+	case s.flushChan <- struct{}{}:
+	default:
+	}
+}
+
+func (s *ResultStream) loop() {
+	defer close(s.done)
+	ticker := time.NewTicker(resultStreamInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-s.flushChan:
+			s.flush()
+		case <-ticker.C:
+			s.flush()
+		}
+	}
+}
+
+// flush 将缓冲区切分为批次并按顺序发送，失败的批次保留到下次重试
+func (s *ResultStream) flush() {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+
+	s.mu.Lock()
+	assets, vuls := s.assets, s.vuls
+	s.assets, s.vuls = nil, nil
+	s.mu.Unlock()
+
+	// 范围外的资产只记录不上报，归属地在发送前补全
+	if len(assets) > 0 {
+		assets = s.w.filterAssetsByScope(s.task.WorkspaceId, s.task.MainTaskId, s.task.TaskId, s.phase, assets)
+		s.w.enrichGeo(s.w.ctx, s.task.TaskId, assets)
+		if s.prepare != nil && len(assets) > 0 {
+			s.prepare(assets)
+		}
+	}
+
+	s.mu.Lock()
+	s.accepted = append(s.accepted, assets...)
+	for len(assets) > 0 || len(vuls) > 0 {
+		batch := &resultBatch{}
+		for len(assets) > 0 && len(batch.assets) < resultStreamBatchSize {
+			batch.assets = append(batch.assets, toAssetDocument(assets[0]))
+			assets = assets[1:]
+		}
+		for len(vuls) > 0 && len(batch.assets)+len(batch.vuls) < resultStreamBatchSize {
+			batch.vuls = append(batch.vuls, ToVulDocument(vuls[0], s.task.MainTaskId))
+			vuls = vuls[1:]
+		}
+		s.seq++
+		batch.seq = s.seq
+		s.pending = append(s.pending, batch)
+	}
+	pending := s.pending
+	s.mu.Unlock()
+
+	sent := 0
+	for _, batch := range pending {
+		if err := s.send(batch); err != nil {
+			s.w.taskLog(s.task.TaskId, LevelWarn, "Result stream [%s]: batch %d upload failed, will retry: %v", s.phase, batch.seq, err)
+			break
+		}
+		sent++
+	}
+
+	s.mu.Lock()
+	s.pending = s.pending[sent:]
+	s.mu.Unlock()
+}
+
+// send 发送单个批次，使用独立超时上下文，任务取消后仍能完成上报
+func (s *ResultStream) send(batch *resultBatch) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := s.w.httpClient.StreamTaskResult(ctx, &TaskResultStreamReq{
+		WorkspaceId: s.task.WorkspaceId,
+		MainTaskId:  s.task.MainTaskId,
+		TaskId:      s.task.TaskId,
+		OrgId:       s.orgId,
+		Phase:       s.phase,
+		StreamId:    s.streamId,
+		Seq:         batch.seq,
+		Assets:      batch.assets,
+		Vuls:        batch.vuls,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sentAssets += len(batch.assets)
+	s.sentVuls += len(batch.vuls)
+	s.w.taskLog(s.task.TaskId, LevelDebug, "Result stream [%s]: batch %d uploaded, assets=%d (new=%d), vuls=%d, duplicate=%v",
+		s.phase, batch.seq, len(batch.assets), resp.NewAsset, len(batch.vuls), resp.Duplicate)
+	return nil
+}
+
+// saveBulk 使用批量接口保存流式上报失败的批次
+func (s *ResultStream) saveBulk(batch *resultBatch) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if len(batch.assets) > 0 {
+		resp, err := s.w.httpClient.SaveTaskResult(ctx, &TaskResultReq{
+			WorkspaceId: s.task.WorkspaceId,
+			MainTaskId:  s.task.MainTaskId,
+			OrgId:       s.orgId,
+			Assets:      batch.assets,
+		})
+		if err != nil {
+			return err
+		}
+		if resp.Code != 0 {
+			return fmt.Errorf("%s", resp.Msg)
+		}
+		s.sentAssets += len(batch.assets)
+		// 资产已保存，漏洞保存失败时不再重复上报资产
+		batch.assets = nil
+	}
+	if len(batch.vuls) > 0 {
+		resp, err := s.w.httpClient.SaveVulResult(ctx, &VulResultReq{
+			WorkspaceId: s.task.WorkspaceId,
+			MainTaskId:  s.task.MainTaskId,
+			Vuls:        batch.vuls,
+		})
+		if err != nil {
+			return err
+		}
+		if resp.Code != 0 {
+			return fmt.Errorf("%s", resp.Msg)
+		}
+		s.sentVuls += len(batch.vuls)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// streamCall 服务端收到的一次上报
+type streamCall struct {
+	Path     string
+	StreamId string            `json:"streamId"`
+	Seq      int64             `json:"seq"`
+	Assets   []json.RawMessage `json:"assets"`
+	Vuls     []json.RawMessage `json:"vuls"`
+}
+
+// fakeResultServer 记录上报请求，reject 返回 true 的流式批次按服务端入库中处理（409）
+type fakeResultServer struct {
+	mu     sync.Mutex
+	calls  []streamCall
+	reject func(call streamCall) bool
+}
+
+func (f *fakeResultServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var call streamCall
+	json.NewDecoder(r.Body).Decode(&call)
+	call.Path = r.URL.Path
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	reject := f.reject != nil && call.Path == "/api/v1/worker/task/result/stream" && f.reject(call)
+	f.mu.Unlock()
+	if reject {
+		fmt.Fprint(w, `{"code":409,"msg":"批次正在入库，请稍后重试"}`)
+		return
+	}
+	fmt.Fprint(w, `{"code":0,"msg":"success","success":true}`)
+}
+
+func (f *fakeResultServer) callsTo(path string) []streamCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []streamCall
+	for _, c := range f.calls {
+		if c.Path == path {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// newTestResultStream 创建不启动后台协程的结果流，由测试直接调用 flush 和 Close
+func newTestResultStream(t *testing.T, f *fakeResultServer) *ResultStream {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	w := &Worker{
+		ctx:        context.Background(),
+		config:     WorkerConfig{Name: "test-worker"},
+		httpClient: NewWorkerHTTPClient(srv.URL, "csw_test", "test-worker"),
+	}
+	done := make(chan struct{})
+	close(done)
+	return &ResultStream{
+		w:         w,
+		task:      &scheduler.TaskInfo{TaskId: "task-1", MainTaskId: "main-1", WorkspaceId: "ws-1"},
+		phase:     "portscan",
+		streamId:  "task-1-portscan-1",
+		added:     make(map[*scanner.Asset]bool),
+		flushChan: make(chan struct{}, 1),
+		stopChan:  make(chan struct{}),
+		done:      done,
+	}
+}
+
+func testAsset(i int) *scanner.Asset {
+	host := fmt.Sprintf("10.0.%d.%d", i/250, i%250+1)
+	return &scanner.Asset{Authority: host + ":80", Host: host, Port: 80}
+}
+
+func TestResultStreamFlushBatches(t *testing.T) {
+	f := &fakeResultServer{}
+	s := newTestResultStream(t, f)
+	for i := 0; i < 2*resultStreamBatchSize+10; i++ {
+		s.AddAsset(testAsset(i))
+	}
+	s.AddVul(&scanner.Vulnerability{Authority: "10.0.0.1:80", Host: "10.0.0.1", Port: 80, PocFile: "test"})
+	s.flush()
+
+	calls := f.callsTo("/api/v1/worker/task/result/stream")
+	if len(calls) != 3 {
+		t.Fatalf("got %d batches, want 3", len(calls))
+	}
+	wantAssets := []int{resultStreamBatchSize, resultStreamBatchSize, 10}
+	wantVuls := []int{0, 0, 1}
+	for i, c := range calls {
+		if c.Seq != int64(i+1) || c.StreamId != s.streamId {
+			t.Errorf("batch %d: stream=%s seq=%d, want %s/%d", i, c.StreamId, c.Seq, s.streamId, i+1)
+		}
+		if len(c.Assets) != wantAssets[i] || len(c.Vuls) != wantVuls[i] {
+			t.Errorf("batch %d: assets=%d vuls=%d, want %d/%d", i, len(c.Assets), len(c.Vuls), wantAssets[i], wantVuls[i])
+		}
+	}
+	if assets, vuls := s.Close(); assets != 2*resultStreamBatchSize+10 || vuls != 1 {
+		t.Errorf("Close() = %d, %d", assets, vuls)
+	}
+	if n := len(s.Assets()); n != 2*resultStreamBatchSize+10 {
+		t.Errorf("Assets() returned %d assets", n)
+	}
+}
+
+func TestResultStreamDeduplicatesAssets(t *testing.T) {
+	f := &fakeResultServer{}
+	s := newTestResultStream(t, f)
+	asset := testAsset(0)
+	s.AddAsset(asset)
+	s.flush()
+	s.AddAsset(asset)
+	s.AddOpenPort(asset)
+	s.Close()
+
+	calls := f.callsTo("/api/v1/worker/task/result/stream")
+	if len(calls) != 1 || len(calls[0].Assets) != 1 {
+		t.Errorf("asset uploaded %d times, want once", len(calls))
+	}
+}
+
+func TestResultStreamRetriesWithSameSeq(t *testing.T) {
+	rejected := false
+	f := &fakeResultServer{reject: func(c streamCall) bool {
+		// 第一批第一次发送时服务端仍在入库
+		if c.Seq == 1 && !rejected {
+			rejected = true
+			return true
+		}
+		return false
+	}}
+	s := newTestResultStream(t, f)
+	s.AddAsset(testAsset(0))
+	s.flush()
+	s.AddAsset(testAsset(1))
+	s.flush()
+
+	calls := f.callsTo("/api/v1/worker/task/result/stream")
+	var seqs []int64
+	for _, c := range calls {
+		seqs = append(seqs, c.Seq)
+	}
+	if len(seqs) != 3 || seqs[0] != 1 || seqs[1] != 1 || seqs[2] != 2 {
+		t.Errorf("sent seqs = %v, want [1 1 2]", seqs)
+	}
+	if assets, _ := s.Close(); assets != 2 {
+		t.Errorf("sent assets = %d, want 2", assets)
+	}
+	if n := len(f.callsTo("/api/v1/worker/task/result")); n != 0 {
+		t.Errorf("bulk save called %d times after successful retry", n)
+	}
+}
+
+func TestResultStreamBulkFallback(t *testing.T) {
+	f := &fakeResultServer{reject: func(streamCall) bool { return true }}
+	s := newTestResultStream(t, f)
+	s.AddAsset(testAsset(0))
+	s.AddAsset(testAsset(1))
+	s.AddVul(&scanner.Vulnerability{Authority: "10.0.0.1:80", Host: "10.0.0.1", Port: 80, PocFile: "test"})
+	s.flush()
+
+	assets, vuls := s.Close()
+	if assets != 2 || vuls != 1 {
+		t.Errorf("Close() = %d, %d, want 2, 1", assets, vuls)
+	}
+	bulkAssets := f.callsTo("/api/v1/worker/task/result")
+	bulkVuls := f.callsTo("/api/v1/worker/task/vul")
+	if len(bulkAssets) != 1 || len(bulkAssets[0].Assets) != 2 {
+		t.Errorf("bulk asset saves = %+v", bulkAssets)
+	}
+	if len(bulkVuls) != 1 || len(bulkVuls[0].Vuls) != 1 {
+		t.Errorf("bulk vul saves = %+v", bulkVuls)
+	}
+}
@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cscan/pkg/utils"
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// loadScanScope 获取工作空间扫描范围并缓存，未启用时返回 nil
+// 获取失败时返回错误，调用方不应在范围未知的情况下继续扫描
+func (w *Worker) loadScanScope(ctx context.Context, workspaceId string) (*utils.ScopeMatcher, error) {
+	resp, err := w.httpClient.GetScanScope(ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("%s", resp.Msg)
+	}
+
+	var matcher *utils.ScopeMatcher
+	if resp.Enabled && resp.Rules != nil {
+		if matcher, err = utils.NewScopeMatcher(resp.Rules); err != nil {
+			return nil, err
+		}
+	}
+	w.scanScopes.Store(workspaceId, matcher)
+	return matcher, nil
+}
+
+// scanScopeOf 获取已缓存的工作空间扫描范围，未启用或未加载时返回 nil
+func (w *Worker) scanScopeOf(workspaceId string) *utils.ScopeMatcher {
+	if v, ok := w.scanScopes.Load(workspaceId); ok {
+		matcher, _ := v.(*utils.ScopeMatcher)
+		return matcher
+	}
+	return nil
+}
+
+// filterTargetsByScope 过滤范围外的任务目标并记录
+func (w *Worker) filterTargetsByScope(task *scheduler.TaskInfo, targets []string) []string {
+	matcher := w.scanScopeOf(task.WorkspaceId)
+	if matcher.IsEmpty() {
+		return targets
+	}
+
+	inScope, outOfScope := matcher.FilterTargets(targets)
+	if len(outOfScope) > 0 {
+		items := make([]*OutOfScopeItem, 0, len(outOfScope))
+		for _, t := range outOfScope {
+			items = append(items, &OutOfScopeItem{Target: t, Host: t, Phase: "target", Reason: "target not in scope"})
+			w.taskLog(task.TaskId, LevelDebug, "Scope: skipped target: %s", t)
+		}
+		w.taskLog(task.TaskId, LevelInfo, "Scope: %d/%d targets out of scope", len(outOfScope), len(targets))
+		w.reportOutOfScope(task.WorkspaceId, task.MainTaskId, task.TaskId, items)
+	}
+	return inScope
+}
+
+// filterAssetsByScope 过滤范围外的资产并记录，phase 为发现该资产的阶段
+// 子域名枚举、CNAME解析、爬虫和外部扫描器在任务中途发现的资产都经过此处
+func (w *Worker) filterAssetsByScope(workspaceId, mainTaskId, taskId, phase string, assets []*scanner.Asset) []*scanner.Asset {
+	matcher := w.scanScopeOf(workspaceId)
+	if matcher.IsEmpty() || len(assets) == 0 {
+		return assets
+	}
+
+	filtered := make([]*scanner.Asset, 0, len(assets))
+	var items []*OutOfScopeItem
+	for _, asset := range assets {
+		ips := make([]string, 0, len(asset.IPV4)+len(asset.IPV6))
+		for _, ip := range asset.IPV4 {
+			ips = append(ips, ip.IP)
+		}
+		for _, ip := range asset.IPV6 {
+			ips = append(ips, ip.IP)
+		}
+		if matcher.AssetInScope(asset.Host, ips, asset.CName) {
+			filtered = append(filtered, asset)
+			continue
+		}
+
+		target := asset.Authority
+		if target == "" {
+			target = asset.Host
+		}
+		reason := "host not in scope"
+		if matcher.InScope(asset.Host) {
+			reason = "resolved IP or CNAME not in scope"
+		}
+		items = append(items, &OutOfScopeItem{Target: target, Host: asset.Host, Phase: phase, Reason: reason})
+	}
+
+	if len(items) > 0 {
+		w.taskLog(taskId, LevelInfo, "Scope: %d assets out of scope (%s), recorded and skipped", len(items), phase)
+		w.reportOutOfScope(workspaceId, mainTaskId, taskId, items)
+	}
+	return filtered
+}
+
+// reportOutOfScope 上报范围外目标
+func (w *Worker) reportOutOfScope(workspaceId, mainTaskId, taskId string, items []*OutOfScopeItem) {
+	// 使用新的context，任务被取消时仍能完成记录
+	reportCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := w.httpClient.ReportOutOfScope(reportCtx, &OutOfScopeReq{
+		WorkspaceId: workspaceId,
+		MainTaskId:  mainTaskId,
+		TaskId:      taskId,
+		Items:       items,
+	})
+	if err != nil {
+		w.taskLog(taskId, LevelWarn, "Scope: report out-of-scope targets failed: %v", err)
+	} else if resp.Code != 0 {
+		w.taskLog(taskId, LevelWarn, "Scope: report out-of-scope targets failed: %s", resp.Msg)
+	}
+}
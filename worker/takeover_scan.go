@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// loadTakeoverSignatures 获取服务端已启用的接管签名，获取失败时使用内置签名
+func (w *Worker) loadTakeoverSignatures(ctx context.Context, taskId string) []*scanner.TakeoverSignature {
+	resp, err := w.httpClient.GetTakeoverSignatures(ctx)
+	if err != nil {
+		w.taskLog(taskId, LevelWarn, "Takeover: get signatures failed: %v, using built-in signatures", err)
+		return scanner.DefaultTakeoverSignatures()
+	}
+	if resp.Code != 0 {
+		w.taskLog(taskId, LevelWarn, "Takeover: get signatures failed: %s, using built-in signatures", resp.Msg)
+		return scanner.DefaultTakeoverSignatures()
+	}
+
+	signatures := make([]*scanner.TakeoverSignature, 0, len(resp.Signatures))
+	for _, sig := range resp.Signatures {
+		if sig == nil {
+			continue
+		}
+		if err := sig.Validate(); err != nil {
+			w.taskLog(taskId, LevelWarn, "Takeover: invalid signature %s: %v", sig.Service, err)
+			continue
+		}
+		signatures = append(signatures, sig)
+	}
+	return signatures
+}
+
+// executeTakeoverScan 对目标和资产中的域名执行子域接管检测
+// 返回被标记接管风险的资产和确认的漏洞，漏洞已保存
+func (w *Worker) executeTakeoverScan(ctx context.Context, task *scheduler.TaskInfo, target string, assets []*scanner.Asset, config *scheduler.TakeoverConfig, orgId string) (flagged []*scanner.Asset, vuls []*scanner.Vulnerability) {
+	// 添加 panic 恢复机制
+	defer func() {
+		if r := recover(); r != nil {
+			w.taskLog(task.TaskId, LevelError, "Takeover panic recovered: %v, stack: %s", r, string(getStackTrace()))
+		}
+	}()
+
+	signatures := w.loadTakeoverSignatures(ctx, task.TaskId)
+	if len(signatures) == 0 {
+		w.taskLog(task.TaskId, LevelWarn, "Takeover: no enabled signatures, skipped")
+		return nil, nil
+	}
+
+	s := scanner.NewTakeoverScanner()
+	result, err := s.Scan(ctx, &scanner.ScanConfig{
+		Target:  target,
+		Targets: ParseTargets(target),
+		Assets:  assets,
+		Options: &scanner.TakeoverOptions{
+			Signatures:  signatures,
+			Concurrency: config.Concurrency,
+			Timeout:     config.Timeout,
+		},
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger: func(level, format string, args ...interface{}) {
+			w.taskLog(task.TaskId, level, format, args...)
+		},
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "Takeover: %v", err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+
+	if len(result.Assets) > 0 {
+		w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, result.Assets)
+	}
+	if len(result.Vulnerabilities) > 0 {
+		w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, result.Vulnerabilities)
+	}
+	return result.Assets, result.Vulnerabilities
+}
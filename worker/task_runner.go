@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"cscan/pkg/utils"
 	"cscan/scanner"
 	"cscan/scheduler"
 )
@@ -16,9 +17,14 @@ type TaskPhase string
 
 const (
 	PhaseDomainScan    TaskPhase = "domainscan"
+	PhaseTakeover      TaskPhase = "takeover"
+	PhaseDNSRecord     TaskPhase = "dnsrecord"
 	PhasePortScan      TaskPhase = "portscan"
 	PhasePortIdentify  TaskPhase = "portidentify"
 	PhaseFingerprint   TaskPhase = "fingerprint"
+	PhaseExternal      TaskPhase = "external"
+	PhaseCrawler       TaskPhase = "crawler"
+	PhaseBruteforce    TaskPhase = "bruteforce"
 	PhaseDirScan       TaskPhase = "dirscan"
 	PhasePocScan       TaskPhase = "pocscan"
 )
@@ -36,9 +42,14 @@ type PhaseConfig struct {
 // DefaultPhaseOrder 默认阶段执行顺序
 var DefaultPhaseOrder = []PhaseConfig{
 	{Phase: PhaseDomainScan, Name: "子域名扫描", Scanner: "subfinder", ProgressStart: 10, ProgressEnd: 20, ContinueOnError: true},
+	{Phase: PhaseTakeover, Name: "子域接管检测", Scanner: "takeover", ProgressStart: 18, ProgressEnd: 19, ContinueOnError: true},
+	{Phase: PhaseDNSRecord, Name: "DNS记录采集", Scanner: "dnsrecord", ProgressStart: 19, ProgressEnd: 20, ContinueOnError: true},
 	{Phase: PhasePortScan, Name: "端口扫描", Scanner: "naabu", ProgressStart: 20, ProgressEnd: 40, ContinueOnError: true},
 	{Phase: PhasePortIdentify, Name: "端口识别", Scanner: "nmap/fingerprintx", ProgressStart: 40, ProgressEnd: 50, ContinueOnError: true},
 	{Phase: PhaseFingerprint, Name: "指纹识别", Scanner: "fingerprint", ProgressStart: 50, ProgressEnd: 70, ContinueOnError: true},
+	{Phase: PhaseExternal, Name: "外部扫描", Scanner: "external", ProgressStart: 65, ProgressEnd: 68, ContinueOnError: true},
+	{Phase: PhaseCrawler, Name: "爬虫", Scanner: "crawler", ProgressStart: 68, ProgressEnd: 69, ContinueOnError: true},
+	{Phase: PhaseBruteforce, Name: "弱口令检测", Scanner: "bruteforce", ProgressStart: 69, ProgressEnd: 70, ContinueOnError: true},
 	{Phase: PhaseDirScan, Name: "目录扫描", Scanner: "ffuf", ProgressStart: 70, ProgressEnd: 80, ContinueOnError: true},
 	{Phase: PhasePocScan, Name: "漏洞扫描", Scanner: "nuclei", ProgressStart: 80, ProgressEnd: 100, ContinueOnError: true},
 }
@@ -72,7 +83,8 @@ type TaskContext struct {
 	Vulnerabilities []*scanner.Vulnerability
 	CompletedPhases map[TaskPhase]bool
 	Runner          *TaskRunner
-	Worker          *Worker // 引用Worker以访问其方法
+	Worker          *Worker                 // 引用Worker以访问其方法
+	Blacklist       *utils.BlacklistMatcher // 全局黑名单，用于过滤子域名结果
 }
 
 // PhaseResult 阶段执行结果
@@ -115,6 +127,12 @@ func (r *TaskRunner) RegisterPhaseExecutor(phase TaskPhase, executor PhaseExecut
 	r.phaseExecutors[phase] = executor
 }
 
+// GetPhaseExecutor 获取阶段执行器
+func (r *TaskRunner) GetPhaseExecutor(phase TaskPhase) (PhaseExecutor, bool) {
+	e, ok := r.phaseExecutors[phase]
+	return e, ok
+}
+
 // GetScanner 获取扫描器
 func (r *TaskRunner) GetScanner(name string) (scanner.Scanner, bool) {
 	s, ok := r.scanners[name]
@@ -345,12 +363,22 @@ func (r *TaskRunner) isPhaseEnabled(taskCtx *TaskContext, phase TaskPhase) bool
 	switch phase {
 	case PhaseDomainScan:
 		return config.DomainScan != nil && config.DomainScan.Enable
+	case PhaseTakeover:
+		return config.Takeover != nil && config.Takeover.Enable
+	case PhaseDNSRecord:
+		return config.DNSRecord != nil && config.DNSRecord.Enable
 	case PhasePortScan:
 		return config.PortScan != nil && config.PortScan.Enable
 	case PhasePortIdentify:
 		return config.PortIdentify != nil && config.PortIdentify.Enable
 	case PhaseFingerprint:
 		return config.Fingerprint != nil && config.Fingerprint.Enable
+	case PhaseExternal:
+		return config.External != nil && config.External.Enable
+	case PhaseCrawler:
+		return config.Crawler != nil && config.Crawler.Enable
+	case PhaseBruteforce:
+		return config.Bruteforce != nil && config.Bruteforce.Enable
 	case PhaseDirScan:
 		return config.DirScan != nil && config.DirScan.Enable
 	case PhasePocScan:
@@ -420,12 +448,22 @@ func (r *TaskRunner) getPhaseOptions(taskCtx *TaskContext, phase TaskPhase) inte
 	switch phase {
 	case PhaseDomainScan:
 		return config.DomainScan
+	case PhaseTakeover:
+		return config.Takeover
+	case PhaseDNSRecord:
+		return config.DNSRecord
 	case PhasePortScan:
 		return config.PortScan
 	case PhasePortIdentify:
 		return config.PortIdentify
 	case PhaseFingerprint:
 		return config.Fingerprint
+	case PhaseExternal:
+		return config.External
+	case PhaseCrawler:
+		return config.Crawler
+	case PhaseBruteforce:
+		return config.Bruteforce
 	case PhaseDirScan:
 		return config.DirScan
 	case PhasePocScan:
@@ -490,6 +528,12 @@ func GetEnabledPhases(config *scheduler.TaskConfig) []string {
 	if config.DomainScan != nil && config.DomainScan.Enable {
 		phases = append(phases, "Domain Scan")
 	}
+	if config.Takeover != nil && config.Takeover.Enable {
+		phases = append(phases, "Takeover")
+	}
+	if config.DNSRecord != nil && config.DNSRecord.Enable {
+		phases = append(phases, "DNS Record")
+	}
 	if config.PortScan != nil && config.PortScan.Enable {
 		phases = append(phases, "Port Scan")
 	}
@@ -499,6 +543,15 @@ func GetEnabledPhases(config *scheduler.TaskConfig) []string {
 	if config.Fingerprint != nil && config.Fingerprint.Enable {
 		phases = append(phases, "Fingerprint")
 	}
+	if config.External != nil && config.External.Enable {
+		phases = append(phases, "External Scan")
+	}
+	if config.Crawler != nil && config.Crawler.Enable {
+		phases = append(phases, "Crawler")
+	}
+	if config.Bruteforce != nil && config.Bruteforce.Enable {
+		phases = append(phases, "Bruteforce")
+	}
 	if config.DirScan != nil && config.DirScan.Enable {
 		phases = append(phases, "Dir Scan")
 	}
@@ -3,10 +3,10 @@ package worker
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"strings"
 	"time"
 
+	"cscan/pkg/utils"
 	"cscan/scanner"
 	"cscan/scheduler"
 )
@@ -70,7 +70,8 @@ func (e *DomainScanExecutor) CanExecute(ctx *TaskContext) bool {
 	return ctx.Config.DomainScan != nil && ctx.Config.DomainScan.Enable
 }
 
-// Execute 执行子域名扫描
+// Execute 执行子域名扫描，被动枚举和暴力破解的结果各自完成后立即过滤并上报
+// 返回新发现的子域名资产，调用方将其主机追加到后续阶段的目标中
 func (e *DomainScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	w := e.worker
 	task := ctx.Task
@@ -93,12 +94,15 @@ func (e *DomainScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 		for _, p := range providerResp.Providers {
 			if len(p.Keys) > 0 {
 				providerConfig[p.Provider] = p.Keys
+				w.taskLog(task.TaskId, LevelDebug, "Subfinder provider: %s, keys: %d", p.Provider, len(p.Keys))
 			}
 		}
 		w.taskLog(task.TaskId, LevelInfo, "Loaded %d subfinder providers with keys", len(providerConfig))
+	} else {
+		w.taskLog(task.TaskId, LevelInfo, "No subfinder providers configured in database")
 	}
 
-	// 构建 Subfinder 选项
+	// 构建 Subfinder 选项，使用 Worker 并发数
 	subfinderOpts := &scanner.SubfinderOptions{
 		Timeout:            config.Timeout,
 		MaxEnumerationTime: config.MaxEnumerationTime,
@@ -110,7 +114,7 @@ func (e *DomainScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 		Recursive:          config.Recursive,
 		RemoveWildcard:     config.RemoveWildcard,
 		ResolveDNS:         config.ResolveDNS,
-		Concurrent:         w.config.Concurrency * 10,
+		Concurrent:         w.config.Concurrency,
 		ProviderConfig:     providerConfig,
 	}
 
@@ -121,11 +125,52 @@ func (e *DomainScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	if subfinderOpts.MaxEnumerationTime <= 0 {
 		subfinderOpts.MaxEnumerationTime = 10
 	}
+	w.taskLog(task.TaskId, LevelInfo, "Subfinder using worker concurrency: threads=%d, dns_concurrent=%d", subfinderOpts.Threads, subfinderOpts.Concurrent)
+
+	domainStream := w.newResultStream(task, ctx.OrgId, "domainscan", func(assets []*scanner.Asset) {
+		w.detectCDN(ctx.Ctx, task, assets, ctx.Config.CDN)
+	})
+	defer domainStream.Close()
+	var excludeMatcher *utils.BlacklistMatcher
+	if ctx.Config.PortScan != nil && ctx.Config.PortScan.ExcludeHosts != "" {
+		excludeMatcher = utils.NewExcludeHostsMatcher(ctx.Config.PortScan.ExcludeHosts)
+	}
+	seenSubdomains := make(map[string]bool)
+	var mergedAssets []*scanner.Asset
+	addSubdomains := func(assets []*scanner.Asset) {
+		var fresh []*scanner.Asset
+		for _, asset := range assets {
+			if asset.Host != "" && !seenSubdomains[asset.Host] {
+				seenSubdomains[asset.Host] = true
+				fresh = append(fresh, asset)
+			}
+		}
+
+		// 应用黑名单过滤子域名结果
+		if ctx.Blacklist != nil && !ctx.Blacklist.IsEmpty() {
+			fresh = w.filterAssetsByBlacklist(fresh, ctx.Blacklist, task.TaskId)
+		}
 
-	var subfinderAssets []*scanner.Asset
-	var bruteforceAssets []*scanner.Asset
+		// 子域名枚举和CNAME解析发现的资产必须在扫描范围内
+		fresh = w.filterAssetsByScope(task.WorkspaceId, task.MainTaskId, task.TaskId, "domainscan", fresh)
 
-	// 执行 Subfinder（如果启用）
+		// 应用端口扫描排除目标过滤子域名解析的IP
+		if excludeMatcher != nil && !excludeMatcher.IsEmpty() {
+			originalCount := len(fresh)
+			fresh = w.filterAssetsByExcludeHosts(fresh, excludeMatcher, task.TaskId)
+			if filteredCount := originalCount - len(fresh); filteredCount > 0 {
+				w.taskLog(task.TaskId, LevelInfo, "ExcludeHosts: filtered %d subdomains by resolved IP", filteredCount)
+			}
+		}
+
+		for _, asset := range fresh {
+			domainStream.AddAsset(asset)
+		}
+		mergedAssets = append(mergedAssets, fresh...)
+	}
+
+	// 只有启用 Subfinder 时才执行被动枚举
+	var subfinderCount, bruteforceCount int
 	if config.Subfinder {
 		if s, ok := w.scanners["subfinder"]; ok {
 			result, err := s.Scan(ctx.Ctx, &scanner.ScanConfig{
@@ -139,34 +184,43 @@ func (e *DomainScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 			if err != nil {
 				w.taskLog(task.TaskId, LevelError, "Subfinder error: %v", err)
 			} else if result != nil && len(result.Assets) > 0 {
-				subfinderAssets = result.Assets
-				w.taskLog(task.TaskId, LevelInfo, "Subfinder: found %d subdomains", len(result.Assets))
+				subfinderCount = len(result.Assets)
+				w.taskLog(task.TaskId, LevelInfo, "Subfinder: found %d subdomains", subfinderCount)
+				addSubdomains(result.Assets)
 			}
+		} else {
+			w.taskLog(task.TaskId, LevelWarn, "Subfinder scanner not available")
 		}
-	}
-
-	// 检查控制信号
-	if ctrl := w.checkTaskControl(ctx.Ctx, task.TaskId); ctrl == "STOP" {
-		return &PhaseResult{Stopped: true}, nil
-	} else if ctrl == "PAUSE" {
-		return &PhaseResult{Paused: true, Assets: subfinderAssets}, nil
+	} else {
+		w.taskLog(task.TaskId, LevelInfo, "Subfinder disabled, skipping passive enumeration")
 	}
 
 	// 执行子域名暴力破解（如果配置了字典）
 	if len(config.SubdomainDictIds) > 0 {
-		bruteforceAssets = e.executeBruteforce(ctx, config, domainTaskLogger)
+		bruteforceAssets := e.executeBruteforce(ctx, config, domainTaskLogger)
+		bruteforceCount = len(bruteforceAssets)
+		addSubdomains(bruteforceAssets)
 	}
 
-	// 合并结果
-	allAssets := e.mergeAssets(subfinderAssets, bruteforceAssets)
+	// 等待子域名全部上报，上报时已完成CDN/WAF/云厂商识别
+	domainStream.Close()
 
-	// 保存结果
-	if len(allAssets) > 0 {
-		w.taskLog(task.TaskId, LevelInfo, "Saving %d subdomains to database", len(allAssets))
-		w.saveAssetResult(ctx.Ctx, task.WorkspaceId, task.MainTaskId, ctx.OrgId, allAssets)
+	// 检查控制信号，已发现的子域名随暂停状态保存
+	if ctrl := w.checkTaskControl(ctx.Ctx, task.TaskId); ctrl == "STOP" {
+		return &PhaseResult{Stopped: true, Assets: mergedAssets}, nil
+	} else if ctrl == "PAUSE" {
+		return &PhaseResult{Paused: true, Assets: mergedAssets}, nil
+	}
+	if ctx.Ctx.Err() != nil {
+		w.taskLog(task.TaskId, LevelInfo, "Domain scan cancelled by context")
+		return &PhaseResult{Paused: true, Assets: mergedAssets}, nil
 	}
 
-	return &PhaseResult{Assets: allAssets}, nil
+	if len(mergedAssets) > 0 {
+		w.taskLog(task.TaskId, LevelInfo, "Domain scan completed: found %d subdomains (subfinder: %d, bruteforce: %d)",
+			len(mergedAssets), subfinderCount, bruteforceCount)
+	}
+	return &PhaseResult{Assets: mergedAssets}, nil
 }
 
 // executeBruteforce 执行子域名暴力破解
@@ -216,7 +270,9 @@ func (e *DomainScanExecutor) executeBruteforce(ctx *TaskContext, config *schedul
 	// 获取递归爆破字典
 	if config.RecursiveBrute && len(config.RecursiveDictIds) > 0 {
 		recursiveDictResp, err := w.httpClient.GetSubdomainDicts(ctx.Ctx, config.RecursiveDictIds)
-		if err == nil && recursiveDictResp != nil && len(recursiveDictResp.Dicts) > 0 {
+		if err != nil {
+			w.taskLog(task.TaskId, LevelWarn, "Bruteforce: get recursive dicts failed: %v", err)
+		} else if recursiveDictResp != nil && len(recursiveDictResp.Dicts) > 0 {
 			recursiveWords := e.mergeDictWords(recursiveDictResp.Dicts, task.TaskId)
 			if len(recursiveWords) > 0 {
 				bruteforceOpts.RecursiveWordlist = strings.Join(recursiveWords, "\n")
@@ -273,28 +329,80 @@ func (e *DomainScanExecutor) mergeDictWords(dicts []SubdomainDictItem, taskId st
 	return allWords
 }
 
-// mergeAssets 合并资产列表（去重）
-func (e *DomainScanExecutor) mergeAssets(subfinderAssets, bruteforceAssets []*scanner.Asset) []*scanner.Asset {
-	assetMap := make(map[string]*scanner.Asset)
+// TakeoverExecutor 子域接管检测阶段执行器
+type TakeoverExecutor struct {
+	worker *Worker
+}
 
-	for _, asset := range subfinderAssets {
-		if asset.Host != "" {
-			assetMap[asset.Host] = asset
-		}
+// NewTakeoverExecutor 创建子域接管检测执行器
+func NewTakeoverExecutor(worker *Worker) *TakeoverExecutor {
+	return &TakeoverExecutor{worker: worker}
+}
+
+// CanExecute 检查是否可以执行
+func (e *TakeoverExecutor) CanExecute(ctx *TaskContext) bool {
+	return ctx.Config.Takeover != nil && ctx.Config.Takeover.Enable
+}
+
+// Execute 执行子域接管检测
+func (e *TakeoverExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
+	w := e.worker
+	task := ctx.Task
+
+	// 检查控制信号
+	if ctrl := w.checkTaskControl(ctx.Ctx, task.TaskId); ctrl == "STOP" {
+		return &PhaseResult{Stopped: true}, nil
+	} else if ctrl == "PAUSE" {
+		return &PhaseResult{Paused: true}, nil
 	}
-	for _, asset := range bruteforceAssets {
-		if asset.Host != "" {
-			if _, exists := assetMap[asset.Host]; !exists {
-				assetMap[asset.Host] = asset
-			}
-		}
+
+	// 漏洞已在 executeTakeoverScan 中保存，被标记的资产已在上游阶段的结果中
+	flagged, vuls := w.executeTakeoverScan(ctx.Ctx, task, ctx.Target, ctx.Assets, ctx.Config.Takeover, ctx.OrgId)
+	w.taskLog(task.TaskId, LevelInfo, "Takeover check completed: flagged assets=%d, vuls=%d", len(flagged), len(vuls))
+
+	if ctx.Ctx.Err() != nil || w.checkTaskControl(ctx.Ctx, task.TaskId) == "STOP" {
+		return &PhaseResult{Stopped: true, Vulnerabilities: vuls}, nil
+	}
+
+	return &PhaseResult{Vulnerabilities: vuls}, nil
+}
+
+// DNSRecordExecutor DNS记录采集阶段执行器
+type DNSRecordExecutor struct {
+	worker *Worker
+}
+
+// NewDNSRecordExecutor 创建DNS记录采集执行器
+func NewDNSRecordExecutor(worker *Worker) *DNSRecordExecutor {
+	return &DNSRecordExecutor{worker: worker}
+}
+
+// CanExecute 检查是否可以执行
+func (e *DNSRecordExecutor) CanExecute(ctx *TaskContext) bool {
+	return ctx.Config.DNSRecord != nil && ctx.Config.DNSRecord.Enable
+}
+
+// Execute 执行DNS记录采集
+func (e *DNSRecordExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
+	w := e.worker
+	task := ctx.Task
+
+	// 检查控制信号
+	if ctrl := w.checkTaskControl(ctx.Ctx, task.TaskId); ctrl == "STOP" {
+		return &PhaseResult{Stopped: true}, nil
+	} else if ctrl == "PAUSE" {
+		return &PhaseResult{Paused: true}, nil
 	}
 
-	var result []*scanner.Asset
-	for _, asset := range assetMap {
-		result = append(result, asset)
+	// 记录和漏洞已在 executeDNSRecordScan 中保存
+	records, vuls := w.executeDNSRecordScan(ctx.Ctx, task, ctx.Target, ctx.Assets, ctx.Config.DNSRecord)
+	w.taskLog(task.TaskId, LevelInfo, "DNS record collection completed: records=%d, vuls=%d", len(records), len(vuls))
+
+	if ctx.Ctx.Err() != nil || w.checkTaskControl(ctx.Ctx, task.TaskId) == "STOP" {
+		return &PhaseResult{Stopped: true, Vulnerabilities: vuls}, nil
 	}
-	return result
+
+	return &PhaseResult{Vulnerabilities: vuls}, nil
 }
 
 // PortScanExecutor 端口扫描阶段执行器
@@ -312,123 +420,125 @@ func (e *PortScanExecutor) CanExecute(ctx *TaskContext) bool {
 	return ctx.Config.PortScan != nil && ctx.Config.PortScan.Enable
 }
 
-// Execute 执行端口扫描
+// Execute 执行端口扫描，发现的端口实时上报
 func (e *PortScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	w := e.worker
 	task := ctx.Task
 	config := ctx.Config.PortScan
 
-	// 解析目标，分离带端口和不带端口的目标
-	parseResult := scanner.ParseTargetsForPortScan(ctx.Target)
+	// 创建带超时的上下文，防止端口扫描卡死
+	portScanTimeout := 600 // 默认10分钟总超时
+	if config.Timeout > 0 {
+		// 根据单个端口超时计算总超时（至少10分钟）
+		portScanTimeout = config.Timeout * 100
+		if portScanTimeout < 600 {
+			portScanTimeout = 600
+		}
+	}
+	portCtx, portCancel := context.WithTimeout(ctx.Ctx, time.Duration(portScanTimeout)*time.Second)
+	defer portCancel()
 
-	var openPorts []*scanner.Asset
+	// 根据配置选择端口发现工具（默认使用Naabu）
+	portDiscoveryTool := "naabu"
+	if config.Tool != "" {
+		portDiscoveryTool = config.Tool
+	}
 
-	// 1. 处理带端口的目标（直接创建资产，跳过端口扫描）
-	if len(parseResult.WithPort) > 0 {
-		w.taskLog(task.TaskId, LevelInfo, "Port scan: %d targets with explicit port (skip discovery)", len(parseResult.WithPort))
-		for _, pt := range parseResult.WithPort {
-			asset := &scanner.Asset{
-				Authority: pt.Raw,
-				Host:      pt.Host,
-				Port:      pt.Port,
-				Category:  scanner.GetCategoryNew(pt.Host),
-				Source:    "user_input",
-				IsHTTP:    scanner.IsHTTPService("", pt.Port),
-			}
-			// 如果有协议，设置服务类型
-			if pt.Protocol == "https" {
-				asset.Service = "https"
-				asset.IsHTTP = true
-			} else if pt.Protocol == "http" {
-				asset.Service = "http"
-				asset.IsHTTP = true
-			}
-			openPorts = append(openPorts, asset)
-		}
+	// 创建任务日志回调
+	taskLogger := func(level, format string, args ...interface{}) {
+		w.taskLog(task.TaskId, level, format, args...)
 	}
 
-	// 2. 处理不带端口的目标（执行端口扫描）
-	if len(parseResult.WithoutPort) > 0 {
-		// 创建带超时的上下文
-		portScanTimeout := 600
-		if config.Timeout > 0 {
-			portScanTimeout = config.Timeout * 100
-			if portScanTimeout < 600 {
-				portScanTimeout = 600
-			}
-		}
-		portCtx, portCancel := context.WithTimeout(ctx.Ctx, time.Duration(portScanTimeout)*time.Second)
-		defer portCancel()
+	// 创建进度回调
+	onProgress := func(progress int, message string) {
+		w.updateTaskProgress(ctx.Ctx, task.TaskId, progress, message)
+	}
 
-		// 选择端口发现工具
-		portDiscoveryTool := "naabu"
-		if config.Tool != "" {
-			portDiscoveryTool = config.Tool
-		}
+	// 跳过指向CDN边缘节点的目标
+	portTarget := ctx.Target
+	if ctx.Config.CDN != nil && ctx.Config.CDN.Enable {
+		portTarget = strings.Join(w.skipCDNTargets(ctx.Ctx, task, ParseTargets(portTarget), ctx.Config.CDN, config), "\n")
+	}
+	if config.IPv6 {
+		portTarget = strings.Join(w.appendIPv6Targets(ctx.Ctx, task, ParseTargets(portTarget), ctx.Assets, config, ctx.Config.CDN), "\n")
+	}
 
-		// 创建任务日志回调
-		taskLogger := func(level, format string, args ...interface{}) {
-			w.taskLog(task.TaskId, level, format, args...)
-		}
+	// 发现的端口实时上报，任务中途停止时已发现的端口不会丢失，CDN识别在上报前完成
+	portStream := w.newResultStream(task, ctx.OrgId, "portscan", func(assets []*scanner.Asset) {
+		w.detectCDN(ctx.Ctx, task, assets, ctx.Config.CDN)
+	})
+	defer portStream.Close()
 
-		// 创建进度回调
-		onProgress := func(progress int, message string) {
-			w.updateTaskProgress(ctx.Ctx, task.TaskId, progress, message)
+	var openPorts []*scanner.Asset
+	var scanErr error
+	switch portDiscoveryTool {
+	case "masscan":
+		w.taskLog(task.TaskId, LevelInfo, "Port scan: Masscan")
+		if s, ok := w.scanners["masscan"]; ok {
+			result, err := s.Scan(portCtx, &scanner.ScanConfig{
+				Target:     portTarget,
+				Options:    config,
+				TaskLogger: taskLogger,
+				OnProgress: onProgress,
+				OnResult:   portStream.AddOpenPort,
+			})
+			if result != nil {
+				openPorts = result.Assets
+			}
+			scanErr = err
 		}
-
-		// 将不带端口的目标重新组合为字符串
-		targetStr := strings.Join(parseResult.WithoutPort, "\n")
-
-		switch portDiscoveryTool {
-		case "masscan":
-			w.taskLog(task.TaskId, LevelInfo, "Port scan: Masscan (%d targets)", len(parseResult.WithoutPort))
-			if s, ok := w.scanners["masscan"]; ok {
-				result, err := s.Scan(portCtx, &scanner.ScanConfig{
-					Target:     targetStr,
-					Options:    config,
-					TaskLogger: taskLogger,
-					OnProgress: onProgress,
-				})
-				if err != nil {
-					w.taskLog(task.TaskId, LevelError, "Masscan error: %v", err)
-				}
-				if result != nil && len(result.Assets) > 0 {
-					openPorts = append(openPorts, result.Assets...)
-				}
+	default:
+		w.taskLog(task.TaskId, LevelInfo, "Port scan: Naabu")
+		if s, ok := w.scanners["naabu"]; ok {
+			result, err := s.Scan(portCtx, &scanner.ScanConfig{
+				Target:     portTarget,
+				Options:    config,
+				TaskLogger: taskLogger,
+				OnProgress: onProgress,
+				OnResult:   portStream.AddOpenPort,
+			})
+			// 有目标超过端口阈值时不终止任务，只记录警告
+			if err == scanner.ErrPortThresholdExceeded {
+				w.taskLog(task.TaskId, LevelWarn, "Some targets exceeded port threshold and were skipped")
+				err = nil
 			}
-		default:
-			w.taskLog(task.TaskId, LevelInfo, "Port scan: Naabu (%d targets)", len(parseResult.WithoutPort))
-			if s, ok := w.scanners["naabu"]; ok {
-				result, err := s.Scan(portCtx, &scanner.ScanConfig{
-					Target:     targetStr,
-					Options:    config,
-					TaskLogger: taskLogger,
-					OnProgress: onProgress,
-				})
-				if err != nil && err != scanner.ErrPortThresholdExceeded {
-					w.taskLog(task.TaskId, LevelError, "Naabu error: %v", err)
-				}
-				if result != nil && len(result.Assets) > 0 {
-					openPorts = append(openPorts, result.Assets...)
-				}
+			if result != nil {
+				openPorts = result.Assets
 			}
+			scanErr = err
 		}
 	}
 
-	// 检查控制信号
-	if w.checkTaskControl(ctx.Ctx, task.TaskId) == "STOP" || ctx.Ctx.Err() != nil {
-		return &PhaseResult{Stopped: true, Assets: openPorts}, nil
+	// 检查是否被停止或超时
+	if portCtx.Err() == context.DeadlineExceeded {
+		w.taskLog(task.TaskId, LevelWarn, "Port scan timeout, continuing with partial results")
+	} else if ctx.Ctx.Err() != nil || w.checkTaskControl(ctx.Ctx, task.TaskId) == "STOP" {
+		return &PhaseResult{Stopped: true}, nil
+	}
+	if scanErr != nil {
+		w.taskLog(task.TaskId, LevelError, "%s error: %v", portDiscoveryTool, scanErr)
+	}
+	if len(openPorts) > 0 {
+		w.taskLog(task.TaskId, LevelInfo, "Found %d open ports", len(openPorts))
 	}
 
-	// 设置 IsHTTP 字段
+	// UDP端口发现
+	openPorts = append(openPorts, w.executeUDPScan(portCtx, task, portTarget, config, portStream.AddOpenPort)...)
+	// 扫描器未实时回调的端口在此补充，已上报的端口不会重复上报
 	for _, asset := range openPorts {
-		asset.IsHTTP = scanner.IsHTTPService(asset.Service, asset.Port)
+		portStream.AddOpenPort(asset)
 	}
+	portStream.Close()
 
-	// 保存结果
+	// 检查是否被停止
+	if ctx.Ctx.Err() != nil || w.checkTaskControl(ctx.Ctx, task.TaskId) == "STOP" {
+		return &PhaseResult{Stopped: true}, nil
+	}
+
+	// 通过范围过滤并已上报的端口
+	openPorts = portStream.Assets()
 	if len(openPorts) > 0 {
-		w.taskLog(task.TaskId, LevelInfo, "Port scan completed: %d assets", len(openPorts))
-		w.saveAssetResult(ctx.Ctx, task.WorkspaceId, task.MainTaskId, ctx.OrgId, openPorts)
+		w.taskLog(task.TaskId, LevelInfo, "Port scan completed: %d assets", len(ctx.Assets)+len(openPorts))
 	} else {
 		w.taskLog(task.TaskId, LevelInfo, "No open ports found")
 	}
@@ -448,31 +558,18 @@ func NewFingerprintExecutor(worker *Worker) *FingerprintExecutor {
 
 // CanExecute 检查是否可以执行
 func (e *FingerprintExecutor) CanExecute(ctx *TaskContext) bool {
-	// 修改：即使没有资产，如果有目标也可以执行（支持用户直接输入目标进行部分扫描）
-	if ctx.Config.Fingerprint == nil || !ctx.Config.Fingerprint.Enable {
-		return false
-	}
-	return len(ctx.Assets) > 0 || ctx.Target != ""
+	return ctx.Config.Fingerprint != nil && ctx.Config.Fingerprint.Enable
 }
 
-// Execute 执行指纹识别
+// Execute 执行指纹识别，识别完成的资产实时上报
 func (e *FingerprintExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	w := e.worker
 	task := ctx.Task
 	config := ctx.Config.Fingerprint
 
-	// 如果没有资产但有目标，从目标生成资产（支持用户直接输入目标进行部分扫描）
-	assets := ctx.Assets
-	if len(assets) == 0 && ctx.Target != "" {
-		generatedAssets := scanner.GenerateAssetsFromTargets(ctx.Target)
-		if len(generatedAssets) > 0 {
-			assets = generatedAssets
-			ctx.Assets = generatedAssets
-			w.taskLog(task.TaskId, LevelInfo, "Fingerprint: generated %d assets from user input targets", len(assets))
-		}
-	}
-
-	if len(assets) == 0 {
+	// 强制扫描模式：没有资产时从用户输入目标生成资产
+	forceScanAssets(ctx, config.ForceScan, "Fingerprint")
+	if len(ctx.Assets) == 0 {
 		w.taskLog(task.TaskId, LevelInfo, "Fingerprint: skipped (no assets)")
 		return &PhaseResult{}, nil
 	}
@@ -486,29 +583,55 @@ func (e *FingerprintExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 
 	s, ok := w.scanners["fingerprint"]
 	if !ok {
-		return &PhaseResult{}, fmt.Errorf("fingerprint scanner not found")
+		return &PhaseResult{}, nil
 	}
 
-	// 获取超时配置
+	// 根据过滤模式处理资产
+	assetsToScan := ctx.Assets
+	filterMode := config.FilterMode
+	if filterMode == "" {
+		filterMode = "http_mapping" // 默认使用HTTP映射模式
+	}
+	if filterMode == "service_mapping" {
+		// 模式B：服务映射中明确标识为非HTTP的服务不参与识别
+		var httpAssets []*scanner.Asset
+		nonHttpCount := 0
+		for _, asset := range ctx.Assets {
+			if checker := scanner.GetHttpServiceChecker(); checker != nil {
+				if isHttp, found := checker.IsHttpService(strings.ToLower(asset.Service)); found && !isHttp {
+					nonHttpCount++
+					continue
+				}
+			}
+			httpAssets = append(httpAssets, asset)
+		}
+		assetsToScan = httpAssets
+		w.taskLog(task.TaskId, LevelInfo, "Fingerprint: FilterMode=service_mapping, filtered %d assets (excluded %d non-HTTP services), remaining %d assets",
+			len(ctx.Assets), nonHttpCount, len(httpAssets))
+	} else {
+		// 模式A：使用HTTP映射（默认行为，不做额外过滤）
+		w.taskLog(task.TaskId, LevelInfo, "Fingerprint: FilterMode=http_mapping, using all %d assets", len(ctx.Assets))
+	}
+
+	// 获取单目标超时配置
 	targetTimeout := config.TargetTimeout
 	if targetTimeout <= 0 {
 		targetTimeout = 30
 	}
-
-	// 使用 Worker 并发数
+	// 使用 Worker 并发数覆盖配置中的并发数
 	config.Concurrency = w.config.Concurrency
-	w.taskLog(task.TaskId, LevelInfo, "Fingerprint: %d assets, timeout %ds/target, concurrency=%d",
-		len(assets), targetTimeout, w.config.Concurrency)
+	w.taskLog(task.TaskId, LevelInfo, "Fingerprint: %d assets, timeout %ds/target, concurrency=%d, activeScan=%v, filterMode=%s",
+		len(assetsToScan), targetTimeout, w.config.Concurrency, config.ActiveScan, filterMode)
 
-	// 加载 HTTP 服务映射配置
+	// 每次扫描前实时加载 HTTP 服务映射配置
 	w.loadHttpServiceMappings()
 
-	// 如果启用自定义指纹引擎，加载自定义指纹
+	// 如果启用自定义指纹引擎，加载自定义指纹（包括主动指纹）
 	if config.CustomEngine {
 		w.loadCustomFingerprints(ctx.Ctx, s.(*scanner.FingerprintScanner), config.ActiveScan)
 	}
 
-	// 创建带超时的上下文
+	// 创建带超时的上下文，防止指纹识别卡死
 	fingerprintTimeout := config.Timeout
 	if fingerprintTimeout <= 0 {
 		fingerprintTimeout = 300
@@ -521,17 +644,27 @@ func (e *FingerprintExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 		w.taskLog(task.TaskId, level, format, args...)
 	}
 
+	// 识别完成的资产实时上报，CDN/WAF 结合响应头和拦截页在上报前识别
+	mergeFingerprint := newFingerprintMerger(ctx.Assets)
+	fpStream := w.newResultStream(task, ctx.OrgId, "fingerprint", func(assets []*scanner.Asset) {
+		w.detectCDN(ctx.Ctx, task, assets, ctx.Config.CDN)
+	})
+	defer fpStream.Close()
+
 	result, err := s.Scan(fpCtx, &scanner.ScanConfig{
-		Assets:     assets, // 使用可能从目标生成的资产
+		Assets:     assetsToScan,
 		Options:    config,
 		TaskLogger: fpTaskLogger,
+		OnResult: func(fpAsset *scanner.Asset) {
+			if asset := mergeFingerprint(fpAsset); asset != nil {
+				fpStream.AddAsset(asset)
+			}
+		},
 	})
 
-	ctx.Assets = assets
-
 	// 检查是否超时
 	if fpCtx.Err() == context.DeadlineExceeded {
-		w.taskLog(task.TaskId, LevelWarn, "Fingerprint scan timeout after %ds", fingerprintTimeout)
+		w.taskLog(task.TaskId, LevelWarn, "Fingerprint scan timeout after %ds, continuing with partial results", fingerprintTimeout)
 	}
 
 	// 检查控制信号
@@ -539,40 +672,19 @@ func (e *FingerprintExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 		return &PhaseResult{Stopped: true}, nil
 	}
 
-	if err != nil {
-		return &PhaseResult{Error: err}, err
-	}
-
-	// 更新资产信息
-	if result != nil && len(result.Assets) > 0 {
-		// 构建 Host:Port -> Asset 的映射
-		assetMap := make(map[string]*scanner.Asset)
-		for _, asset := range ctx.Assets {
-			key := fmt.Sprintf("%s:%d", asset.Host, asset.Port)
-			assetMap[key] = asset
-		}
-
-		// 通过 Host:Port 匹配来更新资产信息
+	if err == nil && result != nil {
+		// 扫描器未实时回调的结果在此补充，已上报的资产不会重复上报
 		for _, fpAsset := range result.Assets {
-			key := fmt.Sprintf("%s:%d", fpAsset.Host, fpAsset.Port)
-			if originalAsset, ok := assetMap[key]; ok {
-				originalAsset.Service = fpAsset.Service
-				originalAsset.Title = fpAsset.Title
-				originalAsset.App = fpAsset.App
-				originalAsset.HttpStatus = fpAsset.HttpStatus
-				originalAsset.HttpHeader = fpAsset.HttpHeader
-				originalAsset.HttpBody = fpAsset.HttpBody
-				originalAsset.Server = fpAsset.Server
-				originalAsset.IconHash = fpAsset.IconHash
-				if len(fpAsset.IconData) > 0 {
-					originalAsset.IconData = fpAsset.IconData
-				}
-				originalAsset.Screenshot = fpAsset.Screenshot
+			if asset := mergeFingerprint(fpAsset); asset != nil {
+				fpStream.AddAsset(asset)
 			}
 		}
+	}
+	fpStream.Close()
 
-		// 保存更新结果
-		w.saveAssetResult(ctx.Ctx, task.WorkspaceId, task.MainTaskId, ctx.OrgId, ctx.Assets)
+	// 采集所有TLS服务的证书，不限于HTTP资产
+	if config.CertScan {
+		return &PhaseResult{Vulnerabilities: w.collectCerts(ctx.Ctx, task, ctx.Assets, config, ctx.OrgId)}, nil
 	}
 
 	return &PhaseResult{}, nil
@@ -590,32 +702,18 @@ func NewPocScanExecutor(worker *Worker) *PocScanExecutor {
 
 // CanExecute 检查是否可以执行
 func (e *PocScanExecutor) CanExecute(ctx *TaskContext) bool {
-	// 修改：即使没有资产，如果有目标也可以执行（支持用户直接输入目标进行部分扫描）
-	if ctx.Config.PocScan == nil || !ctx.Config.PocScan.Enable {
-		return false
-	}
-	return len(ctx.Assets) > 0 || ctx.Target != ""
+	return ctx.Config.PocScan != nil && ctx.Config.PocScan.Enable
 }
 
-// Execute 执行POC扫描
+// Execute 执行POC扫描，发现漏洞立即上报
 func (e *PocScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	w := e.worker
 	task := ctx.Task
 	config := ctx.Config.PocScan
 
-	// 如果没有资产但有目标，从目标生成资产（支持用户直接输入目标进行部分扫描）
+	// 强制扫描模式：没有资产时从用户输入目标生成资产
+	forceScanAssets(ctx, config.ForceScan, "POC scan")
 	assets := ctx.Assets
-	if len(assets) == 0 && ctx.Target != "" {
-		generatedAssets := scanner.GenerateAssetsFromTargets(ctx.Target)
-		if len(generatedAssets) > 0 {
-			assets = generatedAssets
-			ctx.Assets = generatedAssets
-			w.taskLog(task.TaskId, LevelInfo, "POC scan: generated %d assets from user input targets", len(assets))
-		}
-	}
-
-	assets = ctx.Assets
-
 	if len(assets) == 0 {
 		w.taskLog(task.TaskId, LevelInfo, "POC scan: skipped (no assets)")
 		return &PhaseResult{}, nil
@@ -630,7 +728,7 @@ func (e *PocScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 
 	s, ok := w.scanners["nuclei"]
 	if !ok {
-		return &PhaseResult{}, fmt.Errorf("nuclei scanner not found")
+		return &PhaseResult{}, nil
 	}
 
 	// 获取超时配置
@@ -691,8 +789,9 @@ func (e *PocScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	var allVuls []*scanner.Vulnerability
 	var vulCount int
 
-	// 创建漏洞缓冲区
-	vulBuffer := NewVulnerabilityBuffer(1)
+	// 创建结果流，发现漏洞立即上报
+	vulStream := w.newResultStream(task, ctx.OrgId, "pocscan", nil)
+	defer vulStream.Close()
 
 	// 计算总超时
 	pocTimeout := pocTargetTimeout * len(assets)
@@ -702,28 +801,6 @@ func (e *PocScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	pocCtx, pocCancel := context.WithTimeout(ctx.Ctx, time.Duration(pocTimeout)*time.Second)
 	defer pocCancel()
 
-	// 启动后台刷新协程
-	flushDone := make(chan struct{})
-	go func() {
-		defer close(flushDone)
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-pocCtx.Done():
-				return
-			case <-vulBuffer.flushChan:
-				vulBuffer.Flush(pocCtx, func(vuls []*scanner.Vulnerability) {
-					w.saveVulResult(ctx.Ctx, task.WorkspaceId, task.MainTaskId, vuls)
-				})
-			case <-ticker.C:
-				vulBuffer.Flush(pocCtx, func(vuls []*scanner.Vulnerability) {
-					w.saveVulResult(ctx.Ctx, task.WorkspaceId, task.MainTaskId, vuls)
-				})
-			}
-		}
-	}()
-
 	// 构建 Nuclei 扫描选项
 	taskIdForCallback := task.TaskId
 	nucleiOpts := &scanner.NucleiOptions{
@@ -743,13 +820,13 @@ func (e *PocScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 		OnVulnerabilityFound: func(vul *scanner.Vulnerability) {
 			vulCount++
 			w.taskLog(taskIdForCallback, LevelInfo, "Vulnerability found: %s → %s", vul.PocFile, vul.Url)
-			vulBuffer.Add(vul)
+			vulStream.AddVul(vul)
 		},
 	}
 
 	// 设置默认值
 	if nucleiOpts.RateLimit == 0 {
-		nucleiOpts.RateLimit = 800
+		nucleiOpts.RateLimit = 150
 	}
 	if nucleiOpts.Concurrency == 0 {
 		nucleiOpts.Concurrency = 25
@@ -766,12 +843,8 @@ func (e *PocScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 		TaskLogger: pocTaskLogger,
 	})
 
-	ctx.Assets = assets
-
-	// 刷新剩余漏洞
-	vulBuffer.Flush(ctx.Ctx, func(vuls []*scanner.Vulnerability) {
-		w.saveVulResult(ctx.Ctx, task.WorkspaceId, task.MainTaskId, vuls)
-	})
+	// 上报剩余漏洞
+	vulStream.Close()
 
 	// 检查是否超时
 	if pocCtx.Err() == context.DeadlineExceeded {
@@ -809,7 +882,7 @@ func NewPortIdentifyExecutor(worker *Worker) *PortIdentifyExecutor {
 
 // CanExecute 检查是否可以执行
 func (e *PortIdentifyExecutor) CanExecute(ctx *TaskContext) bool {
-	return ctx.Config.PortIdentify != nil && ctx.Config.PortIdentify.Enable && len(ctx.Assets) > 0
+	return ctx.Config.PortIdentify != nil && ctx.Config.PortIdentify.Enable
 }
 
 // Execute 执行端口识别
@@ -818,6 +891,8 @@ func (e *PortIdentifyExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	task := ctx.Task
 	config := ctx.Config.PortIdentify
 
+	// 强制扫描模式：没有资产时从用户输入目标生成资产
+	forceScanAssets(ctx, config.ForceScan, "Port identify")
 	if len(ctx.Assets) == 0 {
 		w.taskLog(task.TaskId, LevelInfo, "Port identify: skipped (no assets)")
 		return &PhaseResult{}, nil
@@ -838,17 +913,129 @@ func (e *PortIdentifyExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 		return &PhaseResult{Stopped: true, Assets: identifiedAssets}, nil
 	}
 
-	// 更新 ctx.Assets 为识别后的资产
+	// 识别后的资产替换输入资产
 	if len(identifiedAssets) > 0 {
-		// 保存结果
+		ctx.Assets = identifiedAssets
 		w.saveAssetResult(ctx.Ctx, task.WorkspaceId, task.MainTaskId, ctx.OrgId, identifiedAssets)
-		// 返回更新后的资产列表
-		return &PhaseResult{Assets: identifiedAssets}, nil
 	}
 
 	return &PhaseResult{}, nil
 }
 
+// ExternalScanExecutor 外部扫描器阶段执行器
+type ExternalScanExecutor struct {
+	worker *Worker
+}
+
+// NewExternalScanExecutor 创建外部扫描器执行器
+func NewExternalScanExecutor(worker *Worker) *ExternalScanExecutor {
+	return &ExternalScanExecutor{worker: worker}
+}
+
+// CanExecute 检查是否可以执行
+func (e *ExternalScanExecutor) CanExecute(ctx *TaskContext) bool {
+	return ctx.Config.External != nil && ctx.Config.External.Enable
+}
+
+// Execute 执行外部扫描器
+func (e *ExternalScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
+	w := e.worker
+	task := ctx.Task
+
+	// 检查控制信号
+	if ctrl := w.checkTaskControl(ctx.Ctx, task.TaskId); ctrl == "STOP" {
+		return &PhaseResult{Stopped: true}, nil
+	} else if ctrl == "PAUSE" {
+		return &PhaseResult{Paused: true}, nil
+	}
+
+	// 调用 Worker 的 executeExternalScan 方法，结果已在其中保存
+	assets, vuls := w.executeExternalScan(ctx.Ctx, task, ctx.Target, ctx.Assets, ctx.Config.External, ctx.OrgId)
+	w.taskLog(task.TaskId, LevelInfo, "External scan completed: new assets=%d, vuls=%d", len(assets), len(vuls))
+
+	if ctx.Ctx.Err() != nil || w.checkTaskControl(ctx.Ctx, task.TaskId) == "STOP" {
+		return &PhaseResult{Stopped: true, Assets: assets, Vulnerabilities: vuls}, nil
+	}
+
+	return &PhaseResult{Assets: assets, Vulnerabilities: vuls}, nil
+}
+
+// CrawlerExecutor 爬虫阶段执行器
+type CrawlerExecutor struct {
+	worker *Worker
+}
+
+// NewCrawlerExecutor 创建爬虫执行器
+func NewCrawlerExecutor(worker *Worker) *CrawlerExecutor {
+	return &CrawlerExecutor{worker: worker}
+}
+
+// CanExecute 检查是否可以执行
+func (e *CrawlerExecutor) CanExecute(ctx *TaskContext) bool {
+	return ctx.Config.Crawler != nil && ctx.Config.Crawler.Enable
+}
+
+// Execute 执行爬虫
+func (e *CrawlerExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
+	w := e.worker
+	task := ctx.Task
+
+	// 检查控制信号
+	if ctrl := w.checkTaskControl(ctx.Ctx, task.TaskId); ctrl == "STOP" {
+		return &PhaseResult{Stopped: true}, nil
+	} else if ctrl == "PAUSE" {
+		return &PhaseResult{Paused: true}, nil
+	}
+
+	// 端点和漏洞已在 executeCrawlerScan 中保存，返回的URL资产供目录扫描和POC扫描使用
+	assets, vuls := w.executeCrawlerScan(ctx.Ctx, task, ctx.Target, ctx.Assets, ctx.Config.Crawler)
+	w.taskLog(task.TaskId, LevelInfo, "Crawler completed: url assets=%d, secrets=%d", len(assets), len(vuls))
+
+	if ctx.Ctx.Err() != nil || w.checkTaskControl(ctx.Ctx, task.TaskId) == "STOP" {
+		return &PhaseResult{Stopped: true, Assets: assets, Vulnerabilities: vuls}, nil
+	}
+
+	return &PhaseResult{Assets: assets, Vulnerabilities: vuls}, nil
+}
+
+// BruteforceExecutor 弱口令检测阶段执行器
+type BruteforceExecutor struct {
+	worker *Worker
+}
+
+// NewBruteforceExecutor 创建弱口令检测执行器
+func NewBruteforceExecutor(worker *Worker) *BruteforceExecutor {
+	return &BruteforceExecutor{worker: worker}
+}
+
+// CanExecute 检查是否可以执行
+func (e *BruteforceExecutor) CanExecute(ctx *TaskContext) bool {
+	return ctx.Config.Bruteforce != nil && ctx.Config.Bruteforce.Enable
+}
+
+// Execute 执行弱口令检测
+func (e *BruteforceExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
+	w := e.worker
+	task := ctx.Task
+
+	// 检查控制信号
+	if ctrl := w.checkTaskControl(ctx.Ctx, task.TaskId); ctrl == "STOP" {
+		return &PhaseResult{Stopped: true}, nil
+	} else if ctrl == "PAUSE" {
+		return &PhaseResult{Paused: true}, nil
+	}
+
+	// 漏洞已在 executeBruteforceScan 中保存
+	vuls := w.executeBruteforceScan(ctx.Ctx, task, ctx.Assets, ctx.Config.Bruteforce)
+	w.taskLog(task.TaskId, LevelInfo, "Bruteforce completed: findings=%d", len(vuls))
+
+	if ctx.Ctx.Err() != nil || w.checkTaskControl(ctx.Ctx, task.TaskId) == "STOP" {
+		return &PhaseResult{Stopped: true, Vulnerabilities: vuls}, nil
+	}
+
+	return &PhaseResult{Vulnerabilities: vuls}, nil
+}
+
 // DirScanExecutor 目录扫描阶段执行器
 type DirScanExecutor struct {
 	worker *Worker
@@ -870,19 +1057,9 @@ func (e *DirScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	task := ctx.Task
 	config := ctx.Config.DirScan
 
-	// 如果没有资产但有目标，从目标生成资产（支持用户直接输入带路径的目标）
+	// 强制扫描模式：没有资产时从用户输入目标生成资产
+	forceScanAssets(ctx, config.ForceScan, "Dir scan")
 	assets := ctx.Assets
-	if len(assets) == 0 && ctx.Target != "" {
-		generatedAssets := scanner.GenerateAssetsFromTargets(ctx.Target)
-		if len(generatedAssets) > 0 {
-			assets = generatedAssets
-			ctx.Assets = generatedAssets
-			w.taskLog(task.TaskId, LevelInfo, "Dir scan: generated %d assets from user input targets", len(assets))
-		}
-	}
-
-	assets = ctx.Assets
-
 	if len(assets) == 0 {
 		w.taskLog(task.TaskId, LevelInfo, "Dir scan: skipped (no assets)")
 		return &PhaseResult{}, nil
@@ -913,12 +1090,28 @@ func (e *DirScanExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
 	return &PhaseResult{}, nil
 }
 
+// forceScanAssets 强制扫描模式下没有资产时从任务目标生成资产
+func forceScanAssets(ctx *TaskContext, force bool, phaseName string) {
+	if !force || len(ctx.Assets) > 0 || ctx.Target == "" {
+		return
+	}
+	if generated := scanner.GenerateAssetsFromTargets(ctx.Target); len(generated) > 0 {
+		ctx.Assets = generated
+		ctx.Worker.taskLog(ctx.Task.TaskId, LevelInfo, "%s: generated %d assets from target (force scan)", phaseName, len(generated))
+	}
+}
+
 // RegisterDefaultExecutors 注册默认阶段执行器
 func (i *TaskRunnerIntegration) RegisterDefaultExecutors() {
 	i.taskRunner.RegisterPhaseExecutor(PhaseDomainScan, NewDomainScanExecutor(i.worker))
+	i.taskRunner.RegisterPhaseExecutor(PhaseTakeover, NewTakeoverExecutor(i.worker))
+	i.taskRunner.RegisterPhaseExecutor(PhaseDNSRecord, NewDNSRecordExecutor(i.worker))
 	i.taskRunner.RegisterPhaseExecutor(PhasePortScan, NewPortScanExecutor(i.worker))
 	i.taskRunner.RegisterPhaseExecutor(PhasePortIdentify, NewPortIdentifyExecutor(i.worker))
 	i.taskRunner.RegisterPhaseExecutor(PhaseFingerprint, NewFingerprintExecutor(i.worker))
+	i.taskRunner.RegisterPhaseExecutor(PhaseExternal, NewExternalScanExecutor(i.worker))
+	i.taskRunner.RegisterPhaseExecutor(PhaseCrawler, NewCrawlerExecutor(i.worker))
+	i.taskRunner.RegisterPhaseExecutor(PhaseBruteforce, NewBruteforceExecutor(i.worker))
 	i.taskRunner.RegisterPhaseExecutor(PhaseDirScan, NewDirScanExecutor(i.worker))
 	i.taskRunner.RegisterPhaseExecutor(PhasePocScan, NewPocScanExecutor(i.worker))
 }
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 // ==================== Terminal Configuration ====================
@@ -79,6 +80,11 @@ type TerminalSession struct {
 	lastActive time.Time
 	cols       int
 	rows       int
+
+	// PTY 模式（Unix）：会话对应一个运行在伪终端中的交互式 shell
+	pty     *os.File
+	inputMu sync.Mutex // 串行化写入 PTY，不与 mu 共用，避免写阻塞时无法关闭会话
+	lineBuf []byte     // 当前输入行，用于回车前检查黑名单
 }
 
 // NewTerminalSession 创建新的终端会话
@@ -113,6 +119,9 @@ func (s *TerminalSession) Close() {
 		if s.stderr != nil {
 			s.stderr.Close()
 		}
+		if s.pty != nil {
+			s.pty.Close()
+		}
 		if s.cmd != nil && s.cmd.Process != nil {
 			s.cmd.Process.Kill()
 		}
@@ -262,9 +271,198 @@ func (h *TerminalHandler) CreateSession(sessionId string) (*TerminalSession, err
 	session := NewTerminalSession(sessionId)
 	h.sessions.Store(sessionId, session)
 
+	// Unix 下为会话启动 PTY shell，Windows 退化为逐条执行命令
+	if ptySupported {
+		if err := h.startShell(session); err != nil {
+			h.sessions.Delete(sessionId)
+			return nil, err
+		}
+	}
+
 	return session, nil
 }
 
+// ==================== PTY Session ====================
+
+// startShell 在伪终端中启动交互式 shell，输出以原始字节流回调
+func (h *TerminalHandler) startShell(session *TerminalSession) error {
+	cmd := exec.Command(defaultShell())
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color", "PYTHONIOENCODING=utf-8")
+	if h.config.WorkingDir != "" {
+		cmd.Dir = h.config.WorkingDir
+	}
+
+	session.mu.Lock()
+	cols, rows := session.cols, session.rows
+	session.mu.Unlock()
+
+	f, err := startPTY(cmd, cols, rows)
+	if err != nil {
+		return &TerminalError{Code: ErrCodeExecFailed, Message: "failed to start pty: " + err.Error()}
+	}
+
+	session.mu.Lock()
+	session.cmd = cmd
+	session.pty = f
+	session.isRunning = true
+	session.mu.Unlock()
+
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		h.readPTY(session, f)
+	}()
+
+	// shell 退出后通知客户端并清理会话
+	go func() {
+		err := cmd.Wait()
+
+		session.mu.Lock()
+		session.isRunning = false
+		session.mu.Unlock()
+
+		// 等待剩余输出读完；后台进程可能仍持有 PTY，不无限等待
+		select {
+		case <-readDone:
+		case <-time.After(time.Second):
+		}
+
+		select {
+		case <-session.closeChan:
+			// 主动关闭的会话无需再通知
+			return
+		default:
+		}
+
+		if h.onOutput != nil {
+			exitMsg := "\r\n[Session exited]\r\n"
+			if err != nil {
+				exitMsg = fmt.Sprintf("\r\n[Session exited: %v]\r\n", err)
+			}
+			h.onOutput(session.ID, []byte(exitMsg))
+		}
+		h.sessions.CompareAndDelete(session.ID, session)
+		session.Close()
+	}()
+
+	return nil
+}
+
+// readPTY 读取 PTY 原始输出，保证每次回调的数据不会截断 UTF-8 字符
+func (h *TerminalHandler) readPTY(session *TerminalSession, f *os.File) {
+	buf := make([]byte, 32*1024)
+	var pending []byte
+
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			data := append(pending, buf[:n]...)
+			cut := completeUTF8Prefix(data)
+			pending = append([]byte(nil), data[cut:]...)
+
+			if cut > 0 {
+				output := data[:cut]
+				select {
+				case session.outputChan <- output:
+				default:
+					// 通道满了，丢弃
+				}
+				if h.onOutput != nil {
+					h.onOutput(session.ID, output)
+				}
+			}
+		}
+		if err != nil {
+			// shell 退出后读取主设备返回 EIO
+			if len(pending) > 0 && h.onOutput != nil {
+				h.onOutput(session.ID, pending)
+			}
+			return
+		}
+	}
+}
+
+// completeUTF8Prefix 返回末尾不含不完整 UTF-8 字符的前缀长度
+func completeUTF8Prefix(p []byte) int {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if utf8.FullRune(p[i:]) {
+				return len(p)
+			}
+			return i
+		}
+	}
+	return len(p)
+}
+
+// writePTY 写入原始输入到 PTY
+// 同时维护当前输入行，回车提交前检查黑名单，命中时用 Ctrl+U 清除该行而不提交。
+// 行缓冲只跟踪可见字符和退格，历史命令、Tab 补全等无法覆盖，仅作为兜底拦截，完整操作以会话录像为准
+func (h *TerminalHandler) writePTY(session *TerminalSession, data []byte) error {
+	session.inputMu.Lock()
+	defer session.inputMu.Unlock()
+
+	session.mu.Lock()
+	f := session.pty
+	session.mu.Unlock()
+	if f == nil {
+		return &TerminalError{Code: ErrCodeNoStdin, Message: "no pty available"}
+	}
+
+	var blocked string
+	start := 0
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		switch {
+		case b == '\r' || b == '\n':
+			line := string(session.lineBuf)
+			session.lineBuf = session.lineBuf[:0]
+			if h.IsCommandBlacklisted(line) {
+				if _, err := f.Write(data[start:i]); err != nil {
+					return &TerminalError{Code: ErrCodeWriteFailed, Message: "failed to write to pty: " + err.Error()}
+				}
+				f.Write([]byte{0x15})
+				start = i + 1
+				blocked = line
+			}
+		case b == 0x7f || b == 0x08:
+			// 退格删除最后一个字符
+			if len(session.lineBuf) > 0 {
+				_, size := utf8.DecodeLastRune(session.lineBuf)
+				session.lineBuf = session.lineBuf[:len(session.lineBuf)-size]
+			}
+		case b == 0x03 || b == 0x04 || b == 0x15:
+			// Ctrl+C / Ctrl+D / Ctrl+U 放弃当前行
+			session.lineBuf = session.lineBuf[:0]
+		case b == 0x1b:
+			// 跳过方向键等转义序列
+			if i+1 < len(data) && (data[i+1] == '[' || data[i+1] == 'O') {
+				i++
+				for i+1 < len(data) {
+					i++
+					if data[i] >= 0x40 && data[i] <= 0x7e {
+						break
+					}
+				}
+			}
+		case b >= 0x20:
+			session.lineBuf = append(session.lineBuf, b)
+		}
+	}
+
+	if _, err := f.Write(data[start:]); err != nil {
+		return &TerminalError{Code: ErrCodeWriteFailed, Message: "failed to write to pty: " + err.Error()}
+	}
+
+	if blocked != "" {
+		if h.onOutput != nil {
+			h.onOutput(session.ID, []byte("\r\n[Command is blacklisted]\r\n"))
+		}
+		return &TerminalError{Code: ErrCodeBlacklisted, Message: "command is blacklisted: " + blocked}
+	}
+	return nil
+}
+
 // CloseSession 关闭会话
 func (h *TerminalHandler) CloseSession(sessionId string) error {
 	session, ok := h.sessions.Load(sessionId)
@@ -292,7 +490,8 @@ func (h *TerminalHandler) CloseAllSessions() {
 
 // ==================== Command Execution ====================
 
-// ExecuteCommand 执行命令（单次执行，非交互式）
+// ExecuteCommand 执行命令
+// PTY 会话中命令被输入到 shell；否则单次执行，非交互式
 func (h *TerminalHandler) ExecuteCommand(ctx context.Context, sessionId, command string) error {
 	// 检查黑名单
 	if h.IsCommandBlacklisted(command) {
@@ -311,6 +510,14 @@ func (h *TerminalHandler) ExecuteCommand(ctx context.Context, sessionId, command
 
 	session.UpdateLastActive()
 
+	// PTY 会话中直接输入命令并回车，由 shell 执行
+	session.mu.Lock()
+	hasPTY := session.pty != nil
+	session.mu.Unlock()
+	if hasPTY {
+		return h.writePTY(session, []byte(command+"\r"))
+	}
+
 	// 创建带超时的上下文
 	timeout := h.config.DefaultTimeout
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -436,8 +643,13 @@ func (h *TerminalHandler) SendInput(sessionId string, data []byte) error {
 
 	session.mu.Lock()
 	stdin := session.stdin
+	hasPTY := session.pty != nil
 	session.mu.Unlock()
 
+	if hasPTY {
+		return h.writePTY(session, data)
+	}
+
 	if stdin == nil {
 		return &TerminalError{Code: ErrCodeNoStdin, Message: "no stdin available"}
 	}
@@ -457,13 +669,22 @@ func (h *TerminalHandler) ResizeTerminal(sessionId string, cols, rows int) error
 		return &TerminalError{Code: ErrCodeSessionNotFound, Message: "session not found"}
 	}
 
+	if cols <= 0 || rows <= 0 {
+		return &TerminalError{Code: ErrCodeInvalidSize, Message: "invalid terminal size"}
+	}
+
 	session.mu.Lock()
 	session.cols = cols
 	session.rows = rows
+	f := session.pty
 	session.mu.Unlock()
 
-	// 注意：对于非PTY的命令执行，resize不会有实际效果
-	// 如果需要真正的终端大小调整，需要使用PTY
+	// 非 PTY 模式下只记录大小，没有实际效果
+	if f != nil {
+		if err := resizePTY(f, cols, rows); err != nil {
+			return &TerminalError{Code: ErrCodeExecFailed, Message: "failed to resize pty: " + err.Error()}
+		}
+	}
 
 	return nil
 }
@@ -478,8 +699,14 @@ func (h *TerminalHandler) InterruptCommand(sessionId string) error {
 	session.mu.Lock()
 	cmd := session.cmd
 	cancel := session.cancel
+	hasPTY := session.pty != nil
 	session.mu.Unlock()
 
+	// PTY 会话写入 Ctrl+C，由终端行规程向前台进程组发送 SIGINT
+	if hasPTY {
+		return h.writePTY(session, []byte{0x03})
+	}
+
 	if cancel != nil {
 		cancel()
 	}
@@ -518,6 +745,7 @@ const (
 	ErrCodeTimeout         = 408 // 命令执行超时
 	ErrCodeNoStdin         = 400 // 无stdin可用
 	ErrCodeWriteFailed     = 500 // 写入失败
+	ErrCodeInvalidSize     = 400 // 终端大小无效
 )
 
 // IsBlacklistedError 检查是否是黑名单错误
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package worker
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/creack/pty"
+)
+
+// ptySupported 当前平台支持伪终端
+const ptySupported = true
+
+// startPTY 在伪终端中启动命令，返回 PTY 主设备
+func startPTY(cmd *exec.Cmd, cols, rows int) (*os.File, error) {
+	return pty.StartWithSize(cmd, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+}
+
+// resizePTY 调整伪终端窗口大小，前台进程会收到 SIGWINCH
+func resizePTY(f *os.File, cols, rows int) error {
+	return pty.Setsize(f, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+}
+
+// defaultShell 交互终端使用的 shell，优先使用 $SHELL
+func defaultShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	if path, err := exec.LookPath("bash"); err == nil {
+		return path
+	}
+	return "/bin/sh"
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package worker
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// ptySupported Windows 不支持伪终端，终端会话退化为逐条执行命令
+const ptySupported = false
+
+// startPTY Windows不支持，返回错误
+func startPTY(cmd *exec.Cmd, cols, rows int) (*os.File, error) {
+	return nil, errors.New("pty not supported on Windows")
+}
+
+// resizePTY Windows不支持，忽略
+func resizePTY(f *os.File, cols, rows int) error {
+	return nil
+}
+
+// defaultShell Windows 使用 cmd
+func defaultShell() string {
+	return "cmd"
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// collectCerts 采集资产的TLS证书链并分析证书问题
+// 证书信息写回资产并保存，证书SAN中发现的新域名作为域名资产保存，返回已保存的证书漏洞
+func (w *Worker) collectCerts(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset, config *scheduler.FingerprintConfig, orgId string) []*scanner.Vulnerability {
+	defer func() {
+		if r := recover(); r != nil {
+			w.taskLog(task.TaskId, LevelError, "TLSCert panic recovered: %v, stack: %s", r, string(getStackTrace()))
+		}
+	}()
+
+	s := scanner.NewTLSCertScanner()
+	result, err := s.Scan(ctx, &scanner.ScanConfig{
+		Assets: assets,
+		Options: &scanner.TLSCertOptions{
+			Concurrency: w.config.Concurrency,
+			ExpiryDays:  config.CertExpiryDays,
+		},
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger: func(level, format string, args ...interface{}) {
+			w.taskLog(task.TaskId, level, format, args...)
+		},
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "TLSCert: %v", err)
+	}
+	if result == nil || len(result.Assets) == 0 {
+		return nil
+	}
+
+	w.taskLog(task.TaskId, LevelInfo, "TLSCert: collected %d certificates, %d issues", len(result.Assets), len(result.Vulnerabilities))
+	w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, result.Assets)
+	if len(result.Vulnerabilities) > 0 {
+		w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, result.Vulnerabilities)
+	}
+
+	// 证书SAN中的新域名作为候选域名资产
+	var candidates []*scanner.Asset
+	for _, domain := range scanner.CertCandidateDomains(result.Assets) {
+		candidates = append(candidates, &scanner.Asset{
+			Authority: domain,
+			Host:      domain,
+			Category:  "domain",
+			Source:    "tlscert",
+		})
+	}
+	candidates = w.filterAssetsByScope(task.WorkspaceId, task.MainTaskId, task.TaskId, "tlscert", candidates)
+	if len(candidates) > 0 {
+		w.taskLog(task.TaskId, LevelInfo, "TLSCert: %d new domains from certificate SANs", len(candidates))
+		w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, candidates)
+	}
+
+	return result.Vulnerabilities
+}
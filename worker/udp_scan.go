@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// executeUDPScan 端口扫描启用UDP时，对不带端口的目标执行UDP协议探测，返回开放的UDP端口资产
+// onResult 不为空时每发现一个端口立即回调
+func (w *Worker) executeUDPScan(ctx context.Context, task *scheduler.TaskInfo, target string, config *scheduler.PortScanConfig, onResult func(*scanner.Asset)) []*scanner.Asset {
+	// 添加 panic 恢复机制
+	defer func() {
+		if r := recover(); r != nil {
+			w.taskLog(task.TaskId, LevelError, "UDP scan panic recovered: %v, stack: %s", r, string(getStackTrace()))
+		}
+	}()
+
+	if config == nil || !config.UDP {
+		return nil
+	}
+	targets := scanner.ParseTargetsForPortScan(target).WithoutPort
+	if len(targets) == 0 {
+		return nil
+	}
+
+	w.taskLog(task.TaskId, LevelInfo, "Port scan: UDP (%d targets)", len(targets))
+	s := scanner.NewUDPScanner()
+	result, err := s.Scan(ctx, &scanner.ScanConfig{
+		Targets: targets,
+		Options: &scanner.UDPScanOptions{
+			Ports:   config.UDPPorts,
+			Rate:    config.UDPRate,
+			Retries: config.Retries,
+		},
+		TaskLogger: func(level, format string, args ...interface{}) {
+			w.taskLog(task.TaskId, level, format, args...)
+		},
+		OnResult: onResult,
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "UDP scan error: %v", err)
+	}
+	if result == nil {
+		return nil
+	}
+	w.taskLog(task.TaskId, LevelInfo, "UDP scan: found %d open ports", len(result.Assets))
+	return result.Assets
+}
@@ -26,12 +26,15 @@ import (
 
 // WorkerConfig Worker配置
 type WorkerConfig struct {
-	Name        string `json:"name"`
-	IP          string `json:"ip"`
-	ServerAddr  string `json:"serverAddr"` // API 服务地址 (e.g., http://server:8888)
-	InstallKey  string `json:"installKey"` // 安装密钥
-	Concurrency int    `json:"concurrency"`
-	Timeout     int    `json:"timeout"`
+	Name                string `json:"name"`
+	IP                  string `json:"ip"`
+	ServerAddr          string `json:"serverAddr"`     // API 服务地址 (e.g., http://server:8888)
+	Token               string `json:"token"`          // 注册后获得的Worker凭证
+	CredentialFile      string `json:"credentialFile"` // 凭证文件路径，重命名时同步更新
+	Concurrency         int    `json:"concurrency"`
+	Timeout             int    `json:"timeout"`
+	ExternalScannerFile string `json:"externalScannerFile"` // 外部扫描器声明文件（YAML/JSON）
+	GeoIPDir            string `json:"geoipDir"`            // IP归属地数据库目录，服务端数据集缓存在其 datasets 子目录
 }
 
 // Worker 工作节点
@@ -64,6 +67,12 @@ type Worker struct {
 	// 正在执行的任务
 	runningTasks sync.Map // taskId -> true
 
+	// 工作空间扫描范围，任务开始时刷新
+	scanScopes sync.Map // workspaceId -> *utils.ScopeMatcher
+
+	// IP归属地数据集，定期与服务端同步
+	geoIP geoIPCache
+
 	// 日志组件
 	logger Logger
 
@@ -135,50 +144,6 @@ func (w *Worker) taskLog(taskId, level, format string, args ...interface{}) {
 	}
 }
 
-// VulnerabilityBuffer 批量缓冲保存漏洞
-type VulnerabilityBuffer struct {
-	vuls      []*scanner.Vulnerability
-	mu        sync.Mutex
-	maxSize   int
-	flushChan chan struct{}
-}
-
-// NewVulnerabilityBuffer 创建漏洞缓冲区
-func NewVulnerabilityBuffer(maxSize int) *VulnerabilityBuffer {
-	return &VulnerabilityBuffer{
-		vuls:      make([]*scanner.Vulnerability, 0, maxSize),
-		maxSize:   maxSize,
-		flushChan: make(chan struct{}, 1),
-	}
-}
-
-// Add 添加漏洞到缓冲区，返回是否需要刷新
-func (b *VulnerabilityBuffer) Add(vul *scanner.Vulnerability) {
-	b.mu.Lock()
-	b.vuls = append(b.vuls, vul)
-	shouldFlush := len(b.vuls) >= b.maxSize
-	b.mu.Unlock()
-
-	if shouldFlush {
-		select {
-		case b.flushChan <- struct{}{}:
-		default:
-		}
-	}
-}
-
-// Flush 刷新缓冲区，批量保存
-func (b *VulnerabilityBuffer) Flush(ctx context.Context, saver func([]*scanner.Vulnerability)) {
-	b.mu.Lock()
-	vuls := b.vuls
-	b.vuls = nil
-	b.mu.Unlock()
-
-	if len(vuls) > 0 {
-		saver(vuls) // 批量保存
-	}
-}
-
 // NewWorker 创建Worker
 func NewWorker(config WorkerConfig) (*Worker, error) {
 	// 自动获取本机IP地址
@@ -187,7 +152,7 @@ func NewWorker(config WorkerConfig) (*Worker, error) {
 	}
 
 	// 创建 HTTP 客户端（替代 RPC 和 Redis）
-	httpClient := NewWorkerHTTPClient(config.ServerAddr, config.InstallKey, config.Name)
+	httpClient := NewWorkerHTTPClient(config.ServerAddr, config.Token, config.Name)
 
 	logx.Infof("[Worker] HTTP client created, API server: %s", config.ServerAddr)
 
@@ -211,7 +176,7 @@ func NewWorker(config WorkerConfig) (*Worker, error) {
 	}
 
 	// 创建 WebSocket 客户端
-	wsConfig := DefaultWSClientConfig(config.ServerAddr, config.Name, config.InstallKey)
+	wsConfig := DefaultWSClientConfig(config.ServerAddr, config.Name, config.Token)
 	w.wsClient = NewWorkerWSClient(wsConfig)
 
 	// 更新 logger 为 WebSocket 版本，将日志发送到服务器
@@ -257,6 +222,7 @@ func NewWorker(config WorkerConfig) (*Worker, error) {
 
 	// 注册扫描器
 	w.registerScanners()
+	w.loadExternalScanners()
 
 	// 初始化自适应扫描配置（根据系统硬件自动调整扫描器参数）
 	adaptiveCfg := scanner.GetGlobalAdaptiveConfig()
@@ -311,6 +277,8 @@ func (w *Worker) handleWorkerControl(action, param string) {
 	case "rename":
 		w.logger.Info("Renaming worker to: %s", param)
 		w.config.Name = param
+		// 服务端已同步更新凭证绑定的名称，本地凭证文件保持一致，重启后使用新名称
+		w.updateCredentialName(param)
 		// 更新日志前缀（使用 WebSocket 版本）
 		w.logger = NewWorkerLoggerWS(param, w.wsClient)
 		// 立即发送心跳，让服务端更新状态
@@ -830,29 +798,6 @@ func (w *Worker) SubmitTask(task *scheduler.TaskInfo) {
 
 // checkTaskControl 检查任务控制信号
 // 返回: "PAUSE" - 暂停, "STOP" - 停止, "" - 继续执行
-
-// handleTaskControl 统一处理任务控制信号(STOP/PAUSE)
-// 返回 true 表示任务被中止或暂停，调用方应直接 return
-func (w *Worker) handleTaskControl(ctx context.Context, task *scheduler.TaskInfo, completedPhases map[string]bool, assets []*scanner.Asset, phaseName string) bool {
-	if ctrl := w.checkTaskControl(ctx, task.TaskId); ctrl == "STOP" {
-		if phaseName != "" {
-			w.taskLog(task.TaskId, LevelInfo, "Task stopped during %s", phaseName)
-		} else {
-			w.taskLog(task.TaskId, LevelInfo, "Task stopped")
-		}
-		return true
-	} else if ctrl == "PAUSE" {
-		if phaseName != "" {
-			w.taskLog(task.TaskId, LevelInfo, "Task paused during %s, saving progress...", phaseName)
-		} else {
-			w.taskLog(task.TaskId, LevelInfo, "Task paused, saving progress...")
-		}
-		w.saveTaskProgress(ctx, task, completedPhases, assets)
-		return true
-	}
-	return false
-}
-
 func (w *Worker) checkTaskControl(ctx context.Context, taskId string) string {
 	// 从控制信号映射中检查
 	if signal, ok := w.taskControlSignals.Load(taskId); ok {
@@ -882,11 +827,28 @@ func (w *Worker) shouldStopTask(ctx context.Context, taskId string) bool {
 
 // saveTaskProgress 保存任务进度（用于暂停后继续扫描)
 func (w *Worker) saveTaskProgress(ctx context.Context, task *scheduler.TaskInfo, completedPhases map[string]bool, assets []*scanner.Asset) {
+	w.saveWorkflowProgress(ctx, task, completedPhases, assets, nil)
+}
+
+// saveWorkflowProgress 保存任务进度，stageAssets 为工作流各已完成阶段输出资产的 authority
+func (w *Worker) saveWorkflowProgress(ctx context.Context, task *scheduler.TaskInfo, completedPhases map[string]bool, assets []*scanner.Asset, stageAssets map[string][]string) {
 	// 使用新的context，因为原context可能已被取消
 	saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// 构建状态
+	stateJson, phases := buildResumeState(completedPhases, assets, stageAssets)
+
+	// 通过 HTTP 接口保存到数据库
+	w.httpClient.UpdateTask(saveCtx, &TaskUpdateReq{
+		TaskId: task.TaskId,
+		State:  "PAUSED",
+		Result: string(stateJson),
+	})
+	w.taskLog(task.TaskId, LevelInfo, "Task %s progress saved: completedPhases=%v, assets=%d", task.TaskId, phases, len(assets))
+}
+
+// buildResumeState 构建暂停状态，返回状态JSON和已完成阶段列表
+func buildResumeState(completedPhases map[string]bool, assets []*scanner.Asset, stageAssets map[string][]string) ([]byte, []string) {
 	phases := make([]string, 0)
 	for phase, completed := range completedPhases {
 		if completed {
@@ -899,15 +861,31 @@ func (w *Worker) saveTaskProgress(ctx context.Context, task *scheduler.TaskInfo,
 		"completedPhases": phases,
 		"assets":          string(assetsJson),
 	}
+	if len(stageAssets) > 0 {
+		state["stageAssets"] = stageAssets
+	}
 	stateJson, _ := json.Marshal(state)
+	return stateJson, phases
+}
 
-	// 通过 HTTP 接口保存到数据库
-	w.httpClient.UpdateTask(saveCtx, &TaskUpdateReq{
-		TaskId: task.TaskId,
-		State:  "PAUSED",
-		Result: string(stateJson),
-	})
-	w.taskLog(task.TaskId, LevelInfo, "Task %s progress saved: completedPhases=%v, assets=%d", task.TaskId, phases, len(assets))
+// parseResumeState 解析暂停状态，旧版状态没有 stageAssets 时返回 nil
+func parseResumeState(stateStr string) (map[string]bool, []*scanner.Asset, map[string][]string) {
+	var state struct {
+		CompletedPhases []string            `json:"completedPhases"`
+		Assets          string              `json:"assets"`
+		StageAssets     map[string][]string `json:"stageAssets"`
+	}
+	json.Unmarshal([]byte(stateStr), &state)
+
+	completedPhases := make(map[string]bool, len(state.CompletedPhases))
+	for _, phase := range state.CompletedPhases {
+		completedPhases[phase] = true
+	}
+	var assets []*scanner.Asset
+	if state.Assets != "" {
+		json.Unmarshal([]byte(state.Assets), &assets)
+	}
+	return completedPhases, assets, state.StageAssets
 }
 
 // createTaskContext 创建带有任务控制信号检查的上下文
@@ -1054,7 +1032,6 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 	w.taskLog(task.TaskId, LevelDebug, "Full task config: %s", task.Config)
 
 	var allAssets []*scanner.Asset
-	var allVuls []*scanner.Vulnerability
 
 	// 解析扫描配置
 	config, err := scheduler.ParseTaskConfig(task.Config)
@@ -1084,6 +1061,20 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		configDetails = append(configDetails, "DomainScan=nil")
 	}
 
+	if config.Takeover != nil {
+		configDetails = append(configDetails, fmt.Sprintf("Takeover.Enable=%v", config.Takeover.Enable))
+		if config.Takeover.Enable {
+			enabledPhases = append(enabledPhases, "Takeover")
+		}
+	}
+
+	if config.DNSRecord != nil {
+		configDetails = append(configDetails, fmt.Sprintf("DNSRecord.Enable=%v", config.DNSRecord.Enable))
+		if config.DNSRecord.Enable {
+			enabledPhases = append(enabledPhases, "DNSRecord")
+		}
+	}
+
 	if config.PortScan != nil {
 		configDetails = append(configDetails, fmt.Sprintf("PortScan.Enable=%v", config.PortScan.Enable))
 		if config.PortScan.Enable {
@@ -1111,6 +1102,27 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		configDetails = append(configDetails, "Fingerprint=nil")
 	}
 
+	if config.External != nil {
+		configDetails = append(configDetails, fmt.Sprintf("External.Enable=%v", config.External.Enable))
+		if config.External.Enable {
+			enabledPhases = append(enabledPhases, "External Scan")
+		}
+	}
+
+	if config.Crawler != nil {
+		configDetails = append(configDetails, fmt.Sprintf("Crawler.Enable=%v", config.Crawler.Enable))
+		if config.Crawler.Enable {
+			enabledPhases = append(enabledPhases, "Crawler")
+		}
+	}
+
+	if config.Bruteforce != nil {
+		configDetails = append(configDetails, fmt.Sprintf("Bruteforce.Enable=%v", config.Bruteforce.Enable))
+		if config.Bruteforce.Enable {
+			enabledPhases = append(enabledPhases, "Bruteforce")
+		}
+	}
+
 	if config.DirScan != nil {
 		configDetails = append(configDetails, fmt.Sprintf("DirScan.Enable=%v", config.DirScan.Enable))
 		if config.DirScan.Enable {
@@ -1131,6 +1143,20 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 
 	w.taskLog(task.TaskId, LevelInfo, "Config parsed: %s", strings.Join(configDetails, ", "))
 
+	// 声明式工作流：启用的阶段以工作流为准
+	useWorkflow := config.Workflow != nil && len(config.Workflow.Stages) > 0
+	if useWorkflow {
+		if err := config.Workflow.Validate(); err != nil {
+			w.taskLog(task.TaskId, LevelError, "Workflow invalid: %v", err)
+			w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusFailure, "工作流配置错误: "+err.Error())
+			return
+		}
+		enabledPhases = enabledPhases[:0]
+		for _, stage := range config.Workflow.Stages {
+			enabledPhases = append(enabledPhases, stage.DisplayName())
+		}
+	}
+
 	// 检查是否有启用的扫描阶段
 	if len(enabledPhases) == 0 {
 		w.taskLog(task.TaskId, LevelError, "No scan phases enabled in config")
@@ -1173,897 +1199,70 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		}
 	}
 
+	// 应用工作空间扫描范围，范围未知时不执行扫描
+	scopeMatcher, err := w.loadScanScope(ctx, task.WorkspaceId)
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "Scope: load scan scope failed: %v", err)
+		w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusFailure, "获取扫描范围失败: "+err.Error())
+		return
+	}
+	if !scopeMatcher.IsEmpty() {
+		targets = w.filterTargetsByScope(task, targets)
+		if len(targets) == 0 {
+			w.taskLog(task.TaskId, LevelInfo, "All targets out of scope, marking task as complete")
+			for _, phase := range enabledPhases {
+				w.incrSubTaskDone(ctx, task, phase)
+			}
+			w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusSuccess, "All targets out of scope")
+			return
+		}
+	}
+	// 只把通过黑名单和范围过滤的目标交给扫描器
+	target = strings.Join(targets, "\n")
+
 	// 输出任务开始日志
 	w.taskLog(task.TaskId, LevelInfo, "Starting: %s", strings.Join(enabledPhases, " → "))
 	w.taskLog(task.TaskId, LevelInfo, "Targets (%d): %s", len(targets), strings.Join(targets, ", "))
 
 	// 解析恢复状态（如果是继续执行的任务）
-	var resumeState map[string]interface{}
+	var completedPhases map[string]bool
+	var stageAssets map[string][]string
 	if stateStr, ok := taskConfig["resumeState"].(string); ok && stateStr != "" {
-		json.Unmarshal([]byte(stateStr), &resumeState)
+		completedPhases, allAssets, stageAssets = parseResumeState(stateStr)
 		w.taskLog(task.TaskId, LevelInfo, "Resuming from saved state")
-	}
-	completedPhases := make(map[string]bool)
-	if resumeState != nil {
-		if phases, ok := resumeState["completedPhases"].([]interface{}); ok {
-			for _, p := range phases {
-				if ps, ok := p.(string); ok {
-					completedPhases[ps] = true
-				}
-			}
-		}
-		// 恢复已扫描的资产
-		if assetsJson, ok := resumeState["assets"].(string); ok && assetsJson != "" {
-			json.Unmarshal([]byte(assetsJson), &allAssets)
-			w.taskLog(task.TaskId, LevelInfo, "Restored %d assets", len(allAssets))
-		}
+		w.taskLog(task.TaskId, LevelInfo, "Restored %d assets", len(allAssets))
 	}
 
 	// 当端口扫描禁用时，需要从目标生成初始资产列表
 	// 支持 IP:Port 格式的目标，用于资产扫描场景
-	if config.PortScan != nil && !config.PortScan.Enable && len(allAssets) == 0 {
+	var seed []*scanner.Asset
+	if !useWorkflow && config.PortScan != nil && !config.PortScan.Enable {
 		// 检查是否有其他阶段需要资产
 		needAssets := (config.PortIdentify != nil && config.PortIdentify.Enable) ||
 			(config.Fingerprint != nil && config.Fingerprint.Enable) ||
 			(config.PocScan != nil && config.PocScan.Enable)
 
 		if needAssets {
-			generatedAssets := w.generateAssetsFromTarget(target, config.PortScan)
-			if len(generatedAssets) > 0 {
-				allAssets = generatedAssets
-				w.taskLog(task.TaskId, LevelInfo, "Generated %d assets from target (port scan disabled)", len(allAssets))
-			}
-		}
-	}
-
-	// 执行子域名扫描（在端口扫描之前）
-	if config.DomainScan != nil && config.DomainScan.Enable && !completedPhases["domainscan"] {
-		// 检查控制信号
-		if ctrl := w.checkTaskControl(ctx, task.TaskId); ctrl == "STOP" {
-			w.taskLog(task.TaskId, LevelInfo, "Task stopped")
-			return
-		}
-
-		// 更新当前阶段
-		w.updateTaskProgressWithPhase(ctx, task.TaskId, 10, "子域名扫描中", "子域名扫描")
-		w.taskLog(task.TaskId, LevelInfo, "Starting domain scan...")
-
-		// 创建任务日志回调
-		domainTaskLogger := func(level, format string, args ...interface{}) {
-			w.taskLog(task.TaskId, level, format, args...)
-		}
-
-		// 通过 HTTP 接口获取 Subfinder 配置
-		var providerConfig map[string][]string
-		providerResp, err := w.httpClient.GetSubfinderProviders(ctx, task.WorkspaceId)
-		if err != nil {
-			w.taskLog(task.TaskId, LevelWarn, "Failed to get subfinder providers: %v", err)
-		} else if providerResp != nil && len(providerResp.Providers) > 0 {
-			providerConfig = make(map[string][]string)
-			for _, p := range providerResp.Providers {
-				if len(p.Keys) > 0 {
-					providerConfig[p.Provider] = p.Keys
-					w.taskLog(task.TaskId, LevelDebug, "Subfinder provider: %s, keys: %d", p.Provider, len(p.Keys))
-				}
-			}
-			w.taskLog(task.TaskId, LevelInfo, "Loaded %d subfinder providers with keys", len(providerConfig))
-		} else {
-			w.taskLog(task.TaskId, LevelInfo, "No subfinder providers configured in database")
-		}
-
-		// 构建Subfinder选项，使用Worker并发数
-		subfinderOpts := &scanner.SubfinderOptions{
-			Timeout:            config.DomainScan.Timeout,
-			MaxEnumerationTime: config.DomainScan.MaxEnumerationTime,
-			Threads:            w.config.Concurrency, // 使用Worker并发数
-			RateLimit:          config.DomainScan.RateLimit,
-			Sources:            config.DomainScan.Sources,
-			ExcludeSources:     config.DomainScan.ExcludeSources,
-			All:                config.DomainScan.All,
-			Recursive:          config.DomainScan.Recursive,
-			RemoveWildcard:     config.DomainScan.RemoveWildcard,
-			ResolveDNS:         config.DomainScan.ResolveDNS,
-			Concurrent:         w.config.Concurrency * 1, // DNS解析并发数为Worker并发数的10倍
-			ProviderConfig:     providerConfig,
-		}
-
-		// 设置默认值
-		if subfinderOpts.Timeout <= 0 {
-			subfinderOpts.Timeout = 30
-		}
-		if subfinderOpts.MaxEnumerationTime <= 0 {
-			subfinderOpts.MaxEnumerationTime = 10
-		}
-		w.taskLog(task.TaskId, LevelInfo, "Subfinder using worker concurrency: threads=%d, dns_concurrent=%d", subfinderOpts.Threads, subfinderOpts.Concurrent)
-
-		// 执行子域名扫描
-		var subfinderAssets []*scanner.Asset
-		// 只有启用Subfinder时才执行被动枚举
-		if config.DomainScan.Subfinder {
-			if s, ok := w.scanners["subfinder"]; ok {
-				result, err := s.Scan(ctx, &scanner.ScanConfig{
-					Target:      target,
-					WorkspaceId: task.WorkspaceId,
-					MainTaskId:  task.MainTaskId,
-					Options:     subfinderOpts,
-					TaskLogger:  domainTaskLogger,
-				})
-
-				if err != nil {
-					w.taskLog(task.TaskId, LevelError, "Subfinder error: %v", err)
-				} else if result != nil && len(result.Assets) > 0 {
-					subfinderAssets = result.Assets
-					w.taskLog(task.TaskId, LevelInfo, "Subfinder: found %d subdomains", len(subfinderAssets))
-				}
-			} else {
-				w.taskLog(task.TaskId, LevelWarn, "Subfinder scanner not available")
-			}
-		} else {
-			w.taskLog(task.TaskId, LevelInfo, "Subfinder disabled, skipping passive enumeration")
-		}
-
-		// 执行子域名暴力破解（如果配置了字典）
-		var bruteforceAssets []*scanner.Asset
-		if len(config.DomainScan.SubdomainDictIds) > 0 {
-			w.taskLog(task.TaskId, LevelInfo, "Starting subdomain bruteforce with %d dicts", len(config.DomainScan.SubdomainDictIds))
-
-			// 获取字典内容
-			dictResp, err := w.httpClient.GetSubdomainDicts(ctx, config.DomainScan.SubdomainDictIds)
-			if err != nil {
-				w.taskLog(task.TaskId, LevelError, "Bruteforce: get dicts failed: %v", err)
-			} else if dictResp != nil && len(dictResp.Dicts) > 0 {
-				// 合并所有字典内容
-				var allWords []string
-				wordSet := make(map[string]bool)
-				for _, dict := range dictResp.Dicts {
-					lines := strings.Split(dict.Content, "\n")
-					for _, line := range lines {
-						word := strings.TrimSpace(line)
-						if word != "" && !strings.HasPrefix(word, "#") && !wordSet[word] {
-							wordSet[word] = true
-							allWords = append(allWords, word)
-						}
-					}
-					w.taskLog(task.TaskId, LevelInfo, "Bruteforce: loaded dict '%s'", dict.Name)
-				}
-
-				if len(allWords) > 0 {
-					w.taskLog(task.TaskId, LevelInfo, "Bruteforce: total %d unique words", len(allWords))
-
-					// 构建暴力破解选项（包含增强功能）
-					bruteforceOpts := &scanner.SubdomainBruteforceOptions{
-						Wordlist:       strings.Join(allWords, "\n"),
-						Threads:        w.config.Concurrency * 2,
-						Timeout:        config.DomainScan.BruteforceTimeout * 60, // 转换为秒
-						WildcardFilter: config.DomainScan.RemoveWildcard,
-						ResolveDNS:     config.DomainScan.ResolveDNS,
-						Concurrent:     w.config.Concurrency * 10,
-						// 引擎配置
-						Engine:       config.DomainScan.BruteforceEngine,
-						Bandwidth:    config.DomainScan.Bandwidth,
-						Retry:        config.DomainScan.Retry,
-						WildcardMode: config.DomainScan.WildcardMode,
-						// 增强功能配置
-						RecursiveBrute: config.DomainScan.RecursiveBrute,
-						RecursiveDepth: 2,
-						WildcardDetect: config.DomainScan.WildcardDetect,
-					}
-
-					// 获取递归爆破字典（如果启用了递归爆破）
-					if config.DomainScan.RecursiveBrute && len(config.DomainScan.RecursiveDictIds) > 0 {
-						recursiveDictResp, err := w.httpClient.GetSubdomainDicts(ctx, config.DomainScan.RecursiveDictIds)
-						if err != nil {
-							w.taskLog(task.TaskId, LevelWarn, "Bruteforce: get recursive dicts failed: %v", err)
-						} else if recursiveDictResp != nil && len(recursiveDictResp.Dicts) > 0 {
-							var recursiveWords []string
-							recursiveWordSet := make(map[string]bool)
-							for _, dict := range recursiveDictResp.Dicts {
-								lines := strings.Split(dict.Content, "\n")
-								for _, line := range lines {
-									word := strings.TrimSpace(line)
-									if word != "" && !strings.HasPrefix(word, "#") && !recursiveWordSet[word] {
-										recursiveWordSet[word] = true
-										recursiveWords = append(recursiveWords, word)
-									}
-								}
-								w.taskLog(task.TaskId, LevelInfo, "Bruteforce: loaded recursive dict '%s'", dict.Name)
-							}
-							if len(recursiveWords) > 0 {
-								bruteforceOpts.RecursiveWordlist = strings.Join(recursiveWords, "\n")
-								w.taskLog(task.TaskId, LevelInfo, "Bruteforce: recursive wordlist total %d unique words", len(recursiveWords))
-							}
-						}
-					}
-
-					// 执行暴力破解
-					if bruteScanner, ok := w.scanners["subdomain_bruteforce"]; ok {
-						bruteResult, err := bruteScanner.Scan(ctx, &scanner.ScanConfig{
-							Target:      target,
-							WorkspaceId: task.WorkspaceId,
-							MainTaskId:  task.MainTaskId,
-							Options:     bruteforceOpts,
-							TaskLogger:  domainTaskLogger,
-						})
-
-						if err != nil {
-							w.taskLog(task.TaskId, LevelError, "Bruteforce error: %v", err)
-						} else if bruteResult != nil && len(bruteResult.Assets) > 0 {
-							bruteforceAssets = bruteResult.Assets
-							w.taskLog(task.TaskId, LevelInfo, "Bruteforce: found %d subdomains", len(bruteforceAssets))
-						}
-					} else {
-						w.taskLog(task.TaskId, LevelWarn, "Subdomain bruteforce scanner not available")
-					}
-				}
-			}
-		}
-
-		// 合并subfinder和bruteforce结果
-		allAssetMap := make(map[string]*scanner.Asset)
-		for _, asset := range subfinderAssets {
-			if asset.Host != "" {
-				allAssetMap[asset.Host] = asset
-			}
-		}
-		for _, asset := range bruteforceAssets {
-			if asset.Host != "" {
-				if _, exists := allAssetMap[asset.Host]; !exists {
-					allAssetMap[asset.Host] = asset
-				}
-			}
-		}
-
-		// 转换为切片
-		var mergedAssets []*scanner.Asset
-		for _, asset := range allAssetMap {
-			mergedAssets = append(mergedAssets, asset)
-		}
-
-		// 应用黑名单过滤子域名结果
-		if blacklistMatcher != nil && !blacklistMatcher.IsEmpty() {
-			mergedAssets = w.filterAssetsByBlacklist(mergedAssets, blacklistMatcher, task.TaskId)
-		}
-
-		// 应用端口扫描排除目标过滤子域名解析的IP
-		if config.PortScan != nil && config.PortScan.ExcludeHosts != "" {
-			excludeMatcher := utils.NewExcludeHostsMatcher(config.PortScan.ExcludeHosts)
-			if excludeMatcher != nil && !excludeMatcher.IsEmpty() {
-				originalCount := len(mergedAssets)
-				mergedAssets = w.filterAssetsByExcludeHosts(mergedAssets, excludeMatcher, task.TaskId)
-				if filteredCount := originalCount - len(mergedAssets); filteredCount > 0 {
-					w.taskLog(task.TaskId, LevelInfo, "ExcludeHosts: filtered %d subdomains by resolved IP", filteredCount)
-				}
-			}
-		}
-
-		if len(mergedAssets) > 0 {
-			allAssets = append(allAssets, mergedAssets...)
-		}
-		if w.handleTaskControl(ctx, task, completedPhases, allAssets, "domain scan") {
-			return
-		}
-
-		// 检查context是否被取消
-		select {
-		case <-ctx.Done():
-			w.taskLog(task.TaskId, LevelInfo, "Domain scan cancelled by context")
-			// 保存已发现的子域名资产
-			if len(mergedAssets) > 0 {
-				allAssets = append(allAssets, mergedAssets...)
-			}
-			w.saveTaskProgress(ctx, task, completedPhases, allAssets)
-			return
-		default:
-		}
-
-		if len(mergedAssets) > 0 {
-			// 保存子域名扫描结果到数据库
-			w.taskLog(task.TaskId, LevelInfo, "Saving %d subdomains to database", len(mergedAssets))
-			w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, mergedAssets)
-
-			// 将发现的子域名添加到目标列表
-			var newTargets []string
-			for _, asset := range mergedAssets {
-				if asset.Host != "" {
-					newTargets = append(newTargets, asset.Host)
-				}
-			}
-			if len(newTargets) > 0 {
-				// 更新目标（将子域名添加到原始目标）
-				target = target + "\n" + strings.Join(newTargets, "\n")
-				w.taskLog(task.TaskId, LevelInfo, "Domain scan completed: found %d subdomains (subfinder: %d, bruteforce: %d)",
-					len(newTargets), len(subfinderAssets), len(bruteforceAssets))
-			}
-		}
-
-		completedPhases["domainscan"] = true
-		// 子域名扫描模块完成，递增子任务进度
-		w.incrSubTaskDone(ctx, task, "子域名扫描")
-	}
-
-	// 执行端口扫描（只有明确启用时才执行）
-	if config.PortScan != nil && config.PortScan.Enable && !completedPhases["portscan"] {
-		// 检查控制信号
-		if w.handleTaskControl(ctx, task, completedPhases, allAssets, "") {
-			return
-		}
-
-		// 更新当前阶段
-		w.updateTaskProgressWithPhase(ctx, task.TaskId, 20, "端口扫描中", "端口扫描")
-
-		// 创建带超时的上下文，防止端口扫描卡死
-		portScanTimeout := 600 // 默认10分钟总超时
-		if config.PortScan != nil && config.PortScan.Timeout > 0 {
-			// 根据单个端口超时计算总超时（至少10分钟）
-			portScanTimeout = config.PortScan.Timeout * 100
-			if portScanTimeout < 600 {
-				portScanTimeout = 600
-			}
-		}
-		portCtx, portCancel := context.WithTimeout(ctx, time.Duration(portScanTimeout)*time.Second)
-
-		// 根据配置选择端口发现工具（默认使用Naabu)
-		portDiscoveryTool := "naabu"
-		if config.PortScan != nil && config.PortScan.Tool != "" {
-			portDiscoveryTool = config.PortScan.Tool
-		}
-
-		var openPorts []*scanner.Asset
-
-		// 创建任务日志回调
-		taskLogger := func(level, format string, args ...interface{}) {
-			w.taskLog(task.TaskId, level, format, args...)
-		}
-
-		// 创建进度回调
-		onProgress := func(progress int, message string) {
-			w.updateTaskProgress(ctx, task.TaskId, progress, message)
-		}
-
-		// 第一步：端口发现
-		switch portDiscoveryTool {
-		case "masscan":
-			w.taskLog(task.TaskId, LevelInfo, "Port scan: Masscan")
-			masscanScanner := w.scanners["masscan"]
-			masscanResult, err := masscanScanner.Scan(portCtx, &scanner.ScanConfig{
-				Target:     target,
-				Options:    config.PortScan,
-				TaskLogger: taskLogger,
-				OnProgress: onProgress,
-			})
-			// 检查是否被停止或超时
-			if portCtx.Err() == context.DeadlineExceeded {
-				w.taskLog(task.TaskId, LevelWarn, "Port scan timeout, continuing with partial results")
-			} else if ctx.Err() != nil {
-				portCancel()
-				w.taskLog(task.TaskId, LevelInfo, "Task stopped")
-				return
-			}
-			if err != nil {
-				w.taskLog(task.TaskId, LevelError, "Masscan error: %v", err)
-			}
-			if masscanResult != nil && len(masscanResult.Assets) > 0 {
-				openPorts = masscanResult.Assets
-				w.taskLog(task.TaskId, LevelInfo, "Found %d open ports", len(openPorts))
-			}
-		default: // naabu
-			w.taskLog(task.TaskId, LevelInfo, "Port scan: Naabu")
-			naabuScanner := w.scanners["naabu"]
-			naabuResult, err := naabuScanner.Scan(portCtx, &scanner.ScanConfig{
-				Target:     target,
-				Options:    config.PortScan,
-				TaskLogger: taskLogger,
-				OnProgress: onProgress,
-			})
-			// 检查是否有目标超过端口阈值（不终止任务，只记录警告）
-			if err == scanner.ErrPortThresholdExceeded {
-				w.taskLog(task.TaskId, LevelWarn, "Some targets exceeded port threshold and were skipped")
-			}
-			// 检查是否被停止或超时
-			if portCtx.Err() == context.DeadlineExceeded {
-				w.taskLog(task.TaskId, LevelWarn, "Port scan timeout, continuing with partial results")
-			} else if ctx.Err() != nil || w.checkTaskControl(ctx, task.TaskId) == "STOP" {
-				portCancel()
-				w.taskLog(task.TaskId, LevelInfo, "Task stopped")
-				return
-			}
-			if err != nil && err != scanner.ErrPortThresholdExceeded {
-				w.taskLog(task.TaskId, LevelError, "Naabu error: %v", err)
-			}
-			if naabuResult != nil && len(naabuResult.Assets) > 0 {
-				openPorts = naabuResult.Assets
-				w.taskLog(task.TaskId, LevelInfo, "Found %d open ports", len(openPorts))
-			}
-		}
-
-		// 检查是否被停止
-		if ctx.Err() != nil || w.checkTaskControl(ctx, task.TaskId) == "STOP" {
-			portCancel()
-			w.taskLog(task.TaskId, LevelInfo, "Task stopped")
-			return
-		}
-
-		// 端口发现完成，将结果添加到 allAssets
-		if len(openPorts) > 0 {
-			for _, asset := range openPorts {
-				asset.IsHTTP = scanner.IsHTTPService(asset.Service, asset.Port)
-			}
-			allAssets = append(allAssets, openPorts...)
-			w.taskLog(task.TaskId, LevelInfo, "Port scan completed: %d assets", len(allAssets))
-
-			// 端口扫描完成后立即保存结果
-			w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, allAssets)
-		} else {
-			w.taskLog(task.TaskId, LevelInfo, "No open ports found")
-		}
-
-		portCancel() // 释放端口扫描上下文
-		completedPhases["portscan"] = true
-		// 端口扫描模块完成，递增子任务进度
-		w.incrSubTaskDone(ctx, task, "端口扫描")
-	}
-
-	// 检查控制信号
-	if w.handleTaskControl(ctx, task, completedPhases, allAssets, "") {
-		return
-	}
-
-	// 执行端口识别（Nmap服务识别）- 独立阶段
-	if config.PortIdentify != nil && config.PortIdentify.Enable && !completedPhases["portidentify"] {
-		// 强制扫描模式：没有资产时从用户输入目标生成资产
-		if len(allAssets) == 0 && target != "" && config.PortIdentify.ForceScan {
-			generatedAssets := scanner.GenerateAssetsFromTargets(target)
-			if len(generatedAssets) > 0 {
-				allAssets = append(allAssets, generatedAssets...)
-				w.taskLog(task.TaskId, LevelInfo, "Port identify: generated %d assets from target (force scan)", len(generatedAssets))
-			}
-		}
-
-		// 没有资产时跳过实际扫描，但仍需递增进度
-		if len(allAssets) == 0 {
-			w.taskLog(task.TaskId, LevelInfo, "Port identify: skipped (no assets)")
-			completedPhases["portidentify"] = true
-			w.incrSubTaskDone(ctx, task, "端口识别")
-		} else {
-			// 检查控制信号
-			if w.handleTaskControl(ctx, task, completedPhases, allAssets, "") {
-				return
-			}
-
-			// 更新当前阶段
-			w.updateTaskProgressWithPhase(ctx, task.TaskId, 40, "端口识别中", "端口识别")
-
-			identifiedAssets := w.executePortIdentify(ctx, task, allAssets, config.PortIdentify)
-			if len(identifiedAssets) > 0 {
-				allAssets = identifiedAssets
-				// 端口识别完成后保存更新结果
-				w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, allAssets)
-			}
-			completedPhases["portidentify"] = true
-			// 端口识别模块完成，递增子任务进度
-			w.incrSubTaskDone(ctx, task, "端口识别")
-		}
-	}
-
-	// 检查控制信号
-	if w.handleTaskControl(ctx, task, completedPhases, allAssets, "") {
-		return
-	}
-
-	// 执行指纹识别
-	if config.Fingerprint != nil && config.Fingerprint.Enable && !completedPhases["fingerprint"] {
-		// 强制扫描模式：没有资产时从用户输入目标生成资产
-		if len(allAssets) == 0 && target != "" && config.Fingerprint.ForceScan {
-			generatedAssets := scanner.GenerateAssetsFromTargets(target)
-			if len(generatedAssets) > 0 {
-				allAssets = append(allAssets, generatedAssets...)
-				w.taskLog(task.TaskId, LevelInfo, "Fingerprint: generated %d assets from target (force scan)", len(generatedAssets))
-			}
-		}
-
-		// 没有资产时跳过实际扫描，但仍需递增进度
-		if len(allAssets) == 0 {
-			w.taskLog(task.TaskId, LevelInfo, "Fingerprint: skipped (no assets)")
-			completedPhases["fingerprint"] = true
-			w.incrSubTaskDone(ctx, task, "指纹识别")
-		} else {
-			// 在指纹识别开始前检查停止信号
-			if ctrl := w.checkTaskControl(ctx, task.TaskId); ctrl == "STOP" {
-				w.taskLog(task.TaskId, LevelInfo, "Task stopped")
-				return
-			} else if ctrl == "PAUSE" {
-				w.taskLog(task.TaskId, LevelInfo, "Task paused, saving progress...")
-				w.saveTaskProgress(ctx, task, completedPhases, allAssets)
-				return
-			}
-
-			// 更新当前阶段
-			w.updateTaskProgressWithPhase(ctx, task.TaskId, 60, "指纹识别中", "指纹识别")
-
-			if s, ok := w.scanners["fingerprint"]; ok {
-				// 根据过滤模式处理资产
-				assetsToScan := allAssets
-				filterMode := config.Fingerprint.FilterMode
-				if filterMode == "" {
-					filterMode = "http_mapping" // 默认使用HTTP映射模式
-				}
-
-				if filterMode == "service_mapping" {
-					// 模式B：使用服务映射过滤非HTTP服务
-					var httpAssets []*scanner.Asset
-					nonHttpCount := 0
-
-					for _, asset := range allAssets {
-						// 通过全局HTTP服务检查器判断服务是否为HTTP
-						// 如果服务映射中明确标识为非HTTP，则排除
-						if globalHttpServiceChecker := scanner.GetHttpServiceChecker(); globalHttpServiceChecker != nil {
-							serviceLower := strings.ToLower(asset.Service)
-							if isHttp, found := globalHttpServiceChecker.IsHttpService(serviceLower); found {
-								if !isHttp {
-									// 服务映射中明确标识为非HTTP，排除
-									nonHttpCount++
-									continue
-								}
-							}
-							// 未在服务映射中找到或标识为HTTP，保留
-						}
-						httpAssets = append(httpAssets, asset)
-					}
-
-					assetsToScan = httpAssets
-					w.taskLog(task.TaskId, LevelInfo, "Fingerprint: FilterMode=service_mapping, filtered %d assets (excluded %d non-HTTP services), remaining %d assets",
-						len(allAssets), nonHttpCount, len(httpAssets))
-				} else {
-					// 模式A：使用HTTP映射（默认行为，不做额外过滤）
-					w.taskLog(task.TaskId, LevelInfo, "Fingerprint: FilterMode=http_mapping, using all %d assets", len(allAssets))
-				}
-
-				// 获取单目标超时配置
-				targetTimeout := config.Fingerprint.TargetTimeout
-				if targetTimeout <= 0 {
-					targetTimeout = 30 // 默认30秒
-				}
-				// 使用Worker并发数覆盖配置中的并发数
-				config.Fingerprint.Concurrency = w.config.Concurrency
-				w.taskLog(task.TaskId, LevelInfo, "Fingerprint: %d assets, timeout %ds/target, concurrency=%d, activeScan=%v, filterMode=%s",
-					len(assetsToScan), targetTimeout, w.config.Concurrency, config.Fingerprint.ActiveScan, filterMode)
-
-				// 每次扫描前实时加载HTTP服务映射配置
-				w.loadHttpServiceMappings()
-
-				// 如果启用自定义指纹引擎，加载自定义指纹（包括主动指纹）
-				if config.Fingerprint.CustomEngine {
-					w.loadCustomFingerprints(ctx, s.(*scanner.FingerprintScanner), config.Fingerprint.ActiveScan)
-				}
-
-				// 创建带超时的上下文，防止指纹识别卡死
-				fingerprintTimeout := config.Fingerprint.Timeout
-				if fingerprintTimeout <= 0 {
-					fingerprintTimeout = 300 // 默认5分钟总超时
-				}
-				fpCtx, fpCancel := context.WithTimeout(ctx, time.Duration(fingerprintTimeout)*time.Second)
-
-				// 创建任务日志回调
-				fpTaskLogger := func(level, format string, args ...interface{}) {
-					w.taskLog(task.TaskId, level, format, args...)
-				}
-
-				result, err := s.Scan(fpCtx, &scanner.ScanConfig{
-					Assets:     assetsToScan,
-					Options:    config.Fingerprint,
-					TaskLogger: fpTaskLogger,
-				})
-				fpCancel()
-
-				// 检查是否超时
-				if fpCtx.Err() == context.DeadlineExceeded {
-					w.taskLog(task.TaskId, LevelWarn, "Fingerprint scan timeout after %ds, continuing with partial results", fingerprintTimeout)
-				}
-
-				// 检查是否被取消
-				if ctx.Err() != nil || w.checkTaskControl(ctx, task.TaskId) == "STOP" {
-					w.taskLog(task.TaskId, LevelInfo, "Task stopped")
-					return
-				}
-
-				if err == nil && result != nil {
-					// 构建 Host:Port -> Asset 的映射，用于匹配指纹结果
-					assetMap := make(map[string]*scanner.Asset)
-					for _, asset := range allAssets {
-						key := fmt.Sprintf("%s:%d", asset.Host, asset.Port)
-						assetMap[key] = asset
-					}
-
-					// 通过 Host:Port 匹配来更新资产信息，而不是按索引
-					for _, fpAsset := range result.Assets {
-						key := fmt.Sprintf("%s:%d", fpAsset.Host, fpAsset.Port)
-						if originalAsset, ok := assetMap[key]; ok {
-							originalAsset.Service = fpAsset.Service
-							originalAsset.Title = fpAsset.Title
-							originalAsset.App = fpAsset.App
-							originalAsset.HttpStatus = fpAsset.HttpStatus
-							originalAsset.HttpHeader = fpAsset.HttpHeader
-							originalAsset.HttpBody = fpAsset.HttpBody
-							originalAsset.Server = fpAsset.Server
-							originalAsset.IconHash = fpAsset.IconHash
-							if len(fpAsset.IconData) > 0 {
-								originalAsset.IconData = fpAsset.IconData
-							}
-							originalAsset.Screenshot = fpAsset.Screenshot
-						}
-					}
-
-					// 指纹识别完成后保存更新结果
-					w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, allAssets)
-				}
-			}
-			completedPhases["fingerprint"] = true
-			// 指纹识别模块完成，递增子任务进度
-			w.incrSubTaskDone(ctx, task, "指纹识别")
-		} // 结束 len(allAssets) > 0 的 else 分支
-	}
-
-	// 检查控制信号
-	if w.handleTaskControl(ctx, task, completedPhases, allAssets, "") {
-		return
-	}
-
-	// 执行目录扫描（在指纹识别之后、POC扫描之前）
-	if config.DirScan != nil && config.DirScan.Enable && !completedPhases["dirscan"] {
-		// 强制扫描模式：没有资产时从用户输入目标生成资产
-		if len(allAssets) == 0 && target != "" && config.DirScan.ForceScan {
-			generatedAssets := scanner.GenerateAssetsFromTargets(target)
-			if len(generatedAssets) > 0 {
-				allAssets = append(allAssets, generatedAssets...)
-				w.taskLog(task.TaskId, LevelInfo, "Dir scan: generated %d assets from target (force scan)", len(generatedAssets))
-			}
-		}
-
-		// 仍然没有资产时跳过
-		if len(allAssets) == 0 {
-			w.taskLog(task.TaskId, LevelInfo, "Dir scan: skipped (no assets)")
-			completedPhases["dirscan"] = true
-			w.incrSubTaskDone(ctx, task, "目录扫描")
-		} else {
-			// 检查控制信号
-			if w.handleTaskControl(ctx, task, completedPhases, allAssets, "") {
-				return
-			}
-
-			// 更新当前阶段
-			w.updateTaskProgressWithPhase(ctx, task.TaskId, 70, "目录扫描中", "目录扫描")
-
-			// 执行目录扫描
-			dirScanAssets := w.executeDirScan(ctx, task, allAssets, config.DirScan, orgId)
-			if len(dirScanAssets) > 0 {
-				// 注意：目录扫描结果不添加到 allAssets，避免影响后续 POC 扫描
-				// 目录扫描结果是 URL 路径，不是独立的扫描目标
-				w.taskLog(task.TaskId, LevelInfo, "Dir scan completed: found %d paths", len(dirScanAssets))
-				// 目录扫描结果已在 executeDirScan 中通过 saveDirScanResults 保存到数据库
-			}
-			completedPhases["dirscan"] = true
-			w.incrSubTaskDone(ctx, task, "目录扫描")
-		}
-	}
-
-	// 检查控制信号
-	if w.handleTaskControl(ctx, task, completedPhases, allAssets, "") {
-		return
-	}
-
-	// 执行POC扫描 (使用Nuclei引擎)
-	if config.PocScan != nil && config.PocScan.Enable && !completedPhases["pocscan"] {
-		// 强制扫描模式：没有资产时从用户输入目标生成资产
-		if len(allAssets) == 0 && target != "" && config.PocScan.ForceScan {
-			generatedAssets := scanner.GenerateAssetsFromTargets(target)
-			if len(generatedAssets) > 0 {
-				allAssets = append(allAssets, generatedAssets...)
-				w.taskLog(task.TaskId, LevelInfo, "POC scan: generated %d assets from target (force scan)", len(generatedAssets))
-			}
-		}
-
-		// 没有资产时跳过实际扫描，但仍需递增进度
-		if len(allAssets) == 0 {
-			w.taskLog(task.TaskId, LevelInfo, "POC scan: skipped (no assets)")
-			completedPhases["pocscan"] = true
-			w.incrSubTaskDone(ctx, task, "漏洞扫描")
-		} else {
-			// 在POC扫描开始前检查停止信号
-			if ctrl := w.checkTaskControl(ctx, task.TaskId); ctrl == "STOP" {
-				w.taskLog(task.TaskId, LevelInfo, "Task stopped")
-				return
-			} else if ctrl == "PAUSE" {
-				w.taskLog(task.TaskId, LevelInfo, "Task paused, saving progress...")
-				w.saveTaskProgress(ctx, task, completedPhases, allAssets)
-				return
-			}
-
-			// 更新当前阶段
-			w.updateTaskProgressWithPhase(ctx, task.TaskId, 80, "漏洞扫描中", "漏洞扫描")
-
-			if s, ok := w.scanners["nuclei"]; ok {
-				// 获取单目标超时配置
-				pocTargetTimeout := config.PocScan.TargetTimeout
-				if pocTargetTimeout <= 0 {
-					pocTargetTimeout = 600 // 默认600秒
-				}
-				w.taskLog(task.TaskId, LevelInfo, "POC scan: %d assets, timeout %ds/target", len(allAssets), pocTargetTimeout)
-
-				// 从数据库获取模板（所有模板都存储在数据库中）
-				var templates []string
-				var autoTags []string
-
-				// 检查是否有模板ID列表（任务创建时已筛选好的模板）
-				if len(config.PocScan.NucleiTemplateIds) > 0 || len(config.PocScan.CustomPocIds) > 0 {
-					// 通过RPC根据ID获取模板内容（包括默认模板和自定义POC)
-					w.taskLog(task.TaskId, LevelInfo, "POC template request: nucleiTemplateIds=%d, customPocIds=%d", len(config.PocScan.NucleiTemplateIds), len(config.PocScan.CustomPocIds))
-					templates = w.getTemplatesByIds(ctx, config.PocScan.NucleiTemplateIds, config.PocScan.CustomPocIds)
-					w.taskLog(task.TaskId, LevelInfo, "Loaded %d POC templates", len(templates))
-				} else if config.PocScan.CustomPocOnly {
-					// 只使用自定义POC模式，但没有指定具体ID，获取所有自定义POC
-					severities := []string{}
-					if config.PocScan.Severity != "" {
-						severities = strings.Split(config.PocScan.Severity, ",")
-					}
-					templates = w.getAllCustomPocs(ctx, severities)
-					w.taskLog(task.TaskId, LevelInfo, "CustomPocOnly mode: loaded %d custom POC templates", len(templates))
-				} else {
-					// 没有预设的模板ID，根据自动扫描配置生成标签并获取模板
-					var matchInfos []TagMatchInfo
-					if config.PocScan.AutoScan || config.PocScan.AutomaticScan {
-						autoTags, matchInfos = w.generateAutoTags(allAssets, config.PocScan)
-						// 输出匹配信息日志
-						for _, info := range matchInfos {
-							sourceDesc := "自定义标签映射"
-							if info.Source == "builtin" {
-								sourceDesc = "内置映射"
-							}
-							w.taskLog(task.TaskId, LevelInfo, "Auto-scan matched: fingerprint [%s] -> tags %v (source: %s)", info.Fingerprint, info.Tags, sourceDesc)
-						}
-					}
-
-					if len(autoTags) > 0 {
-						// 有自动生成的标签，通过RPC获取符合标签的模板
-						severities := []string{}
-						if config.PocScan.Severity != "" {
-							severities = strings.Split(config.PocScan.Severity, ",")
-						}
-						w.taskLog(task.TaskId, LevelInfo, "POC template auto selection: tags=%v", autoTags)
-						templates = w.getTemplatesByTags(ctx, autoTags, severities)
-						w.taskLog(task.TaskId, LevelInfo, "Loaded %d POC templates", len(templates))
-					} else {
-						// 没有模板ID也没有自动标签，记录警告
-						w.taskLog(task.TaskId, LevelWarn, "No POC templates configured, skipping POC scan")
-					}
-				}
-
-				// 只有在有模板时才执行扫描
-				if len(templates) > 0 {
-					// 用于统计漏洞数量
-					var vulCount int
-
-					// 创建漏洞缓冲区，发现漏洞立即保存
-					vulBuffer := NewVulnerabilityBuffer(1)
-
-					// 获取单目标超时配置
-					targetTimeout := config.PocScan.TargetTimeout
-					if targetTimeout <= 0 {
-						targetTimeout = 600 // 默认600秒
-					}
-
-					// 总超时基于目标数量和单目标超时计算，至少10分钟
-					pocTimeout := targetTimeout * len(allAssets)
-					if pocTimeout < 600 {
-						pocTimeout = 600
-					}
-					pocCtx, pocCancel := context.WithTimeout(ctx, time.Duration(pocTimeout)*time.Second)
-
-					// 启动后台刷新协程
-					flushDone := make(chan struct{})
-					go func() {
-						defer close(flushDone)
-						ticker := time.NewTicker(5 * time.Second) // 5秒也刷新一次
-						defer ticker.Stop()
-						for {
-							select {
-							case <-pocCtx.Done():
-								return
-							case <-flushDone:
-								return
-							case <-vulBuffer.flushChan:
-								vulBuffer.Flush(pocCtx, func(vuls []*scanner.Vulnerability) {
-									w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, vuls)
-								})
-							case <-ticker.C:
-								vulBuffer.Flush(pocCtx, func(vuls []*scanner.Vulnerability) {
-									w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, vuls)
-								})
-							}
-						}
-					}()
-
-					// 构建Nuclei扫描选项，设置回调函数批量保存漏洞
-					taskIdForCallback := task.TaskId // 捕获taskId用于回调
-
-					nucleiOpts := &scanner.NucleiOptions{
-						Severity:        config.PocScan.Severity,
-						Tags:            autoTags,
-						ExcludeTags:     config.PocScan.ExcludeTags,
-						RateLimit:       config.PocScan.RateLimit,
-						Concurrency:     config.PocScan.Concurrency,
-						Timeout:         pocTimeout,
-						TargetTimeout:   targetTimeout,
-						AutoScan:        false, // 标签已在Worker端生成
-						AutomaticScan:   false,
-						CustomPocOnly:   config.PocScan.CustomPocOnly,
-						CustomTemplates: templates,
-						TagMappings:     config.PocScan.TagMappings,
-						CustomHeaders:   config.PocScan.CustomHeaders,
-						// 设置回调函数，发现漏洞时添加到缓冲区
-						OnVulnerabilityFound: func(vul *scanner.Vulnerability) {
-							vulCount++
-							w.taskLog(taskIdForCallback, LevelInfo, "Vulnerability found: %s → %s", vul.PocFile, vul.Url)
-							vulBuffer.Add(vul)
-						},
-					}
-					// 设置默认
-					if nucleiOpts.RateLimit == 0 {
-						nucleiOpts.RateLimit = 150
-					}
-					if nucleiOpts.Concurrency == 0 {
-						nucleiOpts.Concurrency = 25
-					}
-
-					// 创建任务日志回调
-					pocTaskLogger := func(level, format string, args ...interface{}) {
-						w.taskLog(task.TaskId, level, format, args...)
-					}
-
-					result, err := s.Scan(pocCtx, &scanner.ScanConfig{
-						Assets:     allAssets,
-						Options:    nucleiOpts,
-						TaskLogger: pocTaskLogger,
-					})
-					pocCancel()
-
-					// 扫描完成后，刷新剩余的漏洞
-					vulBuffer.Flush(ctx, func(vuls []*scanner.Vulnerability) {
-						w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, vuls)
-					})
-
-					// 检查是否超时
-					if pocCtx.Err() == context.DeadlineExceeded {
-						w.taskLog(task.TaskId, LevelWarn, "POC scan timeout after %ds, continuing with partial results", pocTimeout)
-					}
-
-					// 检查是否被停止
-					if ctx.Err() != nil || w.checkTaskControl(ctx, task.TaskId) == "STOP" {
-						w.taskLog(task.TaskId, LevelInfo, "Task stopped")
-						return
-					}
-
-					if err != nil {
-						w.taskLog(task.TaskId, LevelError, "POC scan error: %v", err)
-					}
-					if result != nil {
-						allVuls = append(allVuls, result.Vulnerabilities...)
-						if vulCount > 0 {
-							w.taskLog(task.TaskId, LevelInfo, "POC scan completed: found %d vulnerabilities", vulCount)
-						}
-					}
-				}
-			}
-			// POC扫描模块完成，递增子任务进度
-			w.incrSubTaskDone(ctx, task, "漏洞扫描")
-		} // 结束 len(allAssets) > 0 的 else 分支
-	}
-
-	// 更新任务状态为完成
-	duration := time.Since(startTime).Seconds()
-	result := fmt.Sprintf("Assets:%d Vuls:%d Duration:%.0fs", len(allAssets), len(allVuls), duration)
-
-	w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusSuccess, result)
-	w.taskLog(task.TaskId, LevelInfo, "Completed: %s", result)
+			seed = w.generateAssetsFromTarget(target, config.PortScan)
+			if len(seed) > 0 {
+				w.taskLog(task.TaskId, LevelInfo, "Generated %d assets from target (port scan disabled)", len(seed))
+			}
+		}
+	}
+
+	// 旧版配置映射为默认线性工作流，与声明式工作流使用同一套阶段执行器
+	w.executeWorkflow(ctx, &workflowRun{
+		w:           w,
+		task:        task,
+		config:      config,
+		target:      target,
+		orgId:       orgId,
+		blacklist:   blacklistMatcher,
+		seed:        seed,
+		completed:   completedPhases,
+		restored:    allAssets,
+		stageAssets: stageAssets,
+	}, startTime)
 	// 注意：taskExecuted 由 defer 递增，无需在此处理
 }
 
@@ -2156,10 +1355,15 @@ func (w *Worker) saveAssetResult(ctx context.Context, workspaceId, mainTaskId, o
 		}
 	}()
 
+	// 范围外的资产只记录不入库
+	assets = w.filterAssetsByScope(workspaceId, mainTaskId, mainTaskId, "result", assets)
 	if len(assets) == 0 {
 		return
 	}
 
+	// 补全IP归属地和ASN
+	w.enrichGeo(ctx, mainTaskId, assets)
+
 	// 分批保存，每批最多500个
 	batchSize := 500
 	totalAssets := len(assets)
@@ -2180,46 +1384,7 @@ func (w *Worker) saveAssetResult(ctx context.Context, workspaceId, mainTaskId, o
 		httpAssets := make([]AssetDocument, 0, len(batchAssets))
 
 		for _, asset := range batchAssets {
-			httpAsset := AssetDocument{
-				Authority:  asset.Authority,
-				Host:       asset.Host,
-				Port:       int32(asset.Port),
-				Category:   asset.Category,
-				Service:    asset.Service,
-				Title:      asset.Title,
-				App:        asset.App,
-				HttpStatus: asset.HttpStatus,
-				HttpHeader: asset.HttpHeader,
-				HttpBody:   asset.HttpBody,
-				IconHash:   asset.IconHash,
-				IconData:   asset.IconData,
-				Screenshot: asset.Screenshot,
-				Server:     asset.Server,
-				Banner:     asset.Banner,
-				IsHttp:     asset.IsHTTP,
-				Cname:      asset.CName,
-				IsCdn:      asset.IsCDN,
-				IsCloud:    asset.IsCloud,
-				Source:     asset.Source,
-			}
-
-			// 添加IPv4信息
-			for _, ip := range asset.IPV4 {
-				httpAsset.Ipv4 = append(httpAsset.Ipv4, IPV4Info{
-					IP:       ip.IP,
-					Location: ip.Location,
-				})
-			}
-
-			// 添加IPv6信息
-			for _, ip := range asset.IPV6 {
-				httpAsset.Ipv6 = append(httpAsset.Ipv6, IPV6Info{
-					IP:       ip.IP,
-					Location: ip.Location,
-				})
-			}
-
-			httpAssets = append(httpAssets, httpAsset)
+			httpAssets = append(httpAssets, toAssetDocument(asset))
 		}
 
 		// 使用独立的超时上下文，每批30秒超时
@@ -2244,6 +1409,95 @@ func (w *Worker) saveAssetResult(ctx context.Context, workspaceId, mainTaskId, o
 	w.taskLog(mainTaskId, LevelInfo, "Save completed: total=%d, new=%d, update=%d", totalAssets, totalNew, totalUpdate)
 }
 
+// newFingerprintMerger 返回按 Host:Port 将指纹识别结果合并到原资产的函数，返回被更新的原资产，未匹配时返回 nil
+func newFingerprintMerger(assets []*scanner.Asset) func(fpAsset *scanner.Asset) *scanner.Asset {
+	assetMap := make(map[string]*scanner.Asset, len(assets))
+	for _, asset := range assets {
+		assetMap[fmt.Sprintf("%s:%d", asset.Host, asset.Port)] = asset
+	}
+	return func(fpAsset *scanner.Asset) *scanner.Asset {
+		originalAsset, ok := assetMap[fmt.Sprintf("%s:%d", fpAsset.Host, fpAsset.Port)]
+		if !ok || originalAsset == fpAsset {
+			return originalAsset
+		}
+		originalAsset.Service = fpAsset.Service
+		originalAsset.Title = fpAsset.Title
+		originalAsset.App = fpAsset.App
+		originalAsset.HttpStatus = fpAsset.HttpStatus
+		originalAsset.HttpHeader = fpAsset.HttpHeader
+		originalAsset.HttpBody = fpAsset.HttpBody
+		originalAsset.Server = fpAsset.Server
+		originalAsset.IconHash = fpAsset.IconHash
+		if len(fpAsset.IconData) > 0 {
+			originalAsset.IconData = fpAsset.IconData
+		}
+		originalAsset.Screenshot = fpAsset.Screenshot
+		originalAsset.CPE = fpAsset.CPE
+		return originalAsset
+	}
+}
+
+// toAssetDocument 将扫描资产转换为上报文档
+func toAssetDocument(asset *scanner.Asset) AssetDocument {
+	httpAsset := AssetDocument{
+		Authority:     asset.Authority,
+		Host:          asset.Host,
+		Port:          int32(asset.Port),
+		Transport:     asset.Transport,
+		Category:      asset.Category,
+		Service:       asset.Service,
+		Title:         asset.Title,
+		App:           asset.App,
+		HttpStatus:    asset.HttpStatus,
+		HttpHeader:    asset.HttpHeader,
+		HttpBody:      asset.HttpBody,
+		Cert:          asset.Cert,
+		CertInfo:      asset.CertInfo,
+		IconHash:      asset.IconHash,
+		IconData:      asset.IconData,
+		Screenshot:    asset.Screenshot,
+		Server:        asset.Server,
+		Banner:        asset.Banner,
+		IsHttp:        asset.IsHTTP,
+		Cname:         asset.CName,
+		IsCdn:         asset.IsCDN,
+		IsCloud:       asset.IsCloud,
+		Source:        asset.Source,
+		CDNProvider:   asset.CDNProvider,
+		CloudProvider: asset.CloudProvider,
+		WAF:           asset.WAF,
+		CPE:           asset.CPE,
+	}
+
+	// 添加IPv4信息
+	for _, ip := range asset.IPV4 {
+		httpAsset.Ipv4 = append(httpAsset.Ipv4, IPV4Info{
+			IP:       ip.IP,
+			Location: ip.Location,
+			Country:  ip.Country,
+			Region:   ip.Region,
+			City:     ip.City,
+			ASN:      ip.ASN,
+			Org:      ip.Org,
+		})
+	}
+
+	// 添加IPv6信息
+	for _, ip := range asset.IPV6 {
+		httpAsset.Ipv6 = append(httpAsset.Ipv6, IPV6Info{
+			IP:       ip.IP,
+			Location: ip.Location,
+			Country:  ip.Country,
+			Region:   ip.Region,
+			City:     ip.City,
+			ASN:      ip.ASN,
+			Org:      ip.Org,
+		})
+	}
+
+	return httpAsset
+}
+
 // saveVulResult 保存漏洞结果（支持去重与聚合）
 func (w *Worker) saveVulResult(ctx context.Context, workspaceId, mainTaskId string, vuls []*scanner.Vulnerability) {
 	// 添加 panic 恢复机制
@@ -2815,20 +2069,22 @@ func (w *Worker) loadCustomFingerprints(ctx context.Context, fpScanner *scanner.
 		// 转换为model.Fingerprint（被动指纹）
 		for _, fp := range resp.Fingerprints {
 			mfp := &model.Fingerprint{
-				Name:      fp.Name,
-				Category:  fp.Category,
-				Rule:      fp.Rule,
-				Source:    fp.Source,
-				Headers:   fp.Headers,
-				Cookies:   fp.Cookies,
-				HTML:      fp.Html,
-				Scripts:   fp.Scripts,
-				ScriptSrc: fp.ScriptSrc,
-				Meta:      fp.Meta,
-				CSS:       fp.Css,
-				URL:       fp.Url,
-				IsBuiltin: fp.IsBuiltin,
-				Enabled:   fp.Enabled,
+				Name:       fp.Name,
+				Category:   fp.Category,
+				Rule:       fp.Rule,
+				Source:     fp.Source,
+				Headers:    fp.Headers,
+				Cookies:    fp.Cookies,
+				HTML:       fp.Html,
+				Scripts:    fp.Scripts,
+				ScriptSrc:  fp.ScriptSrc,
+				Meta:       fp.Meta,
+				CSS:        fp.Css,
+				URL:        fp.Url,
+				CPE:        fp.CPE,
+				Extractors: fp.Extractors,
+				IsBuiltin:  fp.IsBuiltin,
+				Enabled:    fp.Enabled,
 			}
 			// 解析ID
 			if fp.Id != "" {
@@ -2889,6 +2145,12 @@ func (w *Worker) loadCustomFingerprints(ctx context.Context, fpScanner *scanner.
 					w.logger.Debug("Active fingerprint '%s' loaded with rule from API: %s", afp.Name, mfp.Rule)
 				}
 
+				// 版本提取规则和CPE沿用同名被动指纹
+				if passiveFp := passiveFpMap[strings.ToLower(afp.Name)]; passiveFp != nil {
+					mfp.CPE = passiveFp.CPE
+					mfp.Extractors = passiveFp.Extractors
+				}
+
 				// 解析ID
 				if afp.Id != "" {
 					if oid, err := primitive.ObjectIDFromHex(afp.Id); err == nil {
@@ -3483,6 +2745,22 @@ func (w *Worker) executePortIdentify(ctx context.Context, task *scheduler.TaskIn
 		}
 	}()
 
+	// UDP资产已由协议探测识别服务，Nmap/Fingerprintx 按TCP连接识别，不参与
+	var tcpAssets, udpAssets []*scanner.Asset
+	for _, asset := range assets {
+		if asset.Transport == scanner.TransportUDP {
+			udpAssets = append(udpAssets, asset)
+		} else {
+			tcpAssets = append(tcpAssets, asset)
+		}
+	}
+	if len(udpAssets) > 0 {
+		if len(tcpAssets) == 0 {
+			return udpAssets
+		}
+		return append(w.executePortIdentify(ctx, task, tcpAssets, config), udpAssets...)
+	}
+
 	// 确定使用的工具
 	tool := config.Tool
 	if tool == "" {
@@ -3819,8 +3097,28 @@ func (w *Worker) executeDirScan(ctx context.Context, task *scheduler.TaskInfo, a
 		w.taskLog(task.TaskId, level, format, args...)
 	}
 
+	// 每个目标扫描完成后立即保存该目标的路径，任务中途停止时已完成目标的结果不会丢失
+	// ffuf 在目标完成时先回调该目标的全部路径，再回调进度
+	var targetPaths, unsaved []*scanner.Asset
+	saveTargetPaths := func() {
+		if len(targetPaths) > 0 && !w.saveDirScanResults(ctx, task, targetPaths) {
+			unsaved = append(unsaved, targetPaths...)
+		}
+		targetPaths = nil
+	}
+	// 保存失败的路径在阶段结束时重试一次
+	saveRemaining := func() {
+		saveTargetPaths()
+		if len(unsaved) > 0 {
+			w.taskLog(task.TaskId, LevelInfo, "Dir scan: retrying %d unsaved results", len(unsaved))
+			w.saveDirScanResults(ctx, task, unsaved)
+			unsaved = nil
+		}
+	}
+
 	// 创建进度回调
 	onProgress := func(progress int, message string) {
+		saveTargetPaths()
 		w.updateTaskProgress(ctx, task.TaskId, 70+progress/5, message) // 70-90%
 	}
 
@@ -3832,7 +3130,11 @@ func (w *Worker) executeDirScan(ctx context.Context, task *scheduler.TaskInfo, a
 		MainTaskId:  task.MainTaskId,
 		TaskLogger:  taskLogger,
 		OnProgress:  onProgress,
+		OnResult: func(asset *scanner.Asset) {
+			targetPaths = append(targetPaths, asset)
+		},
 	})
+	saveRemaining()
 
 	// 检查是否超时
 	if dirCtx.Err() == context.DeadlineExceeded {
@@ -3851,7 +3153,6 @@ func (w *Worker) executeDirScan(ctx context.Context, task *scheduler.TaskInfo, a
 		// 如果有部分结果，仍然返回
 		if result != nil && len(result.Assets) > 0 {
 			w.taskLog(task.TaskId, LevelInfo, "Dir scan: returning %d partial results despite error", len(result.Assets))
-			w.saveDirScanResults(ctx, task, result.Assets)
 			return result.Assets
 		}
 		return nil
@@ -3859,28 +3160,25 @@ func (w *Worker) executeDirScan(ctx context.Context, task *scheduler.TaskInfo, a
 
 	if result != nil && len(result.Assets) > 0 {
 		w.taskLog(task.TaskId, LevelInfo, "Dir scan completed: found %d paths", len(result.Assets))
-
-		// 保存目录扫描结果到数据库
-		w.saveDirScanResults(ctx, task, result.Assets)
-
 		return result.Assets
 	}
 
 	return nil
 }
 
-// saveDirScanResults 保存目录扫描结果到数据库
-func (w *Worker) saveDirScanResults(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset) {
+// saveDirScanResults 保存目录扫描结果到数据库，返回是否保存成功
+func (w *Worker) saveDirScanResults(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset) (saved bool) {
 	// 添加 panic 恢复机制
 	defer func() {
 		if r := recover(); r != nil {
 			w.taskLog(task.TaskId, LevelError, "Save directory scan results panic recovered: %v, stack: %s", r, string(getStackTrace()))
+			saved = false
 		}
 	}()
 
 	if len(assets) == 0 {
 		w.taskLog(task.TaskId, LevelDebug, "Dir scan: no assets to save")
-		return
+		return true
 	}
 
 	w.taskLog(task.TaskId, LevelInfo, "Dir scan: saving %d results to database", len(assets))
@@ -3897,7 +3195,7 @@ func (w *Worker) saveDirScanResults(ctx context.Context, task *scheduler.TaskInf
 		if (scheme == "http" && asset.Port == 80) || (scheme == "https" && asset.Port == 443) {
 			fullURL = fmt.Sprintf("%s://%s%s", scheme, asset.Host, asset.Path)
 		} else {
-			fullURL = fmt.Sprintf("%s://%s:%d%s", scheme, asset.Host, asset.Port, asset.Path)
+			fullURL = fmt.Sprintf("%s://%s%s", scheme, utils.BuildTargetWithPort(asset.Host, asset.Port), asset.Path)
 		}
 
 		results = append(results, DirScanResultDocument{
@@ -3925,17 +3223,21 @@ func (w *Worker) saveDirScanResults(ctx context.Context, task *scheduler.TaskInf
 
 	w.taskLog(task.TaskId, LevelDebug, "Dir scan: calling SaveDirScanResult API with %d results", len(results))
 
-	resp, err := w.httpClient.SaveDirScanResult(ctx, req)
+	// 使用独立的超时上下文，任务取消后仍能保存已完成目标的结果
+	saveCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := w.httpClient.SaveDirScanResult(saveCtx, req)
 	if err != nil {
 		w.taskLog(task.TaskId, LevelError, "Dir scan: save results failed: %v", err)
-		return
+		return false
 	}
 
 	if resp.Success {
 		w.taskLog(task.TaskId, LevelInfo, "Dir scan: saved %d results to database", resp.Total)
-	} else {
-		w.taskLog(task.TaskId, LevelWarn, "Dir scan: save results response: %s", resp.Msg)
+		return true
 	}
+	w.taskLog(task.TaskId, LevelWarn, "Dir scan: save results response: %s", resp.Msg)
+	return false
 }
 
 // parseStatusCode 解析状态码字符串为整数
@@ -4068,7 +3370,7 @@ func (w *Worker) generateAssetsFromTarget(target string, portConfig *scheduler.P
 				asset := &scanner.Asset{
 					Host:      host,
 					Port:      port,
-					Authority: fmt.Sprintf("%s:%d", host, port),
+					Authority: utils.BuildTargetWithPort(host, port),
 					IsHTTP:    scanner.IsHTTPService("", port),
 				}
 				assets = append(assets, asset)
@@ -0,0 +1,607 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cscan/pkg/utils"
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// workflowStageKey 阶段输出在 StageInput.Data 中的键
+func workflowStageKey(id string) string {
+	return "stage:" + id
+}
+
+// workflowFlow 阶段输出，下游阶段以上游输出的并集为输入
+type workflowFlow struct {
+	Target string           // 目标，子域名扫描会追加发现的子域名
+	Assets []*scanner.Asset // 资产，与固定流程中累积的资产列表一致
+}
+
+// workflowRun 一次工作流执行的共享状态
+type workflowRun struct {
+	w           *Worker
+	task        *scheduler.TaskInfo
+	config      *scheduler.TaskConfig
+	target      string
+	orgId       string
+	blacklist   *utils.BlacklistMatcher
+	seed        []*scanner.Asset    // 无依赖阶段的初始资产
+	completed   map[string]bool     // 已完成阶段ID（恢复执行时跳过）
+	restored    []*scanner.Asset    // 暂停时保存的资产
+	stageAssets map[string][]string // 已完成阶段输出资产的 authority，恢复执行时还原阶段输出
+	mu          sync.Mutex
+	flow        []*scanner.Asset // 当前所有阶段输出资产的并集，暂停时保存
+	progress    int              // 管道在阶段开始前报告的进度
+	paused      bool
+}
+
+// executeWorkflow 执行任务工作流，未声明工作流的配置按默认线性工作流执行
+func (w *Worker) executeWorkflow(ctx context.Context, run *workflowRun, startTime time.Time) {
+	task := run.task
+	result, err := run.execute(ctx)
+
+	if run.paused {
+		w.saveWorkflowProgress(ctx, task, run.completed, run.flowAssets(), run.stageAssets)
+		return
+	}
+	if ctx.Err() != nil || w.checkTaskControl(ctx, task.TaskId) == "STOP" {
+		w.taskLog(task.TaskId, LevelInfo, "Task stopped")
+		return
+	}
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "Workflow failed: %v", err)
+		w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusFailure, err.Error())
+		return
+	}
+
+	duration := time.Since(startTime).Seconds()
+	summary := fmt.Sprintf("Assets:%d Vuls:%d Duration:%.0fs", len(run.flowAssets()), len(result.Vulnerabilities), duration)
+	w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusSuccess, summary)
+	w.taskLog(task.TaskId, LevelInfo, "Completed: %s", summary)
+}
+
+// execute 通过 scanner.Pipeline 按拓扑顺序执行工作流阶段
+// 每个阶段复用 TaskRunner 中对应阶段类型的执行器，结果由执行器自行保存
+func (r *workflowRun) execute(ctx context.Context) (*scanner.ScanResult, error) {
+	w, task := r.w, r.task
+	stages, err := r.config.GetWorkflow().TopoOrder()
+	if err != nil {
+		return nil, err
+	}
+	if r.completed == nil {
+		r.completed = make(map[string]bool)
+	}
+	if r.flow == nil {
+		r.flow = r.restored
+	}
+
+	pipeline := scanner.NewPipeline("workflow").
+		SetLogger(func(level, format string, args ...interface{}) {
+			w.taskLog(task.TaskId, level, format, args...)
+		}).
+		SetProgressCallback(func(progress int, message string) {
+			r.progress = progress
+		})
+	for _, stage := range stages {
+		pipeline.AddStage(r.pipelineStage(stage))
+	}
+
+	return pipeline.Execute(ctx, &scanner.StageInput{
+		Target:      r.target,
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+	})
+}
+
+// pipelineStage 将工作流阶段包装为管道阶段
+func (r *workflowRun) pipelineStage(stage *scheduler.WorkflowStage) scanner.Stage {
+	return scanner.Stage{
+		Name:     stage.DisplayName(),
+		Weight:   stage.EffectiveWeight(),
+		Optional: stage.Optional,
+		Execute: func(ctx context.Context, input *scanner.StageInput) (*scanner.StageOutput, error) {
+			return r.executeStage(ctx, stage, input)
+		},
+	}
+}
+
+// stageInput 阶段输入：没有依赖的阶段以任务目标和初始资产为输入，否则合并上游输出
+func (r *workflowRun) stageInput(stage *scheduler.WorkflowStage, data map[string]interface{}) workflowFlow {
+	if len(stage.DependsOn) == 0 {
+		assets := r.seed
+		// 旧版暂停状态未记录阶段输出，沿用固定流程的做法以全部已保存资产为输入
+		if r.stageAssets == nil && len(r.restored) > 0 {
+			assets = r.restored
+		}
+		return workflowFlow{Target: r.target, Assets: assets}
+	}
+
+	var input workflowFlow
+	for _, dep := range stage.DependsOn {
+		if upstream, ok := data[workflowStageKey(dep)].(workflowFlow); ok {
+			input.Target = mergeWorkflowTargets(input.Target, upstream.Target)
+			input.Assets = mergeWorkflowAssets(input.Assets, upstream.Assets)
+		}
+	}
+	return input
+}
+
+// restoredOutput 恢复执行时还原已完成阶段的输出
+func (r *workflowRun) restoredOutput(stage *scheduler.WorkflowStage, input workflowFlow) workflowFlow {
+	authorities, ok := r.stageAssets[stage.Id]
+	if !ok {
+		return r.stageOutput(stage, input, r.restored)
+	}
+	index := make(map[string]*scanner.Asset, len(r.restored))
+	for _, asset := range r.restored {
+		index[asset.Authority] = asset
+	}
+	assets := make([]*scanner.Asset, 0, len(authorities))
+	for _, authority := range authorities {
+		if asset, ok := index[authority]; ok {
+			assets = append(assets, asset)
+		}
+	}
+	return r.stageOutput(stage, input, assets)
+}
+
+// stageOutput 构建阶段输出，子域名扫描将新发现的子域名追加到目标中
+func (r *workflowRun) stageOutput(stage *scheduler.WorkflowStage, input workflowFlow, assets []*scanner.Asset) workflowFlow {
+	output := workflowFlow{Target: input.Target, Assets: assets}
+	if stage.Phase != string(PhaseDomainScan) {
+		return output
+	}
+	known := make(map[string]bool, len(input.Assets))
+	for _, asset := range input.Assets {
+		known[asset.Authority] = true
+	}
+	var found []*scanner.Asset
+	for _, asset := range assets {
+		if !known[asset.Authority] {
+			found = append(found, asset)
+		}
+	}
+	output.Target = mergeWorkflowTargets(input.Target, strings.Join(workflowHosts(found), "\n"))
+	return output
+}
+
+// executeStage 执行单个工作流阶段
+func (r *workflowRun) executeStage(ctx context.Context, stage *scheduler.WorkflowStage, in *scanner.StageInput) (*scanner.StageOutput, error) {
+	w, task := r.w, r.task
+	key := workflowStageKey(stage.Id)
+	input := r.stageInput(stage, in.Data)
+
+	if r.completed[stage.Id] {
+		w.taskLog(task.TaskId, LevelInfo, "Workflow stage %s: already completed, skipped", stage.DisplayName())
+		return &scanner.StageOutput{Data: map[string]interface{}{key: r.restoredOutput(stage, input)}}, nil
+	}
+
+	if ctrl := w.checkTaskControl(ctx, task.TaskId); ctrl == "STOP" {
+		return &scanner.StageOutput{Stopped: true, Message: "stopped"}, nil
+	} else if ctrl == "PAUSE" {
+		w.taskLog(task.TaskId, LevelInfo, "Task paused, saving progress...")
+		r.paused = true
+		return &scanner.StageOutput{Stopped: true, Message: "paused"}, nil
+	}
+	if !w.scanScopeOf(task.WorkspaceId).AllowedAt(time.Now()) {
+		w.taskLog(task.TaskId, LevelWarn, "Scope: outside the allowed scan window, pausing task")
+		r.paused = true
+		return &scanner.StageOutput{Stopped: true, Message: "paused"}, nil
+	}
+
+	w.updateTaskProgressWithPhase(ctx, task.TaskId, r.progress, stage.DisplayName()+"中", stage.DisplayName())
+
+	// 没有条件时阶段处理全部上游资产，与固定流程一致；有条件时只处理匹配的资产，其余资产直接向下游传递
+	target, assets := input.Target, input.Assets
+	var passthrough []*scanner.Asset
+	if stage.Condition != nil && len(stage.DependsOn) > 0 {
+		matched := r.filterAssets(ctx, stage, assets)
+		if len(matched) == 0 {
+			w.taskLog(task.TaskId, LevelInfo, "Workflow stage %s: skipped (no matching assets)", stage.DisplayName())
+			r.finishStage(ctx, stage, input)
+			return &scanner.StageOutput{Data: map[string]interface{}{key: input}}, nil
+		}
+		matchedSet := make(map[string]bool, len(matched))
+		for _, asset := range matched {
+			matchedSet[asset.Authority] = true
+		}
+		for _, asset := range assets {
+			if !matchedSet[asset.Authority] {
+				passthrough = append(passthrough, asset)
+			}
+		}
+		assets = matched
+		// 发现类阶段以匹配资产的主机为目标，其余阶段只处理资产
+		target = ""
+		if stage.Phase == string(PhaseDomainScan) || stage.Phase == string(PhasePortScan) {
+			target = strings.Join(workflowHosts(matched), "\n")
+		}
+	}
+
+	groups := groupWorkflowAssets(assets, stage.FanOut)
+	if len(groups) > 1 {
+		w.taskLog(task.TaskId, LevelInfo, "Workflow stage %s: fan out %d groups by %s", stage.DisplayName(), len(groups), stage.FanOut)
+	}
+
+	concurrency := stage.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	type groupResult struct {
+		assets []*scanner.Asset
+		phase  *PhaseResult
+	}
+	var errOnce sync.Once
+	var stageErr error
+	results, _ := scanner.ExecuteGeneric(ctx, concurrency, groups, func(ctx context.Context, assets []*scanner.Asset) (groupResult, error) {
+		groupTarget := target
+		if len(groups) > 1 && target != "" {
+			groupTarget = strings.Join(workflowHosts(assets), "\n")
+		}
+		taskCtx, res, err := r.runPhase(ctx, stage, groupTarget, assets)
+		if err != nil {
+			errOnce.Do(func() { stageErr = err })
+		}
+		return groupResult{assets: taskCtx.Assets, phase: res}, nil
+	})
+
+	output := &scanner.StageOutput{}
+	flow := passthrough
+	for _, gr := range results {
+		// 执行器就地更新、替换或生成的资产在前，本阶段新发现的资产追加在后
+		flow = mergeWorkflowAssets(flow, gr.assets)
+		if gr.phase == nil {
+			continue
+		}
+		flow = mergeWorkflowAssets(flow, gr.phase.Assets)
+		output.Assets = append(output.Assets, gr.phase.Assets...)
+		output.Vulnerabilities = append(output.Vulnerabilities, gr.phase.Vulnerabilities...)
+		if gr.phase.Paused {
+			r.paused = true
+		}
+		if gr.phase.Stopped || gr.phase.Paused {
+			output.Stopped = true
+		}
+	}
+	// 本阶段发现的范围外资产不向下游传递
+	flow = w.filterAssetsByScope(task.WorkspaceId, task.MainTaskId, task.TaskId, stage.Phase, flow)
+	result := r.stageOutput(stage, input, flow)
+	output.Data = map[string]interface{}{key: result}
+
+	r.mu.Lock()
+	r.flow = mergeWorkflowAssets(r.flow, flow)
+	r.mu.Unlock()
+
+	if output.Stopped {
+		return output, nil
+	}
+	// 可选阶段失败时仍向下游传递资产，必选阶段失败则终止工作流
+	if stageErr != nil {
+		if !stage.Optional {
+			return output, stageErr
+		}
+		w.taskLog(task.TaskId, LevelWarn, "Workflow stage %s failed: %v, continuing", stage.DisplayName(), stageErr)
+	}
+	r.finishStage(ctx, stage, result)
+	return output, nil
+}
+
+// runPhase 使用阶段类型对应的执行器执行一组资产
+func (r *workflowRun) runPhase(ctx context.Context, stage *scheduler.WorkflowStage, target string, assets []*scanner.Asset) (*TaskContext, *PhaseResult, error) {
+	taskCtx := &TaskContext{
+		Ctx:             ctx,
+		Task:            r.task,
+		Config:          stageTaskConfig(r.config, stage.Phase),
+		Target:          target,
+		OrgId:           r.orgId,
+		Assets:          assets,
+		CompletedPhases: make(map[TaskPhase]bool),
+		Worker:          r.w,
+		Blacklist:       r.blacklist,
+	}
+
+	executor, ok := r.w.taskRunnerIntegration.GetTaskRunner().GetPhaseExecutor(TaskPhase(stage.Phase))
+	if !ok {
+		return taskCtx, nil, fmt.Errorf("no executor for phase %s", stage.Phase)
+	}
+	if !executor.CanExecute(taskCtx) {
+		return taskCtx, &PhaseResult{}, nil
+	}
+	res, err := executor.Execute(taskCtx)
+	return taskCtx, res, err
+}
+
+// finishStage 标记阶段完成、记录阶段输出并递增子任务进度
+func (r *workflowRun) finishStage(ctx context.Context, stage *scheduler.WorkflowStage, output workflowFlow) {
+	authorities := make([]string, 0, len(output.Assets))
+	for _, asset := range output.Assets {
+		authorities = append(authorities, asset.Authority)
+	}
+
+	r.mu.Lock()
+	r.completed[stage.Id] = true
+	if r.stageAssets == nil {
+		r.stageAssets = make(map[string][]string)
+	}
+	r.stageAssets[stage.Id] = authorities
+	r.flow = mergeWorkflowAssets(r.flow, output.Assets)
+	r.mu.Unlock()
+	r.w.incrSubTaskDone(ctx, r.task, stage.DisplayName())
+}
+
+func (r *workflowRun) flowAssets() []*scanner.Asset {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flow
+}
+
+// filterAssets 按阶段条件过滤上游资产
+func (r *workflowRun) filterAssets(ctx context.Context, stage *scheduler.WorkflowStage, assets []*scanner.Asset) []*scanner.Asset {
+	cond := stage.Condition
+	if cond == nil {
+		return assets
+	}
+
+	var newSet map[string]bool
+	if cond.OnlyNew {
+		newSet = make(map[string]bool)
+		resp, err := r.w.httpClient.GetTaskNewAssets(ctx, r.task.WorkspaceId, r.task.MainTaskId)
+		if err != nil || resp.Code != 0 {
+			// 无法判断时不处理任何资产，避免对存量资产执行高开销扫描
+			r.w.taskLog(r.task.TaskId, LevelWarn, "Workflow stage %s: query new assets failed, no assets matched", stage.DisplayName())
+		} else {
+			for _, a := range resp.Authorities {
+				newSet[a] = true
+			}
+		}
+	}
+
+	var matched []*scanner.Asset
+	for _, asset := range assets {
+		if matchStageCondition(cond, asset, newSet) {
+			matched = append(matched, asset)
+		}
+	}
+	r.w.taskLog(r.task.TaskId, LevelInfo, "Workflow stage %s: %d/%d assets matched condition", stage.DisplayName(), len(matched), len(assets))
+	return matched
+}
+
+// matchStageCondition 判断资产是否满足阶段条件，newSet 为本次任务新发现资产的 authority 集合
+func matchStageCondition(cond *scheduler.StageCondition, asset *scanner.Asset, newSet map[string]bool) bool {
+	if cond == nil {
+		return true
+	}
+	if cond.HttpOnly && !asset.IsHTTP {
+		return false
+	}
+	if cond.OnlyNew && !newSet[asset.Authority] {
+		return false
+	}
+	if len(cond.Ports) > 0 {
+		found := false
+		for _, p := range cond.Ports {
+			if p == asset.Port {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(cond.Services) > 0 {
+		found := false
+		for _, s := range cond.Services {
+			if strings.EqualFold(s, asset.Service) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(cond.Apps) > 0 {
+		found := false
+		for _, want := range cond.Apps {
+			want = strings.ToLower(want)
+			for _, app := range asset.App {
+				if strings.Contains(strings.ToLower(app), want) {
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// groupWorkflowAssets 按扇出方式拆分资产，不拆分时返回单个分组
+func groupWorkflowAssets(assets []*scanner.Asset, fanOut string) [][]*scanner.Asset {
+	if fanOut == scheduler.FanOutNone || len(assets) == 0 {
+		return [][]*scanner.Asset{assets}
+	}
+
+	groups := make(map[string][]*scanner.Asset)
+	for _, asset := range assets {
+		key := asset.Host
+		if fanOut == scheduler.FanOutPort {
+			key = strconv.Itoa(asset.Port)
+		}
+		groups[key] = append(groups[key], asset)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([][]*scanner.Asset, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, groups[k])
+	}
+	return result
+}
+
+// mergeWorkflowAssets 按 authority 合并资产，后出现的覆盖先出现的
+func mergeWorkflowAssets(base []*scanner.Asset, extra []*scanner.Asset) []*scanner.Asset {
+	if len(extra) == 0 {
+		return base
+	}
+	index := make(map[string]int, len(base)+len(extra))
+	merged := make([]*scanner.Asset, 0, len(base)+len(extra))
+	for _, list := range [][]*scanner.Asset{base, extra} {
+		for _, asset := range list {
+			if asset == nil {
+				continue
+			}
+			if i, ok := index[asset.Authority]; ok {
+				merged[i] = asset
+				continue
+			}
+			index[asset.Authority] = len(merged)
+			merged = append(merged, asset)
+		}
+	}
+	return merged
+}
+
+// workflowHosts 资产主机去重列表
+func workflowHosts(assets []*scanner.Asset) []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, asset := range assets {
+		if asset.Host != "" && !seen[asset.Host] {
+			seen[asset.Host] = true
+			hosts = append(hosts, asset.Host)
+		}
+	}
+	return hosts
+}
+
+// mergeWorkflowTargets 按行合并目标并去重
+func mergeWorkflowTargets(base, extra string) string {
+	if extra == "" {
+		return base
+	}
+	seen := make(map[string]bool)
+	var lines []string
+	for _, target := range []string{base, extra} {
+		for _, line := range strings.Split(target, "\n") {
+			if line = strings.TrimSpace(line); line != "" && !seen[line] {
+				seen[line] = true
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stageTaskConfig 构建只启用单个阶段的任务配置，阶段参数取自原配置，未配置时使用默认值
+func stageTaskConfig(config *scheduler.TaskConfig, phase string) *scheduler.TaskConfig {
+	sc := &scheduler.TaskConfig{}
+	switch TaskPhase(phase) {
+	case PhaseDomainScan:
+		c := scheduler.DomainScanConfig{}
+		if config.DomainScan != nil {
+			c = *config.DomainScan
+		}
+		c.Enable = true
+		sc.DomainScan = &c
+		// 子域名结果按端口扫描排除目标过滤
+		if config.PortScan != nil {
+			ps := *config.PortScan
+			ps.Enable = false
+			sc.PortScan = &ps
+		}
+	case PhaseTakeover:
+		c := scheduler.TakeoverConfig{}
+		if config.Takeover != nil {
+			c = *config.Takeover
+		}
+		c.Enable = true
+		sc.Takeover = &c
+	case PhaseDNSRecord:
+		c := scheduler.DNSRecordConfig{}
+		if config.DNSRecord != nil {
+			c = *config.DNSRecord
+		}
+		c.Enable = true
+		sc.DNSRecord = &c
+	case PhasePortScan:
+		c := scheduler.PortScanConfig{}
+		if config.PortScan != nil {
+			c = *config.PortScan
+		}
+		c.Enable = true
+		sc.PortScan = &c
+	case PhasePortIdentify:
+		c := scheduler.PortIdentifyConfig{}
+		if config.PortIdentify != nil {
+			c = *config.PortIdentify
+		}
+		c.Enable = true
+		sc.PortIdentify = &c
+	case PhaseFingerprint:
+		c := scheduler.FingerprintConfig{}
+		if config.Fingerprint != nil {
+			c = *config.Fingerprint
+		}
+		c.Enable = true
+		sc.Fingerprint = &c
+	case PhaseExternal:
+		c := scheduler.ExternalScanConfig{}
+		if config.External != nil {
+			c = *config.External
+		}
+		c.Enable = true
+		sc.External = &c
+	case PhaseCrawler:
+		c := scheduler.CrawlerConfig{}
+		if config.Crawler != nil {
+			c = *config.Crawler
+		}
+		c.Enable = true
+		sc.Crawler = &c
+	case PhaseBruteforce:
+		c := scheduler.BruteforceConfig{}
+		if config.Bruteforce != nil {
+			c = *config.Bruteforce
+		}
+		c.Enable = true
+		sc.Bruteforce = &c
+	case PhaseDirScan:
+		c := scheduler.DirScanConfig{}
+		if config.DirScan != nil {
+			c = *config.DirScan
+		}
+		c.Enable = true
+		sc.DirScan = &c
+	case PhasePocScan:
+		c := scheduler.PocScanConfig{}
+		if config.PocScan != nil {
+			c = *config.PocScan
+		}
+		c.Enable = true
+		sc.PocScan = &c
+	}
+	// CDN识别不是独立阶段，随资产产出阶段执行
+	sc.CDN = config.CDN
+	scheduler.NewConfigValidator().ApplyDefaults(sc)
+	return sc
+}
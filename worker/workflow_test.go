@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// phaseCall 执行器收到的一次调用
+type phaseCall struct {
+	Phase  string
+	Target string
+	Assets []string
+}
+
+// recordingExecutor 记录输入并按固定规则产出结果的阶段执行器
+type recordingExecutor struct {
+	phase TaskPhase
+	calls *[]phaseCall
+	onRun func(ctx *TaskContext)
+}
+
+func (e *recordingExecutor) CanExecute(ctx *TaskContext) bool { return true }
+
+func (e *recordingExecutor) Execute(ctx *TaskContext) (*PhaseResult, error) {
+	*e.calls = append(*e.calls, phaseCall{Phase: string(e.phase), Target: ctx.Target, Assets: workflowAuthorities(ctx.Assets)})
+	if e.onRun != nil {
+		e.onRun(ctx)
+	}
+	res := fakePhase(e.phase, ctx)
+	// 与真实执行器一致，暂停时返回已产出的部分结果
+	if ctx.Worker.checkTaskControl(ctx.Ctx, ctx.Task.TaskId) == "PAUSE" {
+		res.Paused = true
+	}
+	return res, nil
+}
+
+// fakePhase 模拟各阶段的资产行为：发现子域名和端口、替换识别后的资产、就地补充指纹、追加URL资产
+func fakePhase(phase TaskPhase, ctx *TaskContext) *PhaseResult {
+	switch phase {
+	case PhaseDomainScan:
+		return &PhaseResult{Assets: []*scanner.Asset{{Authority: "www.example.com", Host: "www.example.com"}}}
+	case PhasePortScan:
+		var open []*scanner.Asset
+		for _, host := range ParseTargets(ctx.Target) {
+			open = append(open, &scanner.Asset{Authority: host + ":80", Host: host, Port: 80})
+		}
+		return &PhaseResult{Assets: open}
+	case PhasePortIdentify:
+		identified := make([]*scanner.Asset, 0, len(ctx.Assets))
+		for _, asset := range ctx.Assets {
+			copied := *asset
+			copied.Service = "http"
+			identified = append(identified, &copied)
+		}
+		ctx.Assets = identified
+	case PhaseFingerprint:
+		for _, asset := range ctx.Assets {
+			asset.App = []string{"nginx"}
+		}
+	case PhaseCrawler:
+		return &PhaseResult{Assets: []*scanner.Asset{{Authority: "www.example.com:80/login", Host: "www.example.com", Port: 80}}}
+	case PhasePocScan:
+		return &PhaseResult{Vulnerabilities: []*scanner.Vulnerability{{Authority: "example.com:80", PocFile: "test"}}}
+	}
+	return &PhaseResult{}
+}
+
+func workflowAuthorities(assets []*scanner.Asset) []string {
+	authorities := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		authorities = append(authorities, asset.Authority)
+	}
+	return authorities
+}
+
+// newWorkflowTestWorker 创建只注册记录执行器的 Worker
+func newWorkflowTestWorker(calls *[]phaseCall, onRun map[TaskPhase]func(ctx *TaskContext)) *Worker {
+	w := &Worker{config: WorkerConfig{Name: "test-worker"}}
+	w.taskRunnerIntegration = NewTaskRunnerIntegration(w)
+	for _, phase := range DefaultPhaseOrder {
+		w.taskRunnerIntegration.GetTaskRunner().RegisterPhaseExecutor(phase.Phase, &recordingExecutor{phase: phase.Phase, calls: calls, onRun: onRun[phase.Phase]})
+	}
+	return w
+}
+
+// runFixedChain 按旧版固定流程的语义执行：各阶段按固定顺序共享目标和累积资产，子域名追加到目标
+func runFixedChain(config *scheduler.TaskConfig, target string) ([]phaseCall, []string) {
+	var calls []phaseCall
+	var assets []*scanner.Asset
+	for _, stage := range scheduler.DefaultWorkflow(config).Stages {
+		phase := TaskPhase(stage.Phase)
+		ctx := &TaskContext{Target: target, Assets: assets}
+		calls = append(calls, phaseCall{Phase: stage.Phase, Target: target, Assets: workflowAuthorities(assets)})
+		res := fakePhase(phase, ctx)
+		assets = append(ctx.Assets, res.Assets...)
+		if phase == PhaseDomainScan {
+			target = target + "\n" + strings.Join(workflowHosts(res.Assets), "\n")
+		}
+	}
+	return calls, workflowAuthorities(assets)
+}
+
+func legacyTestConfig() *scheduler.TaskConfig {
+	return &scheduler.TaskConfig{
+		DomainScan:   &scheduler.DomainScanConfig{Enable: true},
+		PortScan:     &scheduler.PortScanConfig{Enable: true},
+		PortIdentify: &scheduler.PortIdentifyConfig{Enable: true},
+		Fingerprint:  &scheduler.FingerprintConfig{Enable: true},
+		Crawler:      &scheduler.CrawlerConfig{Enable: true},
+		PocScan:      &scheduler.PocScanConfig{Enable: true},
+	}
+}
+
+func TestLegacyConfigMatchesDefaultWorkflow(t *testing.T) {
+	config := legacyTestConfig()
+	wantCalls, wantAssets := runFixedChain(config, "example.com")
+
+	var calls []phaseCall
+	w := newWorkflowTestWorker(&calls, nil)
+	task := &scheduler.TaskInfo{TaskId: "task-1", MainTaskId: "task-1"}
+	run := &workflowRun{w: w, task: task, config: config, target: "example.com"}
+	result, err := run.execute(context.Background())
+	if err != nil {
+		t.Fatalf("execute() error: %v", err)
+	}
+
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("phase calls differ from fixed chain:\n got  %+v\n want %+v", calls, wantCalls)
+	}
+	if got := workflowAuthorities(run.flowAssets()); !reflect.DeepEqual(got, wantAssets) {
+		t.Errorf("assets = %v, want %v", got, wantAssets)
+	}
+	if len(result.Vulnerabilities) != 1 {
+		t.Errorf("vulnerabilities = %d, want 1", len(result.Vulnerabilities))
+	}
+	for _, stage := range config.GetWorkflow().Stages {
+		if !run.completed[stage.Id] {
+			t.Errorf("stage %s not marked completed", stage.Id)
+		}
+	}
+
+	t.Run("Resume", func(t *testing.T) {
+		// 爬虫执行中暂停，已发现的URL资产随暂停状态保存但爬虫未完成
+		// 恢复后爬虫应以指纹识别的输出为输入，剩余阶段的输入与不中断时一致
+		var calls []phaseCall
+		var w *Worker
+		w = newWorkflowTestWorker(&calls, map[TaskPhase]func(ctx *TaskContext){
+			PhaseCrawler: func(ctx *TaskContext) { w.taskControlSignals.Store(task.TaskId, "PAUSE") },
+		})
+		paused := &workflowRun{w: w, task: task, config: config, target: "example.com"}
+		if _, err := paused.execute(context.Background()); err != nil {
+			t.Fatalf("execute() error: %v", err)
+		}
+		if !paused.paused || !paused.completed["fingerprint"] || paused.completed["crawler"] {
+			t.Fatalf("run should pause during crawler, completed=%v", paused.completed)
+		}
+
+		state, _ := buildResumeState(paused.completed, paused.flowAssets(), paused.stageAssets)
+		completed, restored, stageAssets := parseResumeState(string(state))
+		calls = nil
+		resumed := &workflowRun{w: newWorkflowTestWorker(&calls, nil), task: task, config: config, target: "example.com",
+			completed: completed, restored: restored, stageAssets: stageAssets}
+		if _, err := resumed.execute(context.Background()); err != nil {
+			t.Fatalf("resume execute() error: %v", err)
+		}
+
+		if !reflect.DeepEqual(calls, wantCalls[4:]) {
+			t.Errorf("resumed phase calls:\n got  %+v\n want %+v", calls, wantCalls[4:])
+		}
+		if got := workflowAuthorities(resumed.flowAssets()); !reflect.DeepEqual(got, wantAssets) {
+			t.Errorf("resumed assets = %v, want %v", got, wantAssets)
+		}
+	})
+}
@@ -50,7 +50,7 @@ type WSMessage struct {
 // WSAuthPayload 认证消息载荷
 type WSAuthPayload struct {
 	WorkerName string `json:"workerName"`
-	InstallKey string `json:"installKey"`
+	Token      string `json:"token"` // Worker凭证
 }
 
 // WSLogPayload 日志消息载荷
@@ -78,7 +78,7 @@ type WSControlPayload struct {
 type WSClientConfig struct {
 	ServerURL       string        // WebSocket服务器URL (e.g., ws://server:8888/api/v1/worker/ws)
 	WorkerName      string        // Worker名称
-	InstallKey      string        // 安装密钥
+	Token           string        // Worker凭证
 	ReconnectDelay  time.Duration // 初始重连延迟
 	MaxReconnect    time.Duration // 最大重连延�?
 	PingInterval    time.Duration // 心跳间隔
@@ -89,11 +89,11 @@ type WSClientConfig struct {
 }
 
 // DefaultWSClientConfig 默认配置
-func DefaultWSClientConfig(serverURL, workerName, installKey string) *WSClientConfig {
+func DefaultWSClientConfig(serverURL, workerName, token string) *WSClientConfig {
 	return &WSClientConfig{
 		ServerURL:       serverURL,
 		WorkerName:      workerName,
-		InstallKey:      installKey,
+		Token:           token,
 		ReconnectDelay:  1 * time.Second,
 		MaxReconnect:    30 * time.Second,
 		PingInterval:    30 * time.Second,
@@ -314,7 +314,7 @@ func (c *WorkerWSClient) authenticate() error {
 	// 构建认证消息
 	authPayload := WSAuthPayload{
 		WorkerName: c.config.WorkerName,
-		InstallKey: c.config.InstallKey,
+		Token:      c.config.Token,
 	}
 	payloadData, _ := json.Marshal(authPayload)
 